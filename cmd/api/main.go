@@ -31,7 +31,6 @@ func main() {
 	if err != nil {
 		logger.Fatalf("Failed to initialize dependencies: %v", err)
 	}
-	defer deps.Close()
 
 	// Setup routes with dependency injection
 	router := routes.SetupRoutes(deps)
@@ -43,17 +42,57 @@ func main() {
 
 	go startServer(server)
 
+	// Watch Redis before the jobs that use it start
+	deps.RedisHealth.Start(context.Background())
+
+	// Warm the event cache so the first requests after a deploy don't all reach Postgres
+	go func() {
+		if err := deps.EventService.PreheatCache(context.Background()); err != nil {
+			logger.Warnf("Failed to warm the event cache: %v", err)
+		}
+	}()
+
+	// Start background jobs
+	deps.Scheduler.Start(context.Background())
+	deps.TaskQueue.Start(context.Background())
+	if deps.InventorySync != nil {
+		deps.InventorySync.Start(context.Background())
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	logger.Info("Shutting down server...")
 
-	// Give tasks time to finish cleanup
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// Fail the health check and refuse new booking intents and other changes, then give load
+	// balancers time to stop routing here while reads are still served
+	deps.Drain.Start()
+	time.Sleep(deps.Config.ShutdownDrainDelay)
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), deps.Config.ShutdownTimeout)
 	defer shutdownCancel()
 
+	// Confirmations and other changes already running finish, along with the notifications and
+	// domain events they deliver before responding
+	if err := deps.Drain.Wait(shutdownCtx); err != nil {
+		logger.Warnf("Requests still running at shutdown: %v", err)
+	}
+
 	if err := server.Shutdown(shutdownCtx); err != nil {
-		logger.Fatalf("Server forced to shutdown: %v", err)
+		logger.Errorf("Server forced to shutdown: %v", err)
+	}
+
+	// Stop background jobs before closing DB/Redis connections
+	deps.Scheduler.Stop()
+	// Running tasks such as event seat generation finish; queued ones wait for the next start
+	deps.TaskQueue.Stop()
+	if deps.InventorySync != nil {
+		deps.InventorySync.Stop()
+	}
+	deps.RedisHealth.Stop()
+
+	if err := deps.Close(); err != nil {
+		logger.Errorf("Failed to close connections: %v", err)
 	}
 
 	logger.Info("Server exiting")
@@ -0,0 +1,42 @@
+// Command reencrypt rewrites encrypted columns with the active encryption key. Run it after
+// adding a new active key (ENCRYPTION_ACTIVE_KEY_ID) and keep the old key configured until it
+// reports nothing left to rotate. It also encrypts values stored before encryption was enabled.
+package main
+
+import (
+	"api/internal/container"
+	"api/internal/encryption"
+	"api/internal/entities"
+	logger "api/pkg/logging"
+	"context"
+	"flag"
+)
+
+func main() {
+	batchSize := flag.Int("batch", 500, "rows read per batch")
+	dryRun := flag.Bool("dry-run", false, "only count the values that need re-encryption")
+	flag.Parse()
+
+	logger.Init(logger.Config{
+		Level: "info",
+	})
+
+	deps, err := container.NewContainer()
+	if err != nil {
+		logger.Fatalf("Failed to initialize dependencies: %v", err)
+	}
+	defer deps.Close()
+
+	ctx := context.Background()
+	for _, model := range []interface{}{&entities.User{}, &entities.Booking{}} {
+		count, err := encryption.Reencrypt(ctx, deps.DB, deps.Keyring, model, *batchSize, *dryRun)
+		if err != nil {
+			logger.Fatalf("Re-encryption failed after %d values: %v", count, err)
+		}
+		if *dryRun {
+			logger.Infof("%T: %d values need re-encryption with key %q", model, count, deps.Keyring.ActiveKeyID())
+		} else {
+			logger.Infof("%T: re-encrypted %d values with key %q", model, count, deps.Keyring.ActiveKeyID())
+		}
+	}
+}
@@ -24,6 +24,22 @@ const (
 	BookingStatusConfirmed = "confirmed"
 	BookingStatusCancelled = "cancelled"
 	BookingStatusRefunded  = "refunded"
+	BookingStatusReleased  = "released" // not checked in by the no-show cutoff; its seat went back on sale
+)
+
+// Sales channels: bookings made online, or sold by staff at the box office
+const (
+	SalesChannelOnline   = "online"
+	SalesChannelDoor     = "door"
+	SalesChannelComp     = "comp"       // complimentary tickets issued by admins
+	SalesChannelOversell = "oversell"   // oversell places of free events, given to the waitlist
+	BoxOfficeProvider    = "box_office" // payment provider of door sales
+)
+
+// Seat price change sources
+const (
+	PriceSourceAdmin   = "admin"   // bulk updates by admins
+	PriceSourceDynamic = "dynamic" // set by a dynamic pricing rule
 )
 
 // Payment Status
@@ -32,6 +48,12 @@ const (
 	PaymentStatusPaid     = "paid"
 	PaymentStatusFailed   = "failed"
 	PaymentStatusRefunded = "refunded"
+	PaymentStatusSandbox  = "sandbox" // sandbox event bookings, which take no payment
+	PaymentStatusComp     = "comp"    // complimentary tickets, which take no payment
+	PaymentStatusFree     = "free"    // bookings of free events, which take no payment
+
+	PaymentStatusDisputed    = "disputed"     // payment transactions with an open dispute
+	PaymentStatusChargedBack = "charged_back" // payment transactions whose dispute was lost
 )
 
 // Booking Intent Status
@@ -56,6 +78,44 @@ const (
 	QueueStatusActive    = "active"
 	QueueStatusExpired   = "expired"
 	QueueStatusCompleted = "completed"
+	QueueStatusCancelled = "cancelled"
+)
+
+// Queue Kinds: event_queues holds both the waitlist of sold-out events and the queue of
+// users waiting for an on-sale to admit them
+const (
+	QueueKindWaitlist = "waitlist"
+	QueueKindOnSale   = "on_sale"
+)
+
+// Reminder Status
+const (
+	ReminderStatusPending = "pending"
+	ReminderStatusSent    = "sent"
+	ReminderStatusFailed  = "failed"
+)
+
+// Dispute Status
+const (
+	DisputeStatusOpen        = "open"
+	DisputeStatusUnderReview = "under_review"
+	DisputeStatusWon         = "won"
+	DisputeStatusLost        = "lost"
+)
+
+// Notification Types
+const (
+	NotificationTypeBookingReminder       = "booking_reminder"
+	NotificationTypeFeedbackRequest       = "feedback_request"
+	NotificationTypeDisputeOpened         = "dispute_opened"
+	NotificationTypeBookingConfirmed      = "booking_confirmed"
+	NotificationTypeBookingCancelled      = "booking_cancelled"
+	NotificationTypeHoldExpired           = "hold_expired"
+	NotificationTypeWaitlistSeatAvailable = "waitlist_seat_available"
+	NotificationTypeAccountDeletion       = "account_deletion"
+	NotificationTypeNoShowReleased        = "no_show_released"
+	NotificationTypeSupportCaseUpdated    = "support_case_updated"
+	NotificationTypeEventAnnouncement     = "event_announcement"
 )
 
 // Seat Types
@@ -74,29 +134,282 @@ const (
 	EventTypeOther      = "other"
 )
 
+// Waitlist Tiers
+const (
+	WaitlistTierGeneral = "general"
+)
+
+// Membership Tiers, earned from lifetime loyalty points
+const (
+	MembershipTierSilver   = "silver"
+	MembershipTierGold     = "gold"
+	MembershipTierPlatinum = "platinum"
+)
+
+// Loyalty Ledger Reasons
+const (
+	LoyaltyReasonEarn     = "earn"
+	LoyaltyReasonRedeem   = "redeem"
+	LoyaltyReasonReversal = "reversal"
+)
+
+// Task Statuses
+const (
+	TaskStatusPending   = "pending"
+	TaskStatusRunning   = "running"
+	TaskStatusCompleted = "completed"
+	TaskStatusFailed    = "failed"
+)
+
+// Task Kinds
+const (
+	TaskKindEventCreation = "event_creation"
+	TaskKindArchival      = "archival"
+)
+
+// Dead Letter Statuses
+const (
+	DeadLetterStatusDead      = "dead"      // failed, waiting for an admin to retry or discard it
+	DeadLetterStatusRetried   = "retried"   // a retry succeeded
+	DeadLetterStatusDiscarded = "discarded" // dropped by an admin
+)
+
+// Dead Letter Sources: the kinds of async work kept when they fail
+const (
+	DeadLetterSourceNotification      = "notification"       // a notification to a user, e.g. an email
+	DeadLetterSourceAdminNotification = "admin_notification" // a post to the admins' team chat webhook
+	DeadLetterSourceDomainEvent       = "domain_event"       // a booking workflow event a consumer failed to handle
+)
+
+// Offline Check-in Outcomes, reported to scanners syncing the scans they made offline
+const (
+	CheckInSyncCheckedIn = "checked_in"
+	CheckInSyncDuplicate = "duplicate" // the booking was already checked in by another scan
+	CheckInSyncRejected  = "rejected"
+)
+
+// Scanner Devices
+const (
+	ScannerTokenPrefix        = "scn_" // tells device tokens apart from user JWTs
+	GateStatsWindowMinutes    = 15     // default window gate entry rates are computed over
+	MaxGateStatsWindowMinutes = 120
+)
+
+// Support Case Statuses
+const (
+	SupportCaseStatusOpen     = "open"     // waiting for staff
+	SupportCaseStatusAnswered = "answered" // staff responded
+	SupportCaseStatusResolved = "resolved"
+)
+
+// Support Case Categories
+const (
+	SupportCategoryRefund  = "refund"
+	SupportCategoryTicket  = "ticket"
+	SupportCategoryAccess  = "access"
+	SupportCategoryPayment = "payment"
+	SupportCategoryOther   = "other"
+)
+
+// Event Post Kinds
+const (
+	EventPostKindAnnouncement = "announcement" // e.g. a gate change, emailed to booked attendees
+	EventPostKindFAQ          = "faq"
+)
+
+// Autocomplete
+const AutocompleteMinQueryLength = 2 // shorter queries get no suggestions
+
+// Import Kinds
+const (
+	ImportKindVenues = "venues"
+	ImportKindEvents = "events"
+)
+
+// Import Actions
+const (
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionUnchanged = "unchanged"
+)
+
 // Redis Keys
 const (
-	SeatLockPrefix    = "seat_lock:"
-	QueuePrefix       = "queue:"
-	UserSessionPrefix = "user_session:"
+	SeatLockPrefix     = "seat_lock:"
+	QueuePrefix        = "queue:"
+	UserSessionPrefix  = "user_session:"
+	IntentHeartbeatKey = "intent_heartbeats"
 )
 
 // Lock Durations (in minutes)
 const (
-	SeatLockDuration    = 8
-	QueueActiveDuration = 10
+	SeatLockDuration      = 8
+	QueueActiveDuration   = 10
+	WaitlistPromotionHold = 10 // how long a waitlisted user offered a seat has to book it
+)
+
+// QueueMaxActive is how many users of an event's on-sale queue may be admitted at once
+const QueueMaxActive = 200
+
+// Seat lock divergence policies: what the periodic check does about seats whose database and
+// Redis locks disagree
+const (
+	LockDivergenceRepair = "repair" // restore missing Redis locks and release orphaned ones
+	LockDivergenceReport = "report" // only count and log them
+)
+
+// Heartbeats (in seconds)
+const (
+	// IntentHeartbeatTimeout releases a seat lock early once a checkout that has
+	// started sending heartbeats goes quiet for this long
+	IntentHeartbeatTimeout = 45
+	// IntentResumeTokenTTL is how long a checkout handoff token can be redeemed on another device
+	IntentResumeTokenTTL = 120
+)
+
+// Payments
+const (
+	MaxPaymentAttempts     = 5
+	PaymentReferencePrefix = "pay_"
+	DefaultPaymentProvider = "manual"
+	DefaultCurrency        = "USD"
+)
+
+// Loyalty
+const (
+	LoyaltyPointsPerCurrencyUnit = 10  // points earned per unit of currency paid
+	LoyaltyPointsPerDiscountUnit = 100 // points redeemed per unit of currency discounted
+	SilverTierThreshold          = 1000
+	GoldTierThreshold            = 5000
+	PlatinumTierThreshold        = 15000
+)
+
+// Presale Codes
+const (
+	PresaleCodeLength   = 10
+	PresaleCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // no 0/O or 1/I lookalikes
+)
+
+// Ledger accounts and the kinds of journals posted to them
+const (
+	LedgerAccountCash    = "cash"    // money collected through payment providers and the box office
+	LedgerAccountSales   = "sales"   // ticket sales, owed to organizers
+	LedgerAccountRefunds = "refunds" // money returned for cancelled bookings
+	LedgerAccountFees    = "fees"    // platform fees kept from organizers' sales
+	LedgerKindSale       = "sale"
+	LedgerKindRefund     = "refund"
+	LedgerKindFee        = "fee"
+)
+
+// Outcomes of external inventory messages that were accepted
+const (
+	InventoryHeld      = "held"
+	InventoryReleased  = "released"
+	InventoryDuplicate = "duplicate" // already applied
+	InventoryStale     = "stale"     // older than the latest message applied to the seat
+	InventoryNotHeld   = "not_held"  // release of a seat that isn't held externally
+)
+
+// Catalog sync
+const (
+	CatalogSyncSucceeded = "succeeded"
+	CatalogSyncFailed    = "failed"
+	CatalogKindVenue     = "venue"
+	CatalogKindEvent     = "event"
+)
+
+// Referrals
+const (
+	ReferrerKindUser        = "user"    // a user's own code, created on request
+	ReferrerKindPartner     = "partner" // an affiliate set up by a platform admin
+	ReferralCodeLength      = 8
+	CommissionStatusPending = "pending" // earned, not paid out yet
+	CommissionStatusPaid    = "paid"
+)
+
+// Reminders
+const (
+	DefaultReminderOffsets = "24h,2h"
+	MaxReminderOffsetHours = 7 * 24 // reminders can be scheduled at most a week before start
+)
+
+// Platform policies users accept at registration and again when their version changes
+const (
+	PolicyTermsOfService = "terms_of_service"
+	PolicyPrivacy        = "privacy_policy"
+)
+
+// Runtime settings platform admins can tune without a deploy
+const (
+	SettingLockDuration = "booking.lock_duration"     // how long a booking intent holds its seat
+	SettingWaitlistHold = "waitlist.promotion_hold"   // how long a promoted waitlist entry has to book
+	SettingFeePercent   = "settlement.fee_percent"    // platform fee percent of net sales
+	SettingFeePerTicket = "settlement.fee_per_ticket" // platform fee per ticket sold
+)
+
+// Runtime setting kinds
+const (
+	SettingKindDuration = "duration"
+	SettingKindNumber   = "number"
 )
 
 // Error Messages
 const (
-	ErrSeatNotAvailable    = "seat is not available"
-	ErrSeatAlreadyLocked   = "seat is already locked by another user"
-	ErrPaymentFailed       = "payment processing failed"
-	ErrBookingExpired      = "booking intent has expired"
-	ErrInsufficientSeats   = "not enough seats available"
-	ErrEventSoldOut        = "event is sold out"
-	ErrEventNotFound       = "event not found"
-	ErrUnauthorizedAccess  = "unauthorized access"
-	ErrInvalidBookingState = "invalid booking state"
-	ErrVenueTimeConflict   = "venue is already booked for another event during this time period"
+	ErrSeatNotAvailable      = "seat is not available"
+	ErrSeatAlreadyLocked     = "seat is already locked by another user"
+	ErrPaymentFailed         = "payment processing failed"
+	ErrBookingExpired        = "booking intent has expired"
+	ErrPaymentAttemptLimit   = "maximum number of payment attempts reached"
+	ErrInsufficientSeats     = "not enough seats available"
+	ErrEventSoldOut          = "event is sold out"
+	ErrEventNotFound         = "event not found"
+	ErrUnauthorizedAccess    = "unauthorized access"
+	ErrInvalidBookingState   = "invalid booking state"
+	ErrVenueTimeConflict     = "venue is already booked for another event during this time period"
+	ErrVenueLayoutHasEvents  = "can't change the rows or columns of a venue with events"
+	ErrSandboxOfSandbox      = "sandbox events can't have sandboxes of their own"
+	ErrWaitlistFull          = "waitlist for this event is full"
+	ErrNotInQueue            = "you are not in the queue for this event"
+	ErrNoQueue               = "this event has no on-sale queue"
+	ErrInsufficientPoints    = "insufficient loyalty points"
+	ErrNotOnSale             = "tickets for this event are not on sale yet"
+	ErrInvalidPresaleCode    = "invalid presale code"
+	ErrPresaleCodeUsedUp     = "presale code has already been used"
+	ErrCompanionSeat         = "companion seats can only be booked together with their accessible seat"
+	ErrSeatNotReleased       = "seat is not on sale yet"
+	ErrSalesClosed           = "ticket sales for this event have closed"
+	ErrDoorSalesDisabled     = "door sales are not enabled for this event"
+	ErrBookingReleasedNoShow = "booking wasn't checked in by the no-show cutoff and its seat went back on sale"
+	ErrTermsNotAccepted      = "you must accept the event's terms and conditions"
+	ErrTermsVersionChanged   = "the event's terms and conditions have changed, please review and accept the current version"
+	ErrTooManyIntents        = "you have too many pending booking intents, complete or cancel one first"
+	ErrInvalidResumeToken    = "resume token is invalid or has expired"
+	ErrSeatHoldLost          = "the seat is no longer held for this booking intent"
+
+	ErrAttendeeNameRequired      = "attendee full name is required for this event"
+	ErrAttendeeIDRequired        = "a valid attendee ID number is required for this event"
+	ErrAttendeeBirthDateRequired = "attendee date of birth is required for this age-restricted event"
+
+	ErrSaleRegionNotAllowed = "tickets for this event can't be bought from your region"
+	ErrSaleRegionUnknown    = "tickets for this event are only sold in some regions and yours couldn't be determined"
+
+	ErrPoliciesNotAccepted  = "you must accept the terms of service and privacy policy"
+	ErrPolicyVersionChanged = "the terms of service or privacy policy have changed, please review and accept the current versions"
+
+	ErrSupportCaseNotFound    = "support case not found"
+	ErrSupportCaseAlreadyOpen = "a support case is already open for this booking"
+	ErrSupportCaseResolved    = "support case is already resolved"
+
+	ErrEventPostNotFound = "event post not found"
+
+	ErrOversellPaidEvent  = "only free events can be oversold"
+	ErrOversellSeat       = "oversell places are only given out from the waitlist"
+	ErrTicketLimitReached = "you already hold the maximum number of tickets for this event"
+)
+
+// Error codes sent with errors clients are expected to handle specifically
+const (
+	CodeSaleRegionNotAllowed = "SALE_REGION_NOT_ALLOWED"
+	CodeSaleRegionUnknown    = "SALE_REGION_UNKNOWN"
+	CodePoliciesNotAccepted  = "POLICIES_NOT_ACCEPTED"
 )
@@ -0,0 +1,107 @@
+// Package alerting delivers operational alerts to the on-call channel: a Slack incoming
+// webhook, PagerDuty or the application log.
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Channels
+const (
+	ChannelLog       = "log"
+	ChannelSlack     = "slack"
+	ChannelPagerDuty = "pagerduty"
+)
+
+// Alert severities
+const (
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Alert is a metric crossing its threshold, or going back under it once resolved
+type Alert struct {
+	Rule      string // e.g. booking_failure_rate; alerts of one rule are deduplicated by it
+	Severity  string
+	Summary   string
+	Value     float64
+	Threshold float64
+	Resolved  bool
+	At        time.Time
+}
+
+// Channel delivers alerts
+type Channel interface {
+	Send(ctx context.Context, alert Alert) error
+}
+
+// Config selects and configures the alert channel
+type Config struct {
+	Channel string
+
+	// Slack channel: an incoming webhook URL
+	SlackWebhookURL string
+
+	// PagerDuty channel: the routing key of an Events API v2 integration
+	PagerDutyRoutingKey string
+	PagerDutyURL        string // defaults to the public Events API
+	Source              string // names the deployment alerts come from
+
+	Timeout time.Duration
+}
+
+// New returns the channel selected by the config; without one alerts are logged
+func New(cfg Config) (Channel, error) {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	client := &http.Client{Timeout: timeout}
+
+	switch cfg.Channel {
+	case "", ChannelLog:
+		return NewLogChannel(), nil
+	case ChannelSlack:
+		if cfg.SlackWebhookURL == "" {
+			return nil, fmt.Errorf("the slack alert channel needs a webhook URL")
+		}
+		return NewSlackChannel(cfg.SlackWebhookURL, client), nil
+	case ChannelPagerDuty:
+		if cfg.PagerDutyRoutingKey == "" {
+			return nil, fmt.Errorf("the pagerduty alert channel needs a routing key")
+		}
+		return NewPagerDutyChannel(cfg.PagerDutyURL, cfg.PagerDutyRoutingKey, cfg.Source, client), nil
+	default:
+		return nil, fmt.Errorf("unknown alert channel %q", cfg.Channel)
+	}
+}
+
+// postJSON posts body as JSON to url and fails on non-2xx responses
+func postJSON(ctx context.Context, client *http.Client, url string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build alert request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send alert: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("alert channel responded %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewChannel(t *testing.T) {
+	if ch, err := New(Config{}); err != nil {
+		t.Fatalf("default channel: %v", err)
+	} else if _, ok := ch.(*LogChannel); !ok {
+		t.Errorf("default channel is %T, want *LogChannel", ch)
+	}
+	if _, err := New(Config{Channel: ChannelSlack}); err == nil {
+		t.Error("slack channel without a webhook URL was accepted")
+	}
+	if _, err := New(Config{Channel: ChannelPagerDuty}); err == nil {
+		t.Error("pagerduty channel without a routing key was accepted")
+	}
+	if _, err := New(Config{Channel: "email"}); err == nil {
+		t.Error("unknown channel was accepted")
+	}
+}
+
+func TestPagerDutyTriggersAndResolves(t *testing.T) {
+	var events []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("decode event: %v", err)
+		}
+		events = append(events, event)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	ch, err := New(Config{Channel: ChannelPagerDuty, PagerDutyRoutingKey: "key", PagerDutyURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	alert := Alert{Rule: "payment_error_rate", Severity: SeverityCritical, Summary: "12% failed", Value: 12, Threshold: 10, At: time.Now()}
+	if err := ch.Send(context.Background(), alert); err != nil {
+		t.Fatalf("trigger: %v", err)
+	}
+	alert.Resolved = true
+	if err := ch.Send(context.Background(), alert); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if events[0]["event_action"] != "trigger" || events[0]["dedup_key"] != "payment_error_rate" || events[0]["routing_key"] != "key" {
+		t.Errorf("unexpected trigger event %v", events[0])
+	}
+	if payload, _ := events[0]["payload"].(map[string]interface{}); payload["severity"] != SeverityCritical || payload["source"] != "ticket-booking-api" {
+		t.Errorf("unexpected trigger payload %v", events[0]["payload"])
+	}
+	if events[1]["event_action"] != "resolve" || events[1]["dedup_key"] != "payment_error_rate" || events[1]["payload"] != nil {
+		t.Errorf("unexpected resolve event %v", events[1])
+	}
+}
+
+func TestSlackRejectedWebhook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	ch, err := New(Config{Channel: ChannelSlack, SlackWebhookURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ch.Send(context.Background(), Alert{Rule: "lock_contention", Severity: SeverityWarning}); err == nil {
+		t.Error("a rejected webhook call was reported as sent")
+	}
+}
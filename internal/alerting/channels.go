@@ -0,0 +1,103 @@
+package alerting
+
+import (
+	logger "api/pkg/logging"
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// LogChannel writes alerts to the application log, for deployments without an on-call channel
+type LogChannel struct{}
+
+// Ensure LogChannel implements Channel
+var _ Channel = (*LogChannel)(nil)
+
+func NewLogChannel() *LogChannel {
+	return &LogChannel{}
+}
+
+func (c *LogChannel) Send(ctx context.Context, alert Alert) error {
+	if alert.Resolved {
+		logger.Infof("Alert %s resolved: %s", alert.Rule, alert.Summary)
+		return nil
+	}
+	logger.Warnf("Alert %s [%s]: %s", alert.Rule, alert.Severity, alert.Summary)
+	return nil
+}
+
+// SlackChannel posts alerts to a Slack incoming webhook
+type SlackChannel struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// Ensure SlackChannel implements Channel
+var _ Channel = (*SlackChannel)(nil)
+
+func NewSlackChannel(webhookURL string, client *http.Client) *SlackChannel {
+	return &SlackChannel{webhookURL: webhookURL, client: client}
+}
+
+func (c *SlackChannel) Send(ctx context.Context, alert Alert) error {
+	text := fmt.Sprintf(":rotating_light: *%s* [%s]: %s", alert.Rule, alert.Severity, alert.Summary)
+	if alert.Resolved {
+		text = fmt.Sprintf(":white_check_mark: *%s* resolved: %s", alert.Rule, alert.Summary)
+	}
+	return postJSON(ctx, c.client, c.webhookURL, map[string]string{"text": text})
+}
+
+// pagerDutyEventsURL is the PagerDuty Events API v2 endpoint
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutyChannel triggers and resolves PagerDuty incidents through the Events API v2. An
+// alert's rule is its dedup key, so a rule firing again updates its open incident.
+type PagerDutyChannel struct {
+	url        string
+	routingKey string
+	source     string
+	client     *http.Client
+}
+
+// Ensure PagerDutyChannel implements Channel
+var _ Channel = (*PagerDutyChannel)(nil)
+
+func NewPagerDutyChannel(url, routingKey, source string, client *http.Client) *PagerDutyChannel {
+	if url == "" {
+		url = pagerDutyEventsURL
+	}
+	if source == "" {
+		source = "ticket-booking-api"
+	}
+	return &PagerDutyChannel{url: url, routingKey: routingKey, source: source, client: client}
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"` // trigger or resolve
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string             `json:"summary"`
+	Source        string             `json:"source"`
+	Severity      string             `json:"severity"` // critical, error, warning or info
+	Timestamp     string             `json:"timestamp"`
+	CustomDetails map[string]float64 `json:"custom_details"`
+}
+
+func (c *PagerDutyChannel) Send(ctx context.Context, alert Alert) error {
+	event := pagerDutyEvent{RoutingKey: c.routingKey, EventAction: "resolve", DedupKey: alert.Rule}
+	if !alert.Resolved {
+		event.EventAction = "trigger"
+		event.Payload = &pagerDutyPayload{
+			Summary:       alert.Summary,
+			Source:        c.source,
+			Severity:      alert.Severity,
+			Timestamp:     alert.At.UTC().Format("2006-01-02T15:04:05Z"),
+			CustomDetails: map[string]float64{"value": alert.Value, "threshold": alert.Threshold},
+		}
+	}
+	return postJSON(ctx, c.client, c.url, event)
+}
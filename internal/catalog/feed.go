@@ -0,0 +1,161 @@
+// Package catalog fetches a partner's catalog feed of venues and events.
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Feed formats
+const (
+	FormatJSON = "json"
+	FormatXML  = "xml"
+)
+
+// maxFeedSize bounds the feed read from the partner
+const maxFeedSize = 32 << 20
+
+// Feed is a partner's catalog. Venues and events are identified by the partner's IDs; events
+// refer to their venue by its partner ID.
+type Feed struct {
+	XMLName xml.Name `json:"-" xml:"catalog"`
+	Venues  []Venue  `json:"venues" xml:"venues>venue"`
+	Events  []Event  `json:"events" xml:"events>event"`
+}
+
+type Venue struct {
+	ExternalID  string    `json:"external_id" xml:"external_id"`
+	Name        string    `json:"name" xml:"name"`
+	Address     string    `json:"address" xml:"address"`
+	City        string    `json:"city" xml:"city"`
+	State       string    `json:"state" xml:"state"`
+	Country     string    `json:"country" xml:"country"`
+	Description string    `json:"description" xml:"description"`
+	Columns     int       `json:"columns" xml:"columns"`
+	Sections    []Section `json:"sections" xml:"sections>section"`
+}
+
+type Section struct {
+	Name            string  `json:"name" xml:"name"`
+	RowStart        int     `json:"row_start" xml:"row_start"`
+	RowEnd          int     `json:"row_end" xml:"row_end"`
+	SeatType        string  `json:"seat_type" xml:"seat_type"`
+	PriceMultiplier float64 `json:"price_multiplier" xml:"price_multiplier"` // 0 means 1
+}
+
+type Event struct {
+	ExternalID      string  `json:"external_id" xml:"external_id"`
+	VenueExternalID string  `json:"venue_external_id" xml:"venue_external_id"`
+	Name            string  `json:"name" xml:"name"`
+	Description     string  `json:"description" xml:"description"`
+	StartTime       string  `json:"start_time" xml:"start_time"` // RFC3339
+	EndTime         string  `json:"end_time" xml:"end_time"`
+	Price           float64 `json:"price" xml:"price"`
+	EventType       string  `json:"event_type" xml:"event_type"`
+	IsHighDemand    bool    `json:"is_high_demand" xml:"is_high_demand"`
+}
+
+// Parse decodes a feed in the given format, or in the one its content looks like when format
+// is empty
+func Parse(data []byte, format string) (*Feed, error) {
+	if format == "" {
+		format = FormatJSON
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '<' {
+			format = FormatXML
+		}
+	}
+
+	var feed Feed
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal(data, &feed); err != nil {
+			return nil, fmt.Errorf("invalid JSON feed: %w", err)
+		}
+	case FormatXML:
+		if err := xml.Unmarshal(data, &feed); err != nil {
+			return nil, fmt.Errorf("invalid XML feed: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown feed format %q", format)
+	}
+	return &feed, nil
+}
+
+// Fetcher downloads and parses the partner's feed
+type Fetcher struct {
+	url    string
+	format string
+	token  string
+	client *http.Client
+}
+
+// NewFetcher returns a fetcher of the feed at url. The token, if any, is sent as a bearer
+// token; an empty format is detected from the content.
+func NewFetcher(url, format, token string, timeout time.Duration) (*Fetcher, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "" && format != FormatJSON && format != FormatXML {
+		return nil, fmt.Errorf("unknown catalog feed format %q", format)
+	}
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &Fetcher{
+		url:    url,
+		format: format,
+		token:  token,
+		client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Fetch downloads the feed
+func (f *Fetcher) Fetch(ctx context.Context) (*Feed, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build feed request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json, application/xml")
+	if f.token != "" {
+		req.Header.Set("Authorization", "Bearer "+f.token)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch feed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch feed: unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read feed: %w", err)
+	}
+	if len(data) > maxFeedSize {
+		return nil, fmt.Errorf("feed is larger than %d bytes", maxFeedSize)
+	}
+
+	format := f.format
+	if format == "" {
+		format = formatOf(resp.Header.Get("Content-Type"))
+	}
+	return Parse(data, format)
+}
+
+// formatOf maps a content type to a feed format, empty if it doesn't tell
+func formatOf(contentType string) string {
+	switch {
+	case strings.Contains(contentType, "json"):
+		return FormatJSON
+	case strings.Contains(contentType, "xml"):
+		return FormatXML
+	}
+	return ""
+}
@@ -0,0 +1,103 @@
+package catalog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const jsonFeed = `{
+  "venues": [{
+    "external_id": "V-1", "name": "Arena", "address": "1 Main St", "city": "Austin", "state": "TX", "country": "US",
+    "columns": 20,
+    "sections": [{"name": "Floor", "row_start": 1, "row_end": 5, "seat_type": "vip", "price_multiplier": 2}]
+  }],
+  "events": [{
+    "external_id": "E-1", "venue_external_id": "V-1", "name": "Rock Night",
+    "start_time": "2030-05-01T20:00:00Z", "end_time": "2030-05-01T23:00:00Z",
+    "price": 49.5, "event_type": "concert", "is_high_demand": true
+  }]
+}`
+
+const xmlFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<catalog>
+  <venues>
+    <venue>
+      <external_id>V-1</external_id><name>Arena</name><address>1 Main St</address>
+      <city>Austin</city><state>TX</state><country>US</country><columns>20</columns>
+      <sections>
+        <section><name>Floor</name><row_start>1</row_start><row_end>5</row_end><seat_type>vip</seat_type><price_multiplier>2</price_multiplier></section>
+      </sections>
+    </venue>
+  </venues>
+  <events>
+    <event>
+      <external_id>E-1</external_id><venue_external_id>V-1</venue_external_id><name>Rock Night</name>
+      <start_time>2030-05-01T20:00:00Z</start_time><end_time>2030-05-01T23:00:00Z</end_time>
+      <price>49.5</price><event_type>concert</event_type><is_high_demand>true</is_high_demand>
+    </event>
+  </events>
+</catalog>`
+
+func TestParseFormatsAgree(t *testing.T) {
+	fromJSON, err := Parse([]byte(jsonFeed), "")
+	if err != nil {
+		t.Fatalf("parse JSON: %v", err)
+	}
+	fromXML, err := Parse([]byte(xmlFeed), "")
+	if err != nil {
+		t.Fatalf("parse XML: %v", err)
+	}
+
+	fromJSON.XMLName = fromXML.XMLName // only set when decoding XML
+	if !reflect.DeepEqual(fromJSON, fromXML) {
+		t.Errorf("JSON feed %+v differs from XML feed %+v", fromJSON, fromXML)
+	}
+	if len(fromJSON.Venues) != 1 || len(fromJSON.Venues[0].Sections) != 1 || len(fromJSON.Events) != 1 {
+		t.Fatalf("unexpected feed %+v", fromJSON)
+	}
+	if event := fromJSON.Events[0]; event.VenueExternalID != "V-1" || event.Price != 49.5 || !event.IsHighDemand {
+		t.Errorf("unexpected event %+v", event)
+	}
+}
+
+func TestParseRejectsMismatchedFormat(t *testing.T) {
+	if _, err := Parse([]byte(xmlFeed), FormatJSON); err == nil {
+		t.Error("expected an XML document to fail as JSON")
+	}
+	if _, err := Parse([]byte(jsonFeed), "csv"); err == nil {
+		t.Error("expected an unknown format to fail")
+	}
+}
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(xmlFeed))
+	}))
+	defer server.Close()
+
+	fetcher, err := NewFetcher(server.URL, "", "secret", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed, err := fetcher.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if len(feed.Venues) != 1 || feed.Venues[0].ExternalID != "V-1" {
+		t.Errorf("unexpected feed %+v", feed)
+	}
+
+	unauthorized, _ := NewFetcher(server.URL, "", "", time.Second)
+	if _, err := unauthorized.Fetch(context.Background()); err == nil {
+		t.Error("expected a non-200 response to fail")
+	}
+}
@@ -1,13 +1,32 @@
 package container
 
 import (
+	"api/constants"
+	"api/internal/alerting"
+	"api/internal/catalog"
 	"api/internal/config"
 	"api/internal/db"
+	"api/internal/domain"
+	"api/internal/encryption"
+	"api/internal/enrichment"
 	"api/internal/entities"
+	"api/internal/geoip"
+	"api/internal/inventory"
+	"api/internal/jobs"
 	"api/internal/middleware"
+	"api/internal/notifications"
+	"api/internal/password"
+	"api/internal/payments"
 	redisconn "api/internal/redis"
 	"api/internal/repository"
 	"api/internal/services"
+	"api/internal/storage"
+	"api/internal/tasks"
+	"api/internal/tickets"
+	logger "api/pkg/logging"
+	"api/pkg/money"
+	"context"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
@@ -15,19 +34,63 @@ import (
 
 // Container holds all application dependencies
 type Container struct {
-	Config           *config.Config
-	DB               *gorm.DB
-	Redis            *redis.Client
-	UserService      *services.UserService
-	JWTService       *services.JWTService
-	EventService     *services.EventService
-	VenueService     *services.VenueService
-	BookingService   *services.BookingService
-	SeatLockService  *services.SeatLockService
-	WaitlistService  *services.WaitlistService
-	AnalyticsService services.AnalyticsServiceInterface
-	JWTMiddleware    *middleware.JWTMiddleware
-	RateLimiter      *middleware.RateLimiter
+	Config            *config.Config
+	DB                *gorm.DB
+	Redis             redis.UniversalClient
+	RedisHealth       *redisconn.Health
+	UserService       *services.UserService
+	AccountService    *services.AccountService
+	PolicyService     *services.PolicyService
+	SettingsService   *services.SettingsService
+	JWTService        *services.JWTService
+	EventService      *services.EventService
+	VenueService      *services.VenueService
+	BookingService    *services.BookingService
+	SeatLockService   *services.SeatLockService
+	LockDivergence    *services.LockDivergenceDetector
+	DeadLetters       *services.DeadLetterService
+	WaitlistService   *services.WaitlistService
+	QueueService      *services.QueueService
+	AnalyticsService  services.AnalyticsServiceInterface
+	ImportService     *services.ImportService
+	CatalogSync       *services.CatalogSyncService
+	SeatBitmaps       *services.SeatBitmapService
+	ReminderService   *services.ReminderService
+	AttendanceService *services.AttendanceService
+	TicketService     *services.TicketService
+	ScannerService    *services.ScannerService
+	Autocomplete      *services.AutocompleteService
+	DisputeService    *services.DisputeService
+	SupportService    *services.SupportService
+	EventPosts        *services.EventPostService
+	PaymentService    *services.PaymentService
+	LoyaltyService    *services.LoyaltyService
+	PresaleService    *services.PresaleService
+	SaleRegionService *services.SaleRegionService
+	ArtifactService   *services.ArtifactService
+	TenantService     *services.TenantService
+	FeedService       *services.FeedService
+	ReferralService   *services.ReferralService
+	BoxOfficeService  *services.BoxOfficeService
+	SettlementService *services.SettlementService
+	LedgerService     *services.LedgerService
+	TaskService       *services.TaskService
+	ArchiveService    *services.ArchiveService
+	TaskQueue         *tasks.Queue
+	InventorySync     *inventory.Consumer // nil unless an inventory sync backend is configured
+	BookingLimiter    *middleware.Backpressure
+	Drain             *middleware.Drain
+	Storage           storage.Storage
+	GeoIP             geoip.Locator // nil unless a GeoIP provider is configured
+	Enrichment        *enrichment.Registry
+	Keyring           *encryption.Keyring
+	Notifier          notifications.Notifier
+	Scheduler         *jobs.Scheduler
+	JWTMiddleware     *middleware.JWTMiddleware
+	RateLimiter       *middleware.RateLimiter
+	Allowlist         *middleware.Allowlist
+	WebhookVerifier   *middleware.WebhookVerifier
+	MockPayments      *payments.MockProvider // nil unless the mock payment provider is enabled
 }
 
 // NewContainer creates a new dependency container
@@ -38,71 +101,498 @@ func NewContainer() (*Container, error) {
 		return nil, err
 	}
 
+	// Sensitive fields tagged `serializer:encrypted` are encrypted at rest
+	keyring, err := encryption.NewKeyring(encryption.Config{
+		Keys:           cfg.EncryptionKeys,
+		KeysFile:       cfg.EncryptionKeysFile,
+		ActiveKeyID:    cfg.EncryptionActiveKeyID,
+		Key:            cfg.EncryptionKey,
+		FallbackSecret: cfg.JwtSecret,
+	})
+	if err != nil {
+		return nil, err
+	}
+	encryption.Register(keyring)
+
+	// New passwords use the configured algorithm; older hashes are upgraded on login
+	hasher, err := password.NewHasher(password.Params{
+		Algorithm:         cfg.PasswordHashAlgorithm,
+		Argon2Memory:      cfg.Argon2Memory,
+		Argon2Iterations:  cfg.Argon2Iterations,
+		Argon2Parallelism: cfg.Argon2Parallelism,
+		BcryptCost:        cfg.BcryptCost,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	// Connect to database
-	database, err := db.Connect(cfg.DBUrl)
+	dbConfig := db.Config{
+		DSN:                      cfg.DBUrl,
+		MaxOpenConns:             cfg.DBMaxOpenConns,
+		MaxIdleConns:             cfg.DBMaxIdleConns,
+		ConnMaxLifetime:          cfg.DBConnMaxLifetime,
+		ConnMaxIdleTime:          cfg.DBConnMaxIdleTime,
+		StatementTimeout:         cfg.DBStatementTimeout,
+		LockTimeout:              cfg.DBLockTimeout,
+		IdleInTransactionTimeout: cfg.DBIdleInTxTimeout,
+		SlowQueryThreshold:       cfg.DBSlowQueryThreshold,
+		PrepareStmt:              cfg.DBPrepareStmt,
+		PrepareStmtMaxSize:       cfg.DBPrepareStmtMaxSize,
+		PrepareStmtTTL:           cfg.DBPrepareStmtTTL,
+		QueryExecMode:            cfg.DBQueryExecMode,
+		StatementCacheCapacity:   cfg.DBStatementCacheSize,
+		TransactionPooling:       cfg.DBTransactionPooling,
+	}
+	database, err := db.Connect(dbConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	// Connect to Redis
-	redisWrapper := redisconn.NewRedisClient(cfg.RedisUrl)
+	// Connect to Redis: a single node, a cluster or a sentinel-managed master
+	redisWrapper, err := redisconn.NewRedisClient(redisconn.Config{
+		Mode:             cfg.RedisMode,
+		URL:              cfg.RedisUrl,
+		Addrs:            cfg.RedisAddrs,
+		MasterName:       cfg.RedisMasterName,
+		Username:         cfg.RedisUsername,
+		Password:         cfg.RedisPassword,
+		SentinelPassword: cfg.RedisSentinelPassword,
+	})
+	if err != nil {
+		return nil, err
+	}
 	redisClient := redisWrapper.Client
+	// Connection errors switch to degraded mode, where Redis is skipped until it answers again
+	redisHealth := redisconn.NewHealth(redisClient, cfg.RedisHealthInterval)
+
+	// Venues, events and bookings that predate multi-tenancy belong to the default tenant,
+	// which has to exist before the tenant foreign keys are created
+	if err := database.AutoMigrate(&entities.Tenant{}); err != nil {
+		return nil, err
+	}
+	if err := repository.NewTenantRepository(database).EnsureDefaultTenant(context.Background()); err != nil {
+		return nil, err
+	}
+
+	// Amounts are stored in minor units; convert columns that still hold decimals first, since
+	// AutoMigrate would cast them as they are
+	converted, err := repository.NewMoneyRepository(database).ConvertColumns(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if converted > 0 {
+		logger.Infof("Converted %d amount columns to minor units", converted)
+	}
 
 	// Run migrations
 	if err := database.AutoMigrate(
 		&entities.User{},
 		&entities.Venue{},
+		&entities.VenueSection{},
 		&entities.Event{},
 		&entities.Seat{},
 		&entities.BookingIntent{},
+		&entities.PaymentAttempt{},
+		&entities.PaymentTransaction{},
+		&entities.PaymentTransactionEvent{},
 		&entities.Booking{},
 		&entities.EventQueue{},
+		&entities.BookingReminder{},
+		&entities.Dispute{},
+		&entities.DisputeEvent{},
+		&entities.SupportCase{},
+		&entities.SupportCaseMessage{},
+		&entities.EventPost{},
+		&entities.LoyaltyTransaction{},
+		&entities.LedgerJournal{},
+		&entities.LedgerEntry{},
+		&entities.PresaleBatch{},
+		&entities.PresaleCode{},
+		&entities.SaleRegionOverride{},
+		&entities.EventReview{},
+		&entities.Referrer{},
+		&entities.ReferralCommission{},
+		&entities.EventSettlement{},
+		&entities.ScannerDevice{},
+		&entities.SeatRelease{},
+		&entities.SeatPriceHistory{},
+		&entities.Artifact{},
+		&entities.Task{},
+		&entities.DeadLetter{},
+		&entities.CatalogSyncRun{},
+		&entities.PolicyAcceptance{},
+		&entities.RuntimeSetting{},
+		&entities.SettingChange{},
 	); err != nil {
 		return nil, err
 	}
 
+	// Intents created before lock expiries were stored get theirs from their creation time
+	if err := repository.NewBookingRepository(database).BackfillLockExpiry(context.Background()); err != nil {
+		return nil, err
+	}
+
+	// Archive tables mirror bookings and booking intents, so they follow the migrated tables
+	if err := repository.NewArchiveRepository(database).EnsureTables(context.Background()); err != nil {
+		return nil, err
+	}
+
+	// Venue and city autocomplete matches prefixes and trigrams through their own indexes
+	autocompleteRepo := repository.NewAutocompleteRepository(database, redisClient, cfg.AutocompleteCacheTTL)
+	if err := autocompleteRepo.EnsureIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+
+	// Repositories query through the prepared statement cache, if enabled, from here on
+	database = db.Prepared(database, dbConfig)
+
 	// Initialize repositories
-	userRepo := repository.NewUserRepository(database)
+	tenantRepo := repository.NewTenantRepository(database)
+	userRepo := repository.NewUserRepository(database, hasher)
 	venueRepo := repository.NewVenueRepository(database)
 	eventRepo := repository.NewEventRepository(database)
 	analyticsRepo := repository.NewAnalyticsRepository(database)
+	importRepo := repository.NewImportRepository(database)
+	reminderRepo := repository.NewReminderRepository(database)
+	attendanceRepo := repository.NewAttendanceRepository(database)
+	disputeRepo := repository.NewDisputeRepository(database)
+	supportRepo := repository.NewSupportRepository(database)
+	eventPostRepo := repository.NewEventPostRepository(database)
+	paymentRepo := repository.NewPaymentRepository(database)
+	loyaltyRepo := repository.NewLoyaltyRepository(database)
+	presaleRepo := repository.NewPresaleRepository(database)
+	saleRegionRepo := repository.NewSaleRegionRepository(database)
+	artifactRepo := repository.NewArtifactRepository(database)
+	taskRepo := repository.NewTaskRepository(database)
+	archiveRepo := repository.NewArchiveRepository(database)
+	feedRepo := repository.NewFeedRepository(database)
+	referralRepo := repository.NewReferralRepository(database)
+	boxOfficeRepo := repository.NewBoxOfficeRepository(database)
+	settlementRepo := repository.NewSettlementRepository(database)
+	ledgerRepo := repository.NewLedgerRepository(database)
+	deadLetterRepo := repository.NewDeadLetterRepository(database)
+	scannerRepo := repository.NewScannerRepository(database)
+
+	// Async work that fails is kept as dead letters for platform admins to retry or discard
+	deadLetterService := services.NewDeadLetterService(deadLetterRepo)
+
+	// Notifications are logged until a delivery provider is configured
+	notifier := deadLetterService.Notifier(notifications.NewLogNotifier())
+	// Operational events for admins go to their team chat
+	adminChannel, err := notifications.NewAdminChannel(notifications.AdminChannelConfig{
+		Channel:    cfg.AdminNotifyChannel,
+		WebhookURL: cfg.AdminNotifyWebhookURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+	adminChannel = deadLetterService.AdminChannel(adminChannel)
+
+	// Generated artifacts (tickets, receipts, exports) go to the configured storage backend
+	store, err := storage.New(storage.Config{
+		Backend:       cfg.StorageBackend,
+		LocalDir:      cfg.StorageLocalDir,
+		PublicURL:     cfg.StoragePublicURL,
+		SigningSecret: cfg.StorageSigningSecret,
+		Bucket:        cfg.StorageBucket,
+		Region:        cfg.StorageRegion,
+		Endpoint:      cfg.StorageEndpoint,
+		AccessKey:     cfg.StorageAccessKey,
+		SecretKey:     cfg.StorageSecretKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Clients are located for events' sale countries, which aren't enforced without a provider
+	locator, err := geoip.New(geoip.Config{
+		Provider: cfg.GeoIPProvider,
+		Header:   cfg.GeoIPHeader,
+		URL:      cfg.GeoIPURL,
+		Timeout:  cfg.GeoIPTimeout,
+		CacheTTL: cfg.GeoIPCacheTTL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Outside data such as weather forecasts, added to event details at venues that enable it
+	enrichers := enrichment.New(enrichment.Config{
+		WeatherURL: cfg.WeatherForecastURL,
+		Timeout:    cfg.EnrichmentTimeout,
+		CacheTTL:   cfg.WeatherCacheTTL,
+	})
+
+	// Booking failure, lock contention and payment error alerts go to the on-call channel
+	alertChannel, err := alerting.New(alerting.Config{
+		Channel:             cfg.AlertChannel,
+		SlackWebhookURL:     cfg.AlertSlackWebhookURL,
+		PagerDutyRoutingKey: cfg.AlertPagerDutyRoutingKey,
+		Source:              cfg.AlertSource,
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	// Initialize services
-	jwtService := services.NewJWTService(cfg.JwtSecret)
-	userService := services.NewUserService(userRepo)
+	jwtService := services.NewJWTService(cfg.JwtSecret, cfg.JwtIssuer, cfg.JwtAudience, cfg.JwtExpiry)
+	userService := services.NewUserService(userRepo).WithTokenVersionCache(cfg.JwtVersionCacheTTL)
 	venueService := services.NewVenueService(venueRepo)
-	eventService := services.NewEventService(eventRepo)
+	// Background tasks run on a worker pool; services enqueue them and register their handlers
+	taskQueue := tasks.NewQueue(taskRepo, cfg.TaskWorkers, cfg.TaskMaxAttempts)
+	eventService := services.NewEventService(eventRepo, taskQueue)
+	// Public event reads are served from Redis so an on-sale's first stampede doesn't hit Postgres
+	if cfg.EventCacheTTL > 0 {
+		eventService.WithCache(repository.NewEventCacheRepository(redisClient, cfg.EventCacheTTL), cfg.CachePreheatWindow)
+	}
 	seatLockService := services.NewSeatLockService(redisClient)
-	analyticsService := services.NewAnalyticsService(analyticsRepo)
+	importService := services.NewImportService(importRepo)
+	// A partner's catalog feed of venues and events, synced on a schedule when configured
+	var catalogFetcher *catalog.Fetcher
+	if cfg.CatalogFeedURL != "" {
+		if catalogFetcher, err = catalog.NewFetcher(cfg.CatalogFeedURL, cfg.CatalogFeedFormat, cfg.CatalogFeedToken, 0); err != nil {
+			return nil, err
+		}
+	}
+	catalogSync := services.NewCatalogSyncService(repository.NewCatalogRepository(database), importRepo, repository.NewUnitOfWork(database),
+		catalogFetcher, cfg.CatalogFeedSource, cfg.CatalogFeedTenantID, cfg.CatalogSyncInterval)
+	reminderService := services.NewReminderService(reminderRepo, notifier)
+	// Ticket QR codes are signed so door scanners can validate them offline
+	ticketSigner, err := tickets.NewSignerFromConfig(cfg.TicketSigningKey, cfg.JwtSecret)
+	if err != nil {
+		return nil, err
+	}
+	ticketService := services.NewTicketService(ticketSigner, attendanceRepo, cfg.TicketValidBefore)
+	scannerService := services.NewScannerService(scannerRepo, ticketService)
+	autocompleteService := services.NewAutocompleteService(autocompleteRepo)
+	disputeService := services.NewDisputeService(disputeRepo, userRepo, notifier, cfg.RevokeTicketsOnDispute)
+	supportService := services.NewSupportService(supportRepo, notifier)
+	eventPostService := services.NewEventPostService(eventPostRepo, notifier)
+	paymentService := services.NewPaymentService(paymentRepo)
+	loyaltyService := services.NewLoyaltyService(loyaltyRepo)
+	presaleService := services.NewPresaleService(presaleRepo)
+	saleRegionService := services.NewSaleRegionService(saleRegionRepo)
+	artifactService := services.NewArtifactService(artifactRepo, store, cfg.StorageURLTTL, cfg.ArtifactRetention)
+	tenantService := services.NewTenantService(tenantRepo)
+	feedService := services.NewFeedService(feedRepo)
+	referralService := services.NewReferralService(referralRepo, cfg.ReferralCommissionRate)
+	settlementService := services.NewSettlementService(settlementRepo, cfg.PlatformFeePercent, money.FromMajor(cfg.PlatformFeePerTicket))
+	ledgerService := services.NewLedgerService(ledgerRepo)
+	taskService := services.NewTaskService(taskRepo)
+	archiveService := services.NewArchiveService(archiveRepo, taskQueue, cfg.ArchiveAfterMonths)
 
-	// BookingRepository needs SeatLockRepository as dependency
+	// BookingService locks seats through SeatLockRepository
 	seatLockRepo := repository.NewSeatLockRepository(redisClient)
-	bookingRepo := repository.NewBookingRepository(database, seatLockRepo)
+	// The booking flow feeds the live on-sale dashboard's counters
+	liveStatsRepo := repository.NewLiveStatsRepository(redisClient)
+	bookingRepo := repository.NewBookingRepository(database)
 	
 	// Initialize waitlist services
 	waitlistRepo := repository.NewWaitlistRepository(redisClient)
-	waitlistService := services.NewWaitlistService(waitlistRepo, eventRepo, database)
+	// Waitlist keys now carry the event hash tag; rename any written before
+	if renamed, err := waitlistRepo.MigrateKeys(context.Background()); err != nil {
+		logger.Warnf("Failed to migrate waitlist keys: %v", err)
+	} else if renamed > 0 {
+		logger.Infof("Migrated %d waitlist keys", renamed)
+	}
+	waitlistService := services.NewWaitlistService(waitlistRepo, eventRepo, userRepo, database, redisHealth, notifier)
+	redisHealth.OnRecover(waitlistService.RestoreWaitlists)
+	// On-sale queues of high-demand events, separate from the waitlist of sold-out ones
+	queueService := services.NewQueueService(repository.NewQueueRepository(database, redisClient), eventRepo, redisHealth)
+	redisHealth.OnRecover(queueService.RestoreQueues)
+	analyticsService := services.NewAnalyticsService(analyticsRepo, liveStatsRepo, waitlistRepo)
 	
-	// BookingService needs WaitlistService as dependency
-	bookingService := services.NewBookingService(bookingRepo, seatLockService, waitlistService)
+	// Other modules follow the booking workflow through the domain events it publishes
+	bookingEvents := domain.NewDispatcher()
+	attendanceService := services.NewAttendanceService(attendanceRepo, notifier, cfg.FeedbackRequestsEnabled).WithEvents(bookingEvents)
+	deadLetterService.WatchDispatcher(bookingEvents)
+	services.NewBookingNotifications(userRepo, notifier).Subscribe(bookingEvents)
+	services.SubscribeLiveStats(bookingEvents, liveStatsRepo)
+	eventService.SubscribeCache(bookingEvents)
+	// Listings read available seats from Redis counters the booking workflow keeps in step
+	availabilityRepo := repository.NewAvailabilityRepository(database, redisClient)
+	availabilityCounters := services.NewAvailabilityCounters(availabilityRepo)
+	availabilityCounters.Subscribe(bookingEvents)
+	eventService.WithAvailabilityCounters(availabilityCounters)
+	// Admins hear of sell-outs, large refunds, lock discrepancies and saturated queues
+	adminNotifications := services.NewAdminNotifications(adminChannel, availabilityRepo, money.FromMajor(cfg.AdminNotifyLargeRefund))
+	adminNotifications.Subscribe(bookingEvents)
+	queueService.WithSaturationAlerts(adminNotifications, cfg.AdminNotifyQueueSaturation)
+	// Seat maps of large venues are also served as bitmaps the booking workflow patches
+	seatBitmaps := services.NewSeatBitmapService(repository.NewSeatBitmapRepository(database, redisClient))
+	seatBitmaps.Subscribe(bookingEvents)
+	// Seats freed by cancellations and expired holds go to the next user on the waitlist
+	waitlistService.Subscribe(bookingEvents)
+	queueService.Subscribe(bookingEvents)
+	bookingPolicy := services.DefaultBookingPolicy()
+	bookingPolicy.MaxPendingIntents = cfg.BookingMaxPendingIntents
+	bookingPolicy.FreeTicketLimit = cfg.FreeEventTicketLimit
+	// Platform admins tune lock durations, the waitlist hold and fees at runtime
+	settingsService := services.NewSettingsService(repository.NewSettingsRepository(database), cfg.SettingsCacheTTL, services.RuntimeSettings(services.SettingDefaults{
+		LockDuration: bookingPolicy.LockDuration,
+		WaitlistHold: constants.WaitlistPromotionHold * time.Minute,
+		FeePercent:   cfg.PlatformFeePercent,
+		FeePerTicket: cfg.PlatformFeePerTicket,
+	}))
+	settlementService.WithSettings(settingsService)
+	waitlistService.WithSettings(settingsService)
+	bookingService := services.NewBookingService(bookingRepo, seatLockRepo, liveStatsRepo, repository.NewUnitOfWork(database), bookingEvents, bookingPolicy).
+		WithSettings(settingsService)
+	if locator != nil {
+		bookingService.WithSaleRegions(saleRegionService)
+	}
+	boxOfficeService := services.NewBoxOfficeService(boxOfficeRepo, seatLockRepo)
+	oversellService := services.NewOversellService(repository.NewOversellRepository(database), waitlistService, bookingEvents, cfg.FreeEventTicketLimit)
+	policyService := services.NewPolicyService(repository.NewPolicyRepository(database), cfg.TermsVersion, cfg.PrivacyPolicyVersion)
+	accountService := services.NewAccountService(repository.NewAccountRepository(database), userService, bookingService, notifier, cfg.AccountDeletionGrace, cfg.SiteURL)
+
+	// Seat holds and releases from venues' external inventory systems, consumed once main starts
+	inventorySource, err := inventory.New(inventory.Config{
+		Backend:          cfg.InventorySyncBackend,
+		Stream:           cfg.InventorySyncStream,
+		Group:            cfg.InventorySyncGroup,
+		DeadLetterStream: cfg.InventorySyncDeadLetterStream,
+		RetryAfter:       cfg.InventorySyncRetryAfter,
+	}, redisClient)
+	if err != nil {
+		return nil, err
+	}
+	var inventorySync *inventory.Consumer
+	if inventorySource != nil {
+		inventorySyncService := services.NewInventorySyncService(repository.NewInventorySyncRepository(database), seatLockRepo)
+		inventorySync = inventory.NewConsumer(inventorySource, inventorySyncService.Apply, cfg.InventorySyncMaxAttempts)
+	}
+
+	// Seats whose database and Redis locks disagree past the grace period are repaired or reported
+	lockDivergence, err := services.NewLockDivergenceDetector(bookingRepo, seatLockRepo, redisHealth, cfg.LockDivergencePolicy, cfg.LockDivergenceGrace)
+	if err != nil {
+		return nil, err
+	}
+	lockDivergence.WithAdminNotifications(adminNotifications)
 
-	jwtMiddleware := middleware.NewJWTMiddleware(jwtService)
-	rateLimiter := middleware.NewRateLimiter(redisClient)
+	alertMonitor := services.NewAlertMonitor(liveStatsRepo, paymentRepo, deadLetterRepo, redisHealth, alertChannel, services.AlertThresholds{
+		Window:                 cfg.AlertWindow,
+		MinSamples:             cfg.AlertMinSamples,
+		BookingFailureRate:     cfg.AlertBookingFailureRate,
+		LockConflictsPerMinute: cfg.AlertLockConflictsPerMinute,
+		PaymentErrorRate:       cfg.AlertPaymentErrorRate,
+		DeadLetters:            cfg.AlertDeadLetters,
+	})
+
+	// Background jobs, started by main once the server is up. Instances claim each run through
+	// Redis, so every job runs once per interval across the fleet.
+	scheduler := jobs.NewScheduler().WithLocker(repository.NewJobLockRepository(redisClient))
+	scheduler.Register("booking_reminders", time.Minute, reminderService.SendDueReminders)
+	scheduler.Register("event_follow_up", 5*time.Minute, attendanceService.ProcessCompletedEvents)
+	scheduler.Register("event_announcements", time.Minute, eventPostService.SendAnnouncements)
+	// Seats of no-shows go back on sale at the door after their event's cutoff
+	scheduler.Register("no_show_release", time.Minute, attendanceService.ReleaseNoShows)
+	scheduler.Register("abandoned_intents", 15*time.Second, bookingService.ReleaseAbandonedIntents)
+	// Also checks that the Redis locks of pending intents expire with them
+	scheduler.Register("expired_intents", 30*time.Second, bookingService.CleanupExpiredIntents)
+	scheduler.Register("artifact_cleanup", time.Hour, artifactService.CleanupExpired)
+	// Accounts deleted past their grace period lose their personal data
+	scheduler.Register("account_anonymization", time.Hour, accountService.AnonymizeDueAccounts)
+	scheduler.Register("seat_lock_divergence", time.Minute, lockDivergence.Check)
+	scheduler.Register("booking_alerts", cfg.AlertInterval, alertMonitor.Evaluate)
+	// Notified waitlist users who didn't book in time lose their place
+	scheduler.Register("waitlist_cleanup", time.Minute, waitlistService.CleanupExpiredWaitlist)
+	// Sold out free events book their oversell places for the users next on the waitlist
+	scheduler.Register("oversell_promotions", time.Minute, oversellService.PromoteWaitlists)
+	// Admits the next users of each on-sale queue as earlier admissions are used or expire
+	scheduler.Register("queue_admission", 10*time.Second, queueService.AdmitQueues)
+	// Corrects counters that missed sales made outside the booking workflow, e.g. at the door
+	scheduler.Register("availability_reconcile", cfg.AvailabilityReconcileInterval, availabilityCounters.Reconcile)
+	if cfg.EventCacheTTL > 0 {
+		// Keeps hot events cached ahead of their on-sale; entries are refreshed before they expire
+		scheduler.Register("cache_preheat", max(cfg.EventCacheTTL/2, 5*time.Second), eventService.PreheatCache)
+	}
+	if catalogSync.Enabled() {
+		scheduler.Register("catalog_sync", catalogSync.Interval(), catalogSync.Sync)
+	}
+
+	taskQueue.Register(constants.TaskKindEventCreation, eventService.RunEventCreation)
+	taskQueue.Register(constants.TaskKindArchival, archiveService.RunArchival)
+
+	jwtMiddleware := middleware.NewJWTMiddleware(jwtService, userService)
+	// Monitoring probes and internal tooling listed here bypass rate limiting
+	allowlist, err := middleware.NewAllowlist(redisClient, cfg.RateLimitAllowlistIPs, cfg.RateLimitAllowlistUsers)
+	if err != nil {
+		return nil, err
+	}
+	rateLimiter := middleware.NewRateLimiter(redisClient, allowlist, redisHealth)
+	webhookVerifier := middleware.NewWebhookVerifier(cfg.PaymentWebhookSecret, cfg.PaymentWebhookTolerance, redisClient, redisHealth)
+	// The mock payment provider lets dev and E2E runs pay for bookings without a provider account
+	var mockPayments *payments.MockProvider
+	if cfg.PaymentMockEnabled {
+		mockPayments = payments.NewMockProvider(payments.MockConfig{
+			Delay:         cfg.PaymentMockDelay,
+			WebhookURL:    cfg.PaymentMockWebhookURL,
+			WebhookSecret: cfg.PaymentWebhookSecret,
+		})
+	}
+	// Booking intents and confirmations are the heaviest database work; cap them during flash sales
+	bookingLimiter := middleware.NewBackpressure("bookings", cfg.BookingMaxConcurrency, cfg.BookingMaxQueue, cfg.BookingQueueTimeout)
 
 	return &Container{
-		Config:           cfg,
-		DB:               database,
-		Redis:            redisClient,
-		UserService:      userService,
-		JWTService:       jwtService,
-		EventService:     eventService,
-		VenueService:     venueService,
-		BookingService:   bookingService,
-		SeatLockService:  seatLockService,
-		WaitlistService:  waitlistService,
-		AnalyticsService: analyticsService,
-		JWTMiddleware:    jwtMiddleware,
-		RateLimiter:      rateLimiter,
+		Config:            cfg,
+		DB:                database,
+		Redis:             redisClient,
+		RedisHealth:       redisHealth,
+		UserService:       userService,
+		AccountService:    accountService,
+		PolicyService:     policyService,
+		SettingsService:   settingsService,
+		JWTService:        jwtService,
+		EventService:      eventService,
+		VenueService:      venueService,
+		BookingService:    bookingService,
+		SeatLockService:   seatLockService,
+		LockDivergence:    lockDivergence,
+		DeadLetters:       deadLetterService,
+		WaitlistService:   waitlistService,
+		QueueService:      queueService,
+		AnalyticsService:  analyticsService,
+		ImportService:     importService,
+		CatalogSync:       catalogSync,
+		SeatBitmaps:       seatBitmaps,
+		ReminderService:   reminderService,
+		AttendanceService: attendanceService,
+		TicketService:     ticketService,
+		ScannerService:    scannerService,
+		Autocomplete:      autocompleteService,
+		DisputeService:    disputeService,
+		SupportService:    supportService,
+		EventPosts:        eventPostService,
+		PaymentService:    paymentService,
+		LoyaltyService:    loyaltyService,
+		PresaleService:    presaleService,
+		SaleRegionService: saleRegionService,
+		ArtifactService:   artifactService,
+		TenantService:     tenantService,
+		FeedService:       feedService,
+		ReferralService:   referralService,
+		BoxOfficeService:  boxOfficeService,
+		SettlementService: settlementService,
+		LedgerService:     ledgerService,
+		TaskService:       taskService,
+		ArchiveService:    archiveService,
+		TaskQueue:         taskQueue,
+		InventorySync:     inventorySync,
+		BookingLimiter:    bookingLimiter,
+		Drain:             middleware.NewDrain(),
+		Storage:           store,
+		GeoIP:             locator,
+		Enrichment:        enrichers,
+		Keyring:           keyring,
+		Notifier:          notifier,
+		Scheduler:         scheduler,
+		JWTMiddleware:     jwtMiddleware,
+		RateLimiter:       rateLimiter,
+		Allowlist:         allowlist,
+		WebhookVerifier:   webhookVerifier,
+		MockPayments:      mockPayments,
 	}, nil
 }
 
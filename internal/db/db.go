@@ -1,8 +1,13 @@
 package db
 
 import (
+	"fmt"
 	"log"
+	"net/url"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"gorm.io/driver/postgres"
@@ -10,21 +15,79 @@ import (
 	"gorm.io/gorm/logger"
 )
 
-func Connect(dsn string) (*gorm.DB, error) {
-	// Custom logger configuration
+// Config holds the connection pool and Postgres session settings. Zero timeouts are left
+// to the server's defaults.
+type Config struct {
+	DSN             string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	// StatementTimeout aborts any statement running longer, so runaway queries free their connection
+	StatementTimeout time.Duration
+	// LockTimeout aborts statements waiting longer for a row or table lock
+	LockTimeout time.Duration
+	// IdleInTransactionTimeout ends sessions left idle inside an open transaction
+	IdleInTransactionTimeout time.Duration
+	// SlowQueryThreshold is the duration above which queries are logged as slow; 0 disables
+	SlowQueryThreshold time.Duration
+	// PrepareStmt sends queries through GORM's prepared statement cache once Prepared is applied.
+	// The cache keeps at most PrepareStmtMaxSize statements, each for up to PrepareStmtTTL.
+	PrepareStmt        bool
+	PrepareStmtMaxSize int
+	PrepareStmtTTL     time.Duration
+	// QueryExecMode is the driver's default_query_exec_mode; empty keeps the driver default,
+	// cache_statement, which prepares and caches statements per connection
+	QueryExecMode string
+	// StatementCacheCapacity is the size of each connection's statement cache; 0 keeps the driver default
+	StatementCacheCapacity int
+	// TransactionPooling is set when the database is reached through a transaction-pooling proxy
+	// such as pgbouncer, which hands each transaction to any server connection
+	TransactionPooling bool
+}
+
+// queryExecModes are the values the driver accepts for default_query_exec_mode
+var queryExecModes = map[string]bool{
+	"cache_statement": true,
+	"cache_describe":  true,
+	"describe_exec":   true,
+	"exec":            true,
+	"simple_protocol": true,
+}
+
+func Connect(cfg Config) (*gorm.DB, error) {
+	// Custom logger configuration; slow queries are logged by queryLogger instead
 	newLogger := logger.New(
 		log.New(os.Stdout, "\r\n", log.LstdFlags), // io writer
 		logger.Config{
-			SlowThreshold:             200 * time.Millisecond, // Slow SQL threshold
-			LogLevel:                  logger.Warn,            // Log level
-			IgnoreRecordNotFoundError: false,                  // Ignore ErrRecordNotFound error for logger
-			Colorful:                  false,                  // Disable color for production
+			SlowThreshold:             0,           // Slow SQL logging disabled
+			LogLevel:                  logger.Warn, // Log level
+			IgnoreRecordNotFoundError: false,       // Ignore ErrRecordNotFound error for logger
+			Colorful:                  false,       // Disable color for production
 		},
 	)
 
+	if cfg.QueryExecMode != "" && !queryExecModes[cfg.QueryExecMode] {
+		return nil, fmt.Errorf("invalid query exec mode %q", cfg.QueryExecMode)
+	}
+	if cfg.TransactionPooling {
+		var err error
+		if cfg, err = forTransactionPooling(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	dsn, err := withDSNParams(cfg.DSN, connectionParams(cfg))
+	if err != nil {
+		return nil, err
+	}
+
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger:                                   newLogger,
-		PrepareStmt:                              false, // Disable prepared statement cache to avoid conflicts
+		Logger: newQueryLogger(newLogger, cfg.SlowQueryThreshold),
+		// Prepared statements are enabled by Prepared after migrations, see there
+		PrepareStmt:                              false,
+		PrepareStmtMaxSize:                       cfg.PrepareStmtMaxSize,
+		PrepareStmtTTL:                           cfg.PrepareStmtTTL,
 		DisableForeignKeyConstraintWhenMigrating: true,
 	})
 	if err != nil {
@@ -38,10 +101,78 @@ func Connect(dsn string) (*gorm.DB, error) {
 	}
 
 	// connection pool to handle high concurrency
-	sqlDB.SetMaxIdleConns(25)
-	sqlDB.SetMaxOpenConns(100)
-	sqlDB.SetConnMaxLifetime(30 * time.Minute)
-	sqlDB.SetConnMaxIdleTime(5 * time.Minute)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
 	return db, nil
 }
+
+// Prepared returns a handle whose queries use GORM's prepared statement cache if cfg.PrepareStmt
+// is set, or database unchanged otherwise. Apply it once migrations have run: statements
+// cached before a migration changes a table fail with "cached plan must not change result
+// type" until they are evicted, which is what PrepareStmt used to run into. The TTL bounds how
+// long statements cached by instances still running during a rolling deploy stay stale.
+func Prepared(database *gorm.DB, cfg Config) *gorm.DB {
+	if !cfg.PrepareStmt {
+		return database
+	}
+	return database.Session(&gorm.Session{PrepareStmt: true})
+}
+
+// connectionParams returns the DSN parameters every connection starts with: Postgres run-time
+// parameters, and statement caching options the driver keeps for itself
+func connectionParams(cfg Config) map[string]string {
+	settings := map[string]string{
+		"application_name": "api",
+	}
+	if cfg.QueryExecMode != "" {
+		settings["default_query_exec_mode"] = cfg.QueryExecMode
+	}
+	if cfg.StatementCacheCapacity > 0 {
+		settings["statement_cache_capacity"] = strconv.Itoa(cfg.StatementCacheCapacity)
+	}
+	if cfg.StatementTimeout > 0 {
+		settings["statement_timeout"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+	if cfg.LockTimeout > 0 {
+		settings["lock_timeout"] = strconv.FormatInt(cfg.LockTimeout.Milliseconds(), 10)
+	}
+	if cfg.IdleInTransactionTimeout > 0 {
+		settings["idle_in_transaction_session_timeout"] = strconv.FormatInt(cfg.IdleInTransactionTimeout.Milliseconds(), 10)
+	}
+	return settings
+}
+
+// withDSNParams adds parameters to a URL or keyword/value DSN. The driver sends run-time
+// parameters when each connection starts. Parameters already in the DSN are kept.
+func withDSNParams(dsn string, settings map[string]string) (string, error) {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return "", fmt.Errorf("invalid database URL: %w", err)
+		}
+		query := u.Query()
+		for _, key := range keys {
+			if query.Get(key) == "" {
+				query.Set(key, settings[key])
+			}
+		}
+		u.RawQuery = query.Encode()
+		return u.String(), nil
+	}
+
+	for _, key := range keys {
+		if !strings.Contains(dsn, key+"=") {
+			dsn += fmt.Sprintf(" %s=%s", key, settings[key])
+		}
+	}
+	return strings.TrimSpace(dsn), nil
+}
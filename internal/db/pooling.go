@@ -0,0 +1,31 @@
+package db
+
+import (
+	logger "api/pkg/logging"
+	"fmt"
+)
+
+// forTransactionPooling checks cfg for features a transaction-pooling proxy breaks. A server
+// connection serves many clients one transaction at a time, so:
+//   - named prepared statements, GORM's cache or the driver's cache_statement mode, may not
+//     exist on the connection the next transaction runs on; the driver defaults to exec mode,
+//     which uses unnamed statements
+//   - session timeouts sent at connection start are rejected by the proxy or would leak to other
+//     clients; they are left out and belong on the database role instead
+func forTransactionPooling(cfg Config) (Config, error) {
+	if cfg.PrepareStmt {
+		return cfg, fmt.Errorf("prepared statement caching can't be used with transaction pooling")
+	}
+	switch cfg.QueryExecMode {
+	case "":
+		cfg.QueryExecMode = "exec"
+	case "cache_statement":
+		return cfg, fmt.Errorf("query exec mode cache_statement can't be used with transaction pooling")
+	}
+
+	if cfg.StatementTimeout > 0 || cfg.LockTimeout > 0 || cfg.IdleInTransactionTimeout > 0 {
+		logger.Warnf("Transaction pooling: session timeouts are not sent, set them on the database role (ALTER ROLE ... SET statement_timeout = ...)")
+		cfg.StatementTimeout, cfg.LockTimeout, cfg.IdleInTransactionTimeout = 0, 0, 0
+	}
+	return cfg, nil
+}
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"api/internal/metrics"
+	logger "api/pkg/logging"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	gormlogger "gorm.io/gorm/logger"
+)
+
+var (
+	queryDuration = metrics.Default.NewHistogramVec("db_query_duration_seconds",
+		"Duration of database queries by repository method.", "method", metrics.DefaultBuckets)
+	slowQueries = metrics.Default.NewCounterVec("db_slow_queries_total",
+		"Queries slower than the slow query threshold by repository method.", "method")
+)
+
+var (
+	stringLiteralPattern = regexp.MustCompile(`'(?:[^']|'')*'`)
+	numberPattern        = regexp.MustCompile(`\$\d+|\b\d+(?:\.\d+)?\b`)
+	valueListPattern     = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	whitespacePattern    = regexp.MustCompile(`\s+`)
+)
+
+// maxLoggedQueryLength keeps slow query log lines readable
+const maxLoggedQueryLength = 500
+
+// queryLogger times every query into the db_query_duration_seconds histogram and logs queries
+// slower than slowThreshold through pkg/logging with a fingerprint, so the same statement
+// can be grouped across log lines whatever its parameters. Errors go to the wrapped logger.
+type queryLogger struct {
+	gormlogger.Interface
+	slowThreshold time.Duration
+}
+
+func newQueryLogger(inner gormlogger.Interface, slowThreshold time.Duration) *queryLogger {
+	return &queryLogger{Interface: inner, slowThreshold: slowThreshold}
+}
+
+func (l *queryLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	return &queryLogger{Interface: l.Interface.LogMode(level), slowThreshold: l.slowThreshold}
+}
+
+func (l *queryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	method := repositoryMethod()
+	queryDuration.Observe(method, elapsed.Seconds())
+
+	if l.slowThreshold > 0 && elapsed > l.slowThreshold {
+		slowQueries.Inc(method)
+		sql, rows := fc()
+		normalized := normalizeQuery(sql)
+		logger.Warnf("Slow query: %s took %s (threshold %s), rows=%d fingerprint=%s query=%s",
+			method, elapsed.Round(time.Millisecond), l.slowThreshold, rows, fingerprint(normalized), truncate(normalized, maxLoggedQueryLength))
+	}
+
+	l.Interface.Trace(ctx, begin, fc, err)
+}
+
+// repositoryMethod names the repository method that issued the query, e.g.
+// "EventRepository.GetEvents", or "other" for queries from outside the repositories
+func repositoryMethod() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if i := strings.Index(frame.Function, "/internal/repository."); i >= 0 {
+			name := frame.Function[i+len("/internal/repository."):]
+			// Methods look like (*EventRepository).GetEvents; closures add .func1 and so on,
+			// and belong to their enclosing method or function
+			if strings.HasPrefix(name, "(*") {
+				parts := strings.SplitN(strings.NewReplacer("(*", "", ")", "").Replace(name), ".", 3)
+				return parts[0] + "." + parts[1]
+			}
+			return strings.SplitN(name, ".", 2)[0]
+		}
+		if !more {
+			return "other"
+		}
+	}
+}
+
+// normalizeQuery replaces literals with placeholders and collapses whitespace and value
+// lists, so queries that differ only in their parameters normalize the same
+func normalizeQuery(sql string) string {
+	sql = stringLiteralPattern.ReplaceAllString(sql, "?")
+	sql = numberPattern.ReplaceAllString(sql, "?")
+	sql = valueListPattern.ReplaceAllString(sql, "(?)")
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(sql, " "))
+}
+
+// fingerprint is a short stable hash of a normalized query
+func fingerprint(normalized string) string {
+	h := fnv.New64a()
+	h.Write([]byte(normalized))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "..."
+}
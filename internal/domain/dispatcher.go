@@ -0,0 +1,113 @@
+package domain
+
+import (
+	logger "api/pkg/logging"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Handler consumes one kind of event
+type Handler[T Event] func(ctx context.Context, event T) error
+
+// FailureFunc is told about an event a consumer failed to handle
+type FailureFunc func(ctx context.Context, consumer string, event Event, err error)
+
+type subscription struct {
+	consumer string
+	handle   func(ctx context.Context, event Event) error
+	decode   func(data []byte) (Event, error)
+}
+
+// Dispatcher delivers published events to the handlers subscribed to them, in process and in
+// the order they subscribed. Delivery is best effort: the change an event describes is already
+// committed, so a failing handler is logged and doesn't stop the others or fail the publisher.
+type Dispatcher struct {
+	mu            sync.RWMutex
+	subscriptions map[string][]subscription
+	onFailure     FailureFunc
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{subscriptions: make(map[string][]subscription)}
+}
+
+// Subscribe registers a consumer's handler for events of type T. The consumer name identifies
+// the handler in logs.
+func Subscribe[T Event](d *Dispatcher, consumer string, handle Handler[T]) {
+	var event T
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.subscriptions[event.Name()] = append(d.subscriptions[event.Name()], subscription{
+		consumer: consumer,
+		handle: func(ctx context.Context, event Event) error {
+			return handle(ctx, event.(T))
+		},
+		decode: func(data []byte) (Event, error) {
+			var event T
+			err := json.Unmarshal(data, &event)
+			return event, err
+		},
+	})
+}
+
+// OnFailure sets the function told about events a consumer failed to handle, e.g. to keep
+// them for a later redelivery
+func (d *Dispatcher) OnFailure(fn FailureFunc) {
+	d.mu.Lock()
+	d.onFailure = fn
+	d.mu.Unlock()
+}
+
+// Publish delivers events to their handlers before returning. A nil dispatcher drops them.
+func (d *Dispatcher) Publish(ctx context.Context, events ...Event) {
+	if d == nil {
+		return
+	}
+	for _, event := range events {
+		d.mu.RLock()
+		subscriptions := d.subscriptions[event.Name()]
+		onFailure := d.onFailure
+		d.mu.RUnlock()
+
+		for _, sub := range subscriptions {
+			if err := deliver(ctx, sub, event); err != nil {
+				logger.Warnf("Event %s not handled by %s: %v", event.Name(), sub.consumer, err)
+				if onFailure != nil {
+					onFailure(ctx, sub.consumer, event, err)
+				}
+			}
+		}
+	}
+}
+
+// Redeliver hands a JSON encoded event back to the one consumer that failed to handle it.
+// Failures are returned rather than reported to the failure function.
+func (d *Dispatcher) Redeliver(ctx context.Context, consumer, name string, data []byte) error {
+	d.mu.RLock()
+	subscriptions := d.subscriptions[name]
+	d.mu.RUnlock()
+
+	for _, sub := range subscriptions {
+		if sub.consumer != consumer {
+			continue
+		}
+		event, err := sub.decode(data)
+		if err != nil {
+			return fmt.Errorf("failed to decode %s event: %w", name, err)
+		}
+		return deliver(ctx, sub, event)
+	}
+	return fmt.Errorf("%s doesn't handle %s events", consumer, name)
+}
+
+// deliver runs one handler, turning a panic into an error so it can't take the publisher down
+func deliver(ctx context.Context, sub subscription, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return sub.handle(ctx, event)
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDispatcherDeliversToSubscribersInOrder(t *testing.T) {
+	d := NewDispatcher()
+	var got []string
+	Subscribe(d, "first", func(ctx context.Context, event SeatReleased) error {
+		got = append(got, "first:"+event.Reason)
+		return nil
+	})
+	Subscribe(d, "failing", func(ctx context.Context, event SeatReleased) error {
+		return errors.New("boom")
+	})
+	Subscribe(d, "panicking", func(ctx context.Context, event SeatReleased) error {
+		panic("boom")
+	})
+	Subscribe(d, "last", func(ctx context.Context, event SeatReleased) error {
+		got = append(got, "last:"+event.Reason)
+		return nil
+	})
+	Subscribe(d, "other", func(ctx context.Context, event BookingConfirmed) error {
+		got = append(got, "other")
+		return nil
+	})
+
+	d.Publish(context.Background(), SeatReleased{Reason: ReleaseIntentExpired}, SeatReleased{Reason: ReleaseIntentCancelled})
+
+	want := []string{"first:intent_expired", "last:intent_expired", "first:intent_cancelled", "last:intent_cancelled"}
+	if len(got) != len(want) {
+		t.Fatalf("delivered %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("delivered %v, want %v", got, want)
+		}
+	}
+}
+
+func TestNilDispatcherDropsEvents(t *testing.T) {
+	var d *Dispatcher
+	d.Publish(context.Background(), BookingConfirmed{BookingID: 1})
+}
+
+func TestDispatcherRedeliversFailedEvents(t *testing.T) {
+	d := NewDispatcher()
+	fail := true
+	var handled []uint
+	Subscribe(d, "flaky", func(ctx context.Context, event BookingConfirmed) error {
+		if fail {
+			return errors.New("boom")
+		}
+		handled = append(handled, event.BookingID)
+		return nil
+	})
+	Subscribe(d, "steady", func(ctx context.Context, event BookingConfirmed) error {
+		handled = append(handled, 0)
+		return nil
+	})
+
+	var failedConsumer string
+	var failed Event
+	d.OnFailure(func(ctx context.Context, consumer string, event Event, err error) {
+		failedConsumer, failed = consumer, event
+	})
+	d.Publish(context.Background(), BookingConfirmed{BookingID: 7})
+	if failedConsumer != "flaky" || failed.(BookingConfirmed).BookingID != 7 {
+		t.Fatalf("failure reported for %s with %v", failedConsumer, failed)
+	}
+
+	fail = false
+	if err := d.Redeliver(context.Background(), "flaky", EventBookingConfirmed, []byte(`{"BookingID":7}`)); err != nil {
+		t.Fatalf("redeliver: %v", err)
+	}
+	// Only the consumer that failed gets the event again
+	if len(handled) != 2 || handled[0] != 0 || handled[1] != 7 {
+		t.Fatalf("handled %v, want [0 7]", handled)
+	}
+	if err := d.Redeliver(context.Background(), "unknown", EventBookingConfirmed, []byte(`{}`)); err == nil {
+		t.Fatal("redelivered to a consumer that isn't subscribed")
+	}
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"api/pkg/money"
+	"time"
+)
+
+// Event is something that happened in the booking workflow. The booking service publishes
+// events once the change they describe is committed.
+type Event interface {
+	// Name identifies the kind of event subscribers register for
+	Name() string
+}
+
+// Event names
+const (
+	EventBookingConfirmed = "booking_confirmed"
+	EventBookingCancelled = "booking_cancelled"
+	EventIntentExpired    = "intent_expired"
+	EventSeatReleased     = "seat_released"
+	EventSeatLocked       = "seat_locked"
+	EventNoShowReleased   = "no_show_released"
+)
+
+// Reasons a seat was released
+const (
+	ReleaseIntentCancelled  = "intent_cancelled"
+	ReleaseIntentExpired    = "intent_expired"
+	ReleaseIntentAbandoned  = "intent_abandoned"
+	ReleaseIntentReleased   = "intent_released" // dropped when the user confirmed another intent
+	ReleaseBookingCancelled = "booking_cancelled"
+	ReleaseNoShow           = "no_show" // back on sale at the door after the no-show cutoff
+)
+
+// BookingConfirmed is published when a booking intent is confirmed
+type BookingConfirmed struct {
+	BookingID   uint
+	IntentID    uint
+	UserID      uint
+	EventID     uint
+	SeatID      uint
+	TotalAmount money.Money
+	Oversell    bool // an oversell place of a free event, outside its seats on sale
+	OccurredAt  time.Time
+}
+
+func (BookingConfirmed) Name() string { return EventBookingConfirmed }
+
+// BookingCancelled is published for every confirmed booking that is cancelled, companion
+// bookings cancelled along with an accessible seat included
+type BookingCancelled struct {
+	BookingID    uint
+	UserID       uint
+	EventID      uint
+	SeatID       uint
+	RefundAmount money.Money // refunded to the buyer; 0 for bookings that weren't paid for
+	Oversell     bool        // an oversell place of a free event, outside its seats on sale
+	OccurredAt   time.Time
+}
+
+func (BookingCancelled) Name() string { return EventBookingCancelled }
+
+// IntentExpired is published when a pending intent runs out, either at the end of its lock or
+// early because its checkout stopped sending heartbeats
+type IntentExpired struct {
+	IntentID   uint
+	UserID     uint
+	EventID    uint
+	SeatID     uint
+	Abandoned  bool
+	OccurredAt time.Time
+}
+
+func (IntentExpired) Name() string { return EventIntentExpired }
+
+// SeatReleased is published when a seat held by an intent or a booking can be booked again
+type SeatReleased struct {
+	EventID    uint
+	SeatID     uint
+	UserID     uint // user who held the seat
+	Reason     string
+	Oversell   bool // an oversell place of a free event, given to the waitlist rather than sold
+	OccurredAt time.Time
+}
+
+func (SeatReleased) Name() string { return EventSeatReleased }
+
+// SeatLocked is published when a booking intent takes a seat into checkout
+type SeatLocked struct {
+	IntentID   uint
+	EventID    uint
+	SeatID     uint
+	UserID     uint
+	OccurredAt time.Time
+}
+
+func (SeatLocked) Name() string { return EventSeatLocked }
+
+// NoShowReleased is published for every booking whose seat went back on sale at the door
+// because it wasn't checked in by the event's no-show cutoff
+type NoShowReleased struct {
+	BookingID    uint
+	UserID       uint
+	EventID      uint
+	SeatID       uint
+	RefundAmount money.Money // 0 unless the event refunds released bookings
+	OccurredAt   time.Time
+}
+
+func (NoShowReleased) Name() string { return EventNoShowReleased }
@@ -0,0 +1,190 @@
+package encryption
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const (
+	// Values are stored as "enc:v2:<key id>:<base64 nonce+ciphertext>"
+	encryptedPrefix = "enc:v2:"
+	// legacyPrefix marks values written before key IDs were recorded
+	legacyPrefix = "enc:v1:"
+
+	// DefaultKeyID names the key configured through a single ENCRYPTION_KEY
+	DefaultKeyID = "default"
+)
+
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// Config describes where encryption keys come from
+type Config struct {
+	// Keys is a comma-separated list of "id:base64key" pairs
+	Keys string
+	// KeysFile holds one "id:base64key" pair per line, e.g. a secret mounted from a KMS-backed store
+	KeysFile string
+	// ActiveKeyID selects the key new values are encrypted with; defaults to the first key
+	ActiveKeyID string
+	// Key is a single base64 key, used when no keyring is configured
+	Key string
+	// FallbackSecret derives a development key when nothing else is configured
+	FallbackSecret string
+}
+
+// Keyring encrypts with the active key and decrypts with any key it holds, so keys can be
+// rotated by adding a new active key and re-encrypting existing values
+type Keyring struct {
+	keys     map[string]*Cipher
+	order    []string
+	activeID string
+}
+
+func NewKeyring(cfg Config) (*Keyring, error) {
+	pairs, err := keyPairs(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &Keyring{keys: make(map[string]*Cipher, len(pairs))}
+	for _, pair := range pairs {
+		id, encoded, ok := strings.Cut(pair, ":")
+		id = strings.TrimSpace(id)
+		if !ok || !keyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("invalid encryption key entry %q, expected id:base64key", id)
+		}
+		if _, exists := k.keys[id]; exists {
+			return nil, fmt.Errorf("duplicate encryption key id %q", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q is not valid base64: %w", id, err)
+		}
+		c, err := NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q: %w", id, err)
+		}
+		k.keys[id] = c
+		k.order = append(k.order, id)
+	}
+
+	k.activeID = cfg.ActiveKeyID
+	if k.activeID == "" {
+		k.activeID = k.order[0]
+	}
+	if _, ok := k.keys[k.activeID]; !ok {
+		return nil, fmt.Errorf("active encryption key %q is not configured", k.activeID)
+	}
+	return k, nil
+}
+
+// keyPairs collects the configured "id:base64key" pairs in priority order
+func keyPairs(cfg Config) ([]string, error) {
+	var pairs []string
+	for _, pair := range strings.Split(cfg.Keys, ",") {
+		if pair = strings.TrimSpace(pair); pair != "" {
+			pairs = append(pairs, pair)
+		}
+	}
+
+	if cfg.KeysFile != "" {
+		f, err := os.Open(cfg.KeysFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption keys file: %w", err)
+		}
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line != "" && !strings.HasPrefix(line, "#") {
+				pairs = append(pairs, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read encryption keys file: %w", err)
+		}
+	}
+
+	if len(pairs) > 0 {
+		return pairs, nil
+	}
+	if cfg.Key != "" {
+		return []string{DefaultKeyID + ":" + cfg.Key}, nil
+	}
+	// Development only: derive a key so the API starts without configuration
+	derived := sha256.Sum256([]byte(cfg.FallbackSecret))
+	return []string{DefaultKeyID + ":" + base64.StdEncoding.EncodeToString(derived[:])}, nil
+}
+
+// ActiveKeyID returns the id of the key new values are encrypted with
+func (k *Keyring) ActiveKeyID() string {
+	return k.activeID
+}
+
+// Encrypt seals the value with the active key; empty values are left empty
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	sealed, err := k.keys[k.activeID].Seal([]byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return encryptedPrefix + k.activeID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value produced by Encrypt with whichever key sealed it. Values that were
+// never encrypted are returned unchanged, so existing plaintext stays readable until re-encrypted.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, encryptedPrefix):
+		id, encoded, ok := strings.Cut(strings.TrimPrefix(value, encryptedPrefix), ":")
+		if !ok {
+			return "", errors.New("malformed encrypted value")
+		}
+		c, ok := k.keys[id]
+		if !ok {
+			return "", fmt.Errorf("encryption key %q is not configured", id)
+		}
+		return open(c, encoded)
+
+	case strings.HasPrefix(value, legacyPrefix):
+		// Written without a key id: GCM authentication tells us which key matches
+		encoded := strings.TrimPrefix(value, legacyPrefix)
+		for _, id := range k.order {
+			if plaintext, err := open(k.keys[id], encoded); err == nil {
+				return plaintext, nil
+			}
+		}
+		return "", errors.New("no configured encryption key can decrypt value")
+
+	default:
+		return value, nil
+	}
+}
+
+// NeedsRotation reports whether a stored value is plaintext or sealed with a key other than the active one
+func (k *Keyring) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+	return !strings.HasPrefix(value, encryptedPrefix+k.activeID+":")
+}
+
+func open(c *Cipher, encoded string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted value: %w", err)
+	}
+	plaintext, err := c.Open(sealed)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,144 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+const (
+	oldKey = "MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MDE="
+	newKey = "YWJjZGVmZ2hpamtsbW5vcHFyc3R1dnd4eXphYmNkZWY="
+)
+
+func mustKeyring(t *testing.T, cfg Config) *Keyring {
+	t.Helper()
+	k, err := NewKeyring(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+// legacyValue seals the plaintext the way values were stored before key ids were recorded
+func legacyValue(t *testing.T, key, plaintext string) string {
+	t.Helper()
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewCipher(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := c.Seal([]byte(plaintext))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return legacyPrefix + base64.StdEncoding.EncodeToString(sealed)
+}
+
+func TestKeyringDecrypt(t *testing.T) {
+	retired := mustKeyring(t, Config{Keys: "old:" + oldKey})
+	rotated := mustKeyring(t, Config{Keys: "old:" + oldKey + ",new:" + newKey, ActiveKeyID: "new"})
+	unrelated := mustKeyring(t, Config{Keys: "other:" + newKey})
+
+	sealedOld, err := retired.Encrypt("555-0100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealedNew, err := rotated.Encrypt("555-0100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sealedNew, "enc:v2:new:") {
+		t.Fatalf("Encrypt = %q, want it sealed with the active key", sealedNew)
+	}
+
+	tests := []struct {
+		name    string
+		keyring *Keyring
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"active key round-trip", rotated, sealedNew, "555-0100", false},
+		{"retired key", rotated, sealedOld, "555-0100", false},
+		{"legacy value sealed with the first key", rotated, legacyValue(t, oldKey, "555-0100"), "555-0100", false},
+		{"legacy value sealed with a later key", rotated, legacyValue(t, newKey, "555-0100"), "555-0100", false},
+		{"legacy value no key opens", retired, legacyValue(t, newKey, "555-0100"), "", true},
+		{"unknown key id", unrelated, sealedOld, "", true},
+		{"missing key id", rotated, "enc:v2:bm9uY2U=", "", true},
+		{"tampered ciphertext", rotated, sealedNew[:len(sealedNew)-4] + "AAAA", "", true},
+		{"plaintext passes through", rotated, "555-0100", "555-0100", false},
+		{"empty value", rotated, "", "", false},
+	}
+	for _, tt := range tests {
+		got, err := tt.keyring.Decrypt(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Decrypt() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: Decrypt() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := unrelated.Decrypt(sealedOld); err == nil || !strings.Contains(err.Error(), `"old"`) {
+		t.Errorf("Decrypt with an unknown key id returned %v, want it to name the key", err)
+	}
+}
+
+func TestKeyringNeedsRotation(t *testing.T) {
+	retired := mustKeyring(t, Config{Keys: "old:" + oldKey})
+	rotated := mustKeyring(t, Config{Keys: "old:" + oldKey + ",new:" + newKey, ActiveKeyID: "new"})
+	sealedOld, _ := retired.Encrypt("555-0100")
+	sealedNew, _ := rotated.Encrypt("555-0100")
+
+	tests := []struct {
+		name  string
+		value string
+		want  bool
+	}{
+		{"sealed with the active key", sealedNew, false},
+		{"sealed with a retired key", sealedOld, true},
+		{"legacy value", legacyValue(t, newKey, "555-0100"), true},
+		{"plaintext", "555-0100", true},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		if got := rotated.NeedsRotation(tt.value); got != tt.want {
+			t.Errorf("%s: NeedsRotation() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewKeyring(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        Config
+		wantActive string
+		wantErr    bool
+	}{
+		{"first key is active by default", Config{Keys: "old:" + oldKey + ",new:" + newKey}, "old", false},
+		{"active key id", Config{Keys: "old:" + oldKey + ",new:" + newKey, ActiveKeyID: "new"}, "new", false},
+		{"single key", Config{Key: oldKey}, DefaultKeyID, false},
+		{"fallback secret", Config{FallbackSecret: "dev"}, DefaultKeyID, false},
+		{"duplicate id", Config{Keys: "old:" + oldKey + ",old:" + newKey}, "", true},
+		{"invalid id", Config{Keys: "old key:" + oldKey}, "", true},
+		{"missing id", Config{Keys: oldKey}, "", true},
+		{"invalid base64", Config{Keys: "old:not-base64!"}, "", true},
+		{"wrong key length", Config{Keys: "old:c2hvcnQ="}, "", true},
+		{"unknown active key id", Config{Keys: "old:" + oldKey, ActiveKeyID: "new"}, "", true},
+	}
+	for _, tt := range tests {
+		k, err := NewKeyring(tt.cfg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: NewKeyring() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && k.ActiveKeyID() != tt.wantActive {
+			t.Errorf("%s: ActiveKeyID() = %q, want %q", tt.name, k.ActiveKeyID(), tt.wantActive)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Reencrypt rewrites the model's encrypted columns that are still plaintext or sealed with
+// an old key, walking the table in primary key order. It returns the number of values that
+// need rewriting; with dryRun nothing is written. Each value is only replaced if it hasn't
+// changed since it was read, so it is safe to run against a live database.
+func Reencrypt(ctx context.Context, db *gorm.DB, k *Keyring, model interface{}, batchSize int, dryRun bool) (int, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return 0, err
+	}
+	table := stmt.Schema.Table
+	if stmt.Schema.PrioritizedPrimaryField == nil {
+		return 0, fmt.Errorf("%s has no primary key", table)
+	}
+	pk := stmt.Schema.PrioritizedPrimaryField.DBName
+
+	var columns []string
+	for _, field := range stmt.Schema.Fields {
+		if field.TagSettings["SERIALIZER"] == SerializerName && field.DBName != "" {
+			columns = append(columns, field.DBName)
+		}
+	}
+	if len(columns) == 0 {
+		return 0, nil
+	}
+
+	rewritten := 0
+	var lastID interface{} = 0
+	for {
+		// Read raw column values; the table query bypasses the serializer and soft-delete scope
+		var rows []map[string]interface{}
+		if err := db.WithContext(ctx).Table(table).
+			Select(append([]string{pk}, columns...)).
+			Where(pk+" > ?", lastID).
+			Order(pk).
+			Limit(batchSize).
+			Find(&rows).Error; err != nil {
+			return rewritten, fmt.Errorf("failed to read %s: %w", table, err)
+		}
+		if len(rows) == 0 {
+			return rewritten, nil
+		}
+
+		for _, row := range rows {
+			lastID = row[pk]
+			for _, column := range columns {
+				stored, _ := row[column].(string)
+				if !k.NeedsRotation(stored) {
+					continue
+				}
+				rewritten++
+				if dryRun {
+					continue
+				}
+
+				plaintext, err := k.Decrypt(stored)
+				if err != nil {
+					return rewritten, fmt.Errorf("%s %v %s: %w", table, lastID, column, err)
+				}
+				sealed, err := k.Encrypt(plaintext)
+				if err != nil {
+					return rewritten, err
+				}
+				if err := db.WithContext(ctx).Table(table).
+					Where(pk+" = ? AND "+column+" = ?", lastID, stored).
+					Update(column, sealed).Error; err != nil {
+					return rewritten, fmt.Errorf("failed to update %s %v %s: %w", table, lastID, column, err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package encryption
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName is used in struct tags: `gorm:"type:text;serializer:encrypted"`
+const SerializerName = "encrypted"
+
+// Serializer transparently encrypts string fields on write and decrypts them on read
+type Serializer struct {
+	keyring *Keyring
+}
+
+// Ensure Serializer implements schema.SerializerInterface
+var _ schema.SerializerInterface = (*Serializer)(nil)
+
+// Register makes the encrypted serializer available to GORM models. It must be called
+// before any model using it is read or written.
+func Register(k *Keyring) {
+	schema.RegisterSerializer(SerializerName, &Serializer{keyring: k})
+}
+
+func (s *Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var value string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		return fmt.Errorf("unsupported encrypted column value %T", dbValue)
+	}
+
+	plaintext, err := s.keyring.Decrypt(value)
+	if err != nil {
+		return err
+	}
+	return field.Set(ctx, dst, plaintext)
+}
+
+func (s *Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	value, ok := fieldValue.(string)
+	if !ok {
+		return nil, errors.New("encrypted fields must be strings")
+	}
+	return s.keyring.Encrypt(value)
+}
@@ -0,0 +1,127 @@
+// Package enrichment adds data from outside sources, such as weather forecasts, to event
+// details. Enrichers are registered once and enabled per venue.
+package enrichment
+
+import (
+	"api/internal/entities"
+	logger "api/pkg/logging"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Enrichers
+const (
+	Weather = "weather"
+)
+
+// Names are the enrichers venues can enable
+var Names = []string{Weather}
+
+// Enricher adds data to the details of events held at venues that enable it
+type Enricher interface {
+	// Name is the key of the enricher's data in event details and in venues' enrichments
+	Name() string
+	// Enrich returns the data to add for an event with its venue loaded, or nil when there is
+	// nothing to add, e.g. because the event is too far away for a forecast
+	Enrich(ctx context.Context, event *entities.Event) (interface{}, error)
+}
+
+// Config configures the enrichers
+type Config struct {
+	// WeatherURL is an Open-Meteo compatible forecast API; empty disables weather forecasts
+	WeatherURL string
+	Timeout    time.Duration
+	// CacheTTL is how long a forecast is reused for events at the same place and day
+	CacheTTL time.Duration
+}
+
+// Registry runs the enrichers a venue enables
+type Registry struct {
+	enrichers map[string]Enricher
+	timeout   time.Duration
+}
+
+// New returns a registry with the enrichers the config enables
+func New(cfg Config) *Registry {
+	registry := NewRegistry(cfg.Timeout)
+	if cfg.WeatherURL != "" {
+		registry.Register(NewWeatherEnricher(cfg.WeatherURL, cfg.Timeout, cfg.CacheTTL))
+	}
+	return registry
+}
+
+// NewRegistry returns an empty registry giving each enricher timeout to answer
+func NewRegistry(timeout time.Duration) *Registry {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &Registry{enrichers: make(map[string]Enricher), timeout: timeout}
+}
+
+// Register adds an enricher, replacing one of the same name
+func (r *Registry) Register(enricher Enricher) {
+	r.enrichers[enricher.Name()] = enricher
+}
+
+// Enrich returns the data of every registered enricher the event's venue enables, by name.
+// Enrichment is best effort: enrichers that fail or time out are left out.
+func (r *Registry) Enrich(ctx context.Context, event *entities.Event) map[string]interface{} {
+	var data map[string]interface{}
+	for _, name := range ParseNames(event.Venue.Enrichments) {
+		enricher, ok := r.enrichers[name]
+		if !ok {
+			continue
+		}
+		enrichCtx, cancel := context.WithTimeout(ctx, r.timeout)
+		value, err := enricher.Enrich(enrichCtx, event)
+		cancel()
+		if err != nil {
+			logger.Warnf("Failed to add %s to event %d: %v", name, event.ID, err)
+			continue
+		}
+		if value == nil {
+			continue
+		}
+		if data == nil {
+			data = make(map[string]interface{})
+		}
+		data[name] = value
+	}
+	return data
+}
+
+// ParseNames splits a venue's comma-separated enrichments
+func ParseNames(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// NormalizeNames returns enricher names as a venue stores them, rejecting unknown ones
+func NormalizeNames(names []string) (string, error) {
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		known := false
+		for _, candidate := range Names {
+			known = known || candidate == name
+		}
+		if !known {
+			return "", fmt.Errorf("unknown enrichment %q, expected one of %s", name, strings.Join(Names, ", "))
+		}
+		seen[name] = true
+	}
+	normalized := make([]string, 0, len(seen))
+	for name := range seen {
+		normalized = append(normalized, name)
+	}
+	sort.Strings(normalized)
+	return strings.Join(normalized, ","), nil
+}
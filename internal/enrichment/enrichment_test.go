@@ -0,0 +1,99 @@
+package enrichment
+
+import (
+	"api/internal/entities"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWeatherEnricher(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		query := r.URL.Query()
+		if query.Get("latitude") != "51.5560" || query.Get("start_date") != "2026-10-20" || query.Get("end_date") != "2026-10-20" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"daily":{"time":["2026-10-20"],"weather_code":[63],"temperature_2m_max":[14.2],"temperature_2m_min":[8.5],"precipitation_probability_max":[80]}}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	weather := NewWeatherEnricher(server.URL, time.Second, time.Hour)
+	weather.now = func() time.Time { return now }
+
+	latitude, longitude := 51.556, -0.2796
+	event := &entities.Event{
+		ID:        1,
+		StartTime: time.Date(2026, 10, 20, 19, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2026, 10, 20, 23, 0, 0, 0, time.UTC),
+		Venue:     entities.Venue{Latitude: &latitude, Longitude: &longitude, Enrichments: "weather"},
+	}
+
+	registry := NewRegistry(time.Second)
+	registry.Register(weather)
+	data := registry.Enrich(context.Background(), event)
+	forecast, ok := data[Weather].(*Forecast)
+	if !ok {
+		t.Fatalf("expected a forecast, got %v", data)
+	}
+	if forecast.Summary != "Rain" || forecast.TemperatureMaxC != 14.2 || forecast.PrecipitationProbability == nil || *forecast.PrecipitationProbability != 80 {
+		t.Errorf("unexpected forecast %+v", forecast)
+	}
+
+	// Forecasts are cached per place and day
+	registry.Enrich(context.Background(), event)
+	if requests.Load() != 1 {
+		t.Errorf("expected 1 forecast request, got %d", requests.Load())
+	}
+
+	// Venues that don't enable it, events beyond the forecast horizon and venues without a
+	// location get no forecast
+	cases := map[string]*entities.Event{
+		"disabled":  {StartTime: event.StartTime, EndTime: event.EndTime, Venue: entities.Venue{Latitude: &latitude, Longitude: &longitude}},
+		"too far":   {StartTime: now.AddDate(0, 1, 0), EndTime: now.AddDate(0, 1, 0), Venue: event.Venue},
+		"ended":     {StartTime: now.AddDate(0, 0, -2), EndTime: now.AddDate(0, 0, -2), Venue: event.Venue},
+		"no coords": {StartTime: event.StartTime, EndTime: event.EndTime, Venue: entities.Venue{Enrichments: "weather"}},
+	}
+	for name, event := range cases {
+		if data := registry.Enrich(context.Background(), event); data != nil {
+			t.Errorf("%s: expected no enrichments, got %v", name, data)
+		}
+	}
+}
+
+type failingEnricher struct{}
+
+func (failingEnricher) Name() string { return "failing" }
+
+func (failingEnricher) Enrich(ctx context.Context, event *entities.Event) (interface{}, error) {
+	return nil, errors.New("provider down")
+}
+
+// TestRegistryLeavesOutFailures keeps event details working when an enricher fails
+func TestRegistryLeavesOutFailures(t *testing.T) {
+	registry := NewRegistry(time.Second)
+	registry.Register(failingEnricher{})
+	event := &entities.Event{Venue: entities.Venue{Enrichments: "failing"}}
+	if data := registry.Enrich(context.Background(), event); data != nil {
+		t.Errorf("expected no enrichments, got %v", data)
+	}
+}
+
+func TestNormalizeNames(t *testing.T) {
+	names, err := NormalizeNames([]string{" Weather", "weather"})
+	if err != nil || names != "weather" {
+		t.Errorf("NormalizeNames = %q, %v", names, err)
+	}
+	if names, err := NormalizeNames(nil); err != nil || names != "" {
+		t.Errorf("NormalizeNames(nil) = %q, %v", names, err)
+	}
+	if _, err := NormalizeNames([]string{"traffic"}); err == nil {
+		t.Error("expected unknown enrichments to be rejected")
+	}
+}
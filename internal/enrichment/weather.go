@@ -0,0 +1,184 @@
+package enrichment
+
+import (
+	"api/internal/entities"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// forecastHorizon is how far ahead daily forecasts are available
+const forecastHorizon = 16 * 24 * time.Hour
+
+// maxCachedForecasts bounds the weather enricher's cache; it is cleared when full
+const maxCachedForecasts = 10000
+
+// Forecast is the weather expected on the day of an event, at its venue
+type Forecast struct {
+	Date            string  `json:"date"`
+	Summary         string  `json:"summary"`
+	WeatherCode     int     `json:"weather_code"` // WMO weather interpretation code
+	TemperatureMaxC float64 `json:"temperature_max_c"`
+	TemperatureMinC float64 `json:"temperature_min_c"`
+	// Chance of rain or snow in percent, when the provider has it
+	PrecipitationProbability *int `json:"precipitation_probability,omitempty"`
+}
+
+// WeatherEnricher adds the daily forecast of an Open-Meteo compatible API to events at venues
+// with coordinates, once they are close enough to be forecast. Forecasts are cached per place
+// and day, so a popular event costs one lookup per cache TTL.
+type WeatherEnricher struct {
+	url      string
+	cacheTTL time.Duration
+	client   *http.Client
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedForecast
+}
+
+type cachedForecast struct {
+	forecast  *Forecast
+	expiresAt time.Time
+}
+
+// Ensure WeatherEnricher implements Enricher
+var _ Enricher = (*WeatherEnricher)(nil)
+
+func NewWeatherEnricher(forecastURL string, timeout, cacheTTL time.Duration) *WeatherEnricher {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &WeatherEnricher{
+		url:      forecastURL,
+		cacheTTL: cacheTTL,
+		client:   &http.Client{Timeout: timeout},
+		now:      time.Now,
+		cache:    make(map[string]cachedForecast),
+	}
+}
+
+func (w *WeatherEnricher) Name() string {
+	return Weather
+}
+
+func (w *WeatherEnricher) Enrich(ctx context.Context, event *entities.Event) (interface{}, error) {
+	venue := event.Venue
+	if venue.Latitude == nil || venue.Longitude == nil {
+		return nil, nil
+	}
+	now := w.now()
+	if event.EndTime.Before(now) || event.StartTime.After(now.Add(forecastHorizon)) {
+		return nil, nil
+	}
+
+	date := event.StartTime.UTC().Format(time.DateOnly)
+	key := fmt.Sprintf("%.3f,%.3f,%s", *venue.Latitude, *venue.Longitude, date)
+	w.mu.Lock()
+	cached, ok := w.cache[key]
+	w.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.forecast, nil
+	}
+
+	forecast, err := w.fetch(ctx, *venue.Latitude, *venue.Longitude, date)
+	if err != nil {
+		return nil, err
+	}
+	if w.cacheTTL > 0 {
+		w.mu.Lock()
+		if len(w.cache) >= maxCachedForecasts {
+			w.cache = make(map[string]cachedForecast)
+		}
+		w.cache[key] = cachedForecast{forecast: forecast, expiresAt: now.Add(w.cacheTTL)}
+		w.mu.Unlock()
+	}
+	if forecast == nil {
+		return nil, nil // an untyped nil, so the registry leaves it out
+	}
+	return forecast, nil
+}
+
+// fetch asks for the daily forecast of one day, returning nil if the provider has none
+func (w *WeatherEnricher) fetch(ctx context.Context, latitude, longitude float64, date string) (*Forecast, error) {
+	query := url.Values{}
+	query.Set("latitude", strconv.FormatFloat(latitude, 'f', 4, 64))
+	query.Set("longitude", strconv.FormatFloat(longitude, 'f', 4, 64))
+	query.Set("daily", "weather_code,temperature_2m_max,temperature_2m_min,precipitation_probability_max")
+	query.Set("timezone", "UTC")
+	query.Set("start_date", date)
+	query.Set("end_date", date)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.url+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("weather forecast failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("weather forecast failed: %s", resp.Status)
+	}
+
+	var body struct {
+		Daily struct {
+			Time                     []string   `json:"time"`
+			WeatherCode              []int      `json:"weather_code"`
+			TemperatureMax           []float64  `json:"temperature_2m_max"`
+			TemperatureMin           []float64  `json:"temperature_2m_min"`
+			PrecipitationProbability []*float64 `json:"precipitation_probability_max"`
+		} `json:"daily"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("weather forecast failed: %w", err)
+	}
+	daily := body.Daily
+	if len(daily.Time) == 0 || len(daily.WeatherCode) == 0 || len(daily.TemperatureMax) == 0 || len(daily.TemperatureMin) == 0 {
+		return nil, nil
+	}
+
+	forecast := &Forecast{
+		Date:            daily.Time[0],
+		Summary:         weatherSummary(daily.WeatherCode[0]),
+		WeatherCode:     daily.WeatherCode[0],
+		TemperatureMaxC: daily.TemperatureMax[0],
+		TemperatureMinC: daily.TemperatureMin[0],
+	}
+	if len(daily.PrecipitationProbability) > 0 && daily.PrecipitationProbability[0] != nil {
+		probability := int(*daily.PrecipitationProbability[0])
+		forecast.PrecipitationProbability = &probability
+	}
+	return forecast, nil
+}
+
+// weatherSummary describes a WMO weather interpretation code
+func weatherSummary(code int) string {
+	switch {
+	case code == 0:
+		return "Clear sky"
+	case code <= 2:
+		return "Partly cloudy"
+	case code == 3:
+		return "Overcast"
+	case code == 45 || code == 48:
+		return "Fog"
+	case code >= 51 && code <= 57:
+		return "Drizzle"
+	case code >= 61 && code <= 67, code >= 80 && code <= 82:
+		return "Rain"
+	case code >= 71 && code <= 77, code == 85 || code == 86:
+		return "Snow"
+	case code >= 95:
+		return "Thunderstorm"
+	default:
+		return "Unknown"
+	}
+}
@@ -1,13 +1,16 @@
 package entities
 
-import "time"
+import (
+	"api/pkg/money"
+	"time"
+)
 
 type BookingAnalytics struct {
 	TotalBookings       int64                 `json:"total_bookings"`
 	ConfirmedBookings   int64                 `json:"confirmed_bookings"`
 	CancelledBookings   int64                 `json:"cancelled_bookings"`
 	CancellationRate    float64               `json:"cancellation_rate"`
-	TotalRevenue        float64               `json:"total_revenue"`
+	TotalRevenue        money.Money           `json:"total_revenue"`
 	MostPopularEvents   []PopularEvent        `json:"most_popular_events"`
 	MostBookedEvents    []BookedEvent         `json:"most_booked_events"`
 	CapacityUtilization []CapacityUtilization `json:"capacity_utilization"`
@@ -15,21 +18,21 @@ type BookingAnalytics struct {
 }
 
 type PopularEvent struct {
-	EventID      uint    `json:"event_id"`
-	EventName    string  `json:"event_name"`
-	VenueName    string  `json:"venue_name"`
-	BookingCount int64   `json:"booking_count"`
-	Revenue      float64 `json:"revenue"`
+	EventID      uint        `json:"event_id"`
+	EventName    string      `json:"event_name"`
+	VenueName    string      `json:"venue_name"`
+	BookingCount int64       `json:"booking_count"`
+	Revenue      money.Money `json:"revenue"`
 }
 
 type BookedEvent struct {
-	EventID         uint    `json:"event_id"`
-	EventName       string  `json:"event_name"`
-	VenueName       string  `json:"venue_name"`
-	TotalSeats      int64   `json:"total_seats"`
-	BookedSeats     int64   `json:"booked_seats"`
-	UtilizationRate float64 `json:"utilization_rate"`
-	Revenue         float64 `json:"revenue"`
+	EventID         uint        `json:"event_id"`
+	EventName       string      `json:"event_name"`
+	VenueName       string      `json:"venue_name"`
+	TotalSeats      int64       `json:"total_seats"`
+	BookedSeats     int64       `json:"booked_seats"`
+	UtilizationRate float64     `json:"utilization_rate"`
+	Revenue         money.Money `json:"revenue"`
 }
 
 type CapacityUtilization struct {
@@ -44,31 +47,168 @@ type CapacityUtilization struct {
 }
 
 type DailyBookingStat struct {
-	Date             time.Time `json:"date"`
-	TotalBookings    int64     `json:"total_bookings"`
-	ConfirmedCount   int64     `json:"confirmed_count"`
-	CancelledCount   int64     `json:"cancelled_count"`
-	Revenue          float64   `json:"revenue"`
-	CancellationRate float64   `json:"cancellation_rate"`
+	Date             time.Time   `json:"date"`
+	TotalBookings    int64       `json:"total_bookings"`
+	ConfirmedCount   int64       `json:"confirmed_count"`
+	CancelledCount   int64       `json:"cancelled_count"`
+	Revenue          money.Money `json:"revenue"`
+	CancellationRate float64     `json:"cancellation_rate"`
 }
 
 // Database query result structures
 type EventBookingStats struct {
-	EventID      uint      `json:"event_id"`
-	EventName    string    `json:"event_name"`
-	VenueName    string    `json:"venue_name"`
-	BookingCount int64     `json:"booking_count"`
-	Revenue      float64   `json:"revenue"`
-	TotalSeats   int64     `json:"total_seats"`
-	BookedSeats  int64     `json:"booked_seats"`
-	StartTime    time.Time `json:"start_time"`
-	Status       string    `json:"status"`
+	EventID      uint        `json:"event_id"`
+	EventName    string      `json:"event_name"`
+	VenueName    string      `json:"venue_name"`
+	BookingCount int64       `json:"booking_count"`
+	Revenue      money.Money `json:"revenue"`
+	TotalSeats   int64       `json:"total_seats"`
+	BookedSeats  int64       `json:"booked_seats"`
+	StartTime    time.Time   `json:"start_time"`
+	Status       string      `json:"status"`
 }
 
 type DailyStats struct {
-	Date           time.Time `json:"date"`
-	TotalBookings  int64     `json:"total_bookings"`
-	ConfirmedCount int64     `json:"confirmed_count"`
-	CancelledCount int64     `json:"cancelled_count"`
-	Revenue        float64   `json:"revenue"`
+	Date           time.Time   `json:"date"`
+	TotalBookings  int64       `json:"total_bookings"`
+	ConfirmedCount int64       `json:"confirmed_count"`
+	CancelledCount int64       `json:"cancelled_count"`
+	Revenue        money.Money `json:"revenue"`
+}
+
+// LiveEventStats are the real-time on-sale counters of an event, for war-room monitoring
+type LiveEventStats struct {
+	EventID     uint      `json:"event_id"`
+	EventName   string    `json:"event_name"`
+	GeneratedAt time.Time `json:"generated_at"`
+	// Current state
+	LocksHeld      int64 `json:"locks_held"`
+	IntentsPending int64 `json:"intents_pending"`
+	QueueLength    int   `json:"queue_length"` // users on the waitlist
+	// Rates over the last WindowMinutes minutes, the current one included
+	WindowMinutes          int               `json:"window_minutes"`
+	IntentsPerMinute       float64           `json:"intents_per_minute"`
+	ConfirmationsPerMinute float64           `json:"confirmations_per_minute"`
+	RejectionRate          float64           `json:"rejection_rate"` // % of intent and confirmation attempts refused, e.g. seat taken
+	ErrorRate              float64           `json:"error_rate"`     // % of intent and confirmation attempts that failed with an internal error
+	Minutes                []LiveMinuteStats `json:"minutes"`        // oldest first
+	// Degraded is set while Redis is unavailable: its counters read as zero until it recovers
+	Degraded bool `json:"degraded"`
+}
+
+// LiveMinuteStats counts the booking flow outcomes of an event during one minute
+type LiveMinuteStats struct {
+	Minute                time.Time `json:"minute"`
+	IntentsCreated        int64     `json:"intents_created"`
+	IntentsRejected       int64     `json:"intents_rejected"`
+	Confirmations         int64     `json:"confirmations"`
+	ConfirmationsRejected int64     `json:"confirmations_rejected"`
+	Errors                int64     `json:"errors"`
+	SeatsReleased         int64     `json:"seats_released"`
+	LockConflicts         int64     `json:"lock_conflicts"` // intents refused because the seat was locked
+}
+
+// WaitlistConversion is how an event's waitlist turned into sales: how many users were offered
+// a seat after waiting, how long they waited, and how many of them, and how fast, bought one
+type WaitlistConversion struct {
+	EventID   uint      `json:"event_id"`
+	EventName string    `json:"event_name"`
+	VenueName string    `json:"venue_name"`
+	Capacity  int64     `json:"capacity"`
+	StartTime time.Time `json:"start_time"`
+	// Waitlist entries, counting a user who joined again once per join
+	Waitlisted   int64 `json:"waitlisted"`
+	StillWaiting int64 `json:"still_waiting"`
+	Promoted     int64 `json:"promoted"`  // offered a released seat
+	Purchased    int64 `json:"purchased"` // booked after joining, offered a seat or not
+	// Promoted users who booked after their offer
+	PurchasedAfterPromotion int64   `json:"purchased_after_promotion"`
+	ConversionRate          float64 `json:"conversion_rate"`              // % of promoted users who booked
+	PurchaseRate            float64 `json:"purchase_rate"`                // % of waitlisted users who booked
+	UnmetDemand             int64   `json:"unmet_demand"`                 // waitlisted users who never booked
+	AvgWaitSeconds          float64 `json:"avg_wait_seconds"`             // from joining to the offer
+	AvgTimeToPurchase       float64 `json:"avg_time_to_purchase_seconds"` // from the offer to the booking
+}
+
+// Acquisition report groupings
+const (
+	AcquisitionByChannel  = "channel"  // utm_source
+	AcquisitionByCampaign = "campaign" // utm_source, utm_medium and utm_campaign
+	AcquisitionByReferral = "referral" // referral code
+)
+
+// AcquisitionFilter selects the bookings an acquisition report covers
+type AcquisitionFilter struct {
+	EventID uint       // 0 for every event
+	From    *time.Time // booked at or after
+	To      *time.Time // booked before
+	GroupBy string     // AcquisitionByChannel, AcquisitionByCampaign or AcquisitionByReferral
+	Limit   int
+}
+
+// AcquisitionChannel is the bookings and revenue brought in through one acquisition channel
+type AcquisitionChannel struct {
+	// utm_source, "referral" for bookings with only a referral code, or "direct" without either
+	Channel      string      `json:"channel"`
+	Medium       string      `json:"medium,omitempty"`        // grouped by campaign
+	Campaign     string      `json:"campaign,omitempty"`      // grouped by campaign
+	ReferralCode string      `json:"referral_code,omitempty"` // grouped by referral
+	Bookings     int64       `json:"bookings"`                // confirmed
+	Cancelled    int64       `json:"cancelled"`               // cancelled or refunded
+	Revenue      money.Money `json:"revenue"`
+	BookingShare float64     `json:"booking_share"` // % of all confirmed bookings
+	RevenueShare float64     `json:"revenue_share"` // % of all revenue
+}
+
+// AcquisitionReport breaks bookings and revenue down by how buyers found the events
+type AcquisitionReport struct {
+	GroupBy  string               `json:"group_by"`
+	Bookings int64                `json:"bookings"` // confirmed, across every channel
+	Revenue  money.Money          `json:"revenue"`
+	Channels []AcquisitionChannel `json:"channels"` // highest revenue first
+}
+
+// VenueMonth is how a venue's events starting in one month sold
+type VenueMonth struct {
+	Month         string      `json:"month"` // YYYY-MM
+	Events        int64       `json:"events"`
+	TotalSeats    int64       `json:"total_seats"`
+	BookedSeats   int64       `json:"booked_seats"`   // confirmed, comps included
+	BookedPercent float64     `json:"booked_percent"` // % of the seats of the month's events
+	Revenue       money.Money `json:"revenue"`
+}
+
+// VenueUtilization is a venue's performance over time, month by month
+type VenueUtilization struct {
+	VenueID       uint         `json:"venue_id"`
+	VenueName     string       `json:"venue_name"`
+	From          string       `json:"from"` // first month, YYYY-MM
+	To            string       `json:"to"`   // last month
+	Events        int64        `json:"events"`
+	BookedPercent float64      `json:"booked_percent"` // across every month
+	Revenue       money.Money  `json:"revenue"`
+	Months        []VenueMonth `json:"months"` // months without events are left out
+}
+
+// SeatHeat is how one seat position of a venue sold across its past events
+type SeatHeat struct {
+	Row         int         `json:"row"`
+	Column      int         `json:"column"`
+	SeatType    string      `json:"seat_type"` // the most common one across the events
+	Offered     int64       `json:"offered"`   // events the seat was on sale for
+	Sold        int64       `json:"sold"`
+	SellThrough float64     `json:"sell_through"` // % of the events it was offered for that it sold
+	AvgPrice    money.Money `json:"avg_price"`    // average seat price it sold at, before discounts
+}
+
+// SeatHeatmap is a venue's historical sales by seat position, to guide pricing tier design
+type SeatHeatmap struct {
+	VenueID   uint       `json:"venue_id"`
+	VenueName string     `json:"venue_name"`
+	Rows      int        `json:"rows"`
+	Columns   int        `json:"columns"`
+	From      string     `json:"from"` // first month, YYYY-MM
+	To        string     `json:"to"`   // last month
+	Events    int64      `json:"events"`
+	Seats     []SeatHeat `json:"seats"` // by row, then column
 }
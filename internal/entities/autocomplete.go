@@ -0,0 +1,18 @@
+package entities
+
+// VenueSuggestion is a venue matching what a user is typing in a search box
+type VenueSuggestion struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	City    string `json:"city"`
+	State   string `json:"state"`
+	Country string `json:"country"`
+}
+
+// CitySuggestion is a city with venues matching what a user is typing in a search box
+type CitySuggestion struct {
+	City    string `json:"city"`
+	State   string `json:"state"`
+	Country string `json:"country"`
+	Venues  int64  `json:"venues"`
+}
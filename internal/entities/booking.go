@@ -0,0 +1,107 @@
+package entities
+
+import (
+	"api/pkg/money"
+	"time"
+)
+
+// BookingIntentOptions carries the optional inputs to creating a booking intent
+type BookingIntentOptions struct {
+	// PresaleCode allows booking before the general on-sale
+	PresaleCode string
+	// AcceptTerms and TermsVersion record the user's acceptance of the event's terms and conditions
+	AcceptTerms  bool
+	TermsVersion string
+	// Country is the client's ISO country code from GeoIP, empty when unknown
+	Country string
+	// Attribution is how the user found the event, kept on the intent and its booking
+	Attribution Attribution
+	// Attendee holds the details the event requires of free events' intents, which are
+	// confirmed as soon as they are created
+	Attendee AttendeeDetails
+}
+
+// Attribution records the acquisition channel of a booking: the UTM parameters of the link
+// the buyer arrived through, or a referral code they were given
+type Attribution struct {
+	UTMSource    string `gorm:"size:100"`
+	UTMMedium    string `gorm:"size:100"`
+	UTMCampaign  string `gorm:"size:100"`
+	ReferralCode string `gorm:"size:50"`
+}
+
+// ConfirmOptions carries the optional inputs to confirming a booking
+type ConfirmOptions struct {
+	// ReleaseOtherIntents cancels the user's other pending intents for the same event in the
+	// confirming transaction, freeing their seats
+	ReleaseOtherIntents bool
+	// FreeTicketLimit caps the user's tickets for a free event without a limit of its own; 0 is
+	// unlimited
+	FreeTicketLimit int
+}
+
+// Booking history periods, by whether the event has ended
+const (
+	BookingsUpcoming = "upcoming" // events that haven't ended, including ones in progress
+	BookingsPast     = "past"
+)
+
+// Booking history orders
+const (
+	BookingSortNewest         = "newest" // most recently booked first
+	BookingSortOldest         = "oldest"
+	BookingSortEventStart     = "event_start"      // soonest event first
+	BookingSortEventStartDesc = "event_start_desc" // latest event first
+)
+
+// BookingFilter narrows a user's booking history
+type BookingFilter struct {
+	Status  string // empty for every status
+	EventID uint   // 0 for every event
+	Period  string // BookingsUpcoming, BookingsPast or empty for both
+	Sort    string // defaults to BookingSortNewest
+}
+
+// AttendeeDetails are collected at confirmation for events that require them
+type AttendeeDetails struct {
+	FullName    string
+	DateOfBirth *time.Time
+	IDNumber    string
+}
+
+// MaskIDNumber renders an ID number for printing, keeping only the last 4 characters, e.g. "•••• 6789"
+func MaskIDNumber(idNumber string) string {
+	if idNumber == "" {
+		return ""
+	}
+	if len(idNumber) <= 4 {
+		return "••••"
+	}
+	return "•••• " + idNumber[len(idNumber)-4:]
+}
+
+// DoorSale is a seat sold by staff at the box office
+type DoorSale struct {
+	SeatID uint // 0 sells the best available seat
+	// UserID is the customer's account, if they have one; otherwise the booking belongs to SoldBy
+	UserID        *uint
+	SoldBy        uint
+	Amount        *money.Money // overrides the seat price
+	PaymentMethod string       // cash or card
+	Currency      string
+	Reference     string // receipt or terminal reference, generated when empty
+	Attendee      AttendeeDetails
+	CheckIn       bool // admit the buyer straight away
+	// ExcludeSeatIDs are held by online checkouts, so they aren't picked as the best seat
+	ExcludeSeatIDs []uint
+}
+
+// CompIssue is a batch of complimentary tickets for specific seats of an event
+type CompIssue struct {
+	SeatIDs []uint
+	// UserID is the guest's account, if they have one; otherwise the tickets belong to IssuedBy
+	UserID       *uint
+	IssuedBy     uint
+	Reason       string
+	AttendeeName string // printed on the tickets
+}
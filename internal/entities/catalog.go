@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// CatalogSyncError is a feed entry rejected by a catalog sync
+type CatalogSyncError struct {
+	Kind       string `json:"kind"` // venue or event
+	ExternalID string `json:"external_id"`
+	Field      string `json:"field,omitempty"`
+	Message    string `json:"message"`
+}
+
+// CatalogSyncStatus describes the partner catalog sync and its latest runs
+type CatalogSyncStatus struct {
+	Enabled     bool
+	Source      string
+	TenantID    uint
+	Interval    time.Duration
+	LastSuccess *CatalogSyncRun
+	Runs        []CatalogSyncRun // newest first
+}
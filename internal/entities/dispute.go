@@ -0,0 +1,16 @@
+package entities
+
+import (
+	"api/pkg/money"
+	"time"
+)
+
+// DisputeUpdate is a dispute state reported by the payment provider
+type DisputeUpdate struct {
+	ProviderDisputeID string
+	PaymentID         string
+	Status            string
+	Reason            string
+	Amount            money.Money
+	OccurredAt        time.Time
+}
@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"api/pkg/money"
+	"time"
+)
+
+// SalesCloseAt returns when ticket sales for the event close: its start time, brought
+// forward by its sales cutoff
+func (e *Event) SalesCloseAt() time.Time {
+	if e.SalesCloseMinutesBeforeStart == nil {
+		return e.StartTime
+	}
+	return e.StartTime.Add(-time.Duration(*e.SalesCloseMinutesBeforeStart) * time.Minute)
+}
+
+// IsFree reports whether the event's tickets are free
+func (e *Event) IsFree() bool {
+	return e.Price == 0
+}
+
+// OversellPlaces returns how many oversell places the event has on top of capacity seats,
+// rounded down
+func (e *Event) OversellPlaces(capacity int) int {
+	if !e.IsFree() || e.OversellPercent <= 0 {
+		return 0
+	}
+	return capacity * e.OversellPercent / 100
+}
+
+// DoorsOpenAt returns when attendees start being let in
+func (e *Event) DoorsOpenAt() time.Time {
+	return e.StartTime.Add(-time.Duration(e.DoorsOpenMinutesBeforeStart) * time.Minute)
+}
+
+// NoShowReleaseAt returns when the seats of bookings not checked in go back on sale at the door,
+// and false if the event doesn't release them. It is never before online sales close, so the
+// released seats are only sold at the door.
+func (e *Event) NoShowReleaseAt() (time.Time, bool) {
+	if !e.DoorSales || e.NoShowReleaseMinutes <= 0 {
+		return time.Time{}, false
+	}
+	releaseAt := e.DoorsOpenAt().Add(time.Duration(e.NoShowReleaseMinutes) * time.Minute)
+	if salesClose := e.SalesCloseAt(); releaseAt.Before(salesClose) {
+		releaseAt = salesClose
+	}
+	return releaseAt, true
+}
+
+// SeatPriceChange sets a new price on an event's seats matching all of the given filters
+type SeatPriceChange struct {
+	SeatIDs  []uint
+	SeatType string
+	RowStart int // 0 with RowEnd 0 matches every row
+	RowEnd   int
+	Price    money.Money
+	Source   string // admin or dynamic
+	// PricingRuleID is the dynamic pricing rule making the change
+	PricingRuleID *uint
+	ChangedBy     *uint
+}
+
+// CacheWarmResult reports a warm-up of the event cache
+type CacheWarmResult struct {
+	Events   []CachedEvent
+	WarmedAt time.Time
+}
+
+// CachedEvent is one event loaded into the cache by a warm-up, or why it couldn't be
+type CachedEvent struct {
+	EventID        uint
+	Seats          int // seats on sale in the cached seat map
+	AvailableSeats int64
+	Error          string
+}
@@ -0,0 +1,21 @@
+package entities
+
+// Sitemap lists the public pages search engines should index
+type Sitemap struct {
+	Organizers []Tenant
+	Events     []Event
+	Truncated  bool // more pages exist than a sitemap may list
+}
+
+// EventFeedPage is one page of the newly published events feed
+type EventFeedPage struct {
+	Events   []Event // newest first, with their venue and organizer
+	Page     int
+	PageSize int
+	Total    int64
+}
+
+// HasNext reports whether older events follow on another page
+func (p *EventFeedPage) HasNext() bool {
+	return int64(p.Page*p.PageSize) < p.Total
+}
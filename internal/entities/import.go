@@ -0,0 +1,32 @@
+package entities
+
+// ImportResult describes the outcome of a CSV import, either as a dry-run diff
+// or after the changes have been applied
+type ImportResult struct {
+	Kind      string           `json:"kind"`
+	DryRun    bool             `json:"dry_run"`
+	Applied   bool             `json:"applied"`
+	Created   int              `json:"created"`
+	Updated   int              `json:"updated"`
+	Unchanged int              `json:"unchanged"`
+	Changes   []ImportChange   `json:"changes"`
+	Errors    []ImportRowError `json:"errors,omitempty"`
+}
+
+type ImportChange struct {
+	Line   int                    `json:"line"`
+	Action string                 `json:"action"` // create, update, unchanged
+	Key    string                 `json:"key"`
+	Fields map[string]FieldChange `json:"fields,omitempty"`
+}
+
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+type ImportRowError struct {
+	Line    int    `json:"line"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"api/pkg/money"
+	"time"
+)
+
+// LedgerFilter selects the journals a balance report covers
+type LedgerFilter struct {
+	EventID uint       // 0 for every event
+	From    *time.Time // posted at or after
+	To      *time.Time // posted before
+}
+
+// LedgerBalance is the total debited and credited to a ledger account
+type LedgerBalance struct {
+	Account string      `json:"account"`
+	Debits  money.Money `json:"debits"`
+	Credits money.Money `json:"credits"`
+	// Balance is debits less credits, so accounts credited by sales have negative balances
+	Balance money.Money `json:"balance"`
+}
+
+// LedgerReport is the balance of every ledger account; across all accounts the debits and
+// credits are equal
+type LedgerReport struct {
+	Accounts     []LedgerBalance `json:"accounts"`
+	TotalDebits  money.Money     `json:"total_debits"`
+	TotalCredits money.Money     `json:"total_credits"`
+	Balanced     bool            `json:"balanced"`
+}
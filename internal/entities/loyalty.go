@@ -0,0 +1,47 @@
+package entities
+
+import "api/constants"
+
+// LoyaltyTier is a membership tier reached once lifetime points meet the threshold
+type LoyaltyTier struct {
+	Name      string
+	Threshold int
+}
+
+// LoyaltyTiers lists the earned membership tiers, lowest first
+var LoyaltyTiers = []LoyaltyTier{
+	{Name: constants.MembershipTierSilver, Threshold: constants.SilverTierThreshold},
+	{Name: constants.MembershipTierGold, Threshold: constants.GoldTierThreshold},
+	{Name: constants.MembershipTierPlatinum, Threshold: constants.PlatinumTierThreshold},
+}
+
+// LoyaltyTierFor returns the highest tier reached with the given lifetime points, or "" if none
+func LoyaltyTierFor(lifetimePoints int) string {
+	tier := ""
+	for _, t := range LoyaltyTiers {
+		if lifetimePoints >= t.Threshold {
+			tier = t.Name
+		}
+	}
+	return tier
+}
+
+// LoyaltyTierRank returns the position of an earned tier in LoyaltyTiers, or -1 for
+// no tier and tiers assigned manually by admins
+func LoyaltyTierRank(tier string) int {
+	for i, t := range LoyaltyTiers {
+		if t.Name == tier {
+			return i
+		}
+	}
+	return -1
+}
+
+// LoyaltySummary describes a user's points balance and progress to the next tier
+type LoyaltySummary struct {
+	Points           int
+	LifetimePoints   int
+	Tier             string
+	NextTier         string
+	PointsToNextTier int
+}
@@ -0,0 +1,137 @@
+package entities
+
+import (
+	"api/constants"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+const (
+	maxMetadataKeys        = 20
+	maxMetadataValueLength = 500
+)
+
+var metadataKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// Metadata holds custom fields on events and venues, stored as a JSONB object.
+// Values are strings, numbers or booleans.
+type Metadata map[string]interface{}
+
+// Value stores nil metadata as an empty object so the column is never NULL
+func (m Metadata) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(map[string]interface{}(m))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (m *Metadata) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported metadata type %T", value)
+	}
+	return json.Unmarshal(data, (*map[string]interface{})(m))
+}
+
+// MetadataField is a field an event type expects in its metadata
+type MetadataField struct {
+	Name     string
+	Kind     string // string, number or bool
+	Required bool
+}
+
+// EventMetadataSchemas lists the fields each event type expects. Fields not listed are
+// allowed as long as they hold a string, number or boolean.
+var EventMetadataSchemas = map[string][]MetadataField{
+	constants.EventTypeSports: {
+		{Name: "home_team", Kind: "string", Required: true},
+		{Name: "away_team", Kind: "string", Required: true},
+		{Name: "league", Kind: "string"},
+	},
+	constants.EventTypeConcert: {
+		{Name: "headliner", Kind: "string"},
+		{Name: "genre", Kind: "string"},
+	},
+	constants.EventTypeTheater: {
+		{Name: "runtime_minutes", Kind: "number"},
+	},
+	constants.EventTypeConference: {
+		{Name: "track", Kind: "string"},
+	},
+}
+
+// ValidateMetadata checks the keys and value types common to event and venue metadata
+func ValidateMetadata(m Metadata) error {
+	if len(m) > maxMetadataKeys {
+		return fmt.Errorf("metadata may have at most %d fields", maxMetadataKeys)
+	}
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !metadataKeyPattern.MatchString(key) {
+			return fmt.Errorf("metadata field %q must be lowercase letters, digits and underscores", key)
+		}
+		switch v := m[key].(type) {
+		case string:
+			if len(v) > maxMetadataValueLength {
+				return fmt.Errorf("metadata field %q is longer than %d characters", key, maxMetadataValueLength)
+			}
+		case float64, bool:
+		default:
+			return fmt.Errorf("metadata field %q must be a string, number or boolean", key)
+		}
+	}
+	return nil
+}
+
+// ValidateEventMetadata checks metadata against the schema of the event type
+func ValidateEventMetadata(eventType string, m Metadata) error {
+	if err := ValidateMetadata(m); err != nil {
+		return err
+	}
+
+	for _, field := range EventMetadataSchemas[eventType] {
+		value, ok := m[field.Name]
+		if !ok {
+			if field.Required {
+				return fmt.Errorf("%s events require metadata field %q", eventType, field.Name)
+			}
+			continue
+		}
+
+		valid := false
+		switch field.Kind {
+		case "string":
+			s, isString := value.(string)
+			valid = isString && (s != "" || !field.Required)
+		case "number":
+			_, valid = value.(float64)
+		case "bool":
+			_, valid = value.(bool)
+		}
+		if !valid {
+			return fmt.Errorf("metadata field %q must be a %s", field.Name, field.Kind)
+		}
+	}
+	return nil
+}
@@ -1,122 +1,689 @@
 package entities
 
 import (
+	"api/pkg/money"
 	"time"
 
 	"gorm.io/gorm"
 )
 
 type User struct {
+	ID             uint   `gorm:"primaryKey"`
+	Email          string `gorm:"unique;not null"`
+	Password       string `gorm:"not null"`
+	IsAdmin        bool   `gorm:"default:false"`
+	FirstName      string `gorm:"size:100"`
+	LastName       string `gorm:"size:100"`
+	Phone          string `gorm:"type:text;serializer:encrypted"`
+	MembershipTier string `gorm:"size:50;index"`                       // used to prioritise waitlists, empty means general
+	LoyaltyPoints  int    `gorm:"default:0;check:loyalty_points >= 0"` // redeemable balance
+	LifetimePoints int    `gorm:"default:0"`                           // total earned, determines the membership tier
+	TenantID       *uint  `gorm:"index"`                               // scopes an admin to one organizer, nil for platform admins
+	TokenVersion   int    `gorm:"not null;default:1"`                  // bumped when credentials change, revoking issued tokens
+	// Deleting an account blocks it until DeletionScheduledAt, when it is anonymized unless
+	// restored with the link holding the token RestoreTokenHash was made from
+	DeletionScheduledAt *time.Time `gorm:"index"`
+	RestoreTokenHash    string     `gorm:"size:64;index"`
+	AnonymizedAt        *time.Time
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	Bookings            []Booking `gorm:"foreignKey:UserID"`
+}
+
+// Tenant is an organizer running events on the platform. Venues, events and bookings
+// belong to a tenant, and tenant admins only see their own tenant's records.
+type Tenant struct {
 	ID        uint   `gorm:"primaryKey"`
-	Email     string `gorm:"unique;not null"`
-	Password  string `gorm:"not null"`
-	IsAdmin   bool   `gorm:"default:false"`
-	FirstName string `gorm:"size:100"`
-	LastName  string `gorm:"size:100"`
-	Phone     string `gorm:"size:20"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Bookings  []Booking `gorm:"foreignKey:UserID"`
+	Name      string `gorm:"not null;size:255"`
+	Slug      string `gorm:"not null;size:100;uniqueIndex"`
+	RateLimit int    `gorm:"default:0"` // admin requests per minute for the tenant, 0 uses the default
+	// Branding returned with event data so white-label storefronts can render per organizer
+	DisplayName    string `gorm:"size:255"` // shown to attendees, defaults to Name
+	LogoURL        string `gorm:"size:500"`
+	SupportEmail   string `gorm:"size:255"`
+	PrimaryColor   string `gorm:"size:7"` // #rrggbb
+	SecondaryColor string `gorm:"size:7"`
+	AccentColor    string `gorm:"size:7"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 type Venue struct {
-	ID          uint   `gorm:"primaryKey"`
-	Name        string `gorm:"not null;size:255"`
-	Address     string `gorm:"not null;size:500"`
-	City        string `gorm:"not null;size:100"`
-	State       string `gorm:"not null;size:100"`
-	Country     string `gorm:"not null;size:100"`
-	Rows        int    `gorm:"not null"`
-	Columns     int    `gorm:"not null"`
-	Description string `gorm:"type:text"`
+	ID          uint     `gorm:"primaryKey"`
+	TenantID    uint     `gorm:"not null;default:1;index"`
+	Name        string   `gorm:"not null;size:255"`
+	Address     string   `gorm:"not null;size:500"`
+	City        string   `gorm:"not null;size:100"`
+	State       string   `gorm:"not null;size:100"`
+	Country     string   `gorm:"not null;size:100"`
+	Rows        int      `gorm:"not null"`
+	Columns     int      `gorm:"not null"`
+	Description string   `gorm:"type:text"`
+	Metadata    Metadata `gorm:"type:jsonb;not null;default:'{}'"` // custom fields, e.g. parking or transit notes
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
-	Events      []Event `gorm:"foreignKey:VenueID"`
+	Events      []Event        `gorm:"foreignKey:VenueID"`
+	Sections    []VenueSection `gorm:"foreignKey:VenueID"`
+	// Venue policy: ticket sales close this many minutes before an event starts, unless the
+	// event sets its own cutoff
+	SalesCloseMinutesBeforeStart int `gorm:"not null;default:0"`
+	// Location of the venue, used by weather forecasts
+	Latitude  *float64
+	Longitude *float64
+	// comma-separated enrichers that add outside data to the venue's event details, e.g. "weather"
+	// for outdoor venues; empty enables none
+	Enrichments string `gorm:"size:255"`
+	// Venues synced from a partner's catalog feed: the feed's source name and its ID for the venue
+	ExternalSource string `gorm:"size:50;index:idx_venues_external,priority:1"`
+	ExternalID     string `gorm:"size:100;index:idx_venues_external,priority:2"`
+}
+
+// VenueSection describes a block of rows in a venue's seat map. Seats generated
+// for an event inherit the section's seat type and price multiplier.
+type VenueSection struct {
+	ID              uint    `gorm:"primaryKey"`
+	VenueID         uint    `gorm:"index;not null"`
+	Name            string  `gorm:"not null;size:100"`
+	RowStart        int     `gorm:"not null"`
+	RowEnd          int     `gorm:"not null"`
+	SeatType        string  `gorm:"not null;size:50"`
+	PriceMultiplier float64 `gorm:"not null;default:1"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
 
 type Event struct {
-	ID             uint      `gorm:"primaryKey"`
-	Name           string    `gorm:"not null;size:255;index"`
-	Description    string    `gorm:"type:text"`
-	VenueID        uint      `gorm:"index;not null"`
-	Venue          Venue     `gorm:"foreignKey:VenueID;references:ID"`
-	StartTime      time.Time `gorm:"not null;index"`
-	EndTime        time.Time `gorm:"not null;index"`
-	Price          float64   `gorm:"not null"`
-	EventType      string    `gorm:"not null;size:50;index"`                  // concert, theater, sports, etc. - add index
-	Status         string    `gorm:"not null;size:20;default:'active';index"` // active, cancelled, completed - add index
-	IsHighDemand   bool      `gorm:"default:false;index"`                     // for queue system - add index
-	AvailableSeats int       `gorm:"default:0;index;check:available_seats >= 0"`
+	ID                 uint        `gorm:"primaryKey"`
+	TenantID           uint        `gorm:"not null;default:1;index"` // copied from the venue
+	Tenant             Tenant      `gorm:"foreignKey:TenantID"`
+	Name               string      `gorm:"not null;size:255;index"`
+	Description        string      `gorm:"type:text"`
+	VenueID            uint        `gorm:"index;not null"`
+	Venue              Venue       `gorm:"foreignKey:VenueID;references:ID"`
+	StartTime          time.Time   `gorm:"not null;index;index:idx_events_status_start_time,priority:2"`
+	EndTime            time.Time   `gorm:"not null;index"`
+	Price              money.Money `gorm:"not null"`
+	EventType          string      `gorm:"not null;size:50;index"`                                                                // concert, theater, sports, etc. - add index
+	Status             string      `gorm:"not null;size:20;default:'active';index;index:idx_events_status_start_time,priority:1"` // active, cancelled, completed - add index
+	IsHighDemand       bool        `gorm:"default:false;index"`                                                                   // for queue system - add index
+	AvailableSeats     int         `gorm:"default:0;index;check:available_seats >= 0"`
+	ReminderOffsets    string      `gorm:"size:100"`  // comma-separated durations before start_time, empty disables reminders
+	FollowUpAt         *time.Time  `gorm:"index"`     // when post-event no-show marking and feedback requests ran
+	ArchivedAt         *time.Time  `gorm:"index"`     // when its bookings and intents were moved to the archive tables
+	WaitlistCap        int         `gorm:"default:0"` // maximum waitlist size, 0 means unlimited
+	WaitlistTiers      string      `gorm:"size:255"`  // comma-separated priority tiers, highest first, e.g. "member,general"
+	OnSaleAt           *time.Time  // general on-sale, bookings before it need early access; nil means on sale immediately
+	EarlyAccessAt      *time.Time  // when the presale opens for presale code holders and members of EarlyAccessTier and above
+	EarlyAccessTier    string      `gorm:"size:50"`
+	InitialReleaseRows int         `gorm:"default:0"`                        // rows 1..N go on sale at creation, later rows are held for release waves; 0 releases all
+	Terms              string      `gorm:"type:text"`                        // terms and conditions attendees must accept to book
+	TermsVersion       string      `gorm:"size:50"`                          // bumped whenever Terms change; empty means no terms
+	MinimumAge         int         `gorm:"default:0"`                        // attendees must be at least this old on the event date, 0 means no restriction
+	RequireFullName    bool        `gorm:"default:false"`                    // attendee's full name is collected at confirmation and printed on the ticket
+	RequireIDNumber    bool        `gorm:"default:false"`                    // attendee's ID number is collected at confirmation and stored encrypted
+	Metadata           Metadata    `gorm:"type:jsonb;not null;default:'{}'"` // custom fields validated against EventMetadataSchemas
+	// comma-separated ISO country codes intents may be created from, e.g. "GB,IE"; empty sells everywhere
+	SaleCountries string `gorm:"size:255"`
+	// minutes before start_time that intents and confirmations stop being accepted; taken from
+	// the venue's policy when the event is created without one
+	SalesCloseMinutesBeforeStart *int
+	// box-office mode: staff can sell seats at the door until the event ends, after online sales close
+	DoorSales bool `gorm:"default:false"`
+	// No-show re-inventory of door-sale events: NoShowReleaseMinutes after the doors open, the
+	// seats of bookings not checked in go back on sale at the door; 0 disables it. Released
+	// bookings are only refunded when NoShowReleaseRefund is set.
+	DoorsOpenMinutesBeforeStart int        `gorm:"default:0"`
+	NoShowReleaseMinutes        int        `gorm:"default:0"`
+	NoShowReleaseRefund         bool       `gorm:"default:false"`
+	NoShowReleasedAt            *time.Time `gorm:"index"` // when the seats of no-shows were released
+	// Free events can be oversold by OversellPercent of their capacity to make up for expected
+	// no-shows. The extra places are seats of their own, kept out of the seats on sale and
+	// given to the waitlist once the event sells out.
+	OversellPercent int `gorm:"default:0"`
+	// Free events only: confirmed tickets one user can hold, 0 takes FREE_EVENT_TICKET_LIMIT
+	TicketLimit int `gorm:"default:0"`
+	// Sandbox events rehearse the on-sale of SandboxOfID on their own seats: they are unlisted,
+	// take no payment and are left out of analytics, reminders and loyalty points
+	Sandbox        bool  `gorm:"default:false;index"`
+	SandboxOfID    *uint `gorm:"index"`
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 	Seats          []Seat          `gorm:"foreignKey:EventID"`
 	Bookings       []Booking       `gorm:"foreignKey:EventID"`
 	BookingIntents []BookingIntent `gorm:"foreignKey:EventID"`
+	// Events synced from a partner's catalog feed: the feed's source name and its ID for the event
+	ExternalSource string `gorm:"size:50;index:idx_events_external,priority:1"`
+	ExternalID     string `gorm:"size:100;index:idx_events_external,priority:2"`
 }
 
 type Seat struct {
-	ID             uint       `gorm:"primaryKey"`
-	EventID        uint       `gorm:"index;not null"`
-	Event          Event      `gorm:"foreignKey:EventID"`
-	Row            int        `gorm:"not null;index"`
-	Column         int        `gorm:"not null;index"`
-	SeatType       string     `gorm:"not null;size:50;index"` // VIP, Premium, Standard - add index
-	Price          float64    `gorm:"not null"`
-	IsAvailable    bool       `gorm:"default:true;index"`
-	IsLocked       bool       `gorm:"default:false;index"`
-	LockedAt       *time.Time `gorm:"index"`
-	LockedBy       *uint      `gorm:"index"` // UserID who locked it - add index
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
-	Bookings       []Booking       `gorm:"foreignKey:SeatID"`
-	BookingIntents []BookingIntent `gorm:"foreignKey:SeatID"`
+	ID                uint        `gorm:"primaryKey"`
+	EventID           uint        `gorm:"index;not null;index:idx_seats_event_availability,priority:1"`
+	Event             Event       `gorm:"foreignKey:EventID"`
+	Row               int         `gorm:"not null;index"`
+	Column            int         `gorm:"not null;index"`
+	SeatType          string      `gorm:"not null;size:50;index"` // VIP, Premium, Standard - add index
+	Price             money.Money `gorm:"not null"`
+	IsAvailable       bool        `gorm:"default:true;index;index:idx_seats_event_availability,priority:2"`
+	IsLocked          bool        `gorm:"default:false;index;index:idx_seats_event_availability,priority:3"`
+	LockedAt          *time.Time  `gorm:"index"`
+	LockedBy          *uint       `gorm:"index"`               // UserID who locked it - add index
+	LockExpiresAt     *time.Time  `gorm:"index"`               // lock expiry of the intent holding the database lock
+	IsHeld            bool        `gorm:"default:false;index"` // held back from sale until released in a later wave
+	IsAccessible      bool        `gorm:"default:false;index"` // wheelchair or other accessible seating
+	IsCompanion       bool        `gorm:"default:false;index"` // reserved for a companion of an accessible-seat holder
+	CompanionOfSeatID *uint       `gorm:"index"`               // accessible seat this companion seat belongs to
+	PricingRuleID     *uint       `gorm:"index"`               // dynamic pricing rule that set the current price, if any
+	IsOversell        bool        `gorm:"default:false;index"` // oversell place of a free event, in row 0 and never on sale
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	Bookings          []Booking       `gorm:"foreignKey:SeatID"`
+	BookingIntents    []BookingIntent `gorm:"foreignKey:SeatID"`
+	// Hold placed by the venue's external inventory system, and when its latest message about
+	// the seat happened; older messages are ignored
+	ExternalHoldRef   string `gorm:"size:100;index"`
+	ExternalUpdatedAt *time.Time
 }
 
 type BookingIntent struct {
-	ID              uint   `gorm:"primaryKey"`
-	UserID          uint   `gorm:"index;not null"`
-	User            User   `gorm:"foreignKey:UserID"`
-	EventID         uint   `gorm:"index;not null"`
-	Event           Event  `gorm:"foreignKey:EventID"`
-	SeatID          uint   `gorm:"index;not null"`
-	Seat            Seat   `gorm:"foreignKey:SeatID"`
-	Status          string `gorm:"not null;size:20;index"` // pending, expired, confirmed, cancelled - add index
-	PaymentIntentID string `gorm:"size:255;index"`         // from payment gateway - add index
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
+	ID              uint             `gorm:"primaryKey"`
+	UserID          uint             `gorm:"index;not null"`
+	User            User             `gorm:"foreignKey:UserID"`
+	EventID         uint             `gorm:"index;not null"`
+	Event           Event            `gorm:"foreignKey:EventID"`
+	SeatID          uint             `gorm:"index;not null"`
+	Seat            Seat             `gorm:"foreignKey:SeatID"`
+	Status          string           `gorm:"not null;size:20;index;index:idx_booking_intents_status_created_at,priority:1"` // pending, expired, confirmed, cancelled - add index
+	PaymentIntentID string           `gorm:"size:255;index"`                                                                // from payment gateway - add index
+	PaymentStatus   string           `gorm:"size:20;default:'pending'"`
+	PaymentAttempts []PaymentAttempt `gorm:"foreignKey:BookingIntentID"`
+	PresaleCodeID   *uint            `gorm:"index"`   // presale code that allowed booking before the general on-sale
+	TermsVersion    string           `gorm:"size:50"` // version of the event terms accepted when the intent was created
+	TermsAcceptedAt *time.Time
+	// LockExpiresAt is computed once at creation; the Redis seat lock expires at the same instant
+	LockExpiresAt time.Time `gorm:"index"`
+	// ResumeTokenHash is the SHA-256 of the single-use token that resumes the checkout on another device
+	ResumeTokenHash      string `gorm:"size:64;index"`
+	ResumeTokenExpiresAt *time.Time
+	CreatedAt            time.Time `gorm:"index:idx_booking_intents_status_created_at,priority:2"`
+	UpdatedAt            time.Time
+	// How the user found the event, copied to the booking
+	Attribution `gorm:"embedded"`
+	// Booking is what a free event's intent was confirmed into when it was created
+	Booking *Booking `gorm:"-"`
 }
 
 type Booking struct {
-	ID              uint       `gorm:"primaryKey"`
-	UserID          uint       `gorm:"index;not null"`
-	User            User       `gorm:"foreignKey:UserID"`
-	EventID         uint       `gorm:"index;not null"`
-	Event           Event      `gorm:"foreignKey:EventID"`
-	SeatID          uint       `gorm:"index;not null;uniqueIndex:idx_seat_active_booking,where:status = 'confirmed' AND deleted_at IS NULL"`
-	Seat            Seat       `gorm:"foreignKey:SeatID"`
-	BookingIntentID *uint      `gorm:"index"`                  // reference to the intent that created this booking
-	Status          string     `gorm:"not null;size:20;index"` // confirmed, cancelled, refunded - add index
-	PaymentStatus   string     `gorm:"not null;size:20;index"` // paid, pending, failed, refunded - add index
-	PaymentID       string     `gorm:"size:255;index"`         // from payment gateway - add index
-	TotalAmount     float64    `gorm:"not null"`
-	BookedAt        time.Time  `gorm:"not null;index"`
-	CancelledAt     *time.Time `gorm:"index"`
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
-	DeletedAt       gorm.DeletedAt `gorm:"index"`
+	ID                   uint        `gorm:"primaryKey"`
+	TenantID             uint        `gorm:"not null;default:1;index"` // copied from the event
+	UserID               uint        `gorm:"index;not null;index:idx_bookings_user_created_at,priority:1;index:idx_bookings_user_upcoming,priority:1,where:deleted_at IS NULL"`
+	User                 User        `gorm:"foreignKey:UserID"`
+	EventID              uint        `gorm:"index;not null;index:idx_bookings_user_upcoming,priority:3"`
+	Event                Event       `gorm:"foreignKey:EventID"`
+	SeatID               uint        `gorm:"index;not null;uniqueIndex:idx_seat_active_booking,where:status = 'confirmed' AND deleted_at IS NULL;index:idx_bookings_user_upcoming,priority:4"`
+	Seat                 Seat        `gorm:"foreignKey:SeatID"`
+	BookingIntentID      *uint       `gorm:"index"`                                                              // reference to the intent that created this booking
+	Status               string      `gorm:"not null;size:20;index;index:idx_bookings_user_upcoming,priority:2"` // confirmed, cancelled, refunded - add index
+	PaymentStatus        string      `gorm:"not null;size:20;index"`                                             // paid, pending, failed, refunded - add index
+	PaymentID            string      `gorm:"size:255;index"`                                                     // from payment gateway - add index
+	TotalAmount          money.Money `gorm:"not null"`
+	BookedAt             time.Time   `gorm:"not null;index"`
+	CancelledAt          *time.Time  `gorm:"index"`
+	CheckedInAt          *time.Time  `gorm:"index"`
+	CheckInDeviceID      *uint       `gorm:"index"` // the scanner device that checked the booking in, if any
+	NoShowReleasedAt     *time.Time  // its seat went back on sale at the door because it wasn't checked in
+	NoShow               bool        `gorm:"default:false;index"` // set when the event completed without check-in
+	Disputed             bool        `gorm:"default:false;index"` // an open payment dispute exists for this booking
+	TicketRevokedAt      *time.Time  // tickets revoked by a dispute can no longer be checked in
+	DiscountAmount       money.Money `gorm:"default:0"` // loyalty discount already deducted from TotalAmount
+	PointsRedeemed       int         `gorm:"default:0"`
+	PointsEarned         int         `gorm:"default:0"`
+	PresaleCodeID        *uint       `gorm:"index"`
+	CompanionOfBookingID *uint       `gorm:"index"`   // accessible-seat booking a companion booking is linked to
+	TermsVersion         string      `gorm:"size:50"` // event terms version the user accepted, kept for compliance
+	TermsAcceptedAt      *time.Time
+	AttendeeName         string    `gorm:"size:200"`
+	AttendeeBirthDate    string    `gorm:"type:text;serializer:encrypted"` // YYYY-MM-DD, collected for age-restricted events
+	AttendeeIDNumber     string    `gorm:"type:text;serializer:encrypted"`
+	Sandbox              bool      `gorm:"default:false;index"` // copied from the event; left out of analytics
+	CreatedAt            time.Time `gorm:"index:idx_bookings_user_created_at,priority:2"`
+	UpdatedAt            time.Time
+	DeletedAt            gorm.DeletedAt `gorm:"index"`
+	// Acquisition channel, copied from the intent
+	Attribution `gorm:"embedded"`
+	// online, door for seats sold by staff at the box office, or comp for complimentary tickets
+	Channel string `gorm:"not null;size:20;default:'online';index"`
+	// why a complimentary ticket was issued, e.g. "press" or "artist guest list"
+	CompReason string `gorm:"size:255"`
+	// The seat price charged, before discounts, and the pricing rule that set it. Kept for
+	// disputes and audits, as the seat's price can change after the booking.
+	SeatPrice     money.Money `gorm:"default:0"`
+	PricingRuleID *uint       `gorm:"index"`
 }
 
 type EventQueue struct {
 	ID            uint       `gorm:"primaryKey"`
-	EventID       uint       `gorm:"index;not null"`
+	EventID       uint       `gorm:"index;not null;uniqueIndex:idx_event_queue_on_sale_user,priority:1,where:kind = 'on_sale' AND (status = 'waiting' OR status = 'active')"` // one place per user in an on-sale queue
 	Event         Event      `gorm:"foreignKey:EventID"`
-	UserID        uint       `gorm:"index;not null"`
+	UserID        uint       `gorm:"index;not null;uniqueIndex:idx_event_queue_on_sale_user,priority:2,where:kind = 'on_sale' AND (status = 'waiting' OR status = 'active')"`
 	User          User       `gorm:"foreignKey:UserID"`
-	QueuePosition int        `gorm:"not null;index"`         // Add index for position-based queries
-	Status        string     `gorm:"not null;size:20;index"` // waiting, active, expired, completed - add index
+	Kind          string     `gorm:"not null;size:20;default:'waitlist';index"` // waitlist for sold-out events, on_sale for the queue before an on-sale
+	QueuePosition int        `gorm:"not null;index"`                            // Add index for position-based queries
+	Status        string     `gorm:"not null;size:20;index"`                    // waiting, active, expired, completed, cancelled - add index
 	JoinedAt      time.Time  `gorm:"not null;index"`
 	ActiveAt      *time.Time `gorm:"index"`
 	ExpiresAt     *time.Time `gorm:"index"`
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 }
+
+// BookingReminder records a reminder sent (or attempted) for a booking so each
+// reminder offset is delivered at most once per booking
+type BookingReminder struct {
+	ID             uint       `gorm:"primaryKey"`
+	BookingID      uint       `gorm:"not null;uniqueIndex:idx_booking_reminder_offset"`
+	Booking        Booking    `gorm:"foreignKey:BookingID"`
+	ReminderOffset string     `gorm:"not null;size:20;uniqueIndex:idx_booking_reminder_offset"` // e.g. 24h, 2h
+	Status         string     `gorm:"not null;size:20;index"`                                   // pending, sent, failed
+	SentAt         *time.Time `gorm:"index"`
+	Error          string     `gorm:"type:text"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// PaymentAttempt records each payment attempt made for a booking intent
+type PaymentAttempt struct {
+	ID              uint   `gorm:"primaryKey"`
+	BookingIntentID uint   `gorm:"not null;uniqueIndex:idx_intent_attempt"`
+	AttemptNumber   int    `gorm:"not null;uniqueIndex:idx_intent_attempt"`
+	Reference       string `gorm:"not null;size:255;index"`
+	Status          string `gorm:"not null;size:20;index"` // pending, paid, failed
+	FailureReason   string `gorm:"size:255"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Dispute tracks a payment-provider chargeback against a booking
+type Dispute struct {
+	ID                uint           `gorm:"primaryKey"`
+	ProviderDisputeID string         `gorm:"not null;size:255;uniqueIndex"`
+	BookingID         uint           `gorm:"not null;index"`
+	Booking           Booking        `gorm:"foreignKey:BookingID"`
+	PaymentID         string         `gorm:"not null;size:255;index"`
+	Status            string         `gorm:"not null;size:20;index"` // open, under_review, won, lost
+	Reason            string         `gorm:"size:255"`
+	Amount            money.Money    `gorm:"not null;default:0"`
+	OpenedAt          time.Time      `gorm:"not null;index"`
+	ClosedAt          *time.Time     `gorm:"index"`
+	Events            []DisputeEvent `gorm:"foreignKey:DisputeID"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// DisputeEvent records each lifecycle transition of a dispute
+type DisputeEvent struct {
+	ID         uint      `gorm:"primaryKey"`
+	DisputeID  uint      `gorm:"not null;index"`
+	Status     string    `gorm:"not null;size:20"`
+	OccurredAt time.Time `gorm:"not null"`
+	CreatedAt  time.Time
+}
+
+// PaymentTransaction stores the reconciliation record of a payment. Only provider
+// references and a masked payment method are kept, never raw card data.
+type PaymentTransaction struct {
+	ID                uint                      `gorm:"primaryKey"`
+	BookingID         *uint                     `gorm:"index"`
+	Booking           *Booking                  `gorm:"foreignKey:BookingID"`
+	BookingIntentID   *uint                     `gorm:"index"`
+	Provider          string                    `gorm:"not null;size:50;uniqueIndex:idx_provider_reference"`
+	ProviderReference string                    `gorm:"not null;size:255;uniqueIndex:idx_provider_reference"`
+	Amount            money.Money               `gorm:"not null"`
+	Currency          string                    `gorm:"not null;size:3"`
+	MethodType        string                    `gorm:"size:20"`                // card, wallet, bank_transfer
+	MaskedMethod      string                    `gorm:"size:50"`                // e.g. visa •••• 4242
+	Status            string                    `gorm:"not null;size:20;index"` // pending, paid, failed, refunded, disputed, charged_back
+	Events            []PaymentTransactionEvent `gorm:"foreignKey:PaymentTransactionID"`
+	CreatedAt         time.Time                 `gorm:"index"`
+	UpdatedAt         time.Time
+}
+
+// PaymentTransactionEvent records each status change of a payment transaction
+type PaymentTransactionEvent struct {
+	ID                   uint      `gorm:"primaryKey"`
+	PaymentTransactionID uint      `gorm:"not null;index"`
+	Status               string    `gorm:"not null;size:20"`
+	OccurredAt           time.Time `gorm:"not null"`
+	CreatedAt            time.Time
+}
+
+// LoyaltyTransaction is an entry in a user's loyalty points ledger
+type LoyaltyTransaction struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	BookingID *uint     `gorm:"index"`
+	Booking   *Booking  `gorm:"foreignKey:BookingID"`
+	Points    int       `gorm:"not null"`         // positive when earned, negative when redeemed
+	Reason    string    `gorm:"not null;size:20"` // earn, redeem, reversal
+	Balance   int       `gorm:"not null"`         // balance after this entry
+	CreatedAt time.Time `gorm:"index"`
+}
+
+// LedgerJournal is one double-entry posting of a financial movement, such as a sale or a
+// refund. Journals and their entries are only ever inserted, never changed or deleted.
+type LedgerJournal struct {
+	ID          uint          `gorm:"primaryKey"`
+	TenantID    uint          `gorm:"not null;default:1;index"`
+	Kind        string        `gorm:"not null;size:20;index"` // sale, refund, fee
+	BookingID   *uint         `gorm:"index"`
+	EventID     *uint         `gorm:"index"`
+	Description string        `gorm:"size:255"`
+	Entries     []LedgerEntry `gorm:"foreignKey:JournalID"`
+	CreatedAt   time.Time     `gorm:"index"`
+}
+
+// LedgerEntry debits or credits one account; a journal's debits always equal its credits
+type LedgerEntry struct {
+	ID        uint        `gorm:"primaryKey"`
+	JournalID uint        `gorm:"not null;index"`
+	Account   string      `gorm:"not null;size:20;index"` // cash, sales, refunds, fees
+	Debit     money.Money `gorm:"not null;default:0"`
+	Credit    money.Money `gorm:"not null;default:0"`
+	CreatedAt time.Time
+}
+
+// SaleRegionOverride lets a user book an event from outside its sale countries, e.g. a
+// travelling fan or an artist's guest
+type SaleRegionOverride struct {
+	ID        uint   `gorm:"primaryKey"`
+	EventID   uint   `gorm:"not null;uniqueIndex:idx_sale_region_override_event_user"`
+	Event     Event  `gorm:"foreignKey:EventID"`
+	UserID    uint   `gorm:"not null;uniqueIndex:idx_sale_region_override_event_user"`
+	User      User   `gorm:"foreignKey:UserID"`
+	Note      string `gorm:"size:255"`
+	CreatedBy uint   `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+// EventReview is an attendee's rating of an event they checked in to, one per booking
+type EventReview struct {
+	ID        uint   `gorm:"primaryKey"`
+	TenantID  uint   `gorm:"not null;default:1;index"` // copied from the booking
+	EventID   uint   `gorm:"not null;index"`
+	Event     Event  `gorm:"foreignKey:EventID"`
+	BookingID uint   `gorm:"not null;uniqueIndex"`
+	UserID    uint   `gorm:"not null;index"`
+	Rating    int    `gorm:"not null"` // 1 to 5
+	Comment   string `gorm:"size:1000"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Referrer is a user or affiliate partner whose referral code attributes bookings to them,
+// earning a commission on each
+type Referrer struct {
+	ID             uint    `gorm:"primaryKey"`
+	Code           string  `gorm:"not null;size:50;uniqueIndex"`
+	Kind           string  `gorm:"not null;size:20"` // user or partner
+	Name           string  `gorm:"not null;size:255"`
+	Email          string  `gorm:"size:255"` // payout contact
+	UserID         *uint   `gorm:"index"`    // account that sees the referrer dashboard
+	CommissionRate float64 `gorm:"not null"` // percent of the amount paid for a booking
+	Active         bool    `gorm:"default:true"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// ReferralCommission is what a referrer earns on a booking made with their code. It's only
+// owed while the booking stays confirmed, and paid out once the event has ended.
+type ReferralCommission struct {
+	ID         uint        `gorm:"primaryKey"`
+	ReferrerID uint        `gorm:"not null;index"`
+	BookingID  uint        `gorm:"not null;uniqueIndex"`
+	Booking    Booking     `gorm:"foreignKey:BookingID"`
+	EventID    uint        `gorm:"not null;index"`
+	Event      Event       `gorm:"foreignKey:EventID"`
+	Amount     money.Money `gorm:"not null"` // paid for the booking
+	Rate       float64     `gorm:"not null"` // the referrer's rate when the booking was made
+	Commission money.Money `gorm:"not null"`
+	Status     string      `gorm:"not null;size:20;default:'pending';index"` // pending or paid
+	PaidAt     *time.Time
+	CreatedAt  time.Time
+}
+
+// EventSettlement is an organizer's statement for an event, frozen once the event has completed
+// so later changes no longer move it. Amounts are in the event's currency.
+type EventSettlement struct {
+	ID              uint        `gorm:"primaryKey"`
+	TenantID        uint        `gorm:"not null;default:1;index"`
+	EventID         uint        `gorm:"not null;uniqueIndex"`
+	TicketsSold     int64       `gorm:"not null"` // paid bookings, refunded ones included
+	GrossSales      money.Money `gorm:"not null"`
+	RefundedTickets int64       `gorm:"not null"`
+	Refunds         money.Money `gorm:"not null"`
+	Chargebacks     money.Money `gorm:"not null"` // payment disputes lost
+	CompTickets     int64       `gorm:"not null"`
+	FeePercent      float64     `gorm:"not null"` // platform fee rates the fees were computed with
+	FeePerTicket    money.Money `gorm:"not null"`
+	PlatformFees    money.Money `gorm:"not null"`
+	NetPayable      money.Money `gorm:"not null"`
+	Capacity        int64       `gorm:"not null"` // seats generated for the event
+	HeldSeats       int64       `gorm:"not null"` // never released for sale
+	SellableSeats   int64       `gorm:"not null"` // capacity less held seats and comps
+	SoldSeats       int64       `gorm:"not null"`
+	FrozenAt        *time.Time
+	FrozenBy        *uint
+	CreatedAt       time.Time
+}
+
+// PresaleBatch is a set of presale codes generated together by an admin for one event
+type PresaleBatch struct {
+	ID        uint          `gorm:"primaryKey"`
+	EventID   uint          `gorm:"not null;index"`
+	Event     Event         `gorm:"foreignKey:EventID"`
+	Name      string        `gorm:"not null;size:100"`
+	MaxUses   int           `gorm:"not null"` // uses allowed per code, 0 means unlimited
+	CreatedBy uint          `gorm:"not null"`
+	Codes     []PresaleCode `gorm:"foreignKey:BatchID"`
+	CreatedAt time.Time
+}
+
+// PresaleCode grants booking access during an event's presale window. A use is
+// counted when a booking made with the code is confirmed.
+type PresaleCode struct {
+	ID        uint   `gorm:"primaryKey"`
+	BatchID   uint   `gorm:"not null;index"`
+	EventID   uint   `gorm:"not null;index"`
+	Code      string `gorm:"not null;size:32;uniqueIndex"`
+	MaxUses   int    `gorm:"not null"`
+	Uses      int    `gorm:"not null;default:0"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ScannerDevice is a door scanner registered to one gate of an event. It authenticates with a
+// device token, stored hashed, until revoked.
+type ScannerDevice struct {
+	ID         uint       `gorm:"primaryKey"`
+	TenantID   uint       `gorm:"not null;default:1;index"` // copied from the event
+	EventID    uint       `gorm:"not null;index"`
+	Event      Event      `gorm:"foreignKey:EventID"`
+	Name       string     `gorm:"not null;size:100"`
+	Gate       string     `gorm:"not null;size:50"`
+	TokenHash  string     `gorm:"not null;size:64;uniqueIndex"`
+	CreatedBy  uint       `gorm:"not null"`
+	LastSeenAt *time.Time // last authenticated request, updated at most once a minute
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// EventPost is an announcement, such as a gate change, or an FAQ entry an organizer attached
+// to an event. Both are shown on the public event page; announcements are also emailed to the
+// event's booked attendees.
+type EventPost struct {
+	ID         uint       `gorm:"primaryKey"`
+	TenantID   uint       `gorm:"not null;default:1;index"` // copied from the event
+	EventID    uint       `gorm:"not null;index"`
+	Event      Event      `gorm:"foreignKey:EventID"`
+	Kind       string     `gorm:"not null;size:20"`   // announcement or faq
+	Title      string     `gorm:"not null;size:255"`  // the question of an FAQ
+	Body       string     `gorm:"type:text;not null"` // the answer of an FAQ
+	Position   int        `gorm:"default:0"`          // FAQs are listed by position, lowest first
+	AuthorID   uint       `gorm:"not null"`
+	NotifiedAt *time.Time `gorm:"index"` // when attendees were emailed an announcement
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// SupportCase is a user's request for help with one of their bookings, answered by the
+// organizer's staff in a thread of messages until resolved
+type SupportCase struct {
+	ID         uint   `gorm:"primaryKey"`
+	TenantID   uint   `gorm:"not null;default:1;index"` // copied from the booking
+	BookingID  uint   `gorm:"not null;index"`
+	UserID     uint   `gorm:"not null;index"`
+	User       User   `gorm:"foreignKey:UserID"`
+	EventID    uint   `gorm:"not null;index"`
+	Category   string `gorm:"not null;size:20;index"` // refund, ticket, access, payment, other
+	Status     string `gorm:"not null;size:20;index"` // open, answered, resolved
+	ResolvedAt *time.Time
+	Messages   []SupportCaseMessage `gorm:"foreignKey:CaseID"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// SupportCaseMessage is one message of a support case, from the user or a staff member
+type SupportCaseMessage struct {
+	ID        uint   `gorm:"primaryKey"`
+	CaseID    uint   `gorm:"not null;index"`
+	AuthorID  uint   `gorm:"not null"`
+	FromStaff bool   `gorm:"not null;default:false"`
+	Message   string `gorm:"type:text;not null"`
+	CreatedAt time.Time
+}
+
+// SeatRelease records a wave of held seats an admin put on sale
+type SeatRelease struct {
+	ID            uint `gorm:"primaryKey"`
+	EventID       uint `gorm:"not null;index"`
+	RowStart      int  `gorm:"not null"`
+	RowEnd        int  `gorm:"not null"`
+	SeatsReleased int  `gorm:"not null"`
+	ReleasedBy    uint `gorm:"not null"`
+	CreatedAt     time.Time
+}
+
+// SeatPriceHistory records a change of a seat's price, by an admin or a dynamic pricing rule
+type SeatPriceHistory struct {
+	ID            uint        `gorm:"primaryKey"`
+	EventID       uint        `gorm:"not null;index"`
+	SeatID        uint        `gorm:"not null;index:idx_seat_price_history_seat,priority:1"`
+	OldPrice      money.Money `gorm:"not null"`
+	NewPrice      money.Money `gorm:"not null"`
+	Source        string      `gorm:"not null;size:20;index"` // admin or dynamic
+	PricingRuleID *uint       `gorm:"index"`
+	ChangedBy     *uint       // admin who changed the price
+	CreatedAt     time.Time   `gorm:"index:idx_seat_price_history_seat,priority:2"`
+}
+
+// Artifact is a generated file such as a ticket, receipt or export kept in artifact storage.
+// Artifacts past their expiry are deleted from storage by the cleanup job.
+type Artifact struct {
+	ID          uint       `gorm:"primaryKey"`
+	Kind        string     `gorm:"not null;size:50;index"`
+	Key         string     `gorm:"not null;size:500;uniqueIndex"`
+	FileName    string     `gorm:"not null;size:255"`
+	ContentType string     `gorm:"not null;size:100"`
+	Size        int64      `gorm:"not null"`
+	ExpiresAt   *time.Time `gorm:"index"` // nil keeps the artifact until it is deleted
+	CreatedAt   time.Time
+}
+
+// Task is a unit of background work, such as generating the seats of a new event, run by the
+// task workers. Clients poll it for progress; failed attempts are retried with backoff.
+type Task struct {
+	ID          uint      `gorm:"primaryKey"`
+	TenantID    uint      `gorm:"not null;default:1;index"`
+	Kind        string    `gorm:"not null;size:50;index"`
+	Payload     string    `gorm:"type:text"`                                // JSON input for the kind's handler
+	Status      string    `gorm:"not null;size:20;default:'pending';index"` // pending, running, completed, failed
+	Progress    int       `gorm:"not null;default:0"`                       // units done, e.g. seats created
+	Total       int       `gorm:"not null;default:0"`
+	Error       string    `gorm:"type:text"` // last attempt's error
+	Attempts    int       `gorm:"not null;default:0"`
+	MaxAttempts int       `gorm:"not null;default:3"`
+	RunAt       time.Time `gorm:"not null;default:CURRENT_TIMESTAMP;index"` // when a pending task may next run
+	ResultID    *uint     // ID of the record the task created, e.g. the event
+	CreatedBy   uint      `gorm:"not null"`
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// DeadLetter is async work that failed, kept for platform admins to inspect and retry or
+// discard: notifications that couldn't be sent, admin webhook posts and booking workflow events
+// a consumer couldn't handle
+type DeadLetter struct {
+	ID            uint   `gorm:"primaryKey"`
+	Source        string `gorm:"not null;size:30;index"`                // notification, admin_notification or domain_event
+	Handler       string `gorm:"not null;size:100"`                     // what failed, e.g. the consumer of a domain event
+	Kind          string `gorm:"not null;size:50"`                      // e.g. the event name or notification type
+	Payload       string `gorm:"type:text"`                             // JSON of the work, replayed on retry
+	Error         string `gorm:"type:text"`                             // the latest failure
+	Status        string `gorm:"not null;size:20;default:'dead';index"` // dead, retried or discarded
+	Retries       int    `gorm:"not null;default:0"`                    // retries from the console, failed ones included
+	LastRetriedAt *time.Time
+	ResolvedAt    *time.Time // when it was retried successfully or discarded
+	CreatedAt     time.Time  `gorm:"index"`
+	UpdatedAt     time.Time
+}
+
+// PolicyAcceptance records a user accepting a version of the platform's terms of service or
+// privacy policy. Acceptances are never updated, so they form the user's history.
+type PolicyAcceptance struct {
+	ID         uint      `gorm:"primaryKey"`
+	UserID     uint      `gorm:"not null;index:idx_policy_acceptances_user_policy,priority:1"`
+	Policy     string    `gorm:"not null;size:30;index:idx_policy_acceptances_user_policy,priority:2"` // terms_of_service or privacy_policy
+	Version    string    `gorm:"not null;size:50;index:idx_policy_acceptances_user_policy,priority:3"`
+	IPAddress  string    `gorm:"size:45"` // of the request that accepted it
+	AcceptedAt time.Time `gorm:"not null"`
+}
+
+// RuntimeSetting overrides the configured default of a runtime setting
+type RuntimeSetting struct {
+	Key       string `gorm:"primaryKey;size:100"`
+	Value     string `gorm:"not null;size:100"` // normalized, e.g. 5m0s for durations
+	UpdatedBy uint   `gorm:"not null"`
+	UpdatedAt time.Time
+}
+
+// SettingChange audits a runtime setting being set or reset to its default
+type SettingChange struct {
+	ID        uint      `gorm:"primaryKey"`
+	Key       string    `gorm:"not null;size:100;index"`
+	OldValue  string    `gorm:"size:100"` // empty when the default applied before
+	NewValue  string    `gorm:"size:100"` // empty when reset to the default
+	ChangedBy uint      `gorm:"not null"`
+	ChangedAt time.Time `gorm:"not null;index"`
+}
+
+// CatalogSyncRun records one sync of a partner's catalog feed
+type CatalogSyncRun struct {
+	ID              uint               `gorm:"primaryKey"`
+	TenantID        uint               `gorm:"not null;default:1;index"` // the tenant the catalog is synced into
+	Source          string             `gorm:"not null;size:50;index"`
+	Status          string             `gorm:"not null;size:20"` // succeeded or failed
+	VenuesCreated   int                `gorm:"not null;default:0"`
+	VenuesUpdated   int                `gorm:"not null;default:0"`
+	VenuesUnchanged int                `gorm:"not null;default:0"`
+	EventsCreated   int                `gorm:"not null;default:0"`
+	EventsUpdated   int                `gorm:"not null;default:0"`
+	EventsUnchanged int                `gorm:"not null;default:0"`
+	EventsSkipped   int                `gorm:"not null;default:0"`         // already started, so left as they are
+	Rejected        []CatalogSyncError `gorm:"type:jsonb;serializer:json"` // invalid feed entries, left out of the sync
+	Error           string             `gorm:"type:text"`                  // why the sync failed
+	StartedAt       time.Time          `gorm:"not null;index"`
+	FinishedAt      time.Time          `gorm:"not null"`
+}
@@ -0,0 +1,14 @@
+package entities
+
+// OrganizerRatings aggregates the reviews attendees left for an organizer's events
+type OrganizerRatings struct {
+	Average float64 // rounded to one decimal, 0 without reviews
+	Count   int64
+}
+
+// OrganizerPage is what an organizer's public landing page shows
+type OrganizerPage struct {
+	Organizer      *Tenant
+	UpcomingEvents []Event // with their venues, soonest first
+	Ratings        OrganizerRatings
+}
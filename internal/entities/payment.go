@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PaymentDetails describes the payment that settled a booking intent, as reported by the client
+type PaymentDetails struct {
+	PaymentID  string
+	Provider   string
+	Currency   string
+	MethodType string
+	CardBrand  string
+	CardLast4  string
+	// RedeemPoints is the number of loyalty points to apply as a discount
+	RedeemPoints int
+}
+
+// MaskedMethod renders the payment method for display, e.g. "visa •••• 4242"
+func (p PaymentDetails) MaskedMethod() string {
+	if p.CardLast4 == "" {
+		return strings.ToLower(p.CardBrand)
+	}
+	brand := strings.ToLower(p.CardBrand)
+	if brand == "" {
+		brand = "card"
+	}
+	return fmt.Sprintf("%s •••• %s", brand, p.CardLast4)
+}
+
+// PaymentTransactionFilter narrows the admin reconciliation lookup
+type PaymentTransactionFilter struct {
+	Provider          string
+	ProviderReference string
+	Status            string
+	BookingID         uint
+	From              *time.Time
+	To                *time.Time
+}
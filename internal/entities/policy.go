@@ -0,0 +1,9 @@
+package entities
+
+// PolicyConsent is a user agreeing to the versions of the terms of service and privacy policy
+// they were shown
+type PolicyConsent struct {
+	Accept         bool
+	TermsVersion   string
+	PrivacyVersion string
+}
@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// PresaleBatchSummary is a presale batch with usage totals across its codes
+type PresaleBatchSummary struct {
+	ID            uint
+	EventID       uint
+	Name          string
+	MaxUses       int
+	CreatedBy     uint
+	CreatedAt     time.Time
+	Codes         int
+	RedeemedCodes int
+	Uses          int
+}
@@ -0,0 +1,36 @@
+package entities
+
+import "api/pkg/money"
+
+// ReferrerStats totals the bookings made with a referrer's code and the commission on them
+type ReferrerStats struct {
+	ReferrerID        uint
+	Bookings          int64       // still confirmed
+	Cancelled         int64       // cancelled or refunded, earning nothing
+	Revenue           money.Money // paid for the confirmed bookings
+	CommissionPending money.Money // on bookings of events that haven't ended
+	CommissionPayable money.Money // on bookings of ended events, not paid out yet
+	CommissionPaid    money.Money
+}
+
+// ReferrerSummary is a referrer with the totals of their code
+type ReferrerSummary struct {
+	Referrer Referrer
+	Stats    ReferrerStats
+}
+
+// ReferrerDashboard is what a user sees of the referral codes they manage
+type ReferrerDashboard struct {
+	Referrers   []ReferrerSummary
+	Commissions []ReferralCommission // latest first, with their event
+}
+
+// ReferrerPayout is the commission owed to, or just paid out to, a referrer
+type ReferrerPayout struct {
+	ReferrerID  uint
+	Code        string
+	Name        string
+	Email       string
+	Commissions int64
+	Amount      money.Money
+}
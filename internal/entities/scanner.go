@@ -0,0 +1,41 @@
+package entities
+
+import "time"
+
+// GateStats is the entry throughput of an event's gates during doors-open, from the check-ins
+// made by its scanner devices
+type GateStats struct {
+	EventID     uint      `json:"event_id"`
+	GeneratedAt time.Time `json:"generated_at"`
+	// Rates are over the last WindowMinutes minutes, the current one included
+	WindowMinutes int                  `json:"window_minutes"`
+	Gates         []GateThroughput     `json:"gates"`
+	Devices       []ScannerDeviceStats `json:"devices"`
+}
+
+// GateThroughput is how fast attendees enter through one gate
+type GateThroughput struct {
+	Gate              string       `json:"gate"`
+	Devices           int          `json:"devices"`          // active scanner devices at the gate
+	CheckIns          int64        `json:"check_ins"`        // since doors opened
+	WindowCheckIns    int64        `json:"window_check_ins"` // during the window
+	CheckInsPerMinute float64      `json:"check_ins_per_minute"`
+	Minutes           []GateMinute `json:"minutes"` // oldest first, minutes without check-ins included
+}
+
+// GateMinute counts one gate's check-ins during one minute
+type GateMinute struct {
+	Minute   time.Time `json:"minute"`
+	CheckIns int64     `json:"check_ins"`
+}
+
+// ScannerDeviceStats is how many attendees one scanner device checked in
+type ScannerDeviceStats struct {
+	DeviceID      uint       `json:"device_id"`
+	Name          string     `json:"name"`
+	Gate          string     `json:"gate"`
+	Revoked       bool       `json:"revoked"`
+	CheckIns      int64      `json:"check_ins"`
+	LastCheckInAt *time.Time `json:"last_check_in_at,omitempty"`
+	LastSeenAt    *time.Time `json:"last_seen_at,omitempty"`
+}
@@ -0,0 +1,34 @@
+package entities
+
+import "time"
+
+// SeatFilter narrows seat listings; nil fields are not filtered on
+type SeatFilter struct {
+	Accessible *bool
+	Companion  *bool
+}
+
+// ExternalSeatUpdate is a seat hold or release from a venue's external inventory system. The
+// seat is given by ID, or by row and column.
+type ExternalSeatUpdate struct {
+	EventID    uint
+	SeatID     uint
+	Row        int
+	Column     int
+	Hold       bool // false releases the hold
+	HoldRef    string
+	OccurredAt time.Time
+	// LockedSeatIDs are held in Redis by online checkouts
+	LockedSeatIDs []uint
+}
+
+// SeatBitmap is an event's seat availability as one bit per seat, set for seats on sale. Seat
+// (row, column) is bit (row-1)*Columns + column-1, counting from the most significant bit of
+// the first byte. Version grows with every change.
+type SeatBitmap struct {
+	EventID uint
+	Rows    int
+	Columns int
+	Version int64
+	Bits    []byte
+}
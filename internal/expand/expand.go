@@ -0,0 +1,76 @@
+// Package expand carries the relations a request wants loaded, so repositories can skip
+// preloading the others.
+package expand
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Relations of events, venues and bookings that can be left out, named as in responses
+const (
+	Venue      = "venue"
+	Organizer  = "organizer"
+	Seats      = "seats"
+	Events     = "events"
+	Event      = "event"
+	Seat       = "seat"
+	EventVenue = "event.venue"
+	// Posts organizers attach to events
+	Announcements = "announcements"
+	FAQs          = "faqs"
+)
+
+// Set is the relations to load
+type Set map[string]bool
+
+// Parse parses an expand query parameter such as "venue,seats" against the relations an
+// endpoint has, rejecting others. A nil raw parameter wasn't given and loads every relation,
+// returning a nil set; an empty one loads none.
+func Parse(raw *string, relations ...string) (Set, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	set := Set{}
+	for _, name := range strings.Split(*raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		known := false
+		for _, relation := range relations {
+			known = known || relation == name
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown relation %q, expected one of %s", name, strings.Join(relations, ", "))
+		}
+		set[name] = true
+	}
+	return set, nil
+}
+
+// Has reports whether the relation is loaded; a nil set loads every relation
+func (s Set) Has(relation string) bool {
+	return s == nil || s[relation]
+}
+
+type contextKey struct{}
+
+// With loads only the relations of set for everything done with ctx. A nil set loads all.
+func With(ctx context.Context, set Set) context.Context {
+	return context.WithValue(ctx, contextKey{}, set)
+}
+
+// FromContext returns the relations ctx loads, and false if it loads every relation
+func FromContext(ctx context.Context) (Set, bool) {
+	set, _ := ctx.Value(contextKey{}).(Set)
+	return set, set != nil
+}
+
+// Includes reports whether the relation is loaded for ctx
+func Includes(ctx context.Context, relation string) bool {
+	set, _ := FromContext(ctx)
+	return set.Has(relation)
+}
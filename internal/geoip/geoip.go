@@ -0,0 +1,63 @@
+package geoip
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Providers
+const (
+	ProviderNone   = "none"
+	ProviderHeader = "header"
+	ProviderHTTP   = "http"
+)
+
+// Locator finds the country a request comes from
+type Locator interface {
+	// Country returns the ISO 3166-1 alpha-2 code of the client at ip, or "" if it can't be told
+	Country(ctx context.Context, r *http.Request, ip string) (string, error)
+}
+
+// Config selects and configures a GeoIP provider
+type Config struct {
+	Provider string
+
+	// Header provider: the country header set by a CDN or load balancer in front of the API
+	Header string
+
+	// HTTP provider: a lookup service answering with the plain country code, {ip} in the URL
+	// is replaced with the client's address, e.g. https://ipapi.co/{ip}/country/
+	URL      string
+	Timeout  time.Duration
+	CacheTTL time.Duration
+}
+
+// New returns the provider selected by the config, or nil when lookups are disabled
+func New(cfg Config) (Locator, error) {
+	switch cfg.Provider {
+	case "", ProviderNone:
+		return nil, nil
+	case ProviderHeader:
+		return NewHeaderLocator(cfg.Header)
+	case ProviderHTTP:
+		return NewHTTPLocator(cfg.URL, cfg.Timeout, cfg.CacheTTL)
+	default:
+		return nil, fmt.Errorf("unknown GeoIP provider %q", cfg.Provider)
+	}
+}
+
+// NormalizeCountry returns code as an upper case ISO 3166-1 alpha-2 code, or "" if it isn't one.
+// The codes CDNs use for unknown origins and Tor, XX and T1, count as unknown.
+func NormalizeCountry(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+		return ""
+	}
+	if code == "XX" || code == "T1" {
+		return ""
+	}
+	return code
+}
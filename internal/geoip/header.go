@@ -0,0 +1,27 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// HeaderLocator trusts the country header of a CDN or load balancer in front of the API, such
+// as Cloudflare's CF-IPCountry. Only use it when clients can't reach the API directly.
+type HeaderLocator struct {
+	header string
+}
+
+// Ensure HeaderLocator implements Locator
+var _ Locator = (*HeaderLocator)(nil)
+
+func NewHeaderLocator(header string) (*HeaderLocator, error) {
+	if header == "" {
+		return nil, errors.New("the header GeoIP provider needs a header name")
+	}
+	return &HeaderLocator{header: header}, nil
+}
+
+func (l *HeaderLocator) Country(ctx context.Context, r *http.Request, ip string) (string, error) {
+	return NormalizeCountry(r.Header.Get(l.header)), nil
+}
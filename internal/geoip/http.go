@@ -0,0 +1,101 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCachedLookups bounds the HTTP locator's cache; it is cleared when full
+const maxCachedLookups = 100000
+
+// HTTPLocator looks addresses up with an external service that answers with the plain
+// country code. Answers are cached, so a busy client costs one lookup per cache TTL.
+type HTTPLocator struct {
+	url      string
+	cacheTTL time.Duration
+	client   *http.Client
+
+	mu    sync.Mutex
+	cache map[string]cachedCountry
+}
+
+type cachedCountry struct {
+	country   string
+	expiresAt time.Time
+}
+
+// Ensure HTTPLocator implements Locator
+var _ Locator = (*HTTPLocator)(nil)
+
+func NewHTTPLocator(urlTemplate string, timeout, cacheTTL time.Duration) (*HTTPLocator, error) {
+	if !strings.Contains(urlTemplate, "{ip}") {
+		return nil, errors.New("the http GeoIP provider needs a URL containing {ip}")
+	}
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &HTTPLocator{
+		url:      urlTemplate,
+		cacheTTL: cacheTTL,
+		client:   &http.Client{Timeout: timeout},
+		cache:    make(map[string]cachedCountry),
+	}, nil
+}
+
+func (l *HTTPLocator) Country(ctx context.Context, r *http.Request, ip string) (string, error) {
+	addr := net.ParseIP(ip)
+	// Private and loopback addresses have no country
+	if addr == nil || addr.IsPrivate() || addr.IsLoopback() || addr.IsUnspecified() {
+		return "", nil
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	cached, ok := l.cache[ip]
+	l.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.country, nil
+	}
+
+	country, err := l.lookup(ctx, ip)
+	if err != nil {
+		return "", err
+	}
+	if l.cacheTTL > 0 {
+		l.mu.Lock()
+		if len(l.cache) >= maxCachedLookups {
+			l.cache = make(map[string]cachedCountry)
+		}
+		l.cache[ip] = cachedCountry{country: country, expiresAt: now.Add(l.cacheTTL)}
+		l.mu.Unlock()
+	}
+	return country, nil
+}
+
+func (l *HTTPLocator) lookup(ctx context.Context, ip string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.ReplaceAll(l.url, "{ip}", url.PathEscape(ip)), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("GeoIP lookup failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GeoIP lookup failed: %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64))
+	if err != nil {
+		return "", fmt.Errorf("GeoIP lookup failed: %w", err)
+	}
+	return NormalizeCountry(string(body)), nil
+}
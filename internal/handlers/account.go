@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccountHandler struct {
+	accountService services.AccountServiceInterface
+}
+
+func NewAccountHandler(accountService services.AccountServiceInterface) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+// DeleteAccount schedules the caller's account for deletion. The account is blocked at once
+// and a restore link is sent, valid until the account is anonymized.
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	user, err := h.accountService.DeleteAccount(requestContext(c), userID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, "account scheduled for deletion", response.AccountDeletionResponse{
+		DeletionScheduledAt: *user.DeletionScheduledAt,
+	})
+}
+
+// RestoreAccount cancels an account deletion with the token of its restore link
+func (h *AccountHandler) RestoreAccount(c *gin.Context) {
+	var req request.RestoreAccountRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	user, err := h.accountService.RestoreAccount(requestContext(c), req.Token)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "account restored, log in to continue", response.UserResponse{
+		ID:        user.ID,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Phone:     user.Phone,
+		IsAdmin:   user.IsAdmin,
+	})
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *AccountHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		case "CONFLICT":
+			response.Error(c, http.StatusConflict, appErr.Message)
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -1,9 +1,16 @@
 package handlers
 
 import (
+	"api/internal/entities"
+	"api/internal/repository"
 	"api/internal/services"
+	"api/internal/tenant"
+	"api/pkg/errors"
+	"api/pkg/request"
 	"api/pkg/response"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,13 +31,24 @@ func NewAnalyticsHandler(analyticsService services.AnalyticsServiceInterface) *A
 // @Tags Admin Analytics
 // @Security BearerAuth
 // @Produce json
+// @Param tenant_id query int false "Limit to one tenant (platform admins only; tenant admins always see their own)"
 // @Success 200 {object} entities.BookingAnalytics
 // @Failure 401 {object} response.ErrorResponse "Unauthorized"
 // @Failure 403 {object} response.ErrorResponse "Forbidden - Admin access required"
 // @Failure 500 {object} response.ErrorResponse "Internal server error"
 // @Router /admin/analytics/bookings [get]
 func (h *AnalyticsHandler) GetBookingAnalytics(c *gin.Context) {
-	analytics, err := h.analyticsService.GetBookingAnalytics()
+	ctx := requestContext(c)
+	if _, scoped := tenant.FromContext(ctx); !scoped && c.Query("tenant_id") != "" {
+		tenantID, err := strconv.ParseUint(c.Query("tenant_id"), 10, 32)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid tenant ID")
+			return
+		}
+		ctx = tenant.WithTenant(ctx, uint(tenantID))
+	}
+
+	analytics, err := h.analyticsService.GetBookingAnalytics(ctx)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "failed to retrieve booking analytics")
 		return
@@ -38,3 +56,295 @@ func (h *AnalyticsHandler) GetBookingAnalytics(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, "booking analytics retrieved successfully", analytics)
 }
+
+// defaultConversionEvents and maxConversionEvents bound the events a waitlist conversion report covers
+const (
+	defaultConversionEvents = 50
+	maxConversionEvents     = 200
+)
+
+// GetWaitlistConversion handles GET /admin/analytics/waitlist-conversion
+// @Summary Get waitlist to purchase conversion per event
+// @Description How many waitlisted users were offered a seat and bought one, how long they waited and how fast they bought, to help size future venues
+// @Tags Admin Analytics
+// @Security BearerAuth
+// @Produce json
+// @Param event_id query int false "Limit to one event"
+// @Param limit query int false "Events with the latest start times to cover (default 50, at most 200)"
+// @Param tenant_id query int false "Limit to one tenant (platform admins only; tenant admins always see their own)"
+// @Success 200 {array} entities.WaitlistConversion
+// @Failure 400 {object} response.ErrorResponse "Invalid event ID, limit or tenant ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin access required"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/analytics/waitlist-conversion [get]
+func (h *AnalyticsHandler) GetWaitlistConversion(c *gin.Context) {
+	ctx := requestContext(c)
+	if _, scoped := tenant.FromContext(ctx); !scoped && c.Query("tenant_id") != "" {
+		tenantID, err := strconv.ParseUint(c.Query("tenant_id"), 10, 32)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid tenant ID")
+			return
+		}
+		ctx = tenant.WithTenant(ctx, uint(tenantID))
+	}
+
+	var eventID uint64
+	if c.Query("event_id") != "" {
+		var err error
+		eventID, err = strconv.ParseUint(c.Query("event_id"), 10, 32)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid event ID")
+			return
+		}
+	}
+
+	limit := defaultConversionEvents
+	if c.Query("limit") != "" {
+		var err error
+		limit, err = strconv.Atoi(c.Query("limit"))
+		if err != nil || limit < 1 || limit > maxConversionEvents {
+			response.Error(c, http.StatusBadRequest, "limit must be between 1 and 200")
+			return
+		}
+	}
+
+	conversions, err := h.analyticsService.GetWaitlistConversion(ctx, uint(eventID), limit)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "failed to retrieve waitlist conversion")
+		return
+	}
+	if conversions == nil {
+		conversions = []entities.WaitlistConversion{}
+	}
+
+	response.Success(c, http.StatusOK, "waitlist conversion retrieved successfully", conversions)
+}
+
+// GetAcquisitionReport handles GET /admin/analytics/acquisition
+// @Summary Get bookings and revenue by acquisition channel
+// @Description Confirmed bookings, cancellations and revenue per utm_source, optionally broken down by medium and campaign or by referral code, with each channel's share
+// @Tags Admin Analytics
+// @Security BearerAuth
+// @Produce json
+// @Param event_id query int false "Limit to one event"
+// @Param from query string false "First booking date, YYYY-MM-DD"
+// @Param to query string false "Last booking date, YYYY-MM-DD"
+// @Param group_by query string false "channel (default), campaign or referral"
+// @Param limit query int false "Channels to list, highest revenue first (default 50, at most 200)"
+// @Param tenant_id query int false "Limit to one tenant (platform admins only; tenant admins always see their own)"
+// @Success 200 {object} entities.AcquisitionReport
+// @Failure 400 {object} response.ErrorResponse "Invalid filter or tenant ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin access required"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/analytics/acquisition [get]
+func (h *AnalyticsHandler) GetAcquisitionReport(c *gin.Context) {
+	ctx := requestContext(c)
+	if _, scoped := tenant.FromContext(ctx); !scoped && c.Query("tenant_id") != "" {
+		tenantID, err := strconv.ParseUint(c.Query("tenant_id"), 10, 32)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid tenant ID")
+			return
+		}
+		ctx = tenant.WithTenant(ctx, uint(tenantID))
+	}
+
+	var req request.AcquisitionFilterRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+
+	filter := entities.AcquisitionFilter{EventID: req.EventID, GroupBy: req.GroupBy, Limit: req.Limit}
+	if req.From != "" {
+		from, _ := time.Parse("2006-01-02", req.From)
+		filter.From = &from
+	}
+	if req.To != "" {
+		// Bookings made any time on the last day count
+		to, _ := time.Parse("2006-01-02", req.To)
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		response.Error(c, http.StatusBadRequest, "from must not be after to")
+		return
+	}
+
+	report, err := h.analyticsService.GetAcquisitionReport(ctx, filter)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "failed to retrieve acquisition report")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "acquisition report retrieved successfully", report)
+}
+
+// Months a venue report covers by default and at most
+const (
+	defaultReportMonths = 12
+	maxReportMonths     = 60
+)
+
+// reportMonths parses the months a venue report covers, responding 400 when they are invalid.
+// It returns the start of the first month and the end of the last.
+func reportMonths(c *gin.Context) (time.Time, time.Time, bool) {
+	var req request.VenueReportRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return time.Time{}, time.Time{}, false
+	}
+
+	now := time.Now().UTC()
+	last := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if req.To != "" {
+		last, _ = time.Parse("2006-01", req.To)
+	}
+	first := last.AddDate(0, 1-defaultReportMonths, 0)
+	if req.From != "" {
+		first, _ = time.Parse("2006-01", req.From)
+	}
+	// Events starting any time in the last month count
+	end := last.AddDate(0, 1, 0)
+	if !first.Before(end) {
+		response.Error(c, http.StatusBadRequest, "from must not be after to")
+		return time.Time{}, time.Time{}, false
+	}
+	if first.AddDate(0, maxReportMonths, 0).Before(end) {
+		response.Error(c, http.StatusBadRequest, "a report covers at most 60 months")
+		return time.Time{}, time.Time{}, false
+	}
+	return first, end, true
+}
+
+// GetVenueUtilization handles GET /admin/venues/:id/utilization
+// @Summary Get a venue's utilization per month
+// @Description Events, booked percentage and revenue per month of the events' start, to evaluate a venue's performance over time
+// @Tags Admin Analytics
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Param from query string false "First month, YYYY-MM (default 11 months before to)"
+// @Param to query string false "Last month, YYYY-MM (default the current month)"
+// @Success 200 {object} entities.VenueUtilization
+// @Failure 400 {object} response.ErrorResponse "Invalid venue ID or months"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin access required"
+// @Failure 404 {object} response.ErrorResponse "Venue not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/venues/{id}/utilization [get]
+func (h *AnalyticsHandler) GetVenueUtilization(c *gin.Context) {
+	venueID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid venue ID")
+		return
+	}
+
+	first, end, ok := reportMonths(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.analyticsService.GetVenueUtilization(requestContext(c), uint(venueID), first, end)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok && appErr.Type == "NOT_FOUND" {
+			response.Error(c, http.StatusNotFound, appErr.Message)
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "failed to retrieve venue utilization")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "venue utilization retrieved successfully", report)
+}
+
+// GetVenueHeatmap handles GET /admin/venues/:id/heatmap
+// @Summary Get a venue's seat heatmap
+// @Description Sell-through rate and average price per seat position across the venue's past events, to guide pricing tier design
+// @Tags Admin Analytics
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Param from query string false "First month of event starts, YYYY-MM (default 11 months before to)"
+// @Param to query string false "Last month of event starts, YYYY-MM (default the current month)"
+// @Success 200 {object} entities.SeatHeatmap
+// @Failure 400 {object} response.ErrorResponse "Invalid venue ID or months"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin access required"
+// @Failure 404 {object} response.ErrorResponse "Venue not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/venues/{id}/heatmap [get]
+func (h *AnalyticsHandler) GetVenueHeatmap(c *gin.Context) {
+	venueID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid venue ID")
+		return
+	}
+
+	first, end, ok := reportMonths(c)
+	if !ok {
+		return
+	}
+
+	heatmap, err := h.analyticsService.GetVenueHeatmap(requestContext(c), uint(venueID), first, end)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok && appErr.Type == "NOT_FOUND" {
+			response.Error(c, http.StatusNotFound, appErr.Message)
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "failed to retrieve seat heatmap")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "seat heatmap retrieved successfully", heatmap)
+}
+
+// defaultLiveWindow is the live dashboard window in minutes when none is requested
+const defaultLiveWindow = 5
+
+// GetLiveEventStats handles GET /admin/events/:id/live
+// @Summary Get real-time on-sale counters for an event
+// @Description Locks held, pending intents, waitlist length and per-minute intent, confirmation, rejection and error counts, for monitoring big on-sales
+// @Tags Admin Analytics
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param window query int false "Minutes to compute rates over (default 5, at most 60)"
+// @Success 200 {object} entities.LiveEventStats
+// @Failure 400 {object} response.ErrorResponse "Invalid event ID or window"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden - Admin access required"
+// @Failure 404 {object} response.ErrorResponse "Event not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /admin/events/{id}/live [get]
+func (h *AnalyticsHandler) GetLiveEventStats(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	window := defaultLiveWindow
+	if c.Query("window") != "" {
+		window, err = strconv.Atoi(c.Query("window"))
+		if err != nil || window < 1 || window > repository.MaxLiveWindow {
+			response.Error(c, http.StatusBadRequest, "window must be between 1 and 60 minutes")
+			return
+		}
+	}
+
+	stats, err := h.analyticsService.GetLiveEventStats(requestContext(c), uint(eventID), window)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok && appErr.Type == "NOT_FOUND" {
+			response.Error(c, http.StatusNotFound, appErr.Message)
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "failed to retrieve live event stats")
+		return
+	}
+
+	// Dashboards poll this; never serve it from a cache
+	c.Header("Cache-Control", "no-store")
+	response.Success(c, http.StatusOK, "live event stats retrieved successfully", stats)
+}
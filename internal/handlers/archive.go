@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ArchiveHandler struct {
+	archiveService services.ArchiveServiceInterface
+}
+
+func NewArchiveHandler(archiveService services.ArchiveServiceInterface) *ArchiveHandler {
+	return &ArchiveHandler{
+		archiveService: archiveService,
+	}
+}
+
+// ArchiveBookings starts moving bookings of long-completed events to the archive tables
+// (platform admin only). The body is optional. It responds 202 with a task to poll at
+// GET /admin/tasks/:id.
+func (h *ArchiveHandler) ArchiveBookings(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req request.ArchiveBookingsRequest
+	if c.Request.ContentLength != 0 {
+		if err := request.BindJSON(c, &req); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+			return
+		}
+	}
+
+	task, err := h.archiveService.StartArchival(requestContext(c), req.OlderThanMonths, adminID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, "archival started", map[string]uint{"task_id": task.ID})
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *ArchiveHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "INTERNAL_ERROR":
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"api/internal/storage"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ArtifactHandler struct {
+	artifactService services.ArtifactServiceInterface
+	storage         storage.Storage
+}
+
+func NewArtifactHandler(artifactService services.ArtifactServiceInterface, store storage.Storage) *ArtifactHandler {
+	return &ArtifactHandler{
+		artifactService: artifactService,
+		storage:         store,
+	}
+}
+
+// ListArtifacts returns stored artifacts, optionally filtered by kind (admin only)
+func (h *ArtifactHandler) ListArtifacts(c *gin.Context) {
+	var req request.ArtifactFilterRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+
+	offset := req.Offset()
+	artifacts, total, err := h.artifactService.ListArtifacts(context.Background(), req.Kind, req.Limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	artifactResponses := make([]response.ArtifactResponse, len(artifacts))
+	for i := range artifacts {
+		artifactResponses[i] = toArtifactResponse(&artifacts[i])
+	}
+
+	response.Paginated(c, http.StatusOK, artifactResponses, req.Page, req.Limit, total)
+}
+
+// GetDownloadURL returns a short-lived pre-signed download URL for an artifact (admin only)
+func (h *ArtifactHandler) GetDownloadURL(c *gin.Context) {
+	artifactID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid artifact ID")
+		return
+	}
+
+	url, expiresAt, err := h.artifactService.DownloadURL(context.Background(), uint(artifactID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, response.ArtifactDownloadResponse{
+		URL:       url,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// DeleteArtifact removes an artifact from storage (admin only)
+func (h *ArtifactHandler) DeleteArtifact(c *gin.Context) {
+	artifactID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid artifact ID")
+		return
+	}
+
+	if err := h.artifactService.DeleteArtifact(context.Background(), uint(artifactID)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "artifact deleted", nil)
+}
+
+// Download serves a file from local storage through a signed download link. Remote
+// backends hand out pre-signed URLs to the bucket instead, so this route 404s for them.
+func (h *ArtifactHandler) Download(c *gin.Context) {
+	local, ok := h.storage.(*storage.LocalStorage)
+	if !ok {
+		response.Error(c, http.StatusNotFound, "file not found")
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	file, err := local.Open(key, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		if stderrors.Is(err, os.ErrNotExist) {
+			response.Error(c, http.StatusNotFound, "file not found")
+			return
+		}
+		response.Error(c, http.StatusForbidden, err.Error())
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(key)))
+	http.ServeContent(c.Writer, c.Request, path.Base(key), info.ModTime(), file)
+}
+
+func toArtifactResponse(artifact *entities.Artifact) response.ArtifactResponse {
+	return response.ArtifactResponse{
+		ID:          artifact.ID,
+		Kind:        artifact.Kind,
+		FileName:    artifact.FileName,
+		ContentType: artifact.ContentType,
+		Size:        artifact.Size,
+		ExpiresAt:   artifact.ExpiresAt,
+		CreatedAt:   artifact.CreatedAt,
+	}
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *ArtifactHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "UNAUTHORIZED":
+			response.Error(c, http.StatusUnauthorized, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		case "CONFLICT":
+			response.Error(c, http.StatusConflict, appErr.Message)
+		case "INTERNAL_ERROR":
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
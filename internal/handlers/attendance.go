@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttendanceHandler struct {
+	attendanceService services.AttendanceServiceInterface
+}
+
+func NewAttendanceHandler(attendanceService services.AttendanceServiceInterface) *AttendanceHandler {
+	return &AttendanceHandler{
+		attendanceService: attendanceService,
+	}
+}
+
+// CheckIn records that the holder of a booking has arrived at the event (admin only)
+func (h *AttendanceHandler) CheckIn(c *gin.Context) {
+	bookingIDStr := c.Param("id")
+	bookingID, err := strconv.ParseUint(bookingIDStr, 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid booking ID")
+		return
+	}
+
+	booking, err := h.attendanceService.CheckIn(requestContext(c), uint(bookingID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "booking checked in successfully", gin.H{
+		"booking_id":    booking.ID,
+		"event_id":      booking.EventID,
+		"seat_id":       booking.SeatID,
+		"row":           booking.Seat.Row,
+		"column":        booking.Seat.Column,
+		"checked_in_at": booking.CheckedInAt,
+	})
+}
+
+// SubmitReview rates an event the user checked in to, from 1 to 5 stars
+func (h *AttendanceHandler) SubmitReview(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid booking ID")
+		return
+	}
+
+	var req request.SubmitReviewRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	review, err := h.attendanceService.SubmitReview(context.Background(), userID.(uint), uint(bookingID), req.Rating, req.Comment)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "review saved successfully", response.EventReviewResponse{
+		ID:        review.ID,
+		BookingID: review.BookingID,
+		EventID:   review.EventID,
+		Rating:    review.Rating,
+		Comment:   review.Comment,
+		UpdatedAt: review.UpdatedAt,
+	})
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *AttendanceHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "UNAUTHORIZED":
+			response.Error(c, http.StatusUnauthorized, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		case "CONFLICT":
+			response.Error(c, http.StatusConflict, appErr.Message)
+		case "INTERNAL_ERROR":
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"api/internal/services"
+	"api/pkg/request"
+	"api/pkg/response"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AutocompleteHandler struct {
+	autocompleteService *services.AutocompleteService
+}
+
+func NewAutocompleteHandler(autocompleteService *services.AutocompleteService) *AutocompleteHandler {
+	return &AutocompleteHandler{
+		autocompleteService: autocompleteService,
+	}
+}
+
+// SuggestVenues returns venues whose name matches what the user typed in ?q=, names starting
+// with it first. Queries shorter than two characters get no suggestions.
+func (h *AutocompleteHandler) SuggestVenues(c *gin.Context) {
+	var req request.AutocompleteRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+
+	venues, err := h.autocompleteService.SuggestVenues(context.Background(), req.Q, req.Limit)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	suggestions := make([]response.VenueSuggestionResponse, len(venues))
+	for i, venue := range venues {
+		suggestions[i] = response.VenueSuggestionResponse{
+			ID:      venue.ID,
+			Name:    venue.Name,
+			City:    venue.City,
+			State:   venue.State,
+			Country: venue.Country,
+		}
+	}
+
+	response.JSON(c, http.StatusOK, suggestions)
+}
+
+// SuggestCities returns cities with venues matching what the user typed in ?q=, cities
+// starting with it first, then those with the most venues
+func (h *AutocompleteHandler) SuggestCities(c *gin.Context) {
+	var req request.AutocompleteRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+
+	cities, err := h.autocompleteService.SuggestCities(context.Background(), req.Q, req.Limit)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	suggestions := make([]response.CitySuggestionResponse, len(cities))
+	for i, city := range cities {
+		suggestions[i] = response.CitySuggestionResponse{
+			City:    city.City,
+			State:   city.State,
+			Country: city.Country,
+			Venues:  city.Venues,
+		}
+	}
+
+	response.JSON(c, http.StatusOK, suggestions)
+}
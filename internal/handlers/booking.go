@@ -1,19 +1,25 @@
 package handlers
 
 import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/expand"
 	"api/internal/services"
 	"api/pkg/errors"
 	"api/pkg/request"
 	"api/pkg/response"
 	"context"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type BookingHandler struct {
 	bookingService services.BookingServiceInterface
+	tickets        *services.TicketService
 }
 
 func NewBookingHandler(bookingService services.BookingServiceInterface) *BookingHandler {
@@ -22,6 +28,12 @@ func NewBookingHandler(bookingService services.BookingServiceInterface) *Booking
 	}
 }
 
+// WithTickets adds the signed QR token to printed tickets
+func (h *BookingHandler) WithTickets(tickets *services.TicketService) *BookingHandler {
+	h.tickets = tickets
+	return h
+}
+
 // CreateBookingIntent creates a booking intent and locks the seat
 func (h *BookingHandler) CreateBookingIntent(c *gin.Context) {
 	userID, exists := c.Get("user_id")
@@ -36,13 +48,36 @@ func (h *BookingHandler) CreateBookingIntent(c *gin.Context) {
 		return
 	}
 
-	intent, err := h.bookingService.CreateBookingIntent(context.Background(), userID.(uint), req.SeatID)
+	attendee, err := attendeeDetails(req.Attendee)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	intent, err := h.bookingService.CreateBookingIntent(context.Background(), userID.(uint), req.SeatID, entities.BookingIntentOptions{
+		PresaleCode:  req.PresaleCode,
+		AcceptTerms:  req.AcceptTerms,
+		TermsVersion: req.TermsVersion,
+		Country:      c.GetString("country"),
+		Attribution: entities.Attribution{
+			UTMSource:    req.UTMSource,
+			UTMMedium:    req.UTMMedium,
+			UTMCampaign:  req.UTMCampaign,
+			ReferralCode: req.ReferralCode,
+		},
+		Attendee: attendee,
+	})
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	intentResp := response.BookingIntentResponse{
+	response.Success(c, http.StatusCreated, "booking intent created successfully", newBookingIntentResponse(intent))
+}
+
+// newBookingIntentResponse renders an intent loaded with its event, venue and seat
+func newBookingIntentResponse(intent *entities.BookingIntent) response.BookingIntentResponse {
+	resp := response.BookingIntentResponse{
 		ID: intent.ID,
 		Event: response.EventResponse{
 			ID:          intent.Event.ID,
@@ -78,10 +113,32 @@ func (h *BookingHandler) CreateBookingIntent(c *gin.Context) {
 			IsAvailable: intent.Seat.IsAvailable,
 			IsLocked:    intent.Seat.IsLocked,
 		},
-		Status: intent.Status,
+		Status:    intent.Status,
+		ExpiresAt: intent.LockExpiresAt,
 	}
+	if intent.Booking != nil {
+		booking := newBookingResponse(intent.Booking)
+		resp.Booking = &booking
+	}
+	return resp
+}
 
-	response.Success(c, http.StatusCreated, "booking intent created successfully", intentResp)
+// attendeeDetails reads the attendee details of a booking request, if any
+func attendeeDetails(req *request.AttendeeRequest) (entities.AttendeeDetails, error) {
+	var attendee entities.AttendeeDetails
+	if req == nil {
+		return attendee, nil
+	}
+	attendee.FullName = req.FullName
+	attendee.IDNumber = req.IDNumber
+	if req.DateOfBirth != "" {
+		dateOfBirth, err := time.Parse("2006-01-02", req.DateOfBirth)
+		if err != nil {
+			return attendee, fmt.Errorf("invalid date_of_birth, expected YYYY-MM-DD")
+		}
+		attendee.DateOfBirth = &dateOfBirth
+	}
+	return attendee, nil
 }
 
 // ConfirmBooking confirms a booking intent after successful payment
@@ -98,7 +155,26 @@ func (h *BookingHandler) ConfirmBooking(c *gin.Context) {
 		return
 	}
 
-	booking, err := h.bookingService.ConfirmBooking(context.Background(), req.BookingIntentID, req.PaymentID)
+	payment := entities.PaymentDetails{
+		PaymentID:    req.PaymentID,
+		Provider:     req.Provider,
+		Currency:     req.Currency,
+		RedeemPoints: req.RedeemPoints,
+	}
+	if req.PaymentMethod != nil {
+		payment.MethodType = req.PaymentMethod.Type
+		payment.CardBrand = req.PaymentMethod.CardBrand
+		payment.CardLast4 = req.PaymentMethod.CardLast4
+	}
+
+	attendee, err := attendeeDetails(req.Attendee)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	options := entities.ConfirmOptions{ReleaseOtherIntents: req.ReleaseOtherIntents}
+	booking, err := h.bookingService.ConfirmBooking(context.Background(), req.BookingIntentID, payment, attendee, options)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -110,7 +186,12 @@ func (h *BookingHandler) ConfirmBooking(c *gin.Context) {
 		return
 	}
 
-	bookingResp := response.BookingResponse{
+	response.Success(c, http.StatusOK, "booking confirmed successfully", newBookingResponse(booking))
+}
+
+// newBookingResponse renders a booking loaded with its event, venue and seat
+func newBookingResponse(booking *entities.Booking) response.BookingResponse {
+	return response.BookingResponse{
 		ID: booking.ID,
 		Event: response.EventResponse{
 			ID:          booking.Event.ID,
@@ -146,14 +227,21 @@ func (h *BookingHandler) ConfirmBooking(c *gin.Context) {
 			IsAvailable: booking.Seat.IsAvailable,
 			IsLocked:    booking.Seat.IsLocked,
 		},
-		Status:        booking.Status,
-		PaymentStatus: booking.PaymentStatus,
-		TotalAmount:   booking.TotalAmount,
-		BookedAt:      booking.BookedAt,
-		CancelledAt:   booking.CancelledAt,
+		Status:          booking.Status,
+		PaymentStatus:   booking.PaymentStatus,
+		TotalAmount:     booking.TotalAmount,
+		BookedAt:        booking.BookedAt,
+		CancelledAt:     booking.CancelledAt,
+		CheckedInAt:     booking.CheckedInAt,
+		NoShow:          booking.NoShow,
+		DiscountAmount:  booking.DiscountAmount,
+		PointsRedeemed:  booking.PointsRedeemed,
+		PointsEarned:    booking.PointsEarned,
+		TermsVersion:    booking.TermsVersion,
+		TermsAcceptedAt: booking.TermsAcceptedAt,
+		SeatPrice:       booking.SeatPrice,
+		PricingRuleID:   booking.PricingRuleID,
 	}
-
-	response.Success(c, http.StatusOK, "booking confirmed successfully", bookingResp)
 }
 
 // CancelBookingIntent cancels a booking intent and unlocks the seat
@@ -178,6 +266,159 @@ func (h *BookingHandler) CancelBookingIntent(c *gin.Context) {
 	response.Success(c, http.StatusOK, "booking intent cancelled successfully", nil)
 }
 
+// HeartbeatBookingIntent is pinged by the checkout page to keep the seat lock alive
+func (h *BookingHandler) HeartbeatBookingIntent(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	intentIDStr := c.Param("id")
+	intentID, err := strconv.ParseUint(intentIDStr, 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid booking intent ID")
+		return
+	}
+
+	intent, err := h.bookingService.HeartbeatBookingIntent(context.Background(), uint(intentID), userID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "heartbeat recorded", response.HeartbeatResponse{
+		BookingIntentID:  intent.ID,
+		Status:           intent.Status,
+		ExpiresAt:        intent.LockExpiresAt,
+		HeartbeatTimeout: constants.IntentHeartbeatTimeout,
+	})
+}
+
+// RetryPayment starts a fresh payment attempt for a pending intent while keeping its seat lock
+func (h *BookingHandler) RetryPayment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	intentIDStr := c.Param("id")
+	intentID, err := strconv.ParseUint(intentIDStr, 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid booking intent ID")
+		return
+	}
+
+	// The body is optional
+	var req request.RetryPaymentRequest
+	if c.Request.ContentLength > 0 {
+		if err := request.BindJSON(c, &req); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+			return
+		}
+	}
+
+	intent, err := h.bookingService.RetryPayment(context.Background(), uint(intentID), userID.(uint), req.FailureReason)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	attempts := make([]response.PaymentAttemptResponse, len(intent.PaymentAttempts))
+	for i, attempt := range intent.PaymentAttempts {
+		attempts[i] = response.PaymentAttemptResponse{
+			AttemptNumber: attempt.AttemptNumber,
+			Reference:     attempt.Reference,
+			Status:        attempt.Status,
+			FailureReason: attempt.FailureReason,
+			CreatedAt:     attempt.CreatedAt,
+		}
+	}
+
+	response.Success(c, http.StatusOK, "payment retry started", response.PaymentRetryResponse{
+		BookingIntentID:  intent.ID,
+		PaymentReference: intent.PaymentIntentID,
+		ExpiresAt:        intent.LockExpiresAt,
+		Attempts:         attempts,
+	})
+}
+
+// IssueResumeToken returns a short-lived token for continuing a pending checkout on another device
+func (h *BookingHandler) IssueResumeToken(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	intentIDStr := c.Param("id")
+	intentID, err := strconv.ParseUint(intentIDStr, 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid booking intent ID")
+		return
+	}
+
+	token, expiresAt, err := h.bookingService.IssueResumeToken(context.Background(), uint(intentID), userID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "resume token issued", response.ResumeTokenResponse{
+		BookingIntentID: uint(intentID),
+		ResumeToken:     token,
+		ExpiresAt:       expiresAt,
+	})
+}
+
+// ResumeBookingIntent continues a checkout started on another device, keeping its seat hold
+func (h *BookingHandler) ResumeBookingIntent(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req request.ResumeBookingIntentRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	intent, err := h.bookingService.ResumeBookingIntent(context.Background(), userID.(uint), req.ResumeToken)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "booking intent resumed", newBookingIntentResponse(intent))
+}
+
+// GetBookingIntentStatus returns the intent state and remaining lock time for checkout countdowns
+func (h *BookingHandler) GetBookingIntentStatus(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	intentIDStr := c.Param("id")
+	intentID, err := strconv.ParseUint(intentIDStr, 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid booking intent ID")
+		return
+	}
+
+	status, err := h.bookingService.GetBookingIntentStatus(context.Background(), uint(intentID), userID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, status)
+}
+
 // CancelBooking cancels a confirmed booking
 func (h *BookingHandler) CancelBooking(c *gin.Context) {
 	userID, exists := c.Get("user_id")
@@ -209,14 +450,34 @@ func (h *BookingHandler) GetUserBookings(c *gin.Context) {
 		return
 	}
 
-	var req request.PaginationRequest
+	var req request.BookingHistoryRequest
 	if err := request.BindQuery(c, &req); err != nil {
 		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
 		return
 	}
+	fields, err := response.ParseFields(req.Fields, response.BookingResponse{})
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+	relations, fields, err := loadRelations(req.FieldsRequest, fields, response.BookingResponse{}, expand.Event, expand.EventVenue, expand.Seat)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+	if !relations.Has(expand.EventVenue) {
+		fields = fields.Without(response.BookingResponse{}, "event.capacity")
+	}
 
-	offset := (req.Page - 1) * req.Limit
-	bookings, total, err := h.bookingService.GetUserBookings(context.Background(), userID.(uint), req.Limit, offset)
+	filter := entities.BookingFilter{
+		Status:  req.Status,
+		EventID: req.EventID,
+		Period:  req.Period,
+		Sort:    req.Sort,
+	}
+
+	offset := req.Offset()
+	bookings, total, err := h.bookingService.GetUserBookings(expand.With(context.Background(), relations), userID.(uint), filter, req.Limit, offset)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -261,15 +522,66 @@ func (h *BookingHandler) GetUserBookings(c *gin.Context) {
 				IsAvailable: booking.Seat.IsAvailable,
 				IsLocked:    booking.Seat.IsLocked,
 			},
-			Status:        booking.Status,
-			PaymentStatus: booking.PaymentStatus,
-			TotalAmount:   booking.TotalAmount,
-			BookedAt:      booking.BookedAt,
-			CancelledAt:   booking.CancelledAt,
+			Status:          booking.Status,
+			PaymentStatus:   booking.PaymentStatus,
+			TotalAmount:     booking.TotalAmount,
+			BookedAt:        booking.BookedAt,
+			CancelledAt:     booking.CancelledAt,
+			CheckedInAt:     booking.CheckedInAt,
+			NoShow:          booking.NoShow,
+			DiscountAmount:  booking.DiscountAmount,
+			PointsRedeemed:  booking.PointsRedeemed,
+			PointsEarned:    booking.PointsEarned,
+			TermsVersion:    booking.TermsVersion,
+			TermsAcceptedAt: booking.TermsAcceptedAt,
+			SeatPrice:       booking.SeatPrice,
+			PricingRuleID:   booking.PricingRuleID,
+		}
+	}
+
+	response.PaginatedFields(c, http.StatusOK, bookingResponses, fields, req.Page, req.Limit, total)
+}
+
+// GetUpcomingBookings returns the user's next confirmed bookings with a countdown to each
+// event and a link to its ticket, for app home screens
+func (h *BookingHandler) GetUpcomingBookings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req request.UpcomingBookingsRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+
+	bookings, err := h.bookingService.GetUpcomingBookings(context.Background(), userID.(uint), req.Limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	now := time.Now()
+	upcoming := make([]response.UpcomingBookingResponse, len(bookings))
+	for i, booking := range bookings {
+		upcoming[i] = response.UpcomingBookingResponse{
+			BookingID:       booking.ID,
+			EventID:         booking.EventID,
+			EventName:       booking.Event.Name,
+			VenueName:       booking.Event.Venue.Name,
+			City:            booking.Event.Venue.City,
+			StartTime:       booking.Event.StartTime,
+			StartsInSeconds: max(int64(booking.Event.StartTime.Sub(now).Seconds()), 0),
+			Row:             booking.Seat.Row,
+			Column:          booking.Seat.Column,
+			SeatType:        booking.Seat.SeatType,
+			TicketURL:       fmt.Sprintf("/api/bookings/%d/ticket", booking.ID),
 		}
 	}
 
-	response.Paginated(c, http.StatusOK, bookingResponses, req.Page, req.Limit, total)
+	response.JSON(c, http.StatusOK, upcoming)
 }
 
 // GetBookingByID returns a specific booking
@@ -287,7 +599,26 @@ func (h *BookingHandler) GetBookingByID(c *gin.Context) {
 		return
 	}
 
-	booking, err := h.bookingService.GetBookingByID(context.Background(), uint(bookingID), userID.(uint))
+	var req request.FieldsRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+	fields, err := response.ParseFields(req.Fields, response.BookingResponse{})
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+	relations, fields, err := loadRelations(req, fields, response.BookingResponse{}, expand.Event, expand.EventVenue, expand.Seat)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+	if !relations.Has(expand.EventVenue) {
+		fields = fields.Without(response.BookingResponse{}, "event.capacity")
+	}
+
+	booking, err := h.bookingService.GetBookingByID(expand.With(context.Background(), relations), uint(bookingID), userID.(uint))
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -329,14 +660,115 @@ func (h *BookingHandler) GetBookingByID(c *gin.Context) {
 			IsAvailable: booking.Seat.IsAvailable,
 			IsLocked:    booking.Seat.IsLocked,
 		},
-		Status:        booking.Status,
-		PaymentStatus: booking.PaymentStatus,
-		TotalAmount:   booking.TotalAmount,
-		BookedAt:      booking.BookedAt,
-		CancelledAt:   booking.CancelledAt,
+		Status:          booking.Status,
+		PaymentStatus:   booking.PaymentStatus,
+		TotalAmount:     booking.TotalAmount,
+		BookedAt:        booking.BookedAt,
+		CancelledAt:     booking.CancelledAt,
+		CheckedInAt:     booking.CheckedInAt,
+		NoShow:          booking.NoShow,
+		DiscountAmount:  booking.DiscountAmount,
+		PointsRedeemed:  booking.PointsRedeemed,
+		PointsEarned:    booking.PointsEarned,
+		TermsVersion:    booking.TermsVersion,
+		TermsAcceptedAt: booking.TermsAcceptedAt,
+		SeatPrice:       booking.SeatPrice,
+		PricingRuleID:   booking.PricingRuleID,
+	}
+
+	response.JSONFields(c, http.StatusOK, bookingResp, fields)
+}
+
+// GetTicket returns the printable ticket for a confirmed booking, including the attendee details
+// the event requires. ID numbers are masked.
+func (h *BookingHandler) GetTicket(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid booking ID")
+		return
+	}
+
+	booking, err := h.bookingService.GetBookingByID(context.Background(), uint(bookingID), userID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	if booking.Status != constants.BookingStatusConfirmed || booking.TicketRevokedAt != nil {
+		response.Error(c, http.StatusConflict, "ticket is not valid for this booking")
+		return
+	}
+
+	ticket := response.TicketResponse{
+		BookingID:    booking.ID,
+		EventName:    booking.Event.Name,
+		VenueName:    booking.Event.Venue.Name,
+		VenueAddress: booking.Event.Venue.Address,
+		StartTime:    booking.Event.StartTime,
+		Row:          booking.Seat.Row,
+		Column:       booking.Seat.Column,
+		SeatType:     booking.Seat.SeatType,
+		AttendeeName: booking.AttendeeName,
+		AttendeeID:   entities.MaskIDNumber(booking.AttendeeIDNumber),
+		MinimumAge:   booking.Event.MinimumAge,
+	}
+	if h.tickets != nil {
+		token, claims := h.tickets.Token(booking)
+		ticket.Token = token
+		ticket.ValidFrom = &claims.ValidFrom
+		ticket.ValidUntil = &claims.ValidUntil
+	}
+
+	response.JSON(c, http.StatusOK, ticket)
+}
+
+// InspectSeatLock shows a seat's database lock, Redis lock and the intent behind them, for
+// support to diagnose seats stuck as locked (admin only)
+func (h *BookingHandler) InspectSeatLock(c *gin.Context) {
+	seatID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid seat ID")
+		return
+	}
+
+	inspection, err := h.bookingService.InspectSeatLock(requestContext(c), uint(seatID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, inspection)
+}
+
+// PurgeEventIntents expires an event's pending intents older than a cutoff and releases their
+// seats and locks, e.g. before sales re-open (admin only)
+func (h *BookingHandler) PurgeEventIntents(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	var req request.PurgeIntentsRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	createdBefore := time.Now().Add(-time.Duration(req.OlderThanMinutes) * time.Minute)
+	result, err := h.bookingService.PurgeEventIntents(requestContext(c), uint(eventID), createdBefore)
+	if err != nil {
+		h.handleError(c, err)
+		return
 	}
 
-	response.JSON(c, http.StatusOK, bookingResp)
+	response.Success(c, http.StatusOK, "pending intents purged", result)
 }
 
 // handleError converts application errors to appropriate HTTP responses
@@ -347,6 +779,8 @@ func (h *BookingHandler) handleError(c *gin.Context, err error) {
 			response.Error(c, http.StatusBadRequest, appErr.Message)
 		case "UNAUTHORIZED":
 			response.Error(c, http.StatusUnauthorized, appErr.Message)
+		case "FORBIDDEN":
+			response.ErrorWithCode(c, http.StatusForbidden, appErr.Code, appErr.Message)
 		case "NOT_FOUND":
 			response.Error(c, http.StatusNotFound, appErr.Message)
 		case "CONFLICT":
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BoxOfficeHandler struct {
+	boxOfficeService services.BoxOfficeServiceInterface
+}
+
+func NewBoxOfficeHandler(boxOfficeService services.BoxOfficeServiceInterface) *BoxOfficeHandler {
+	return &BoxOfficeHandler{
+		boxOfficeService: boxOfficeService,
+	}
+}
+
+// SellAtDoor sells a seat at the box office of an event in box-office mode (admin only)
+func (h *BoxOfficeHandler) SellAtDoor(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	var req request.DoorSaleRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	sale := entities.DoorSale{
+		SeatID:        req.SeatID,
+		UserID:        req.UserID,
+		SoldBy:        adminID.(uint),
+		Amount:        req.Amount,
+		PaymentMethod: req.PaymentMethod,
+		Currency:      req.Currency,
+		Reference:     req.Reference,
+		CheckIn:       req.CheckIn,
+	}
+	if req.Attendee != nil {
+		sale.Attendee.FullName = req.Attendee.FullName
+		sale.Attendee.IDNumber = req.Attendee.IDNumber
+		if req.Attendee.DateOfBirth != "" {
+			dateOfBirth, err := time.Parse("2006-01-02", req.Attendee.DateOfBirth)
+			if err != nil {
+				response.Error(c, http.StatusBadRequest, "invalid date_of_birth, expected YYYY-MM-DD")
+				return
+			}
+			sale.Attendee.DateOfBirth = &dateOfBirth
+		}
+	}
+
+	booking, err := h.boxOfficeService.SellAtDoor(requestContext(c), uint(eventID), sale)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "seat sold", response.DoorSaleResponse{
+		BookingID:     booking.ID,
+		EventID:       booking.EventID,
+		UserID:        booking.UserID,
+		SeatID:        booking.SeatID,
+		Row:           booking.Seat.Row,
+		Column:        booking.Seat.Column,
+		SeatType:      booking.Seat.SeatType,
+		TotalAmount:   booking.TotalAmount,
+		PaymentStatus: booking.PaymentStatus,
+		PaymentID:     booking.PaymentID,
+		AttendeeName:  booking.AttendeeName,
+		BookedAt:      booking.BookedAt,
+		CheckedInAt:   booking.CheckedInAt,
+	})
+}
+
+// IssueComps issues complimentary tickets for specific seats of an event (admin only)
+func (h *BoxOfficeHandler) IssueComps(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	var req request.IssueCompsRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	bookings, err := h.boxOfficeService.IssueComps(requestContext(c), uint(eventID), entities.CompIssue{
+		SeatIDs:      req.SeatIDs,
+		UserID:       req.UserID,
+		IssuedBy:     adminID.(uint),
+		Reason:       req.Reason,
+		AttendeeName: req.AttendeeName,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "complimentary tickets issued", toCompResponses(bookings))
+}
+
+// ListComps returns the complimentary tickets issued for an event (admin only)
+func (h *BoxOfficeHandler) ListComps(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	bookings, err := h.boxOfficeService.ListComps(requestContext(c), uint(eventID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, toCompResponses(bookings))
+}
+
+func toCompResponses(bookings []entities.Booking) []response.CompResponse {
+	compResponses := make([]response.CompResponse, len(bookings))
+	for i, booking := range bookings {
+		compResponses[i] = response.CompResponse{
+			BookingID:    booking.ID,
+			EventID:      booking.EventID,
+			UserID:       booking.UserID,
+			SeatID:       booking.SeatID,
+			Row:          booking.Seat.Row,
+			Column:       booking.Seat.Column,
+			SeatType:     booking.Seat.SeatType,
+			Status:       booking.Status,
+			Reason:       booking.CompReason,
+			AttendeeName: booking.AttendeeName,
+			BookedAt:     booking.BookedAt,
+			CheckedInAt:  booking.CheckedInAt,
+		}
+	}
+	return compResponses
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *BoxOfficeHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		case "CONFLICT":
+			response.Error(c, http.StatusConflict, appErr.Message)
+		case "INTERNAL_ERROR":
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
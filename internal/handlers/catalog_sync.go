@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CatalogSyncHandler struct {
+	catalogSyncService services.CatalogSyncServiceInterface
+}
+
+func NewCatalogSyncHandler(catalogSyncService services.CatalogSyncServiceInterface) *CatalogSyncHandler {
+	return &CatalogSyncHandler{
+		catalogSyncService: catalogSyncService,
+	}
+}
+
+// GetStatus returns the partner catalog sync's configuration and latest runs (platform admin only)
+func (h *CatalogSyncHandler) GetStatus(c *gin.Context) {
+	status, err := h.catalogSyncService.GetStatus(requestContext(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	runs := make([]response.CatalogSyncRunResponse, len(status.Runs))
+	for i := range status.Runs {
+		runs[i] = toCatalogSyncRunResponse(&status.Runs[i])
+	}
+	res := response.CatalogSyncStatusResponse{
+		Enabled:  status.Enabled,
+		Source:   status.Source,
+		TenantID: status.TenantID,
+		Interval: status.Interval.String(),
+		Runs:     runs,
+	}
+	if status.LastSuccess != nil {
+		res.LastSuccessAt = &status.LastSuccess.FinishedAt
+	}
+
+	response.JSON(c, http.StatusOK, res)
+}
+
+// RunSync syncs the partner catalog now instead of waiting for the next scheduled sync
+// (platform admin only). A sync that fails, e.g. because the feed is unreachable, is returned
+// with the failed status.
+func (h *CatalogSyncHandler) RunSync(c *gin.Context) {
+	run, err := h.catalogSyncService.RunSync(requestContext(c))
+	if run == nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, toCatalogSyncRunResponse(run))
+}
+
+func toCatalogSyncRunResponse(run *entities.CatalogSyncRun) response.CatalogSyncRunResponse {
+	rejected := make([]response.CatalogSyncErrorResponse, len(run.Rejected))
+	for i, entry := range run.Rejected {
+		rejected[i] = response.CatalogSyncErrorResponse{
+			Kind:       entry.Kind,
+			ExternalID: entry.ExternalID,
+			Field:      entry.Field,
+			Message:    entry.Message,
+		}
+	}
+	return response.CatalogSyncRunResponse{
+		ID:              run.ID,
+		Status:          run.Status,
+		VenuesCreated:   run.VenuesCreated,
+		VenuesUpdated:   run.VenuesUpdated,
+		VenuesUnchanged: run.VenuesUnchanged,
+		EventsCreated:   run.EventsCreated,
+		EventsUpdated:   run.EventsUpdated,
+		EventsUnchanged: run.EventsUnchanged,
+		EventsSkipped:   run.EventsSkipped,
+		Rejected:        rejected,
+		Error:           run.Error,
+		StartedAt:       run.StartedAt,
+		FinishedAt:      run.FinishedAt,
+	}
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *CatalogSyncHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "CONFLICT":
+			response.Error(c, http.StatusConflict, appErr.Message)
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DeadLetterHandler struct {
+	deadLetterService services.DeadLetterServiceInterface
+}
+
+func NewDeadLetterHandler(deadLetterService services.DeadLetterServiceInterface) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		deadLetterService: deadLetterService,
+	}
+}
+
+// ListDeadLetters returns failed async work newest first, filtered by source and status
+// (platform admin only)
+func (h *DeadLetterHandler) ListDeadLetters(c *gin.Context) {
+	var req request.DeadLetterFilterRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+
+	letters, total, err := h.deadLetterService.ListDeadLetters(requestContext(c), req.Source, req.Status, req.Limit, req.Offset())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	letterResponses := make([]response.DeadLetterResponse, len(letters))
+	for i := range letters {
+		letterResponses[i] = toDeadLetterResponse(&letters[i], false)
+	}
+
+	response.Paginated(c, http.StatusOK, letterResponses, req.Page, req.Limit, total)
+}
+
+// GetDeadLetter returns a dead letter with the payload of its work (platform admin only)
+func (h *DeadLetterHandler) GetDeadLetter(c *gin.Context) {
+	letterID, ok := parseDeadLetterID(c)
+	if !ok {
+		return
+	}
+
+	letter, err := h.deadLetterService.GetDeadLetter(requestContext(c), letterID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, toDeadLetterResponse(letter, true))
+}
+
+// RetryDeadLetter runs a dead letter's work again (platform admin only). A failed retry is
+// reported with the letter, still dead, and its new error.
+func (h *DeadLetterHandler) RetryDeadLetter(c *gin.Context) {
+	letterID, ok := parseDeadLetterID(c)
+	if !ok {
+		return
+	}
+
+	letter, err := h.deadLetterService.RetryDeadLetter(requestContext(c), letterID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	message := "Retry succeeded"
+	if letter.Status != constants.DeadLetterStatusRetried {
+		message = "Retry failed"
+	}
+	response.Success(c, http.StatusOK, message, toDeadLetterResponse(letter, false))
+}
+
+// DiscardDeadLetter drops a dead letter's work without retrying it (platform admin only)
+func (h *DeadLetterHandler) DiscardDeadLetter(c *gin.Context) {
+	letterID, ok := parseDeadLetterID(c)
+	if !ok {
+		return
+	}
+
+	letter, err := h.deadLetterService.DiscardDeadLetter(requestContext(c), letterID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Dead letter discarded", toDeadLetterResponse(letter, false))
+}
+
+func parseDeadLetterID(c *gin.Context) (uint, bool) {
+	letterID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid dead letter ID")
+		return 0, false
+	}
+	return uint(letterID), true
+}
+
+func toDeadLetterResponse(letter *entities.DeadLetter, withPayload bool) response.DeadLetterResponse {
+	resp := response.DeadLetterResponse{
+		ID:            letter.ID,
+		Source:        letter.Source,
+		Handler:       letter.Handler,
+		Kind:          letter.Kind,
+		Status:        letter.Status,
+		Error:         letter.Error,
+		Retries:       letter.Retries,
+		CreatedAt:     letter.CreatedAt,
+		LastRetriedAt: letter.LastRetriedAt,
+		ResolvedAt:    letter.ResolvedAt,
+	}
+	if withPayload && json.Valid([]byte(letter.Payload)) {
+		resp.Payload = json.RawMessage(letter.Payload)
+	}
+	return resp
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *DeadLetterHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		case "CONFLICT":
+			response.Error(c, http.StatusConflict, appErr.Message)
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
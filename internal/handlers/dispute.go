@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DisputeHandler struct {
+	disputeService services.DisputeServiceInterface
+}
+
+func NewDisputeHandler(disputeService services.DisputeServiceInterface) *DisputeHandler {
+	return &DisputeHandler{
+		disputeService: disputeService,
+	}
+}
+
+// HandleDisputeWebhook processes dispute notifications from the payment provider
+func (h *DisputeHandler) HandleDisputeWebhook(c *gin.Context) {
+	var req request.DisputeWebhookRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	occurredAt := req.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+
+	dispute, err := h.disputeService.HandleDisputeUpdate(context.Background(), entities.DisputeUpdate{
+		ProviderDisputeID: req.DisputeID,
+		PaymentID:         req.PaymentID,
+		Status:            req.Status,
+		Reason:            req.Reason,
+		Amount:            req.Amount,
+		OccurredAt:        occurredAt,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "dispute recorded", gin.H{
+		"dispute_id": dispute.ProviderDisputeID,
+		"booking_id": dispute.BookingID,
+		"status":     dispute.Status,
+	})
+}
+
+// ListDisputes returns payment disputes for reporting (admin only)
+func (h *DisputeHandler) ListDisputes(c *gin.Context) {
+	var req request.DisputeFilterRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+
+	offset := req.Offset()
+	disputes, total, err := h.disputeService.ListDisputes(requestContext(c), req.Status, req.Limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	disputeResponses := make([]response.DisputeResponse, len(disputes))
+	for i := range disputes {
+		disputeResponses[i] = toDisputeResponse(&disputes[i])
+	}
+
+	response.Paginated(c, http.StatusOK, disputeResponses, req.Page, req.Limit, total)
+}
+
+func toDisputeResponse(dispute *entities.Dispute) response.DisputeResponse {
+	events := make([]response.DisputeEventResponse, len(dispute.Events))
+	for i, event := range dispute.Events {
+		events[i] = response.DisputeEventResponse{
+			Status:     event.Status,
+			OccurredAt: event.OccurredAt,
+		}
+	}
+
+	return response.DisputeResponse{
+		ID:                dispute.ID,
+		ProviderDisputeID: dispute.ProviderDisputeID,
+		BookingID:         dispute.BookingID,
+		EventID:           dispute.Booking.EventID,
+		UserID:            dispute.Booking.UserID,
+		PaymentID:         dispute.PaymentID,
+		Status:            dispute.Status,
+		Reason:            dispute.Reason,
+		Amount:            dispute.Amount,
+		TicketRevoked:     dispute.Booking.TicketRevokedAt != nil,
+		OpenedAt:          dispute.OpenedAt,
+		ClosedAt:          dispute.ClosedAt,
+		Events:            events,
+	}
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *DisputeHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "UNAUTHORIZED":
+			response.Error(c, http.StatusUnauthorized, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		case "CONFLICT":
+			response.Error(c, http.StatusConflict, appErr.Message)
+		case "INTERNAL_ERROR":
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
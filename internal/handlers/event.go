@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"api/constants"
+	"api/internal/enrichment"
 	"api/internal/entities"
+	"api/internal/expand"
 	"api/internal/services"
 	"api/pkg/errors"
+	"api/pkg/money"
 	"api/pkg/request"
 	"api/pkg/response"
 	"context"
@@ -17,6 +20,8 @@ import (
 type EventHandler struct {
 	eventService services.EventServiceInterface
 	venueService services.VenueServiceInterface
+	posts        services.EventPostServiceInterface
+	enrichment   *enrichment.Registry
 }
 
 func NewEventHandler(eventService services.EventServiceInterface, venueService services.VenueServiceInterface) *EventHandler {
@@ -26,6 +31,19 @@ func NewEventHandler(eventService services.EventServiceInterface, venueService s
 	}
 }
 
+// WithEnrichment adds outside data, such as weather forecasts, to event details at venues that
+// enable it
+func (h *EventHandler) WithEnrichment(registry *enrichment.Registry) *EventHandler {
+	h.enrichment = registry
+	return h
+}
+
+// WithPosts adds the organizer's announcements and FAQs to event details
+func (h *EventHandler) WithPosts(posts services.EventPostServiceInterface) *EventHandler {
+	h.posts = posts
+	return h
+}
+
 // GetEvents returns a list of events with pagination and filters
 func (h *EventHandler) GetEvents(c *gin.Context) {
 	var req request.EventFilterRequest
@@ -34,23 +52,39 @@ func (h *EventHandler) GetEvents(c *gin.Context) {
 		return
 	}
 
-	offset := (req.Page - 1) * req.Limit
-	events, total, err := h.eventService.GetEvents(context.Background(), req.Limit, offset, req.EventType, req.City)
+	metadata, err := request.ParseMetadataFilters(req.Metadata)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+	fields, err := response.ParseFields(req.Fields, response.EventResponse{})
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+	relations, fields, err := loadRelations(req.FieldsRequest, fields, response.EventResponse{}, expand.Venue, expand.Organizer)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+	if !relations.Has(expand.Venue) {
+		// Capacity is the venue's
+		fields = fields.Without(response.EventResponse{}, "capacity")
+	}
+
+	offset := req.Offset()
+	events, total, err := h.eventService.GetEvents(expand.With(context.Background(), relations), req.Limit, offset, req.EventType, req.City, metadata)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
+	// Available seats of the whole page at once, from the availability counters
+	availability := h.eventService.GetListingAvailability(context.Background(), events)
+
 	// Convert to response format
 	eventResponses := make([]response.EventResponse, len(events))
 	for i, event := range events {
-		// Calculate available seats using the service
-		availableSeats, err := h.eventService.GetAvailableSeatsCount(context.Background(), event.ID)
-		if err != nil {
-			// Log error but don't fail the request, just set to 0
-			availableSeats = 0
-		}
-
 		eventResponses[i] = response.EventResponse{
 			ID:          event.ID,
 			Name:        event.Name,
@@ -70,15 +104,17 @@ func (h *EventHandler) GetEvents(c *gin.Context) {
 			StartTime:      event.StartTime,
 			EndTime:        event.EndTime,
 			Capacity:       event.Venue.Rows * event.Venue.Columns,
-			AvailableSeats: int(availableSeats),
+			AvailableSeats: int(availability[event.ID]),
 			Price:          event.Price,
 			EventType:      event.EventType,
 			Status:         event.Status,
 			IsHighDemand:   event.IsHighDemand,
+			Organizer:      toOrganizerResponse(&event.Tenant),
+			Metadata:       event.Metadata,
 		}
 	}
 
-	response.Paginated(c, http.StatusOK, eventResponses, req.Page, req.Limit, total)
+	response.PaginatedFields(c, http.StatusOK, eventResponses, fields, req.Page, req.Limit, total)
 }
 
 // GetEventByID returns a single event with details
@@ -90,31 +126,49 @@ func (h *EventHandler) GetEventByID(c *gin.Context) {
 		return
 	}
 
-	event, err := h.eventService.GetEventByID(context.Background(), uint(eventID))
+	var req request.FieldsRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+	fields, err := response.ParseFields(req.Fields, response.EventDetailResponse{})
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+	relations, fields, err := loadRelations(req, fields, response.EventDetailResponse{},
+		expand.Venue, expand.Organizer, expand.Seats, expand.Announcements, expand.FAQs)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+	if !relations.Has(expand.Venue) {
+		fields = fields.Without(response.EventDetailResponse{}, "capacity")
+	}
+
+	event, err := h.eventService.GetEventByID(expand.With(context.Background(), relations), uint(eventID))
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	// Convert seats to response format
-	seatResponses := make([]response.SeatResponse, len(event.Seats))
-	for i, seat := range event.Seats {
-		seatResponses[i] = response.SeatResponse{
-			ID:          seat.ID,
-			Row:         seat.Row,
-			Column:      seat.Column,
-			SeatType:    seat.SeatType,
-			Price:       seat.Price,
-			IsAvailable: seat.IsAvailable,
-			IsLocked:    seat.IsLocked,
+	// Convert seats to response format, unless the client left them out
+	var seatResponses []response.SeatResponse
+	if relations.Has(expand.Seats) {
+		seatResponses = make([]response.SeatResponse, len(event.Seats))
+		for i, seat := range event.Seats {
+			seatResponses[i] = toSeatResponse(&seat)
 		}
 	}
 
 	// Calculate available seats count using the service
-	availableSeats, err := h.eventService.GetAvailableSeatsCount(context.Background(), event.ID)
-	if err != nil {
-		h.handleError(c, err)
-		return
+	var availableSeats int64
+	if fields.Includes("available_seats") {
+		availableSeats, err = h.eventService.GetAvailableSeatsCount(context.Background(), event.ID)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
 	}
 
 	eventResp := response.EventDetailResponse{
@@ -142,11 +196,57 @@ func (h *EventHandler) GetEventByID(c *gin.Context) {
 			EventType:      event.EventType,
 			Status:         event.Status,
 			IsHighDemand:   event.IsHighDemand,
+			Sandbox:        event.Sandbox,
+			Organizer:      toOrganizerResponse(&event.Tenant),
+			Metadata:       event.Metadata,
 		},
-		Seats: seatResponses,
+		Terms:           event.Terms,
+		TermsVersion:    event.TermsVersion,
+		MinimumAge:      event.MinimumAge,
+		RequireFullName: event.RequireFullName,
+		RequireIDNumber: event.RequireIDNumber,
+		SaleCountries:   services.ParseSaleCountries(event.SaleCountries),
+		Seats:           seatResponses,
+		SalesCloseAt:    event.SalesCloseAt(),
+		DoorSales:       event.DoorSales,
+		DoorsOpenAt:     event.DoorsOpenAt(),
+		OversellPercent: event.OversellPercent,
+		TicketLimit:     event.TicketLimit,
+	}
+	if releaseAt, ok := event.NoShowReleaseAt(); ok {
+		eventResp.NoShowReleaseAt = &releaseAt
+		eventResp.NoShowReleaseRefund = event.NoShowReleaseRefund
+	}
+
+	// Posts aren't cached with the event, so they show as soon as they are published
+	var kinds []string
+	if relations.Has(expand.Announcements) && fields.Includes("announcements") {
+		kinds = append(kinds, constants.EventPostKindAnnouncement)
+	}
+	if relations.Has(expand.FAQs) && fields.Includes("faqs") {
+		kinds = append(kinds, constants.EventPostKindFAQ)
+	}
+	if h.posts != nil && len(kinds) > 0 {
+		posts, err := h.posts.ListPosts(context.Background(), event.ID, kinds...)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		for i := range posts {
+			if posts[i].Kind == constants.EventPostKindFAQ {
+				eventResp.FAQs = append(eventResp.FAQs, toEventPostResponse(&posts[i]))
+			} else {
+				eventResp.Announcements = append(eventResp.Announcements, toEventPostResponse(&posts[i]))
+			}
+		}
 	}
 
-	response.JSON(c, http.StatusOK, eventResp)
+	// Enrichers read the venue's location and settings
+	if h.enrichment != nil && relations.Has(expand.Venue) && fields.Includes("enrichments") {
+		eventResp.Enrichments = h.enrichment.Enrich(context.Background(), event)
+	}
+
+	response.JSONFields(c, http.StatusOK, eventResp, fields)
 }
 
 // GetAvailableSeats returns available seats for an event
@@ -165,7 +265,17 @@ func (h *EventHandler) GetAvailableSeats(c *gin.Context) {
 		return
 	}
 
-	seats, err := h.eventService.GetAvailableSeats(context.Background(), uint(eventID))
+	var req request.SeatFilterRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+
+	filter := entities.SeatFilter{
+		Accessible: req.Accessible,
+		Companion:  req.Companion,
+	}
+	seats, err := h.eventService.GetAvailableSeats(context.Background(), uint(eventID), filter)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -180,22 +290,242 @@ func (h *EventHandler) GetAvailableSeats(c *gin.Context) {
 	// Convert to response format
 	seatResponses := make([]response.SeatResponse, len(seats))
 	for i, seat := range seats {
-		seatResponses[i] = response.SeatResponse{
-			ID:          seat.ID,
-			Row:         seat.Row,
-			Column:      seat.Column,
-			SeatType:    seat.SeatType,
-			Price:       seat.Price,
-			IsAvailable: seat.IsAvailable,
-			IsLocked:    seat.IsLocked,
-		}
+		seatResponses[i] = toSeatResponse(&seat)
 	}
 
 	response.JSON(c, http.StatusOK, seatResponses)
 }
 
-// CreateEvent creates a new event (admin only)
+// SetSeatAccessibility designates an accessible seat and its companion seats (admin only)
+func (h *EventHandler) SetSeatAccessibility(c *gin.Context) {
+	seatID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid seat ID")
+		return
+	}
+
+	var req request.SetSeatAccessibilityRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	seat, companions, err := h.eventService.SetSeatAccessibility(requestContext(c), uint(seatID), req.IsAccessible, req.CompanionSeatIDs)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	companionResponses := make([]response.SeatResponse, len(companions))
+	for i, companion := range companions {
+		companionResponses[i] = toSeatResponse(&companion)
+	}
+
+	response.Success(c, http.StatusOK, "seat accessibility updated", gin.H{
+		"seat":       toSeatResponse(seat),
+		"companions": companionResponses,
+	})
+}
+
+// ReleaseSeats opens a further block of held rows for sale (admin only)
+func (h *EventHandler) ReleaseSeats(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	var req request.ReleaseSeatsRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	release, err := h.eventService.ReleaseSeats(requestContext(c), uint(eventID), req.RowStart, req.RowEnd, adminID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	available, err := h.eventService.GetAvailableSeatsCount(requestContext(c), uint(eventID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "seats released", gin.H{
+		"release":         toSeatReleaseResponse(release),
+		"available_seats": available,
+	})
+}
+
+// ListReleases returns an event's release waves and how many seats are still held (admin only)
+func (h *EventHandler) ListReleases(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	releases, held, err := h.eventService.ListReleases(requestContext(c), uint(eventID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	releaseResponses := make([]response.SeatReleaseResponse, len(releases))
+	for i := range releases {
+		releaseResponses[i] = toSeatReleaseResponse(&releases[i])
+	}
+
+	response.JSON(c, http.StatusOK, gin.H{
+		"releases":   releaseResponses,
+		"held_seats": held,
+	})
+}
+
+// WarmCache preloads events into the cache ahead of a big on-sale (admin only). The body is
+// optional; without event IDs the hot events are warmed.
+func (h *EventHandler) WarmCache(c *gin.Context) {
+	var req request.WarmCacheRequest
+	if c.Request.ContentLength != 0 {
+		if err := request.BindJSON(c, &req); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+			return
+		}
+	}
+
+	result, err := h.eventService.WarmCache(requestContext(c), req.EventIDs)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	events := make([]response.CachedEventResponse, len(result.Events))
+	for i, event := range result.Events {
+		events[i] = response.CachedEventResponse{
+			EventID:        event.EventID,
+			Seats:          event.Seats,
+			AvailableSeats: event.AvailableSeats,
+			Error:          event.Error,
+		}
+	}
+
+	response.JSON(c, http.StatusOK, response.CacheWarmResponse{Events: events, WarmedAt: result.WarmedAt})
+}
+
+// UpdateSeatPrices reprices an event's seats in bulk, recording each change in the seats'
+// price history (admin only)
+func (h *EventHandler) UpdateSeatPrices(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	var req request.UpdateSeatPricesRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	changedBy := adminID.(uint)
+	history, err := h.eventService.UpdateSeatPrices(requestContext(c), uint(eventID), entities.SeatPriceChange{
+		SeatIDs:   req.SeatIDs,
+		SeatType:  req.SeatType,
+		RowStart:  req.RowStart,
+		RowEnd:    req.RowEnd,
+		Price:     *req.Price,
+		ChangedBy: &changedBy,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "seat prices updated", gin.H{
+		"seats_repriced": len(history),
+		"price":          *req.Price,
+	})
+}
+
+// GetSeatPriceHistory returns every price change of a seat, oldest first (admin only)
+func (h *EventHandler) GetSeatPriceHistory(c *gin.Context) {
+	seatID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid seat ID")
+		return
+	}
+
+	history, err := h.eventService.ListSeatPriceHistory(requestContext(c), uint(seatID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	changes := make([]response.SeatPriceChangeResponse, len(history))
+	for i, change := range history {
+		changes[i] = response.SeatPriceChangeResponse{
+			SeatID:        change.SeatID,
+			OldPrice:      change.OldPrice,
+			NewPrice:      change.NewPrice,
+			Source:        change.Source,
+			PricingRuleID: change.PricingRuleID,
+			ChangedBy:     change.ChangedBy,
+			ChangedAt:     change.CreatedAt,
+		}
+	}
+
+	response.JSON(c, http.StatusOK, gin.H{"changes": changes})
+}
+
+func toSeatReleaseResponse(release *entities.SeatRelease) response.SeatReleaseResponse {
+	return response.SeatReleaseResponse{
+		ID:            release.ID,
+		RowStart:      release.RowStart,
+		RowEnd:        release.RowEnd,
+		SeatsReleased: release.SeatsReleased,
+		ReleasedBy:    release.ReleasedBy,
+		CreatedAt:     release.CreatedAt,
+	}
+}
+
+func toSeatResponse(seat *entities.Seat) response.SeatResponse {
+	return response.SeatResponse{
+		ID:                seat.ID,
+		Row:               seat.Row,
+		Column:            seat.Column,
+		SeatType:          seat.SeatType,
+		Price:             seat.Price,
+		IsAvailable:       seat.IsAvailable,
+		IsLocked:          seat.IsLocked,
+		IsAccessible:      seat.IsAccessible,
+		IsCompanion:       seat.IsCompanion,
+		CompanionOfSeatID: seat.CompanionOfSeatID,
+	}
+}
+
+// CreateEvent validates a new event and generates its seats in the background (admin only).
+// It responds 202 with a task to poll at GET /admin/tasks/:id.
 func (h *EventHandler) CreateEvent(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
 	var req request.CreateEventRequest
 	if err := request.BindJSON(c, &req); err != nil {
 		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
@@ -203,7 +533,7 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
 	}
 
 	// Validate venue exists
-	_, err := h.venueService.GetVenueByID(context.Background(), req.VenueID)
+	_, err := h.venueService.GetVenueByID(requestContext(c), req.VenueID)
 	if err != nil {
 		response.Error(c, http.StatusBadRequest, "venue not found")
 		return
@@ -211,23 +541,113 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
 
 	// Create event entity
 	event := &entities.Event{
-		Name:         req.Name,
-		Description:  req.Description,
-		VenueID:      req.VenueID,
-		StartTime:    req.StartTime,
-		EndTime:      req.EndTime,
-		Price:        req.Price,
-		EventType:    req.EventType,
-		Status:       constants.EventStatusActive,
-		IsHighDemand: req.IsHighDemand,
-	}
-
-	if err := h.eventService.CreateEvent(context.Background(), event); err != nil {
+		Name:               req.Name,
+		Description:        req.Description,
+		VenueID:            req.VenueID,
+		StartTime:          req.StartTime,
+		EndTime:            req.EndTime,
+		Price:              req.Price,
+		EventType:          req.EventType,
+		Status:             constants.EventStatusActive,
+		IsHighDemand:       req.IsHighDemand,
+		ReminderOffsets:    constants.DefaultReminderOffsets,
+		InitialReleaseRows: req.InitialReleaseRows,
+		Metadata:           req.Metadata,
+
+		SalesCloseMinutesBeforeStart: req.SalesCloseMinutesBeforeStart,
+	}
+
+	// An empty list turns reminders off, leaving the offsets out keeps the default
+	if req.ReminderOffsets != nil {
+		offsets, err := services.NormalizeReminderOffsets(req.ReminderOffsets)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		event.ReminderOffsets = offsets
+	}
+
+	if len(req.WaitlistTiers) > 0 {
+		tiers, err := services.NormalizeWaitlistTiers(req.WaitlistTiers)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		event.WaitlistTiers = tiers
+	}
+	event.WaitlistCap = req.WaitlistCap
+
+	event.SaleCountries, err = services.NormalizeSaleCountries(req.SaleCountries)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	earlyAccessTier, err := services.NormalizeEarlyAccess(req.OnSaleAt, req.EarlyAccessAt, req.EarlyAccessTier)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	event.OnSaleAt = req.OnSaleAt
+	event.EarlyAccessAt = req.EarlyAccessAt
+	event.EarlyAccessTier = earlyAccessTier
+
+	event.Terms, event.TermsVersion, err = services.NormalizeTerms(req.Terms, req.TermsVersion)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	event.MinimumAge = req.MinimumAge
+	event.RequireFullName = req.RequireFullName
+	event.RequireIDNumber = req.RequireIDNumber
+	event.DoorSales = req.DoorSales
+	event.DoorsOpenMinutesBeforeStart = req.DoorsOpenMinutesBeforeStart
+	event.NoShowReleaseMinutes = req.NoShowReleaseMinutes
+	event.NoShowReleaseRefund = req.NoShowReleaseRefund
+	event.OversellPercent = req.OversellPercent
+	event.TicketLimit = req.TicketLimit
+
+	task, err := h.eventService.CreateEvent(requestContext(c), event, adminID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, "event creation started", map[string]uint{"task_id": task.ID})
+}
+
+// CreateSandbox starts creating a sandbox copy of an event with its own seats, to rehearse the
+// on-sale end to end without taking payments or touching the real inventory (admin only).
+// Like CreateEvent it responds 202 with a task to poll.
+func (h *EventHandler) CreateSandbox(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	// The body is optional
+	var req request.CreateSandboxRequest
+	if c.Request.ContentLength > 0 {
+		if err := request.BindJSON(c, &req); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+			return
+		}
+	}
+
+	task, err := h.eventService.CreateSandbox(requestContext(c), uint(eventID), req.OnSaleAt, req.EarlyAccessAt, adminID.(uint))
+	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	response.Success(c, http.StatusCreated, "event created successfully", map[string]uint{"event_id": event.ID})
+	response.Success(c, http.StatusAccepted, "sandbox event creation started", map[string]uint{"task_id": task.ID})
 }
 
 // UpdateEvent updates an existing event (admin only)
@@ -274,8 +694,101 @@ func (h *EventHandler) UpdateEvent(c *gin.Context) {
 	if req.Status != nil {
 		updates["status"] = *req.Status
 	}
+	if req.ReminderOffsets != nil {
+		offsets, err := services.NormalizeReminderOffsets(*req.ReminderOffsets)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		updates["reminder_offsets"] = offsets
+	}
+	if req.WaitlistCap != nil {
+		updates["waitlist_cap"] = *req.WaitlistCap
+	}
+	if req.WaitlistTiers != nil {
+		tiers, err := services.NormalizeWaitlistTiers(*req.WaitlistTiers)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		updates["waitlist_tiers"] = tiers
+	}
+	if req.SaleCountries != nil {
+		countries, err := services.NormalizeSaleCountries(*req.SaleCountries)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		updates["sale_countries"] = countries
+	}
+	if req.SalesCloseMinutesBeforeStart != nil {
+		updates["sales_close_minutes_before_start"] = *req.SalesCloseMinutesBeforeStart
+	}
+	if req.OnSaleAt != nil || req.EarlyAccessAt != nil || req.EarlyAccessTier != nil {
+		tier := ""
+		if req.EarlyAccessTier != nil {
+			tier = *req.EarlyAccessTier
+		}
+		tier, err := services.NormalizeEarlyAccess(req.OnSaleAt, req.EarlyAccessAt, tier)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		if req.OnSaleAt != nil {
+			updates["on_sale_at"] = *req.OnSaleAt
+		}
+		if req.EarlyAccessAt != nil {
+			updates["early_access_at"] = *req.EarlyAccessAt
+		}
+		if req.EarlyAccessTier != nil {
+			updates["early_access_tier"] = tier
+		}
+	}
+	if req.Terms != nil || req.TermsVersion != nil {
+		if req.Terms == nil || req.TermsVersion == nil {
+			response.Error(c, http.StatusBadRequest, "terms and terms_version must be updated together")
+			return
+		}
+		terms, version, err := services.NormalizeTerms(*req.Terms, *req.TermsVersion)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		updates["terms"] = terms
+		updates["terms_version"] = version
+	}
+	if req.MinimumAge != nil {
+		updates["minimum_age"] = *req.MinimumAge
+	}
+	if req.RequireFullName != nil {
+		updates["require_full_name"] = *req.RequireFullName
+	}
+	if req.RequireIDNumber != nil {
+		updates["require_id_number"] = *req.RequireIDNumber
+	}
+	if req.DoorSales != nil {
+		updates["door_sales"] = *req.DoorSales
+	}
+	if req.DoorsOpenMinutesBeforeStart != nil {
+		updates["doors_open_minutes_before_start"] = *req.DoorsOpenMinutesBeforeStart
+	}
+	if req.NoShowReleaseMinutes != nil {
+		updates["no_show_release_minutes"] = *req.NoShowReleaseMinutes
+	}
+	if req.NoShowReleaseRefund != nil {
+		updates["no_show_release_refund"] = *req.NoShowReleaseRefund
+	}
+	if req.OversellPercent != nil {
+		updates["oversell_percent"] = *req.OversellPercent
+	}
+	if req.TicketLimit != nil {
+		updates["ticket_limit"] = *req.TicketLimit
+	}
+	if req.Metadata != nil {
+		updates["metadata"] = entities.Metadata(*req.Metadata)
+	}
 
-	event, err := h.eventService.UpdateEvent(context.Background(), uint(eventID), updates)
+	event, err := h.eventService.UpdateEvent(requestContext(c), uint(eventID), updates)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -293,7 +806,7 @@ func (h *EventHandler) DeleteEvent(c *gin.Context) {
 		return
 	}
 
-	if err := h.eventService.DeleteEvent(context.Background(), uint(eventID)); err != nil {
+	if err := h.eventService.DeleteEvent(requestContext(c), uint(eventID)); err != nil {
 		h.handleError(c, err)
 		return
 	}
@@ -310,7 +823,7 @@ func (h *EventHandler) GetEventStats(c *gin.Context) {
 		return
 	}
 
-	stats, err := h.eventService.GetEventStats(context.Background(), uint(eventID))
+	stats, err := h.eventService.GetEventStats(requestContext(c), uint(eventID))
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -324,8 +837,18 @@ func (h *EventHandler) GetEventStats(c *gin.Context) {
 		LockedSeats:         stats["locked_seats"].(int64),
 		AvailableSeats:      stats["available_seats"].(int64),
 		CapacityUtilization: stats["capacity_utilization"].(float64),
-		TotalRevenue:        stats["total_revenue"].(float64),
+		TotalRevenue:        stats["total_revenue"].(money.Money),
 		BookingRate:         stats["booking_rate"].(float64),
+		CheckedIn:           stats["checked_in"].(int64),
+		NoShows:             stats["no_shows"].(int64),
+		NoShowRate:          stats["no_show_rate"].(float64),
+		OnlineSeats:         stats["online_seats"].(int64),
+		OnlineRevenue:       stats["online_revenue"].(money.Money),
+		DoorSeats:           stats["door_seats"].(int64),
+		DoorRevenue:         stats["door_revenue"].(money.Money),
+		CompSeats:           stats["comp_seats"].(int64),
+		OversellPlaces:      stats["oversell_places"].(int64),
+		OversellSeats:       stats["oversell_seats"].(int64),
 	}
 
 	response.JSON(c, http.StatusOK, statsResp)
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EventPostHandler struct {
+	postService services.EventPostServiceInterface
+}
+
+func NewEventPostHandler(postService services.EventPostServiceInterface) *EventPostHandler {
+	return &EventPostHandler{
+		postService: postService,
+	}
+}
+
+// CreatePost publishes an announcement or FAQ entry on an event (admin only). Announcements
+// are emailed to the event's booked attendees shortly after.
+func (h *EventPostHandler) CreatePost(c *gin.Context) {
+	authorID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	var req request.CreateEventPostRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	post, err := h.postService.CreatePost(requestContext(c), &entities.EventPost{
+		EventID:  uint(eventID),
+		Kind:     req.Kind,
+		Title:    req.Title,
+		Body:     req.Body,
+		Position: req.Position,
+		AuthorID: authorID.(uint),
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Event post published", toEventPostResponse(post))
+}
+
+// UpdatePost edits an announcement or FAQ entry (admin only)
+func (h *EventPostHandler) UpdatePost(c *gin.Context) {
+	postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid post ID")
+		return
+	}
+
+	var req request.UpdateEventPostRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.Title != nil {
+		updates["title"] = *req.Title
+	}
+	if req.Body != nil {
+		updates["body"] = *req.Body
+	}
+	if req.Position != nil {
+		updates["position"] = *req.Position
+	}
+
+	post, err := h.postService.UpdatePost(requestContext(c), uint(postID), updates)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Event post updated", toEventPostResponse(post))
+}
+
+// DeletePost takes an announcement or FAQ entry down (admin only)
+func (h *EventPostHandler) DeletePost(c *gin.Context) {
+	postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid post ID")
+		return
+	}
+
+	if err := h.postService.DeletePost(requestContext(c), uint(postID)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Event post deleted", nil)
+}
+
+func toEventPostResponse(post *entities.EventPost) response.EventPostResponse {
+	return response.EventPostResponse{
+		ID:        post.ID,
+		EventID:   post.EventID,
+		Kind:      post.Kind,
+		Title:     post.Title,
+		Body:      post.Body,
+		Position:  post.Position,
+		CreatedAt: post.CreatedAt,
+		UpdatedAt: post.UpdatedAt,
+	}
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *EventPostHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
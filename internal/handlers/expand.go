@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"api/internal/expand"
+	"api/pkg/request"
+	"api/pkg/response"
+	"strings"
+)
+
+// loadRelations decides which of an endpoint's relations to load: those named by expand, or
+// all of them without it, less those fields leaves out. A nested relation is only loaded with
+// its parent. It returns them with fields narrowed to leave the others out of the response.
+func loadRelations(req request.FieldsRequest, fields response.FieldSet, sample interface{}, relations ...string) (expand.Set, response.FieldSet, error) {
+	requested, err := expand.Parse(req.Expand, relations...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loaded := expand.Set{}
+	var skipped []string
+	for _, relation := range relations {
+		parent, _, nested := strings.Cut(relation, ".")
+		if requested.Has(relation) && fields.Includes(relation) && (!nested || loaded[parent]) {
+			loaded[relation] = true
+		} else {
+			skipped = append(skipped, relation)
+		}
+	}
+	return loaded, fields.Without(sample, skipped...), nil
+}
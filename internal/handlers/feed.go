@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// feedCacheControl lets CDNs and crawlers reuse sitemaps and feeds as long as the server does
+const feedCacheControl = "public, max-age=300"
+
+type FeedHandler struct {
+	feedService services.FeedServiceInterface
+	siteURL     string
+}
+
+// NewFeedHandler serves the sitemap and event feeds with links into the storefront at siteURL
+func NewFeedHandler(feedService services.FeedServiceInterface, siteURL string) *FeedHandler {
+	return &FeedHandler{
+		feedService: feedService,
+		siteURL:     siteURL,
+	}
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID        string       `xml:"id"`
+	Title     string       `xml:"title"`
+	Link      atomLink     `xml:"link"`
+	Published string       `xml:"published"`
+	Updated   string       `xml:"updated"`
+	Summary   string       `xml:"summary"`
+	Author    atomAuthor   `xml:"author"`
+	Category  atomCategory `xml:"category"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	LastBuildDate string     `xml:"lastBuildDate"`
+	Links         []atomLink `xml:"atom:link"` // self and next page (RFC 5005)
+	Items         []rssItem  `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+	Category    string  `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// GetSitemap lists the storefront pages of organizers and events that haven't ended
+func (h *FeedHandler) GetSitemap(c *gin.Context) {
+	sitemap, err := h.feedService.Sitemap(context.Background())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	urlSet := sitemapURLSet{URLs: make([]sitemapURL, 0, len(sitemap.Organizers)+len(sitemap.Events))}
+	for _, organizer := range sitemap.Organizers {
+		urlSet.URLs = append(urlSet.URLs, sitemapURL{
+			Loc:     h.siteURL + "/organizers/" + url.PathEscape(organizer.Slug),
+			LastMod: organizer.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	for _, event := range sitemap.Events {
+		urlSet.URLs = append(urlSet.URLs, sitemapURL{
+			Loc:     h.eventURL(event.ID),
+			LastMod: event.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	h.writeXML(c, "application/xml; charset=utf-8", urlSet)
+}
+
+// GetAtomFeed returns a page of newly published events as an Atom feed
+func (h *FeedHandler) GetAtomFeed(c *gin.Context) {
+	feed, ok := h.eventFeed(c)
+	if !ok {
+		return
+	}
+
+	atom := atomFeed{
+		ID:      h.siteURL + "/events",
+		Title:   "New events",
+		Updated: feedUpdated(feed).Format(time.RFC3339),
+		Links: append(h.pageLinks(c, feed),
+			atomLink{Rel: "alternate", Type: "text/html", Href: h.siteURL + "/events"}),
+		Entries: make([]atomEntry, len(feed.Events)),
+	}
+	for i, event := range feed.Events {
+		atom.Entries[i] = atomEntry{
+			ID:        h.eventURL(event.ID),
+			Title:     event.Name,
+			Link:      atomLink{Rel: "alternate", Type: "text/html", Href: h.eventURL(event.ID)},
+			Published: event.CreatedAt.UTC().Format(time.RFC3339),
+			Updated:   event.UpdatedAt.UTC().Format(time.RFC3339),
+			Summary:   eventSummary(&event),
+			Author:    atomAuthor{Name: organizerName(&event.Tenant)},
+			Category:  atomCategory{Term: event.EventType},
+		}
+	}
+
+	h.writeXML(c, "application/atom+xml; charset=utf-8", atom)
+}
+
+// GetRSSFeed returns a page of newly published events as an RSS 2.0 feed
+func (h *FeedHandler) GetRSSFeed(c *gin.Context) {
+	feed, ok := h.eventFeed(c)
+	if !ok {
+		return
+	}
+
+	rss := rssFeed{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         "New events",
+			Link:          h.siteURL + "/events",
+			Description:   "Events most recently put on sale",
+			LastBuildDate: feedUpdated(feed).Format(time.RFC1123Z),
+			Links:         h.pageLinks(c, feed),
+			Items:         make([]rssItem, len(feed.Events)),
+		},
+	}
+	for i, event := range feed.Events {
+		rss.Channel.Items[i] = rssItem{
+			Title:       event.Name,
+			Link:        h.eventURL(event.ID),
+			GUID:        rssGUID{IsPermaLink: true, Value: h.eventURL(event.ID)},
+			PubDate:     event.CreatedAt.UTC().Format(time.RFC1123Z),
+			Description: eventSummary(&event),
+			Category:    event.EventType,
+		}
+	}
+
+	h.writeXML(c, "application/rss+xml; charset=utf-8", rss)
+}
+
+func (h *FeedHandler) eventFeed(c *gin.Context) (*entities.EventFeedPage, bool) {
+	var req request.FeedRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return nil, false
+	}
+
+	feed, err := h.feedService.EventFeed(context.Background(), req.Page)
+	if err != nil {
+		h.handleError(c, err)
+		return nil, false
+	}
+	return feed, true
+}
+
+func (h *FeedHandler) eventURL(eventID uint) string {
+	return fmt.Sprintf("%s/events/%d", h.siteURL, eventID)
+}
+
+// pageLinks returns the feed's self link and, unless it's the last page, the next page's
+func (h *FeedHandler) pageLinks(c *gin.Context, feed *entities.EventFeedPage) []atomLink {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	base := scheme + "://" + c.Request.Host + c.Request.URL.Path
+
+	self := base
+	if feed.Page > 1 {
+		self += "?page=" + strconv.Itoa(feed.Page)
+	}
+	links := []atomLink{{Rel: "self", Href: self}}
+	if feed.HasNext() {
+		links = append(links, atomLink{Rel: "next", Href: base + "?page=" + strconv.Itoa(feed.Page+1)})
+	}
+	return links
+}
+
+func (h *FeedHandler) writeXML(c *gin.Context, contentType string, v interface{}) {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	c.Header("Cache-Control", feedCacheControl)
+	c.Data(http.StatusOK, contentType, append([]byte(xml.Header), body...))
+}
+
+// feedUpdated is when the newest change to the page's events was made
+func feedUpdated(feed *entities.EventFeedPage) time.Time {
+	var updated time.Time
+	for _, event := range feed.Events {
+		if event.UpdatedAt.After(updated) {
+			updated = event.UpdatedAt
+		}
+	}
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	return updated.UTC()
+}
+
+// eventSummary describes where and when an event takes place, followed by its description
+func eventSummary(event *entities.Event) string {
+	summary := fmt.Sprintf("%s, %s. %s.", event.Venue.Name, event.Venue.City,
+		event.StartTime.UTC().Format("Mon 2 Jan 2006 15:04 MST"))
+	if event.Description != "" {
+		summary += " " + event.Description
+	}
+	return summary
+}
+
+func organizerName(t *entities.Tenant) string {
+	if organizer := toOrganizerResponse(t); organizer != nil {
+		return organizer.DisplayName
+	}
+	return ""
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *FeedHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		case "INTERNAL_ERROR":
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
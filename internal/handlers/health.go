@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"api/internal/middleware"
+	redisconn "api/internal/redis"
+	"api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// degradedBanner is the message clients show while Redis is unavailable
+const degradedBanner = "Some features are running in a reduced mode. Bookings still work, but waitlist positions may shift once service is fully restored."
+
+// degradedFeatures describes what changes in degraded mode
+var degradedFeatures = []string{
+	"seat locks are held in the database only",
+	"waitlists are served in join order, without priority tiers",
+	"rate limits are counted per API instance",
+}
+
+type HealthHandler struct {
+	redisHealth *redisconn.Health
+	drain       *middleware.Drain
+}
+
+func NewHealthHandler(redisHealth *redisconn.Health, drain *middleware.Drain) *HealthHandler {
+	return &HealthHandler{
+		redisHealth: redisHealth,
+		drain:       drain,
+	}
+}
+
+// GetHealth reports ok, or degraded while Redis is unavailable. It answers 200 in both
+// cases since the API keeps serving requests without Redis. While the instance drains for
+// shutdown it answers 503, so load balancers stop sending it traffic.
+func (h *HealthHandler) GetHealth(c *gin.Context) {
+	degraded, since := h.redisHealth.Status()
+	redis := "up"
+	if degraded {
+		redis = "unavailable"
+	}
+	if h.drain != nil && h.drain.Draining() {
+		health := response.HealthResponse{Status: "draining", Redis: redis}
+		if degraded {
+			health.DegradedSince = &since
+		}
+		response.JSON(c, http.StatusServiceUnavailable, health)
+		return
+	}
+	if !degraded {
+		response.JSON(c, http.StatusOK, response.HealthResponse{Status: "ok", Redis: "up"})
+		return
+	}
+	response.JSON(c, http.StatusOK, response.HealthResponse{Status: "degraded", Redis: "unavailable", DegradedSince: &since})
+}
+
+// GetStatus tells clients whether to show the degraded mode banner
+func (h *HealthHandler) GetStatus(c *gin.Context) {
+	if !h.redisHealth.Degraded() {
+		response.Success(c, http.StatusOK, "Service status retrieved", response.ServiceStatusResponse{})
+		return
+	}
+	response.Success(c, http.StatusOK, "Service status retrieved", response.ServiceStatusResponse{
+		Degraded: true,
+		Banner:   degradedBanner,
+		Features: degradedFeatures,
+	})
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImportHandler struct {
+	importService services.ImportServiceInterface
+}
+
+func NewImportHandler(importService services.ImportServiceInterface) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// ImportVenues imports venue seat maps from a CSV upload (admin only)
+func (h *ImportHandler) ImportVenues(c *gin.Context) {
+	h.runImport(c, h.importService.ImportVenues)
+}
+
+// ImportEvents imports event schedules from a CSV upload (admin only)
+func (h *ImportHandler) ImportEvents(c *gin.Context) {
+	h.runImport(c, h.importService.ImportEvents)
+}
+
+type importFunc func(ctx context.Context, r io.Reader, dryRun bool) (*entities.ImportResult, error)
+
+// runImport reads the CSV from the "file" multipart field or the raw request body
+func (h *ImportHandler) runImport(c *gin.Context, run importFunc) {
+	var req request.ImportRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+
+	var body io.Reader = c.Request.Body
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "failed to read uploaded file", err.Error())
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	result, err := run(requestContext(c), body, req.DryRun)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	if len(result.Errors) > 0 {
+		response.JSON(c, http.StatusUnprocessableEntity, result)
+		return
+	}
+
+	message := "import applied successfully"
+	if req.DryRun {
+		message = "import validated successfully"
+	}
+	response.Success(c, http.StatusOK, message, result)
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *ImportHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "UNAUTHORIZED":
+			response.Error(c, http.StatusUnauthorized, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		case "CONFLICT":
+			response.Error(c, http.StatusConflict, appErr.Message)
+		case "INTERNAL_ERROR":
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
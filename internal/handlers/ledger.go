@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"api/internal/tenant"
+	"api/pkg/request"
+	"api/pkg/response"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LedgerHandler struct {
+	ledgerService services.LedgerServiceInterface
+}
+
+func NewLedgerHandler(ledgerService services.LedgerServiceInterface) *LedgerHandler {
+	return &LedgerHandler{
+		ledgerService: ledgerService,
+	}
+}
+
+// GetBalances handles GET /admin/ledger/balances
+func (h *LedgerHandler) GetBalances(c *gin.Context) {
+	ctx := requestContext(c)
+	if _, scoped := tenant.FromContext(ctx); !scoped && c.Query("tenant_id") != "" {
+		tenantID, err := strconv.ParseUint(c.Query("tenant_id"), 10, 32)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid tenant ID")
+			return
+		}
+		ctx = tenant.WithTenant(ctx, uint(tenantID))
+	}
+
+	var req request.LedgerFilterRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+
+	filter := entities.LedgerFilter{EventID: req.EventID}
+	if req.From != "" {
+		from, _ := time.Parse("2006-01-02", req.From)
+		filter.From = &from
+	}
+	if req.To != "" {
+		// Journals posted any time on the last day count
+		to, _ := time.Parse("2006-01-02", req.To)
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		response.Error(c, http.StatusBadRequest, "from must not be after to")
+		return
+	}
+
+	report, err := h.ledgerService.GetBalances(ctx, filter)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "failed to retrieve ledger balances")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "ledger balances retrieved successfully", report)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"api/internal/services"
+	"api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LockDivergenceHandler struct {
+	detector *services.LockDivergenceDetector
+}
+
+func NewLockDivergenceHandler(detector *services.LockDivergenceDetector) *LockDivergenceHandler {
+	return &LockDivergenceHandler{
+		detector: detector,
+	}
+}
+
+// GetReport returns the outcome of the latest seat lock divergence check (platform admin only)
+func (h *LockDivergenceHandler) GetReport(c *gin.Context) {
+	report := h.detector.LastReport()
+	if report == nil {
+		response.Error(c, http.StatusNotFound, "no seat lock divergence check has run yet")
+		return
+	}
+
+	response.JSON(c, http.StatusOK, report)
+}
+
+// RunCheck checks for diverged seat locks now instead of waiting for the next scheduled check
+// (platform admin only)
+func (h *LockDivergenceHandler) RunCheck(c *gin.Context) {
+	if err := h.detector.Check(requestContext(c)); err != nil {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	response.JSON(c, http.StatusOK, h.detector.LastReport())
+}
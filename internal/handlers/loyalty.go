@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LoyaltyHandler struct {
+	loyaltyService services.LoyaltyServiceInterface
+}
+
+func NewLoyaltyHandler(loyaltyService services.LoyaltyServiceInterface) *LoyaltyHandler {
+	return &LoyaltyHandler{
+		loyaltyService: loyaltyService,
+	}
+}
+
+// GetSummary returns the user's loyalty points balance and membership tier
+func (h *LoyaltyHandler) GetSummary(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	summary, err := h.loyaltyService.GetSummary(context.Background(), userID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, response.LoyaltySummaryResponse{
+		Points:           summary.Points,
+		LifetimePoints:   summary.LifetimePoints,
+		Tier:             summary.Tier,
+		NextTier:         summary.NextTier,
+		PointsToNextTier: summary.PointsToNextTier,
+	})
+}
+
+// ListTransactions returns the user's loyalty points history
+func (h *LoyaltyHandler) ListTransactions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req request.PaginationRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+
+	offset := req.Offset()
+	transactions, total, err := h.loyaltyService.ListTransactions(context.Background(), userID.(uint), req.Limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	transactionResponses := make([]response.LoyaltyTransactionResponse, len(transactions))
+	for i, transaction := range transactions {
+		transactionResponses[i] = response.LoyaltyTransactionResponse{
+			ID:        transaction.ID,
+			BookingID: transaction.BookingID,
+			Points:    transaction.Points,
+			Reason:    transaction.Reason,
+			Balance:   transaction.Balance,
+			CreatedAt: transaction.CreatedAt,
+		}
+	}
+
+	response.Paginated(c, http.StatusOK, transactionResponses, req.Page, req.Limit, total)
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *LoyaltyHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "UNAUTHORIZED":
+			response.Error(c, http.StatusUnauthorized, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		case "CONFLICT":
+			response.Error(c, http.StatusConflict, appErr.Message)
+		case "INTERNAL_ERROR":
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"api/internal/metrics"
+	logger "api/pkg/logging"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MetricsHandler struct {
+	registry *metrics.Registry
+}
+
+func NewMetricsHandler(registry *metrics.Registry) *MetricsHandler {
+	return &MetricsHandler{
+		registry: registry,
+	}
+}
+
+// GetMetrics serves metrics in the Prometheus text exposition format
+func (h *MetricsHandler) GetMetrics(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := h.registry.WritePrometheus(c.Writer); err != nil {
+		logger.Errorf("Failed to write metrics: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"api/constants"
+	"api/internal/payments"
+	"api/pkg/request"
+	"api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MockPaymentHandler struct {
+	provider *payments.MockProvider
+}
+
+func NewMockPaymentHandler(provider *payments.MockProvider) *MockPaymentHandler {
+	return &MockPaymentHandler{
+		provider: provider,
+	}
+}
+
+// CreateCharge charges a test card. Successful charges return the payment_id, provider and
+// card details to pass to ConfirmBooking; declines answer 402 with the decline code, which the
+// client sends as the failure reason when it retries the payment.
+func (h *MockPaymentHandler) CreateCharge(c *gin.Context) {
+	var req request.MockChargeRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	charge, err := h.provider.Charge(c.Request.Context(), payments.ChargeRequest{
+		Reference:  req.PaymentReference,
+		Amount:     req.Amount,
+		Currency:   req.Currency,
+		CardNumber: req.CardNumber,
+	})
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	if charge.Status == constants.PaymentStatusFailed {
+		response.Error(c, http.StatusPaymentRequired, "payment declined", charge.DeclineCode)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "payment succeeded", response.MockChargeResponse{
+		PaymentID:        charge.PaymentID,
+		PaymentReference: charge.Reference,
+		Provider:         payments.MockProviderName,
+		Status:           charge.Status,
+		Amount:           charge.Amount,
+		Currency:         charge.Currency,
+		CardBrand:        charge.CardBrand,
+		CardLast4:        charge.CardLast4,
+		DisputeOpened:    charge.DisputeOpened,
+		CreatedAt:        charge.CreatedAt,
+	})
+}
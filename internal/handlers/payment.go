@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentHandler struct {
+	paymentService services.PaymentServiceInterface
+}
+
+func NewPaymentHandler(paymentService services.PaymentServiceInterface) *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: paymentService,
+	}
+}
+
+// ListTransactions returns payment transactions for reconciliation (admin only)
+func (h *PaymentHandler) ListTransactions(c *gin.Context) {
+	var req request.PaymentTransactionFilterRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+
+	filter := entities.PaymentTransactionFilter{
+		Provider:          req.Provider,
+		ProviderReference: req.Reference,
+		Status:            req.Status,
+		BookingID:         req.BookingID,
+		From:              req.From,
+		To:                req.To,
+	}
+
+	offset := req.Offset()
+	transactions, total, err := h.paymentService.ListTransactions(requestContext(c), filter, req.Limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	transactionResponses := make([]response.PaymentTransactionResponse, len(transactions))
+	for i := range transactions {
+		transactionResponses[i] = toPaymentTransactionResponse(&transactions[i])
+	}
+
+	response.Paginated(c, http.StatusOK, transactionResponses, req.Page, req.Limit, total)
+}
+
+// GetTransaction returns a single payment transaction with its status timeline (admin only)
+func (h *PaymentHandler) GetTransaction(c *gin.Context) {
+	transactionIDStr := c.Param("id")
+	transactionID, err := strconv.ParseUint(transactionIDStr, 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid transaction ID")
+		return
+	}
+
+	transaction, err := h.paymentService.GetTransaction(requestContext(c), uint(transactionID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, toPaymentTransactionResponse(transaction))
+}
+
+func toPaymentTransactionResponse(transaction *entities.PaymentTransaction) response.PaymentTransactionResponse {
+	events := make([]response.PaymentTransactionEventResponse, len(transaction.Events))
+	for i, event := range transaction.Events {
+		events[i] = response.PaymentTransactionEventResponse{
+			Status:     event.Status,
+			OccurredAt: event.OccurredAt,
+		}
+	}
+
+	return response.PaymentTransactionResponse{
+		ID:                transaction.ID,
+		BookingID:         transaction.BookingID,
+		BookingIntentID:   transaction.BookingIntentID,
+		Provider:          transaction.Provider,
+		ProviderReference: transaction.ProviderReference,
+		Amount:            transaction.Amount,
+		Currency:          transaction.Currency,
+		MethodType:        transaction.MethodType,
+		MaskedMethod:      transaction.MaskedMethod,
+		Status:            transaction.Status,
+		CreatedAt:         transaction.CreatedAt,
+		Timeline:          events,
+	}
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *PaymentHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "UNAUTHORIZED":
+			response.Error(c, http.StatusUnauthorized, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		case "CONFLICT":
+			response.Error(c, http.StatusConflict, appErr.Message)
+		case "INTERNAL_ERROR":
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
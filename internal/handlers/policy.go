@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PolicyHandler struct {
+	policyService services.PolicyServiceInterface
+}
+
+func NewPolicyHandler(policyService services.PolicyServiceInterface) *PolicyHandler {
+	return &PolicyHandler{
+		policyService: policyService,
+	}
+}
+
+// GetPolicies returns the terms of service and privacy policy versions users must accept
+func (h *PolicyHandler) GetPolicies(c *gin.Context) {
+	versions := h.policyService.CurrentVersions()
+	response.JSON(c, http.StatusOK, response.PolicyVersionsResponse{
+		TermsVersion:   versions[constants.PolicyTermsOfService],
+		PrivacyVersion: versions[constants.PolicyPrivacy],
+	})
+}
+
+// AcceptPolicies records the caller accepting the current policy versions, lifting the gate
+// on the rest of the API after a version bump
+func (h *PolicyHandler) AcceptPolicies(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req request.PolicyAcceptanceRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	acceptances, err := h.policyService.Accept(requestContext(c), userID.(uint), policyConsent(req), c.ClientIP())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "policies accepted", toPolicyAcceptanceResponses(acceptances))
+}
+
+// ListAcceptances returns the caller's policy acceptance history, newest first
+func (h *PolicyHandler) ListAcceptances(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	acceptances, err := h.policyService.ListAcceptances(requestContext(c), userID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, toPolicyAcceptanceResponses(acceptances))
+}
+
+func policyConsent(req request.PolicyAcceptanceRequest) entities.PolicyConsent {
+	return entities.PolicyConsent{
+		Accept:         req.AcceptPolicies,
+		TermsVersion:   req.TermsVersion,
+		PrivacyVersion: req.PrivacyVersion,
+	}
+}
+
+func toPolicyAcceptanceResponses(acceptances []entities.PolicyAcceptance) []response.PolicyAcceptanceResponse {
+	responses := make([]response.PolicyAcceptanceResponse, len(acceptances))
+	for i, a := range acceptances {
+		responses[i] = response.PolicyAcceptanceResponse{
+			Policy:     a.Policy,
+			Version:    a.Version,
+			IPAddress:  a.IPAddress,
+			AcceptedAt: a.AcceptedAt,
+		}
+	}
+	return responses
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *PolicyHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PresaleHandler struct {
+	presaleService services.PresaleServiceInterface
+}
+
+func NewPresaleHandler(presaleService services.PresaleServiceInterface) *PresaleHandler {
+	return &PresaleHandler{
+		presaleService: presaleService,
+	}
+}
+
+// CreateBatch generates a batch of presale codes for an event (admin only)
+func (h *PresaleHandler) CreateBatch(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	var req request.CreatePresaleBatchRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	maxUses := 1
+	if req.MaxUses != nil {
+		maxUses = *req.MaxUses
+	}
+
+	batch, err := h.presaleService.CreateBatch(requestContext(c), uint(eventID), req.Name, req.Count, maxUses, adminID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "presale codes generated", toPresaleBatchResponse(batch))
+}
+
+// ListBatches returns an event's presale batches with usage totals (admin only)
+func (h *PresaleHandler) ListBatches(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	summaries, err := h.presaleService.ListBatches(requestContext(c), uint(eventID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	batchResponses := make([]response.PresaleBatchResponse, len(summaries))
+	for i, summary := range summaries {
+		batchResponses[i] = response.PresaleBatchResponse{
+			ID:            summary.ID,
+			EventID:       summary.EventID,
+			Name:          summary.Name,
+			MaxUses:       summary.MaxUses,
+			CreatedBy:     summary.CreatedBy,
+			CreatedAt:     summary.CreatedAt,
+			CodeCount:     summary.Codes,
+			RedeemedCodes: summary.RedeemedCodes,
+			Uses:          summary.Uses,
+		}
+	}
+
+	response.JSON(c, http.StatusOK, batchResponses)
+}
+
+// GetBatch returns a presale batch with every code and its uses (admin only)
+func (h *PresaleHandler) GetBatch(c *gin.Context) {
+	batchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid batch ID")
+		return
+	}
+
+	batch, err := h.presaleService.GetBatch(requestContext(c), uint(batchID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, toPresaleBatchResponse(batch))
+}
+
+func toPresaleBatchResponse(batch *entities.PresaleBatch) response.PresaleBatchResponse {
+	resp := response.PresaleBatchResponse{
+		ID:        batch.ID,
+		EventID:   batch.EventID,
+		Name:      batch.Name,
+		MaxUses:   batch.MaxUses,
+		CreatedBy: batch.CreatedBy,
+		CreatedAt: batch.CreatedAt,
+		CodeCount: len(batch.Codes),
+		Codes:     make([]response.PresaleCodeResponse, len(batch.Codes)),
+	}
+	for i, code := range batch.Codes {
+		resp.Codes[i] = response.PresaleCodeResponse{
+			Code:    code.Code,
+			MaxUses: code.MaxUses,
+			Uses:    code.Uses,
+		}
+		resp.Uses += code.Uses
+		if code.Uses > 0 {
+			resp.RedeemedCodes++
+		}
+	}
+	return resp
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *PresaleHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "UNAUTHORIZED":
+			response.Error(c, http.StatusUnauthorized, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		case "CONFLICT":
+			response.Error(c, http.StatusConflict, appErr.Message)
+		case "INTERNAL_ERROR":
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
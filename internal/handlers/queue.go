@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QueueHandler struct {
+	queueService services.QueueServiceInterface
+}
+
+func NewQueueHandler(queueService services.QueueServiceInterface) *QueueHandler {
+	return &QueueHandler{
+		queueService: queueService,
+	}
+}
+
+// JoinQueue puts the user in the on-sale queue of a high-demand event
+func (h *QueueHandler) JoinQueue(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("eventId"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	entry, err := h.queueService.JoinQueue(requestContext(c), userID.(uint), uint(eventID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Successfully joined queue", toQueueResponse(entry))
+}
+
+// GetQueueStatus returns the user's position in an event's queue, or their admission
+func (h *QueueHandler) GetQueueStatus(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("eventId"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	entry, err := h.queueService.GetQueueStatus(requestContext(c), userID.(uint), uint(eventID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Queue status retrieved", toQueueResponse(entry))
+}
+
+// LeaveQueue gives up the user's place in an event's queue
+func (h *QueueHandler) LeaveQueue(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("eventId"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	if err := h.queueService.LeaveQueue(requestContext(c), userID.(uint), uint(eventID)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Successfully left queue", nil)
+}
+
+// GetQueueLength returns how many users are waiting in an event's queue
+func (h *QueueHandler) GetQueueLength(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("eventId"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	length, err := h.queueService.GetQueueLength(requestContext(c), uint(eventID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Queue length retrieved", response.QueueLengthResponse{
+		EventID: uint(eventID),
+		Length:  length,
+	})
+}
+
+func toQueueResponse(entry *entities.EventQueue) response.QueueResponse {
+	return response.QueueResponse{
+		ID:            entry.ID,
+		EventID:       entry.EventID,
+		UserID:        entry.UserID,
+		QueuePosition: entry.QueuePosition,
+		Status:        entry.Status,
+		JoinedAt:      entry.JoinedAt,
+		ActiveAt:      entry.ActiveAt,
+		ExpiresAt:     entry.ExpiresAt,
+	}
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *QueueHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "UNAUTHORIZED":
+			response.Error(c, http.StatusUnauthorized, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		case "CONFLICT":
+			response.Error(c, http.StatusConflict, appErr.Message)
+		case "INTERNAL_ERROR":
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"api/internal/middleware"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RateLimitHandler struct {
+	rateLimiter *middleware.RateLimiter
+	allowlist   *middleware.Allowlist
+	limiters    []*middleware.Backpressure
+}
+
+func NewRateLimitHandler(rateLimiter *middleware.RateLimiter, allowlist *middleware.Allowlist, limiters ...*middleware.Backpressure) *RateLimitHandler {
+	return &RateLimitHandler{
+		rateLimiter: rateLimiter,
+		allowlist:   allowlist,
+		limiters:    limiters,
+	}
+}
+
+// GetLimits returns the caller's usage of each rate limit policy that applies to them, with
+// what remains and when it resets, so clients can throttle themselves before being refused
+func (h *RateLimitHandler) GetLimits(c *gin.Context) {
+	statuses := h.rateLimiter.Status(c)
+	limits := make([]response.RateLimitStatusResponse, len(statuses))
+	for i, status := range statuses {
+		limits[i] = response.RateLimitStatusResponse{
+			Scope:         status.Scope,
+			Limit:         status.Limit,
+			WindowSeconds: int(status.Window / time.Second),
+			Used:          status.Used,
+			Remaining:     status.Remaining,
+			ResetAt:       status.ResetAt,
+		}
+	}
+
+	response.JSON(c, http.StatusOK, response.RateLimitsResponse{
+		Allowlisted: h.rateLimiter.Allowlisted(c),
+		Limits:      limits,
+	})
+}
+
+// GetLoad returns the queue depth and shedding counters of the backpressure limiters (admin only)
+func (h *RateLimitHandler) GetLoad(c *gin.Context) {
+	loadResponses := make([]response.BackpressureResponse, len(h.limiters))
+	for i, limiter := range h.limiters {
+		stats := limiter.Stats()
+		loadResponses[i] = response.BackpressureResponse{
+			Name:        stats.Name,
+			Concurrency: stats.Concurrency,
+			InFlight:    stats.InFlight,
+			Waiting:     stats.Waiting,
+			MaxQueue:    stats.MaxQueue,
+			Admitted:    stats.Admitted,
+			Shed:        stats.Shed,
+			TimedOut:    stats.TimedOut,
+		}
+	}
+
+	response.JSON(c, http.StatusOK, loadResponses)
+}
+
+// ListAllowlist returns the IPs and users exempt from rate limiting (admin only)
+func (h *RateLimitHandler) ListAllowlist(c *gin.Context) {
+	entries, err := h.allowlist.Entries(context.Background())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	entryResponses := make([]response.AllowlistEntryResponse, len(entries))
+	for i, entry := range entries {
+		entryResponses[i] = toAllowlistEntryResponse(entry)
+	}
+
+	response.JSON(c, http.StatusOK, entryResponses)
+}
+
+// AddToAllowlist exempts an IP, CIDR range or user from rate limiting (admin only)
+func (h *RateLimitHandler) AddToAllowlist(c *gin.Context) {
+	var req request.RateLimitAllowlistRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	entry, err := h.allowlist.Add(context.Background(), req.Type, req.Value)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "allowlist entry added", toAllowlistEntryResponse(entry))
+}
+
+// RemoveFromAllowlist removes a runtime allowlist entry given as ?type=&value= (admin only)
+func (h *RateLimitHandler) RemoveFromAllowlist(c *gin.Context) {
+	var req request.RateLimitAllowlistRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+
+	if err := h.allowlist.Remove(context.Background(), req.Type, req.Value); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "allowlist entry removed", nil)
+}
+
+func toAllowlistEntryResponse(entry middleware.AllowlistEntry) response.AllowlistEntryResponse {
+	return response.AllowlistEntryResponse{
+		Type:   entry.Type,
+		Value:  entry.Value,
+		Source: entry.Source,
+	}
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *RateLimitHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		case "INTERNAL_ERROR":
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReferralHandler struct {
+	referralService services.ReferralServiceInterface
+}
+
+func NewReferralHandler(referralService services.ReferralServiceInterface) *ReferralHandler {
+	return &ReferralHandler{
+		referralService: referralService,
+	}
+}
+
+// GetDashboard returns the user's referral codes with their bookings and commissions
+func (h *ReferralHandler) GetDashboard(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	dashboard, err := h.referralService.GetDashboard(requestContext(c), userID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	resp := response.ReferrerDashboardResponse{
+		Referrers:   toReferrerSummaryResponses(dashboard.Referrers),
+		Commissions: make([]response.ReferralCommissionResponse, len(dashboard.Commissions)),
+	}
+	for i, commission := range dashboard.Commissions {
+		resp.Commissions[i] = response.ReferralCommissionResponse{
+			ID:            commission.ID,
+			ReferrerID:    commission.ReferrerID,
+			BookingID:     commission.BookingID,
+			BookingStatus: commission.Booking.Status,
+			EventID:       commission.EventID,
+			EventName:     commission.Event.Name,
+			Amount:        commission.Amount,
+			Rate:          commission.Rate,
+			Commission:    commission.Commission,
+			Status:        commission.Status,
+			PaidAt:        commission.PaidAt,
+			CreatedAt:     commission.CreatedAt,
+		}
+	}
+
+	response.JSON(c, http.StatusOK, resp)
+}
+
+// GetCode returns the user's own referral code, creating it on first use
+func (h *ReferralHandler) GetCode(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	referrer, err := h.referralService.GetUserCode(requestContext(c), userID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, toReferrerResponse(referrer))
+}
+
+// CreateReferrer sets up an affiliate partner (platform admin only)
+func (h *ReferralHandler) CreateReferrer(c *gin.Context) {
+	var req request.CreateReferrerRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	referrer, err := h.referralService.CreatePartner(requestContext(c), req.Name, req.Email, req.Code, req.CommissionRate, req.UserID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "referrer created successfully", toReferrerResponse(referrer))
+}
+
+// ListReferrers returns every referrer with their totals (platform admin only)
+func (h *ReferralHandler) ListReferrers(c *gin.Context) {
+	summaries, err := h.referralService.ListReferrers(requestContext(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, toReferrerSummaryResponses(summaries))
+}
+
+// UpdateReferrer changes a referrer's details, commission rate or whether its code still
+// earns (platform admin only)
+func (h *ReferralHandler) UpdateReferrer(c *gin.Context) {
+	referrerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid referrer ID")
+		return
+	}
+
+	var req request.UpdateReferrerRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		updates["name"] = strings.TrimSpace(*req.Name)
+	}
+	if req.Email != nil {
+		updates["email"] = strings.TrimSpace(*req.Email)
+	}
+	if req.CommissionRate != nil {
+		updates["commission_rate"] = *req.CommissionRate
+	}
+	if req.Active != nil {
+		updates["active"] = *req.Active
+	}
+	if len(updates) == 0 {
+		response.Error(c, http.StatusBadRequest, "no fields to update")
+		return
+	}
+
+	referrer, err := h.referralService.UpdateReferrer(requestContext(c), uint(referrerID), updates)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "referrer updated successfully", toReferrerResponse(referrer))
+}
+
+// GetPayouts returns the commission owed to each referrer (platform admin only)
+func (h *ReferralHandler) GetPayouts(c *gin.Context) {
+	payouts, err := h.referralService.GetPayouts(requestContext(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	payoutResponses := make([]response.ReferrerPayoutResponse, len(payouts))
+	for i := range payouts {
+		payoutResponses[i] = toReferrerPayoutResponse(&payouts[i])
+	}
+
+	response.JSON(c, http.StatusOK, payoutResponses)
+}
+
+// PayOut records that everything owed to a referrer was paid (platform admin only)
+func (h *ReferralHandler) PayOut(c *gin.Context) {
+	referrerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid referrer ID")
+		return
+	}
+
+	payout, err := h.referralService.PayOut(requestContext(c), uint(referrerID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "referrer paid out", toReferrerPayoutResponse(payout))
+}
+
+func toReferrerResponse(referrer *entities.Referrer) response.ReferrerResponse {
+	return response.ReferrerResponse{
+		ID:             referrer.ID,
+		Code:           referrer.Code,
+		Kind:           referrer.Kind,
+		Name:           referrer.Name,
+		Email:          referrer.Email,
+		UserID:         referrer.UserID,
+		CommissionRate: referrer.CommissionRate,
+		Active:         referrer.Active,
+		CreatedAt:      referrer.CreatedAt,
+	}
+}
+
+func toReferrerSummaryResponses(summaries []entities.ReferrerSummary) []response.ReferrerSummaryResponse {
+	summaryResponses := make([]response.ReferrerSummaryResponse, len(summaries))
+	for i, summary := range summaries {
+		summaryResponses[i] = response.ReferrerSummaryResponse{
+			ReferrerResponse: toReferrerResponse(&summary.Referrer),
+			Stats: response.ReferrerStatsResponse{
+				Bookings:          summary.Stats.Bookings,
+				Cancelled:         summary.Stats.Cancelled,
+				Revenue:           summary.Stats.Revenue,
+				CommissionPending: summary.Stats.CommissionPending,
+				CommissionPayable: summary.Stats.CommissionPayable,
+				CommissionPaid:    summary.Stats.CommissionPaid,
+			},
+		}
+	}
+	return summaryResponses
+}
+
+func toReferrerPayoutResponse(payout *entities.ReferrerPayout) response.ReferrerPayoutResponse {
+	return response.ReferrerPayoutResponse{
+		ReferrerID:  payout.ReferrerID,
+		Code:        payout.Code,
+		Name:        payout.Name,
+		Email:       payout.Email,
+		Commissions: payout.Commissions,
+		Amount:      payout.Amount,
+	}
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *ReferralHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		case "CONFLICT":
+			response.Error(c, http.StatusConflict, appErr.Message)
+		case "INTERNAL_ERROR":
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SaleRegionHandler struct {
+	saleRegionService services.SaleRegionServiceInterface
+}
+
+func NewSaleRegionHandler(saleRegionService services.SaleRegionServiceInterface) *SaleRegionHandler {
+	return &SaleRegionHandler{
+		saleRegionService: saleRegionService,
+	}
+}
+
+// ListOverrides returns the users allowed to book an event from outside its sale countries (admin only)
+func (h *SaleRegionHandler) ListOverrides(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	overrides, err := h.saleRegionService.ListOverrides(requestContext(c), uint(eventID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	overrideResponses := make([]response.SaleRegionOverrideResponse, len(overrides))
+	for i := range overrides {
+		overrideResponses[i] = toSaleRegionOverrideResponse(&overrides[i])
+	}
+	response.JSON(c, http.StatusOK, overrideResponses)
+}
+
+// AddOverride lets a user book an event from any country (admin only)
+func (h *SaleRegionHandler) AddOverride(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	var req request.AddSaleRegionOverrideRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	override, err := h.saleRegionService.AddOverride(requestContext(c), uint(eventID), req.UserID, req.Note, adminID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "sale region override added", toSaleRegionOverrideResponse(override))
+}
+
+// RemoveOverride takes a user off an event's sale region overrides (admin only)
+func (h *SaleRegionHandler) RemoveOverride(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	if err := h.saleRegionService.RemoveOverride(requestContext(c), uint(eventID), uint(userID)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "sale region override removed", nil)
+}
+
+func toSaleRegionOverrideResponse(override *entities.SaleRegionOverride) response.SaleRegionOverrideResponse {
+	return response.SaleRegionOverrideResponse{
+		EventID:   override.EventID,
+		UserID:    override.UserID,
+		Note:      override.Note,
+		CreatedBy: override.CreatedBy,
+		CreatedAt: override.CreatedAt,
+	}
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *SaleRegionHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		case "CONFLICT":
+			response.Error(c, http.StatusConflict, appErr.Message)
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
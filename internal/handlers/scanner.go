@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScannerHandler struct {
+	scannerService *services.ScannerService
+}
+
+func NewScannerHandler(scannerService *services.ScannerService) *ScannerHandler {
+	return &ScannerHandler{
+		scannerService: scannerService,
+	}
+}
+
+// RegisterDevice registers a door scanner at a gate of an event and returns its device token,
+// which isn't shown again (admin only)
+func (h *ScannerHandler) RegisterDevice(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	var req request.RegisterScannerRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	device, token, err := h.scannerService.RegisterDevice(requestContext(c), uint(eventID), req.Name, req.Gate, adminID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	resp := toScannerDeviceResponse(device)
+	resp.Token = token
+	response.Success(c, http.StatusCreated, "Scanner device registered", resp)
+}
+
+// ListDevices returns an event's scanner devices, revoked ones included (admin only)
+func (h *ScannerHandler) ListDevices(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	devices, err := h.scannerService.ListDevices(requestContext(c), uint(eventID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	resp := make([]response.ScannerDeviceResponse, 0, len(devices))
+	for i := range devices {
+		resp = append(resp, toScannerDeviceResponse(&devices[i]))
+	}
+	response.Success(c, http.StatusOK, "Scanner devices retrieved", resp)
+}
+
+// RevokeDevice stops a lost or retired scanner's token from working (admin only)
+func (h *ScannerHandler) RevokeDevice(c *gin.Context) {
+	deviceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid device ID")
+		return
+	}
+
+	device, err := h.scannerService.RevokeDevice(requestContext(c), uint(deviceID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Scanner device revoked", toScannerDeviceResponse(device))
+}
+
+// GetGateStats reports entry rates by gate, over the last "window" minutes (default 15), for
+// venue ops to watch during doors-open (admin only)
+func (h *ScannerHandler) GetGateStats(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	window := 0
+	if raw := c.Query("window"); raw != "" {
+		window, err = strconv.Atoi(raw)
+		if err != nil || window <= 0 {
+			response.Error(c, http.StatusBadRequest, "invalid window")
+			return
+		}
+	}
+
+	stats, err := h.scannerService.GateStats(requestContext(c), uint(eventID), window)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Gate stats retrieved", stats)
+}
+
+// CheckIn checks in the ticket the calling scanner just read (scanner devices only)
+func (h *ScannerHandler) CheckIn(c *gin.Context) {
+	device := c.MustGet("scanner_device").(*entities.ScannerDevice)
+
+	var req request.ScanTicketRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.scannerService.CheckIn(requestContext(c), device, req.Token)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Ticket scanned", toOfflineCheckInResult(result))
+}
+
+// SyncCheckIns records the tickets the calling scanner admitted while offline, and returns the
+// outcome of each scan in upload order (scanner devices only)
+func (h *ScannerHandler) SyncCheckIns(c *gin.Context) {
+	device := c.MustGet("scanner_device").(*entities.ScannerDevice)
+
+	var req request.SyncCheckInsRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	results, err := h.scannerService.SyncCheckIns(requestContext(c), device, toOfflineCheckIns(req.CheckIns))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	resp := make([]response.OfflineCheckInResult, 0, len(results))
+	for _, result := range results {
+		resp = append(resp, toOfflineCheckInResult(result))
+	}
+	response.Success(c, http.StatusOK, "Check-ins synced", resp)
+}
+
+func (h *ScannerHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "UNAUTHORIZED":
+			response.Error(c, http.StatusUnauthorized, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func toScannerDeviceResponse(device *entities.ScannerDevice) response.ScannerDeviceResponse {
+	return response.ScannerDeviceResponse{
+		ID:         device.ID,
+		EventID:    device.EventID,
+		Name:       device.Name,
+		Gate:       device.Gate,
+		CreatedBy:  device.CreatedBy,
+		CreatedAt:  device.CreatedAt,
+		LastSeenAt: device.LastSeenAt,
+		RevokedAt:  device.RevokedAt,
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	redisconn "api/internal/redis"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/response"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SeatBitmapHandler struct {
+	seatBitmapService services.SeatBitmapServiceInterface
+}
+
+func NewSeatBitmapHandler(seatBitmapService services.SeatBitmapServiceInterface) *SeatBitmapHandler {
+	return &SeatBitmapHandler{
+		seatBitmapService: seatBitmapService,
+	}
+}
+
+// GetBitmap returns an event's seat availability as a bitmap with its version. The version is
+// the ETag, so clients polling with If-None-Match get 304 until a seat changes.
+func (h *SeatBitmapHandler) GetBitmap(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	bitmap, err := h.seatBitmapService.GetBitmap(context.Background(), uint(eventID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	etag := `"` + strconv.FormatInt(bitmap.Version, 10) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, response.SeatBitmapResponse{
+		EventID:  bitmap.EventID,
+		Rows:     bitmap.Rows,
+		Columns:  bitmap.Columns,
+		Version:  bitmap.Version,
+		Encoding: "base64",
+		Bitmap:   base64.StdEncoding.EncodeToString(bitmap.Bits),
+	})
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *SeatBitmapHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else if redisconn.IsUnavailable(err) {
+		// Bitmaps only live in Redis; clients fall back to GET /events/:id/seats
+		response.Error(c, http.StatusServiceUnavailable, "seat bitmaps are unavailable")
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
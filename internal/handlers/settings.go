@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SettingsHandler struct {
+	settingsService services.SettingsServiceInterface
+}
+
+func NewSettingsHandler(settingsService services.SettingsServiceInterface) *SettingsHandler {
+	return &SettingsHandler{
+		settingsService: settingsService,
+	}
+}
+
+// ListSettings returns the runtime settings with their current values (platform admin only)
+func (h *SettingsHandler) ListSettings(c *gin.Context) {
+	settings, err := h.settingsService.ListSettings(requestContext(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	settingResponses := make([]response.SettingResponse, len(settings))
+	for i := range settings {
+		settingResponses[i] = toSettingResponse(&settings[i])
+	}
+
+	response.JSON(c, http.StatusOK, settingResponses)
+}
+
+// UpdateSetting overrides a runtime setting (platform admin only). Other instances pick the
+// change up once their cached settings expire.
+func (h *SettingsHandler) UpdateSetting(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req request.UpdateSettingRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	setting, err := h.settingsService.UpdateSetting(requestContext(c), c.Param("key"), req.Value, adminID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "setting updated", toSettingResponse(setting))
+}
+
+// ResetSetting removes a runtime setting's override so its configured default applies again
+// (platform admin only)
+func (h *SettingsHandler) ResetSetting(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	setting, err := h.settingsService.ResetSetting(requestContext(c), c.Param("key"), adminID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "setting reset to its default", toSettingResponse(setting))
+}
+
+// ListSettingChanges returns the audit log of runtime setting changes newest first, optionally
+// of one setting (platform admin only)
+func (h *SettingsHandler) ListSettingChanges(c *gin.Context) {
+	var req request.SettingChangeFilterRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+
+	changes, total, err := h.settingsService.ListSettingChanges(requestContext(c), req.Key, req.Limit, req.Offset())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	changeResponses := make([]response.SettingChangeResponse, len(changes))
+	for i, change := range changes {
+		changeResponses[i] = response.SettingChangeResponse{
+			ID:        change.ID,
+			Key:       change.Key,
+			OldValue:  change.OldValue,
+			NewValue:  change.NewValue,
+			ChangedBy: change.ChangedBy,
+			ChangedAt: change.ChangedAt,
+		}
+	}
+
+	response.Paginated(c, http.StatusOK, changeResponses, req.Page, req.Limit, total)
+}
+
+func toSettingResponse(setting *services.Setting) response.SettingResponse {
+	return response.SettingResponse{
+		Key:         setting.Key,
+		Kind:        setting.Kind,
+		Description: setting.Description,
+		Value:       setting.Value,
+		Default:     setting.Default,
+		Overridden:  setting.UpdatedAt != nil,
+		Min:         setting.Min,
+		Max:         setting.Max,
+		UpdatedBy:   setting.UpdatedBy,
+		UpdatedAt:   setting.UpdatedAt,
+	}
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *SettingsHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SettlementHandler struct {
+	settlementService services.SettlementServiceInterface
+}
+
+func NewSettlementHandler(settlementService services.SettlementServiceInterface) *SettlementHandler {
+	return &SettlementHandler{
+		settlementService: settlementService,
+	}
+}
+
+// GetSettlement returns an event's settlement, generated on demand until it is frozen (organizer admins)
+func (h *SettlementHandler) GetSettlement(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	settlement, err := h.settlementService.GetSettlement(requestContext(c), uint(eventID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, toSettlementResponse(settlement))
+}
+
+// FreezeSettlement fixes a completed event's settlement (organizer admins)
+func (h *SettlementHandler) FreezeSettlement(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	settlement, err := h.settlementService.FreezeSettlement(requestContext(c), uint(eventID), adminID.(uint))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "settlement frozen", toSettlementResponse(settlement))
+}
+
+func toSettlementResponse(settlement *entities.EventSettlement) response.SettlementResponse {
+	return response.SettlementResponse{
+		EventID:         settlement.EventID,
+		TicketsSold:     settlement.TicketsSold,
+		GrossSales:      settlement.GrossSales,
+		RefundedTickets: settlement.RefundedTickets,
+		Refunds:         settlement.Refunds,
+		Chargebacks:     settlement.Chargebacks,
+		CompTickets:     settlement.CompTickets,
+		FeePercent:      settlement.FeePercent,
+		FeePerTicket:    settlement.FeePerTicket,
+		PlatformFees:    settlement.PlatformFees,
+		NetPayable:      settlement.NetPayable,
+		Capacity:        settlement.Capacity,
+		HeldSeats:       settlement.HeldSeats,
+		SellableSeats:   settlement.SellableSeats,
+		SoldSeats:       settlement.SoldSeats,
+		Frozen:          settlement.FrozenAt != nil,
+		FrozenAt:        settlement.FrozenAt,
+		GeneratedAt:     settlement.CreatedAt,
+	}
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *SettlementHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		case "CONFLICT":
+			response.Error(c, http.StatusConflict, appErr.Message)
+		case "INTERNAL_ERROR":
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
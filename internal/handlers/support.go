@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SupportHandler struct {
+	supportService services.SupportServiceInterface
+}
+
+func NewSupportHandler(supportService services.SupportServiceInterface) *SupportHandler {
+	return &SupportHandler{
+		supportService: supportService,
+	}
+}
+
+// OpenCase opens a support case on one of the user's bookings
+func (h *SupportHandler) OpenCase(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid booking ID")
+		return
+	}
+
+	var req request.OpenSupportCaseRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	supportCase, err := h.supportService.OpenCase(context.Background(), userID.(uint), uint(bookingID), req.Category, req.Message)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Support case opened", toSupportCaseResponse(supportCase))
+}
+
+// ListUserCases returns the user's support cases, most recently updated first
+func (h *SupportHandler) ListUserCases(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req request.SupportCaseFilterRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+
+	cases, total, err := h.supportService.ListUserCases(context.Background(), userID.(uint), req.Status, req.Limit, req.Offset())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Paginated(c, http.StatusOK, toSupportCaseResponses(cases), req.Page, req.Limit, total)
+}
+
+// GetUserCase returns one of the user's support cases with its messages
+func (h *SupportHandler) GetUserCase(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	caseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid support case ID")
+		return
+	}
+
+	supportCase, err := h.supportService.GetUserCase(context.Background(), userID.(uint), uint(caseID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, toSupportCaseResponse(supportCase))
+}
+
+// ListCases returns the support cases of the organizer's events (admin only)
+func (h *SupportHandler) ListCases(c *gin.Context) {
+	var req request.SupportCaseFilterRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+
+	cases, total, err := h.supportService.ListCases(requestContext(c), req.Status, req.Category, req.Limit, req.Offset())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Paginated(c, http.StatusOK, toSupportCaseResponses(cases), req.Page, req.Limit, total)
+}
+
+// GetCase returns a support case with its messages (admin only)
+func (h *SupportHandler) GetCase(c *gin.Context) {
+	caseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid support case ID")
+		return
+	}
+
+	supportCase, err := h.supportService.GetCase(requestContext(c), uint(caseID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, toSupportCaseResponse(supportCase))
+}
+
+// Respond answers a support case or resolves it, emailing the user (admin only)
+func (h *SupportHandler) Respond(c *gin.Context) {
+	staffID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	caseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid support case ID")
+		return
+	}
+
+	var req request.RespondSupportCaseRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	supportCase, err := h.supportService.Respond(requestContext(c), uint(caseID), staffID.(uint), req.Message, req.Resolve)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Support case updated", toSupportCaseResponse(supportCase))
+}
+
+func toSupportCaseResponses(cases []entities.SupportCase) []response.SupportCaseResponse {
+	responses := make([]response.SupportCaseResponse, len(cases))
+	for i := range cases {
+		responses[i] = toSupportCaseResponse(&cases[i])
+	}
+	return responses
+}
+
+func toSupportCaseResponse(supportCase *entities.SupportCase) response.SupportCaseResponse {
+	var messages []response.SupportCaseMessageResponse
+	for _, message := range supportCase.Messages {
+		messages = append(messages, response.SupportCaseMessageResponse{
+			ID:        message.ID,
+			AuthorID:  message.AuthorID,
+			FromStaff: message.FromStaff,
+			Message:   message.Message,
+			CreatedAt: message.CreatedAt,
+		})
+	}
+
+	return response.SupportCaseResponse{
+		ID:         supportCase.ID,
+		BookingID:  supportCase.BookingID,
+		EventID:    supportCase.EventID,
+		UserID:     supportCase.UserID,
+		Category:   supportCase.Category,
+		Status:     supportCase.Status,
+		CreatedAt:  supportCase.CreatedAt,
+		UpdatedAt:  supportCase.UpdatedAt,
+		ResolvedAt: supportCase.ResolvedAt,
+		Messages:   messages,
+	}
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *SupportHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "UNAUTHORIZED":
+			response.Error(c, http.StatusUnauthorized, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		case "CONFLICT":
+			response.Error(c, http.StatusConflict, appErr.Message)
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
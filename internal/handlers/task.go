@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaskHandler struct {
+	taskService services.TaskServiceInterface
+}
+
+func NewTaskHandler(taskService services.TaskServiceInterface) *TaskHandler {
+	return &TaskHandler{
+		taskService: taskService,
+	}
+}
+
+// GetTask reports the status and progress of a background task (admin only)
+func (h *TaskHandler) GetTask(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+
+	task, err := h.taskService.GetTask(requestContext(c), uint(taskID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, toTaskResponse(task))
+}
+
+// ListTasks returns background tasks newest first, filtered by kind and status (admin only)
+func (h *TaskHandler) ListTasks(c *gin.Context) {
+	var req request.TaskFilterRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+
+	offset := req.Offset()
+	tasks, total, err := h.taskService.ListTasks(requestContext(c), req.Kind, req.Status, req.Limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	taskResponses := make([]response.TaskResponse, len(tasks))
+	for i := range tasks {
+		taskResponses[i] = toTaskResponse(&tasks[i])
+	}
+
+	response.Paginated(c, http.StatusOK, taskResponses, req.Page, req.Limit, total)
+}
+
+func toTaskResponse(task *entities.Task) response.TaskResponse {
+	percent := 0
+	if task.Status == constants.TaskStatusCompleted {
+		percent = 100
+	} else if task.Total > 0 {
+		percent = task.Progress * 100 / task.Total
+	}
+	return response.TaskResponse{
+		ID:          task.ID,
+		Kind:        task.Kind,
+		Status:      task.Status,
+		Progress:    task.Progress,
+		Total:       task.Total,
+		Percent:     percent,
+		Error:       task.Error,
+		Attempts:    task.Attempts,
+		MaxAttempts: task.MaxAttempts,
+		ResultID:    task.ResultID,
+		CreatedAt:   task.CreatedAt,
+		StartedAt:   task.StartedAt,
+		CompletedAt: task.CompletedAt,
+	}
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *TaskHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		case "INTERNAL_ERROR":
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"api/internal/tenant"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/pkg/response"
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TenantHandler struct {
+	tenantService services.TenantServiceInterface
+}
+
+func NewTenantHandler(tenantService services.TenantServiceInterface) *TenantHandler {
+	return &TenantHandler{
+		tenantService: tenantService,
+	}
+}
+
+// requestContext scopes admin requests from tenant admins to their tenant, so repositories
+// only return and change that tenant's venues, events and bookings
+func requestContext(c *gin.Context) context.Context {
+	ctx := context.Background()
+	if tenantID, exists := c.Get("tenant_id"); exists {
+		ctx = tenant.WithTenant(ctx, tenantID.(uint))
+	}
+	return ctx
+}
+
+// CreateTenant registers a new organizer tenant (platform admin only)
+func (h *TenantHandler) CreateTenant(c *gin.Context) {
+	var req request.CreateTenantRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	t := &entities.Tenant{
+		Name:      req.Name,
+		Slug:      req.Slug,
+		RateLimit: req.RateLimit,
+	}
+	if err := h.tenantService.CreateTenant(context.Background(), t); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "tenant created successfully", toTenantResponse(t))
+}
+
+// ListTenants returns every tenant (platform admin only)
+func (h *TenantHandler) ListTenants(c *gin.Context) {
+	tenants, err := h.tenantService.ListTenants(context.Background())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	tenantResponses := make([]response.TenantResponse, len(tenants))
+	for i := range tenants {
+		tenantResponses[i] = toTenantResponse(&tenants[i])
+	}
+
+	response.JSON(c, http.StatusOK, tenantResponses)
+}
+
+// UpdateTenant changes a tenant's name or admin rate limit (platform admin only)
+func (h *TenantHandler) UpdateTenant(c *gin.Context) {
+	tenantID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid tenant ID")
+		return
+	}
+
+	var req request.UpdateTenantRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.RateLimit != nil {
+		updates["rate_limit"] = *req.RateLimit
+	}
+	if len(updates) == 0 {
+		response.Error(c, http.StatusBadRequest, "no fields to update")
+		return
+	}
+
+	t, err := h.tenantService.UpdateTenant(context.Background(), uint(tenantID), updates)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "tenant updated successfully", toTenantResponse(t))
+}
+
+// AssignAdmin scopes an admin to a tenant, or makes them a platform admin (platform admin only)
+func (h *TenantHandler) AssignAdmin(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	var req request.AssignTenantRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	user, err := h.tenantService.AssignAdmin(context.Background(), uint(userID), req.TenantID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "admin tenant updated, effective from the next login", response.UserResponse{
+		ID:             user.ID,
+		Email:          user.Email,
+		FirstName:      user.FirstName,
+		LastName:       user.LastName,
+		Phone:          user.Phone,
+		IsAdmin:        user.IsAdmin,
+		MembershipTier: user.MembershipTier,
+		TenantID:       user.TenantID,
+	})
+}
+
+// GetOrganizerPage returns an organizer's landing page by tenant ID or storefront slug: the
+// branding profile, upcoming events and ratings
+func (h *TenantHandler) GetOrganizerPage(c *gin.Context) {
+	page, err := h.tenantService.GetOrganizerPage(context.Background(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	events := make([]response.OrganizerEventResponse, len(page.UpcomingEvents))
+	for i, event := range page.UpcomingEvents {
+		events[i] = response.OrganizerEventResponse{
+			ID:   event.ID,
+			Name: event.Name,
+			Venue: response.VenueResponse{
+				ID:          event.Venue.ID,
+				Name:        event.Venue.Name,
+				Address:     event.Venue.Address,
+				City:        event.Venue.City,
+				State:       event.Venue.State,
+				Country:     event.Venue.Country,
+				Rows:        event.Venue.Rows,
+				Columns:     event.Venue.Columns,
+				Capacity:    event.Venue.Rows * event.Venue.Columns,
+				Description: event.Venue.Description,
+			},
+			StartTime:    event.StartTime,
+			EndTime:      event.EndTime,
+			Price:        event.Price,
+			EventType:    event.EventType,
+			Status:       event.Status,
+			IsHighDemand: event.IsHighDemand,
+		}
+	}
+
+	// Pages are cached for a minute on the server as well
+	c.Header("Cache-Control", "public, max-age=60")
+	response.JSON(c, http.StatusOK, response.OrganizerPageResponse{
+		OrganizerResponse: *toOrganizerResponse(page.Organizer),
+		UpcomingEvents:    events,
+		Ratings: response.OrganizerRatingsResponse{
+			Average: page.Ratings.Average,
+			Count:   page.Ratings.Count,
+		},
+	})
+}
+
+// GetOrganizerProfile returns a tenant's branding (admin only, tenant admins their own tenant)
+func (h *TenantHandler) GetOrganizerProfile(c *gin.Context) {
+	tenantID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid tenant ID")
+		return
+	}
+
+	t, err := h.tenantService.GetBranding(requestContext(c), uint(tenantID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, toOrganizerResponse(t))
+}
+
+// UpdateOrganizerProfile changes a tenant's branding (admin only, tenant admins their own tenant)
+func (h *TenantHandler) UpdateOrganizerProfile(c *gin.Context) {
+	tenantID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid tenant ID")
+		return
+	}
+
+	var req request.UpdateOrganizerProfileRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.DisplayName != nil {
+		updates["display_name"] = *req.DisplayName
+	}
+	if req.LogoURL != nil {
+		updates["logo_url"] = *req.LogoURL
+	}
+	if req.SupportEmail != nil {
+		updates["support_email"] = *req.SupportEmail
+	}
+	if req.PrimaryColor != nil {
+		updates["primary_color"] = *req.PrimaryColor
+	}
+	if req.SecondaryColor != nil {
+		updates["secondary_color"] = *req.SecondaryColor
+	}
+	if req.AccentColor != nil {
+		updates["accent_color"] = *req.AccentColor
+	}
+	if len(updates) == 0 {
+		response.Error(c, http.StatusBadRequest, "no fields to update")
+		return
+	}
+
+	t, err := h.tenantService.UpdateBranding(requestContext(c), uint(tenantID), updates)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "organizer profile updated successfully", toOrganizerResponse(t))
+}
+
+func toTenantResponse(t *entities.Tenant) response.TenantResponse {
+	return response.TenantResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		Slug:      t.Slug,
+		RateLimit: t.RateLimit,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+// toOrganizerResponse returns nil when the tenant wasn't loaded, so the organizer is left out
+func toOrganizerResponse(t *entities.Tenant) *response.OrganizerResponse {
+	if t == nil || t.ID == 0 {
+		return nil
+	}
+	displayName := t.DisplayName
+	if displayName == "" {
+		displayName = t.Name
+	}
+	return &response.OrganizerResponse{
+		ID:           t.ID,
+		Slug:         t.Slug,
+		DisplayName:  displayName,
+		LogoURL:      t.LogoURL,
+		SupportEmail: t.SupportEmail,
+		Colors: response.OrganizerColors{
+			Primary:   t.PrimaryColor,
+			Secondary: t.SecondaryColor,
+			Accent:    t.AccentColor,
+		},
+	}
+}
+
+// handleError converts application errors to appropriate HTTP responses
+func (h *TenantHandler) handleError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		switch appErr.Type {
+		case "BAD_REQUEST":
+			response.Error(c, http.StatusBadRequest, appErr.Message)
+		case "NOT_FOUND":
+			response.Error(c, http.StatusNotFound, appErr.Message)
+		case "CONFLICT":
+			response.Error(c, http.StatusConflict, appErr.Message)
+		case "INTERNAL_ERROR":
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		default:
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+		}
+	} else {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+	}
+}
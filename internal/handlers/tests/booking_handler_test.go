@@ -1,15 +1,24 @@
 package tests
 
 import (
+	"api/constants"
 	"api/internal/entities"
+	"api/internal/expand"
+	"api/internal/geoip"
 	"api/internal/handlers"
+	"api/internal/middleware"
+	"api/internal/services"
+	"api/internal/tickets"
 	"api/pkg/errors"
+	"api/pkg/money"
 	"api/pkg/request"
 	"api/test"
 	"api/test/mocks"
+	"context"
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -39,9 +48,16 @@ func (suite *BookingHandlerTestSuite) SetupTest() {
 		protected.POST("/booking-intents", suite.handler.CreateBookingIntent)
 		protected.POST("/bookings/confirm", suite.handler.ConfirmBooking)
 		protected.POST("/booking-intents/cancel", suite.handler.CancelBookingIntent)
+		protected.POST("/booking-intents/:id/heartbeat", suite.handler.HeartbeatBookingIntent)
+		protected.GET("/booking-intents/:id/status", suite.handler.GetBookingIntentStatus)
+		protected.POST("/booking-intents/:id/retry-payment", suite.handler.RetryPayment)
+		protected.POST("/booking-intents/:id/resume-token", suite.handler.IssueResumeToken)
+		protected.POST("/booking-intents/resume", suite.handler.ResumeBookingIntent)
 		protected.DELETE("/bookings/:id", suite.handler.CancelBooking)
 		protected.GET("/bookings", suite.handler.GetUserBookings)
+		protected.GET("/bookings/upcoming", suite.handler.GetUpcomingBookings)
 		protected.GET("/bookings/:id", suite.handler.GetBookingByID)
+		protected.GET("/bookings/:id/ticket", suite.handler.GetTicket)
 	}
 }
 
@@ -65,6 +81,7 @@ func (suite *BookingHandlerTestSuite) TestCreateBookingIntent_Success() {
 		mock.Anything,
 		uint(1),
 		uint(1),
+		entities.BookingIntentOptions{},
 	).Return(mockIntent, nil)
 
 	reqBody := request.CreateBookingIntentRequest{
@@ -84,12 +101,70 @@ func (suite *BookingHandlerTestSuite) TestCreateBookingIntent_Success() {
 	assert.NotNil(suite.T(), response["data"])
 }
 
+// Test CreateBookingIntent - UTM parameters and referral codes are passed on as attribution
+func (suite *BookingHandlerTestSuite) TestCreateBookingIntent_Attribution() {
+	suite.bookingService.On("CreateBookingIntent",
+		mock.Anything,
+		uint(1),
+		uint(1),
+		entities.BookingIntentOptions{Attribution: entities.Attribution{
+			UTMSource:    "newsletter",
+			UTMMedium:    "email",
+			UTMCampaign:  "spring-tour",
+			ReferralCode: "FRIEND10",
+		}},
+	).Return(suite.mockEntities.GetMockBookingIntent(), nil)
+
+	reqBody := request.CreateBookingIntentRequest{
+		SeatID:       1,
+		UTMSource:    "newsletter",
+		UTMMedium:    "email",
+		UTMCampaign:  "spring-tour",
+		ReferralCode: "FRIEND10",
+	}
+
+	req, _ := test.CreateTestRequest("POST", "/api/booking-intents", reqBody)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, w.Code)
+}
+
+// Test CreateBookingIntent - free events come back confirmed with their booking
+func (suite *BookingHandlerTestSuite) TestCreateBookingIntent_FreeEventBooked() {
+	intent := suite.mockEntities.GetMockBookingIntent()
+	intent.Status = constants.IntentStatusConfirmed
+	intent.Booking = suite.mockEntities.GetMockBooking()
+	suite.bookingService.On("CreateBookingIntent",
+		mock.Anything,
+		uint(1),
+		uint(1),
+		entities.BookingIntentOptions{Attendee: entities.AttendeeDetails{FullName: "Ada Lovelace"}},
+	).Return(intent, nil)
+
+	reqBody := request.CreateBookingIntentRequest{
+		SeatID:   1,
+		Attendee: &request.AttendeeRequest{FullName: "Ada Lovelace"},
+	}
+
+	req, _ := test.CreateTestRequest("POST", "/api/booking-intents", reqBody)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &response))
+	data := response["data"].(map[string]interface{})
+	assert.Equal(suite.T(), constants.IntentStatusConfirmed, data["status"])
+	assert.NotNil(suite.T(), data["booking"])
+}
+
 // Test CreateBookingIntent - Seat not available
 func (suite *BookingHandlerTestSuite) TestCreateBookingIntent_SeatNotAvailable() {
 	suite.bookingService.On("CreateBookingIntent",
 		mock.Anything,
 		uint(1),
 		uint(1),
+		entities.BookingIntentOptions{},
 	).Return(nil, errors.NewConflictError("Seat is not available", nil))
 
 	reqBody := request.CreateBookingIntentRequest{
@@ -107,12 +182,133 @@ func (suite *BookingHandlerTestSuite) TestCreateBookingIntent_SeatNotAvailable()
 	assert.Equal(suite.T(), "Seat is not available", response["error"])
 }
 
+// Test CreateBookingIntent - Presale code is passed through
+func (suite *BookingHandlerTestSuite) TestCreateBookingIntent_WithPresaleCode() {
+	mockIntent := suite.mockEntities.GetMockBookingIntent()
+
+	suite.bookingService.On("CreateBookingIntent",
+		mock.Anything,
+		uint(1),
+		uint(1),
+		entities.BookingIntentOptions{PresaleCode: "ABCD2345EF"},
+	).Return(mockIntent, nil)
+
+	reqBody := request.CreateBookingIntentRequest{
+		SeatID:      1,
+		PresaleCode: "ABCD2345EF",
+	}
+
+	req, _ := test.CreateTestRequest("POST", "/api/booking-intents", reqBody)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, w.Code)
+}
+
+// Test CreateBookingIntent - Booking before the on-sale without access is rejected
+func (suite *BookingHandlerTestSuite) TestCreateBookingIntent_NotOnSale() {
+	suite.bookingService.On("CreateBookingIntent",
+		mock.Anything,
+		uint(1),
+		uint(1),
+		entities.BookingIntentOptions{PresaleCode: "WRONGCODE1"},
+	).Return(nil, errors.NewBadRequestError("invalid presale code", nil))
+
+	reqBody := request.CreateBookingIntentRequest{
+		SeatID:      1,
+		PresaleCode: "WRONGCODE1",
+	}
+
+	req, _ := test.CreateTestRequest("POST", "/api/booking-intents", reqBody)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "invalid presale code", response["error"])
+}
+
+// Test CreateBookingIntent - Terms acceptance is passed through
+func (suite *BookingHandlerTestSuite) TestCreateBookingIntent_AcceptTerms() {
+	mockIntent := suite.mockEntities.GetMockBookingIntent()
+
+	suite.bookingService.On("CreateBookingIntent",
+		mock.Anything,
+		uint(1),
+		uint(1),
+		entities.BookingIntentOptions{AcceptTerms: true, TermsVersion: "2024-01"},
+	).Return(mockIntent, nil)
+
+	reqBody := request.CreateBookingIntentRequest{
+		SeatID:       1,
+		AcceptTerms:  true,
+		TermsVersion: "2024-01",
+	}
+
+	req, _ := test.CreateTestRequest("POST", "/api/booking-intents", reqBody)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, w.Code)
+}
+
+// Test CreateBookingIntent - Booking without accepting the event's terms is rejected
+func (suite *BookingHandlerTestSuite) TestCreateBookingIntent_TermsNotAccepted() {
+	suite.bookingService.On("CreateBookingIntent",
+		mock.Anything,
+		uint(1),
+		uint(1),
+		entities.BookingIntentOptions{},
+	).Return(nil, errors.NewBadRequestError("you must accept the event's terms and conditions", nil))
+
+	reqBody := request.CreateBookingIntentRequest{
+		SeatID: 1,
+	}
+
+	req, _ := test.CreateTestRequest("POST", "/api/booking-intents", reqBody)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "you must accept the event's terms and conditions", response["error"])
+}
+
+// Test CreateBookingIntent - Clients outside an event's sale countries get a coded 403
+func (suite *BookingHandlerTestSuite) TestCreateBookingIntent_SaleRegionNotAllowed() {
+	locator, err := geoip.NewHeaderLocator("CF-IPCountry")
+	suite.Require().NoError(err)
+	suite.router.POST("/api/located/booking-intents", suite.mockAuthMiddleware(), middleware.GeoIP(locator), suite.handler.CreateBookingIntent)
+
+	suite.bookingService.On("CreateBookingIntent",
+		mock.Anything,
+		uint(1),
+		uint(1),
+		entities.BookingIntentOptions{Country: "FR"},
+	).Return(nil, errors.NewForbiddenError(constants.ErrSaleRegionNotAllowed, nil).WithCode(constants.CodeSaleRegionNotAllowed))
+
+	req, _ := test.CreateTestRequest("POST", "/api/located/booking-intents", request.CreateBookingIntentRequest{SeatID: 1})
+	req.Header.Set("CF-IPCountry", "fr")
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), constants.CodeSaleRegionNotAllowed, response["code"])
+	assert.Equal(suite.T(), constants.ErrSaleRegionNotAllowed, response["error"])
+}
+
 // Test CreateBookingIntent - Seat not found
 func (suite *BookingHandlerTestSuite) TestCreateBookingIntent_SeatNotFound() {
 	suite.bookingService.On("CreateBookingIntent",
 		mock.Anything,
 		uint(1),
 		uint(999),
+		entities.BookingIntentOptions{},
 	).Return(nil, errors.NewNotFoundError("Seat not found", nil))
 
 	reqBody := request.CreateBookingIntentRequest{
@@ -137,7 +333,9 @@ func (suite *BookingHandlerTestSuite) TestConfirmBooking_Success() {
 	suite.bookingService.On("ConfirmBooking",
 		mock.Anything,
 		uint(1),
-		"pay_test123",
+		entities.PaymentDetails{PaymentID: "pay_test123"},
+		entities.AttendeeDetails{},
+		entities.ConfirmOptions{},
 	).Return(mockBooking, nil)
 
 	reqBody := request.ConfirmBookingRequest{
@@ -163,7 +361,9 @@ func (suite *BookingHandlerTestSuite) TestConfirmBooking_IntentNotFound() {
 	suite.bookingService.On("ConfirmBooking",
 		mock.Anything,
 		uint(999),
-		"pay_test123",
+		entities.PaymentDetails{PaymentID: "pay_test123"},
+		entities.AttendeeDetails{},
+		entities.ConfirmOptions{},
 	).Return(nil, errors.NewNotFoundError("Booking intent not found", nil))
 
 	reqBody := request.ConfirmBookingRequest{
@@ -187,7 +387,9 @@ func (suite *BookingHandlerTestSuite) TestConfirmBooking_IntentExpired() {
 	suite.bookingService.On("ConfirmBooking",
 		mock.Anything,
 		uint(1),
-		"pay_test123",
+		entities.PaymentDetails{PaymentID: "pay_test123"},
+		entities.AttendeeDetails{},
+		entities.ConfirmOptions{},
 	).Return(nil, errors.NewBadRequestError("Booking intent has expired", nil))
 
 	reqBody := request.ConfirmBookingRequest{
@@ -206,6 +408,173 @@ func (suite *BookingHandlerTestSuite) TestConfirmBooking_IntentExpired() {
 	assert.Equal(suite.T(), "Booking intent has expired", response["error"])
 }
 
+// Test ConfirmBooking - Payment method details are passed through masked
+func (suite *BookingHandlerTestSuite) TestConfirmBooking_WithPaymentMethod() {
+	mockBooking := suite.mockEntities.GetMockBooking()
+
+	suite.bookingService.On("ConfirmBooking",
+		mock.Anything,
+		uint(1),
+		entities.PaymentDetails{
+			PaymentID:  "pay_test123",
+			Provider:   "stripe",
+			Currency:   "EUR",
+			MethodType: "card",
+			CardBrand:  "visa",
+			CardLast4:  "4242",
+		},
+		entities.AttendeeDetails{},
+		entities.ConfirmOptions{},
+	).Return(mockBooking, nil)
+
+	reqBody := request.ConfirmBookingRequest{
+		BookingIntentID: 1,
+		PaymentID:       "pay_test123",
+		Provider:        "stripe",
+		Currency:        "EUR",
+		PaymentMethod: &request.PaymentMethodRequest{
+			Type:      "card",
+			CardBrand: "visa",
+			CardLast4: "4242",
+		},
+	}
+
+	req, _ := test.CreateTestRequest("POST", "/api/bookings/confirm", reqBody)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+}
+
+// Test ConfirmBooking - Full card numbers are rejected
+func (suite *BookingHandlerTestSuite) TestConfirmBooking_RejectsCardNumber() {
+	reqBody := request.ConfirmBookingRequest{
+		BookingIntentID: 1,
+		PaymentID:       "pay_test123",
+		PaymentMethod: &request.PaymentMethodRequest{
+			Type:      "card",
+			CardLast4: "4242424242424242",
+		},
+	}
+
+	req, _ := test.CreateTestRequest("POST", "/api/bookings/confirm", reqBody)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.bookingService.AssertNotCalled(suite.T(), "ConfirmBooking", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test ConfirmBooking - Loyalty points to redeem are passed through
+func (suite *BookingHandlerTestSuite) TestConfirmBooking_RedeemPoints() {
+	mockBooking := suite.mockEntities.GetMockBooking()
+	mockBooking.DiscountAmount = money.FromMajor(5)
+	mockBooking.PointsRedeemed = 500
+
+	suite.bookingService.On("ConfirmBooking",
+		mock.Anything,
+		uint(1),
+		entities.PaymentDetails{PaymentID: "pay_test123", RedeemPoints: 500},
+		entities.AttendeeDetails{},
+		entities.ConfirmOptions{},
+	).Return(mockBooking, nil)
+
+	reqBody := request.ConfirmBookingRequest{
+		BookingIntentID: 1,
+		PaymentID:       "pay_test123",
+		RedeemPoints:    500,
+	}
+
+	req, _ := test.CreateTestRequest("POST", "/api/bookings/confirm", reqBody)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"points_redeemed":500`)
+}
+
+// Test ConfirmBooking - Negative point redemptions are rejected
+func (suite *BookingHandlerTestSuite) TestConfirmBooking_RejectsNegativeRedeemPoints() {
+	reqBody := request.ConfirmBookingRequest{
+		BookingIntentID: 1,
+		PaymentID:       "pay_test123",
+		RedeemPoints:    -10,
+	}
+
+	req, _ := test.CreateTestRequest("POST", "/api/bookings/confirm", reqBody)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.bookingService.AssertNotCalled(suite.T(), "ConfirmBooking", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test ConfirmBooking - Attendee details are passed through with a parsed date of birth
+func (suite *BookingHandlerTestSuite) TestConfirmBooking_WithAttendeeDetails() {
+	mockBooking := suite.mockEntities.GetMockBooking()
+	dateOfBirth := time.Date(1990, time.May, 17, 0, 0, 0, 0, time.UTC)
+
+	suite.bookingService.On("ConfirmBooking",
+		mock.Anything,
+		uint(1),
+		entities.PaymentDetails{PaymentID: "pay_test123"},
+		entities.AttendeeDetails{FullName: "Jane Doe", DateOfBirth: &dateOfBirth, IDNumber: "X1234567"},
+		entities.ConfirmOptions{},
+	).Return(mockBooking, nil)
+
+	reqBody := request.ConfirmBookingRequest{
+		BookingIntentID: 1,
+		PaymentID:       "pay_test123",
+		Attendee: &request.AttendeeRequest{
+			FullName:    "Jane Doe",
+			DateOfBirth: "1990-05-17",
+			IDNumber:    "X1234567",
+		},
+	}
+
+	req, _ := test.CreateTestRequest("POST", "/api/bookings/confirm", reqBody)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+}
+
+// Test ConfirmBooking - Releasing the user's other intents is passed through
+func (suite *BookingHandlerTestSuite) TestConfirmBooking_ReleaseOtherIntents() {
+	mockBooking := suite.mockEntities.GetMockBooking()
+
+	suite.bookingService.On("ConfirmBooking",
+		mock.Anything,
+		uint(1),
+		entities.PaymentDetails{PaymentID: "pay_test123"},
+		entities.AttendeeDetails{},
+		entities.ConfirmOptions{ReleaseOtherIntents: true},
+	).Return(mockBooking, nil)
+
+	reqBody := request.ConfirmBookingRequest{
+		BookingIntentID:     1,
+		PaymentID:           "pay_test123",
+		ReleaseOtherIntents: true,
+	}
+
+	req, _ := test.CreateTestRequest("POST", "/api/bookings/confirm", reqBody)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+}
+
+// Test ConfirmBooking - Malformed dates of birth are rejected
+func (suite *BookingHandlerTestSuite) TestConfirmBooking_RejectsInvalidDateOfBirth() {
+	reqBody := request.ConfirmBookingRequest{
+		BookingIntentID: 1,
+		PaymentID:       "pay_test123",
+		Attendee: &request.AttendeeRequest{
+			DateOfBirth: "17/05/1990",
+		},
+	}
+
+	req, _ := test.CreateTestRequest("POST", "/api/bookings/confirm", reqBody)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.bookingService.AssertNotCalled(suite.T(), "ConfirmBooking", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 // Test CancelBookingIntent - Success
 func (suite *BookingHandlerTestSuite) TestCancelBookingIntent_Success() {
 	suite.bookingService.On("CancelBookingIntent",
@@ -252,6 +621,223 @@ func (suite *BookingHandlerTestSuite) TestCancelBookingIntent_NotFound() {
 	assert.Equal(suite.T(), "Booking intent not found", response["error"])
 }
 
+// Test HeartbeatBookingIntent - Success
+func (suite *BookingHandlerTestSuite) TestHeartbeatBookingIntent_Success() {
+	mockIntent := suite.mockEntities.GetMockBookingIntent()
+
+	suite.bookingService.On("HeartbeatBookingIntent",
+		mock.Anything,
+		uint(1),
+		uint(1),
+	).Return(mockIntent, nil)
+
+	req, _ := test.CreateTestRequest("POST", "/api/booking-intents/1/heartbeat", nil)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "heartbeat recorded", response["message"])
+
+	data := response["data"].(map[string]interface{})
+	assert.Equal(suite.T(), float64(1), data["booking_intent_id"])
+	assert.Equal(suite.T(), float64(45), data["heartbeat_timeout_seconds"])
+}
+
+// Test HeartbeatBookingIntent - Intent expired
+func (suite *BookingHandlerTestSuite) TestHeartbeatBookingIntent_Expired() {
+	suite.bookingService.On("HeartbeatBookingIntent",
+		mock.Anything,
+		uint(1),
+		uint(1),
+	).Return(nil, errors.NewBadRequestError("booking intent has expired", nil))
+
+	req, _ := test.CreateTestRequest("POST", "/api/booking-intents/1/heartbeat", nil)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+}
+
+// Test HeartbeatBookingIntent - Invalid ID
+func (suite *BookingHandlerTestSuite) TestHeartbeatBookingIntent_InvalidID() {
+	req, _ := test.CreateTestRequest("POST", "/api/booking-intents/abc/heartbeat", nil)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+}
+
+// Test IssueResumeToken - Success
+func (suite *BookingHandlerTestSuite) TestIssueResumeToken_Success() {
+	expiresAt := time.Now().Add(2 * time.Minute)
+
+	suite.bookingService.On("IssueResumeToken",
+		mock.Anything,
+		uint(1),
+		uint(1),
+	).Return("resume_abc", expiresAt, nil)
+
+	req, _ := test.CreateTestRequest("POST", "/api/booking-intents/1/resume-token", nil)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(suite.T(), err)
+
+	data := response["data"].(map[string]interface{})
+	assert.Equal(suite.T(), float64(1), data["booking_intent_id"])
+	assert.Equal(suite.T(), "resume_abc", data["resume_token"])
+}
+
+// Test ResumeBookingIntent - Success
+func (suite *BookingHandlerTestSuite) TestResumeBookingIntent_Success() {
+	mockIntent := suite.mockEntities.GetMockBookingIntent()
+
+	suite.bookingService.On("ResumeBookingIntent",
+		mock.Anything,
+		uint(1),
+		"resume_abc",
+	).Return(mockIntent, nil)
+
+	reqBody := request.ResumeBookingIntentRequest{ResumeToken: "resume_abc"}
+	req, _ := test.CreateTestRequest("POST", "/api/booking-intents/resume", reqBody)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "booking intent resumed", response["message"])
+
+	data := response["data"].(map[string]interface{})
+	assert.Equal(suite.T(), float64(mockIntent.ID), data["id"])
+}
+
+// Test ResumeBookingIntent - Seat hold lost
+func (suite *BookingHandlerTestSuite) TestResumeBookingIntent_SeatHoldLost() {
+	suite.bookingService.On("ResumeBookingIntent",
+		mock.Anything,
+		uint(1),
+		"resume_abc",
+	).Return(nil, errors.NewConflictError(constants.ErrSeatHoldLost, nil))
+
+	reqBody := request.ResumeBookingIntentRequest{ResumeToken: "resume_abc"}
+	req, _ := test.CreateTestRequest("POST", "/api/booking-intents/resume", reqBody)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+}
+
+// Test ResumeBookingIntent - Missing token
+func (suite *BookingHandlerTestSuite) TestResumeBookingIntent_MissingToken() {
+	req, _ := test.CreateTestRequest("POST", "/api/booking-intents/resume", request.ResumeBookingIntentRequest{})
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.bookingService.AssertNotCalled(suite.T(), "ResumeBookingIntent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test GetBookingIntentStatus - Success
+func (suite *BookingHandlerTestSuite) TestGetBookingIntentStatus_Success() {
+	expiresAt := time.Now().Add(5 * time.Minute)
+	status := &services.BookingIntentStatus{
+		BookingIntentID:  1,
+		Status:           "pending",
+		PaymentStatus:    "pending",
+		ExpiresAt:        &expiresAt,
+		RemainingSeconds: 300,
+	}
+
+	suite.bookingService.On("GetBookingIntentStatus",
+		mock.Anything,
+		uint(1),
+		uint(1),
+	).Return(status, nil)
+
+	req, _ := test.CreateTestRequest("GET", "/api/booking-intents/1/status", nil)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "pending", response["status"])
+	assert.Equal(suite.T(), float64(300), response["remaining_seconds"])
+}
+
+// Test GetBookingIntentStatus - Not found
+func (suite *BookingHandlerTestSuite) TestGetBookingIntentStatus_NotFound() {
+	suite.bookingService.On("GetBookingIntentStatus",
+		mock.Anything,
+		uint(999),
+		uint(1),
+	).Return(nil, errors.NewNotFoundError("Booking intent not found", nil))
+
+	req, _ := test.CreateTestRequest("GET", "/api/booking-intents/999/status", nil)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+// Test RetryPayment - Success
+func (suite *BookingHandlerTestSuite) TestRetryPayment_Success() {
+	mockIntent := suite.mockEntities.GetMockBookingIntent()
+	mockIntent.PaymentIntentID = "pay_retry456"
+	mockIntent.PaymentAttempts = []entities.PaymentAttempt{
+		{BookingIntentID: 1, AttemptNumber: 1, Reference: "pi_test123", Status: "failed", FailureReason: "card_declined"},
+		{BookingIntentID: 1, AttemptNumber: 2, Reference: "pay_retry456", Status: "pending"},
+	}
+
+	suite.bookingService.On("RetryPayment",
+		mock.Anything,
+		uint(1),
+		uint(1),
+		"card_declined",
+	).Return(mockIntent, nil)
+
+	reqBody := request.RetryPaymentRequest{
+		FailureReason: "card_declined",
+	}
+
+	req, _ := test.CreateTestRequest("POST", "/api/booking-intents/1/retry-payment", reqBody)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(suite.T(), err)
+
+	data := response["data"].(map[string]interface{})
+	assert.Equal(suite.T(), "pay_retry456", data["payment_reference"])
+	assert.Len(suite.T(), data["attempts"], 2)
+}
+
+// Test RetryPayment - Attempt limit reached
+func (suite *BookingHandlerTestSuite) TestRetryPayment_AttemptLimit() {
+	suite.bookingService.On("RetryPayment",
+		mock.Anything,
+		uint(1),
+		uint(1),
+		"",
+	).Return(nil, errors.NewBadRequestError("maximum number of payment attempts reached", nil))
+
+	req, _ := test.CreateTestRequest("POST", "/api/booking-intents/1/retry-payment", nil)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "maximum number of payment attempts reached", response["error"])
+}
+
 // Test CancelBooking - Success
 func (suite *BookingHandlerTestSuite) TestCancelBooking_Success() {
 	suite.bookingService.On("CancelBooking",
@@ -297,6 +883,7 @@ func (suite *BookingHandlerTestSuite) TestGetUserBookings_Success() {
 	suite.bookingService.On("GetUserBookings",
 		mock.Anything,
 		uint(1),
+		entities.BookingFilter{},
 		10,
 		0,
 	).Return(mockBookings, int64(1), nil)
@@ -314,11 +901,123 @@ func (suite *BookingHandlerTestSuite) TestGetUserBookings_Success() {
 	assert.Equal(suite.T(), float64(1), response["total"])
 }
 
+// Test GetUserBookings - Pagination metadata links the neighbouring pages
+func (suite *BookingHandlerTestSuite) TestGetUserBookings_PageLinks() {
+	mockBookings := []entities.Booking{*suite.mockEntities.GetMockBooking()}
+
+	suite.bookingService.On("GetUserBookings",
+		mock.Anything,
+		uint(1),
+		entities.BookingFilter{},
+		10,
+		10,
+	).Return(mockBookings, int64(35), nil)
+
+	req, _ := test.CreateTestRequest("GET", "/api/bookings?page=2&limit=10", nil)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "35", w.Header().Get("X-Total-Count"))
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), float64(4), response["total_pages"])
+	assert.Equal(suite.T(), true, response["has_next"])
+	assert.Equal(suite.T(), true, response["has_prev"])
+	links := response["links"].(map[string]interface{})
+	assert.Equal(suite.T(), "/api/bookings?limit=10&page=2", links["self"])
+	assert.Equal(suite.T(), "/api/bookings?limit=10&page=1", links["prev"])
+	assert.Equal(suite.T(), "/api/bookings?limit=10&page=3", links["next"])
+	assert.Equal(suite.T(), "/api/bookings?limit=10&page=4", links["last"])
+}
+
+// Test GetUserBookings - Only the requested fields are returned
+func (suite *BookingHandlerTestSuite) TestGetUserBookings_Fields() {
+	mockBookings := []entities.Booking{*suite.mockEntities.GetMockBooking()}
+
+	suite.bookingService.On("GetUserBookings",
+		mock.Anything,
+		uint(1),
+		entities.BookingFilter{},
+		10,
+		0,
+	).Return(mockBookings, int64(1), nil)
+
+	req, _ := test.CreateTestRequest("GET", "/api/bookings?fields=id,status,event.name,event.venue.city", nil)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(suite.T(), err)
+
+	booking := response["data"].([]interface{})[0].(map[string]interface{})
+	assert.ElementsMatch(suite.T(), []string{"id", "status", "event"}, keys(booking))
+	event := booking["event"].(map[string]interface{})
+	assert.ElementsMatch(suite.T(), []string{"name", "venue"}, keys(event))
+	assert.Equal(suite.T(), mockBookings[0].Event.Name, event["name"])
+	assert.ElementsMatch(suite.T(), []string{"city"}, keys(event["venue"].(map[string]interface{})))
+}
+
+// Test GetUserBookings - Relations left out of expand aren't loaded or returned
+func (suite *BookingHandlerTestSuite) TestGetUserBookings_Expand() {
+	mockBookings := []entities.Booking{*suite.mockEntities.GetMockBooking()}
+
+	onlySeat := mock.MatchedBy(func(ctx context.Context) bool {
+		return expand.Includes(ctx, expand.Seat) && !expand.Includes(ctx, expand.Event) && !expand.Includes(ctx, expand.EventVenue)
+	})
+	suite.bookingService.On("GetUserBookings",
+		onlySeat,
+		uint(1),
+		entities.BookingFilter{},
+		10,
+		0,
+	).Return(mockBookings, int64(1), nil)
+
+	req, _ := test.CreateTestRequest("GET", "/api/bookings?expand=seat", nil)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(suite.T(), err)
+
+	booking := response["data"].([]interface{})[0].(map[string]interface{})
+	assert.NotContains(suite.T(), booking, "event")
+	assert.Contains(suite.T(), booking, "seat")
+	assert.Contains(suite.T(), booking, "status")
+
+	req, _ = test.CreateTestRequest("GET", "/api/bookings?expand=venue", nil)
+	w = test.ExecuteRequest(suite.router, req)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+}
+
+// Test GetUserBookings - Unknown fields are rejected
+func (suite *BookingHandlerTestSuite) TestGetUserBookings_UnknownField() {
+	req, _ := test.CreateTestRequest("GET", "/api/bookings?fields=id,event.secret", nil)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+}
+
+func keys(m map[string]interface{}) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	return names
+}
+
 // Test GetUserBookings - Empty result
 func (suite *BookingHandlerTestSuite) TestGetUserBookings_EmptyResult() {
 	suite.bookingService.On("GetUserBookings",
 		mock.Anything,
 		uint(1),
+		entities.BookingFilter{},
 		10,
 		0,
 	).Return([]entities.Booking{}, int64(0), nil)
@@ -334,6 +1033,57 @@ func (suite *BookingHandlerTestSuite) TestGetUserBookings_EmptyResult() {
 
 	data := response["data"].([]interface{})
 	assert.Equal(suite.T(), 0, len(data))
+	assert.Equal(suite.T(), false, response["has_next"])
+	assert.Equal(suite.T(), false, response["has_prev"])
+}
+
+// Test GetUserBookings - Filters
+func (suite *BookingHandlerTestSuite) TestGetUserBookings_Filters() {
+	filter := entities.BookingFilter{
+		Status:  "confirmed",
+		EventID: 3,
+		Period:  entities.BookingsUpcoming,
+		Sort:    entities.BookingSortEventStart,
+	}
+	suite.bookingService.On("GetUserBookings", mock.Anything, uint(1), filter, 10, 0).
+		Return([]entities.Booking{}, int64(0), nil)
+
+	req, _ := test.CreateTestRequest("GET", "/api/bookings?status=confirmed&event_id=3&period=upcoming&sort=event_start", nil)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	suite.bookingService.AssertExpectations(suite.T())
+}
+
+// Test GetUserBookings - Invalid period
+func (suite *BookingHandlerTestSuite) TestGetUserBookings_InvalidPeriod() {
+	req, _ := test.CreateTestRequest("GET", "/api/bookings?period=tomorrow", nil)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.bookingService.AssertNotCalled(suite.T(), "GetUserBookings")
+}
+
+// Test GetUpcomingBookings - Success
+func (suite *BookingHandlerTestSuite) TestGetUpcomingBookings_Success() {
+	booking := entities.Booking{
+		ID:      12,
+		EventID: 3,
+		Event:   entities.Event{ID: 3, Name: "Concert", StartTime: time.Now().Add(2 * time.Hour), Venue: entities.Venue{Name: "Arena"}},
+		Seat:    entities.Seat{Row: 4, Column: 7, SeatType: "vip"},
+	}
+	suite.bookingService.On("GetUpcomingBookings", mock.Anything, uint(1), 5).Return([]entities.Booking{booking}, nil)
+
+	req, _ := test.CreateTestRequest("GET", "/api/bookings/upcoming?limit=5", nil)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+
+	var upcoming []map[string]interface{}
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &upcoming))
+	assert.Len(suite.T(), upcoming, 1)
+	assert.Equal(suite.T(), "/api/bookings/12/ticket", upcoming[0]["ticket_url"])
+	assert.InDelta(suite.T(), 7200, upcoming[0]["starts_in_seconds"], 5)
 }
 
 // Test GetBookingByID - Success
@@ -377,6 +1127,80 @@ func (suite *BookingHandlerTestSuite) TestGetBookingByID_NotFound() {
 	assert.Equal(suite.T(), "Booking not found", response["error"])
 }
 
+// Test GetTicket - Attendee details are printed with the ID number masked
+func (suite *BookingHandlerTestSuite) TestGetTicket_MasksIDNumber() {
+	mockBooking := suite.mockEntities.GetMockBooking()
+	mockBooking.AttendeeName = "Jane Doe"
+	mockBooking.AttendeeIDNumber = "X1234567"
+
+	suite.bookingService.On("GetBookingByID",
+		mock.Anything,
+		uint(1),
+		uint(1),
+	).Return(mockBooking, nil)
+
+	req, _ := test.CreateTestRequest("GET", "/api/bookings/1/ticket", nil)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Jane Doe", response["attendee_name"])
+	assert.Equal(suite.T(), "•••• 4567", response["attendee_id"])
+	assert.NotContains(suite.T(), w.Body.String(), "X1234567")
+}
+
+// Test GetTicket - The QR token verifies with the signing key and covers the event
+func (suite *BookingHandlerTestSuite) TestGetTicket_SignedToken() {
+	signer, err := tickets.NewSignerFromConfig("", "test-secret")
+	suite.Require().NoError(err)
+	suite.handler.WithTickets(services.NewTicketService(signer, nil, 6*time.Hour))
+
+	mockBooking := suite.mockEntities.GetMockBooking()
+	suite.bookingService.On("GetBookingByID",
+		mock.Anything,
+		uint(1),
+		uint(1),
+	).Return(mockBooking, nil)
+
+	req, _ := test.CreateTestRequest("GET", "/api/bookings/1/ticket", nil)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+
+	var response struct {
+		Token      string    `json:"token"`
+		ValidUntil time.Time `json:"valid_until"`
+	}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	claims, err := signer.Verify(response.Token)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), mockBooking.ID, claims.BookingID)
+	assert.Equal(suite.T(), mockBooking.EventID, claims.EventID)
+	assert.Equal(suite.T(), mockBooking.SeatID, claims.SeatID)
+	assert.True(suite.T(), claims.ValidAt(mockBooking.Event.StartTime))
+	assert.Equal(suite.T(), mockBooking.Event.EndTime.Unix(), response.ValidUntil.Unix())
+}
+
+// Test GetTicket - Cancelled bookings have no valid ticket
+func (suite *BookingHandlerTestSuite) TestGetTicket_CancelledBooking() {
+	mockBooking := suite.mockEntities.GetMockBooking()
+	mockBooking.Status = "cancelled"
+
+	suite.bookingService.On("GetBookingByID",
+		mock.Anything,
+		uint(1),
+		uint(1),
+	).Return(mockBooking, nil)
+
+	req, _ := test.CreateTestRequest("GET", "/api/bookings/1/ticket", nil)
+	w := test.ExecuteRequest(suite.router, req)
+
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+}
+
 // Test authentication scenarios
 func (suite *BookingHandlerTestSuite) TestCreateBookingIntent_NoAuth() {
 	// Create router without auth middleware
@@ -408,6 +1232,7 @@ func (suite *BookingHandlerTestSuite) TestConcurrentBookingIntents() {
 		mock.Anything,
 		uint(1),
 		uint(1),
+		entities.BookingIntentOptions{},
 	).Return(mockIntent, nil).Once()
 
 	// Second request fails due to seat being locked
@@ -415,6 +1240,7 @@ func (suite *BookingHandlerTestSuite) TestConcurrentBookingIntents() {
 		mock.Anything,
 		uint(1),
 		uint(1),
+		entities.BookingIntentOptions{},
 	).Return(nil, errors.NewConflictError("Seat is already locked by another user", nil)).Once()
 
 	reqBody := request.CreateBookingIntentRequest{
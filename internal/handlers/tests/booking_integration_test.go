@@ -1,18 +1,31 @@
 package tests
 
 import (
+	"api/constants"
+	"api/internal/encryption"
 	"api/internal/entities"
 	"api/internal/handlers"
+	"api/internal/repository"
+	"api/internal/services"
+	"api/pkg/money"
 	"api/pkg/request"
 	"api/test"
 	"api/test/mocks"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 // TestBookingFlowIntegration tests the complete booking flow
@@ -45,6 +58,7 @@ func TestBookingFlowIntegration(t *testing.T) {
 		mock.Anything,
 		uint(1),
 		uint(1),
+		entities.BookingIntentOptions{},
 	).Return(mockIntent, nil).Once()
 
 	createReq := request.CreateBookingIntentRequest{SeatID: 1}
@@ -62,7 +76,9 @@ func TestBookingFlowIntegration(t *testing.T) {
 	bookingService.On("ConfirmBooking",
 		mock.Anything,
 		uint(1),
-		"pay_test123",
+		entities.PaymentDetails{PaymentID: "pay_test123"},
+		entities.AttendeeDetails{},
+		entities.ConfirmOptions{},
 	).Return(mockBooking, nil).Once()
 
 	confirmReq := request.ConfirmBookingRequest{
@@ -83,6 +99,7 @@ func TestBookingFlowIntegration(t *testing.T) {
 	bookingService.On("GetUserBookings",
 		mock.Anything,
 		uint(1),
+		entities.BookingFilter{},
 		10,
 		0,
 	).Return([]entities.Booking{*mockBooking}, int64(1), nil).Once()
@@ -103,3 +120,114 @@ func TestBookingFlowIntegration(t *testing.T) {
 	// Verify all expectations were met
 	bookingService.AssertExpectations(t)
 }
+
+// openTestDatabase connects to the scratch Postgres database in TEST_DATABASE_URL, skipping the
+// test without one, and migrates the booking and payment tables
+func openTestDatabase(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	keyring, err := encryption.NewKeyring(encryption.Config{Keys: "test:MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MDE="})
+	require.NoError(t, err)
+	encryption.Register(keyring)
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger:                                   logger.Discard,
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&entities.Tenant{}, &entities.User{}, &entities.Event{}, &entities.Seat{},
+		&entities.Booking{}, &entities.PaymentTransaction{}, &entities.PaymentTransactionEvent{}, &entities.LoyaltyTransaction{},
+		&entities.LedgerJournal{}, &entities.LedgerEntry{}, &entities.Dispute{}, &entities.DisputeEvent{}))
+	require.NoError(t, repository.NewTenantRepository(db).EnsureDefaultTenant(context.Background()))
+	return db
+}
+
+// createPaidBooking stores a confirmed booking paid with paymentID, and its paid transaction
+func createPaidBooking(t *testing.T, db *gorm.DB, paymentID string) (*entities.Booking, *entities.PaymentTransaction) {
+	start := time.Now().Add(30 * 24 * time.Hour)
+	user := entities.User{Email: paymentID + "@example.com", Password: "unused"}
+	require.NoError(t, db.Create(&user).Error)
+	event := entities.Event{Name: "Timeline " + paymentID, VenueID: 1, StartTime: start, EndTime: start.Add(3 * time.Hour),
+		Price: money.FromMajor(50), EventType: constants.EventTypeConcert, Status: constants.EventStatusActive}
+	require.NoError(t, db.Create(&event).Error)
+	seat := entities.Seat{EventID: event.ID, Row: 1, Column: 1, SeatType: constants.SeatTypeStandard, Price: event.Price}
+	require.NoError(t, db.Create(&seat).Error)
+
+	booking := entities.Booking{UserID: user.ID, EventID: event.ID, SeatID: seat.ID, Status: constants.BookingStatusConfirmed,
+		PaymentStatus: constants.PaymentStatusPaid, PaymentID: paymentID, TotalAmount: event.Price, BookedAt: time.Now()}
+	require.NoError(t, db.Create(&booking).Error)
+	transaction := entities.PaymentTransaction{BookingID: &booking.ID, Provider: constants.DefaultPaymentProvider,
+		ProviderReference: paymentID, Amount: booking.TotalAmount, Currency: constants.DefaultCurrency, Status: constants.PaymentStatusPaid}
+	require.NoError(t, db.Create(&transaction).Error)
+	require.NoError(t, db.Create(&entities.PaymentTransactionEvent{PaymentTransactionID: transaction.ID,
+		Status: constants.PaymentStatusPaid, OccurredAt: booking.BookedAt}).Error)
+	return &booking, &transaction
+}
+
+// TestPaymentTransactionTimeline checks against a scratch Postgres database that refunds and
+// disputes move a payment's reconciliation record along, as the admin lookup shows it.
+// It needs TEST_DATABASE_URL.
+func TestPaymentTransactionTimeline(t *testing.T) {
+	db := openTestDatabase(t)
+	ctx := context.Background()
+	router := test.SetupTestGin()
+	router.GET("/admin/payments/:id", handlers.NewPaymentHandler(services.NewPaymentService(repository.NewPaymentRepository(db))).GetTransaction)
+	runID := time.Now().Format("150405.000000")
+
+	timeline := func(t *testing.T, transactionID uint) (string, []string) {
+		req, err := test.CreateTestRequest(http.MethodGet, fmt.Sprintf("/admin/payments/%d", transactionID), nil)
+		require.NoError(t, err)
+		w := test.ExecuteRequest(router, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var transaction struct {
+			Status   string `json:"status"`
+			Timeline []struct {
+				Status string `json:"status"`
+			} `json:"timeline"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &transaction))
+		statuses := make([]string, len(transaction.Timeline))
+		for i, event := range transaction.Timeline {
+			statuses[i] = event.Status
+		}
+		return transaction.Status, statuses
+	}
+
+	t.Run("cancelling refunds the payment", func(t *testing.T) {
+		booking, transaction := createPaidBooking(t, db, "pay_refund_"+runID)
+		_, err := repository.NewBookingRepository(db).CancelBooking(ctx, booking.ID)
+		require.NoError(t, err)
+
+		status, statuses := timeline(t, transaction.ID)
+		assert.Equal(t, constants.PaymentStatusRefunded, status)
+		assert.Equal(t, []string{constants.PaymentStatusPaid, constants.PaymentStatusRefunded}, statuses)
+	})
+
+	disputes := repository.NewDisputeRepository(db)
+	for _, tt := range []struct {
+		outcome string
+		want    string
+	}{
+		{constants.DisputeStatusLost, constants.PaymentStatusChargedBack},
+		{constants.DisputeStatusWon, constants.PaymentStatusPaid},
+	} {
+		t.Run("dispute "+tt.outcome, func(t *testing.T) {
+			paymentID := "pay_dispute_" + tt.outcome + "_" + runID
+			_, transaction := createPaidBooking(t, db, paymentID)
+			opened := time.Now()
+			for i, status := range []string{constants.DisputeStatusOpen, constants.DisputeStatusUnderReview, tt.outcome} {
+				_, _, _, err := disputes.RecordDisputeUpdate(ctx, entities.DisputeUpdate{ProviderDisputeID: "dp_" + paymentID,
+					PaymentID: paymentID, Status: status, Amount: transaction.Amount, OccurredAt: opened.Add(time.Duration(i) * time.Hour)})
+				require.NoError(t, err)
+			}
+
+			status, statuses := timeline(t, transaction.ID)
+			assert.Equal(t, tt.want, status)
+			assert.Equal(t, []string{constants.PaymentStatusPaid, constants.PaymentStatusDisputed, tt.want}, statuses)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package tests
+
+import (
+	"api/internal/entities"
+	"api/internal/handlers"
+	redisconn "api/internal/redis"
+	"api/internal/services"
+	"api/pkg/client"
+	"api/pkg/errors"
+	"api/pkg/request"
+	"api/test"
+	"api/test/mocks"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// ContractTestSuite runs the client SDK against the real handlers over HTTP. The client
+// decodes strictly, so a handler that changes envelope, status code or field set fails here.
+type ContractTestSuite struct {
+	suite.Suite
+	server         *httptest.Server
+	bookingService *mocks.MockBookingService
+	client         *client.Client
+	mockEntities   *test.MockEntities
+}
+
+func (suite *ContractTestSuite) SetupTest() {
+	suite.bookingService = &mocks.MockBookingService{}
+	suite.mockEntities = &test.MockEntities{}
+	bookingHandler := handlers.NewBookingHandler(suite.bookingService)
+
+	// Health is never started against the closed port, so it reports ok
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+	addr := listener.Addr().String()
+	listener.Close()
+	redisClient, err := redisconn.NewRedisClient(redisconn.Config{URL: "redis://" + addr})
+	suite.Require().NoError(err)
+	suite.T().Cleanup(func() { redisClient.Client.Close() })
+	healthHandler := handlers.NewHealthHandler(redisconn.NewHealth(redisClient.Client, time.Minute), nil)
+
+	router := test.SetupTestGin()
+	router.GET("/health", healthHandler.GetHealth)
+	api := router.Group("/api")
+	api.GET("/status", healthHandler.GetStatus)
+	protected := api.Group("/")
+	protected.Use(func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+	{
+		protected.POST("/booking-intents", bookingHandler.CreateBookingIntent)
+		protected.POST("/bookings/confirm", bookingHandler.ConfirmBooking)
+		protected.POST("/booking-intents/cancel", bookingHandler.CancelBookingIntent)
+		protected.POST("/booking-intents/:id/heartbeat", bookingHandler.HeartbeatBookingIntent)
+		protected.GET("/booking-intents/:id/status", bookingHandler.GetBookingIntentStatus)
+		protected.POST("/booking-intents/:id/retry-payment", bookingHandler.RetryPayment)
+		protected.POST("/booking-intents/:id/resume-token", bookingHandler.IssueResumeToken)
+		protected.POST("/booking-intents/resume", bookingHandler.ResumeBookingIntent)
+		protected.DELETE("/bookings/:id", bookingHandler.CancelBooking)
+		protected.GET("/bookings", bookingHandler.GetUserBookings)
+		protected.GET("/bookings/:id", bookingHandler.GetBookingByID)
+		protected.GET("/bookings/:id/ticket", bookingHandler.GetTicket)
+	}
+
+	suite.server = httptest.NewServer(router)
+	suite.client = client.New(suite.server.URL, client.WithStrictDecoding(), client.WithToken("test-token"))
+}
+
+func (suite *ContractTestSuite) TearDownTest() {
+	suite.server.Close()
+	suite.bookingService.AssertExpectations(suite.T())
+}
+
+func (suite *ContractTestSuite) TestStatus() {
+	health, err := suite.client.GetHealth(context.Background())
+	suite.Require().NoError(err)
+	suite.Equal("ok", health.Status)
+
+	status, err := suite.client.GetStatus(context.Background())
+	suite.Require().NoError(err)
+	suite.False(status.Degraded)
+}
+
+func (suite *ContractTestSuite) TestBookingIntentLifecycle() {
+	ctx := context.Background()
+	mockIntent := suite.mockEntities.GetMockBookingIntent()
+	lockExpiresAt := time.Now().Add(5 * time.Minute)
+	mockIntent.LockExpiresAt = lockExpiresAt
+
+	suite.bookingService.On("CreateBookingIntent", mock.Anything, uint(1), uint(1), entities.BookingIntentOptions{}).Return(mockIntent, nil)
+	intent, err := suite.client.CreateBookingIntent(ctx, request.CreateBookingIntentRequest{SeatID: 1})
+	suite.Require().NoError(err)
+	suite.Equal(mockIntent.ID, intent.ID)
+
+	suite.bookingService.On("HeartbeatBookingIntent", mock.Anything, uint(1), uint(1)).Return(mockIntent, nil)
+	heartbeat, err := suite.client.HeartbeatBookingIntent(ctx, intent.ID)
+	suite.Require().NoError(err)
+	suite.Equal(mockIntent.ID, heartbeat.BookingIntentID)
+
+	suite.bookingService.On("GetBookingIntentStatus", mock.Anything, uint(1), uint(1)).Return(&services.BookingIntentStatus{
+		BookingIntentID:  1,
+		Status:           "pending",
+		PaymentStatus:    "pending",
+		ExpiresAt:        &lockExpiresAt,
+		RemainingSeconds: 300,
+	}, nil)
+	status, err := suite.client.GetBookingIntentStatus(ctx, intent.ID)
+	suite.Require().NoError(err)
+	suite.Equal(300, status.RemainingSeconds)
+
+	suite.bookingService.On("RetryPayment", mock.Anything, uint(1), uint(1), "").Return(mockIntent, nil)
+	retry, err := suite.client.RetryPayment(ctx, intent.ID, "")
+	suite.Require().NoError(err)
+	suite.Equal(mockIntent.PaymentIntentID, retry.PaymentReference)
+
+	suite.bookingService.On("IssueResumeToken", mock.Anything, uint(1), uint(1)).Return("resume-token", lockExpiresAt, nil)
+	token, err := suite.client.IssueResumeToken(ctx, intent.ID)
+	suite.Require().NoError(err)
+	suite.Equal("resume-token", token.ResumeToken)
+
+	suite.bookingService.On("ResumeBookingIntent", mock.Anything, uint(1), "resume-token").Return(mockIntent, nil)
+	resumed, err := suite.client.ResumeBookingIntent(ctx, token.ResumeToken)
+	suite.Require().NoError(err)
+	suite.Equal(mockIntent.ID, resumed.ID)
+
+	suite.bookingService.On("CancelBookingIntent", mock.Anything, uint(1), uint(1)).Return(nil)
+	suite.Require().NoError(suite.client.CancelBookingIntent(ctx, intent.ID))
+}
+
+func (suite *ContractTestSuite) TestBookings() {
+	ctx := context.Background()
+	mockBooking := suite.mockEntities.GetMockBooking()
+
+	suite.bookingService.On("ConfirmBooking", mock.Anything, uint(1), mock.Anything, mock.Anything, entities.ConfirmOptions{}).Return(mockBooking, nil)
+	booking, err := suite.client.ConfirmBooking(ctx, request.ConfirmBookingRequest{BookingIntentID: 1, PaymentID: "pay_test123"})
+	suite.Require().NoError(err)
+	suite.Equal(mockBooking.ID, booking.ID)
+
+	suite.bookingService.On("GetUserBookings", mock.Anything, uint(1), entities.BookingFilter{}, 10, 0).Return([]entities.Booking{*mockBooking}, int64(1), nil)
+	page, err := suite.client.ListBookings(ctx, 1, 10)
+	suite.Require().NoError(err)
+	suite.Len(page.Data, 1)
+	suite.Equal(1, page.TotalPages)
+	suite.False(page.HasNext)
+	suite.Equal("/api/bookings?limit=10&page=1", page.Links.Last)
+
+	suite.bookingService.On("GetBookingByID", mock.Anything, uint(1), uint(1)).Return(mockBooking, nil)
+	booking, err = suite.client.GetBooking(ctx, 1)
+	suite.Require().NoError(err)
+	suite.Equal(mockBooking.Status, booking.Status)
+
+	ticket, err := suite.client.GetTicket(ctx, 1)
+	suite.Require().NoError(err)
+	suite.Equal(mockBooking.ID, ticket.BookingID)
+
+	suite.bookingService.On("CancelBooking", mock.Anything, uint(1), uint(1)).Return(nil)
+	suite.Require().NoError(suite.client.CancelBooking(ctx, 1))
+}
+
+func (suite *ContractTestSuite) TestErrorsDecodeAsAPIError() {
+	suite.bookingService.On("GetBookingByID", mock.Anything, uint(2), uint(1)).Return(nil, errors.NewNotFoundError("booking not found", nil))
+
+	_, err := suite.client.GetBooking(context.Background(), 2)
+	apiErr, ok := err.(*client.APIError)
+	suite.Require().True(ok, "expected *client.APIError, got %v", err)
+	suite.Equal(http.StatusNotFound, apiErr.StatusCode)
+	suite.Equal("booking not found", apiErr.Code)
+}
+
+func TestContractTestSuite(t *testing.T) {
+	suite.Run(t, new(ContractTestSuite))
+}
@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/repository"
+	"api/pkg/errors"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRecordDisputeUpdate checks against a scratch Postgres database how dispute webhooks advance
+// a dispute and flag its booking. It needs TEST_DATABASE_URL.
+func TestRecordDisputeUpdate(t *testing.T) {
+	db := openTestDatabase(t)
+	ctx := context.Background()
+	disputes := repository.NewDisputeRepository(db)
+	runID := time.Now().Format("150405.000000")
+	opened := time.Now().Truncate(time.Microsecond)
+
+	record := func(paymentID, status string, occurredAt time.Time) (*entities.Dispute, bool, bool, error) {
+		return disputes.RecordDisputeUpdate(ctx, entities.DisputeUpdate{ProviderDisputeID: "dp_" + paymentID,
+			PaymentID: paymentID, Status: status, OccurredAt: occurredAt})
+	}
+	assertConflict := func(t *testing.T, err error) {
+		appErr, ok := err.(*errors.AppError)
+		require.True(t, ok, "got %v, want a conflict", err)
+		assert.Equal(t, "CONFLICT", appErr.Type)
+	}
+	disputeEvents := func(t *testing.T, disputeID uint) int64 {
+		var count int64
+		require.NoError(t, db.Model(&entities.DisputeEvent{}).Where("dispute_id = ?", disputeID).Count(&count).Error)
+		return count
+	}
+
+	t.Run("redelivered update is a no-op", func(t *testing.T) {
+		paymentID := "pay_redelivery_" + runID
+		booking, _ := createPaidBooking(t, db, paymentID)
+
+		dispute, isNew, changed, err := record(paymentID, constants.DisputeStatusOpen, opened)
+		require.NoError(t, err)
+		assert.True(t, isNew)
+		assert.True(t, changed)
+		assert.Equal(t, booking.ID, dispute.BookingID)
+
+		_, isNew, changed, err = record(paymentID, constants.DisputeStatusOpen, opened)
+		require.NoError(t, err)
+		assert.False(t, isNew)
+		assert.False(t, changed)
+		assert.Equal(t, int64(1), disputeEvents(t, dispute.ID))
+	})
+
+	t.Run("closed dispute can't be reopened", func(t *testing.T) {
+		paymentID := "pay_closed_" + runID
+		createPaidBooking(t, db, paymentID)
+
+		_, _, _, err := record(paymentID, constants.DisputeStatusOpen, opened)
+		require.NoError(t, err)
+		dispute, _, _, err := record(paymentID, constants.DisputeStatusLost, opened.Add(time.Hour))
+		require.NoError(t, err)
+
+		_, _, _, err = record(paymentID, constants.DisputeStatusUnderReview, opened.Add(30*time.Minute))
+		assertConflict(t, err)
+		assert.Equal(t, int64(2), disputeEvents(t, dispute.ID))
+	})
+
+	t.Run("dispute first reported closed stays closed", func(t *testing.T) {
+		paymentID := "pay_first_closed_" + runID
+		createPaidBooking(t, db, paymentID)
+
+		dispute, isNew, _, err := record(paymentID, constants.DisputeStatusLost, opened)
+		require.NoError(t, err)
+		assert.True(t, isNew)
+		require.NotNil(t, dispute.ClosedAt)
+		assert.True(t, dispute.ClosedAt.Equal(opened))
+
+		// A stale delivery of the opening arrives after the outcome
+		_, _, _, err = record(paymentID, constants.DisputeStatusOpen, opened.Add(-time.Hour))
+		assertConflict(t, err)
+
+		var stored entities.Dispute
+		require.NoError(t, db.First(&stored, dispute.ID).Error)
+		assert.Equal(t, constants.DisputeStatusLost, stored.Status)
+	})
+
+	t.Run("won dispute clears the flag and reinstates the ticket", func(t *testing.T) {
+		paymentID := "pay_won_" + runID
+		booking, _ := createPaidBooking(t, db, paymentID)
+
+		_, _, _, err := record(paymentID, constants.DisputeStatusOpen, opened)
+		require.NoError(t, err)
+		require.NoError(t, disputes.RevokeTicket(ctx, booking.ID))
+
+		var flagged entities.Booking
+		require.NoError(t, db.First(&flagged, booking.ID).Error)
+		assert.True(t, flagged.Disputed)
+		assert.NotNil(t, flagged.TicketRevokedAt)
+
+		dispute, _, changed, err := record(paymentID, constants.DisputeStatusWon, opened.Add(time.Hour))
+		require.NoError(t, err)
+		assert.True(t, changed)
+		var stored entities.Dispute
+		require.NoError(t, db.First(&stored, dispute.ID).Error)
+		assert.Equal(t, constants.DisputeStatusWon, stored.Status)
+		assert.NotNil(t, stored.ClosedAt)
+
+		var cleared entities.Booking
+		require.NoError(t, db.First(&cleared, booking.ID).Error)
+		assert.False(t, cleared.Disputed)
+		assert.Nil(t, cleared.TicketRevokedAt)
+	})
+}
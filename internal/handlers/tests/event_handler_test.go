@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/handlers"
+	"api/internal/services"
+	"api/test"
+	"api/test/mocks"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateEventReminderOffsets(t *testing.T) {
+	eventService := &mocks.MockEventService{}
+	venueRepo := &mocks.MockVenueRepository{}
+	handler := handlers.NewEventHandler(eventService, services.NewVenueService(venueRepo))
+	router := test.SetupTestGin()
+	router.POST("/admin/events", func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Next()
+	}, handler.CreateEvent)
+
+	venueRepo.On("GetVenueByID", mock.Anything, uint(3)).Return(&entities.Venue{ID: 3, Rows: 5, Columns: 10}, nil)
+	start := time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(time.Second)
+	createEvent := func(t *testing.T, fields map[string]interface{}) *entities.Event {
+		body := map[string]interface{}{
+			"name":       "Spring Concert",
+			"venue_id":   3,
+			"start_time": start,
+			"end_time":   start.Add(3 * time.Hour),
+			"price":      25,
+			"event_type": constants.EventTypeConcert,
+		}
+		for field, value := range fields {
+			body[field] = value
+		}
+
+		var created *entities.Event
+		eventService.On("CreateEvent", mock.Anything, mock.AnythingOfType("*entities.Event"), uint(1)).
+			Run(func(args mock.Arguments) { created = args.Get(1).(*entities.Event) }).
+			Return(&entities.Task{ID: 9}, nil).Once()
+
+		req, err := test.CreateTestRequest(http.MethodPost, "/admin/events", body)
+		require.NoError(t, err)
+		w := test.ExecuteRequest(router, req)
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+		return created
+	}
+
+	t.Run("left out uses the default offsets", func(t *testing.T) {
+		event := createEvent(t, nil)
+		assert.Equal(t, constants.DefaultReminderOffsets, event.ReminderOffsets)
+	})
+
+	t.Run("given offsets are normalized", func(t *testing.T) {
+		event := createEvent(t, map[string]interface{}{"reminder_offsets": []string{"90m", "48h"}})
+		assert.Equal(t, "1h30m,48h", event.ReminderOffsets)
+	})
+
+	t.Run("an empty list turns reminders off", func(t *testing.T) {
+		event := createEvent(t, map[string]interface{}{"reminder_offsets": []string{}})
+		assert.Equal(t, "", event.ReminderOffsets)
+	})
+
+	eventService.AssertExpectations(t)
+}
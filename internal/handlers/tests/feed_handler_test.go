@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"api/internal/entities"
+	"api/internal/handlers"
+	"api/test"
+	"api/test/mocks"
+	"encoding/xml"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedHandler(t *testing.T) {
+	feedService := &mocks.MockFeedService{}
+	handler := handlers.NewFeedHandler(feedService, "https://tickets.example.com")
+	router := test.SetupTestGin()
+	router.GET("/sitemap.xml", handler.GetSitemap)
+	router.GET("/api/feeds/events.atom", handler.GetAtomFeed)
+	router.GET("/api/feeds/events.rss", handler.GetRSSFeed)
+
+	published := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	event := entities.Event{
+		ID:        7,
+		Name:      "Spring Concert",
+		EventType: "concert",
+		StartTime: published.Add(30 * 24 * time.Hour),
+		Venue:     entities.Venue{Name: "Town Hall", City: "Leeds"},
+		Tenant:    entities.Tenant{ID: 2, Name: "Northern Live", Slug: "northern-live"},
+		CreatedAt: published,
+		UpdatedAt: published,
+	}
+
+	t.Run("sitemap lists organizers and events", func(t *testing.T) {
+		feedService.On("Sitemap", mock.Anything).Return(&entities.Sitemap{
+			Organizers: []entities.Tenant{{Slug: "northern-live", UpdatedAt: published}},
+			Events:     []entities.Event{event},
+		}, nil).Once()
+
+		w := test.ExecuteRequest(router, feedRequest("/sitemap.xml"))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+
+		var urlSet struct {
+			URLs []struct {
+				Loc string `xml:"loc"`
+			} `xml:"url"`
+		}
+		require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &urlSet))
+		require.Len(t, urlSet.URLs, 2)
+		assert.Equal(t, "https://tickets.example.com/organizers/northern-live", urlSet.URLs[0].Loc)
+		assert.Equal(t, "https://tickets.example.com/events/7", urlSet.URLs[1].Loc)
+	})
+
+	t.Run("atom feed links the next page", func(t *testing.T) {
+		feedService.On("EventFeed", mock.Anything, 2).Return(&entities.EventFeedPage{
+			Events: []entities.Event{event}, Page: 2, PageSize: 1, Total: 3,
+		}, nil).Once()
+
+		w := test.ExecuteRequest(router, feedRequest("/api/feeds/events.atom?page=2"))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/atom+xml")
+
+		var feed struct {
+			Links   []feedLink `xml:"link"`
+			Entries []struct {
+				ID     string `xml:"id"`
+				Title  string `xml:"title"`
+				Author string `xml:"author>name"`
+			} `xml:"entry"`
+		}
+		require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &feed))
+		require.Len(t, feed.Entries, 1)
+		assert.Equal(t, "https://tickets.example.com/events/7", feed.Entries[0].ID)
+		assert.Equal(t, "Northern Live", feed.Entries[0].Author)
+		assert.Contains(t, feed.Links, feedLink{Rel: "next", Href: "http://example.com/api/feeds/events.atom?page=3"})
+	})
+
+	t.Run("rss feed ends on the last page", func(t *testing.T) {
+		feedService.On("EventFeed", mock.Anything, 1).Return(&entities.EventFeedPage{
+			Events: []entities.Event{event}, Page: 1, PageSize: 50, Total: 1,
+		}, nil).Once()
+
+		w := test.ExecuteRequest(router, feedRequest("/api/feeds/events.rss"))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var rss struct {
+			Items []struct {
+				Link    string `xml:"link"`
+				PubDate string `xml:"pubDate"`
+			} `xml:"channel>item"`
+		}
+		require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &rss))
+		require.Len(t, rss.Items, 1)
+		assert.Equal(t, "https://tickets.example.com/events/7", rss.Items[0].Link)
+		assert.Equal(t, "Sun, 01 Mar 2026 12:00:00 +0000", rss.Items[0].PubDate)
+		assert.NotContains(t, w.Body.String(), `rel="next"`)
+	})
+
+	t.Run("rejects invalid pages", func(t *testing.T) {
+		w := test.ExecuteRequest(router, feedRequest("/api/feeds/events.rss?page=0"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	feedService.AssertExpectations(t)
+}
+
+type feedLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+}
+
+func feedRequest(url string) *http.Request {
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Host = "example.com"
+	return req
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"api/internal/handlers"
+	"api/internal/middleware"
+	redisconn "api/internal/redis"
+	"api/test"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHealthReportsDegradedMode points the client at a closed port, so the first health
+// check switches to degraded mode
+func TestHealthReportsDegradedMode(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	client, err := redisconn.NewRedisClient(redisconn.Config{URL: "redis://" + addr})
+	require.NoError(t, err)
+	defer client.Client.Close()
+
+	health := redisconn.NewHealth(client.Client, time.Minute)
+	handler := handlers.NewHealthHandler(health, middleware.NewDrain())
+
+	router := test.SetupTestGin()
+	router.Use(middleware.DegradedMode(health))
+	router.GET("/health", handler.GetHealth)
+	router.GET("/api/status", handler.GetStatus)
+
+	// Not checked yet
+	w := test.ExecuteRequest(router, newGetRequest(t, "/health"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok","redis":"up"}`, w.Body.String())
+	assert.Empty(t, w.Header().Get(middleware.DegradedModeHeader))
+
+	health.Start(context.Background())
+	defer health.Stop()
+	require.True(t, health.Degraded())
+
+	w = test.ExecuteRequest(router, newGetRequest(t, "/health"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var healthResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &healthResp))
+	assert.Equal(t, "degraded", healthResp["status"])
+	assert.Equal(t, "unavailable", healthResp["redis"])
+	assert.NotEmpty(t, healthResp["degraded_since"])
+	assert.Equal(t, "true", w.Header().Get(middleware.DegradedModeHeader))
+
+	w = test.ExecuteRequest(router, newGetRequest(t, "/api/status"))
+	var statusResp struct {
+		Data struct {
+			Degraded bool   `json:"degraded"`
+			Banner   string `json:"banner"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &statusResp))
+	assert.True(t, statusResp.Data.Degraded)
+	assert.NotEmpty(t, statusResp.Data.Banner)
+
+	// Commands fail fast instead of waiting on the connection
+	err = client.Client.Get(context.Background(), "key").Err()
+	assert.True(t, redisconn.IsUnavailable(err))
+}
+
+// TestDrainRejectsNewMutations starts draining while a confirmation is running: the health
+// check fails, new mutations are refused, reads still work and Wait returns once the running
+// request is done
+func TestDrainRejectsNewMutations(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	client, err := redisconn.NewRedisClient(redisconn.Config{URL: "redis://" + addr})
+	require.NoError(t, err)
+	defer client.Client.Close()
+
+	drain := middleware.NewDrain()
+	handler := handlers.NewHealthHandler(redisconn.NewHealth(client.Client, time.Minute), drain)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	router := test.SetupTestGin()
+	router.Use(drain.RejectMutations())
+	router.GET("/health", handler.GetHealth)
+	router.POST("/api/bookings/confirm", func(c *gin.Context) {
+		close(started)
+		<-release
+		c.Status(http.StatusOK)
+	})
+	router.POST("/api/booking-intents", func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	confirmed := make(chan int)
+	go func() {
+		req, _ := test.CreateTestRequest("POST", "/api/bookings/confirm", nil)
+		confirmed <- test.ExecuteRequest(router, req).Code
+	}()
+	<-started
+
+	drain.Start()
+
+	w := test.ExecuteRequest(router, newGetRequest(t, "/health"))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"status":"draining","redis":"up"}`, w.Body.String())
+
+	req, err := test.CreateTestRequest("POST", "/api/booking-intents", nil)
+	require.NoError(t, err)
+	w = test.ExecuteRequest(router, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	// The confirmation admitted before the drain is still running
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, drain.Wait(ctx), context.DeadlineExceeded)
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-confirmed)
+	require.NoError(t, drain.Wait(context.Background()))
+}
+
+func newGetRequest(t *testing.T, url string) *http.Request {
+	req, err := test.CreateTestRequest("GET", url, nil)
+	require.NoError(t, err)
+	return req
+}
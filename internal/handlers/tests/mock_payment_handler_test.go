@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"api/internal/handlers"
+	"api/internal/middleware"
+	"api/internal/payments"
+	"api/pkg/money"
+	"api/pkg/request"
+	"api/test"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockPaymentRouter(provider *payments.MockProvider) *gin.Engine {
+	router := test.SetupTestGin()
+	router.POST("/api/mock-payments/charges", handlers.NewMockPaymentHandler(provider).CreateCharge)
+	return router
+}
+
+func postMockCharge(t *testing.T, router *gin.Engine, cardNumber string) *httptest.ResponseRecorder {
+	req, err := test.CreateTestRequest("POST", "/api/mock-payments/charges", request.MockChargeRequest{
+		PaymentReference: "pay_ref123",
+		Amount:           money.FromMajor(100),
+		Currency:         "usd",
+		CardNumber:       cardNumber,
+	})
+	require.NoError(t, err)
+	return test.ExecuteRequest(router, req)
+}
+
+func TestMockCharge_Succeeds(t *testing.T) {
+	router := newMockPaymentRouter(payments.NewMockProvider(payments.MockConfig{}))
+
+	w := postMockCharge(t, router, payments.CardSuccess)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp struct {
+		Data struct {
+			PaymentID        string `json:"payment_id"`
+			PaymentReference string `json:"payment_reference"`
+			Provider         string `json:"provider"`
+			Status           string `json:"status"`
+			Currency         string `json:"currency"`
+			CardBrand        string `json:"card_brand"`
+			CardLast4        string `json:"card_last4"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.NotEmpty(t, resp.Data.PaymentID)
+	assert.Equal(t, "pay_ref123", resp.Data.PaymentReference)
+	assert.Equal(t, payments.MockProviderName, resp.Data.Provider)
+	assert.Equal(t, "paid", resp.Data.Status)
+	assert.Equal(t, "USD", resp.Data.Currency)
+	assert.Equal(t, "visa", resp.Data.CardBrand)
+	assert.Equal(t, "4242", resp.Data.CardLast4)
+}
+
+func TestMockCharge_Declined(t *testing.T) {
+	router := newMockPaymentRouter(payments.NewMockProvider(payments.MockConfig{}))
+
+	w := postMockCharge(t, router, payments.CardInsufficientFunds)
+	assert.Equal(t, http.StatusPaymentRequired, w.Code)
+	assert.JSONEq(t, `{"error":"payment declined","message":"insufficient_funds"}`, w.Body.String())
+}
+
+func TestMockCharge_DisputeWebhook(t *testing.T) {
+	received := make(chan request.DisputeWebhookRequest, 1)
+
+	// The webhook lands on the real signature check
+	webhookRouter := test.SetupTestGin()
+	webhookRouter.POST("/webhooks", middleware.NewWebhookVerifier("secret", 5*time.Minute, nil, nil).VerifySignature(), func(c *gin.Context) {
+		var req request.DisputeWebhookRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		received <- req
+		c.Status(http.StatusOK)
+	})
+	server := httptest.NewServer(webhookRouter)
+	defer server.Close()
+
+	router := newMockPaymentRouter(payments.NewMockProvider(payments.MockConfig{
+		WebhookURL:    server.URL + "/webhooks",
+		WebhookSecret: "secret",
+	}))
+
+	w := postMockCharge(t, router, payments.CardDisputed)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var resp struct {
+		Data struct {
+			PaymentID     string `json:"payment_id"`
+			DisputeOpened bool   `json:"dispute_opened"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Data.DisputeOpened)
+
+	select {
+	case webhook := <-received:
+		assert.Equal(t, resp.Data.PaymentID, webhook.PaymentID)
+		assert.Equal(t, "open", webhook.Status)
+		assert.Equal(t, money.FromMajor(100), webhook.Amount)
+	case <-time.After(5 * time.Second):
+		t.Fatal("dispute webhook was not delivered")
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"api/internal/services"
+	"api/pkg/request"
+	"api/pkg/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TicketHandler struct {
+	ticketService *services.TicketService
+}
+
+func NewTicketHandler(ticketService *services.TicketService) *TicketHandler {
+	return &TicketHandler{
+		ticketService: ticketService,
+	}
+}
+
+// GetKeys returns the public keys door scanners download to validate ticket QR codes offline
+func (h *TicketHandler) GetKeys(c *gin.Context) {
+	keys := h.ticketService.Keys()
+	resp := make([]response.TicketKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		resp = append(resp, response.TicketKeyResponse{
+			KeyID:     key.KeyID,
+			Algorithm: key.Algorithm,
+			PublicKey: key.PublicKey,
+		})
+	}
+
+	response.Success(c, http.StatusOK, "Ticket keys retrieved", resp)
+}
+
+// SyncCheckIns records the tickets a scanner admitted at an event's doors while offline, and
+// returns the outcome of each scan in upload order (admin only)
+func (h *TicketHandler) SyncCheckIns(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	var req request.SyncCheckInsRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	results, err := h.ticketService.SyncOfflineCheckIns(requestContext(c), uint(eventID), nil, toOfflineCheckIns(req.CheckIns))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	resp := make([]response.OfflineCheckInResult, 0, len(results))
+	for _, result := range results {
+		resp = append(resp, toOfflineCheckInResult(result))
+	}
+
+	response.Success(c, http.StatusOK, "Check-ins synced", resp)
+}
+
+func toOfflineCheckIns(reqs []request.OfflineCheckInRequest) []services.OfflineCheckIn {
+	checkIns := make([]services.OfflineCheckIn, 0, len(reqs))
+	for _, checkIn := range reqs {
+		checkIns = append(checkIns, services.OfflineCheckIn{Token: checkIn.Token, ScannedAt: checkIn.ScannedAt})
+	}
+	return checkIns
+}
+
+func toOfflineCheckInResult(result services.OfflineCheckInResult) response.OfflineCheckInResult {
+	return response.OfflineCheckInResult{
+		BookingID:   result.BookingID,
+		Outcome:     result.Outcome,
+		Reason:      result.Reason,
+		CheckedInAt: result.CheckedInAt,
+	}
+}
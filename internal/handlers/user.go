@@ -3,23 +3,27 @@ package handlers
 import (
 	"api/internal/services"
 	"api/pkg/errors"
+	logger "api/pkg/logging"
 	"api/pkg/request"
 	"api/pkg/response"
 	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 type UserHandler struct {
-	userService services.UserServiceInterface
-	jwtService  services.JWTServiceInterface
+	userService   services.UserServiceInterface
+	jwtService    services.JWTServiceInterface
+	policyService services.PolicyServiceInterface
 }
 
-func NewUserHandler(userService services.UserServiceInterface, jwtService services.JWTServiceInterface) *UserHandler {
+func NewUserHandler(userService services.UserServiceInterface, jwtService services.JWTServiceInterface, policyService services.PolicyServiceInterface) *UserHandler {
 	return &UserHandler{
-		userService: userService,
-		jwtService:  jwtService,
+		userService:   userService,
+		jwtService:    jwtService,
+		policyService: policyService,
 	}
 }
 
@@ -30,19 +34,32 @@ func (h *UserHandler) Register(c *gin.Context) {
 		return
 	}
 
+	// Users accept the current terms of service and privacy policy to sign up
+	consent := policyConsent(req.PolicyAcceptanceRequest)
+	if err := h.policyService.CheckAcceptance(consent); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
 	user, err := h.userService.Register(context.Background(), req.Email, req.Password, req.FirstName, req.LastName, req.Phone, req.IsAdmin)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
+	// Unrecorded acceptances are asked for again by the policy gate after login
+	if _, err := h.policyService.Accept(requestContext(c), user.ID, consent, c.ClientIP()); err != nil {
+		logger.Warnf("Failed to record policy acceptance of user %d: %v", user.ID, err)
+	}
+
 	userResp := response.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Phone:     user.Phone,
-		IsAdmin:   user.IsAdmin,
+		ID:             user.ID,
+		Email:          user.Email,
+		FirstName:      user.FirstName,
+		LastName:       user.LastName,
+		Phone:          user.Phone,
+		IsAdmin:        user.IsAdmin,
+		MembershipTier: user.MembershipTier,
 	}
 
 	response.Success(c, http.StatusCreated, "user registered successfully", userResp)
@@ -61,7 +78,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.jwtService.GenerateToken(user.ID, user.IsAdmin)
+	token, err := h.jwtService.GenerateToken(user.ID, user.IsAdmin, user.TenantID, user.TokenVersion)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -76,6 +93,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 			LastName:  user.LastName,
 			Phone:     user.Phone,
 			IsAdmin:   user.IsAdmin,
+			TenantID:  user.TenantID,
 		},
 	}
 
@@ -96,17 +114,59 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 	}
 
 	userResp := response.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Phone:     user.Phone,
-		IsAdmin:   user.IsAdmin,
+		ID:             user.ID,
+		Email:          user.Email,
+		FirstName:      user.FirstName,
+		LastName:       user.LastName,
+		Phone:          user.Phone,
+		IsAdmin:        user.IsAdmin,
+		MembershipTier: user.MembershipTier,
 	}
 
 	response.JSON(c, http.StatusOK, userResp)
 }
 
+// ChangePassword replaces the caller's password, signing out every session. The caller gets a
+// new token to stay signed in.
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req request.ChangePasswordRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	user, err := h.userService.ChangePassword(requestContext(c), userID.(uint), req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	token, err := h.jwtService.GenerateToken(user.ID, user.IsAdmin, user.TenantID, user.TokenVersion)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "password changed", response.LoginResponse{
+		Token: token,
+		User: response.UserResponse{
+			ID:        user.ID,
+			Email:     user.Email,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Phone:     user.Phone,
+			IsAdmin:   user.IsAdmin,
+			TenantID:  user.TenantID,
+		},
+	})
+}
+
 func (h *UserHandler) ListUsers(c *gin.Context) {
 	// This would be an admin-only endpoint
 	// For now, just return a placeholder
@@ -115,6 +175,46 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 	})
 }
 
+// SetMembershipTier assigns a user's membership tier, used to order event waitlists (admin only)
+func (h *UserHandler) SetMembershipTier(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	var req request.SetMembershipTierRequest
+	if err := request.BindJSON(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	tier := ""
+	if req.Tier != "" {
+		tier, err = services.NormalizeWaitlistTiers([]string{req.Tier})
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+	}
+
+	user, err := h.userService.SetMembershipTier(context.Background(), uint(userID), tier)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "membership tier updated", response.UserResponse{
+		ID:             user.ID,
+		Email:          user.Email,
+		FirstName:      user.FirstName,
+		LastName:       user.LastName,
+		Phone:          user.Phone,
+		IsAdmin:        user.IsAdmin,
+		MembershipTier: user.MembershipTier,
+	})
+}
+
 // handleError converts application errors to appropriate HTTP responses
 func (h *UserHandler) handleError(c *gin.Context, err error) {
 	if appErr, ok := err.(*errors.AppError); ok {
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"api/internal/enrichment"
 	"api/internal/entities"
+	"api/internal/expand"
 	"api/internal/services"
 	"api/pkg/errors"
 	"api/pkg/request"
@@ -31,8 +33,23 @@ func (h *VenueHandler) GetVenues(c *gin.Context) {
 		return
 	}
 
-	offset := (req.Page - 1) * req.Limit
-	venues, total, err := h.venueService.GetVenues(context.Background(), req.Limit, offset, req.City)
+	metadata, err := request.ParseMetadataFilters(req.Metadata)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+	fields, err := response.ParseFields(req.Fields, response.VenueResponse{})
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+	if _, _, err := loadRelations(req.FieldsRequest, fields, response.VenueResponse{}); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+
+	offset := req.Offset()
+	venues, total, err := h.venueService.GetVenues(context.Background(), req.Limit, offset, req.City, metadata)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -52,10 +69,16 @@ func (h *VenueHandler) GetVenues(c *gin.Context) {
 			Columns:     venue.Columns,
 			Capacity:    venue.Rows * venue.Columns,
 			Description: venue.Description,
+			Metadata:    venue.Metadata,
+
+			SalesCloseMinutesBeforeStart: venue.SalesCloseMinutesBeforeStart,
+			Latitude:                     venue.Latitude,
+			Longitude:                    venue.Longitude,
+			Enrichments:                  enrichment.ParseNames(venue.Enrichments),
 		}
 	}
 
-	response.Paginated(c, http.StatusOK, venueResponses, req.Page, req.Limit, total)
+	response.PaginatedFields(c, http.StatusOK, venueResponses, fields, req.Page, req.Limit, total)
 }
 
 // GetVenueByID returns a single venue with details
@@ -67,7 +90,23 @@ func (h *VenueHandler) GetVenueByID(c *gin.Context) {
 		return
 	}
 
-	venue, err := h.venueService.GetVenueByID(context.Background(), uint(venueID))
+	var req request.FieldsRequest
+	if err := request.BindQuery(c, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+	fields, err := response.ParseFields(req.Fields, response.VenueDetailResponse{})
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+	relations, fields, err := loadRelations(req, fields, response.VenueDetailResponse{}, expand.Events)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request parameters", err.Error())
+		return
+	}
+
+	venue, err := h.venueService.GetVenueByID(expand.With(context.Background(), relations), uint(venueID))
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -101,11 +140,17 @@ func (h *VenueHandler) GetVenueByID(c *gin.Context) {
 			Columns:     venue.Columns,
 			Capacity:    venue.Rows * venue.Columns,
 			Description: venue.Description,
+			Metadata:    venue.Metadata,
+
+			SalesCloseMinutesBeforeStart: venue.SalesCloseMinutesBeforeStart,
+			Latitude:                     venue.Latitude,
+			Longitude:                    venue.Longitude,
+			Enrichments:                  enrichment.ParseNames(venue.Enrichments),
 		},
 		Events: eventResponses,
 	}
 
-	response.JSON(c, http.StatusOK, venueResp)
+	response.JSONFields(c, http.StatusOK, venueResp, fields)
 }
 
 // CreateVenue creates a new venue (admin only)
@@ -116,7 +161,14 @@ func (h *VenueHandler) CreateVenue(c *gin.Context) {
 		return
 	}
 
+	enrichments, err := enrichment.NormalizeNames(req.Enrichments)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
 	venue := &entities.Venue{
+		TenantID:    req.TenantID,
 		Name:        req.Name,
 		Address:     req.Address,
 		City:        req.City,
@@ -125,9 +177,15 @@ func (h *VenueHandler) CreateVenue(c *gin.Context) {
 		Rows:        req.Rows,
 		Columns:     req.Columns,
 		Description: req.Description,
+		Metadata:    req.Metadata,
+
+		SalesCloseMinutesBeforeStart: req.SalesCloseMinutesBeforeStart,
+		Latitude:                     req.Latitude,
+		Longitude:                    req.Longitude,
+		Enrichments:                  enrichments,
 	}
 
-	if err := h.venueService.CreateVenue(context.Background(), venue); err != nil {
+	if err := h.venueService.CreateVenue(requestContext(c), venue); err != nil {
 		h.handleError(c, err)
 		return
 	}
@@ -176,8 +234,28 @@ func (h *VenueHandler) UpdateVenue(c *gin.Context) {
 	if req.Description != nil {
 		updates["description"] = *req.Description
 	}
+	if req.Metadata != nil {
+		updates["metadata"] = entities.Metadata(*req.Metadata)
+	}
+	if req.SalesCloseMinutesBeforeStart != nil {
+		updates["sales_close_minutes_before_start"] = *req.SalesCloseMinutesBeforeStart
+	}
+	if req.Latitude != nil {
+		updates["latitude"] = *req.Latitude
+	}
+	if req.Longitude != nil {
+		updates["longitude"] = *req.Longitude
+	}
+	if req.Enrichments != nil {
+		enrichments, err := enrichment.NormalizeNames(*req.Enrichments)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request", err.Error())
+			return
+		}
+		updates["enrichments"] = enrichments
+	}
 
-	venue, err := h.venueService.UpdateVenue(context.Background(), uint(venueID), updates)
+	venue, err := h.venueService.UpdateVenue(requestContext(c), uint(venueID), updates)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -195,7 +273,7 @@ func (h *VenueHandler) DeleteVenue(c *gin.Context) {
 		return
 	}
 
-	if err := h.venueService.DeleteVenue(context.Background(), uint(venueID)); err != nil {
+	if err := h.venueService.DeleteVenue(requestContext(c), uint(venueID)); err != nil {
 		h.handleError(c, err)
 		return
 	}
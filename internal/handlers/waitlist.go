@@ -48,6 +48,7 @@ func (h *WaitlistHandler) JoinWaitlist(c *gin.Context) {
 		Position: entry.Position,
 		JoinedAt: entry.JoinedAt,
 		Status:   "waiting",
+		Tier:     entry.Tier,
 	}
 
 	response.Success(c, http.StatusCreated, "Successfully joined waitlist", waitlistResp)
@@ -88,6 +89,7 @@ func (h *WaitlistHandler) GetWaitlistPosition(c *gin.Context) {
 		Status:     status,
 		JoinedAt:   entry.JoinedAt,
 		NotifiedAt: entry.NotifiedAt,
+		Tier:       entry.Tier,
 	}
 
 	response.Success(c, http.StatusOK, "Waitlist position retrieved", waitlistResp)
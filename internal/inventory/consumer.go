@@ -0,0 +1,133 @@
+package inventory
+
+import (
+	"api/pkg/errors"
+	logger "api/pkg/logging"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Handler applies a message to the local seat state. Bad request, not found and conflict app
+// errors reject the message at once; other errors are retried.
+type Handler func(ctx context.Context, msg *Message) error
+
+// Consumer feeds the messages of a source to a handler, one at a time. Rejected messages and
+// those failing maxAttempts times are dead lettered.
+type Consumer struct {
+	source      Source
+	handler     Handler
+	maxAttempts int64
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+}
+
+func NewConsumer(source Source, handler Handler, maxAttempts int) *Consumer {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &Consumer{
+		source:      source,
+		handler:     handler,
+		maxAttempts: int64(maxAttempts),
+	}
+}
+
+// Start launches the consume loop
+func (c *Consumer) Start(ctx context.Context) {
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.wg.Add(1)
+	go c.consume(ctx)
+	logger.Info("Inventory sync consumer started")
+}
+
+// Stop stops fetching and waits for the message being handled
+func (c *Consumer) Stop() {
+	if c.cancel == nil {
+		return
+	}
+	c.cancel()
+	c.wg.Wait()
+	logger.Info("Inventory sync consumer stopped")
+}
+
+func (c *Consumer) consume(ctx context.Context) {
+	defer c.wg.Done()
+
+	for ctx.Err() == nil {
+		deliveries, err := c.source.Fetch(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Errorf("Failed to fetch inventory messages: %v", err)
+				select {
+				case <-ctx.Done():
+				case <-time.After(5 * time.Second):
+				}
+			}
+			continue
+		}
+
+		for _, delivery := range deliveries {
+			// A message being handled is finished on shutdown rather than cut off halfway
+			c.Process(context.WithoutCancel(ctx), delivery)
+		}
+	}
+}
+
+// Process handles one delivery and acknowledges, dead letters or leaves it for a retry
+func (c *Consumer) Process(ctx context.Context, delivery Delivery) {
+	msg, err := Decode(delivery.Payload)
+	if err != nil {
+		c.deadLetter(ctx, delivery, err.Error())
+		return
+	}
+
+	err = c.handle(ctx, msg)
+	if err == nil {
+		if err := c.source.Ack(ctx, delivery); err != nil {
+			logger.Errorf("Failed to ack inventory message: %v", err)
+		}
+		return
+	}
+
+	if reason, rejected := rejection(err); rejected {
+		c.deadLetter(ctx, delivery, reason)
+		return
+	}
+	if delivery.Attempts >= c.maxAttempts {
+		c.deadLetter(ctx, delivery, fmt.Sprintf("failed after %d attempts: %v", delivery.Attempts, err))
+		return
+	}
+	logger.Warnf("Inventory message %s (attempt %d) failed, will retry: %v", delivery.ID, delivery.Attempts, err)
+}
+
+// handle calls the handler, turning a panic into an error so the consumer survives
+func (c *Consumer) handle(ctx context.Context, msg *Message) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return c.handler(ctx, msg)
+}
+
+func (c *Consumer) deadLetter(ctx context.Context, delivery Delivery, reason string) {
+	logger.Warnf("Dead-lettering inventory message %s: %s", delivery.ID, reason)
+	if err := c.source.DeadLetter(ctx, delivery, reason); err != nil {
+		logger.Errorf("Failed to dead-letter inventory message: %v", err)
+	}
+}
+
+// rejection reports whether retrying can't help, with the reason to record
+func rejection(err error) (string, bool) {
+	appErr, ok := err.(*errors.AppError)
+	if !ok {
+		return "", false
+	}
+	switch appErr.Type {
+	case "BAD_REQUEST", "NOT_FOUND", "CONFLICT":
+		return appErr.Message, true
+	}
+	return "", false
+}
@@ -0,0 +1,77 @@
+package inventory
+
+import (
+	apperrors "api/pkg/errors"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type fakeSource struct {
+	acked        []string
+	deadLettered map[string]string
+}
+
+func (s *fakeSource) Fetch(ctx context.Context) ([]Delivery, error) { return nil, nil }
+
+func (s *fakeSource) Ack(ctx context.Context, delivery Delivery) error {
+	s.acked = append(s.acked, delivery.ID)
+	return nil
+}
+
+func (s *fakeSource) DeadLetter(ctx context.Context, delivery Delivery, reason string) error {
+	if s.deadLettered == nil {
+		s.deadLettered = make(map[string]string)
+	}
+	s.deadLettered[delivery.ID] = reason
+	return nil
+}
+
+const holdPayload = `{"id":"m1","source":"venue","action":"HOLD","event_id":3,"row":2,"column":5,"hold_ref":"H-1","occurred_at":"2026-05-01T10:00:00Z"}`
+
+func TestConsumerProcess(t *testing.T) {
+	tests := []struct {
+		name       string
+		payload    string
+		attempts   int64
+		err        error
+		wantAcked  bool
+		wantReason string // empty when the message isn't dead lettered
+	}{
+		{name: "applied", payload: holdPayload, attempts: 1, wantAcked: true},
+		{name: "malformed", payload: `{"action":`, attempts: 1, wantReason: "invalid message"},
+		{name: "unknown action", payload: `{"action":"sell","event_id":3,"seat_id":1,"hold_ref":"H","occurred_at":"2026-05-01T10:00:00Z"}`, attempts: 1, wantReason: `unknown action "sell"`},
+		{name: "no seat", payload: `{"action":"hold","event_id":3,"hold_ref":"H","occurred_at":"2026-05-01T10:00:00Z"}`, attempts: 1, wantReason: "seat_id or row and column are required"},
+		{name: "conflict", payload: holdPayload, attempts: 1, err: apperrors.NewConflictError("Seat is already sold", nil), wantReason: "Seat is already sold"},
+		{name: "transient failure retried", payload: holdPayload, attempts: 2, err: errors.New("connection reset")},
+		{name: "out of attempts", payload: holdPayload, attempts: 3, err: errors.New("connection reset"), wantReason: "failed after 3 attempts: connection reset"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &fakeSource{}
+			var handled *Message
+			consumer := NewConsumer(source, func(ctx context.Context, msg *Message) error {
+				handled = msg
+				return tt.err
+			}, 3)
+
+			consumer.Process(context.Background(), Delivery{ID: "1-0", Payload: tt.payload, Attempts: tt.attempts})
+
+			if acked := len(source.acked) == 1; acked != tt.wantAcked {
+				t.Errorf("acked = %v, want %v", acked, tt.wantAcked)
+			}
+			reason, deadLettered := source.deadLettered["1-0"]
+			if deadLettered != (tt.wantReason != "") {
+				t.Fatalf("dead lettered = %v (%q), want reason %q", deadLettered, reason, tt.wantReason)
+			}
+			if deadLettered && !strings.HasPrefix(reason, tt.wantReason) {
+				t.Errorf("reason = %q, want prefix %q", reason, tt.wantReason)
+			}
+			if tt.name == "applied" && (handled == nil || handled.Action != ActionHold || handled.Row != 2) {
+				t.Errorf("handled %+v, want the decoded hold", handled)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Backends
+const (
+	BackendNone  = "none"
+	BackendRedis = "redis"
+)
+
+// Actions
+const (
+	ActionHold    = "hold"    // the external system took the seat, e.g. sold it at its own box office
+	ActionRelease = "release" // the external system gave the seat back
+)
+
+// Message is a seat hold or release sent by a venue's external inventory system. The seat is
+// given by ID, or by its row and column in the event's seat map.
+type Message struct {
+	ID         string    `json:"id"`     // the sender's message ID, for tracing
+	Source     string    `json:"source"` // the sending system
+	Action     string    `json:"action"`
+	EventID    uint      `json:"event_id"`
+	SeatID     uint      `json:"seat_id"`
+	Row        int       `json:"row"`
+	Column     int       `json:"column"`
+	HoldRef    string    `json:"hold_ref"` // the external system's reference for the hold
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Decode parses and checks a message payload
+func Decode(payload string) (*Message, error) {
+	var msg Message
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		return nil, fmt.Errorf("invalid message: %w", err)
+	}
+	msg.Action = strings.ToLower(strings.TrimSpace(msg.Action))
+	msg.HoldRef = strings.TrimSpace(msg.HoldRef)
+	switch {
+	case msg.Action != ActionHold && msg.Action != ActionRelease:
+		return nil, fmt.Errorf("unknown action %q", msg.Action)
+	case msg.EventID == 0:
+		return nil, fmt.Errorf("event_id is required")
+	case msg.SeatID == 0 && (msg.Row < 1 || msg.Column < 1):
+		return nil, fmt.Errorf("seat_id or row and column are required")
+	case msg.HoldRef == "" || len(msg.HoldRef) > 100:
+		return nil, fmt.Errorf("hold_ref is required, up to 100 characters")
+	case msg.OccurredAt.IsZero():
+		return nil, fmt.Errorf("occurred_at is required")
+	}
+	return &msg, nil
+}
+
+// Delivery is a message received from a source, not yet acknowledged
+type Delivery struct {
+	ID       string // the source's ID for the delivery
+	Payload  string
+	Attempts int64 // how many times the message has been delivered, this time included
+}
+
+// Source delivers messages from a broker. A delivery that is neither acknowledged nor dead
+// lettered is delivered again once the source's retry delay has passed.
+type Source interface {
+	// Fetch waits briefly for the next deliveries, retries first
+	Fetch(ctx context.Context) ([]Delivery, error)
+	// Ack marks a delivery as processed
+	Ack(ctx context.Context, delivery Delivery) error
+	// DeadLetter moves a delivery that can't be processed to the dead-letter queue
+	DeadLetter(ctx context.Context, delivery Delivery, reason string) error
+}
+
+// Config selects and configures the source of inventory messages
+type Config struct {
+	Backend string
+
+	// Redis backend: the stream the messages are added to, the consumer group this API reads
+	// it with and the stream dead letters go to
+	Stream           string
+	Group            string
+	DeadLetterStream string
+	RetryAfter       time.Duration
+}
+
+// New returns the source selected by the config, or nil when inventory sync is disabled
+func New(cfg Config, redisClient redis.UniversalClient) (Source, error) {
+	switch cfg.Backend {
+	case "", BackendNone:
+		return nil, nil
+	case BackendRedis:
+		return NewRedisStreamSource(redisClient, cfg.Stream, cfg.Group, cfg.DeadLetterStream, cfg.RetryAfter)
+	default:
+		return nil, fmt.Errorf("unknown inventory sync backend %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,174 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// fetchBlock is how long a fetch waits for new messages
+	fetchBlock = 2 * time.Second
+	// fetchCount bounds the messages handled per fetch
+	fetchCount = 50
+	// payloadField is the stream entry field holding the JSON message
+	payloadField = "payload"
+)
+
+// RedisStreamSource reads messages from a Redis stream through a consumer group, so several
+// API instances share the work. Kafka or NATS topics can be bridged into the stream.
+type RedisStreamSource struct {
+	redis            redis.UniversalClient
+	stream           string
+	group            string
+	consumer         string
+	deadLetterStream string
+	retryAfter       time.Duration
+	groupReady       bool
+}
+
+func NewRedisStreamSource(redisClient redis.UniversalClient, stream, group, deadLetterStream string, retryAfter time.Duration) (*RedisStreamSource, error) {
+	if stream == "" || group == "" || deadLetterStream == "" {
+		return nil, fmt.Errorf("inventory sync stream, group and dead-letter stream are required")
+	}
+	if retryAfter <= 0 {
+		retryAfter = 30 * time.Second
+	}
+	hostname, _ := os.Hostname()
+	return &RedisStreamSource{
+		redis:            redisClient,
+		stream:           stream,
+		group:            group,
+		consumer:         fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		deadLetterStream: deadLetterStream,
+		retryAfter:       retryAfter,
+	}, nil
+}
+
+// Fetch claims messages that failed at least retryAfter ago, here or on another instance,
+// and otherwise waits for new ones
+func (s *RedisStreamSource) Fetch(ctx context.Context) ([]Delivery, error) {
+	if err := s.ensureGroup(ctx); err != nil {
+		return nil, err
+	}
+
+	retries, err := s.claimRetries(ctx)
+	if err != nil || len(retries) > 0 {
+		return retries, err
+	}
+
+	streams, err := s.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    s.group,
+		Consumer: s.consumer,
+		Streams:  []string{s.stream, ">"},
+		Count:    fetchCount,
+		Block:    fetchBlock,
+	}).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read inventory stream: %w", err)
+	}
+
+	var deliveries []Delivery
+	for _, stream := range streams {
+		for _, message := range stream.Messages {
+			deliveries = append(deliveries, toDelivery(message, 1))
+		}
+	}
+	return deliveries, nil
+}
+
+// claimRetries takes over pending messages idle for at least retryAfter
+func (s *RedisStreamSource) claimRetries(ctx context.Context) ([]Delivery, error) {
+	pending, err := s.redis.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: s.stream,
+		Group:  s.group,
+		Idle:   s.retryAfter,
+		Start:  "-",
+		End:    "+",
+		Count:  fetchCount,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("list pending inventory messages: %w", err)
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(pending))
+	attempts := make(map[string]int64, len(pending))
+	for i, p := range pending {
+		ids[i] = p.ID
+		attempts[p.ID] = p.RetryCount + 1
+	}
+	// Another instance may claim some first; those aren't returned here
+	messages, err := s.redis.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   s.stream,
+		Group:    s.group,
+		Consumer: s.consumer,
+		MinIdle:  s.retryAfter,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("claim pending inventory messages: %w", err)
+	}
+
+	deliveries := make([]Delivery, 0, len(messages))
+	for _, message := range messages {
+		deliveries = append(deliveries, toDelivery(message, attempts[message.ID]))
+	}
+	return deliveries, nil
+}
+
+// Ack acknowledges a message and removes it from the stream
+func (s *RedisStreamSource) Ack(ctx context.Context, delivery Delivery) error {
+	pipe := s.redis.TxPipeline()
+	pipe.XAck(ctx, s.stream, s.group, delivery.ID)
+	pipe.XDel(ctx, s.stream, delivery.ID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("ack inventory message %s: %w", delivery.ID, err)
+	}
+	return nil
+}
+
+// DeadLetter copies a message to the dead-letter stream with the reason it was rejected, then
+// acknowledges it
+func (s *RedisStreamSource) DeadLetter(ctx context.Context, delivery Delivery, reason string) error {
+	if err := s.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.deadLetterStream,
+		Values: map[string]interface{}{
+			payloadField: delivery.Payload,
+			"message_id": delivery.ID,
+			"reason":     reason,
+			"attempts":   delivery.Attempts,
+			"failed_at":  time.Now().UTC().Format(time.RFC3339),
+		},
+	}).Err(); err != nil {
+		return fmt.Errorf("dead-letter inventory message %s: %w", delivery.ID, err)
+	}
+	return s.Ack(ctx, delivery)
+}
+
+// ensureGroup creates the stream and consumer group the first time they are needed
+func (s *RedisStreamSource) ensureGroup(ctx context.Context) error {
+	if s.groupReady {
+		return nil
+	}
+	err := s.redis.XGroupCreateMkStream(ctx, s.stream, s.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("create inventory consumer group: %w", err)
+	}
+	s.groupReady = true
+	return nil
+}
+
+func toDelivery(message redis.XMessage, attempts int64) Delivery {
+	payload, _ := message.Values[payloadField].(string)
+	return Delivery{ID: message.ID, Payload: payload, Attempts: attempts}
+}
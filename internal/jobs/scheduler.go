@@ -0,0 +1,195 @@
+package jobs
+
+import (
+	redisconn "api/internal/redis"
+	logger "api/pkg/logging"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// jobLease is how long a claimed job's lock lasts without renewal; it is renewed every third
+// of it while the job runs, so a crashed instance holds a job for at most this long
+const jobLease = 30 * time.Second
+
+// Job is a unit of periodic background work
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Locker coordinates jobs across instances, so each job runs once per interval on one of them
+type Locker interface {
+	// Claim claims the job's run for the interval starting at slot, holding its lock for lease.
+	// False means the interval already ran or the job is running elsewhere.
+	Claim(ctx context.Context, job, owner string, slot time.Time, interval, lease time.Duration) (bool, error)
+	// Renew extends the job's lock. False means the lock was lost.
+	Renew(ctx context.Context, job, owner string, lease time.Duration) (bool, error)
+	// Release drops the job's lock once its run is over
+	Release(ctx context.Context, job, owner string) error
+}
+
+// Scheduler runs registered jobs on fixed intervals until stopped
+type Scheduler struct {
+	jobs   []Job
+	locker Locker
+	owner  string // identifies this instance to the locker
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// WithLocker coordinates the jobs with the other instances through locker. Runs are then
+// aligned to interval boundaries, and every instance tries to claim each run; the one that
+// does runs it. While the locker is unreachable, every instance runs its jobs.
+func (s *Scheduler) WithLocker(locker Locker) *Scheduler {
+	hostname, _ := os.Hostname()
+	s.locker = locker
+	s.owner = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	return s
+}
+
+// Register adds a job to the scheduler. Jobs must be registered before Start.
+func (s *Scheduler) Register(name string, interval time.Duration, run func(ctx context.Context) error) {
+	s.jobs = append(s.jobs, Job{Name: name, Interval: interval, Run: run})
+}
+
+// Start launches one goroutine per registered job
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		if s.locker != nil {
+			go s.coordinatedLoop(ctx, job)
+		} else {
+			go s.loop(ctx, job)
+		}
+	}
+	logger.Infof("Job scheduler started with %d jobs", len(s.jobs))
+}
+
+// Stop cancels all jobs and waits for in-flight runs to finish
+func (s *Scheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+	logger.Info("Job scheduler stopped")
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.run(ctx, job)
+		}
+	}
+}
+
+// coordinatedLoop wakes at every interval boundary, so all instances agree on the interval a
+// run belongs to, and runs the job if this instance claims it
+func (s *Scheduler) coordinatedLoop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	for {
+		slot := time.Now().Truncate(job.Interval).Add(job.Interval)
+		timer := time.NewTimer(time.Until(slot))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.claimAndRun(ctx, job, slot)
+		}
+	}
+}
+
+// claimAndRun runs the job for the interval starting at slot unless another instance claimed
+// it, renewing the job's lock until the run is over
+func (s *Scheduler) claimAndRun(ctx context.Context, job Job, slot time.Time) {
+	claimed, err := s.locker.Claim(ctx, job.Name, s.owner, slot, job.Interval, jobLease)
+	if err != nil {
+		// Without the locker jobs run on every instance, as they would uncoordinated
+		if !redisconn.IsUnavailable(err) {
+			logger.Warnf("Failed to claim job %s, running it anyway: %v", job.Name, err)
+		}
+		s.run(ctx, job)
+		return
+	}
+	if !claimed {
+		logger.Debugf("Job %s skipped: already run or running on another instance", job.Name)
+		return
+	}
+
+	renewCtx, stopRenewal := context.WithCancel(ctx)
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		s.renew(renewCtx, job)
+	}()
+
+	s.run(ctx, job)
+
+	stopRenewal()
+	<-renewed
+	// Release even when stopping, so another instance can take over without waiting out the lease
+	if err := s.locker.Release(context.WithoutCancel(ctx), job.Name, s.owner); err != nil && !redisconn.IsUnavailable(err) {
+		logger.Warnf("Failed to release job %s: %v", job.Name, err)
+	}
+}
+
+// renew keeps the job's lock until ctx is done
+func (s *Scheduler) renew(ctx context.Context, job Job) {
+	ticker := time.NewTicker(jobLease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			held, err := s.locker.Renew(ctx, job.Name, s.owner, jobLease)
+			if err != nil {
+				if ctx.Err() == nil && !redisconn.IsUnavailable(err) {
+					logger.Warnf("Failed to renew job %s: %v", job.Name, err)
+				}
+				continue
+			}
+			if !held {
+				logger.Warnf("Job %s lost its lock while running; another instance may start it", job.Name)
+				return
+			}
+		}
+	}
+}
+
+// run executes a single job iteration, recovering from panics so one bad run doesn't kill the loop
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("Job %s panicked: %v", job.Name, r)
+		}
+	}()
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		logger.Errorf("Job %s failed after %s: %v", job.Name, time.Since(start), err)
+		return
+	}
+	logger.Debugf("Job %s completed in %s", job.Name, time.Since(start))
+}
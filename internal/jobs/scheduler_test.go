@@ -0,0 +1,79 @@
+package jobs_test
+
+import (
+	"api/internal/jobs"
+	redisconn "api/internal/redis"
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeLocker lets one instance claim each interval, like the Redis locker
+type fakeLocker struct {
+	mu          sync.Mutex
+	claimed     map[time.Time]string
+	unavailable bool
+}
+
+func (l *fakeLocker) Claim(ctx context.Context, job, owner string, slot time.Time, interval, lease time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.unavailable {
+		return false, redisconn.ErrUnavailable
+	}
+	if _, ok := l.claimed[slot]; ok {
+		return false, nil
+	}
+	l.claimed[slot] = owner
+	return true, nil
+}
+
+func (l *fakeLocker) Renew(ctx context.Context, job, owner string, lease time.Duration) (bool, error) {
+	return true, nil
+}
+
+func (l *fakeLocker) Release(ctx context.Context, job, owner string) error {
+	return nil
+}
+
+func runInstances(locker *fakeLocker, instances int, interval, duration time.Duration) int64 {
+	var runs atomic.Int64
+	schedulers := make([]*jobs.Scheduler, instances)
+	for i := range schedulers {
+		schedulers[i] = jobs.NewScheduler().WithLocker(locker)
+		schedulers[i].Register("cleanup", interval, func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		})
+		schedulers[i].Start(context.Background())
+	}
+	time.Sleep(duration)
+	for _, s := range schedulers {
+		s.Stop()
+	}
+	return runs.Load()
+}
+
+func TestCoordinatedJobsRunOncePerInterval(t *testing.T) {
+	locker := &fakeLocker{claimed: make(map[time.Time]string)}
+
+	runs := runInstances(locker, 3, 50*time.Millisecond, 300*time.Millisecond)
+
+	locker.mu.Lock()
+	defer locker.mu.Unlock()
+	assert.Positive(t, runs)
+	assert.Equal(t, int64(len(locker.claimed)), runs)
+}
+
+func TestJobsRunEverywhereWhileLockerUnavailable(t *testing.T) {
+	locker := &fakeLocker{claimed: make(map[time.Time]string), unavailable: true}
+
+	runs := runInstances(locker, 3, 50*time.Millisecond, 300*time.Millisecond)
+
+	// Every instance runs every interval, as it would uncoordinated
+	assert.GreaterOrEqual(t, runs, int64(3*4))
+}
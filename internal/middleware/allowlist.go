@@ -0,0 +1,293 @@
+package middleware
+
+import (
+	"api/pkg/errors"
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Allowlist entry types and sources
+const (
+	AllowlistIP   = "ip"
+	AllowlistUser = "user"
+
+	AllowlistSourceConfig  = "config"
+	AllowlistSourceRuntime = "runtime"
+)
+
+const (
+	allowlistIPsKey   = "rate_limit:allowlist:ips"
+	allowlistUsersKey = "rate_limit:allowlist:users"
+	// Runtime entries are cached in memory so the limiter doesn't add a Redis round trip
+	// per request; changes made on another instance apply within this interval
+	allowlistRefreshInterval = 10 * time.Second
+)
+
+// AllowlistEntry is an IP, CIDR range or user ID exempt from rate limiting
+type AllowlistEntry struct {
+	Type   string
+	Value  string
+	Source string
+}
+
+// Allowlist exempts monitoring probes and internal tooling from rate limiting. Entries
+// come from config and from a Redis set managed at runtime through the admin API.
+type Allowlist struct {
+	redis redis.UniversalClient
+
+	staticIPs   []*net.IPNet
+	staticUsers map[uint]bool
+
+	mu           sync.Mutex
+	runtimeIPs   []*net.IPNet
+	runtimeUsers map[uint]bool
+	loadedAt     time.Time
+}
+
+// NewAllowlist parses the configured entries: comma separated IPs or CIDR ranges, and user IDs
+func NewAllowlist(redis redis.UniversalClient, ips, users string) (*Allowlist, error) {
+	a := &Allowlist{
+		redis:       redis,
+		staticUsers: make(map[uint]bool),
+	}
+	for _, value := range splitList(ips) {
+		ipNet, err := parseIPEntry(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit allowlist IP %q: %w", value, err)
+		}
+		a.staticIPs = append(a.staticIPs, ipNet)
+	}
+	for _, value := range splitList(users) {
+		userID, err := parseUserEntry(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit allowlist user %q: %w", value, err)
+		}
+		a.staticUsers[userID] = true
+	}
+	return a, nil
+}
+
+// AllowsIP reports whether requests from the IP skip rate limiting
+func (a *Allowlist) AllowsIP(ctx context.Context, ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	if containsIP(a.staticIPs, parsed) {
+		return true
+	}
+	runtimeIPs, _ := a.runtime(ctx)
+	return containsIP(runtimeIPs, parsed)
+}
+
+// AllowsUser reports whether requests from the user skip rate limiting
+func (a *Allowlist) AllowsUser(ctx context.Context, userID uint) bool {
+	if a.staticUsers[userID] {
+		return true
+	}
+	_, runtimeUsers := a.runtime(ctx)
+	return runtimeUsers[userID]
+}
+
+// Entries lists the configured entries followed by the runtime ones
+func (a *Allowlist) Entries(ctx context.Context) ([]AllowlistEntry, error) {
+	var entries []AllowlistEntry
+	for _, ipNet := range a.staticIPs {
+		entries = append(entries, AllowlistEntry{Type: AllowlistIP, Value: formatIPEntry(ipNet), Source: AllowlistSourceConfig})
+	}
+	userIDs := make([]uint, 0, len(a.staticUsers))
+	for userID := range a.staticUsers {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+	for _, userID := range userIDs {
+		entries = append(entries, AllowlistEntry{Type: AllowlistUser, Value: strconv.FormatUint(uint64(userID), 10), Source: AllowlistSourceConfig})
+	}
+
+	ips, err := a.redis.SMembers(ctx, allowlistIPsKey).Result()
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to load rate limit allowlist", err)
+	}
+	users, err := a.redis.SMembers(ctx, allowlistUsersKey).Result()
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to load rate limit allowlist", err)
+	}
+	sort.Strings(ips)
+	sort.Strings(users)
+	for _, value := range ips {
+		entries = append(entries, AllowlistEntry{Type: AllowlistIP, Value: value, Source: AllowlistSourceRuntime})
+	}
+	for _, value := range users {
+		entries = append(entries, AllowlistEntry{Type: AllowlistUser, Value: value, Source: AllowlistSourceRuntime})
+	}
+	return entries, nil
+}
+
+// Add stores a runtime entry and returns it normalized, e.g. "10.0.0.7/8" becomes "10.0.0.0/8"
+func (a *Allowlist) Add(ctx context.Context, entryType, value string) (AllowlistEntry, error) {
+	key, normalized, err := a.normalize(entryType, value)
+	if err != nil {
+		return AllowlistEntry{}, err
+	}
+	if err := a.redis.SAdd(ctx, key, normalized).Err(); err != nil {
+		return AllowlistEntry{}, errors.NewInternalError("Failed to update rate limit allowlist", err)
+	}
+	a.invalidate()
+	return AllowlistEntry{Type: entryType, Value: normalized, Source: AllowlistSourceRuntime}, nil
+}
+
+// Remove deletes a runtime entry. Entries from config can only be removed by changing the config.
+func (a *Allowlist) Remove(ctx context.Context, entryType, value string) error {
+	key, normalized, err := a.normalize(entryType, value)
+	if err != nil {
+		return err
+	}
+	removed, err := a.redis.SRem(ctx, key, normalized).Result()
+	if err != nil {
+		return errors.NewInternalError("Failed to update rate limit allowlist", err)
+	}
+	if removed == 0 {
+		if a.isStatic(entryType, normalized) {
+			return errors.NewBadRequestError("Entry is set in config and can't be removed at runtime", nil)
+		}
+		return errors.NewNotFoundError("Allowlist entry not found", nil)
+	}
+	a.invalidate()
+	return nil
+}
+
+func (a *Allowlist) normalize(entryType, value string) (string, string, error) {
+	value = strings.TrimSpace(value)
+	switch entryType {
+	case AllowlistIP:
+		ipNet, err := parseIPEntry(value)
+		if err != nil {
+			return "", "", errors.NewBadRequestError("Value must be an IP address or CIDR range", err)
+		}
+		return allowlistIPsKey, formatIPEntry(ipNet), nil
+	case AllowlistUser:
+		userID, err := parseUserEntry(value)
+		if err != nil {
+			return "", "", errors.NewBadRequestError("Value must be a user ID", err)
+		}
+		return allowlistUsersKey, strconv.FormatUint(uint64(userID), 10), nil
+	default:
+		return "", "", errors.NewBadRequestError("Type must be ip or user", nil)
+	}
+}
+
+func (a *Allowlist) isStatic(entryType, normalized string) bool {
+	if entryType == AllowlistUser {
+		userID, _ := parseUserEntry(normalized)
+		return a.staticUsers[userID]
+	}
+	for _, ipNet := range a.staticIPs {
+		if formatIPEntry(ipNet) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+// runtime returns the cached runtime entries, reloading them from Redis when stale. If Redis
+// is unavailable the previous entries are kept so allowlisted clients stay exempt.
+func (a *Allowlist) runtime(ctx context.Context) ([]*net.IPNet, map[uint]bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if time.Since(a.loadedAt) < allowlistRefreshInterval {
+		return a.runtimeIPs, a.runtimeUsers
+	}
+	// Retry no sooner than the next interval, even if this load fails
+	a.loadedAt = time.Now()
+
+	ips, err := a.redis.SMembers(ctx, allowlistIPsKey).Result()
+	if err != nil {
+		return a.runtimeIPs, a.runtimeUsers
+	}
+	users, err := a.redis.SMembers(ctx, allowlistUsersKey).Result()
+	if err != nil {
+		return a.runtimeIPs, a.runtimeUsers
+	}
+
+	runtimeIPs := make([]*net.IPNet, 0, len(ips))
+	for _, value := range ips {
+		if ipNet, err := parseIPEntry(value); err == nil {
+			runtimeIPs = append(runtimeIPs, ipNet)
+		}
+	}
+	runtimeUsers := make(map[uint]bool, len(users))
+	for _, value := range users {
+		if userID, err := parseUserEntry(value); err == nil {
+			runtimeUsers[userID] = true
+		}
+	}
+	a.runtimeIPs, a.runtimeUsers = runtimeIPs, runtimeUsers
+	return runtimeIPs, runtimeUsers
+}
+
+// invalidate makes the next check reload the runtime entries
+func (a *Allowlist) invalidate() {
+	a.mu.Lock()
+	a.loadedAt = time.Time{}
+	a.mu.Unlock()
+}
+
+// parseIPEntry accepts a single IP or a CIDR range; a single IP becomes a /32 or /128 range
+func parseIPEntry(value string) (*net.IPNet, error) {
+	if strings.Contains(value, "/") {
+		_, ipNet, err := net.ParseCIDR(value)
+		return ipNet, err
+	}
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, fmt.Errorf("not an IP address")
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return &net.IPNet{IP: v4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+// formatIPEntry prints single-address ranges as a plain IP
+func formatIPEntry(ipNet *net.IPNet) string {
+	if ones, bits := ipNet.Mask.Size(); ones == bits {
+		return ipNet.IP.String()
+	}
+	return ipNet.String()
+}
+
+func parseUserEntry(value string) (uint, error) {
+	userID, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || userID == 0 {
+		return 0, fmt.Errorf("not a user ID")
+	}
+	return uint(userID), nil
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
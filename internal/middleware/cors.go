@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"api/pkg/response"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -23,6 +24,8 @@ func CORSMiddleware() gin.HandlerFunc {
 			"X-Rate-Limit-Limit",
 			"X-Rate-Limit-Remaining",
 			"X-Rate-Limit-Reset",
+			DegradedModeHeader,
+			response.TotalCountHeader,
 		},
 
 		AllowCredentials: true,
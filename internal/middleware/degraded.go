@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	redisconn "api/internal/redis"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DegradedModeHeader is set on every response while Redis is unavailable, so clients can show
+// a banner without polling GET /api/status
+const DegradedModeHeader = "X-Degraded-Mode"
+
+// DegradedMode flags responses served while Redis is unavailable
+func DegradedMode(health *redisconn.Health) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if health.Degraded() {
+			c.Header(DegradedModeHeader, "true")
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Drain stops an instance taking new work during a rolling deploy. Once started, mutation
+// requests such as new booking intents are refused with 503 so clients retry on another
+// instance, while mutations already running, such as booking confirmations, finish.
+type Drain struct {
+	mu       sync.Mutex
+	draining bool
+	inFlight int
+	idle     chan struct{} // closed once draining with no mutation in flight
+}
+
+func NewDrain() *Drain {
+	return &Drain{idle: make(chan struct{})}
+}
+
+// Start stops admitting mutations. It is safe to call more than once.
+func (d *Drain) Start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return
+	}
+	d.draining = true
+	if d.inFlight == 0 {
+		close(d.idle)
+	}
+}
+
+// Draining reports whether the instance stopped admitting mutations
+func (d *Drain) Draining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.draining
+}
+
+// Wait blocks until the mutations running when the drain started have finished, or ctx is done
+func (d *Drain) Wait(ctx context.Context) error {
+	select {
+	case <-d.idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RejectMutations refuses POST, PUT, PATCH and DELETE requests while draining and tracks the
+// ones admitted before, so Wait can tell when they are done. Reads are served until the
+// server stops.
+func (d *Drain) RejectMutations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isMutation(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if !d.admit() {
+			c.Header("Retry-After", "1")
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error":       "Server is restarting, please retry shortly",
+				"retry_after": 1,
+			})
+			c.Abort()
+			return
+		}
+		defer d.done()
+		c.Next()
+	}
+}
+
+func (d *Drain) admit() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return false
+	}
+	d.inFlight++
+	return true
+}
+
+func (d *Drain) done() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.inFlight--
+	if d.draining && d.inFlight == 0 {
+		close(d.idle)
+	}
+}
+
+func isMutation(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"api/internal/geoip"
+	logger "api/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GeoIP sets "country" in the context to the client's ISO country code. It is left unset when
+// lookups are disabled (a nil locator) and empty when the country can't be told.
+func GeoIP(locator geoip.Locator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if locator == nil {
+			c.Next()
+			return
+		}
+
+		country, err := locator.Country(c.Request.Context(), c.Request, c.ClientIP())
+		if err != nil {
+			logger.Warnf("Failed to locate client %s: %v", c.ClientIP(), err)
+		}
+		c.Set("country", country)
+		c.Next()
+	}
+}
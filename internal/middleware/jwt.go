@@ -4,18 +4,25 @@ import (
 	"api/internal/services"
 	"api/pkg/errors"
 	"api/pkg/response"
+	"context"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+// TokenVersions returns the version a user's tokens must carry to be accepted
+type TokenVersions interface {
+	TokenVersion(ctx context.Context, userID uint) (int, error)
+}
+
 type JWTMiddleware struct {
 	jwtService services.JWTServiceInterface
+	versions   TokenVersions
 }
 
-func NewJWTMiddleware(jwtService services.JWTServiceInterface) *JWTMiddleware {
-	return &JWTMiddleware{jwtService: jwtService}
+func NewJWTMiddleware(jwtService services.JWTServiceInterface, versions TokenVersions) *JWTMiddleware {
+	return &JWTMiddleware{jwtService: jwtService, versions: versions}
 }
 
 // AuthRequired middleware validates JWT token
@@ -35,13 +42,43 @@ func (m *JWTMiddleware) AuthRequired() gin.HandlerFunc {
 			return
 		}
 
-		// Set user information in context
-		if userID, ok := claims["user_id"].(float64); ok {
-			c.Set("user_id", uint(userID))
+		userID, ok := claims["user_id"].(float64)
+		if !ok {
+			response.Error(c, http.StatusUnauthorized, "invalid token")
+			c.Abort()
+			return
+		}
+		// Tokens issued before the user's credentials changed are revoked
+		version, ok := claims["ver"].(float64)
+		if !ok {
+			response.Error(c, http.StatusUnauthorized, "invalid token")
+			c.Abort()
+			return
+		}
+		current, err := m.versions.TokenVersion(c.Request.Context(), uint(userID))
+		if err != nil {
+			if appErr, ok := err.(*errors.AppError); ok && appErr.Type == "NOT_FOUND" {
+				response.Error(c, http.StatusUnauthorized, "invalid token")
+			} else {
+				response.Error(c, http.StatusInternalServerError, "internal server error")
+			}
+			c.Abort()
+			return
 		}
+		if int(version) != current {
+			response.Error(c, http.StatusUnauthorized, "token revoked")
+			c.Abort()
+			return
+		}
+
+		// Set user information in context
+		c.Set("user_id", uint(userID))
 		if isAdmin, ok := claims["is_admin"].(bool); ok {
 			c.Set("is_admin", isAdmin)
 		}
+		if tenantID, ok := claims["tenant_id"].(float64); ok {
+			c.Set("tenant_id", uint(tenantID))
+		}
 
 		c.Next()
 	}
@@ -60,6 +97,18 @@ func (m *JWTMiddleware) AdminRequired() gin.HandlerFunc {
 	}
 }
 
+// PlatformAdminRequired restricts platform-wide administration to admins not scoped to a tenant
+func (m *JWTMiddleware) PlatformAdminRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, scoped := c.Get("tenant_id"); scoped {
+			response.Error(c, http.StatusForbidden, "platform admin access required")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
 // extractTokenFromHeader extracts JWT token from Authorization header
 func (m *JWTMiddleware) extractTokenFromHeader(c *gin.Context) (string, error) {
 	authHeader := c.GetHeader("Authorization")
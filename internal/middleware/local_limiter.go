@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+)
+
+// localCounter is the in-memory fixed window counter used while Redis is unavailable. Counts
+// are per instance, so behind a load balancer clients get up to one budget per instance.
+type localCounter struct {
+	mu        sync.Mutex
+	windows   map[string]*localWindow
+	lastSweep time.Time
+}
+
+type localWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+func newLocalCounter() *localCounter {
+	return &localCounter{windows: make(map[string]*localWindow)}
+}
+
+// incr counts a request against key and returns the count in the current window and when it resets
+func (l *localCounter) incr(key string, window time.Duration) (int, time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	// Drop finished windows now and then so idle clients don't accumulate
+	if now.Sub(l.lastSweep) > time.Minute {
+		for k, w := range l.windows {
+			if !now.Before(w.resetAt) {
+				delete(l.windows, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	w, ok := l.windows[key]
+	if !ok || !now.Before(w.resetAt) {
+		w = &localWindow{resetAt: now.Add(window)}
+		l.windows[key] = w
+	}
+	w.count++
+	return w.count, w.resetAt
+}
+
+// reset forgets key's count, so its next request starts a new window
+func (l *localCounter) reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.windows, key)
+}
+
+// peek returns key's count in the current window and when it resets, without counting a request
+func (l *localCounter) peek(key string) (int, time.Time, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	w, ok := l.windows[key]
+	if !ok || !time.Now().Before(w.resetAt) {
+		return 0, time.Time{}, false
+	}
+	return w.count, w.resetAt, true
+}
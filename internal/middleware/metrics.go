@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"api/pkg/response"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsAuth only lets scrapers presenting the metrics bearer token through
+func MetricsAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		presented := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			response.Error(c, http.StatusUnauthorized, "invalid metrics token")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
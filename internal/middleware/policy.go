@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"api/constants"
+	"api/pkg/response"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PendingPolicies returns the policies whose current version a user hasn't accepted
+type PendingPolicies interface {
+	PendingPolicies(ctx context.Context, userID uint) ([]string, error)
+}
+
+// PolicyGate refuses authenticated requests of users who haven't accepted the current terms of
+// service and privacy policy, with a code clients handle by asking the user to accept them.
+// It runs after AuthRequired.
+func PolicyGate(policies PendingPolicies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := c.Get("user_id")
+		if !ok {
+			c.Next()
+			return
+		}
+
+		pending, err := policies.PendingPolicies(c.Request.Context(), userID.(uint))
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, "internal server error")
+			c.Abort()
+			return
+		}
+		if len(pending) > 0 {
+			response.ErrorWithCode(c, http.StatusForbidden, constants.CodePoliciesNotAccepted, constants.ErrPolicyVersionChanged)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// Rate limit scopes: who a policy's requests are counted for
+const (
+	LimitScopeIP     = "ip"
+	LimitScopeUser   = "user"
+	LimitScopeTenant = "tenant"
+)
+
+// limitPolicy is a budget of requests per window that some routes are limited to. Policies of
+// the same scope share one counter.
+type limitPolicy struct {
+	scope    string
+	requests int
+	window   time.Duration
+	budget   func(c *gin.Context) int // the caller's budget, when it isn't always requests
+}
+
+// LimitStatus is where a caller stands against one rate limit policy
+type LimitStatus struct {
+	Scope     string
+	Limit     int
+	Window    time.Duration
+	Used      int
+	Remaining int
+	ResetAt   *time.Time // nil while nothing was counted in the current window
+}
+
+// register records a policy for Status, once however many route groups use it
+func (rl *RateLimiter) register(policy limitPolicy) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	for _, p := range rl.policies {
+		if p.scope == policy.scope && p.requests == policy.requests && p.window == policy.window && (p.budget == nil) == (policy.budget == nil) {
+			return
+		}
+	}
+	rl.policies = append(rl.policies, policy)
+}
+
+// Allowlisted reports whether the caller is exempt from rate limiting
+func (rl *RateLimiter) Allowlisted(c *gin.Context) bool {
+	ctx := c.Request.Context()
+	if rl.allowlist.AllowsIP(ctx, c.ClientIP()) {
+		return true
+	}
+	userID, _ := c.Get("user_id")
+	id, ok := userID.(uint)
+	return ok && rl.allowlist.AllowsUser(ctx, id)
+}
+
+// Status reports the caller's usage of every policy that applies to them, from the same
+// counters the limits are enforced with, so clients can slow down before being refused.
+// User policies only apply to authenticated callers, tenant policies to tenant admins.
+func (rl *RateLimiter) Status(c *gin.Context) []LimitStatus {
+	rl.mu.Lock()
+	policies := append([]limitPolicy(nil), rl.policies...)
+	rl.mu.Unlock()
+	sort.SliceStable(policies, func(i, j int) bool {
+		if policies[i].scope != policies[j].scope {
+			return scopeOrder(policies[i].scope) < scopeOrder(policies[j].scope)
+		}
+		return policies[i].requests < policies[j].requests
+	})
+
+	keys := make([]string, len(policies))
+	for i, policy := range policies {
+		keys[i] = limitKey(c, policy.scope)
+	}
+	used := rl.usage(c.Request.Context(), keys)
+
+	statuses := make([]LimitStatus, 0, len(policies))
+	for i, policy := range policies {
+		if keys[i] == "" {
+			continue
+		}
+		limit := policy.requests
+		if policy.budget != nil {
+			limit = policy.budget(c)
+		}
+		status := LimitStatus{Scope: policy.scope, Limit: limit, Window: policy.window}
+		if count, ok := used[keys[i]]; ok {
+			status.Used = count.count
+			status.ResetAt = &count.resetAt
+		}
+		status.Remaining = max(limit-status.Used, 0)
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+type keyUsage struct {
+	count   int
+	resetAt time.Time
+}
+
+// usage reads the counts and resets of keys from Redis, or from the in-memory counter while
+// Redis is unavailable. Keys without a count in the current window are left out.
+func (rl *RateLimiter) usage(ctx context.Context, keys []string) map[string]keyUsage {
+	if rl.health.Degraded() {
+		return rl.localUsage(keys)
+	}
+
+	used := make(map[string]keyUsage, len(keys))
+	counts := make(map[string]*redis.StringCmd, len(keys))
+	ttls := make(map[string]*redis.DurationCmd, len(keys))
+	_, err := rl.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			if _, seen := counts[key]; key == "" || seen {
+				continue
+			}
+			counts[key] = pipe.Get(ctx, key)
+			ttls[key] = pipe.TTL(ctx, key)
+		}
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		// Redis failed mid-request, so requests are being counted in memory
+		return rl.localUsage(keys)
+	}
+
+	now := time.Now()
+	for key, cmd := range counts {
+		count, err := cmd.Int()
+		if err != nil {
+			continue
+		}
+		used[key] = keyUsage{count: count, resetAt: now.Add(ttls[key].Val()).Truncate(time.Second)}
+	}
+	return used
+}
+
+// localUsage reads the counts of keys from the in-memory counter
+func (rl *RateLimiter) localUsage(keys []string) map[string]keyUsage {
+	used := make(map[string]keyUsage, len(keys))
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if count, resetAt, ok := rl.local.peek(key); ok {
+			used[key] = keyUsage{count: count, resetAt: resetAt}
+		}
+	}
+	return used
+}
+
+// limitKey is the counter a scope's policies use for the caller, or "" if the scope doesn't
+// apply to them
+func limitKey(c *gin.Context, scope string) string {
+	switch scope {
+	case LimitScopeIP:
+		return ipLimitKey(c)
+	case LimitScopeUser:
+		if userID, ok := c.Get("user_id"); ok {
+			return userLimitKey(userID)
+		}
+	case LimitScopeTenant:
+		if tenantID, ok := c.Get("tenant_id"); ok {
+			return tenantLimitKey(tenantID)
+		}
+	}
+	return ""
+}
+
+func ipLimitKey(c *gin.Context) string {
+	return fmt.Sprintf("rate_limit:%s", c.ClientIP())
+}
+
+func userLimitKey(userID any) string {
+	return fmt.Sprintf("rate_limit:user:%v", userID)
+}
+
+func tenantLimitKey(tenantID any) string {
+	return fmt.Sprintf("rate_limit:tenant:%v", tenantID)
+}
+
+func scopeOrder(scope string) int {
+	switch scope {
+	case LimitScopeIP:
+		return 0
+	case LimitScopeUser:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	redisconn "api/internal/redis"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// TestRateLimiterStatus counts requests against an unreachable Redis, so they fall back to the
+// in-memory counter, and reports them per scope with the user policies sharing one counter
+func TestRateLimiterStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond, MaxRetries: -1})
+	defer client.Close()
+	allowlist, err := NewAllowlist(client, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rl := NewRateLimiter(client, allowlist, redisconn.NewHealth(client, time.Minute))
+
+	var statuses []LimitStatus
+	router := gin.New()
+	router.Use(rl.RateLimit(1000, time.Minute), func(c *gin.Context) {
+		c.Set("user_id", uint(7))
+		c.Next()
+	})
+	router.GET("/bookings", rl.UserRateLimit(50, time.Minute), func(c *gin.Context) {})
+	router.GET("/events", rl.UserRateLimit(100, time.Minute), func(c *gin.Context) {})
+	router.GET("/limits", func(c *gin.Context) { statuses = rl.Status(c) })
+
+	for _, path := range []string{"/bookings", "/bookings", "/events", "/limits"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if len(statuses) != 3 {
+		t.Fatalf("statuses %+v, want one IP and two user policies", statuses)
+	}
+	want := []struct {
+		scope       string
+		limit, used int
+		remaining   int
+	}{
+		{LimitScopeIP, 1000, 4, 996},
+		{LimitScopeUser, 50, 3, 47},
+		{LimitScopeUser, 100, 3, 97},
+	}
+	for i, w := range want {
+		s := statuses[i]
+		if s.Scope != w.scope || s.Limit != w.limit || s.Used != w.used || s.Remaining != w.remaining || s.ResetAt == nil {
+			t.Errorf("status %d is %+v, want %s %d used of %d", i, s, w.scope, w.used, w.limit)
+		}
+	}
+}
@@ -1,154 +1,237 @@
 package middleware
 
 import (
-	"fmt"
+	redisconn "api/internal/redis"
+	"context"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 )
 
+// TenantLimitFunc returns a tenant's request budget per window, or 0 to use the default
+type TenantLimitFunc func(ctx context.Context, tenantID uint) int
+
+// QueueTokenFunc reports whether an on-sale queue is running and whether the user holds an
+// active queue token, an unexpired admission from one of them
+type QueueTokenFunc func(ctx context.Context, userID uint) (onSale, admitted bool)
+
+// QueuePolicy is the per-user budget while an on-sale is running: users admitted from the
+// queue get Admitted requests per window to finish checking out, everyone else Browsing
+type QueuePolicy struct {
+	Admitted int
+	Browsing int
+}
+
 type RateLimiter struct {
-	redis *redis.Client
+	redis     redis.UniversalClient
+	allowlist *Allowlist
+	health    *redisconn.Health
+	local     *localCounter
+
+	mu       sync.Mutex
+	policies []limitPolicy // every budget handed out, for reporting callers' usage
 }
 
-func NewRateLimiter(redis *redis.Client) *RateLimiter {
-	return &RateLimiter{redis: redis}
+// NewRateLimiter counts requests in Redis, or in memory while health reports Redis unavailable
+func NewRateLimiter(redis redis.UniversalClient, allowlist *Allowlist, health *redisconn.Health) *RateLimiter {
+	return &RateLimiter{redis: redis, allowlist: allowlist, health: health, local: newLocalCounter()}
 }
 
 // RateLimit middleware limits requests per IP/user
 func (rl *RateLimiter) RateLimit(requests int, window time.Duration) gin.HandlerFunc {
+	rl.register(limitPolicy{scope: LimitScopeIP, requests: requests, window: window})
 	return func(c *gin.Context) {
-		// Using IP address as the key for rate limiting
-		key := fmt.Sprintf("rate_limit:%s", c.ClientIP())
-
 		ctx := c.Request.Context()
 
-		// Get current count
-		current, err := rl.redis.Get(ctx, key).Int()
-		if err == redis.Nil {
-			// First request, set counter
-			err = rl.redis.Set(ctx, key, 1, window).Err()
-			if err != nil {
-				// If Redis fails, allow the request (fail open)
-				c.Next()
-				return
-			}
-			c.Next()
-			return
-		} else if err != nil {
-			// Redis error, allow request (fail open)
+		// Allowlisted clients such as monitoring probes are never limited
+		if rl.allowlist.AllowsIP(ctx, c.ClientIP()) {
 			c.Next()
 			return
 		}
 
-		// Check if limit exceeded
-		if current >= requests {
-			// Get TTL for rate limit reset time
-			ttl, _ := rl.redis.TTL(ctx, key).Result()
-
-			c.Header("X-Rate-Limit-Limit", strconv.Itoa(requests))
-			c.Header("X-Rate-Limit-Remaining", "0")
-			c.Header("X-Rate-Limit-Reset", strconv.FormatInt(time.Now().Add(ttl).Unix(), 10))
+		// Using IP address as the key for rate limiting
+		rl.limit(c, ipLimitKey(c), requests, window)
+	}
+}
 
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":       "Rate limit exceeded",
-				"retry_after": int(ttl.Seconds()),
-			})
-			c.Abort()
+// UserRateLimit uses authenticated user ID instead of IP
+func (rl *RateLimiter) UserRateLimit(requests int, window time.Duration) gin.HandlerFunc {
+	rl.register(limitPolicy{scope: LimitScopeUser, requests: requests, window: window})
+	return func(c *gin.Context) {
+		// Get user ID from context (set by JWT middleware)
+		userID, exists := c.Get("user_id")
+		if !exists {
+			// No user ID, fall back to IP-based rate limiting
+			rl.RateLimit(requests, window)(c)
 			return
 		}
 
-		// Increment counter
-		newCount, err := rl.redis.Incr(ctx, key).Result()
-		if err != nil {
-			// Redis error, allow request (fail open)
+		ctx := c.Request.Context()
+
+		// Allowlisted users (internal tooling) and IPs are never limited
+		id, _ := userID.(uint)
+		if rl.allowlist.AllowsUser(ctx, id) || rl.allowlist.AllowsIP(ctx, c.ClientIP()) {
 			c.Next()
 			return
 		}
 
-		// Set headers
-		remaining := requests - int(newCount)
-		if remaining < 0 {
-			remaining = 0
-		}
-
-		c.Header("X-Rate-Limit-Limit", strconv.Itoa(requests))
-		c.Header("X-Rate-Limit-Remaining", strconv.Itoa(remaining))
-
-		c.Next()
+		rl.limit(c, userLimitKey(userID), requests, window)
 	}
 }
 
-// UserRateLimit uses authenticated user ID instead of IP
-func (rl *RateLimiter) UserRateLimit(requests int, window time.Duration) gin.HandlerFunc {
+// QueueRateLimit is UserRateLimit with budgets weighted by queue token during on-sales, so
+// background browsing can't crowd out users who waited their turn. Outside on-sales every user
+// gets requests per window. The budget changes with the token, the count doesn't, so a user
+// admitted halfway through a window keeps what they already used.
+func (rl *RateLimiter) QueueRateLimit(requests int, window time.Duration, policy QueuePolicy, tokenFor QueueTokenFunc) gin.HandlerFunc {
+	budgetFor := func(ctx context.Context, userID uint) int {
+		if onSale, admitted := tokenFor(ctx, userID); admitted {
+			return policy.Admitted
+		} else if onSale {
+			return policy.Browsing
+		}
+		return requests
+	}
+	rl.register(limitPolicy{scope: LimitScopeUser, requests: requests, window: window, budget: func(c *gin.Context) int {
+		userID, _ := c.Get("user_id")
+		id, _ := userID.(uint)
+		return budgetFor(c.Request.Context(), id)
+	}})
+
 	return func(c *gin.Context) {
-		// Get user ID from context (set by JWT middleware)
 		userID, exists := c.Get("user_id")
 		if !exists {
-			// No user ID, fall back to IP-based rate limiting
 			rl.RateLimit(requests, window)(c)
 			return
 		}
 
-		key := fmt.Sprintf("rate_limit:user:%v", userID)
-
 		ctx := c.Request.Context()
-
-		// Get current count
-		current, err := rl.redis.Get(ctx, key).Int()
-		if err == redis.Nil {
-			// First request, set counter
-			err = rl.redis.Set(ctx, key, 1, window).Err()
-			if err != nil {
-				// If Redis fails, allow the request (fail open)
-				c.Next()
-				return
-			}
-			c.Next()
-			return
-		} else if err != nil {
-			// Redis error, allow request (fail open)
+		id, _ := userID.(uint)
+		if rl.allowlist.AllowsUser(ctx, id) || rl.allowlist.AllowsIP(ctx, c.ClientIP()) {
 			c.Next()
 			return
 		}
 
-		// Check if limit exceeded
-		if current >= requests {
-			// Get TTL for rate limit reset time
-			ttl, _ := rl.redis.TTL(ctx, key).Result()
+		rl.limit(c, userLimitKey(userID), budgetFor(ctx, id), window)
+	}
+}
 
-			c.Header("X-Rate-Limit-Limit", strconv.Itoa(requests))
-			c.Header("X-Rate-Limit-Remaining", "0")
-			c.Header("X-Rate-Limit-Reset", strconv.FormatInt(time.Now().Add(ttl).Unix(), 10))
+// TenantRateLimit shares one budget between all admins of a tenant, so a single organizer
+// can't starve the others. limitFor overrides the default budget per tenant. Platform
+// admins aren't scoped to a tenant and pass through.
+func (rl *RateLimiter) TenantRateLimit(requests int, window time.Duration, limitFor TenantLimitFunc) gin.HandlerFunc {
+	budgetFor := func(ctx context.Context, tenantID uint) int {
+		if limit := limitFor(ctx, tenantID); limit > 0 {
+			return limit
+		}
+		return requests
+	}
+	rl.register(limitPolicy{scope: LimitScopeTenant, requests: requests, window: window, budget: func(c *gin.Context) int {
+		tenantID, _ := c.Get("tenant_id")
+		id, _ := tenantID.(uint)
+		return budgetFor(c.Request.Context(), id)
+	}})
 
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":       "Rate limit exceeded",
-				"retry_after": int(ttl.Seconds()),
-			})
-			c.Abort()
+	return func(c *gin.Context) {
+		tenantID, exists := c.Get("tenant_id")
+		if !exists {
+			c.Next()
 			return
 		}
 
-		// Increment counter
-		newCount, err := rl.redis.Incr(ctx, key).Result()
-		if err != nil {
-			// Redis error, allow request (fail open)
+		ctx := c.Request.Context()
+		if rl.allowlist.AllowsIP(ctx, c.ClientIP()) {
 			c.Next()
 			return
 		}
 
-		// Set headers
-		remaining := requests - int(newCount)
-		if remaining < 0 {
-			remaining = 0
+		rl.limit(c, tenantLimitKey(tenantID), budgetFor(ctx, tenantID.(uint)), window)
+	}
+}
+
+// limit counts the request against key and rejects it once requests have been made in the window
+func (rl *RateLimiter) limit(c *gin.Context, key string, requests int, window time.Duration) {
+	ctx := c.Request.Context()
+
+	if rl.health.Degraded() {
+		rl.limitLocally(c, key, requests, window)
+		return
+	}
+
+	// Get current count
+	current, err := rl.redis.Get(ctx, key).Int()
+	if err == redis.Nil {
+		// First request, set counter
+		err = rl.redis.Set(ctx, key, 1, window).Err()
+		if err != nil {
+			// Redis failed mid-request, count it in memory instead
+			rl.limitLocally(c, key, requests, window)
+			return
 		}
+		c.Next()
+		return
+	} else if err != nil {
+		rl.limitLocally(c, key, requests, window)
+		return
+	}
+
+	// Check if limit exceeded
+	if current >= requests {
+		// Get TTL for rate limit reset time
+		ttl, _ := rl.redis.TTL(ctx, key).Result()
 
 		c.Header("X-Rate-Limit-Limit", strconv.Itoa(requests))
-		c.Header("X-Rate-Limit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-Rate-Limit-Remaining", "0")
+		c.Header("X-Rate-Limit-Reset", strconv.FormatInt(time.Now().Add(ttl).Unix(), 10))
+
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":       "Rate limit exceeded",
+			"retry_after": int(ttl.Seconds()),
+		})
+		c.Abort()
+		return
+	}
 
-		c.Next()
+	// Increment counter
+	newCount, err := rl.redis.Incr(ctx, key).Result()
+	if err != nil {
+		rl.limitLocally(c, key, requests, window)
+		return
 	}
+
+	// Set headers
+	remaining := requests - int(newCount)
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	c.Header("X-Rate-Limit-Limit", strconv.Itoa(requests))
+	c.Header("X-Rate-Limit-Remaining", strconv.Itoa(remaining))
+
+	c.Next()
+}
+
+// limitLocally applies the limit with the in-memory counter while Redis can't be used
+func (rl *RateLimiter) limitLocally(c *gin.Context, key string, requests int, window time.Duration) {
+	count, resetAt := rl.local.incr(key, window)
+
+	c.Header("X-Rate-Limit-Limit", strconv.Itoa(requests))
+	if count > requests {
+		c.Header("X-Rate-Limit-Remaining", "0")
+		c.Header("X-Rate-Limit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":       "Rate limit exceeded",
+			"retry_after": int(time.Until(resetAt).Seconds()),
+		})
+		c.Abort()
+		return
+	}
+
+	c.Header("X-Rate-Limit-Remaining", strconv.Itoa(requests-count))
+	c.Next()
 }
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"api/internal/entities"
+	"api/pkg/errors"
+	"api/pkg/response"
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScannerDevices authenticates door scanners by their device token
+type ScannerDevices interface {
+	AuthenticateScanner(ctx context.Context, token string) (*entities.ScannerDevice, error)
+}
+
+// ScannerAuth only lets registered, unrevoked scanner devices through. The device is stored as
+// "scanner_device", and its event's tenant as "tenant_id" so queries stay in its scope.
+func ScannerAuth(devices ScannerDevices) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			response.Error(c, http.StatusUnauthorized, "missing or invalid authorization header")
+			c.Abort()
+			return
+		}
+
+		device, err := devices.AuthenticateScanner(c.Request.Context(), token)
+		if err != nil {
+			if appErr, ok := err.(*errors.AppError); ok && appErr.Type == "UNAUTHORIZED" {
+				response.Error(c, http.StatusUnauthorized, appErr.Message)
+			} else {
+				response.Error(c, http.StatusInternalServerError, "internal server error")
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set("scanner_device", device)
+		c.Set("tenant_id", device.TenantID)
+		c.Next()
+	}
+}
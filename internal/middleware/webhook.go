@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	redisconn "api/internal/redis"
+	"api/pkg/response"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	webhookSignatureHeader = "X-Webhook-Signature"
+	webhookIDHeader        = "X-Webhook-ID"
+	webhookTimestampHeader = "X-Webhook-Timestamp"
+)
+
+type WebhookVerifier struct {
+	secret    []byte
+	tolerance time.Duration
+	redis     redis.UniversalClient
+	health    *redisconn.Health
+	local     *localCounter
+}
+
+// NewWebhookVerifier checks webhooks signed with secret and sent within tolerance of now.
+// Delivery IDs are remembered in Redis, or in memory while health reports Redis unavailable or
+// without a client.
+func NewWebhookVerifier(secret string, tolerance time.Duration, redis redis.UniversalClient, health *redisconn.Health) *WebhookVerifier {
+	return &WebhookVerifier{secret: []byte(secret), tolerance: tolerance, redis: redis, health: health, local: newLocalCounter()}
+}
+
+// VerifySignature rejects webhooks whose X-Webhook-Signature header ("sha256=<hex>") is not
+// the HMAC-SHA256 of "<X-Webhook-ID>.<X-Webhook-Timestamp>.<body>", whose timestamp (Unix
+// seconds) is further than the tolerance from now, or whose ID was already accepted. An ID is
+// only kept once the handler succeeds, so a delivery it failed can be redelivered.
+func (m *WebhookVerifier) VerifySignature() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(m.secret) == 0 {
+			response.Error(c, http.StatusServiceUnavailable, "webhooks are not configured")
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "failed to read request body")
+			c.Abort()
+			return
+		}
+		// Restore the body for the handler
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		id := c.GetHeader(webhookIDHeader)
+		timestamp := c.GetHeader(webhookTimestampHeader)
+		signature := strings.TrimPrefix(c.GetHeader(webhookSignatureHeader), "sha256=")
+		expected, err := hex.DecodeString(signature)
+		if err != nil || id == "" || !hmac.Equal(expected, m.sign(id, timestamp, body)) {
+			response.Error(c, http.StatusUnauthorized, "invalid webhook signature")
+			c.Abort()
+			return
+		}
+
+		// The timestamp is signed, so an old delivery can't be made to look recent
+		sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, "invalid webhook timestamp")
+			c.Abort()
+			return
+		}
+		if age := time.Since(time.Unix(sentAt, 0)); age > m.tolerance || age < -m.tolerance {
+			response.Error(c, http.StatusUnauthorized, "webhook timestamp outside the allowed window")
+			c.Abort()
+			return
+		}
+
+		key := redisconn.WebhookDeliveryKey(id)
+		if !m.claim(c, key) {
+			response.Error(c, http.StatusConflict, "webhook already received")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+
+		if status := c.Writer.Status(); status < 200 || status > 299 {
+			m.release(c, key)
+		}
+	}
+}
+
+// claim records a delivery ID and reports whether it was new. IDs are kept for twice the
+// tolerance, covering every timestamp that is still accepted.
+func (m *WebhookVerifier) claim(c *gin.Context, key string) bool {
+	if m.redis == nil || m.health.Degraded() {
+		count, _ := m.local.incr(key, 2*m.tolerance)
+		return count == 1
+	}
+
+	claimed, err := m.redis.SetNX(c.Request.Context(), key, 1, 2*m.tolerance).Result()
+	if err != nil {
+		// Redis failed mid-request, remember the ID in memory instead
+		count, _ := m.local.incr(key, 2*m.tolerance)
+		return count == 1
+	}
+	return claimed
+}
+
+func (m *WebhookVerifier) release(c *gin.Context, key string) {
+	m.local.reset(key)
+	if m.redis != nil && !m.health.Degraded() {
+		m.redis.Del(c.Request.Context(), key)
+	}
+}
+
+func (m *WebhookVerifier) sign(id, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write([]byte(id + "." + timestamp + "."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FuzzVerifySignature checks that a webhook only gets through with the HMAC of its exact
+// body, and that the handler then reads the body the signature was checked against. Each
+// delivery gets its own ID so the replay check doesn't get in the way.
+func FuzzVerifySignature(f *testing.F) {
+	const secret = "webhook-secret"
+	gin.SetMode(gin.TestMode)
+
+	const deliveryID, timestamp = "wh_1", "1700000000"
+	sign := func(body []byte) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(deliveryID + "." + timestamp + "."))
+		mac.Write(body)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	body := []byte(`{"dispute_id":"dp_1","payment_id":"pay_1","status":"open"}`)
+	f.Add(body, "sha256="+sign(body))
+	f.Add(body, sign(body))
+	f.Add(body, "sha256="+strings.ToUpper(sign(body)))
+	f.Add(body, "sha256="+sign(body)[:62])
+	f.Add(append(body, ' '), "sha256="+sign(body))
+	f.Add([]byte{}, "sha256="+sign(nil))
+	f.Add(body, "sha256=")
+	f.Add(body, "sha256=zz")
+
+	// The fixed timestamp is always within the tolerance
+	tolerance := time.Since(time.Unix(1700000000, 0)) + time.Hour
+
+	f.Fuzz(func(t *testing.T, body []byte, header string) {
+		router := gin.New()
+		router.POST("/webhooks", NewWebhookVerifier(secret, tolerance, nil, nil).VerifySignature(), func(c *gin.Context) {
+			received, _ := io.ReadAll(c.Request.Body)
+			c.Data(http.StatusOK, "application/octet-stream", received)
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(body))
+		req.Header.Set(webhookIDHeader, deliveryID)
+		req.Header.Set(webhookTimestampHeader, timestamp)
+		req.Header.Set(webhookSignatureHeader, header)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		valid := strings.EqualFold(strings.TrimPrefix(header, "sha256="), sign(body))
+		if valid != (w.Code == http.StatusOK) {
+			t.Fatalf("signature %q for body %q: status %d", header, body, w.Code)
+		}
+		if valid && !bytes.Equal(w.Body.Bytes(), body) {
+			t.Fatalf("handler read %q, signed body was %q", w.Body.Bytes(), body)
+		}
+	})
+}
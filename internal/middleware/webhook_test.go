@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestVerifySignatureRejectsReplays sends correctly signed webhooks that are stale, replayed,
+// or redelivered after the handler failed
+func TestVerifySignatureRejectsReplays(t *testing.T) {
+	const secret = "webhook-secret"
+	gin.SetMode(gin.TestMode)
+
+	status := http.StatusOK
+	router := gin.New()
+	router.POST("/webhooks", NewWebhookVerifier(secret, 5*time.Minute, nil, nil).VerifySignature(), func(c *gin.Context) {
+		c.Status(status)
+	})
+
+	send := func(id string, sentAt time.Time) int {
+		body := []byte(`{"dispute_id":"dp_1","payment_id":"pay_1","status":"open"}`)
+		timestamp := strconv.FormatInt(sentAt.Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(id + "." + timestamp + "."))
+		mac.Write(body)
+
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(body))
+		req.Header.Set(webhookIDHeader, id)
+		req.Header.Set(webhookTimestampHeader, timestamp)
+		req.Header.Set(webhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	cases := []struct {
+		name   string
+		id     string
+		sentAt time.Time
+		status int
+		want   int
+	}{
+		{"fresh delivery", "wh_1", time.Now(), http.StatusOK, http.StatusOK},
+		{"replayed delivery", "wh_1", time.Now(), http.StatusOK, http.StatusConflict},
+		{"stale timestamp", "wh_2", time.Now().Add(-10 * time.Minute), http.StatusOK, http.StatusUnauthorized},
+		{"timestamp from the future", "wh_3", time.Now().Add(10 * time.Minute), http.StatusOK, http.StatusUnauthorized},
+		{"handler fails", "wh_4", time.Now(), http.StatusNotFound, http.StatusNotFound},
+		{"redelivery after a failure", "wh_4", time.Now(), http.StatusOK, http.StatusOK},
+		{"replay after the redelivery", "wh_4", time.Now(), http.StatusOK, http.StatusConflict},
+	}
+	for _, tc := range cases {
+		status = tc.status
+		if got := send(tc.id, tc.sentAt); got != tc.want {
+			t.Errorf("%s: status %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
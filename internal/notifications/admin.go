@@ -0,0 +1,157 @@
+package notifications
+
+import (
+	logger "api/pkg/logging"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Admin notification channels
+const (
+	AdminChannelLog     = "log"
+	AdminChannelSlack   = "slack"
+	AdminChannelDiscord = "discord"
+)
+
+// Kinds of operational events admins are notified of
+const (
+	AdminEventSoldOut                   = "event_sold_out"
+	AdminEventLargeRefund               = "large_refund"
+	AdminEventReconciliationDiscrepancy = "reconciliation_discrepancy"
+	AdminEventQueueSaturated            = "queue_saturated"
+)
+
+// discordContentLimit is the longest message a Discord webhook accepts
+const discordContentLimit = 2000
+
+// AdminEvent is an operational event worth telling the admins' team channel about
+type AdminEvent struct {
+	Kind    string
+	EventID uint // the event it concerns, if any
+	Title   string
+	Message string
+	At      time.Time
+}
+
+// AdminChannel delivers operational events to the admins' team chat
+type AdminChannel interface {
+	Notify(ctx context.Context, event AdminEvent) error
+}
+
+// AdminChannelConfig selects the admin channel and its incoming webhook
+type AdminChannelConfig struct {
+	Channel    string
+	WebhookURL string
+	Timeout    time.Duration
+}
+
+// NewAdminChannel returns the channel selected by the config; without one admin events are logged
+func NewAdminChannel(cfg AdminChannelConfig) (AdminChannel, error) {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	client := &http.Client{Timeout: timeout}
+
+	switch cfg.Channel {
+	case "", AdminChannelLog:
+		return NewLogAdminChannel(), nil
+	case AdminChannelSlack, AdminChannelDiscord:
+		if cfg.WebhookURL == "" {
+			return nil, fmt.Errorf("the %s admin channel needs a webhook URL", cfg.Channel)
+		}
+		if cfg.Channel == AdminChannelSlack {
+			return NewSlackAdminChannel(cfg.WebhookURL, client), nil
+		}
+		return NewDiscordAdminChannel(cfg.WebhookURL, client), nil
+	default:
+		return nil, fmt.Errorf("unknown admin notification channel %q", cfg.Channel)
+	}
+}
+
+// LogAdminChannel writes admin events to the application log
+type LogAdminChannel struct{}
+
+// Ensure LogAdminChannel implements AdminChannel
+var _ AdminChannel = (*LogAdminChannel)(nil)
+
+func NewLogAdminChannel() *LogAdminChannel {
+	return &LogAdminChannel{}
+}
+
+func (c *LogAdminChannel) Notify(ctx context.Context, event AdminEvent) error {
+	logger.Infof("Admin event [%s] %s: %s", event.Kind, event.Title, event.Message)
+	return nil
+}
+
+// SlackAdminChannel posts admin events to a Slack incoming webhook
+type SlackAdminChannel struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// Ensure SlackAdminChannel implements AdminChannel
+var _ AdminChannel = (*SlackAdminChannel)(nil)
+
+func NewSlackAdminChannel(webhookURL string, client *http.Client) *SlackAdminChannel {
+	return &SlackAdminChannel{webhookURL: webhookURL, client: client}
+}
+
+func (c *SlackAdminChannel) Notify(ctx context.Context, event AdminEvent) error {
+	text := fmt.Sprintf("*%s*\n%s", event.Title, event.Message)
+	return postWebhook(ctx, c.client, c.webhookURL, map[string]string{"text": text})
+}
+
+// DiscordAdminChannel posts admin events to a Discord channel webhook
+type DiscordAdminChannel struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// Ensure DiscordAdminChannel implements AdminChannel
+var _ AdminChannel = (*DiscordAdminChannel)(nil)
+
+func NewDiscordAdminChannel(webhookURL string, client *http.Client) *DiscordAdminChannel {
+	return &DiscordAdminChannel{webhookURL: webhookURL, client: client}
+}
+
+func (c *DiscordAdminChannel) Notify(ctx context.Context, event AdminEvent) error {
+	content := []rune(fmt.Sprintf("**%s**\n%s", event.Title, event.Message))
+	if len(content) > discordContentLimit {
+		content = append(content[:discordContentLimit-1], '…')
+	}
+	// Mentions in event names mustn't ping anyone
+	return postWebhook(ctx, c.client, c.webhookURL, map[string]interface{}{
+		"content":          string(content),
+		"allowed_mentions": map[string][]string{"parse": {}},
+	})
+}
+
+// postWebhook posts body as JSON to a chat webhook and fails on non-2xx responses
+func postWebhook(ctx context.Context, client *http.Client, url string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return nil
+}
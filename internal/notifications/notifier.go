@@ -0,0 +1,39 @@
+package notifications
+
+import (
+	logger "api/pkg/logging"
+	"context"
+)
+
+// Notification is a message addressed to a single user
+type Notification struct {
+	Type      string
+	UserID    uint
+	Recipient string // email address of the user
+	EventID   uint
+	BookingID uint
+	Subject   string
+	Message   string
+}
+
+// Notifier delivers notifications to users
+type Notifier interface {
+	Send(ctx context.Context, notification Notification) error
+}
+
+// LogNotifier writes notifications to the application log. It is the default
+// delivery channel until an email/push provider is configured.
+type LogNotifier struct{}
+
+// Ensure LogNotifier implements Notifier
+var _ Notifier = (*LogNotifier)(nil)
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Send(ctx context.Context, notification Notification) error {
+	logger.Infof("Notification [%s] to user %d <%s>: %s - %s",
+		notification.Type, notification.UserID, notification.Recipient, notification.Subject, notification.Message)
+	return nil
+}
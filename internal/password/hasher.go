@@ -0,0 +1,150 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Algorithms
+const (
+	Argon2id = "argon2id"
+	Bcrypt   = "bcrypt"
+)
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// ErrMismatch is returned when a password doesn't match its hash
+var ErrMismatch = errors.New("password does not match")
+
+// Params configures how new hashes are created. Hashes made with other parameters still
+// verify, and are reported as needing a rehash.
+type Params struct {
+	Algorithm         string
+	Argon2Memory      uint32 // KiB
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+	BcryptCost        int
+}
+
+// Hasher hashes and verifies passwords with argon2id or bcrypt
+type Hasher struct {
+	params Params
+	// dummyHash is verified against when a user doesn't exist, so unknown and known
+	// accounts take the same time to reject
+	dummyHash string
+}
+
+func NewHasher(params Params) (*Hasher, error) {
+	switch params.Algorithm {
+	case Argon2id:
+		if params.Argon2Memory < 8*uint32(params.Argon2Parallelism) || params.Argon2Iterations < 1 || params.Argon2Parallelism < 1 {
+			return nil, fmt.Errorf("invalid argon2id parameters m=%d t=%d p=%d",
+				params.Argon2Memory, params.Argon2Iterations, params.Argon2Parallelism)
+		}
+	case Bcrypt:
+		if params.BcryptCost < bcrypt.MinCost || params.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	default:
+		return nil, fmt.Errorf("unknown password hash algorithm %q", params.Algorithm)
+	}
+
+	h := &Hasher{params: params}
+	dummy, err := h.Hash("dummy-password")
+	if err != nil {
+		return nil, err
+	}
+	h.dummyHash = dummy
+	return h, nil
+}
+
+// Hash hashes the password with the configured algorithm and parameters
+func (h *Hasher) Hash(password string) (string, error) {
+	if h.params.Algorithm == Bcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.params.BcryptCost)
+		return string(hash), err
+	}
+
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.params.Argon2Iterations, h.params.Argon2Memory, h.params.Argon2Parallelism, argon2KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version,
+		h.params.Argon2Memory, h.params.Argon2Iterations, h.params.Argon2Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify checks the password against a bcrypt or argon2id hash. needsRehash reports whether
+// the hash was made with a different algorithm or parameters than currently configured.
+func (h *Hasher) Verify(hash, password string) (needsRehash bool, err error) {
+	if strings.HasPrefix(hash, "$argon2id$") {
+		return h.verifyArgon2id(hash, password)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, ErrMismatch
+		}
+		return false, err
+	}
+	if h.params.Algorithm != Bcrypt {
+		return true, nil
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err == nil && cost != h.params.BcryptCost, nil
+}
+
+// VerifyDummy spends as long as a real verification; call it when the account doesn't exist
+func (h *Hasher) VerifyDummy(password string) {
+	_, _ = h.Verify(h.dummyHash, password)
+}
+
+func (h *Hasher) verifyArgon2id(hash, password string) (bool, error) {
+	// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return false, errors.New("malformed argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, errors.New("unsupported argon2id version")
+	}
+	var memory, iterations uint32
+	var parallelism uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &parallelism); err != nil {
+		return false, errors.New("malformed argon2id parameters")
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, errors.New("malformed argon2id salt")
+	}
+	expected, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, errors.New("malformed argon2id key")
+	}
+
+	key := argon2.IDKey([]byte(password), salt, iterations, memory, parallelism, uint32(len(expected)))
+	if subtle.ConstantTimeCompare(key, expected) != 1 {
+		return false, ErrMismatch
+	}
+
+	needsRehash := h.params.Algorithm != Argon2id ||
+		memory != h.params.Argon2Memory ||
+		iterations != h.params.Argon2Iterations ||
+		parallelism != h.params.Argon2Parallelism ||
+		len(expected) != argon2KeyLength ||
+		len(salt) != argon2SaltLength
+	return needsRehash, nil
+}
@@ -0,0 +1,115 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	argon2Params = Params{Algorithm: Argon2id, Argon2Memory: 1024, Argon2Iterations: 1, Argon2Parallelism: 1}
+	bcryptParams = Params{Algorithm: Bcrypt, BcryptCost: bcrypt.MinCost}
+)
+
+func mustHasher(t *testing.T, params Params) *Hasher {
+	t.Helper()
+	h, err := NewHasher(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestHashVerify(t *testing.T) {
+	for _, params := range []Params{argon2Params, bcryptParams} {
+		h := mustHasher(t, params)
+		hash, err := h.Hash("correct horse")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		needsRehash, err := h.Verify(hash, "correct horse")
+		if err != nil || needsRehash {
+			t.Errorf("%s: Verify() = %v, %v, want false, nil", params.Algorithm, needsRehash, err)
+		}
+		if _, err := h.Verify(hash, "wrong horse"); !errors.Is(err, ErrMismatch) {
+			t.Errorf("%s: Verify() with a wrong password returned %v, want ErrMismatch", params.Algorithm, err)
+		}
+	}
+}
+
+func TestVerifyNeedsRehash(t *testing.T) {
+	stronger := argon2Params
+	stronger.Argon2Memory *= 2
+	moreIterations := argon2Params
+	moreIterations.Argon2Iterations++
+	moreThreads := argon2Params
+	moreThreads.Argon2Parallelism++
+	higherCost := bcryptParams
+	higherCost.BcryptCost++
+
+	tests := []struct {
+		name     string
+		hashWith Params
+		verifier Params
+		want     bool
+	}{
+		{"same argon2id parameters", argon2Params, argon2Params, false},
+		{"bcrypt to argon2id", bcryptParams, argon2Params, true},
+		{"argon2id to bcrypt", argon2Params, bcryptParams, true},
+		{"argon2id memory changed", argon2Params, stronger, true},
+		{"argon2id iterations changed", argon2Params, moreIterations, true},
+		{"argon2id parallelism changed", argon2Params, moreThreads, true},
+		{"same bcrypt cost", bcryptParams, bcryptParams, false},
+		{"bcrypt cost changed", bcryptParams, higherCost, true},
+	}
+	for _, tt := range tests {
+		hash, err := mustHasher(t, tt.hashWith).Hash("correct horse")
+		if err != nil {
+			t.Fatal(err)
+		}
+		needsRehash, err := mustHasher(t, tt.verifier).Verify(hash, "correct horse")
+		if err != nil {
+			t.Errorf("%s: Verify() error = %v", tt.name, err)
+			continue
+		}
+		if needsRehash != tt.want {
+			t.Errorf("%s: needsRehash = %v, want %v", tt.name, needsRehash, tt.want)
+		}
+	}
+}
+
+func TestVerifyMalformedArgon2id(t *testing.T) {
+	h := mustHasher(t, argon2Params)
+	hash, err := h.Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(hash, "$")
+	with := func(i int, value string) string {
+		changed := append([]string(nil), parts...)
+		changed[i] = value
+		return strings.Join(changed, "$")
+	}
+
+	tests := []struct {
+		name    string
+		hash    string
+		wantErr string
+	}{
+		{"missing key", strings.Join(parts[:5], "$"), "malformed argon2id hash"},
+		{"unsupported version", with(2, "v=16"), "unsupported argon2id version"},
+		{"unparsable version", with(2, "version"), "unsupported argon2id version"},
+		{"malformed parameters", with(3, "m=1024;t=1;p=1"), "malformed argon2id parameters"},
+		{"malformed salt", with(4, "not*base64"), "malformed argon2id salt"},
+		{"malformed key", with(5, "not*base64"), "malformed argon2id key"},
+	}
+	for _, tt := range tests {
+		_, err := h.Verify(tt.hash, "correct horse")
+		if err == nil || err.Error() != tt.wantErr {
+			t.Errorf("%s: Verify() error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
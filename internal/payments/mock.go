@@ -0,0 +1,244 @@
+// Package payments holds the built-in mock payment provider used in development and E2E
+// tests to run the intent, pay and confirm flow without a real provider account.
+package payments
+
+import (
+	"api/constants"
+	logger "api/pkg/logging"
+	"api/pkg/money"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MockProviderName is the provider recorded on bookings paid through the mock provider
+const MockProviderName = "mock"
+
+// Test card numbers and the behaviour they trigger. Any other card number succeeds.
+const (
+	CardSuccess           = "4242424242424242"
+	CardDeclined          = "4000000000000002"
+	CardInsufficientFunds = "4000000000009995"
+	// CardDisputed succeeds, then the provider opens a dispute against the payment
+	CardDisputed = "4000000000000259"
+)
+
+// Decline codes
+const (
+	DeclineCardDeclined      = "card_declined"
+	DeclineInsufficientFunds = "insufficient_funds"
+)
+
+// Webhook delivery is retried since a dispute can arrive before the booking is confirmed
+const (
+	webhookAttempts = 5
+	webhookBackoff  = 2 * time.Second
+)
+
+// ChargeRequest is a charge made against the mock provider
+type ChargeRequest struct {
+	Reference  string // payment reference of the booking intent
+	Amount     money.Money
+	Currency   string
+	CardNumber string
+}
+
+// Charge is the result of a successful or declined charge
+type Charge struct {
+	PaymentID     string
+	Reference     string
+	Status        string // paid or failed
+	DeclineCode   string
+	Amount        money.Money
+	Currency      string
+	CardBrand     string
+	CardLast4     string
+	CreatedAt     time.Time
+	DisputeOpened bool // a dispute webhook is on its way
+}
+
+// MockConfig configures the mock provider
+type MockConfig struct {
+	// Delay is added to every charge to mimic provider latency
+	Delay time.Duration
+	// WebhookURL receives dispute webhooks, signed with WebhookSecret; empty disables them
+	WebhookURL    string
+	WebhookSecret string
+}
+
+type MockProvider struct {
+	config     MockConfig
+	httpClient *http.Client
+}
+
+func NewMockProvider(config MockConfig) *MockProvider {
+	return &MockProvider{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Charge settles or declines a payment depending on the card number. A declined charge is
+// returned with status failed and a decline code rather than as an error.
+func (p *MockProvider) Charge(ctx context.Context, req ChargeRequest) (*Charge, error) {
+	if p.config.Delay > 0 {
+		select {
+		case <-time.After(p.config.Delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	cardNumber := strings.ReplaceAll(req.CardNumber, " ", "")
+	paymentID, err := newMockPaymentID()
+	if err != nil {
+		return nil, err
+	}
+
+	charge := &Charge{
+		PaymentID: paymentID,
+		Reference: req.Reference,
+		Status:    constants.PaymentStatusPaid,
+		Amount:    req.Amount,
+		Currency:  strings.ToUpper(req.Currency),
+		CardBrand: cardBrand(cardNumber),
+		CardLast4: last4(cardNumber),
+		CreatedAt: time.Now(),
+	}
+
+	switch cardNumber {
+	case CardDeclined:
+		charge.Status = constants.PaymentStatusFailed
+		charge.DeclineCode = DeclineCardDeclined
+	case CardInsufficientFunds:
+		charge.Status = constants.PaymentStatusFailed
+		charge.DeclineCode = DeclineInsufficientFunds
+	case CardDisputed:
+		if p.config.WebhookURL != "" {
+			charge.DisputeOpened = true
+			go p.emitDispute(*charge)
+		}
+	}
+
+	return charge, nil
+}
+
+// disputeWebhook matches request.DisputeWebhookRequest
+type disputeWebhook struct {
+	DisputeID  string      `json:"dispute_id"`
+	PaymentID  string      `json:"payment_id"`
+	Status     string      `json:"status"`
+	Reason     string      `json:"reason"`
+	Amount     money.Money `json:"amount"`
+	OccurredAt time.Time   `json:"occurred_at"`
+}
+
+// emitDispute opens a dispute against the charge, retrying until the booking it paid for exists
+func (p *MockProvider) emitDispute(charge Charge) {
+	body, err := json.Marshal(disputeWebhook{
+		DisputeID:  "dp_" + strings.TrimPrefix(charge.PaymentID, "mock_pay_"),
+		PaymentID:  charge.PaymentID,
+		Status:     "open",
+		Reason:     "fraudulent",
+		Amount:     charge.Amount,
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		logger.Errorf("Mock payments: failed to encode dispute webhook: %v", err)
+		return
+	}
+
+	// Every attempt carries the same delivery ID, freshly timestamped and signed
+	deliveryID, err := newMockWebhookID()
+	if err != nil {
+		logger.Errorf("Mock payments: failed to generate webhook ID: %v", err)
+		return
+	}
+
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		err := p.sendWebhook(deliveryID, body)
+		if err == nil {
+			logger.Infof("Mock payments: opened dispute for payment %s", charge.PaymentID)
+			return
+		}
+		logger.Warnf("Mock payments: dispute webhook attempt %d for payment %s failed: %v", attempt, charge.PaymentID, err)
+		time.Sleep(webhookBackoff)
+	}
+}
+
+func (p *MockProvider) sendWebhook(deliveryID string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, p.config.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("X-Webhook-ID", deliveryID)
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+SignWebhook(p.config.WebhookSecret, deliveryID, timestamp, body))
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SignWebhook returns the hex HMAC-SHA256 of "<deliveryID>.<timestamp>.<body>", as checked by
+// the webhook middleware
+func SignWebhook(secret, deliveryID, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(deliveryID + "." + timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newMockPaymentID() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "mock_pay_" + hex.EncodeToString(buf), nil
+}
+
+func newMockWebhookID() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "mock_wh_" + hex.EncodeToString(buf), nil
+}
+
+func cardBrand(cardNumber string) string {
+	switch {
+	case strings.HasPrefix(cardNumber, "4"):
+		return "visa"
+	case strings.HasPrefix(cardNumber, "5"):
+		return "mastercard"
+	case strings.HasPrefix(cardNumber, "34"), strings.HasPrefix(cardNumber, "37"):
+		return "amex"
+	default:
+		return "card"
+	}
+}
+
+func last4(cardNumber string) string {
+	if len(cardNumber) < 4 {
+		return cardNumber
+	}
+	return cardNumber[len(cardNumber)-4:]
+}
@@ -0,0 +1,201 @@
+package redisconn
+
+import (
+	logger "api/pkg/logging"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrUnavailable is returned for every command issued while Redis is marked unavailable,
+// without waiting for connection timeouts. The connection error that starts degraded mode
+// wraps it too.
+var ErrUnavailable = errors.New("redis unavailable: running in degraded mode")
+
+// IsUnavailable reports whether err means Redis was skipped or couldn't be reached
+func IsUnavailable(err error) bool {
+	return errors.Is(err, ErrUnavailable)
+}
+
+// RecoverFunc is called once Redis is reachable again, with the time it became unavailable
+type RecoverFunc func(ctx context.Context, since time.Time)
+
+// Health tracks whether Redis is reachable. It is installed as a client hook: a connection
+// error on any command switches to degraded mode, in which commands fail fast with
+// ErrUnavailable and callers use their database or in-memory fallbacks. A background ping
+// switches back once Redis answers again.
+type Health struct {
+	client   redis.UniversalClient
+	interval time.Duration
+
+	mu        sync.RWMutex
+	degraded  bool
+	since     time.Time
+	onRecover []RecoverFunc
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// probeKey marks the health check's own pings, which must reach Redis even when degraded
+type probeKey struct{}
+
+func NewHealth(client redis.UniversalClient, interval time.Duration) *Health {
+	h := &Health{client: client, interval: interval}
+	client.AddHook(h)
+	return h
+}
+
+// OnRecover registers fn to run when Redis comes back, e.g. to copy state written to the
+// database while degraded. Callbacks must be registered before Start.
+func (h *Health) OnRecover(fn RecoverFunc) {
+	h.onRecover = append(h.onRecover, fn)
+}
+
+// Start checks Redis immediately, then on every interval until Stop
+func (h *Health) Start(ctx context.Context) {
+	ctx, h.cancel = context.WithCancel(ctx)
+	h.check(ctx)
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				h.check(ctx)
+			}
+		}
+	}()
+}
+
+// Stop ends the background checks
+func (h *Health) Stop() {
+	if h.cancel == nil {
+		return
+	}
+	h.cancel()
+	h.wg.Wait()
+}
+
+// Degraded reports whether Redis is currently treated as unavailable
+func (h *Health) Degraded() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.degraded
+}
+
+// Status returns whether Redis is treated as unavailable and since when
+func (h *Health) Status() (degraded bool, since time.Time) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.degraded, h.since
+}
+
+func (h *Health) check(ctx context.Context) {
+	err := h.client.Ping(context.WithValue(ctx, probeKey{}, true)).Err()
+	if err != nil {
+		if ctx.Err() == nil {
+			h.markUnavailable(err)
+		}
+		return
+	}
+	h.markAvailable(ctx)
+}
+
+func (h *Health) markUnavailable(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.degraded {
+		return
+	}
+	h.degraded = true
+	h.since = time.Now()
+	logger.Warnf("Redis unavailable, entering degraded mode: %v", err)
+}
+
+func (h *Health) markAvailable(ctx context.Context) {
+	h.mu.Lock()
+	if !h.degraded {
+		h.mu.Unlock()
+		return
+	}
+	since := h.since
+	h.degraded = false
+	h.since = time.Time{}
+	h.mu.Unlock()
+
+	logger.Infof("Redis available again after %s, leaving degraded mode", time.Since(since).Round(time.Second))
+	for _, fn := range h.onRecover {
+		fn(ctx, since)
+	}
+}
+
+// isConnectionError tells network failures apart from replies such as redis.Nil or a
+// script error, which come from a working server
+func isConnectionError(err error) bool {
+	if err == nil || err == redis.Nil || errors.Is(err, ErrUnavailable) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		// The caller gave up; that says nothing about Redis
+		return false
+	}
+	var replyErr redis.Error
+	if errors.As(err, &replyErr) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, redis.ErrClosed) || errors.Is(err, redis.ErrPoolTimeout) ||
+		errors.Is(err, net.ErrClosed) || errors.Is(err, io.EOF)
+}
+
+// DialHook implements redis.Hook
+func (h *Health) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements redis.Hook
+func (h *Health) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if h.Degraded() && ctx.Value(probeKey{}) == nil {
+			cmd.SetErr(ErrUnavailable)
+			return ErrUnavailable
+		}
+		err := next(ctx, cmd)
+		if isConnectionError(err) {
+			h.markUnavailable(err)
+			err = fmt.Errorf("%w: %w", ErrUnavailable, err)
+			cmd.SetErr(err)
+		}
+		return err
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook
+func (h *Health) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if h.Degraded() {
+			for _, cmd := range cmds {
+				cmd.SetErr(ErrUnavailable)
+			}
+			return ErrUnavailable
+		}
+		err := next(ctx, cmds)
+		if isConnectionError(err) {
+			h.markUnavailable(err)
+			err = fmt.Errorf("%w: %w", ErrUnavailable, err)
+		}
+		return err
+	}
+}
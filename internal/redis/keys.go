@@ -0,0 +1,103 @@
+package redisconn
+
+import (
+	"api/constants"
+	"fmt"
+)
+
+// Keys read or written together in one script, transaction or pipeline must hash to the same
+// cluster slot. Keys scoped to an event carry its {event:<id>} hash tag, so only the part in
+// braces picks the slot and all of an event's keys live on one node.
+
+// EventTag is the hash tag shared by every key of an event
+func EventTag(eventID uint) string {
+	return fmt.Sprintf("{event:%d}", eventID)
+}
+
+// SeatLockKey holds the lock on one seat of an event
+func SeatLockKey(eventID, seatID uint) string {
+	return fmt.Sprintf("%s%s:%d", constants.SeatLockPrefix, EventTag(eventID), seatID)
+}
+
+// WaitlistQueueKey is the list of an event's waitlist entries in serving order
+func WaitlistQueueKey(eventID uint) string {
+	return fmt.Sprintf("waitlist:%s", EventTag(eventID))
+}
+
+// WaitlistUserKey holds a user's entry in an event's waitlist
+func WaitlistUserKey(eventID, userID uint) string {
+	return fmt.Sprintf("waitlist:%s:user:%d", EventTag(eventID), userID)
+}
+
+// QueueKey is the sorted set of users waiting in an event's on-sale queue, scored by join time
+func QueueKey(eventID uint) string {
+	return fmt.Sprintf("%s%s", constants.QueuePrefix, EventTag(eventID))
+}
+
+// LiveLocksKey indexes an event's held seat locks by expiry, for the live on-sale dashboard
+func LiveLocksKey(eventID uint) string {
+	return fmt.Sprintf("live:%s:locks", EventTag(eventID))
+}
+
+// LiveCounterKey counts one booking flow outcome of an event during one minute
+func LiveCounterKey(eventID uint, counter string, minute int64) string {
+	return fmt.Sprintf("live:%s:%s:%d", EventTag(eventID), counter, minute)
+}
+
+// PlatformCounterKey counts one booking flow outcome across all events during one minute, for
+// alerting. Its hash tag keeps a window's keys on one node.
+func PlatformCounterKey(counter string, minute int64) string {
+	return fmt.Sprintf("live:{platform}:%s:%d", counter, minute)
+}
+
+// EventCacheKey holds one cached part of an event's public data, e.g. its details or seat map
+func EventCacheKey(eventID uint, part string) string {
+	return fmt.Sprintf("cache:%s:%s", EventTag(eventID), part)
+}
+
+// AvailabilityKey counts an event's seats still for sale, mirroring events.available_seats
+func AvailabilityKey(eventID uint) string {
+	return fmt.Sprintf("availability:%s", EventTag(eventID))
+}
+
+// SeatBitmapKey is an event's seat availability bitmap, one bit per seat in row-major order
+func SeatBitmapKey(eventID uint) string {
+	return fmt.Sprintf("seatmap:%s:bits", EventTag(eventID))
+}
+
+// SeatBitmapIndexKey maps an event's seat IDs to their bit in the seat availability bitmap
+func SeatBitmapIndexKey(eventID uint) string {
+	return fmt.Sprintf("seatmap:%s:index", EventTag(eventID))
+}
+
+// SeatBitmapVersionKey counts the changes to an event's seat availability bitmap
+func SeatBitmapVersionKey(eventID uint) string {
+	return fmt.Sprintf("seatmap:%s:version", EventTag(eventID))
+}
+
+// AutocompleteKey holds the cached suggestions of one kind, e.g. venues or cities, for a
+// normalized query and number of suggestions
+func AutocompleteKey(kind, query string, limit int) string {
+	return fmt.Sprintf("autocomplete:%s:%d:%s", kind, limit, query)
+}
+
+// JobTag is the hash tag shared by every key of a scheduled job
+func JobTag(job string) string {
+	return fmt.Sprintf("{job:%s}", job)
+}
+
+// JobLockKey is held by the instance running a scheduled job, renewed while the run lasts
+func JobLockKey(job string) string {
+	return fmt.Sprintf("jobs:%s:lock", JobTag(job))
+}
+
+// JobRunKey marks a scheduled job as run for the interval starting at slot (Unix seconds)
+func JobRunKey(job string, slot int64) string {
+	return fmt.Sprintf("jobs:%s:run:%d", JobTag(job), slot)
+}
+
+// WebhookDeliveryKey marks a payment provider webhook delivery as received, so a replay of it
+// is refused
+func WebhookDeliveryKey(id string) string {
+	return fmt.Sprintf("webhooks:delivery:%s", id)
+}
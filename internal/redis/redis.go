@@ -2,29 +2,137 @@ package redisconn
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// Redis deployment modes
+const (
+	ModeSingle   = "single"
+	ModeCluster  = "cluster"
+	ModeSentinel = "sentinel"
+)
+
+// Config selects how to reach Redis. Single mode connects to URL; cluster mode to the cluster
+// nodes in Addrs; sentinel mode asks the sentinels in Addrs for MasterName's current master.
+type Config struct {
+	Mode             string
+	URL              string
+	Addrs            string // comma-separated host:port list
+	MasterName       string
+	Username         string
+	Password         string
+	SentinelPassword string
+}
+
 type RedisClient struct {
-	Client *redis.Client
+	Client redis.UniversalClient
+}
+
+// Optimized connection pool for high-traffic scenarios; in cluster mode the pool is per node
+const (
+	poolSize        = 50 // this increases the number of connections in the pool, better for high concurrency
+	minIdleConns    = 10
+	maxIdleConns    = 25
+	connMaxLifetime = 30 * time.Minute
+	connMaxIdleTime = 5 * time.Minute
+	maxRetries      = 3
+	maxRetryBackoff = 512 * time.Millisecond
+)
+
+func NewRedisClient(cfg Config) (*RedisClient, error) {
+	switch cfg.Mode {
+	case "", ModeSingle:
+		opt, err := redis.ParseURL(cfg.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Redis URL: %w", err)
+		}
+		opt.PoolSize = poolSize
+		opt.MinIdleConns = minIdleConns
+		opt.MaxIdleConns = maxIdleConns
+		opt.ConnMaxLifetime = connMaxLifetime
+		opt.ConnMaxIdleTime = connMaxIdleTime
+		opt.MaxRetries = maxRetries
+		opt.MaxRetryBackoff = maxRetryBackoff
+		return &RedisClient{Client: redis.NewClient(opt)}, nil
+
+	case ModeCluster:
+		addrs := splitAddrs(cfg.Addrs)
+		if len(addrs) == 0 {
+			return nil, fmt.Errorf("cluster mode needs at least one node address")
+		}
+		return &RedisClient{Client: redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:           addrs,
+			Username:        cfg.Username,
+			Password:        cfg.Password,
+			PoolSize:        poolSize,
+			MinIdleConns:    minIdleConns,
+			MaxIdleConns:    maxIdleConns,
+			ConnMaxLifetime: connMaxLifetime,
+			ConnMaxIdleTime: connMaxIdleTime,
+			MaxRetries:      maxRetries,
+			MaxRetryBackoff: maxRetryBackoff,
+		})}, nil
+
+	case ModeSentinel:
+		addrs := splitAddrs(cfg.Addrs)
+		if len(addrs) == 0 || cfg.MasterName == "" {
+			return nil, fmt.Errorf("sentinel mode needs sentinel addresses and a master name")
+		}
+		return &RedisClient{Client: redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       cfg.MasterName,
+			SentinelAddrs:    addrs,
+			SentinelPassword: cfg.SentinelPassword,
+			Username:         cfg.Username,
+			Password:         cfg.Password,
+			PoolSize:         poolSize,
+			MinIdleConns:     minIdleConns,
+			MaxIdleConns:     maxIdleConns,
+			ConnMaxLifetime:  connMaxLifetime,
+			ConnMaxIdleTime:  connMaxIdleTime,
+			MaxRetries:       maxRetries,
+			MaxRetryBackoff:  maxRetryBackoff,
+		})}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown Redis mode %q", cfg.Mode)
+	}
 }
 
-func NewRedisClient(url string) *RedisClient {
-	opt, _ := redis.ParseURL(url)
+func splitAddrs(addrs string) []string {
+	var result []string
+	for _, addr := range strings.Split(addrs, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			result = append(result, addr)
+		}
+	}
+	return result
+}
 
-	// Optimized connection pool for high-traffic scenarios
-	opt.PoolSize = 50 // this increases the number of connections in the pool, better for high concurrency
-	opt.MinIdleConns = 10
-	opt.MaxIdleConns = 25
-	opt.ConnMaxLifetime = 30 * time.Minute
-	opt.ConnMaxIdleTime = 5 * time.Minute
-	opt.MaxRetries = 3
-	opt.MaxRetryBackoff = 512 * time.Millisecond
+// ScanKeys calls fn for every key matching pattern. Unlike KEYS it doesn't block the server,
+// and in cluster mode it scans every master rather than whichever node the command lands on.
+func ScanKeys(ctx context.Context, client redis.UniversalClient, pattern string, fn func(key string) error) error {
+	scan := func(ctx context.Context, node *redis.Client) error {
+		iter := node.Scan(ctx, 0, pattern, 100).Iterator()
+		for iter.Next(ctx) {
+			if err := fn(iter.Val()); err != nil {
+				return err
+			}
+		}
+		return iter.Err()
+	}
 
-	client := redis.NewClient(opt)
-	return &RedisClient{Client: client}
+	switch c := client.(type) {
+	case *redis.ClusterClient:
+		return c.ForEachMaster(ctx, scan)
+	case *redis.Client:
+		return scan(ctx, c)
+	default:
+		return fmt.Errorf("unsupported Redis client %T", client)
+	}
 }
 
 func (r *RedisClient) LockSeat(ctx context.Context, seatID string, ttl time.Duration) (bool, error) {
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SetSeatAccessibility designates a seat as accessible and replaces its companion seats, or clears
+// the designation and releases its companions when accessible is false. It returns the seat and
+// its companions after the change.
+func (s *eventRepository) SetSeatAccessibility(ctx context.Context, seatID uint, accessible bool, companionSeatIDs []uint) (*entities.Seat, []entities.Seat, error) {
+	var seat entities.Seat
+	var companions []entities.Seat
+
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Scopes(tenantScopeVia(ctx, "seats.event_id", "events")).
+			Clauses(clause.Locking{Strength: "UPDATE"}).First(&seat, seatID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewNotFoundError("Seat not found", errors.ErrRecordNotFound)
+			}
+			return errors.NewInternalError("Failed to fetch seat", err)
+		}
+		if seat.IsCompanion {
+			return errors.NewBadRequestError("A companion seat cannot be designated accessible", nil)
+		}
+
+		if !accessible && len(companionSeatIDs) > 0 {
+			return errors.NewBadRequestError("Only accessible seats can have companion seats", nil)
+		}
+
+		// Release the current companions before assigning new ones
+		if err := tx.Model(&entities.Seat{}).Where("companion_of_seat_id = ?", seat.ID).
+			Updates(map[string]interface{}{
+				"is_companion":         false,
+				"companion_of_seat_id": nil,
+				"updated_at":           time.Now(),
+			}).Error; err != nil {
+			return errors.NewInternalError("Failed to release companion seats", err)
+		}
+
+		if len(companionSeatIDs) > 0 {
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id IN ?", companionSeatIDs).
+				Find(&companions).Error; err != nil {
+				return errors.NewInternalError("Failed to fetch companion seats", err)
+			}
+			if len(companions) != len(companionSeatIDs) {
+				return errors.NewBadRequestError("Companion seat not found", nil)
+			}
+			for _, companion := range companions {
+				if companion.EventID != seat.EventID || companion.ID == seat.ID {
+					return errors.NewBadRequestError("Companion seats must be other seats of the same event", nil)
+				}
+				if companion.IsAccessible || companion.IsCompanion {
+					return errors.NewConflictError("Companion seat is already designated", nil)
+				}
+			}
+
+			if err := tx.Model(&entities.Seat{}).Where("id IN ?", companionSeatIDs).
+				Updates(map[string]interface{}{
+					"is_companion":         true,
+					"companion_of_seat_id": seat.ID,
+					"updated_at":           time.Now(),
+				}).Error; err != nil {
+				return errors.NewInternalError("Failed to assign companion seats", err)
+			}
+			for i := range companions {
+				companions[i].IsCompanion = true
+				companions[i].CompanionOfSeatID = &seat.ID
+			}
+		}
+
+		seat.IsAccessible = accessible
+		if err := tx.Model(&seat).Update("is_accessible", accessible).Error; err != nil {
+			return errors.NewInternalError("Failed to update seat", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &seat, companions, nil
+}
+
+// checkCompanionSeat only allows a companion seat to be booked by the user holding its
+// accessible seat, either as a confirmed booking or a pending booking intent
+func checkCompanionSeat(db *gorm.DB, seat *entities.Seat, userID uint) error {
+	if !seat.IsCompanion {
+		return nil
+	}
+	if seat.CompanionOfSeatID == nil {
+		return errors.NewBadRequestError(constants.ErrCompanionSeat, nil)
+	}
+
+	var count int64
+	if err := db.Model(&entities.Booking{}).
+		Where("seat_id = ? AND user_id = ? AND status = ?", *seat.CompanionOfSeatID, userID, constants.BookingStatusConfirmed).
+		Count(&count).Error; err != nil {
+		return errors.NewInternalError("Failed to check accessible seat booking", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if err := db.Model(&entities.BookingIntent{}).
+		Where("seat_id = ? AND user_id = ? AND status = ?", *seat.CompanionOfSeatID, userID, constants.IntentStatusPending).
+		Count(&count).Error; err != nil {
+		return errors.NewInternalError("Failed to check accessible seat booking", err)
+	}
+	if count == 0 {
+		return errors.NewBadRequestError(constants.ErrCompanionSeat, nil)
+	}
+	return nil
+}
+
+// companionBookingLink returns the user's confirmed booking of the accessible seat a companion
+// seat belongs to, which the companion booking is linked to. The accessible seat must be
+// confirmed first.
+func companionBookingLink(tx *gorm.DB, seat *entities.Seat, userID uint) (*uint, error) {
+	if !seat.IsCompanion {
+		return nil, nil
+	}
+	if seat.CompanionOfSeatID == nil {
+		return nil, errors.NewBadRequestError(constants.ErrCompanionSeat, nil)
+	}
+
+	var booking entities.Booking
+	if err := tx.Select("id").
+		Where("seat_id = ? AND user_id = ? AND status = ?", *seat.CompanionOfSeatID, userID, constants.BookingStatusConfirmed).
+		First(&booking).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewBadRequestError("Confirm the accessible seat booking before its companion seat", nil)
+		}
+		return nil, errors.NewInternalError("Failed to fetch accessible seat booking", err)
+	}
+	return &booking.ID, nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AccountRepository stores account deletions, from the request through the grace period to
+// the final anonymization
+type AccountRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountRepository(db *gorm.DB) *AccountRepository {
+	return &AccountRepository{db: db}
+}
+
+// ScheduleDeletion blocks the account until it is anonymized at the given time, revoking its
+// tokens. Accounts already scheduled for deletion are a conflict.
+func (s *AccountRepository) ScheduleDeletion(ctx context.Context, userID uint, at time.Time, restoreTokenHash string) (*entities.User, error) {
+	result := conn(ctx, s.db).Model(&entities.User{}).
+		Where("id = ? AND deletion_scheduled_at IS NULL", userID).
+		Updates(map[string]interface{}{
+			"deletion_scheduled_at": at,
+			"restore_token_hash":    restoreTokenHash,
+			"token_version":         gorm.Expr("token_version + 1"),
+		})
+	if result.Error != nil {
+		return nil, errors.NewInternalError("Failed to schedule account deletion", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		if _, err := s.getUser(ctx, userID); err != nil {
+			return nil, err
+		}
+		return nil, errors.NewConflictError("Account deletion is already scheduled", nil)
+	}
+	return s.getUser(ctx, userID)
+}
+
+// Restore cancels the deletion of the account the restore token was issued for, if its grace
+// period hasn't ended
+func (s *AccountRepository) Restore(ctx context.Context, restoreTokenHash string, now time.Time) (*entities.User, error) {
+	var user entities.User
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("restore_token_hash = ? AND deletion_scheduled_at > ? AND anonymized_at IS NULL", restoreTokenHash, now).
+			First(&user).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewBadRequestError("Restore link is invalid or expired", nil)
+			}
+			return errors.NewInternalError("Failed to fetch account", err)
+		}
+		if err := tx.Model(&entities.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+			"deletion_scheduled_at": nil,
+			"restore_token_hash":    "",
+		}).Error; err != nil {
+			return errors.NewInternalError("Failed to restore account", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.getUser(ctx, user.ID)
+}
+
+// GetDueDeletions returns the accounts whose grace period ended before the given time and that
+// weren't anonymized yet
+func (s *AccountRepository) GetDueDeletions(ctx context.Context, before time.Time, limit int) ([]uint, error) {
+	var userIDs []uint
+	if err := conn(ctx, s.db).Model(&entities.User{}).
+		Where("deletion_scheduled_at <= ? AND anonymized_at IS NULL", before).
+		Order("deletion_scheduled_at").
+		Limit(limit).
+		Pluck("id", &userIDs).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch due account deletions", err)
+	}
+	return userIDs, nil
+}
+
+// Anonymize erases the personal data of an account and of the attendees on its bookings,
+// live and archived. Bookings, payments and reviews are kept for the organizers' accounting,
+// without anything identifying the user. Accounts restored meanwhile are left alone.
+func (s *AccountRepository) Anonymize(ctx context.Context, userID uint, now time.Time) (bool, error) {
+	anonymized := false
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.User{}).
+			Where("id = ? AND deletion_scheduled_at <= ? AND anonymized_at IS NULL", userID, now).
+			Updates(map[string]interface{}{
+				"email":              fmt.Sprintf("deleted-%d@deleted.invalid", userID),
+				"password":           "",
+				"first_name":         "",
+				"last_name":          "",
+				"phone":              "",
+				"restore_token_hash": "",
+				"anonymized_at":      now,
+				"token_version":      gorm.Expr("token_version + 1"),
+			})
+		if result.Error != nil {
+			return errors.NewInternalError("Failed to anonymize account", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		anonymized = true
+
+		attendee := map[string]interface{}{
+			"attendee_name":       "",
+			"attendee_birth_date": "",
+			"attendee_id_number":  "",
+		}
+		if err := tx.Unscoped().Model(&entities.Booking{}).Where("user_id = ?", userID).Updates(attendee).Error; err != nil {
+			return errors.NewInternalError("Failed to anonymize bookings", err)
+		}
+		if err := tx.Table(bookingsArchiveTable).Where("user_id = ?", userID).Updates(attendee).Error; err != nil {
+			return errors.NewInternalError("Failed to anonymize archived bookings", err)
+		}
+		return nil
+	})
+	return anonymized, err
+}
+
+func (s *AccountRepository) getUser(ctx context.Context, userID uint) (*entities.User, error) {
+	var user entities.User
+	if err := conn(ctx, s.db).First(&user, userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("User not found", errors.ErrUserNotFound)
+		}
+		return nil, errors.NewInternalError("Database error", err)
+	}
+	user.Password = ""
+	return &user, nil
+}
@@ -1,21 +1,34 @@
 package repository
 
 import (
+	"api/constants"
 	"api/internal/entities"
+	"api/pkg/errors"
+	"api/pkg/money"
+	"context"
 	"time"
 
 	"gorm.io/gorm"
 )
 
 type AnalyticsRepository interface {
-	GetTotalBookingCounts() (confirmed int64, cancelled int64, err error)
-	GetTotalRevenue() (float64, error)
-	GetMostPopularEvents(limit int) ([]entities.EventBookingStats, error)
-	GetMostBookedEvents(limit int) ([]entities.EventBookingStats, error)
-	GetCapacityUtilization() ([]entities.EventBookingStats, error)
-	GetDailyBookingStats(days int) ([]entities.DailyStats, error)
+	GetTotalBookingCounts(ctx context.Context) (confirmed int64, cancelled int64, err error)
+	GetTotalRevenue(ctx context.Context) (money.Money, error)
+	GetMostPopularEvents(ctx context.Context, limit int) ([]entities.EventBookingStats, error)
+	GetMostBookedEvents(ctx context.Context, limit int) ([]entities.EventBookingStats, error)
+	GetCapacityUtilization(ctx context.Context) ([]entities.EventBookingStats, error)
+	GetDailyBookingStats(ctx context.Context, days int) ([]entities.DailyStats, error)
+	GetLiveEvent(ctx context.Context, eventID uint) (*entities.Event, error)
+	CountPendingIntents(ctx context.Context, eventID uint) (int64, error)
+	GetWaitlistConversion(ctx context.Context, eventID uint, limit int) ([]entities.WaitlistConversion, error)
+	GetAcquisitionChannels(ctx context.Context, filter entities.AcquisitionFilter) ([]entities.AcquisitionChannel, error)
+	GetAcquisitionTotals(ctx context.Context, filter entities.AcquisitionFilter) (bookings int64, revenue money.Money, err error)
+	GetReportVenue(ctx context.Context, venueID uint) (*entities.Venue, error)
+	GetVenueMonths(ctx context.Context, venueID uint, from, to time.Time) ([]entities.VenueMonth, error)
+	GetSeatHeat(ctx context.Context, venueID uint, from, to time.Time) ([]entities.SeatHeat, int64, error)
 }
 
+// Analytics cover the tenant in ctx, or every tenant for platform admins
 type analyticsRepository struct {
 	db *gorm.DB
 }
@@ -24,18 +37,32 @@ func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
 	return &analyticsRepository{db: db}
 }
 
+// excludeSandbox leaves out the events, or bookings of events, that rehearse an on-sale
+func excludeSandbox(table string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(table + ".sandbox = false")
+	}
+}
+
+// excludeComps leaves out complimentary tickets, which are neither sales nor revenue
+func excludeComps(table string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(table+".channel <> ?", constants.SalesChannelComp)
+	}
+}
+
 // GetTotalBookingCounts returns the count of confirmed and cancelled bookings
-func (r *analyticsRepository) GetTotalBookingCounts() (confirmed int64, cancelled int64, err error) {
-	err = r.db.Model(&entities.Booking{}).
+func (r *analyticsRepository) GetTotalBookingCounts(ctx context.Context) (confirmed int64, cancelled int64, err error) {
+	err = conn(ctx, r.db).Model(&entities.Booking{}).Scopes(tenantScope(ctx, "bookings"), excludeSandbox("bookings"), excludeComps("bookings")).
 		Select("COUNT(CASE WHEN status = 'confirmed' THEN 1 END) as confirmed, COUNT(CASE WHEN status = 'cancelled' THEN 1 END) as cancelled").
 		Row().Scan(&confirmed, &cancelled)
 	return
 }
 
 // GetTotalRevenue returns the total revenue from confirmed bookings
-func (r *analyticsRepository) GetTotalRevenue() (float64, error) {
-	var revenue float64
-	err := r.db.Model(&entities.Booking{}).
+func (r *analyticsRepository) GetTotalRevenue(ctx context.Context) (money.Money, error) {
+	var revenue money.Money
+	err := conn(ctx, r.db).Model(&entities.Booking{}).Scopes(tenantScope(ctx, "bookings"), excludeSandbox("bookings"), excludeComps("bookings")).
 		Where("status = ?", "confirmed").
 		Select("COALESCE(SUM(total_amount), 0)").
 		Row().Scan(&revenue)
@@ -43,10 +70,10 @@ func (r *analyticsRepository) GetTotalRevenue() (float64, error) {
 }
 
 // GetMostPopularEvents returns events with highest booking counts
-func (r *analyticsRepository) GetMostPopularEvents(limit int) ([]entities.EventBookingStats, error) {
+func (r *analyticsRepository) GetMostPopularEvents(ctx context.Context, limit int) ([]entities.EventBookingStats, error) {
 	var results []entities.EventBookingStats
 
-	err := r.db.Table("bookings b").
+	err := conn(ctx, r.db).Table("bookings b").Scopes(tenantScope(ctx, "b"), excludeSandbox("b"), excludeComps("b")).
 		Select(`
 			e.id as event_id,
 			e.name as event_name,
@@ -69,10 +96,10 @@ func (r *analyticsRepository) GetMostPopularEvents(limit int) ([]entities.EventB
 }
 
 // GetMostBookedEvents returns events with highest confirmed bookings
-func (r *analyticsRepository) GetMostBookedEvents(limit int) ([]entities.EventBookingStats, error) {
+func (r *analyticsRepository) GetMostBookedEvents(ctx context.Context, limit int) ([]entities.EventBookingStats, error) {
 	var results []entities.EventBookingStats
 
-	err := r.db.Table("bookings b").
+	err := conn(ctx, r.db).Table("bookings b").Scopes(tenantScope(ctx, "b"), excludeSandbox("b"), excludeComps("b")).
 		Select(`
 			e.id as event_id,
 			e.name as event_name,
@@ -96,10 +123,10 @@ func (r *analyticsRepository) GetMostBookedEvents(limit int) ([]entities.EventBo
 }
 
 // GetCapacityUtilization returns capacity utilization for all events
-func (r *analyticsRepository) GetCapacityUtilization() ([]entities.EventBookingStats, error) {
+func (r *analyticsRepository) GetCapacityUtilization(ctx context.Context) ([]entities.EventBookingStats, error) {
 	var results []entities.EventBookingStats
 
-	err := r.db.Table("events e").
+	err := conn(ctx, r.db).Table("events e").Scopes(tenantScope(ctx, "e"), excludeSandbox("e")).
 		Select(`
 			e.id as event_id,
 			e.name as event_name,
@@ -121,10 +148,10 @@ func (r *analyticsRepository) GetCapacityUtilization() ([]entities.EventBookingS
 }
 
 // GetDailyBookingStats returns daily booking statistics for the last N days
-func (r *analyticsRepository) GetDailyBookingStats(days int) ([]entities.DailyStats, error) {
+func (r *analyticsRepository) GetDailyBookingStats(ctx context.Context, days int) ([]entities.DailyStats, error) {
 	var results []entities.DailyStats
 
-	err := r.db.Table("bookings").
+	err := conn(ctx, r.db).Table("bookings").Scopes(tenantScope(ctx, "bookings"), excludeSandbox("bookings"), excludeComps("bookings")).
 		Select(`
 			DATE(booked_at) as date,
 			COUNT(*) as total_bookings,
@@ -139,3 +166,239 @@ func (r *analyticsRepository) GetDailyBookingStats(days int) ([]entities.DailySt
 
 	return results, err
 }
+
+// GetLiveEvent returns the event the live dashboard reports on
+func (r *analyticsRepository) GetLiveEvent(ctx context.Context, eventID uint) (*entities.Event, error) {
+	var event entities.Event
+	if err := conn(ctx, r.db).Scopes(tenantScope(ctx, "events")).
+		Select("id", "name").
+		First(&event, eventID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Event not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch event", err)
+	}
+	return &event, nil
+}
+
+// CountPendingIntents counts an event's pending intents whose seat lock hasn't expired
+func (r *analyticsRepository) CountPendingIntents(ctx context.Context, eventID uint) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.db).Model(&entities.BookingIntent{}).
+		Where("event_id = ? AND status = ? AND lock_expires_at > ?", eventID, constants.IntentStatusPending, time.Now()).
+		Count(&count).Error; err != nil {
+		return 0, errors.NewInternalError("Failed to count pending intents", err)
+	}
+	return count, nil
+}
+
+// GetWaitlistConversion counts, per event, the waitlist entries that were offered a seat and
+// those whose user booked the event after joining, with the average time from joining to the
+// offer and from the offer to the booking. An eventID of 0 covers the events with the latest
+// start times that had a waitlist, up to limit.
+func (r *analyticsRepository) GetWaitlistConversion(ctx context.Context, eventID uint, limit int) ([]entities.WaitlistConversion, error) {
+	var results []entities.WaitlistConversion
+
+	query := conn(ctx, r.db).Table("event_queues q").Scopes(tenantScope(ctx, "e"), excludeSandbox("e")).
+		Select(`
+			e.id as event_id,
+			e.name as event_name,
+			v.name as venue_name,
+			(v.rows * v.columns) as capacity,
+			e.start_time,
+			COUNT(q.id) as waitlisted,
+			COUNT(CASE WHEN q.status = 'waiting' THEN 1 END) as still_waiting,
+			COUNT(q.active_at) as promoted,
+			COUNT(p.purchased_at) as purchased,
+			COUNT(CASE WHEN p.purchased_at >= q.active_at THEN 1 END) as purchased_after_promotion,
+			COALESCE(AVG(EXTRACT(EPOCH FROM q.active_at - q.joined_at)), 0) as avg_wait_seconds,
+			COALESCE(AVG(CASE WHEN p.purchased_at >= q.active_at THEN EXTRACT(EPOCH FROM p.purchased_at - q.active_at) END), 0) as avg_time_to_purchase
+		`).
+		Joins("JOIN events e ON q.event_id = e.id").
+		Joins("JOIN venues v ON e.venue_id = v.id").
+		// The user's first booking of the event since joining, cancelled later or not
+		Joins(`LEFT JOIN LATERAL (
+			SELECT MIN(b.created_at) as purchased_at FROM bookings b
+			WHERE b.event_id = q.event_id AND b.user_id = q.user_id AND b.created_at >= q.joined_at AND b.deleted_at IS NULL
+		) p ON true`).
+		Where("q.kind = ?", constants.QueueKindWaitlist)
+	if eventID != 0 {
+		query = query.Where("q.event_id = ?", eventID)
+	}
+
+	if err := query.
+		Group("e.id, e.name, v.name, v.rows, v.columns, e.start_time").
+		Order("e.start_time DESC").
+		Limit(limit).
+		Scan(&results).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch waitlist conversion", err)
+	}
+	return results, nil
+}
+
+// acquisitionChannel labels a booking with its utm_source, "referral" when it only has a
+// referral code and "direct" otherwise
+const acquisitionChannel = `CASE WHEN b.utm_source <> '' THEN b.utm_source WHEN b.referral_code <> '' THEN 'referral' ELSE 'direct' END`
+
+// acquisitionBookings restricts a query over bookings b to the ones an acquisition report covers
+func acquisitionBookings(ctx context.Context, filter entities.AcquisitionFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Scopes(tenantScope(ctx, "b"), excludeSandbox("b"), excludeComps("b")).Where("b.deleted_at IS NULL")
+		if filter.EventID != 0 {
+			db = db.Where("b.event_id = ?", filter.EventID)
+		}
+		if filter.From != nil {
+			db = db.Where("b.booked_at >= ?", *filter.From)
+		}
+		if filter.To != nil {
+			db = db.Where("b.booked_at < ?", *filter.To)
+		}
+		if filter.GroupBy == entities.AcquisitionByReferral {
+			db = db.Where("b.referral_code <> ''")
+		}
+		return db
+	}
+}
+
+// GetAcquisitionChannels counts bookings and sums revenue per acquisition channel, broken down
+// by medium and campaign or by referral code as the filter asks, highest revenue first
+func (r *analyticsRepository) GetAcquisitionChannels(ctx context.Context, filter entities.AcquisitionFilter) ([]entities.AcquisitionChannel, error) {
+	var results []entities.AcquisitionChannel
+
+	columns := acquisitionChannel + " as channel"
+	groups := "channel"
+	switch filter.GroupBy {
+	case entities.AcquisitionByCampaign:
+		columns += ", b.utm_medium as medium, b.utm_campaign as campaign"
+		groups += ", b.utm_medium, b.utm_campaign"
+	case entities.AcquisitionByReferral:
+		columns += ", b.referral_code"
+		groups += ", b.referral_code"
+	}
+
+	err := conn(ctx, r.db).Table("bookings b").Scopes(acquisitionBookings(ctx, filter)).
+		Select(columns + `,
+			COUNT(CASE WHEN b.status = 'confirmed' THEN 1 END) as bookings,
+			COUNT(CASE WHEN b.status <> 'confirmed' THEN 1 END) as cancelled,
+			COALESCE(SUM(CASE WHEN b.status = 'confirmed' THEN b.total_amount ELSE 0 END), 0) as revenue
+		`).
+		Group(groups).
+		Order("revenue DESC, bookings DESC, channel ASC").
+		Limit(filter.Limit).
+		Scan(&results).Error
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to fetch acquisition channels", err)
+	}
+	return results, nil
+}
+
+// GetAcquisitionTotals returns the confirmed bookings and revenue an acquisition report covers
+func (r *analyticsRepository) GetAcquisitionTotals(ctx context.Context, filter entities.AcquisitionFilter) (bookings int64, revenue money.Money, err error) {
+	err = conn(ctx, r.db).Table("bookings b").Scopes(acquisitionBookings(ctx, filter)).
+		Where("b.status = ?", constants.BookingStatusConfirmed).
+		Select("COUNT(*), COALESCE(SUM(b.total_amount), 0)").
+		Row().Scan(&bookings, &revenue)
+	if err != nil {
+		err = errors.NewInternalError("Failed to fetch acquisition totals", err)
+	}
+	return
+}
+
+// GetReportVenue returns the venue a utilization report covers
+func (r *analyticsRepository) GetReportVenue(ctx context.Context, venueID uint) (*entities.Venue, error) {
+	var venue entities.Venue
+	if err := conn(ctx, r.db).Scopes(tenantScope(ctx, "venues")).
+		Select("id", "name", "rows", "columns").
+		First(&venue, venueID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Venue not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch venue", err)
+	}
+	return &venue, nil
+}
+
+// GetVenueMonths counts a venue's events starting between from and to, their seats and
+// confirmed bookings, and sums their revenue, per month of the start time. Cancelled events
+// are left out. Bookings are read with the archived ones, as past months' events are archived.
+func (r *analyticsRepository) GetVenueMonths(ctx context.Context, venueID uint, from, to time.Time) ([]entities.VenueMonth, error) {
+	var months []entities.VenueMonth
+
+	db := conn(ctx, r.db)
+	sales := bookingHistory(db.Session(&gorm.Session{NewDB: true})).
+		Select("bookings.event_id, COUNT(*) as booked_seats, COALESCE(SUM(bookings.total_amount), 0) as revenue").
+		Where("bookings.status = ? AND bookings.deleted_at IS NULL", constants.BookingStatusConfirmed).
+		Group("bookings.event_id")
+
+	if err := db.Table("events e").Scopes(tenantScope(ctx, "e"), excludeSandbox("e")).
+		Select(`
+			to_char(date_trunc('month', e.start_time), 'YYYY-MM') as month,
+			COUNT(e.id) as events,
+			COALESCE(SUM(v.rows * v.columns), 0) as total_seats,
+			COALESCE(SUM(s.booked_seats), 0) as booked_seats,
+			COALESCE(SUM(s.revenue), 0) as revenue
+		`).
+		Joins("JOIN venues v ON e.venue_id = v.id").
+		Joins("LEFT JOIN (?) s ON s.event_id = e.id", sales).
+		Where("e.venue_id = ? AND e.status <> ? AND e.deleted_at IS NULL", venueID, constants.EventStatusCancelled).
+		Where("e.start_time >= ? AND e.start_time < ?", from, to).
+		Group("1").
+		Order("1").
+		Scan(&months).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch venue utilization", err)
+	}
+	return months, nil
+}
+
+// heatmapEvents restricts a query over events e to a venue's events that started between from
+// and to and have ended, so their sales are final. Cancelled and sandbox events are left out.
+func heatmapEvents(ctx context.Context, venueID uint, from, to time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Scopes(tenantScope(ctx, "e"), excludeSandbox("e")).
+			Where("e.venue_id = ? AND e.status <> ? AND e.deleted_at IS NULL", venueID, constants.EventStatusCancelled).
+			Where("e.start_time >= ? AND e.start_time < ? AND e.end_time < ?", from, to, time.Now())
+	}
+}
+
+// GetSeatHeat counts, per seat position of a venue, the past events the seat was on sale for
+// and sold at, with the average seat price it sold at, and the number of events covered. Seats
+// still held back from sale when the event ended weren't on sale; comps aren't sales. Sales are
+// read with the archived bookings.
+func (r *analyticsRepository) GetSeatHeat(ctx context.Context, venueID uint, from, to time.Time) ([]entities.SeatHeat, int64, error) {
+	var seats []entities.SeatHeat
+	var events int64
+
+	db := conn(ctx, r.db)
+	if err := db.Table("events e").Scopes(heatmapEvents(ctx, venueID, from, to)).Count(&events).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to count venue events", err)
+	}
+	if events == 0 {
+		return nil, 0, nil
+	}
+
+	// Bookings made before seat prices were recorded fall back to what was paid. Free tickets
+	// count as sold but have no price, so they don't pull the average price down.
+	sales := bookingHistory(db.Session(&gorm.Session{NewDB: true})).
+		Select("bookings.seat_id, CASE WHEN bookings.payment_status = ? THEN NULL WHEN bookings.seat_price > 0 THEN bookings.seat_price ELSE bookings.total_amount + bookings.discount_amount END as price", constants.PaymentStatusFree).
+		Where("bookings.status = ? AND bookings.deleted_at IS NULL AND bookings.channel <> ?", constants.BookingStatusConfirmed, constants.SalesChannelComp)
+
+	if err := db.Table("seats s").
+		Select(`
+			s."row",
+			s."column",
+			MODE() WITHIN GROUP (ORDER BY s.seat_type) as seat_type,
+			COUNT(*) as offered,
+			COUNT(b.seat_id) as sold,
+			COALESCE(ROUND(AVG(b.price)), 0) as avg_price
+		`).
+		Joins("JOIN events e ON s.event_id = e.id").
+		Joins("LEFT JOIN (?) b ON b.seat_id = s.id", sales).
+		Scopes(heatmapEvents(ctx, venueID, from, to)).
+		Where("s.is_held = false AND s.is_oversell = false").
+		Group(`s."row", s."column"`).
+		Order(`s."row", s."column"`).
+		Scan(&seats).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to fetch seat heatmap", err)
+	}
+	return seats, events, nil
+}
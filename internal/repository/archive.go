@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Archive tables have the same columns as their live tables, so archived rows load into the
+// same entities. They have no foreign keys or uniqueness constraints beyond the primary key.
+const (
+	bookingsArchiveTable       = "bookings_archive"
+	bookingIntentsArchiveTable = "booking_intents_archive"
+)
+
+var archiveTables = []struct {
+	live    string
+	archive string
+	model   interface{}
+}{
+	{"bookings", bookingsArchiveTable, &entities.Booking{}},
+	{"booking_intents", bookingIntentsArchiveTable, &entities.BookingIntent{}},
+}
+
+type ArchiveRepository struct {
+	db *gorm.DB
+}
+
+func NewArchiveRepository(db *gorm.DB) *ArchiveRepository {
+	return &ArchiveRepository{db: db}
+}
+
+// EnsureTables creates the archive tables and adds any columns their live tables gained since.
+// It runs after AutoMigrate so the live tables are current.
+func (s *ArchiveRepository) EnsureTables(ctx context.Context) error {
+	db := conn(ctx, s.db)
+	for _, t := range archiveTables {
+		if err := db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (LIKE %s, PRIMARY KEY (id))`, t.archive, t.live)).Error; err != nil {
+			return err
+		}
+
+		var missing []struct {
+			Name string
+			Type string
+		}
+		if err := db.Raw(`SELECT a.attname AS name, format_type(a.atttypid, a.atttypmod) AS type
+			FROM pg_attribute a
+			WHERE a.attrelid = ?::regclass AND a.attnum > 0 AND NOT a.attisdropped
+			AND NOT EXISTS (
+				SELECT 1 FROM pg_attribute b
+				WHERE b.attrelid = ?::regclass AND b.attname = a.attname AND NOT b.attisdropped
+			)
+			ORDER BY a.attnum`, t.live, t.archive).Scan(&missing).Error; err != nil {
+			return err
+		}
+		for _, column := range missing {
+			if err := db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %q %s`, t.archive, column.Name, column.Type)).Error; err != nil {
+				return err
+			}
+		}
+
+		// History reads look archived rows up by user and event
+		for _, index := range []string{"user_id, created_at", "event_id"} {
+			name := fmt.Sprintf("idx_%s_%s", t.archive, strings.SplitN(index, ",", 2)[0])
+			if err := db.Exec(fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (%s)`, name, t.archive, index)).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ListArchivableEvents returns completed events that ended before the cutoff and whose
+// bookings haven't been archived yet, oldest first
+func (s *ArchiveRepository) ListArchivableEvents(ctx context.Context, endedBefore time.Time) ([]uint, error) {
+	var eventIDs []uint
+	if err := conn(ctx, s.db).Model(&entities.Event{}).
+		Where("status = ? AND end_time < ? AND archived_at IS NULL", constants.EventStatusCompleted, endedBefore).
+		Order("end_time ASC").
+		Pluck("id", &eventIDs).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch archivable events", err)
+	}
+	return eventIDs, nil
+}
+
+// ArchiveEvent moves an event's bookings and booking intents into the archive tables and
+// marks the event archived, all in one transaction
+func (s *ArchiveRepository) ArchiveEvent(ctx context.Context, eventID uint) (bookings, intents int64, err error) {
+	err = conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		moved := make([]int64, len(archiveTables))
+		for i, t := range archiveTables {
+			columns, err := columnList(tx, t.model)
+			if err != nil {
+				return err
+			}
+			result := tx.Exec(fmt.Sprintf(
+				`WITH moved AS (DELETE FROM %s WHERE event_id = ? RETURNING %s) INSERT INTO %s (%s) SELECT %s FROM moved`,
+				t.live, columns, t.archive, columns, columns), eventID)
+			if result.Error != nil {
+				return result.Error
+			}
+			moved[i] = result.RowsAffected
+		}
+		bookings, intents = moved[0], moved[1]
+
+		return tx.Model(&entities.Event{}).Where("id = ?", eventID).Update("archived_at", time.Now()).Error
+	})
+	if err != nil {
+		return 0, 0, errors.NewInternalError("Failed to archive event bookings", err)
+	}
+	return bookings, intents, nil
+}
+
+// columnList is the quoted, comma separated list of a model's columns
+func columnList(db *gorm.DB, model interface{}) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return "", err
+	}
+	columns := make([]string, len(stmt.Schema.DBNames))
+	for i, name := range stmt.Schema.DBNames {
+		columns[i] = stmt.Quote(name)
+	}
+	return strings.Join(columns, ", "), nil
+}
+
+// bookingHistory queries live and archived bookings together. The union is aliased as
+// bookings, so conditions, soft deletes and preloads work as they do on the live table.
+func bookingHistory(db *gorm.DB) *gorm.DB {
+	columns, err := columnList(db, &entities.Booking{})
+	if err != nil {
+		db.AddError(err)
+		return db
+	}
+	history := db.Session(&gorm.Session{NewDB: true}).
+		Raw(fmt.Sprintf("SELECT %[1]s FROM bookings UNION ALL SELECT %[1]s FROM %[2]s", columns, bookingsArchiveTable))
+	return db.Table("(?) AS bookings", history)
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ArtifactRepository struct {
+	db *gorm.DB
+}
+
+func NewArtifactRepository(db *gorm.DB) *ArtifactRepository {
+	return &ArtifactRepository{db: db}
+}
+
+func (s *ArtifactRepository) Create(ctx context.Context, artifact *entities.Artifact) error {
+	if err := conn(ctx, s.db).Create(artifact).Error; err != nil {
+		return errors.NewInternalError("Failed to record artifact", err)
+	}
+	return nil
+}
+
+func (s *ArtifactRepository) GetByID(ctx context.Context, id uint) (*entities.Artifact, error) {
+	var artifact entities.Artifact
+	if err := conn(ctx, s.db).First(&artifact, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Artifact not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch artifact", err)
+	}
+	return &artifact, nil
+}
+
+// List returns artifacts, newest first, optionally filtered by kind
+func (s *ArtifactRepository) List(ctx context.Context, kind string, limit, offset int) ([]entities.Artifact, int64, error) {
+	var artifacts []entities.Artifact
+	var total int64
+
+	query := conn(ctx, s.db).Model(&entities.Artifact{})
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to count artifacts", err)
+	}
+
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&artifacts).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to fetch artifacts", err)
+	}
+
+	return artifacts, total, nil
+}
+
+// ListExpired returns up to limit artifacts that expired before the given time, oldest first
+func (s *ArtifactRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]entities.Artifact, error) {
+	var artifacts []entities.Artifact
+	if err := conn(ctx, s.db).
+		Where("expires_at IS NOT NULL AND expires_at <= ?", before).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&artifacts).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch expired artifacts", err)
+	}
+	return artifacts, nil
+}
+
+func (s *ArtifactRepository) Delete(ctx context.Context, id uint) error {
+	if err := conn(ctx, s.db).Delete(&entities.Artifact{}, id).Error; err != nil {
+		return errors.NewInternalError("Failed to delete artifact", err)
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AttendanceRepository struct {
+	db *gorm.DB
+}
+
+func NewAttendanceRepository(db *gorm.DB) *AttendanceRepository {
+	return &AttendanceRepository{db: db}
+}
+
+// CheckInBooking marks a confirmed booking as checked in at the venue
+func (s *AttendanceRepository) CheckInBooking(ctx context.Context, bookingID uint) (*entities.Booking, error) {
+	var booking entities.Booking
+
+	if err := conn(ctx, s.db).Scopes(tenantScope(ctx, "bookings")).Preload("Event").Preload("Seat").
+		First(&booking, bookingID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Booking not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch booking", err)
+	}
+
+	if booking.NoShowReleasedAt != nil {
+		return nil, errors.NewBadRequestError(constants.ErrBookingReleasedNoShow, nil)
+	}
+	if booking.Status != constants.BookingStatusConfirmed {
+		return nil, errors.NewBadRequestError("Only confirmed bookings can be checked in", nil)
+	}
+
+	if booking.TicketRevokedAt != nil {
+		return nil, errors.NewBadRequestError("Ticket has been revoked", nil)
+	}
+
+	if booking.Event.EndTime.Before(time.Now()) {
+		return nil, errors.NewBadRequestError("Event has already ended", nil)
+	}
+
+	// Conditional update so concurrent scans of the same ticket can't both succeed
+	now := time.Now()
+	result := conn(ctx, s.db).Model(&entities.Booking{}).
+		Where("id = ? AND checked_in_at IS NULL", booking.ID).
+		Update("checked_in_at", now)
+	if result.Error != nil {
+		return nil, errors.NewInternalError("Failed to check in booking", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.NewConflictError("Booking is already checked in", nil)
+	}
+
+	booking.CheckedInAt = &now
+	return &booking, nil
+}
+
+// RecordOfflineCheckIn records a check-in a scanner made at scannedAt, by the scanner device if
+// registered. It returns the booking with its check-in time and whether this scan recorded it;
+// a booking already checked in keeps its earlier time.
+func (s *AttendanceRepository) RecordOfflineCheckIn(ctx context.Context, eventID, bookingID uint, scannedAt time.Time, deviceID *uint) (*entities.Booking, bool, error) {
+	var booking entities.Booking
+
+	if err := conn(ctx, s.db).Scopes(tenantScope(ctx, "bookings")).
+		Where("event_id = ?", eventID).First(&booking, bookingID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, false, errors.NewNotFoundError("Booking not found", errors.ErrRecordNotFound)
+		}
+		return nil, false, errors.NewInternalError("Failed to fetch booking", err)
+	}
+
+	if booking.NoShowReleasedAt != nil {
+		return nil, false, errors.NewBadRequestError(constants.ErrBookingReleasedNoShow, nil)
+	}
+	if booking.Status != constants.BookingStatusConfirmed {
+		return nil, false, errors.NewBadRequestError("Only confirmed bookings can be checked in", nil)
+	}
+
+	if booking.TicketRevokedAt != nil && booking.TicketRevokedAt.Before(scannedAt) {
+		return nil, false, errors.NewBadRequestError("Ticket has been revoked", nil)
+	}
+
+	// Scans synced after the event completed also clear the no-show flag
+	result := conn(ctx, s.db).Model(&entities.Booking{}).
+		Where("id = ? AND checked_in_at IS NULL", booking.ID).
+		Updates(map[string]interface{}{"checked_in_at": scannedAt, "check_in_device_id": deviceID, "no_show": false})
+	if result.Error != nil {
+		return nil, false, errors.NewInternalError("Failed to check in booking", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		// Checked in by another scan, report when
+		if err := conn(ctx, s.db).First(&booking, booking.ID).Error; err != nil {
+			return nil, false, errors.NewInternalError("Failed to fetch booking", err)
+		}
+		return &booking, false, nil
+	}
+
+	booking.CheckedInAt = &scannedAt
+	booking.CheckInDeviceID = deviceID
+	booking.NoShow = false
+	return &booking, true, nil
+}
+
+// GetEventsPendingFollowUp returns events that have ended but have not been followed up yet
+func (s *AttendanceRepository) GetEventsPendingFollowUp(ctx context.Context, now time.Time) ([]entities.Event, error) {
+	var events []entities.Event
+
+	if err := conn(ctx, s.db).
+		Where("status IN ? AND end_time < ? AND follow_up_at IS NULL AND sandbox = false",
+			[]string{constants.EventStatusActive, constants.EventStatusSoldOut}, now).
+		Find(&events).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch ended events", err)
+	}
+
+	return events, nil
+}
+
+// GetEventsDueNoShowRelease returns running door-sale events that release the seats of
+// no-shows, whose doors opened at least their no-show cutoff ago and whose seats haven't been
+// released yet. The caller checks that online sales have closed too.
+func (s *AttendanceRepository) GetEventsDueNoShowRelease(ctx context.Context, now time.Time) ([]entities.Event, error) {
+	var events []entities.Event
+
+	if err := conn(ctx, s.db).
+		Where("status IN ? AND door_sales = true AND no_show_release_minutes > 0 AND no_show_released_at IS NULL AND end_time > ?",
+			[]string{constants.EventStatusActive, constants.EventStatusSoldOut}, now).
+		Where("start_time - make_interval(mins => doors_open_minutes_before_start) + make_interval(mins => no_show_release_minutes) <= ?", now).
+		Find(&events).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch events due a no-show release", err)
+	}
+
+	return events, nil
+}
+
+// ReleaseNoShows puts the seats of an event's confirmed bookings that weren't checked in back on
+// sale at the door. Released bookings keep their payment and can no longer be refunded, unless
+// refund is set: then they are cancelled and refunded like a cancellation. It returns the
+// released bookings, or false if another run already released the event's no-shows.
+func (s *AttendanceRepository) ReleaseNoShows(ctx context.Context, eventID uint, refund bool, now time.Time) ([]entities.Booking, bool, error) {
+	var released []entities.Booking
+	claimed := false
+
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.Event{}).
+			Where("id = ? AND no_show_released_at IS NULL", eventID).
+			Update("no_show_released_at", now)
+		if result.Error != nil {
+			return errors.NewInternalError("Failed to claim no-show release", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		claimed = true
+
+		// Oversell places aren't sold at the door, so their no-shows keep them
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("event_id = ? AND status = ? AND checked_in_at IS NULL AND channel <> ?",
+				eventID, constants.BookingStatusConfirmed, constants.SalesChannelOversell).
+			Find(&released).Error; err != nil {
+			return errors.NewInternalError("Failed to fetch no-shows", err)
+		}
+		if len(released) == 0 {
+			return nil
+		}
+
+		bookingIDs := make([]uint, len(released))
+		seatIDs := make([]uint, len(released))
+		for i := range released {
+			bookingIDs[i] = released[i].ID
+			seatIDs[i] = released[i].SeatID
+			released[i].NoShowReleasedAt = &now
+			released[i].NoShow = true
+		}
+
+		if refund {
+			for i := range released {
+				if err := cancelConfirmedBooking(tx, &released[i]); err != nil {
+					return err
+				}
+				released[i].Status = constants.BookingStatusCancelled
+			}
+			if err := tx.Model(&entities.Booking{}).Where("id IN ?", bookingIDs).
+				Updates(map[string]interface{}{"no_show_released_at": now, "no_show": true}).Error; err != nil {
+				return errors.NewInternalError("Failed to release no-shows", err)
+			}
+			return nil
+		}
+
+		if err := tx.Model(&entities.Booking{}).Where("id IN ?", bookingIDs).
+			Updates(map[string]interface{}{
+				"status":              constants.BookingStatusReleased,
+				"no_show_released_at": now,
+				"no_show":             true,
+			}).Error; err != nil {
+			return errors.NewInternalError("Failed to release no-shows", err)
+		}
+		for i := range released {
+			released[i].Status = constants.BookingStatusReleased
+		}
+		if err := tx.Model(&entities.Seat{}).Where("id IN ?", seatIDs).
+			Update("is_available", true).Error; err != nil {
+			return errors.NewInternalError("Failed to update seat availability", err)
+		}
+		if err := tx.Model(&entities.Event{}).Where("id = ?", eventID).
+			UpdateColumn("available_seats", gorm.Expr("available_seats + ?", len(released))).Error; err != nil {
+			return errors.NewInternalError("Failed to update event capacity", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return released, claimed, nil
+}
+
+// CompleteEvent marks the event completed and flags confirmed bookings without check-in as no-shows.
+// It returns the number of no-shows, or false if another run already completed the event.
+func (s *AttendanceRepository) CompleteEvent(ctx context.Context, eventID uint) (int64, bool, error) {
+	var noShows int64
+	completed := false
+
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.Event{}).
+			Where("id = ? AND follow_up_at IS NULL", eventID).
+			Updates(map[string]interface{}{
+				"status":       constants.EventStatusCompleted,
+				"follow_up_at": time.Now(),
+			})
+		if result.Error != nil {
+			return errors.NewInternalError("Failed to complete event", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		completed = true
+
+		result = tx.Model(&entities.Booking{}).
+			Where("event_id = ? AND status = ? AND checked_in_at IS NULL",
+				eventID, constants.BookingStatusConfirmed).
+			Update("no_show", true)
+		if result.Error != nil {
+			return errors.NewInternalError("Failed to mark no-shows", result.Error)
+		}
+		noShows = result.RowsAffected
+
+		return nil
+	})
+
+	return noShows, completed, err
+}
+
+// GetCheckedInBookings returns the bookings of attendees who checked in to an event
+func (s *AttendanceRepository) GetCheckedInBookings(ctx context.Context, eventID uint) ([]entities.Booking, error) {
+	var bookings []entities.Booking
+
+	if err := conn(ctx, s.db).
+		Preload("User").
+		Where("event_id = ? AND status = ? AND checked_in_at IS NOT NULL",
+			eventID, constants.BookingStatusConfirmed).
+		Find(&bookings).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch attendees", err)
+	}
+
+	return bookings, nil
+}
+
+// SaveReview records the user's rating of an event they checked in to, replacing an earlier
+// review of the same booking
+func (s *AttendanceRepository) SaveReview(ctx context.Context, userID, bookingID uint, rating int, comment string) (*entities.EventReview, error) {
+	var booking entities.Booking
+
+	if err := conn(ctx, s.db).Where("id = ? AND user_id = ?", bookingID, userID).
+		First(&booking).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Booking not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch booking", err)
+	}
+
+	if booking.Status != constants.BookingStatusConfirmed || booking.CheckedInAt == nil {
+		return nil, errors.NewBadRequestError("Only attended bookings can be reviewed", nil)
+	}
+
+	review := &entities.EventReview{
+		TenantID:  booking.TenantID,
+		EventID:   booking.EventID,
+		BookingID: booking.ID,
+		UserID:    userID,
+		Rating:    rating,
+		Comment:   comment,
+	}
+	if err := conn(ctx, s.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "booking_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rating", "comment", "updated_at"}),
+	}).Create(review).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to save review", err)
+	}
+
+	return review, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var idNumberPattern = regexp.MustCompile(`^[A-Za-z0-9-]{4,50}$`)
+
+// collectAttendeeDetails validates the attendee details the event requires and stores them
+// on the booking. Details an event doesn't ask for are not kept.
+func collectAttendeeDetails(tx *gorm.DB, eventID uint, attendee entities.AttendeeDetails, booking *entities.Booking) error {
+	var event entities.Event
+	if err := tx.Select("id", "start_time", "minimum_age", "require_full_name", "require_id_number").
+		First(&event, eventID).Error; err != nil {
+		return errors.NewInternalError("Failed to fetch event requirements", err)
+	}
+
+	if event.RequireFullName {
+		name := strings.Join(strings.Fields(attendee.FullName), " ")
+		if len(name) < 2 || len(name) > 200 {
+			return errors.NewBadRequestError(constants.ErrAttendeeNameRequired, nil)
+		}
+		booking.AttendeeName = name
+	}
+
+	if event.RequireIDNumber {
+		idNumber := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(attendee.IDNumber), " ", ""))
+		if !idNumberPattern.MatchString(idNumber) {
+			return errors.NewBadRequestError(constants.ErrAttendeeIDRequired, nil)
+		}
+		booking.AttendeeIDNumber = idNumber
+	}
+
+	if event.MinimumAge > 0 {
+		if attendee.DateOfBirth == nil {
+			return errors.NewBadRequestError(constants.ErrAttendeeBirthDateRequired, nil)
+		}
+		if ageOn(*attendee.DateOfBirth, event.StartTime) < event.MinimumAge {
+			return errors.NewBadRequestError(fmt.Sprintf("attendees must be at least %d years old", event.MinimumAge), nil)
+		}
+		booking.AttendeeBirthDate = attendee.DateOfBirth.Format("2006-01-02")
+	}
+
+	return nil
+}
+
+// ageOn returns the age in whole years of someone born on birthDate at the given date
+func ageOn(birthDate, date time.Time) int {
+	age := date.Year() - birthDate.Year()
+	if date.Month() < birthDate.Month() || (date.Month() == birthDate.Month() && date.Day() < birthDate.Day()) {
+		age--
+	}
+	return age
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"api/internal/entities"
+	redisconn "api/internal/redis"
+	"api/internal/tenant"
+	"api/pkg/errors"
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Kinds of cached autocomplete suggestions
+const (
+	autocompleteVenues = "venues"
+	autocompleteCities = "cities"
+)
+
+// trigramMinLength is the shortest query trigram indexes can narrow down; shorter queries only
+// match prefixes
+const trigramMinLength = 3
+
+// AutocompleteRepository suggests venues and cities by name prefix and by trigram similarity.
+// Suggestions are cached in Redis for a query for ttl; like the event cache it is best
+// effort, falling back to the database on misses and Redis errors.
+type AutocompleteRepository struct {
+	db    *gorm.DB
+	redis redis.UniversalClient
+	ttl   time.Duration
+}
+
+func NewAutocompleteRepository(db *gorm.DB, redisClient redis.UniversalClient, ttl time.Duration) *AutocompleteRepository {
+	return &AutocompleteRepository{db: db, redis: redisClient, ttl: ttl}
+}
+
+// EnsureIndexes creates the pg_trgm extension and the indexes autocomplete queries use: btree
+// indexes on the lowercased names for prefixes, and trigram indexes for matches within names
+func (s *AutocompleteRepository) EnsureIndexes(ctx context.Context) error {
+	db := conn(ctx, s.db)
+	statements := []string{
+		`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+		`CREATE INDEX IF NOT EXISTS idx_venues_name_prefix ON venues (lower(name) text_pattern_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_venues_city_prefix ON venues (lower(city) text_pattern_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_venues_name_trgm ON venues USING gin (name gin_trgm_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_venues_city_trgm ON venues USING gin (city gin_trgm_ops)`,
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SuggestVenues returns up to limit venues whose name matches query, which is lowercase:
+// names starting with it first, then names containing it, most similar first
+func (s *AutocompleteRepository) SuggestVenues(ctx context.Context, query string, limit int) ([]entities.VenueSuggestion, error) {
+	var suggestions []entities.VenueSuggestion
+	if s.cached(ctx, autocompleteVenues, query, limit, &suggestions) {
+		return suggestions, nil
+	}
+
+	if err := conn(ctx, s.db).Model(&entities.Venue{}).Scopes(tenantScope(ctx, "venues"), matchScope("name", query)).
+		Select("id", "name", "city", "state", "country").
+		Order(rankBy("lower(name) LIKE ? DESC, similarity(name, ?) DESC, name ASC, id ASC", likePrefix(query), query)).
+		Limit(limit).
+		Scan(&suggestions).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to suggest venues", err)
+	}
+
+	s.cache(ctx, autocompleteVenues, query, limit, suggestions)
+	return suggestions, nil
+}
+
+// SuggestCities returns up to limit cities with venues whose city matches query, which is
+// lowercase: cities starting with it first, then those with the most venues
+func (s *AutocompleteRepository) SuggestCities(ctx context.Context, query string, limit int) ([]entities.CitySuggestion, error) {
+	var suggestions []entities.CitySuggestion
+	if s.cached(ctx, autocompleteCities, query, limit, &suggestions) {
+		return suggestions, nil
+	}
+
+	if err := conn(ctx, s.db).Model(&entities.Venue{}).Scopes(tenantScope(ctx, "venues"), matchScope("city", query)).
+		Select("city, state, country, COUNT(*) AS venues").
+		Group("city, state, country").
+		Order(rankBy("lower(city) LIKE ? DESC, COUNT(*) DESC, similarity(city, ?) DESC, city ASC", likePrefix(query), query)).
+		Limit(limit).
+		Scan(&suggestions).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to suggest cities", err)
+	}
+
+	s.cache(ctx, autocompleteCities, query, limit, suggestions)
+	return suggestions, nil
+}
+
+// matchScope matches rows whose column starts with query, or contains it once query is long
+// enough for the trigram index
+func matchScope(column, query string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		prefix := "lower(" + column + ") LIKE ?"
+		if len([]rune(query)) < trigramMinLength {
+			return db.Where(prefix, likePrefix(query))
+		}
+		return db.Where(prefix+" OR "+column+" ILIKE ?", likePrefix(query), "%"+escapeLike(query)+"%")
+	}
+}
+
+// rankBy orders by an expression with the query as parameters
+func rankBy(sql string, vars ...interface{}) clause.OrderBy {
+	return clause.OrderBy{Expression: clause.Expr{SQL: sql, Vars: vars, WithoutParentheses: true}}
+}
+
+func likePrefix(query string) string {
+	return escapeLike(query) + "%"
+}
+
+// escapeLike makes LIKE match the wildcards of s literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// cacheable reports whether suggestions for ctx are cached: tenant-scoped reads only see their
+// tenant's venues, so they always go to the database
+func (s *AutocompleteRepository) cacheable(ctx context.Context) bool {
+	_, scoped := tenant.FromContext(ctx)
+	return s.ttl > 0 && !scoped
+}
+
+func (s *AutocompleteRepository) cached(ctx context.Context, kind, query string, limit int, dest interface{}) bool {
+	if !s.cacheable(ctx) {
+		return false
+	}
+	data, err := s.redis.Get(ctx, redisconn.AutocompleteKey(kind, query, limit)).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			warnLockError("read autocomplete cache", err)
+		}
+		return false
+	}
+	return json.Unmarshal(data, dest) == nil
+}
+
+func (s *AutocompleteRepository) cache(ctx context.Context, kind, query string, limit int, suggestions interface{}) {
+	if !s.cacheable(ctx) {
+		return
+	}
+	data, err := json.Marshal(suggestions)
+	if err != nil {
+		return
+	}
+	if err := s.redis.Set(ctx, redisconn.AutocompleteKey(kind, query, limit), data, s.ttl).Err(); err != nil {
+		warnLockError("write autocomplete cache", err)
+	}
+}
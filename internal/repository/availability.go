@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	redisconn "api/internal/redis"
+	"api/pkg/errors"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// availabilityCounterTTL lets the counters of events that ended drop out of Redis; every
+// reconciliation extends the counters of events still on sale
+const availabilityCounterTTL = 24 * time.Hour
+
+// adjustAvailabilityScript moves an event's counter by ARGV[1], never below zero. A counter
+// that doesn't exist is left missing, since it is seeded from the database, and -1 returned.
+const adjustAvailabilityScript = `
+local current = redis.call('GET', KEYS[1])
+if not current then
+	return -1
+end
+local value = tonumber(current) + tonumber(ARGV[1])
+if value < 0 then
+	value = 0
+end
+redis.call('SET', KEYS[1], value, 'KEEPTTL')
+return value
+`
+
+// AvailabilityRepository keeps a Redis counter of each event's seats still for sale, so
+// listings don't read events.available_seats, the row every confirmation updates. The column
+// stays authoritative: counters are seeded and reconciled from it, and adjusting them is best
+// effort.
+type AvailabilityRepository struct {
+	db    *gorm.DB
+	redis redis.UniversalClient
+}
+
+func NewAvailabilityRepository(db *gorm.DB, redisClient redis.UniversalClient) *AvailabilityRepository {
+	return &AvailabilityRepository{db: db, redis: redisClient}
+}
+
+// Adjust moves an event's counter by delta, e.g. -1 when a seat is sold. A missing counter
+// stays missing until it is seeded.
+func (r *AvailabilityRepository) Adjust(ctx context.Context, eventID uint, delta int) {
+	if err := r.redis.Eval(ctx, adjustAvailabilityScript, []string{redisconn.AvailabilityKey(eventID)}, delta).Err(); err != nil {
+		warnLockError("adjust availability counter", err)
+	}
+}
+
+// GetEventAvailability returns an event's name and available_seats from the database
+func (r *AvailabilityRepository) GetEventAvailability(ctx context.Context, eventID uint) (*entities.Event, error) {
+	var event entities.Event
+	if err := conn(ctx, r.db).Select("id", "name", "available_seats").
+		First(&event, eventID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Event not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch event availability", err)
+	}
+	return &event, nil
+}
+
+// Get returns the counters of the events that have one
+func (r *AvailabilityRepository) Get(ctx context.Context, eventIDs []uint) (map[uint]int64, error) {
+	// Each event's counter is in its own slot, so the GETs are pipelined rather than one MGET
+	pipe := r.redis.Pipeline()
+	cmds := make([]*redis.StringCmd, len(eventIDs))
+	for i, eventID := range eventIDs {
+		cmds[i] = pipe.Get(ctx, redisconn.AvailabilityKey(eventID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get availability counters: %w", err)
+	}
+
+	counts := make(map[uint]int64, len(eventIDs))
+	for i, cmd := range cmds {
+		if count, err := cmd.Int64(); err == nil {
+			counts[eventIDs[i]] = count
+		}
+	}
+	return counts, nil
+}
+
+// Seed creates the counters of events that have none from the given counts, leaving existing
+// counters as they are
+func (r *AvailabilityRepository) Seed(ctx context.Context, counts map[uint]int64) error {
+	pipe := r.redis.Pipeline()
+	for eventID, count := range counts {
+		pipe.SetNX(ctx, redisconn.AvailabilityKey(eventID), count, availabilityCounterTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to seed availability counters: %w", err)
+	}
+	return nil
+}
+
+// Reconcile overwrites the counters of every event on sale with events.available_seats and
+// returns the events whose counter had drifted, with their counter's value. Events with no
+// counter yet are seeded and not reported.
+func (r *AvailabilityRepository) Reconcile(ctx context.Context) (map[uint]int64, error) {
+	var events []entities.Event
+	if err := conn(ctx, r.db).Select("id", "available_seats").
+		Where("status IN ? AND end_time > ?", []string{constants.EventStatusActive, constants.EventStatusSoldOut}, time.Now()).
+		Find(&events).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch event availability", err)
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	pipe := r.redis.Pipeline()
+	previous := make([]*redis.StatusCmd, len(events))
+	for i, event := range events {
+		previous[i] = pipe.SetArgs(ctx, redisconn.AvailabilityKey(event.ID), event.AvailableSeats,
+			redis.SetArgs{Get: true, TTL: availabilityCounterTTL})
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to reconcile availability counters: %w", err)
+	}
+
+	drifted := make(map[uint]int64)
+	for i, event := range events {
+		value, err := previous[i].Result()
+		if err != nil {
+			continue
+		}
+		if count, err := strconv.ParseInt(value, 10, 64); err == nil && count != int64(event.AvailableSeats) {
+			drifted[event.ID] = count
+		}
+	}
+	return drifted, nil
+}
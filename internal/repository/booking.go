@@ -3,482 +3,1028 @@ package repository
 import (
 	"api/constants"
 	"api/internal/entities"
+	"api/internal/expand"
 	"api/pkg/errors"
+	"api/pkg/money"
 	"context"
-	"fmt"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-type BookingRepository struct {
-	db                 *gorm.DB
-	seatLockRepository *SeatLockRepository
+// BookingRepository stores booking intents and bookings. It only reads and writes the
+// database: the booking workflow, its expiry rules and the Redis seat locks belong to the
+// booking service.
+type BookingRepository interface {
+	GetSeat(ctx context.Context, seatID uint) (*entities.Seat, error)
+	CheckSaleEligibility(ctx context.Context, seat *entities.Seat, userID uint, presaleCode string) (*entities.PresaleCode, error)
+	CreateBookingIntent(ctx context.Context, intent *entities.BookingIntent, maxPending int) error
+	LockSeatInDatabase(ctx context.Context, seatID, userID uint, lockedAt, expiresAt time.Time) error
+	GetBookingIntent(ctx context.Context, bookingIntentID uint, userID uint) (*entities.BookingIntent, error)
+	GetPendingIntent(ctx context.Context, bookingIntentID uint) (*entities.BookingIntent, error)
+	GetUserPendingIntent(ctx context.Context, bookingIntentID uint, userID uint) (*entities.BookingIntent, error)
+	GetIntentDetails(ctx context.Context, bookingIntentID uint) (*entities.BookingIntent, error)
+	GetPendingIntents(ctx context.Context, expiringAfter time.Time) ([]entities.BookingIntent, error)
+	GetLatestSeatIntent(ctx context.Context, seatID uint) (*entities.BookingIntent, error)
+	GetDatabaseLockedSeats(ctx context.Context) ([]entities.Seat, error)
+	ReleaseDatabaseLock(ctx context.Context, seat *entities.Seat) (bool, error)
+	GetUpcomingEventIDs(ctx context.Context, now time.Time) ([]uint, error)
+	ConfirmBooking(ctx context.Context, bookingIntentID uint, payment entities.PaymentDetails, attendee entities.AttendeeDetails, options entities.ConfirmOptions) (*entities.Booking, []entities.BookingIntent, error)
+	CancelBookingIntent(ctx context.Context, bookingIntentID uint, userID uint) (*entities.BookingIntent, error)
+	CancelUserIntents(ctx context.Context, userID uint) ([]entities.BookingIntent, error)
+	ExpireBookingIntent(ctx context.Context, bookingIntentID uint) (*entities.BookingIntent, error)
+	ExpireIntents(ctx context.Context, before time.Time) ([]entities.BookingIntent, error)
+	ExpireEventIntents(ctx context.Context, eventID uint, createdBefore time.Time) ([]entities.BookingIntent, error)
+	SaveResumeToken(ctx context.Context, bookingIntentID uint, tokenHash string, expiresAt time.Time) error
+	GetIntentByResumeToken(ctx context.Context, userID uint, tokenHash string) (*entities.BookingIntent, error)
+	RedeemResumeToken(ctx context.Context, bookingIntentID uint, tokenHash string) (bool, error)
+	StartPaymentAttempt(ctx context.Context, bookingIntentID uint, reference, failureReason string, maxAttempts int) (*entities.BookingIntent, error)
+	GetConfirmedBooking(ctx context.Context, bookingID uint, userID uint) (*entities.Booking, error)
+	CancelBooking(ctx context.Context, bookingID uint) ([]entities.Booking, error)
+	GetUserBookings(ctx context.Context, userID uint, filter entities.BookingFilter, limit, offset int) ([]entities.Booking, int64, error)
+	GetUpcomingBookings(ctx context.Context, userID uint, now time.Time, limit int) ([]entities.Booking, error)
+	GetBookingByID(ctx context.Context, bookingID, userID uint) (*entities.Booking, error)
+	BackfillLockExpiry(ctx context.Context) error
 }
 
-func NewBookingRepository(db *gorm.DB, seatLockRepository *SeatLockRepository) *BookingRepository {
-	return &BookingRepository{
-		db:                 db,
-		seatLockRepository: seatLockRepository,
-	}
+type bookingRepository struct {
+	db *gorm.DB
 }
 
-// CreateBookingIntent creates a booking intent using Redis-first locking approach
-func (s *BookingRepository) CreateBookingIntent(ctx context.Context, userID, seatID uint) (*entities.BookingIntent, error) {
-	// Step 1: Check Redis for existing lock first (fast path)
-	isLocked, _, err := s.seatLockRepository.IsLocked(ctx, seatID)
-	if err != nil {
-		// Redis is down, fall back to database-only approach
-		return s.createBookingIntentDBFallback(ctx, userID, seatID)
-	}
-
-	if isLocked {
-		// Check if it's locked by the same user
-		isLockedByUser, _, err := s.seatLockRepository.IsLockedByUser(ctx, seatID, userID)
-		if err != nil {
-			// Redis error, fall back to database
-			return s.createBookingIntentDBFallback(ctx, userID, seatID)
-		}
-
-		if isLockedByUser {
-			// User already has a lock on this seat
-			return nil, errors.NewConflictError("You already have an active booking intent for this seat", nil)
-		} else {
-			// Seat is locked by another user
-			return nil, errors.NewConflictError(constants.ErrSeatAlreadyLocked, nil)
-		}
+func NewBookingRepository(db *gorm.DB) BookingRepository {
+	return &bookingRepository{
+		db: db,
 	}
+}
 
-	// Validate seat availability in database (without transaction)
+// GetSeat returns a seat with its event
+func (s *bookingRepository) GetSeat(ctx context.Context, seatID uint) (*entities.Seat, error) {
 	var seat entities.Seat
-	if err := s.db.WithContext(ctx).Preload("Event").First(&seat, seatID).Error; err != nil {
+	if err := conn(ctx, s.db).Scopes(tenantScopeVia(ctx, "event_id", "events")).Preload("Event").First(&seat, seatID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.NewNotFoundError("Seat not found", errors.ErrRecordNotFound)
 		}
 		return nil, errors.NewInternalError("Failed to fetch seat", err)
 	}
+	return &seat, nil
+}
 
-	// Check if seat is available
-	if !seat.IsAvailable {
-		return nil, errors.NewBadRequestError(constants.ErrSeatNotAvailable, nil)
+// CheckSaleEligibility checks the rules that depend on other records: before the general
+// on-sale only early-access members and presale code holders may book, and companion seats
+// are reserved for whoever holds the linked accessible seat. It returns the presale code the
+// booking is made with, if any.
+func (s *bookingRepository) CheckSaleEligibility(ctx context.Context, seat *entities.Seat, userID uint, presaleCode string) (*entities.PresaleCode, error) {
+	db := conn(ctx, s.db)
+	code, err := checkSaleWindow(db, &seat.Event, userID, presaleCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCompanionSeat(db, seat, userID); err != nil {
+		return nil, err
 	}
+	return code, nil
+}
 
-	// Check if seat is locked in database and if the lock has expired
-	if seat.IsLocked && seat.LockedAt != nil {
-		lockDuration := time.Duration(constants.SeatLockDuration) * time.Minute
-		if time.Now().Sub(*seat.LockedAt) <= lockDuration {
-			// Lock is still valid
-			return nil, errors.NewConflictError(constants.ErrSeatAlreadyLocked, nil)
+// CreateBookingIntent inserts a booking intent. Users already holding maxPending intents that
+// are still pending at the intent's creation time get a conflict; 0 is unlimited.
+func (s *bookingRepository) CreateBookingIntent(ctx context.Context, intent *entities.BookingIntent, maxPending int) error {
+	return conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		if err := checkPendingIntents(tx, intent.UserID, maxPending, intent.CreatedAt); err != nil {
+			return err
 		}
-		// Lock has expired, we can proceed (will clean up the DB lock later)
-	}
+		if err := tx.Create(intent).Error; err != nil {
+			return errors.NewInternalError("Failed to create booking intent", err)
+		}
+		return nil
+	})
+}
 
-	// Check if event is still active and in the future
-	if seat.Event.Status != constants.EventStatusActive {
-		return nil, errors.NewBadRequestError("Event is not active", nil)
+// checkPendingIntents rejects a new intent once the user holds max pending intents that
+// haven't expired at now. The user row is locked first so concurrent requests from the same
+// user count one at a time.
+func checkPendingIntents(tx *gorm.DB, userID uint, max int, now time.Time) error {
+	if max <= 0 {
+		return nil
 	}
 
-	if seat.Event.StartTime.Before(time.Now()) {
-		return nil, errors.NewBadRequestError("Event has already started", nil)
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&entities.User{}, userID).Error; err != nil {
+		return errors.NewInternalError("Failed to lock user", err)
 	}
 
-	// Check if event still has available capacity
-	if seat.Event.AvailableSeats <= 0 {
-		return nil, errors.NewBadRequestError(constants.ErrEventSoldOut, nil)
+	var pending int64
+	if err := tx.Model(&entities.BookingIntent{}).
+		Where("user_id = ? AND status = ? AND lock_expires_at > ?", userID, constants.IntentStatusPending, now).
+		Count(&pending).Error; err != nil {
+		return errors.NewInternalError("Failed to count pending booking intents", err)
 	}
-
-	// Try to acquire Redis lock first
-	tempIntentID := fmt.Sprintf("temp_%d_%d", userID, time.Now().UnixNano())
-	if err := s.seatLockRepository.LockSeat(ctx, seatID, userID, tempIntentID); err != nil {
-		// Redis lock failed, another user might have acquired it in the meantime
-		return nil, errors.NewConflictError(constants.ErrSeatAlreadyLocked, err)
+	if int(pending) >= max {
+		return errors.NewConflictError(constants.ErrTooManyIntents, nil)
 	}
+	return nil
+}
 
-	// Create booking intent in database (minimal transaction)
-	tx := s.db.WithContext(ctx).Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-			// Cleanup Redis lock on panic
-			s.seatLockRepository.UnlockSeat(ctx, seatID, userID, tempIntentID)
-		}
-	}()
-
-	// Create booking intent
-	intent := &entities.BookingIntent{
-		UserID:  userID,
-		EventID: seat.EventID,
-		SeatID:  seatID,
-		Status:  constants.IntentStatusPending,
+// LockSeatInDatabase locks a seat for a user in the database until expiresAt, the lock expiry
+// of the intent taking it, for when Redis can't hold the lock. A lock whose holding intent's
+// expiry has passed at lockedAt counts as unlocked; a lock without an expiry never goes stale.
+func (s *bookingRepository) LockSeatInDatabase(ctx context.Context, seatID, userID uint, lockedAt, expiresAt time.Time) error {
+	result := conn(ctx, s.db).Model(&entities.Seat{}).
+		Where("id = ? AND (is_locked = ? OR lock_expires_at <= ?)", seatID, false, lockedAt).
+		Updates(map[string]interface{}{
+			"is_locked":       true,
+			"locked_at":       lockedAt,
+			"locked_by":       userID,
+			"lock_expires_at": expiresAt,
+		})
+	if result.Error != nil {
+		return errors.NewInternalError("Failed to lock seat in database", result.Error)
 	}
-
-	if err := tx.Create(intent).Error; err != nil {
-		tx.Rollback()
-		s.seatLockRepository.UnlockSeat(ctx, seatID, userID, tempIntentID)
-		return nil, errors.NewInternalError("Failed to create booking intent", err)
+	if result.RowsAffected == 0 {
+		return errors.NewConflictError(constants.ErrSeatAlreadyLocked, nil)
 	}
+	return nil
+}
 
-	// Update Redis lock with real intent ID
-	realIntentID := fmt.Sprintf("%d", intent.ID)
-	if err := s.seatLockRepository.UnlockSeat(ctx, seatID, userID, tempIntentID); err != nil {
-		// Log warning but continue
-		fmt.Printf("Warning: Failed to unlock temp Redis lock: %v\n", err)
+// GetBookingIntent returns a user's booking intent without loading its seat or event
+func (s *bookingRepository) GetBookingIntent(ctx context.Context, bookingIntentID uint, userID uint) (*entities.BookingIntent, error) {
+	var intent entities.BookingIntent
+	if err := conn(ctx, s.db).
+		Select("id, user_id, event_id, seat_id, status, payment_intent_id, payment_status, lock_expires_at, created_at").
+		Where("id = ? AND user_id = ?", bookingIntentID, userID).
+		First(&intent).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Booking intent not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch booking intent", err)
 	}
 
-	if err := s.seatLockRepository.LockSeat(ctx, seatID, userID, realIntentID); err != nil {
-		// Redis lock with real ID failed, fall back to database lock
-		fmt.Printf("Warning: Redis lock with real intent ID failed, falling back to database lock: %v\n", err)
-		if err := s.lockSeatInDatabase(tx, &seat, userID); err != nil {
-			tx.Rollback()
-			return nil, err
+	return &intent, nil
+}
+
+// GetPendingIntent returns a pending booking intent
+func (s *bookingRepository) GetPendingIntent(ctx context.Context, bookingIntentID uint) (*entities.BookingIntent, error) {
+	var intent entities.BookingIntent
+	if err := conn(ctx, s.db).
+		Where("id = ? AND status = ?", bookingIntentID, constants.IntentStatusPending).
+		First(&intent).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Booking intent not found or already processed", errors.ErrRecordNotFound)
 		}
+		return nil, errors.NewInternalError("Failed to fetch booking intent", err)
 	}
+	return &intent, nil
+}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		// Cleanup Redis lock if transaction failed
-		s.seatLockRepository.UnlockSeat(ctx, seatID, userID, realIntentID)
-		return nil, errors.NewInternalError("Failed to commit booking intent", err)
+// GetUserPendingIntent returns one of a user's pending booking intents
+func (s *bookingRepository) GetUserPendingIntent(ctx context.Context, bookingIntentID uint, userID uint) (*entities.BookingIntent, error) {
+	var intent entities.BookingIntent
+	if err := conn(ctx, s.db).
+		Where("id = ? AND user_id = ? AND status = ?",
+			bookingIntentID, userID, constants.IntentStatusPending).
+		First(&intent).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Booking intent not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch booking intent", err)
 	}
+	return &intent, nil
+}
 
-	// Load the intent with relationships
-	if err := s.db.WithContext(ctx).
+// GetIntentDetails returns a booking intent with its user, event, venue and seat
+func (s *bookingRepository) GetIntentDetails(ctx context.Context, bookingIntentID uint) (*entities.BookingIntent, error) {
+	var intent entities.BookingIntent
+	if err := conn(ctx, s.db).
 		Preload("User").
 		Preload("Event.Venue").
 		Preload("Event").
 		Preload("Seat").
-		First(intent, intent.ID).Error; err != nil {
+		First(&intent, bookingIntentID).Error; err != nil {
 		return nil, errors.NewInternalError("Failed to load booking intent", err)
 	}
-
-	return intent, nil
+	return &intent, nil
 }
 
-// createBookingIntentDBFallback falls back to the original database-transaction approach
-func (s *BookingRepository) createBookingIntentDBFallback(ctx context.Context, userID, seatID uint) (*entities.BookingIntent, error) {
-	// Start transaction
-	tx := s.db.WithContext(ctx).Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
+// GetPendingIntents returns the pending intents whose lock expires after the given time
+func (s *bookingRepository) GetPendingIntents(ctx context.Context, expiringAfter time.Time) ([]entities.BookingIntent, error) {
+	var intents []entities.BookingIntent
+	if err := conn(ctx, s.db).
+		Select("id, user_id, event_id, seat_id, lock_expires_at").
+		Where("status = ? AND lock_expires_at > ?", constants.IntentStatusPending, expiringAfter).
+		Find(&intents).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch pending intents", err)
+	}
+	return intents, nil
+}
 
-	// Check if seat exists and is available
-	var seat entities.Seat
-	if err := tx.Preload("Event").First(&seat, seatID).Error; err != nil {
-		tx.Rollback()
+// GetLatestSeatIntent returns the intent holding a seat: its pending intent, or else the most
+// recent intent for it
+func (s *bookingRepository) GetLatestSeatIntent(ctx context.Context, seatID uint) (*entities.BookingIntent, error) {
+	var intent entities.BookingIntent
+	if err := conn(ctx, s.db).Scopes(tenantScopeVia(ctx, "event_id", "events")).
+		Where("seat_id = ?", seatID).
+		Order(clause.Expr{SQL: "status = ? DESC, id DESC", Vars: []interface{}{constants.IntentStatusPending}}).
+		First(&intent).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.NewNotFoundError("Seat not found", errors.ErrRecordNotFound)
+			return nil, errors.NewNotFoundError("Booking intent not found", errors.ErrRecordNotFound)
 		}
-		return nil, errors.NewInternalError("Failed to fetch seat", err)
+		return nil, errors.NewInternalError("Failed to fetch booking intent", err)
 	}
+	return &intent, nil
+}
 
-	// Check if seat is available
-	if !seat.IsAvailable {
-		tx.Rollback()
-		return nil, errors.NewBadRequestError(constants.ErrSeatNotAvailable, nil)
+// GetDatabaseLockedSeats returns the seats locked in the database, with their lock fields only
+func (s *bookingRepository) GetDatabaseLockedSeats(ctx context.Context) ([]entities.Seat, error) {
+	var seats []entities.Seat
+	if err := conn(ctx, s.db).
+		Select("id, event_id, is_locked, locked_at, locked_by").
+		Where("is_locked = ?", true).
+		Find(&seats).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch locked seats", err)
 	}
+	return seats, nil
+}
 
-	// Check if seat is already locked
-	if seat.IsLocked {
-		tx.Rollback()
-		return nil, errors.NewConflictError(constants.ErrSeatAlreadyLocked, nil)
+// ReleaseDatabaseLock clears a seat's database lock if it is still the lock read into seat,
+// returning false if it was released or taken again since
+func (s *bookingRepository) ReleaseDatabaseLock(ctx context.Context, seat *entities.Seat) (bool, error) {
+	query := conn(ctx, s.db).Model(&entities.Seat{}).Where("id = ? AND is_locked = ?", seat.ID, true)
+	if seat.LockedAt != nil {
+		query = query.Where("locked_at = ?", *seat.LockedAt)
+	} else {
+		query = query.Where("locked_at IS NULL")
 	}
-
-	// Check if event is still active and in the future
-	if seat.Event.Status != constants.EventStatusActive {
-		tx.Rollback()
-		return nil, errors.NewBadRequestError("Event is not active", nil)
+	result := query.Updates(map[string]interface{}{
+		"is_locked":       false,
+		"locked_at":       nil,
+		"locked_by":       nil,
+		"lock_expires_at": nil,
+	})
+	if result.Error != nil {
+		return false, errors.NewInternalError("Failed to unlock seat", result.Error)
 	}
+	return result.RowsAffected > 0, nil
+}
 
-	if seat.Event.StartTime.Before(time.Now()) {
-		tx.Rollback()
-		return nil, errors.NewBadRequestError("Event has already started", nil)
+// GetUpcomingEventIDs returns the active events that haven't started, whose seats can be locked
+func (s *bookingRepository) GetUpcomingEventIDs(ctx context.Context, now time.Time) ([]uint, error) {
+	var eventIDs []uint
+	if err := conn(ctx, s.db).Model(&entities.Event{}).
+		Where("status = ? AND start_time > ?", constants.EventStatusActive, now).
+		Pluck("id", &eventIDs).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch upcoming events", err)
 	}
+	return eventIDs, nil
+}
 
-	// Check if event still has available capacity
-	if seat.Event.AvailableSeats <= 0 {
-		tx.Rollback()
-		return nil, errors.NewBadRequestError(constants.ErrEventSoldOut, nil)
-	}
+// ConfirmBooking turns a pending booking intent into a booking in one transaction: the seat
+// is sold, loyalty points are redeemed and earned and the payment is recorded. With
+// ReleaseOtherIntents the user's other pending intents for the event are cancelled too and
+// returned, so their Redis locks can be released.
+func (s *bookingRepository) ConfirmBooking(ctx context.Context, bookingIntentID uint, payment entities.PaymentDetails, attendee entities.AttendeeDetails, options entities.ConfirmOptions) (*entities.Booking, []entities.BookingIntent, error) {
+	paymentID := payment.PaymentID
+
+	var booking *entities.Booking
+	var released []entities.BookingIntent
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		// Get booking intent with optimized query
+		var intent entities.BookingIntent
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id, user_id, event_id, seat_id, status, presale_code_id, terms_version, terms_accepted_at, lock_expires_at, created_at, "+
+				"utm_source, utm_medium, utm_campaign, referral_code").
+			Where("id = ? AND status = ?", bookingIntentID, constants.IntentStatusPending).
+			First(&intent).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewNotFoundError("Booking intent not found or already processed", errors.ErrRecordNotFound)
+			}
+			return errors.NewInternalError("Failed to fetch booking intent", err)
+		}
 
-	// Create booking intent
-	intent := &entities.BookingIntent{
-		UserID:  userID,
-		EventID: seat.EventID,
-		SeatID:  seatID,
-		Status:  constants.IntentStatusPending,
-	}
+		// Get seat price, the pricing rule that set it and companion designation efficiently
+		var seat entities.Seat
+		if err := tx.Select("id, price, pricing_rule_id, is_companion, companion_of_seat_id").First(&seat, intent.SeatID).Error; err != nil {
+			return errors.NewInternalError("Failed to fetch seat price", err)
+		}
+		seatPrice := seat.Price
 
-	if err := tx.Create(intent).Error; err != nil {
-		tx.Rollback()
-		return nil, errors.NewInternalError("Failed to create booking intent", err)
-	}
+		// Link a companion seat booking to the user's accessible seat booking
+		companionOf, err := companionBookingLink(tx, &seat, intent.UserID)
+		if err != nil {
+			return err
+		}
 
-	// Lock seat in database
-	if err := s.lockSeatInDatabase(tx, &seat, userID); err != nil {
-		tx.Rollback()
-		return nil, err
-	}
+		// Count the booking against the presale code it was made with
+		if intent.PresaleCodeID != nil {
+			if err := redeemPresaleCode(tx, *intent.PresaleCodeID); err != nil {
+				return err
+			}
+		}
+
+		// Bookings belong to the event's tenant. Sandbox and free events take no payment and leave
+		// loyalty points alone.
+		var event entities.Event
+		if err := tx.Select("id", "tenant_id", "price", "sandbox", "start_time", "sales_close_minutes_before_start", "ticket_limit").
+			First(&event, intent.EventID).Error; err != nil {
+			return errors.NewInternalError("Failed to fetch event tenant", err)
+		}
+		// Intents created just before the cutoff can't be confirmed after it
+		if !time.Now().Before(event.SalesCloseAt()) {
+			return errors.NewBadRequestError(constants.ErrSalesClosed, nil)
+		}
+		free := event.IsFree()
+		paymentStatus := constants.PaymentStatusPaid
+		switch {
+		case event.Sandbox:
+			paymentStatus = constants.PaymentStatusSandbox
+		case free:
+			paymentStatus = constants.PaymentStatusFree
+		}
+		if free {
+			if err := checkTicketLimit(tx, &event, intent.UserID, options.FreeTicketLimit); err != nil {
+				return err
+			}
+		}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		return nil, errors.NewInternalError("Failed to commit booking intent", err)
+		// Apply any loyalty points redeemed as a discount; the account stays locked until commit
+		var account *entities.User
+		var pointsRedeemed, earned int
+		var discount money.Money
+		if !event.Sandbox && !free {
+			if account, err = lockLoyaltyAccount(tx, intent.UserID); err != nil {
+				return err
+			}
+			if pointsRedeemed, discount, err = loyaltyDiscount(account, payment.RedeemPoints, seatPrice); err != nil {
+				return err
+			}
+			earned = pointsEarned(seatPrice - discount)
+		}
+		amountPaid := seatPrice - discount
+
+		// Create booking
+		booking = &entities.Booking{
+			TenantID:             event.TenantID,
+			UserID:               intent.UserID,
+			EventID:              intent.EventID,
+			SeatID:               intent.SeatID,
+			BookingIntentID:      &intent.ID,
+			Status:               constants.BookingStatusConfirmed,
+			PaymentStatus:        paymentStatus,
+			PaymentID:            paymentID,
+			TotalAmount:          amountPaid,
+			SeatPrice:            seatPrice,
+			PricingRuleID:        seat.PricingRuleID,
+			DiscountAmount:       discount,
+			PointsRedeemed:       pointsRedeemed,
+			PointsEarned:         earned,
+			PresaleCodeID:        intent.PresaleCodeID,
+			CompanionOfBookingID: companionOf,
+			TermsVersion:         intent.TermsVersion,
+			TermsAcceptedAt:      intent.TermsAcceptedAt,
+			Sandbox:              event.Sandbox,
+			Attribution:          intent.Attribution,
+			Channel:              constants.SalesChannelOnline,
+			BookedAt:             time.Now(),
+		}
+
+		// Collect the attendee details the event requires, e.g. for age-restricted entry
+		if err := collectAttendeeDetails(tx, intent.EventID, attendee, booking); err != nil {
+			return err
+		}
+
+		if err := tx.Create(booking).Error; err != nil {
+			return errors.NewInternalError("Failed to create booking", err)
+		}
+
+		if err := postBookingSale(tx, booking); err != nil {
+			return err
+		}
+
+		// Credit the referrer whose code the booking was made with
+		if err := recordReferralCommission(tx, booking); err != nil {
+			return err
+		}
+
+		// Burn the redeemed points, then earn on the amount actually paid
+		if err := applyLoyaltyPoints(tx, account, booking.ID, -pointsRedeemed, 0, constants.LoyaltyReasonRedeem); err != nil {
+			return err
+		}
+		if err := applyLoyaltyPoints(tx, account, booking.ID, booking.PointsEarned, booking.PointsEarned, constants.LoyaltyReasonEarn); err != nil {
+			return err
+		}
+
+		// Keep a reconciliation record of the payment (masked method only, never card data).
+		// Sandbox and free bookings took no payment to reconcile.
+		if !event.Sandbox && !free {
+			provider := payment.Provider
+			if provider == "" {
+				provider = constants.DefaultPaymentProvider
+			}
+			currency := strings.ToUpper(payment.Currency)
+			if currency == "" {
+				currency = constants.DefaultCurrency
+			}
+			if err := createPaymentTransaction(tx, &entities.PaymentTransaction{
+				BookingID:         &booking.ID,
+				BookingIntentID:   &intent.ID,
+				Provider:          provider,
+				ProviderReference: paymentID,
+				Amount:            amountPaid,
+				Currency:          currency,
+				MethodType:        payment.MethodType,
+				MaskedMethod:      payment.MaskedMethod(),
+				Status:            constants.PaymentStatusPaid,
+			}); err != nil {
+				return err
+			}
+		}
+
+		// Batch update booking intent and seat in a single operation each
+		if err := tx.Model(&entities.BookingIntent{}).Where("id = ?", intent.ID).
+			Updates(map[string]interface{}{
+				"status":            constants.IntentStatusConfirmed,
+				"payment_intent_id": paymentID,
+				"payment_status":    paymentStatus,
+				"updated_at":        time.Now(),
+			}).Error; err != nil {
+			return errors.NewInternalError("Failed to update booking intent", err)
+		}
+
+		// Close the payment attempt that succeeded, if the intent has an attempt history
+		if err := tx.Model(&entities.PaymentAttempt{}).
+			Where("booking_intent_id = ? AND status = ?", intent.ID, constants.PaymentStatusPending).
+			Update("status", paymentStatus).Error; err != nil {
+			return errors.NewInternalError("Failed to update payment attempt", err)
+		}
+
+		// Update seat availability efficiently
+		if err := tx.Model(&entities.Seat{}).Where("id = ?", intent.SeatID).
+			Updates(map[string]interface{}{
+				"is_available":    false,
+				"is_locked":       false,
+				"locked_at":       nil,
+				"locked_by":       nil,
+				"lock_expires_at": nil,
+				"updated_at":      time.Now(),
+			}).Error; err != nil {
+			return errors.NewInternalError("Failed to update seat", err)
+		}
+
+		// Update event available seats count using atomic operation with capacity check
+		result := tx.Model(&entities.Event{}).
+			Where("id = ? AND available_seats > 0", intent.EventID).
+			Update("available_seats", gorm.Expr("available_seats - ?", 1))
+
+		if result.Error != nil {
+			return errors.NewInternalError("Failed to update event capacity", result.Error)
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.NewBadRequestError(constants.ErrEventSoldOut, nil)
+		}
+
+		// Give up the user's other seats for this event with the same commit, if asked to
+		if options.ReleaseOtherIntents {
+			if released, err = releaseOtherIntents(tx, &intent); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Load the intent with relationships
-	if err := s.db.WithContext(ctx).
+	// Load the booking with relationships using optimized query
+	if err := conn(ctx, s.db).
 		Preload("User").
 		Preload("Event.Venue").
 		Preload("Event").
 		Preload("Seat").
-		First(intent, intent.ID).Error; err != nil {
-		return nil, errors.NewInternalError("Failed to load booking intent", err)
+		First(booking, booking.ID).Error; err != nil {
+		return nil, nil, errors.NewInternalError("Failed to load booking", err)
 	}
 
-	return intent, nil
+	return booking, released, nil
 }
 
-// lockSeatInDatabase locks a seat in the database
-func (s *BookingRepository) lockSeatInDatabase(tx *gorm.DB, seat *entities.Seat, userID uint) error {
-	if err := tx.Model(seat).Updates(map[string]interface{}{
-		"is_locked": true,
-		"locked_at": time.Now(),
-		"locked_by": userID,
-	}).Error; err != nil {
-		return errors.NewInternalError("Failed to lock seat in database", err)
+// checkTicketLimit checks that a user holds fewer confirmed tickets for a free event than its
+// ticket limit, or defaultLimit if it has none; 0 is unlimited. The user's row is locked until
+// commit, so two confirmations of the same user can't both take the last ticket.
+func checkTicketLimit(tx *gorm.DB, event *entities.Event, userID uint, defaultLimit int) error {
+	limit := event.TicketLimit
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	if limit <= 0 {
+		return nil
 	}
-	return nil
-}
-
-// ConfirmBooking confirms a booking intent after successful payment
-func (s *BookingRepository) ConfirmBooking(ctx context.Context, bookingIntentID uint, paymentID string) (*entities.Booking, error) {
-	// Start transaction
-	tx := s.db.WithContext(ctx).Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
 
-	// Get booking intent with optimized query
-	var intent entities.BookingIntent
-	if err := tx.Select("id, user_id, event_id, seat_id, status, created_at").
-		Where("id = ? AND status = ?", bookingIntentID, constants.IntentStatusPending).
-		First(&intent).Error; err != nil {
-		tx.Rollback()
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&entities.User{}, userID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.NewNotFoundError("Booking intent not found or already processed", errors.ErrRecordNotFound)
+			return errors.NewNotFoundError("User not found", errors.ErrUserNotFound)
 		}
-		return nil, errors.NewInternalError("Failed to fetch booking intent", err)
+		return errors.NewInternalError("Failed to fetch user", err)
 	}
-
-	// Check if intent is still valid
-	// logic is Now - lockCreatedAt > duration -> expired
-	if time.Now().After(intent.CreatedAt.Add(time.Duration(constants.SeatLockDuration) * time.Minute)) {
-		tx.Rollback()
-		return nil, errors.NewBadRequestError(constants.ErrBookingExpired, nil)
+	var held int64
+	if err := tx.Model(&entities.Booking{}).
+		Where("event_id = ? AND user_id = ? AND status = ?", event.ID, userID, constants.BookingStatusConfirmed).
+		Count(&held).Error; err != nil {
+		return errors.NewInternalError("Failed to count tickets", err)
 	}
-
-	// Get seat price efficiently
-	var seatPrice float64
-	if err := tx.Model(&entities.Seat{}).Select("price").Where("id = ?", intent.SeatID).Scan(&seatPrice).Error; err != nil {
-		tx.Rollback()
-		return nil, errors.NewInternalError("Failed to fetch seat price", err)
+	if held >= int64(limit) {
+		return errors.NewConflictError(constants.ErrTicketLimitReached, nil)
 	}
+	return nil
+}
 
-	// Create booking
-	booking := &entities.Booking{
-		UserID:          intent.UserID,
-		EventID:         intent.EventID,
-		SeatID:          intent.SeatID,
-		BookingIntentID: &intent.ID,
-		Status:          constants.BookingStatusConfirmed,
-		PaymentStatus:   constants.PaymentStatusPaid,
-		PaymentID:       paymentID,
-		TotalAmount:     seatPrice,
-		BookedAt:        time.Now(),
+// releaseOtherIntents cancels the user's other pending intents for the intent's event and
+// unlocks their seats in the database, returning the cancelled intents
+func releaseOtherIntents(tx *gorm.DB, intent *entities.BookingIntent) ([]entities.BookingIntent, error) {
+	var others []entities.BookingIntent
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id, user_id, event_id, seat_id").
+		Where("user_id = ? AND event_id = ? AND status = ? AND id <> ?",
+			intent.UserID, intent.EventID, constants.IntentStatusPending, intent.ID).
+		Find(&others).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch other booking intents", err)
 	}
-
-	if err := tx.Create(booking).Error; err != nil {
-		tx.Rollback()
-		return nil, errors.NewInternalError("Failed to create booking", err)
+	if len(others) == 0 {
+		return nil, nil
 	}
 
-	// Batch update booking intent and seat in a single operation each
-	if err := tx.Model(&entities.BookingIntent{}).Where("id = ?", intent.ID).
-		Updates(map[string]interface{}{
-			"status":            constants.IntentStatusConfirmed,
-			"payment_intent_id": paymentID,
-			"updated_at":        time.Now(),
-		}).Error; err != nil {
-		tx.Rollback()
-		return nil, errors.NewInternalError("Failed to update booking intent", err)
+	intentIDs := make([]uint, len(others))
+	seatIDs := make([]uint, len(others))
+	for i, other := range others {
+		intentIDs[i] = other.ID
+		seatIDs[i] = other.SeatID
 	}
 
-	// Update seat availability efficiently
-	if err := tx.Model(&entities.Seat{}).Where("id = ?", intent.SeatID).
+	if err := tx.Model(&entities.BookingIntent{}).Where("id IN ?", intentIDs).
+		Update("status", constants.IntentStatusCancelled).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to cancel other booking intents", err)
+	}
+	if err := tx.Model(&entities.Seat{}).Where("id IN ? AND locked_by = ?", seatIDs, intent.UserID).
 		Updates(map[string]interface{}{
-			"is_available": false,
-			"is_locked":    false,
-			"locked_at":    nil,
-			"locked_by":    nil,
-			"updated_at":   time.Now(),
+			"is_locked":       false,
+			"locked_at":       nil,
+			"locked_by":       nil,
+			"lock_expires_at": nil,
 		}).Error; err != nil {
-		tx.Rollback()
-		return nil, errors.NewInternalError("Failed to update seat", err)
+		return nil, errors.NewInternalError("Failed to unlock seats", err)
 	}
+	return others, nil
+}
 
-	// Update event available seats count using atomic operation with capacity check
-	result := tx.Model(&entities.Event{}).
-		Where("id = ? AND available_seats > 0", intent.EventID).
-		Update("available_seats", gorm.Expr("available_seats - ?", 1))
+// CancelBookingIntent cancels a user's pending booking intent and unlocks its seat in the
+// database, returning the cancelled intent
+func (s *bookingRepository) CancelBookingIntent(ctx context.Context, bookingIntentID uint, userID uint) (*entities.BookingIntent, error) {
+	var intent entities.BookingIntent
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ? AND status = ?",
+				bookingIntentID, userID, constants.IntentStatusPending).
+			First(&intent).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewNotFoundError("Booking intent not found", errors.ErrRecordNotFound)
+			}
+			return errors.NewInternalError("Failed to fetch booking intent", err)
+		}
 
-	if result.Error != nil {
-		tx.Rollback()
-		return nil, errors.NewInternalError("Failed to update event capacity", result.Error)
-	}
+		if err := tx.Model(&intent).Update("status", constants.IntentStatusCancelled).Error; err != nil {
+			return errors.NewInternalError("Failed to update booking intent", err)
+		}
 
-	if result.RowsAffected == 0 {
-		tx.Rollback()
-		return nil, errors.NewBadRequestError(constants.ErrEventSoldOut, nil)
+		return unlockIntentSeat(tx, &intent)
+	})
+	if err != nil {
+		return nil, err
 	}
+	return &intent, nil
+}
 
-	// Unlock seat in Redis (don't fail transaction if this fails)
-	intentIDStr := fmt.Sprintf("%d", intent.ID)
-	if err := s.seatLockRepository.UnlockSeat(ctx, intent.SeatID, intent.UserID, intentIDStr); err != nil {
-		// Log this error but don't fail the transaction as the booking is already confirmed
-		fmt.Printf("Warning: Failed to unlock seat in Redis: %v\n", err)
-	}
+// CancelUserIntents cancels all of a user's pending intents and unlocks their seats in the
+// database, returning the cancelled intents
+func (s *bookingRepository) CancelUserIntents(ctx context.Context, userID uint) ([]entities.BookingIntent, error) {
+	var cancelled []entities.BookingIntent
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND status = ?", userID, constants.IntentStatusPending).
+			Find(&cancelled).Error; err != nil {
+			return errors.NewInternalError("Failed to fetch pending intents", err)
+		}
+		if len(cancelled) == 0 {
+			return nil
+		}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		return nil, errors.NewInternalError("Failed to commit booking", err)
-	}
+		intentIDs := make([]uint, len(cancelled))
+		for i, intent := range cancelled {
+			intentIDs[i] = intent.ID
+		}
+		if err := tx.Model(&entities.BookingIntent{}).
+			Where("id IN ?", intentIDs).
+			Update("status", constants.IntentStatusCancelled).Error; err != nil {
+			return errors.NewInternalError("Failed to update booking intents", err)
+		}
 
-	// Load the booking with relationships using optimized query
-	if err := s.db.WithContext(ctx).
-		Preload("User").
-		Preload("Event.Venue").
-		Preload("Event").
-		Preload("Seat").
-		First(booking, booking.ID).Error; err != nil {
-		return nil, errors.NewInternalError("Failed to load booking", err)
+		for i := range cancelled {
+			if err := unlockIntentSeat(tx, &cancelled[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return cancelled, nil
+}
 
-	return booking, nil
+// ExpireBookingIntent expires a pending intent and unlocks its seat in the database. It
+// returns nil if the intent was already confirmed, cancelled or expired.
+func (s *bookingRepository) ExpireBookingIntent(ctx context.Context, bookingIntentID uint) (*entities.BookingIntent, error) {
+	var intent entities.BookingIntent
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.BookingIntent{}).
+			Where("id = ? AND status = ?", bookingIntentID, constants.IntentStatusPending).
+			Update("status", constants.IntentStatusExpired)
+		if result.Error != nil {
+			return errors.NewInternalError("Failed to expire booking intent", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.First(&intent, bookingIntentID).Error; err != nil {
+			return errors.NewInternalError("Failed to fetch booking intent", err)
+		}
+		return unlockIntentSeat(tx, &intent)
+	})
+	if err != nil || intent.ID == 0 {
+		return nil, err
+	}
+	return &intent, nil
 }
 
-// CancelBookingIntent cancels a booking intent and unlocks the seat
-func (s *BookingRepository) CancelBookingIntent(ctx context.Context, bookingIntentID uint, userID uint) error {
-	// Start transaction
-	tx := s.db.WithContext(ctx).Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+// ExpireIntents expires the pending intents whose lock expired before the given time and
+// unlocks their seats in the database, returning the expired intents
+func (s *bookingRepository) ExpireIntents(ctx context.Context, before time.Time) ([]entities.BookingIntent, error) {
+	var expired []entities.BookingIntent
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("status = ? AND lock_expires_at < ?", constants.IntentStatusPending, before).
+			Find(&expired).Error; err != nil {
+			return errors.NewInternalError("Failed to fetch expired intents", err)
+		}
+		if len(expired) == 0 {
+			return nil
 		}
-	}()
 
-	// Get booking intent
-	var intent entities.BookingIntent
-	if err := tx.Where("id = ? AND user_id = ? AND status = ?",
-		bookingIntentID, userID, constants.IntentStatusPending).
-		First(&intent).Error; err != nil {
-		tx.Rollback()
-		if err == gorm.ErrRecordNotFound {
-			return errors.NewNotFoundError("Booking intent not found", errors.ErrRecordNotFound)
+		intentIDs := make([]uint, len(expired))
+		seatIDs := make([]uint, len(expired))
+		for i, intent := range expired {
+			intentIDs[i] = intent.ID
+			seatIDs[i] = intent.SeatID
 		}
-		return errors.NewInternalError("Failed to fetch booking intent", err)
+
+		if err := tx.Model(&entities.BookingIntent{}).
+			Where("id IN ?", intentIDs).
+			Update("status", constants.IntentStatusExpired).Error; err != nil {
+			return errors.NewInternalError("Failed to update expired intents", err)
+		}
+
+		if err := tx.Model(&entities.Seat{}).
+			Where("id IN ?", seatIDs).
+			Updates(map[string]interface{}{
+				"is_locked":       false,
+				"locked_at":       nil,
+				"locked_by":       nil,
+				"lock_expires_at": nil,
+			}).Error; err != nil {
+			return errors.NewInternalError("Failed to unlock seats", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return expired, nil
+}
+
+// ExpireEventIntents expires an event's pending intents created before the given time, whatever
+// their lock expiry, and unlocks their seats in the database, returning the expired intents
+func (s *bookingRepository) ExpireEventIntents(ctx context.Context, eventID uint, createdBefore time.Time) ([]entities.BookingIntent, error) {
+	var expired []entities.BookingIntent
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		var event entities.Event
+		if err := tx.Scopes(tenantScope(ctx, "events")).Select("id").First(&event, eventID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewNotFoundError("Event not found", errors.ErrRecordNotFound)
+			}
+			return errors.NewInternalError("Failed to fetch event", err)
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("event_id = ? AND status = ? AND created_at < ?", eventID, constants.IntentStatusPending, createdBefore).
+			Find(&expired).Error; err != nil {
+			return errors.NewInternalError("Failed to fetch pending intents", err)
+		}
+		if len(expired) == 0 {
+			return nil
+		}
+
+		intentIDs := make([]uint, len(expired))
+		for i, intent := range expired {
+			intentIDs[i] = intent.ID
+		}
+		if err := tx.Model(&entities.BookingIntent{}).
+			Where("id IN ?", intentIDs).
+			Update("status", constants.IntentStatusExpired).Error; err != nil {
+			return errors.NewInternalError("Failed to update expired intents", err)
+		}
 
-	// Update intent status
-	if err := tx.Model(&intent).Update("status", constants.IntentStatusCancelled).Error; err != nil {
-		tx.Rollback()
-		return errors.NewInternalError("Failed to update booking intent", err)
+		for i := range expired {
+			if err := unlockIntentSeat(tx, &expired[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return expired, nil
+}
 
-	// Unlock seat in database
-	if err := tx.Model(&entities.Seat{}).Where("id = ?", intent.SeatID).
+// unlockIntentSeat releases the intent's database seat lock, if the intent's user holds it
+func unlockIntentSeat(tx *gorm.DB, intent *entities.BookingIntent) error {
+	if err := tx.Model(&entities.Seat{}).Where("id = ? AND locked_by = ?", intent.SeatID, intent.UserID).
 		Updates(map[string]interface{}{
-			"is_locked": false,
-			"locked_at": nil,
-			"locked_by": nil,
+			"is_locked":       false,
+			"locked_at":       nil,
+			"locked_by":       nil,
+			"lock_expires_at": nil,
 		}).Error; err != nil {
-		tx.Rollback()
 		return errors.NewInternalError("Failed to unlock seat", err)
 	}
+	return nil
+}
 
-	// Unlock seat in Redis (don't fail transaction if this fails)
-	intentIDStr := fmt.Sprintf("%d", intent.ID)
-	if err := s.seatLockRepository.UnlockSeat(ctx, intent.SeatID, userID, intentIDStr); err != nil {
-		// Log this error but don't fail the transaction as the database unlock is sufficient
-		fmt.Printf("Warning: Failed to unlock seat in Redis: %v\n", err)
+// SaveResumeToken stores the hash of a pending intent's checkout handoff token, replacing
+// any previous one
+func (s *bookingRepository) SaveResumeToken(ctx context.Context, bookingIntentID uint, tokenHash string, expiresAt time.Time) error {
+	result := conn(ctx, s.db).Model(&entities.BookingIntent{}).
+		Where("id = ? AND status = ?", bookingIntentID, constants.IntentStatusPending).
+		Updates(map[string]interface{}{
+			"resume_token_hash":       tokenHash,
+			"resume_token_expires_at": expiresAt,
+		})
+	if result.Error != nil {
+		return errors.NewInternalError("Failed to save resume token", result.Error)
 	}
+	if result.RowsAffected == 0 {
+		return errors.NewNotFoundError("Booking intent not found", errors.ErrRecordNotFound)
+	}
+	return nil
+}
 
-	return tx.Commit().Error
+// GetIntentByResumeToken returns the user's pending intent a resume token was issued for
+func (s *bookingRepository) GetIntentByResumeToken(ctx context.Context, userID uint, tokenHash string) (*entities.BookingIntent, error) {
+	var intent entities.BookingIntent
+	if err := conn(ctx, s.db).
+		Where("resume_token_hash = ? AND user_id = ? AND status = ?",
+			tokenHash, userID, constants.IntentStatusPending).
+		First(&intent).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewBadRequestError(constants.ErrInvalidResumeToken, nil)
+		}
+		return nil, errors.NewInternalError("Failed to fetch booking intent", err)
+	}
+	return &intent, nil
+}
+
+// RedeemResumeToken clears a resume token so it can't be used again. It reports false if the
+// token was already redeemed or replaced.
+func (s *bookingRepository) RedeemResumeToken(ctx context.Context, bookingIntentID uint, tokenHash string) (bool, error) {
+	result := conn(ctx, s.db).Model(&entities.BookingIntent{}).
+		Where("id = ? AND resume_token_hash = ? AND status = ?", bookingIntentID, tokenHash, constants.IntentStatusPending).
+		Updates(map[string]interface{}{
+			"resume_token_hash":       "",
+			"resume_token_expires_at": nil,
+		})
+	if result.Error != nil {
+		return false, errors.NewInternalError("Failed to redeem resume token", result.Error)
+	}
+	return result.RowsAffected > 0, nil
 }
 
-// CancelBooking cancels a confirmed booking
-func (s *BookingRepository) CancelBooking(ctx context.Context, bookingID uint, userID uint) error {
-	// Start transaction
-	tx := s.db.WithContext(ctx).Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+// StartPaymentAttempt fails the current payment attempt of a pending intent and starts a new
+// one with the given reference, unless the intent already made maxAttempts attempts. It
+// returns the intent with its attempt history.
+func (s *bookingRepository) StartPaymentAttempt(ctx context.Context, bookingIntentID uint, reference, failureReason string, maxAttempts int) (*entities.BookingIntent, error) {
+	var intent entities.BookingIntent
+
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", bookingIntentID, constants.IntentStatusPending).
+			First(&intent).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewNotFoundError("Booking intent not found or already processed", errors.ErrRecordNotFound)
+			}
+			return errors.NewInternalError("Failed to fetch booking intent", err)
+		}
+
+		var attempts []entities.PaymentAttempt
+		if err := tx.Where("booking_intent_id = ?", intent.ID).
+			Order("attempt_number ASC").
+			Find(&attempts).Error; err != nil {
+			return errors.NewInternalError("Failed to fetch payment attempts", err)
+		}
+
+		// The first attempt is implicit until a retry happens, record it so the history is complete
+		if len(attempts) == 0 {
+			first := entities.PaymentAttempt{
+				BookingIntentID: intent.ID,
+				AttemptNumber:   1,
+				Reference:       intent.PaymentIntentID,
+				Status:          constants.PaymentStatusPending,
+			}
+			if err := tx.Create(&first).Error; err != nil {
+				return errors.NewInternalError("Failed to record payment attempt", err)
+			}
+			attempts = append(attempts, first)
+		}
+
+		if len(attempts) >= maxAttempts {
+			return errors.NewBadRequestError(constants.ErrPaymentAttemptLimit, nil)
 		}
-	}()
 
-	// Get booking
+		if err := tx.Model(&entities.PaymentAttempt{}).
+			Where("booking_intent_id = ? AND status = ?", intent.ID, constants.PaymentStatusPending).
+			Updates(map[string]interface{}{
+				"status":         constants.PaymentStatusFailed,
+				"failure_reason": failureReason,
+			}).Error; err != nil {
+			return errors.NewInternalError("Failed to update payment attempt", err)
+		}
+
+		attempt := entities.PaymentAttempt{
+			BookingIntentID: intent.ID,
+			AttemptNumber:   attempts[len(attempts)-1].AttemptNumber + 1,
+			Reference:       reference,
+			Status:          constants.PaymentStatusPending,
+		}
+		if err := tx.Create(&attempt).Error; err != nil {
+			return errors.NewInternalError("Failed to create payment attempt", err)
+		}
+
+		if err := tx.Model(&entities.BookingIntent{}).Where("id = ?", intent.ID).
+			Updates(map[string]interface{}{
+				"payment_intent_id": reference,
+				"payment_status":    constants.PaymentStatusPending,
+				"updated_at":        time.Now(),
+			}).Error; err != nil {
+			return errors.NewInternalError("Failed to update booking intent", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn(ctx, s.db).
+		Preload("PaymentAttempts", func(db *gorm.DB) *gorm.DB { return db.Order("attempt_number ASC") }).
+		First(&intent, intent.ID).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to load booking intent", err)
+	}
+
+	return &intent, nil
+}
+
+// GetConfirmedBooking returns a user's confirmed booking with its event
+func (s *bookingRepository) GetConfirmedBooking(ctx context.Context, bookingID uint, userID uint) (*entities.Booking, error) {
 	var booking entities.Booking
-	if err := tx.Preload("Event").
+	if err := conn(ctx, s.db).Preload("Event").
 		Where("id = ? AND user_id = ? AND status = ?",
 			bookingID, userID, constants.BookingStatusConfirmed).
 		First(&booking).Error; err != nil {
-		tx.Rollback()
 		if err == gorm.ErrRecordNotFound {
-			return errors.NewNotFoundError("Booking not found", errors.ErrRecordNotFound)
+			return nil, errors.NewNotFoundError("Booking not found", errors.ErrRecordNotFound)
 		}
-		return errors.NewInternalError("Failed to fetch booking", err)
+		return nil, errors.NewInternalError("Failed to fetch booking", err)
 	}
+	return &booking, nil
+}
+
+// CancelBooking cancels a confirmed booking together with the companion seat bookings made
+// alongside it, returning their seats to the event's inventory. It returns the cancelled
+// bookings, the requested one first.
+func (s *bookingRepository) CancelBooking(ctx context.Context, bookingID uint) ([]entities.Booking, error) {
+	var cancelled []entities.Booking
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		var booking entities.Booking
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", bookingID, constants.BookingStatusConfirmed).
+			First(&booking).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewNotFoundError("Booking not found", errors.ErrRecordNotFound)
+			}
+			return errors.NewInternalError("Failed to fetch booking", err)
+		}
 
-	// Check if event hasn't started yet (allow cancellation only before event starts)
-	if booking.Event.StartTime.Before(time.Now()) {
-		tx.Rollback()
-		return errors.NewBadRequestError("Cannot cancel booking after event has started", nil)
+		if err := cancelConfirmedBooking(tx, &booking); err != nil {
+			return err
+		}
+
+		// Companion seats booked alongside an accessible seat are released with it
+		var companions []entities.Booking
+		if err := tx.Where("companion_of_booking_id = ? AND status = ?", booking.ID, constants.BookingStatusConfirmed).
+			Find(&companions).Error; err != nil {
+			return errors.NewInternalError("Failed to fetch companion bookings", err)
+		}
+		for i := range companions {
+			if err := cancelConfirmedBooking(tx, &companions[i]); err != nil {
+				return err
+			}
+		}
+
+		cancelled = append([]entities.Booking{booking}, companions...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return cancelled, nil
+}
 
-	// Update booking status
-	if err := tx.Model(&booking).Updates(map[string]interface{}{
+// cancelConfirmedBooking cancels a booking inside a transaction, reversing its loyalty points
+// and returning its seat to the event's inventory
+func cancelConfirmedBooking(tx *gorm.DB, booking *entities.Booking) error {
+	now := time.Now()
+	if err := tx.Model(booking).Updates(map[string]interface{}{
 		"status":       constants.BookingStatusCancelled,
-		"cancelled_at": time.Now(),
+		"cancelled_at": now,
 	}).Error; err != nil {
-		tx.Rollback()
 		return errors.NewInternalError("Failed to cancel booking", err)
 	}
 
+	// Give the money back; the booking's payment status keeps recording that it was paid,
+	// its payment transaction shows the refund
+	if err := postBookingRefund(tx, booking); err != nil {
+		return err
+	}
+	if booking.PaymentStatus == constants.PaymentStatusPaid {
+		if err := transitionPaymentTransaction(tx, booking.ID, constants.PaymentStatusRefunded, now); err != nil {
+			return err
+		}
+	}
+
+	// Refund redeemed points and take back the points earned by this booking
+	if err := reverseBookingLoyalty(tx, booking); err != nil {
+		return err
+	}
+
 	// Make seat available again
 	if err := tx.Model(&entities.Seat{}).Where("id = ?", booking.SeatID).
 		Update("is_available", true).Error; err != nil {
-		tx.Rollback()
 		return errors.NewInternalError("Failed to update seat availability", err)
 	}
 
-	// Update event available seats count
-	if err := tx.Model(&booking.Event).UpdateColumn("available_seats", gorm.Expr("available_seats + ?", 1)).Error; err != nil {
-		tx.Rollback()
+	// Update event available seats count; oversell places were never part of it
+	if booking.Channel == constants.SalesChannelOversell {
+		return nil
+	}
+	if err := tx.Model(&entities.Event{}).Where("id = ?", booking.EventID).
+		UpdateColumn("available_seats", gorm.Expr("available_seats + ?", 1)).Error; err != nil {
 		return errors.NewInternalError("Failed to update event capacity", err)
 	}
 
-	return tx.Commit().Error
+	return nil
 }
 
-// GetUserBookings returns user's booking history
-func (s *BookingRepository) GetUserBookings(ctx context.Context, userID uint, limit, offset int) ([]entities.Booking, int64, error) {
+// GetUserBookings returns user's booking history matching the filter, archived bookings included
+func (s *bookingRepository) GetUserBookings(ctx context.Context, userID uint, filter entities.BookingFilter, limit, offset int) ([]entities.Booking, int64, error) {
 	var bookings []entities.Booking
 	var total int64
 
-	query := s.db.WithContext(ctx).Model(&entities.Booking{}).Where("user_id = ?", userID)
+	query := bookingHistory(conn(ctx, s.db)).Model(&entities.Booking{}).Where("bookings.user_id = ?", userID)
+	if filter.Status != "" {
+		query = query.Where("bookings.status = ?", filter.Status)
+	}
+	if filter.EventID != 0 {
+		query = query.Where("bookings.event_id = ?", filter.EventID)
+	}
+	// Periods and event orders need the event's times
+	if filter.Period != "" || filter.Sort == entities.BookingSortEventStart || filter.Sort == entities.BookingSortEventStartDesc {
+		query = query.Joins("JOIN events ON events.id = bookings.event_id")
+	}
+	switch filter.Period {
+	case entities.BookingsUpcoming:
+		query = query.Where("events.end_time > ?", time.Now())
+	case entities.BookingsPast:
+		query = query.Where("events.end_time <= ?", time.Now())
+	}
 
 	// Get total count
 	if err := query.Count(&total).Error; err != nil {
@@ -486,8 +1032,8 @@ func (s *BookingRepository) GetUserBookings(ctx context.Context, userID uint, li
 	}
 
 	// Get paginated results
-	if err := query.Preload("Event.Venue").Preload("Event").Preload("Seat").
-		Order("created_at DESC").
+	if err := query.Select("bookings.*").Scopes(bookingRelations(ctx)).
+		Order(bookingOrder(filter.Sort)).
 		Limit(limit).Offset(offset).
 		Find(&bookings).Error; err != nil {
 		return nil, 0, errors.NewInternalError("Failed to fetch bookings", err)
@@ -496,14 +1042,65 @@ func (s *BookingRepository) GetUserBookings(ctx context.Context, userID uint, li
 	return bookings, total, nil
 }
 
-// GetBookingByID returns a specific booking
-func (s *BookingRepository) GetBookingByID(ctx context.Context, bookingID, userID uint) (*entities.Booking, error) {
+// bookingRelations preloads the event, its venue and the seat of bookings, less the relations
+// ctx leaves out
+func bookingRelations(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if expand.Includes(ctx, expand.Event) {
+			db = db.Preload("Event")
+			if expand.Includes(ctx, expand.EventVenue) {
+				db = db.Preload("Event.Venue")
+			}
+		}
+		if expand.Includes(ctx, expand.Seat) {
+			db = db.Preload("Seat")
+		}
+		return db
+	}
+}
+
+// bookingOrder returns the ORDER BY clause of a booking history order, the booking ID breaking
+// ties so pages don't overlap
+func bookingOrder(sort string) string {
+	switch sort {
+	case entities.BookingSortOldest:
+		return "bookings.created_at ASC, bookings.id ASC"
+	case entities.BookingSortEventStart:
+		return "events.start_time ASC, bookings.id ASC"
+	case entities.BookingSortEventStartDesc:
+		return "events.start_time DESC, bookings.id DESC"
+	default:
+		return "bookings.created_at DESC, bookings.id DESC"
+	}
+}
+
+// GetUpcomingBookings returns a user's next confirmed bookings for events that haven't ended,
+// soonest first, with only what a home screen widget shows. The bookings side is answered from
+// idx_bookings_user_upcoming alone; archived bookings are of past events, so only live ones are read.
+func (s *bookingRepository) GetUpcomingBookings(ctx context.Context, userID uint, now time.Time, limit int) ([]entities.Booking, error) {
+	var bookings []entities.Booking
+	if err := conn(ctx, s.db).Model(&entities.Booking{}).
+		Select("bookings.id", "bookings.event_id", "bookings.seat_id", "bookings.status").
+		Joins("JOIN events ON events.id = bookings.event_id").
+		Where("bookings.user_id = ? AND bookings.status = ? AND events.end_time > ?", userID, constants.BookingStatusConfirmed, now).
+		Preload("Event", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "name", "venue_id", "start_time", "end_time", "status")
+		}).
+		Preload("Event.Venue", func(db *gorm.DB) *gorm.DB { return db.Select("id", "name", "city") }).
+		Preload("Seat", func(db *gorm.DB) *gorm.DB { return db.Select("id", "row", "column", "seat_type") }).
+		Order("events.start_time ASC, bookings.id ASC").
+		Limit(limit).
+		Find(&bookings).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch upcoming bookings", err)
+	}
+	return bookings, nil
+}
+
+// GetBookingByID returns a specific booking, which may be archived
+func (s *bookingRepository) GetBookingByID(ctx context.Context, bookingID, userID uint) (*entities.Booking, error) {
 	var booking entities.Booking
 
-	if err := s.db.WithContext(ctx).
-		Preload("Event.Venue").
-		Preload("Event").
-		Preload("Seat").
+	if err := bookingHistory(conn(ctx, s.db)).Scopes(bookingRelations(ctx)).
 		Where("id = ? AND user_id = ?", bookingID, userID).
 		First(&booking).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -515,59 +1112,13 @@ func (s *BookingRepository) GetBookingByID(ctx context.Context, bookingID, userI
 	return &booking, nil
 }
 
-// CleanupExpiredIntents removes expired booking intents and unlocks seats
-func (s *BookingRepository) CleanupExpiredIntents(ctx context.Context) error {
-	// Start transaction
-	tx := s.db.WithContext(ctx).Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
-	// Find expired intents
-	var expiredIntents []entities.BookingIntent
-	if err := tx.Where("status = ? AND lock_expires_at < ?",
-		constants.IntentStatusPending, time.Now()).
-		Find(&expiredIntents).Error; err != nil {
-		tx.Rollback()
-		return errors.NewInternalError("Failed to fetch expired intents", err)
-	}
-
-	// Update expired intents
-	if len(expiredIntents) > 0 {
-		intentIDs := make([]uint, len(expiredIntents))
-		seatIDs := make([]uint, len(expiredIntents))
-
-		for i, intent := range expiredIntents {
-			intentIDs[i] = intent.ID
-			seatIDs[i] = intent.SeatID
-
-			// Unlock in Redis using intent ID
-			intentIDStr := fmt.Sprintf("%d", intent.ID)
-			s.seatLockRepository.UnlockSeat(ctx, intent.SeatID, intent.UserID, intentIDStr)
-		}
-
-		// Update intent statuses
-		if err := tx.Model(&entities.BookingIntent{}).
-			Where("id IN ?", intentIDs).
-			Update("status", constants.IntentStatusExpired).Error; err != nil {
-			tx.Rollback()
-			return errors.NewInternalError("Failed to update expired intents", err)
-		}
-
-		// Unlock seats
-		if err := tx.Model(&entities.Seat{}).
-			Where("id IN ?", seatIDs).
-			Updates(map[string]interface{}{
-				"is_locked": false,
-				"locked_at": nil,
-				"locked_by": nil,
-			}).Error; err != nil {
-			tx.Rollback()
-			return errors.NewInternalError("Failed to unlock seats", err)
-		}
+// BackfillLockExpiry sets the lock expiry of intents created before it was stored, from their
+// creation time as it used to be derived
+func (s *bookingRepository) BackfillLockExpiry(ctx context.Context) error {
+	if err := conn(ctx, s.db).Model(&entities.BookingIntent{}).
+		Where("lock_expires_at IS NULL").
+		UpdateColumn("lock_expires_at", gorm.Expr("created_at + make_interval(mins => ?)", constants.SeatLockDuration)).Error; err != nil {
+		return errors.NewInternalError("Failed to backfill intent lock expiry", err)
 	}
-
-	return tx.Commit().Error
+	return nil
 }
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/tenant"
+	"api/pkg/errors"
+	"api/pkg/money"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestSeatLookupsAreTenantScoped checks against a scratch Postgres database that a tenant admin
+// can't read another organizer's seat or its lock holder. It needs TEST_DATABASE_URL.
+func TestSeatLookupsAreTenantScoped(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	registerTestKeyring(t)
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger:                                   logger.Discard,
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&entities.Event{}, &entities.Seat{}, &entities.BookingIntent{}); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Add(30 * 24 * time.Hour)
+	event := entities.Event{TenantID: 9001, Name: "Tenant scope " + time.Now().Format("150405.000000"), VenueID: 1,
+		StartTime: start, EndTime: start.Add(3 * time.Hour), Price: money.FromMajor(25),
+		EventType: constants.EventTypeConcert, Status: constants.EventStatusActive}
+	if err := db.Create(&event).Error; err != nil {
+		t.Fatal(err)
+	}
+	seat := entities.Seat{EventID: event.ID, Row: 1, Column: 1, SeatType: constants.SeatTypeStandard, Price: event.Price, IsAvailable: true}
+	if err := db.Create(&seat).Error; err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	intent := entities.BookingIntent{UserID: 1, EventID: event.ID, SeatID: seat.ID, Status: constants.IntentStatusPending,
+		LockExpiresAt: now.Add(time.Minute), CreatedAt: now}
+	if err := db.Create(&intent).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewBookingRepository(db)
+	owner := tenant.WithTenant(context.Background(), event.TenantID)
+	if _, err := repo.GetSeat(owner, seat.ID); err != nil {
+		t.Fatalf("GetSeat for the owning tenant returned %v", err)
+	}
+	if _, err := repo.GetLatestSeatIntent(owner, seat.ID); err != nil {
+		t.Fatalf("GetLatestSeatIntent for the owning tenant returned %v", err)
+	}
+
+	other := tenant.WithTenant(context.Background(), event.TenantID+1)
+	if _, err := repo.GetSeat(other, seat.ID); !isNotFound(err) {
+		t.Errorf("GetSeat for another tenant returned %v, want not found", err)
+	}
+	if _, err := repo.GetLatestSeatIntent(other, seat.ID); !isNotFound(err) {
+		t.Errorf("GetLatestSeatIntent for another tenant returned %v, want not found", err)
+	}
+}
+
+func isNotFound(err error) bool {
+	appErr, ok := err.(*errors.AppError)
+	return ok && appErr.Type == "NOT_FOUND"
+}
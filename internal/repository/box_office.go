@@ -0,0 +1,335 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BoxOfficeRepository records the seats staff sell at the door
+type BoxOfficeRepository struct {
+	db *gorm.DB
+}
+
+func NewBoxOfficeRepository(db *gorm.DB) *BoxOfficeRepository {
+	return &BoxOfficeRepository{db: db}
+}
+
+// SellAtDoor sells a seat of an event in box-office mode as a confirmed door booking. Unlike
+// online sales it is open past the sales cutoff and while the event runs, until it ends.
+func (r *BoxOfficeRepository) SellAtDoor(ctx context.Context, eventID uint, sale entities.DoorSale, now time.Time) (*entities.Booking, error) {
+	var booking *entities.Booking
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var event entities.Event
+		if err := tx.Scopes(tenantScope(ctx, "events")).
+			Select("id", "tenant_id", "status", "end_time", "door_sales", "sandbox").
+			First(&event, eventID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewNotFoundError("Event not found", errors.ErrRecordNotFound)
+			}
+			return errors.NewInternalError("Failed to fetch event", err)
+		}
+		if !event.DoorSales {
+			return errors.NewBadRequestError(constants.ErrDoorSalesDisabled, nil)
+		}
+		if event.Status != constants.EventStatusActive {
+			return errors.NewBadRequestError("Event is not active", nil)
+		}
+		if !now.Before(event.EndTime) {
+			return errors.NewBadRequestError("Event has already ended", nil)
+		}
+
+		seat, err := doorSeat(tx, eventID, sale)
+		if err != nil {
+			return err
+		}
+
+		// Walk-up buyers without an account are booked under the staff member who sold the seat
+		userID := sale.SoldBy
+		if sale.UserID != nil {
+			var userCount int64
+			if err := tx.Model(&entities.User{}).Where("id = ?", *sale.UserID).Count(&userCount).Error; err != nil {
+				return errors.NewInternalError("Failed to fetch user", err)
+			}
+			if userCount == 0 {
+				return errors.NewNotFoundError("User not found", errors.ErrUserNotFound)
+			}
+			userID = *sale.UserID
+		}
+
+		// An amount given by staff overrides the seat price and any pricing rule behind it
+		amount, pricingRuleID := seat.Price, seat.PricingRuleID
+		if sale.Amount != nil {
+			amount, pricingRuleID = *sale.Amount, nil
+		}
+		paymentStatus := constants.PaymentStatusPaid
+		if event.Sandbox {
+			paymentStatus = constants.PaymentStatusSandbox
+		}
+
+		booking = &entities.Booking{
+			TenantID:      event.TenantID,
+			UserID:        userID,
+			EventID:       eventID,
+			SeatID:        seat.ID,
+			Status:        constants.BookingStatusConfirmed,
+			PaymentStatus: paymentStatus,
+			TotalAmount:   amount,
+			SeatPrice:     amount,
+			PricingRuleID: pricingRuleID,
+			Sandbox:       event.Sandbox,
+			Channel:       constants.SalesChannelDoor,
+			BookedAt:      now,
+		}
+		if sale.CheckIn {
+			booking.CheckedInAt = &now
+		}
+		if err := collectAttendeeDetails(tx, eventID, sale.Attendee, booking); err != nil {
+			return err
+		}
+
+		reference := strings.TrimSpace(sale.Reference)
+		if reference != "" {
+			var referenceCount int64
+			if err := tx.Model(&entities.PaymentTransaction{}).
+				Where("provider = ? AND provider_reference = ?", constants.BoxOfficeProvider, reference).
+				Count(&referenceCount).Error; err != nil {
+				return errors.NewInternalError("Failed to check payment reference", err)
+			}
+			if referenceCount > 0 {
+				return errors.NewConflictError("Payment reference has already been used", nil)
+			}
+		}
+		booking.PaymentID = reference
+
+		if err := tx.Create(booking).Error; err != nil {
+			return errors.NewInternalError("Failed to create booking", err)
+		}
+		if err := postBookingSale(tx, booking); err != nil {
+			return err
+		}
+
+		// Keep a reconciliation record of the takings, referenced by booking without a receipt
+		if !event.Sandbox {
+			if reference == "" {
+				reference = fmt.Sprintf("door_%d", booking.ID)
+				if err := tx.Model(booking).Update("payment_id", reference).Error; err != nil {
+					return errors.NewInternalError("Failed to update booking", err)
+				}
+			}
+			currency := strings.ToUpper(sale.Currency)
+			if currency == "" {
+				currency = constants.DefaultCurrency
+			}
+			if err := createPaymentTransaction(tx, &entities.PaymentTransaction{
+				BookingID:         &booking.ID,
+				Provider:          constants.BoxOfficeProvider,
+				ProviderReference: reference,
+				Amount:            amount,
+				Currency:          currency,
+				MethodType:        sale.PaymentMethod,
+				MaskedMethod:      sale.PaymentMethod,
+				Status:            constants.PaymentStatusPaid,
+			}); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&entities.Seat{}).Where("id = ?", seat.ID).
+			Updates(map[string]interface{}{
+				"is_available": false,
+				"updated_at":   now,
+			}).Error; err != nil {
+			return errors.NewInternalError("Failed to update seat", err)
+		}
+
+		result := tx.Model(&entities.Event{}).
+			Where("id = ? AND available_seats > 0", eventID).
+			Update("available_seats", gorm.Expr("available_seats - ?", 1))
+		if result.Error != nil {
+			return errors.NewInternalError("Failed to update event capacity", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errors.NewBadRequestError(constants.ErrEventSoldOut, nil)
+		}
+
+		booking.Seat = *seat
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// doorSeat locks the seat a door sale is for: the one asked for, or else the best released seat
+// that nobody is checking out, front rows first. Companion seats are only sold when asked for.
+func doorSeat(tx *gorm.DB, eventID uint, sale entities.DoorSale) (*entities.Seat, error) {
+	var seat entities.Seat
+	if sale.SeatID != 0 {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND event_id = ?", sale.SeatID, eventID).
+			First(&seat).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.NewNotFoundError("Seat not found", errors.ErrRecordNotFound)
+			}
+			return nil, errors.NewInternalError("Failed to fetch seat", err)
+		}
+		if !seat.IsAvailable {
+			return nil, errors.NewBadRequestError(constants.ErrSeatNotAvailable, nil)
+		}
+		if seat.IsHeld {
+			return nil, errors.NewBadRequestError(constants.ErrSeatNotReleased, nil)
+		}
+		if seat.IsOversell {
+			return nil, errors.NewBadRequestError(constants.ErrOversellSeat, nil)
+		}
+		if seat.IsLocked {
+			return nil, errors.NewConflictError(constants.ErrSeatAlreadyLocked, nil)
+		}
+		return &seat, nil
+	}
+
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("event_id = ? AND is_available = true AND is_held = false AND is_locked = false AND is_companion = false AND is_oversell = false", eventID)
+	if len(sale.ExcludeSeatIDs) > 0 {
+		query = query.Where("id NOT IN ?", sale.ExcludeSeatIDs)
+	}
+	if err := query.Order("\"row\" ASC, \"column\" ASC").First(&seat).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewBadRequestError(constants.ErrEventSoldOut, nil)
+		}
+		return nil, errors.NewInternalError("Failed to fetch seat", err)
+	}
+	return &seat, nil
+}
+
+// IssueComps books complimentary tickets for specific seats of an event, all or none. Seats
+// held back for a release wave can be comped too; they never counted as available.
+func (r *BoxOfficeRepository) IssueComps(ctx context.Context, eventID uint, comp entities.CompIssue, now time.Time) ([]entities.Booking, error) {
+	var bookings []entities.Booking
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var event entities.Event
+		if err := tx.Scopes(tenantScope(ctx, "events")).
+			Select("id", "tenant_id", "status", "end_time", "sandbox").
+			First(&event, eventID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewNotFoundError("Event not found", errors.ErrRecordNotFound)
+			}
+			return errors.NewInternalError("Failed to fetch event", err)
+		}
+		if event.Status != constants.EventStatusActive {
+			return errors.NewBadRequestError("Event is not active", nil)
+		}
+		if !now.Before(event.EndTime) {
+			return errors.NewBadRequestError("Event has already ended", nil)
+		}
+
+		var seats []entities.Seat
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND event_id = ?", comp.SeatIDs, eventID).
+			Order("id ASC").
+			Find(&seats).Error; err != nil {
+			return errors.NewInternalError("Failed to fetch seats", err)
+		}
+		if len(seats) != len(comp.SeatIDs) {
+			return errors.NewNotFoundError("Seat not found", errors.ErrRecordNotFound)
+		}
+		var released int
+		for _, seat := range seats {
+			if !seat.IsAvailable {
+				return errors.NewBadRequestError(fmt.Sprintf("seat %d is not available", seat.ID), nil)
+			}
+			if seat.IsLocked {
+				return errors.NewConflictError(fmt.Sprintf("seat %d is locked by another user", seat.ID), nil)
+			}
+			if seat.IsOversell {
+				return errors.NewBadRequestError(constants.ErrOversellSeat, nil)
+			}
+			if !seat.IsHeld {
+				released++
+			}
+		}
+
+		// Guests without an account get their tickets through the admin who issued them
+		userID := comp.IssuedBy
+		if comp.UserID != nil {
+			var userCount int64
+			if err := tx.Model(&entities.User{}).Where("id = ?", *comp.UserID).Count(&userCount).Error; err != nil {
+				return errors.NewInternalError("Failed to fetch user", err)
+			}
+			if userCount == 0 {
+				return errors.NewNotFoundError("User not found", errors.ErrUserNotFound)
+			}
+			userID = *comp.UserID
+		}
+
+		bookings = make([]entities.Booking, len(seats))
+		for i, seat := range seats {
+			bookings[i] = entities.Booking{
+				TenantID:      event.TenantID,
+				UserID:        userID,
+				EventID:       eventID,
+				SeatID:        seat.ID,
+				Status:        constants.BookingStatusConfirmed,
+				PaymentStatus: constants.PaymentStatusComp,
+				AttendeeName:  comp.AttendeeName,
+				Sandbox:       event.Sandbox,
+				Channel:       constants.SalesChannelComp,
+				CompReason:    comp.Reason,
+				BookedAt:      now,
+			}
+		}
+		if err := tx.Create(&bookings).Error; err != nil {
+			return errors.NewInternalError("Failed to create bookings", err)
+		}
+
+		if err := tx.Model(&entities.Seat{}).Where("id IN ?", comp.SeatIDs).
+			Updates(map[string]interface{}{
+				"is_available": false,
+				"updated_at":   now,
+			}).Error; err != nil {
+			return errors.NewInternalError("Failed to update seats", err)
+		}
+
+		if released > 0 {
+			result := tx.Model(&entities.Event{}).
+				Where("id = ? AND available_seats >= ?", eventID, released).
+				Update("available_seats", gorm.Expr("available_seats - ?", released))
+			if result.Error != nil {
+				return errors.NewInternalError("Failed to update event capacity", result.Error)
+			}
+			if result.RowsAffected == 0 {
+				return errors.NewBadRequestError(constants.ErrInsufficientSeats, nil)
+			}
+		}
+
+		for i := range bookings {
+			bookings[i].Seat = seats[i]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// ListComps returns the complimentary tickets issued for an event, latest first
+func (r *BoxOfficeRepository) ListComps(ctx context.Context, eventID uint) ([]entities.Booking, error) {
+	var bookings []entities.Booking
+	if err := conn(ctx, r.db).Scopes(tenantScope(ctx, "bookings")).Preload("Seat").
+		Where("event_id = ? AND channel = ?", eventID, constants.SalesChannelComp).
+		Order("booked_at DESC, id DESC").
+		Find(&bookings).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch comps", err)
+	}
+	return bookings, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// catalogSyncLockKey is the Postgres advisory lock serializing catalog syncs across instances
+const catalogSyncLockKey = 7_246_001
+
+// CatalogRepository maps a partner's catalog feed onto local venues and events, which the
+// ImportRepository writes, and records the syncs
+type CatalogRepository struct {
+	db *gorm.DB
+}
+
+func NewCatalogRepository(db *gorm.DB) *CatalogRepository {
+	return &CatalogRepository{db: db}
+}
+
+// LockSync waits for other instances' syncs to finish. It must run in a unit of work, whose
+// transaction holds the lock until it ends.
+func (r *CatalogRepository) LockSync(ctx context.Context) error {
+	if err := conn(ctx, r.db).Exec("SELECT pg_advisory_xact_lock(?)", catalogSyncLockKey).Error; err != nil {
+		return errors.NewInternalError("Failed to lock catalog sync", err)
+	}
+	return nil
+}
+
+// FindVenueByExternalID returns the venue synced from the feed entry, or nil if none was
+func (r *CatalogRepository) FindVenueByExternalID(ctx context.Context, source, externalID string) (*entities.Venue, error) {
+	var venue entities.Venue
+
+	if err := conn(ctx, r.db).Scopes(tenantScope(ctx, "venues")).
+		Preload("Sections", func(db *gorm.DB) *gorm.DB { return db.Order("row_start ASC") }).
+		Where("external_source = ? AND external_id = ?", source, externalID).
+		First(&venue).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, errors.NewInternalError("Failed to fetch venue", err)
+	}
+
+	return &venue, nil
+}
+
+// FindEventByExternalID returns the event synced from the feed entry, or nil if none was
+func (r *CatalogRepository) FindEventByExternalID(ctx context.Context, source, externalID string) (*entities.Event, error) {
+	var event entities.Event
+
+	if err := conn(ctx, r.db).Scopes(tenantScope(ctx, "events")).
+		Where("external_source = ? AND external_id = ?", source, externalID).
+		First(&event).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, errors.NewInternalError("Failed to fetch event", err)
+	}
+
+	return &event, nil
+}
+
+// LinkVenue stores the feed key of an existing venue adopted by the sync
+func (r *CatalogRepository) LinkVenue(ctx context.Context, venue *entities.Venue) error {
+	if err := conn(ctx, r.db).Model(&entities.Venue{ID: venue.ID}).
+		Select("external_source", "external_id").
+		Updates(venue).Error; err != nil {
+		return errors.NewInternalError("Failed to link venue", err)
+	}
+	return nil
+}
+
+// UpdateEventKeys stores the fields the CSV import matches events on, which the feed may
+// change: the name and start time, and the feed key of an adopted event
+func (r *CatalogRepository) UpdateEventKeys(ctx context.Context, event *entities.Event) error {
+	if err := conn(ctx, r.db).Model(&entities.Event{ID: event.ID}).
+		Select("name", "start_time", "external_source", "external_id").
+		Updates(event).Error; err != nil {
+		return errors.NewInternalError("Failed to update event", err)
+	}
+	return nil
+}
+
+// CreateRun records a finished sync
+func (r *CatalogRepository) CreateRun(ctx context.Context, run *entities.CatalogSyncRun) error {
+	if err := conn(ctx, r.db).Create(run).Error; err != nil {
+		return errors.NewInternalError("Failed to record catalog sync", err)
+	}
+	return nil
+}
+
+// ListRuns returns the latest syncs of the source, newest first
+func (r *CatalogRepository) ListRuns(ctx context.Context, source string, limit int) ([]entities.CatalogSyncRun, error) {
+	var runs []entities.CatalogSyncRun
+
+	if err := conn(ctx, r.db).Where("source = ?", source).
+		Order("started_at DESC").Limit(limit).
+		Find(&runs).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch catalog syncs", err)
+	}
+
+	return runs, nil
+}
+
+// LastSuccessfulRun returns the latest sync of the source that succeeded, or nil if none has
+func (r *CatalogRepository) LastSuccessfulRun(ctx context.Context, source string) (*entities.CatalogSyncRun, error) {
+	var run entities.CatalogSyncRun
+
+	if err := conn(ctx, r.db).Where("source = ? AND status = ?", source, constants.CatalogSyncSucceeded).
+		Order("started_at DESC").
+		First(&run).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, errors.NewInternalError("Failed to fetch catalog sync", err)
+	}
+
+	return &run, nil
+}
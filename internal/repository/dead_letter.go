@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DeadLetterRepository stores failed async work for the platform admins' retry console. Dead
+// letters aren't tenant scoped: only platform admins see them.
+type DeadLetterRepository struct {
+	db *gorm.DB
+}
+
+func NewDeadLetterRepository(db *gorm.DB) *DeadLetterRepository {
+	return &DeadLetterRepository{db: db}
+}
+
+// Create records failed work as dead
+func (s *DeadLetterRepository) Create(ctx context.Context, letter *entities.DeadLetter) error {
+	letter.Status = constants.DeadLetterStatusDead
+	if err := conn(ctx, s.db).Create(letter).Error; err != nil {
+		return errors.NewInternalError("Failed to record dead letter", err)
+	}
+	return nil
+}
+
+// GetByID returns a dead letter with its payload
+func (s *DeadLetterRepository) GetByID(ctx context.Context, letterID uint) (*entities.DeadLetter, error) {
+	var letter entities.DeadLetter
+	if err := conn(ctx, s.db).First(&letter, letterID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Dead letter not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch dead letter", err)
+	}
+	return &letter, nil
+}
+
+// List returns dead letters newest first, optionally filtered by source and status, without
+// their payloads
+func (s *DeadLetterRepository) List(ctx context.Context, source, status string, limit, offset int) ([]entities.DeadLetter, int64, error) {
+	var letters []entities.DeadLetter
+	var total int64
+
+	query := conn(ctx, s.db).Model(&entities.DeadLetter{})
+	if source != "" {
+		query = query.Where("source = ?", source)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to count dead letters", err)
+	}
+
+	if err := query.Omit("payload").Order("id DESC").Limit(limit).Offset(offset).Find(&letters).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to fetch dead letters", err)
+	}
+
+	return letters, total, nil
+}
+
+// CountSince counts the dead letters recorded since the given time
+func (s *DeadLetterRepository) CountSince(ctx context.Context, since time.Time) (int64, error) {
+	var count int64
+	if err := conn(ctx, s.db).Model(&entities.DeadLetter{}).
+		Where("created_at >= ?", since).
+		Count(&count).Error; err != nil {
+		return 0, errors.NewInternalError("Failed to count dead letters", err)
+	}
+	return count, nil
+}
+
+// Resolve moves a dead letter to the retried or discarded status. Letters that aren't dead any
+// more, e.g. retried by another admin meanwhile, are a conflict.
+func (s *DeadLetterRepository) Resolve(ctx context.Context, letterID uint, status string, retried bool) (*entities.DeadLetter, error) {
+	now := time.Now()
+	updates := map[string]interface{}{"status": status, "resolved_at": now}
+	if retried {
+		updates["retries"] = gorm.Expr("retries + 1")
+		updates["last_retried_at"] = now
+	}
+	return s.update(ctx, letterID, updates)
+}
+
+// RecordRetryFailure counts a failed retry of a dead letter, which stays dead
+func (s *DeadLetterRepository) RecordRetryFailure(ctx context.Context, letterID uint, message string) (*entities.DeadLetter, error) {
+	return s.update(ctx, letterID, map[string]interface{}{
+		"retries":         gorm.Expr("retries + 1"),
+		"last_retried_at": time.Now(),
+		"error":           message,
+	})
+}
+
+// update changes a dead letter that is still dead and returns it
+func (s *DeadLetterRepository) update(ctx context.Context, letterID uint, updates map[string]interface{}) (*entities.DeadLetter, error) {
+	result := conn(ctx, s.db).Model(&entities.DeadLetter{}).
+		Where("id = ? AND status = ?", letterID, constants.DeadLetterStatusDead).
+		Updates(updates)
+	if result.Error != nil {
+		return nil, errors.NewInternalError("Failed to update dead letter", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		if _, err := s.GetByID(ctx, letterID); err != nil {
+			return nil, err
+		}
+		return nil, errors.NewConflictError("Dead letter was already retried or discarded", nil)
+	}
+	return s.GetByID(ctx, letterID)
+}
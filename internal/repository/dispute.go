@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DisputeRepository struct {
+	db *gorm.DB
+}
+
+func NewDisputeRepository(db *gorm.DB) *DisputeRepository {
+	return &DisputeRepository{db: db}
+}
+
+// RecordDisputeUpdate creates or advances a dispute and flags the booking paid with the disputed payment.
+// It returns whether the update opened a new dispute and whether it changed anything at all, so
+// redelivered webhooks are no-ops.
+func (s *DisputeRepository) RecordDisputeUpdate(ctx context.Context, update entities.DisputeUpdate) (*entities.Dispute, bool, bool, error) {
+	var dispute entities.Dispute
+	opened := false
+	changed := false
+
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("provider_dispute_id = ?", update.ProviderDisputeID).
+			First(&dispute).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return errors.NewInternalError("Failed to fetch dispute", err)
+		}
+
+		if err == gorm.ErrRecordNotFound {
+			var booking entities.Booking
+			if err := tx.Where("payment_id = ?", update.PaymentID).First(&booking).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return errors.NewNotFoundError("No booking found for disputed payment", errors.ErrRecordNotFound)
+				}
+				return errors.NewInternalError("Failed to fetch booking", err)
+			}
+
+			dispute = entities.Dispute{
+				ProviderDisputeID: update.ProviderDisputeID,
+				BookingID:         booking.ID,
+				PaymentID:         update.PaymentID,
+				Status:            update.Status,
+				Reason:            update.Reason,
+				Amount:            update.Amount,
+				OpenedAt:          update.OccurredAt,
+			}
+			// The first delivery can already carry the outcome
+			if update.Status == constants.DisputeStatusWon || update.Status == constants.DisputeStatusLost {
+				dispute.ClosedAt = &update.OccurredAt
+			}
+			if err := tx.Create(&dispute).Error; err != nil {
+				return errors.NewInternalError("Failed to create dispute", err)
+			}
+			opened = true
+		} else {
+			if dispute.Status == update.Status {
+				return nil
+			}
+			if dispute.ClosedAt != nil {
+				return errors.NewConflictError("Dispute is already closed", nil)
+			}
+
+			updates := map[string]interface{}{"status": update.Status}
+			if update.Reason != "" {
+				updates["reason"] = update.Reason
+			}
+			if update.Status == constants.DisputeStatusWon || update.Status == constants.DisputeStatusLost {
+				updates["closed_at"] = update.OccurredAt
+			}
+			if err := tx.Model(&dispute).Updates(updates).Error; err != nil {
+				return errors.NewInternalError("Failed to update dispute", err)
+			}
+		}
+		changed = true
+
+		event := entities.DisputeEvent{
+			DisputeID:  dispute.ID,
+			Status:     update.Status,
+			OccurredAt: update.OccurredAt,
+		}
+		if err := tx.Create(&event).Error; err != nil {
+			return errors.NewInternalError("Failed to record dispute event", err)
+		}
+
+		// A won dispute clears the flag and reinstates the ticket; otherwise the booking stays flagged
+		bookingUpdates := map[string]interface{}{"disputed": true}
+		if update.Status == constants.DisputeStatusWon {
+			bookingUpdates = map[string]interface{}{"disputed": false, "ticket_revoked_at": nil}
+		}
+		if err := tx.Model(&entities.Booking{}).Where("id = ?", dispute.BookingID).
+			Updates(bookingUpdates).Error; err != nil {
+			return errors.NewInternalError("Failed to flag booking", err)
+		}
+
+		return transitionPaymentTransaction(tx, dispute.BookingID, disputePaymentStatus(update.Status), update.OccurredAt)
+	})
+	if err != nil {
+		return nil, false, false, err
+	}
+
+	return &dispute, opened, changed, nil
+}
+
+// disputePaymentStatus is the status of a disputed payment's transaction: disputed while the
+// dispute is open, paid again once won and charged back once lost
+func disputePaymentStatus(status string) string {
+	switch status {
+	case constants.DisputeStatusWon:
+		return constants.PaymentStatusPaid
+	case constants.DisputeStatusLost:
+		return constants.PaymentStatusChargedBack
+	}
+	return constants.PaymentStatusDisputed
+}
+
+// RevokeTicket marks a booking's ticket as no longer valid for entry
+func (s *DisputeRepository) RevokeTicket(ctx context.Context, bookingID uint) error {
+	if err := conn(ctx, s.db).Model(&entities.Booking{}).
+		Where("id = ? AND ticket_revoked_at IS NULL", bookingID).
+		Update("ticket_revoked_at", time.Now()).Error; err != nil {
+		return errors.NewInternalError("Failed to revoke ticket", err)
+	}
+	return nil
+}
+
+// ListDisputes returns disputes with their lifecycle events, optionally filtered by status
+func (s *DisputeRepository) ListDisputes(ctx context.Context, status string, limit, offset int) ([]entities.Dispute, int64, error) {
+	var disputes []entities.Dispute
+	var total int64
+
+	query := conn(ctx, s.db).Model(&entities.Dispute{}).Scopes(tenantScopeVia(ctx, "disputes.booking_id", "bookings"))
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to count disputes", err)
+	}
+
+	if err := query.
+		Preload("Booking").
+		Preload("Events", func(db *gorm.DB) *gorm.DB { return db.Order("occurred_at ASC") }).
+		Order("opened_at DESC").
+		Limit(limit).Offset(offset).
+		Find(&disputes).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to fetch disputes", err)
+	}
+
+	return disputes, total, nil
+}
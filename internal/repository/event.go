@@ -3,32 +3,67 @@ package repository
 import (
 	"api/constants"
 	"api/internal/entities"
+	"api/internal/expand"
 	"api/pkg/errors"
+	"api/pkg/money"
 	"context"
+	"sort"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-type EventRepository struct {
+// seatInsertBatchSize is the number of seats per INSERT; at 16 columns a batch stays well
+// under Postgres' limit of 65535 bind parameters per statement
+const seatInsertBatchSize = 2000
+
+// SeatProgressFunc receives the number of seats created so far out of an event's total
+type SeatProgressFunc func(created, total int)
+
+type EventRepository interface {
+	GetEvents(ctx context.Context, limit, offset int, eventType, city string, metadata map[string]string) ([]entities.Event, int64, error)
+	GetEventByID(ctx context.Context, eventID uint) (*entities.Event, error)
+	GetAvailableSeats(ctx context.Context, eventID uint, filter entities.SeatFilter) ([]entities.Seat, error)
+	CountAvailableSeats(ctx context.Context, eventID uint) (int64, error)
+	ListHotEvents(ctx context.Context, onSaleBefore time.Time, limit int) ([]uint, error)
+	PrepareEvent(ctx context.Context, event *entities.Event) (*entities.Venue, error)
+	CreateEvent(ctx context.Context, event *entities.Event, progress SeatProgressFunc) error
+	NewSandboxEvent(ctx context.Context, eventID uint) (*entities.Event, error)
+	UpdateEvent(ctx context.Context, eventID uint, updates map[string]interface{}) (*entities.Event, error)
+	DeleteEvent(ctx context.Context, eventID uint) error
+	GetEventStats(ctx context.Context, eventID uint) (map[string]interface{}, error)
+	SetSeatAccessibility(ctx context.Context, seatID uint, accessible bool, companionSeatIDs []uint) (*entities.Seat, []entities.Seat, error)
+	ReleaseSeats(ctx context.Context, eventID uint, rowStart, rowEnd int, releasedBy uint) (*entities.SeatRelease, error)
+	ListReleases(ctx context.Context, eventID uint) ([]entities.SeatRelease, int64, error)
+	UpdateSeatPrices(ctx context.Context, eventID uint, change entities.SeatPriceChange) ([]entities.SeatPriceHistory, error)
+	ListSeatPriceHistory(ctx context.Context, seatID uint) ([]entities.SeatPriceHistory, error)
+}
+
+type eventRepository struct {
 	db *gorm.DB
 }
 
-func NewEventRepository(db *gorm.DB) *EventRepository {
-	return &EventRepository{db: db}
+func NewEventRepository(db *gorm.DB) EventRepository {
+	return &eventRepository{db: db}
 }
 
 // GetEvents returns a paginated list of events
-func (s *EventRepository) GetEvents(ctx context.Context, limit, offset int, eventType, city string) ([]entities.Event, int64, error) {
+func (s *eventRepository) GetEvents(ctx context.Context, limit, offset int, eventType, city string, metadata map[string]string) ([]entities.Event, int64, error) {
 	var events []entities.Event
 	var total int64
 
-	query := s.db.WithContext(ctx).Model(&entities.Event{}).
-		Where("status = ? AND start_time > ?", constants.EventStatusActive, time.Now()).
-		Preload("Venue")
+	query := conn(ctx, s.db).Model(&entities.Event{}).Scopes(tenantScope(ctx, "events"), metadataScope("events", metadata)).
+		Where("events.status = ? AND events.start_time > ? AND events.sandbox = false", constants.EventStatusActive, time.Now())
+	if expand.Includes(ctx, expand.Venue) {
+		query = query.Preload("Venue")
+	}
+	if expand.Includes(ctx, expand.Organizer) {
+		query = query.Preload("Tenant")
+	}
 
 	if eventType != "" {
-		query = query.Where("event_type = ?", eventType)
+		query = query.Where("events.event_type = ?", eventType)
 	}
 
 	if city != "" {
@@ -42,7 +77,7 @@ func (s *EventRepository) GetEvents(ctx context.Context, limit, offset int, even
 	}
 
 	// Get paginated results
-	if err := query.Order("start_time ASC").
+	if err := query.Order("events.start_time ASC").
 		Limit(limit).Offset(offset).
 		Find(&events).Error; err != nil {
 		return nil, 0, errors.NewInternalError("Failed to fetch events", err)
@@ -51,14 +86,21 @@ func (s *EventRepository) GetEvents(ctx context.Context, limit, offset int, even
 	return events, total, nil
 }
 
-// GetEventByID returns a single event with all details
-func (s *EventRepository) GetEventByID(ctx context.Context, eventID uint) (*entities.Event, error) {
+// GetEventByID returns a single event with all details, less the relations ctx leaves out
+func (s *eventRepository) GetEventByID(ctx context.Context, eventID uint) (*entities.Event, error) {
 	var event entities.Event
 
-	if err := s.db.WithContext(ctx).
-		Preload("Venue").
-		Preload("Seats", "is_available = true").
-		First(&event, eventID).Error; err != nil {
+	query := conn(ctx, s.db).Scopes(tenantScope(ctx, "events"))
+	if expand.Includes(ctx, expand.Venue) {
+		query = query.Preload("Venue")
+	}
+	if expand.Includes(ctx, expand.Organizer) {
+		query = query.Preload("Tenant")
+	}
+	if expand.Includes(ctx, expand.Seats) {
+		query = query.Preload("Seats", "is_available = true AND is_held = false AND is_oversell = false")
+	}
+	if err := query.First(&event, eventID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.NewNotFoundError("Event not found", errors.ErrRecordNotFound)
 		}
@@ -68,12 +110,20 @@ func (s *EventRepository) GetEventByID(ctx context.Context, eventID uint) (*enti
 	return &event, nil
 }
 
-// GetAvailableSeats returns available seats for an event
-func (s *EventRepository) GetAvailableSeats(ctx context.Context, eventID uint) ([]entities.Seat, error) {
+// GetAvailableSeats returns available seats for an event, optionally narrowed to accessible or companion seats
+func (s *eventRepository) GetAvailableSeats(ctx context.Context, eventID uint, filter entities.SeatFilter) ([]entities.Seat, error) {
 	var seats []entities.Seat
 
-	if err := s.db.WithContext(ctx).
-		Where("event_id = ? AND is_available = true AND is_locked = false", eventID).
+	query := conn(ctx, s.db).
+		Where("event_id = ? AND is_available = true AND is_locked = false AND is_held = false AND is_oversell = false", eventID)
+	if filter.Accessible != nil {
+		query = query.Where("is_accessible = ?", *filter.Accessible)
+	}
+	if filter.Companion != nil {
+		query = query.Where("is_companion = ?", *filter.Companion)
+	}
+
+	if err := query.
 		Order("\"row\" ASC, \"column\" ASC").
 		Find(&seats).Error; err != nil {
 		return nil, errors.NewInternalError("Failed to fetch available seats", err)
@@ -83,11 +133,11 @@ func (s *EventRepository) GetAvailableSeats(ctx context.Context, eventID uint) (
 }
 
 // CountAvailableSeats returns the count of available seats for an event
-func (s *EventRepository) CountAvailableSeats(ctx context.Context, eventID uint) (int64, error) {
+func (s *eventRepository) CountAvailableSeats(ctx context.Context, eventID uint) (int64, error) {
 	var count int64
 
-	if err := s.db.WithContext(ctx).Model(&entities.Seat{}).
-		Where("event_id = ? AND is_available = true AND is_locked = false", eventID).
+	if err := conn(ctx, s.db).Model(&entities.Seat{}).
+		Where("event_id = ? AND is_available = true AND is_locked = false AND is_held = false AND is_oversell = false", eventID).
 		Count(&count).Error; err != nil {
 		return 0, errors.NewInternalError("Failed to count available seats", err)
 	}
@@ -95,58 +145,143 @@ func (s *EventRepository) CountAvailableSeats(ctx context.Context, eventID uint)
 	return count, nil
 }
 
-// CreateEvent creates a new event (admin only)
-func (s *EventRepository) CreateEvent(ctx context.Context, event *entities.Event) error {
+// ListHotEvents returns the IDs of listed events whose reads spike: upcoming high-demand
+// events, and events whose on-sale or presale opens before onSaleBefore. Those opening soonest
+// come first.
+func (s *eventRepository) ListHotEvents(ctx context.Context, onSaleBefore time.Time, limit int) ([]uint, error) {
+	var eventIDs []uint
+	now := time.Now()
+
+	if err := conn(ctx, s.db).Model(&entities.Event{}).Scopes(tenantScope(ctx, "events")).
+		Where("status = ? AND start_time > ? AND sandbox = false", constants.EventStatusActive, now).
+		Where("is_high_demand = true OR (on_sale_at > ? AND on_sale_at <= ?) OR (early_access_at > ? AND early_access_at <= ?)",
+			now, onSaleBefore, now, onSaleBefore).
+		Order("COALESCE(early_access_at, on_sale_at, start_time) ASC").
+		Limit(limit).
+		Pluck("id", &eventIDs).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch hot events", err)
+	}
+
+	return eventIDs, nil
+}
+
+// PrepareEvent checks a new event against its venue, times and metadata schema and sets its
+// tenant, so it can be rejected before it is created in the background. It returns the venue.
+func (s *eventRepository) PrepareEvent(ctx context.Context, event *entities.Event) (*entities.Venue, error) {
 	// First, verify the venue exists and get its information
 	var venue entities.Venue
-	if err := s.db.WithContext(ctx).First(&venue, event.VenueID).Error; err != nil {
+	if err := conn(ctx, s.db).Scopes(tenantScope(ctx, "venues")).Preload("Sections").First(&venue, event.VenueID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return errors.NewNotFoundError("Venue not found", errors.ErrRecordNotFound)
+			return nil, errors.NewNotFoundError("Venue not found", errors.ErrRecordNotFound)
 		}
-		return errors.NewInternalError("Failed to fetch venue", err)
+		return nil, errors.NewInternalError("Failed to fetch venue", err)
+	}
+	// Events belong to their venue's tenant
+	event.TenantID = venue.TenantID
+	// and close their sales by the venue's policy unless they set their own cutoff
+	if event.SalesCloseMinutesBeforeStart == nil {
+		event.SalesCloseMinutesBeforeStart = &venue.SalesCloseMinutesBeforeStart
 	}
 
 	// Check for venue time conflicts
-	if err := s.checkVenueTimeConflict(ctx, event.VenueID, event.StartTime, event.EndTime, 0); err != nil {
-		return err
+	if err := s.checkVenueTimeConflict(ctx, event.VenueID, event.StartTime, event.EndTime, 0, event.Sandbox); err != nil {
+		return nil, err
 	}
 
 	// Validate event times
 	if err := s.validateEventTimes(event.StartTime, event.EndTime); err != nil {
-		return err
+		return nil, err
 	}
 
-	// Start transaction
-	tx := s.db.WithContext(ctx).Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
-	// Set initial available seats to venue capacity
-	event.AvailableSeats = venue.Rows * venue.Columns
+	if err := entities.ValidateEventMetadata(event.EventType, event.Metadata); err != nil {
+		return nil, errors.NewBadRequestError(err.Error(), err)
+	}
 
-	// Create the event
-	if err := tx.Create(event).Error; err != nil {
-		tx.Rollback()
-		return errors.NewInternalError("Failed to create event", err)
+	if event.OversellPercent > 0 && !event.IsFree() {
+		return nil, errors.NewBadRequestError(constants.ErrOversellPaidEvent, nil)
 	}
 
-	// Create seats for the event using venue rows and columns
-	if err := s.createSeatsForEvent(tx, event, venue.Rows, venue.Columns); err != nil {
-		tx.Rollback()
+	return &venue, nil
+}
+
+// CreateEvent creates a new event and its seats (admin only). progress, if set, is called
+// after each batch of seats is inserted.
+func (s *eventRepository) CreateEvent(ctx context.Context, event *entities.Event, progress SeatProgressFunc) error {
+	// Checks run again in case another event took the venue since the event was prepared
+	venue, err := s.PrepareEvent(ctx, event)
+	if err != nil {
 		return err
 	}
 
-	return tx.Commit().Error
+	// Set initial available seats to the capacity of the rows released at creation
+	releasedRows := venue.Rows
+	if event.InitialReleaseRows > 0 && event.InitialReleaseRows < venue.Rows {
+		releasedRows = event.InitialReleaseRows
+	}
+	event.AvailableSeats = releasedRows * venue.Columns
+
+	return conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		// Create the event
+		if err := tx.Create(event).Error; err != nil {
+			return errors.NewInternalError("Failed to create event", err)
+		}
+
+		// Create seats for the event using venue rows, columns and sections
+		if err := createSeatsForEvent(tx, event, venue, progress); err != nil {
+			return err
+		}
+		return syncOversellPlaces(tx, event)
+	})
+}
+
+// NewSandboxEvent returns an unsaved sandbox copy of an event, set up like it to rehearse its
+// on-sale. Creating it generates its own seats, so the real event's inventory is untouched.
+func (s *eventRepository) NewSandboxEvent(ctx context.Context, eventID uint) (*entities.Event, error) {
+	var source entities.Event
+	if err := conn(ctx, s.db).Scopes(tenantScope(ctx, "events")).First(&source, eventID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Event not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch event", err)
+	}
+	if source.Sandbox {
+		return nil, errors.NewBadRequestError(constants.ErrSandboxOfSandbox, nil)
+	}
+
+	return &entities.Event{
+		Name:               source.Name,
+		Description:        source.Description,
+		VenueID:            source.VenueID,
+		StartTime:          source.StartTime,
+		EndTime:            source.EndTime,
+		Price:              source.Price,
+		EventType:          source.EventType,
+		Status:             constants.EventStatusActive,
+		IsHighDemand:       source.IsHighDemand,
+		ReminderOffsets:    source.ReminderOffsets,
+		WaitlistCap:        source.WaitlistCap,
+		WaitlistTiers:      source.WaitlistTiers,
+		SaleCountries:      source.SaleCountries,
+		EarlyAccessTier:    source.EarlyAccessTier,
+		InitialReleaseRows: source.InitialReleaseRows,
+		OversellPercent:    source.OversellPercent,
+		TicketLimit:        source.TicketLimit,
+		Terms:              source.Terms,
+		TermsVersion:       source.TermsVersion,
+		MinimumAge:         source.MinimumAge,
+		RequireFullName:    source.RequireFullName,
+		RequireIDNumber:    source.RequireIDNumber,
+		Metadata:           source.Metadata,
+		Sandbox:            true,
+		SandboxOfID:        &source.ID,
+	}, nil
 }
 
 // UpdateEvent updates an existing event (admin only)
-func (s *EventRepository) UpdateEvent(ctx context.Context, eventID uint, updates map[string]interface{}) (*entities.Event, error) {
+func (s *eventRepository) UpdateEvent(ctx context.Context, eventID uint, updates map[string]interface{}) (*entities.Event, error) {
 	var event entities.Event
 
-	if err := s.db.WithContext(ctx).First(&event, eventID).Error; err != nil {
+	if err := conn(ctx, s.db).Scopes(tenantScope(ctx, "events")).First(&event, eventID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.NewNotFoundError("Event not found", errors.ErrRecordNotFound)
 		}
@@ -160,6 +295,16 @@ func (s *EventRepository) UpdateEvent(ctx context.Context, eventID uint, updates
 
 	if newVenueID, ok := updates["venue_id"]; ok {
 		venueID = newVenueID.(uint)
+		// Events can only move to a venue of the same tenant
+		var venueCount int64
+		if err := conn(ctx, s.db).Model(&entities.Venue{}).
+			Where("id = ? AND tenant_id = ?", venueID, event.TenantID).
+			Count(&venueCount).Error; err != nil {
+			return nil, errors.NewInternalError("Failed to fetch venue", err)
+		}
+		if venueCount == 0 {
+			return nil, errors.NewNotFoundError("Venue not found", errors.ErrRecordNotFound)
+		}
 	}
 	if newStartTime, ok := updates["start_time"]; ok {
 		startTime = newStartTime.(time.Time)
@@ -173,37 +318,76 @@ func (s *EventRepository) UpdateEvent(ctx context.Context, eventID uint, updates
 		if err := s.validateEventTimes(startTime, endTime); err != nil {
 			return nil, err
 		}
-		if err := s.checkVenueTimeConflict(ctx, venueID, startTime, endTime, eventID); err != nil {
+		if err := s.checkVenueTimeConflict(ctx, venueID, startTime, endTime, eventID, event.Sandbox); err != nil {
 			return nil, err
 		}
 	} else if _, hasStartTime := updates["start_time"]; hasStartTime {
 		if err := s.validateEventTimes(startTime, endTime); err != nil {
 			return nil, err
 		}
-		if err := s.checkVenueTimeConflict(ctx, venueID, startTime, endTime, eventID); err != nil {
+		if err := s.checkVenueTimeConflict(ctx, venueID, startTime, endTime, eventID, event.Sandbox); err != nil {
 			return nil, err
 		}
 	} else if _, hasEndTime := updates["end_time"]; hasEndTime {
 		if err := s.validateEventTimes(startTime, endTime); err != nil {
 			return nil, err
 		}
-		if err := s.checkVenueTimeConflict(ctx, venueID, startTime, endTime, eventID); err != nil {
+		if err := s.checkVenueTimeConflict(ctx, venueID, startTime, endTime, eventID, event.Sandbox); err != nil {
 			return nil, err
 		}
 	}
 
-	if err := s.db.WithContext(ctx).Model(&event).Updates(updates).Error; err != nil {
-		return nil, errors.NewInternalError("Failed to update event", err)
+	// A new event type or new metadata must satisfy the event type's metadata schema
+	_, hasType := updates["event_type"]
+	newMetadata, hasMetadata := updates["metadata"].(entities.Metadata)
+	if hasType || hasMetadata {
+		eventType := event.EventType
+		if newType, ok := updates["event_type"].(string); ok {
+			eventType = newType
+		}
+		metadata := event.Metadata
+		if hasMetadata {
+			metadata = newMetadata
+		}
+		if err := entities.ValidateEventMetadata(eventType, metadata); err != nil {
+			return nil, errors.NewBadRequestError(err.Error(), err)
+		}
+	}
+
+	// Only free events can be oversold, and a new percentage adds or removes oversell places
+	updated := event
+	if price, ok := updates["price"].(money.Money); ok {
+		updated.Price = price
+	}
+	percent, hasOversell := updates["oversell_percent"].(int)
+	if hasOversell {
+		updated.OversellPercent = percent
+	}
+	if updated.OversellPercent > 0 && !updated.IsFree() {
+		return nil, errors.NewBadRequestError(constants.ErrOversellPaidEvent, nil)
+	}
+
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&event).Updates(updates).Error; err != nil {
+			return errors.NewInternalError("Failed to update event", err)
+		}
+		if hasOversell {
+			return syncOversellPlaces(tx, &updated)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &event, nil
 }
 
 // DeleteEvent soft deletes an event (admin only)
-func (s *EventRepository) DeleteEvent(ctx context.Context, eventID uint) error {
+func (s *eventRepository) DeleteEvent(ctx context.Context, eventID uint) error {
 	var event entities.Event
 
-	if err := s.db.WithContext(ctx).First(&event, eventID).Error; err != nil {
+	if err := conn(ctx, s.db).Scopes(tenantScope(ctx, "events")).First(&event, eventID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return errors.NewNotFoundError("Event not found", errors.ErrRecordNotFound)
 		}
@@ -211,7 +395,7 @@ func (s *EventRepository) DeleteEvent(ctx context.Context, eventID uint) error {
 	}
 
 	// Update status to cancelled instead of hard delete
-	if err := s.db.WithContext(ctx).Model(&event).
+	if err := conn(ctx, s.db).Model(&event).
 		Update("status", constants.EventStatusCancelled).Error; err != nil {
 		return errors.NewInternalError("Failed to cancel event", err)
 	}
@@ -219,98 +403,222 @@ func (s *EventRepository) DeleteEvent(ctx context.Context, eventID uint) error {
 	return nil
 }
 
-// createSeatsForEvent creates seats for a new event using venue's row/column configuration
-func (s *EventRepository) createSeatsForEvent(tx *gorm.DB, event *entities.Event, rows, columns int) error {
-	var seats []entities.Seat
+// createSeatsForEvent creates seats for a new event using venue's row/column configuration.
+// Rows covered by a venue section take the section's seat type and price multiplier, and rows
+// beyond the event's initial release are held for later release waves. Seats are generated and
+// inserted batch by batch so large venues never build one huge slice.
+func createSeatsForEvent(tx *gorm.DB, event *entities.Event, venue *entities.Venue, progress SeatProgressFunc) error {
+	total := venue.Rows * venue.Columns
+	created := 0
+	return generateSeats(event, venue, seatInsertBatchSize, func(batch []entities.Seat) error {
+		if err := tx.Omit(clause.Associations).Create(&batch).Error; err != nil {
+			return errors.NewInternalError("Failed to create seats", err)
+		}
+		created += len(batch)
+		if progress != nil {
+			progress(created, total)
+		}
+		return nil
+	})
+}
+
+// generateSeats passes an event's seats to insert in batches of batchSize, row by row. The
+// batch buffer is reused, so insert must not keep the slice.
+func generateSeats(event *entities.Event, venue *entities.Venue, batchSize int, insert func([]entities.Seat) error) error {
+	batch := make([]entities.Seat, 0, batchSize)
+
+	for row := 1; row <= venue.Rows; row++ {
+		seatType := constants.SeatTypeStandard
+		price := event.Price
+		if section := sectionForRow(venue.Sections, row); section != nil {
+			seatType = section.SeatType
+			price = event.Price.Mul(section.PriceMultiplier)
+		}
+		isHeld := event.InitialReleaseRows > 0 && row > event.InitialReleaseRows
 
-	for row := 1; row <= rows; row++ {
-		for col := 1; col <= columns; col++ {
-			// All seats are standard type with the same price as the event
-			seat := entities.Seat{
+		for col := 1; col <= venue.Columns; col++ {
+			batch = append(batch, entities.Seat{
 				EventID:     event.ID,
 				Row:         row,
 				Column:      col,
-				SeatType:    constants.SeatTypeStandard,
-				Price:       event.Price,
+				SeatType:    seatType,
+				Price:       price,
 				IsAvailable: true,
 				IsLocked:    false,
+				IsHeld:      isHeld,
+			})
+
+			if len(batch) == batchSize {
+				if err := insert(batch); err != nil {
+					return err
+				}
+				batch = batch[:0]
 			}
-			seats = append(seats, seat)
 		}
 	}
 
-	if err := tx.CreateInBatches(seats, 100).Error; err != nil {
-		return errors.NewInternalError("Failed to create seats", err)
+	if len(batch) > 0 {
+		return insert(batch)
 	}
+	return nil
+}
 
+// sectionForRow returns the venue section covering the given row, if any
+func sectionForRow(sections []entities.VenueSection, row int) *entities.VenueSection {
+	for i := range sections {
+		if row >= sections[i].RowStart && row <= sections[i].RowEnd {
+			return &sections[i]
+		}
+	}
 	return nil
 }
 
 // GetEventStats returns statistics for an event (admin only)
-func (s *EventRepository) GetEventStats(ctx context.Context, eventID uint) (map[string]interface{}, error) {
+func (s *eventRepository) GetEventStats(ctx context.Context, eventID uint) (map[string]interface{}, error) {
 	var event entities.Event
 	var totalSeats int64
 	var bookedSeats int64
 	var lockedSeats int64
-	var revenue float64
+	var revenue money.Money
+	var checkedIn int64
+	var noShows int64
 
 	// Check if event exists
-	if err := s.db.WithContext(ctx).First(&event, eventID).Error; err != nil {
+	if err := conn(ctx, s.db).Scopes(tenantScope(ctx, "events")).First(&event, eventID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.NewNotFoundError("Event not found", errors.ErrRecordNotFound)
 		}
 		return nil, errors.NewInternalError("Failed to fetch event", err)
 	}
 
-	// Total seats
-	if err := s.db.WithContext(ctx).Model(&entities.Seat{}).
-		Where("event_id = ?", eventID).Count(&totalSeats).Error; err != nil {
+	// Total seats, and the oversell places of free events on top of them
+	if err := conn(ctx, s.db).Model(&entities.Seat{}).
+		Where("event_id = ? AND is_oversell = false", eventID).Count(&totalSeats).Error; err != nil {
 		return nil, errors.NewInternalError("Failed to count total seats", err)
 	}
+	var oversellPlaces int64
+	if err := conn(ctx, s.db).Model(&entities.Seat{}).
+		Where("event_id = ? AND is_oversell = true", eventID).Count(&oversellPlaces).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to count oversell places", err)
+	}
 
 	// Booked seats
-	if err := s.db.WithContext(ctx).Model(&entities.Booking{}).
+	if err := conn(ctx, s.db).Model(&entities.Booking{}).
 		Where("event_id = ? AND status = ?", eventID, constants.BookingStatusConfirmed).
 		Count(&bookedSeats).Error; err != nil {
 		return nil, errors.NewInternalError("Failed to count booked seats", err)
 	}
 
 	// Locked seats
-	if err := s.db.WithContext(ctx).Model(&entities.Seat{}).
+	if err := conn(ctx, s.db).Model(&entities.Seat{}).
 		Where("event_id = ? AND is_locked = true", eventID).
 		Count(&lockedSeats).Error; err != nil {
 		return nil, errors.NewInternalError("Failed to count locked seats", err)
 	}
 
 	// Total revenue
-	if err := s.db.WithContext(ctx).Model(&entities.Booking{}).
+	if err := conn(ctx, s.db).Model(&entities.Booking{}).
 		Where("event_id = ? AND status = ? AND payment_status = ?",
 			eventID, constants.BookingStatusConfirmed, constants.PaymentStatusPaid).
 		Select("COALESCE(SUM(total_amount), 0)").Scan(&revenue).Error; err != nil {
 		return nil, errors.NewInternalError("Failed to calculate revenue", err)
 	}
 
+	// Door sales and complimentary tickets are counted apart from online sales
+	var channels []struct {
+		Channel string
+		Seats   int64
+		Revenue money.Money
+	}
+	if err := conn(ctx, s.db).Model(&entities.Booking{}).
+		Select("channel, COUNT(*) as seats, COALESCE(SUM(CASE WHEN payment_status = ? THEN total_amount ELSE 0 END), 0) as revenue", constants.PaymentStatusPaid).
+		Where("event_id = ? AND status = ?", eventID, constants.BookingStatusConfirmed).
+		Group("channel").
+		Scan(&channels).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to count sales by channel", err)
+	}
+	var onlineSeats, doorSeats, compSeats, oversellSeats int64
+	var onlineRevenue, doorRevenue money.Money
+	for _, channel := range channels {
+		switch channel.Channel {
+		case constants.SalesChannelDoor:
+			doorSeats, doorRevenue = channel.Seats, channel.Revenue
+		case constants.SalesChannelComp:
+			compSeats = channel.Seats
+		case constants.SalesChannelOversell:
+			oversellSeats = channel.Seats
+		default:
+			onlineSeats, onlineRevenue = onlineSeats+channel.Seats, onlineRevenue+channel.Revenue
+		}
+	}
+
+	// Attendance
+	if err := conn(ctx, s.db).Model(&entities.Booking{}).
+		Where("event_id = ? AND status = ? AND checked_in_at IS NOT NULL", eventID, constants.BookingStatusConfirmed).
+		Count(&checkedIn).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to count checked-in bookings", err)
+	}
+
+	if err := conn(ctx, s.db).Model(&entities.Booking{}).
+		Where("event_id = ? AND status = ? AND no_show = true", eventID, constants.BookingStatusConfirmed).
+		Count(&noShows).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to count no-shows", err)
+	}
+
+	// Seats of no-shows put back on sale at the door, refunded or not
+	var releasedNoShows int64
+	if err := conn(ctx, s.db).Model(&entities.Booking{}).
+		Where("event_id = ? AND no_show_released_at IS NOT NULL", eventID).
+		Count(&releasedNoShows).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to count released no-shows", err)
+	}
+
+	// Oversell bookings take places on top of the seats, so utilization only counts the seats
+	seatsBooked := bookedSeats - oversellSeats
+
+	// No-shows are only recorded once the event has completed
+	var noShowRate float64
+	if bookedSeats > 0 {
+		noShowRate = float64(noShows) / float64(bookedSeats) * 100
+	}
+
 	stats := map[string]interface{}{
 		"event_id":             eventID,
 		"event_name":           event.Name,
 		"total_seats":          totalSeats,
 		"booked_seats":         bookedSeats,
 		"locked_seats":         lockedSeats,
-		"available_seats":      totalSeats - bookedSeats - lockedSeats,
-		"capacity_utilization": float64(bookedSeats) / float64(totalSeats) * 100,
+		"available_seats":      totalSeats - seatsBooked - lockedSeats,
+		"capacity_utilization": float64(seatsBooked) / float64(totalSeats) * 100,
 		"total_revenue":        revenue,
-		"booking_rate":         float64(bookedSeats) / float64(totalSeats) * 100,
+		"booking_rate":         float64(seatsBooked) / float64(totalSeats) * 100,
+		"checked_in":           checkedIn,
+		"no_shows":             noShows,
+		"no_show_rate":         noShowRate,
+		"released_no_shows":    releasedNoShows,
+		"online_seats":         onlineSeats,
+		"online_revenue":       onlineRevenue,
+		"door_seats":           doorSeats,
+		"door_revenue":         doorRevenue,
+		"comp_seats":           compSeats,
+		"oversell_places":      oversellPlaces,
+		"oversell_seats":       oversellSeats,
 	}
 
 	return stats, nil
 }
 
 // checkVenueTimeConflict checks if there's a time conflict for events at the same venue
-func (s *EventRepository) checkVenueTimeConflict(ctx context.Context, venueID uint, startTime, endTime time.Time, excludeEventID uint) error {
+func (s *eventRepository) checkVenueTimeConflict(ctx context.Context, venueID uint, startTime, endTime time.Time, excludeEventID uint, sandbox bool) error {
+	// Sandbox events rehearse in their source event's slot: they neither conflict nor get in the way
+	if sandbox {
+		return nil
+	}
+
 	var conflictingEvent entities.Event
 
-	query := s.db.WithContext(ctx).
-		Where("venue_id = ? AND status = ?", venueID, constants.EventStatusActive).
+	query := conn(ctx, s.db).
+		Where("venue_id = ? AND status = ? AND sandbox = false", venueID, constants.EventStatusActive).
 		Where("NOT (end_time <= ? OR start_time >= ?)", startTime, endTime)
 
 	// Exclude current event when updating
@@ -331,7 +639,7 @@ func (s *EventRepository) checkVenueTimeConflict(ctx context.Context, venueID ui
 }
 
 // validateEventTimes validates event start and end times
-func (s *EventRepository) validateEventTimes(startTime, endTime time.Time) error {
+func (s *eventRepository) validateEventTimes(startTime, endTime time.Time) error {
 	// Check if end time is after start time
 	if !endTime.After(startTime) {
 		return errors.NewBadRequestError("End time must be after start time", nil)
@@ -344,3 +652,20 @@ func (s *EventRepository) validateEventTimes(startTime, endTime time.Time) error
 
 	return nil
 }
+
+// metadataScope matches rows whose custom fields equal every filter value. Values are compared
+// as text, so numbers and booleans match their JSON spelling (e.g. "90", "true").
+func metadataScope(table string, filters map[string]string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		keys := make([]string, 0, len(filters))
+		for key := range filters {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			db = db.Where(table+".metadata ->> ? = ?", key, filters[key])
+		}
+		return db
+	}
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"api/internal/encryption"
+	"api/internal/entities"
+	"api/pkg/money"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// stadium is a 50,000 seat venue with a premium section
+var stadium = &entities.Venue{
+	ID:      1,
+	Rows:    200,
+	Columns: 250,
+	Sections: []entities.VenueSection{
+		{RowStart: 1, RowEnd: 20, SeatType: "Premium", PriceMultiplier: 1.5},
+	},
+}
+
+func BenchmarkGenerateSeats(b *testing.B) {
+	event := &entities.Event{ID: 1, Price: money.FromMajor(50), InitialReleaseRows: 150}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		seats := 0
+		if err := generateSeats(event, stadium, seatInsertBatchSize, func(batch []entities.Seat) error {
+			seats += len(batch)
+			return nil
+		}); err != nil {
+			b.Fatal(err)
+		}
+		if seats != stadium.Rows*stadium.Columns {
+			b.Fatalf("generated %d seats, want %d", seats, stadium.Rows*stadium.Columns)
+		}
+	}
+}
+
+// BenchmarkCreateSeatsForEvent measures building the INSERT statements for a stadium event
+// without a database, using GORM's dry run mode
+func BenchmarkCreateSeatsForEvent(b *testing.B) {
+	keyring, err := encryption.NewKeyring(encryption.Config{Keys: "bench:MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MDE="})
+	if err != nil {
+		b.Fatal(err)
+	}
+	encryption.Register(keyring)
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Discard,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	event := &entities.Event{ID: 1, Price: money.FromMajor(50)}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := createSeatsForEvent(db, event, stadium, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"api/internal/entities"
+	redisconn "api/internal/redis"
+	"api/pkg/money"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cached parts of an event
+const (
+	eventCacheDetails   = "details"
+	eventCacheSeats     = "seats"
+	eventCacheAvailable = "available"
+)
+
+// cachedSeat is the part of a seat the public seat map shows, so cached seat maps of large
+// venues stay small
+type cachedSeat struct {
+	ID                uint        `json:"id"`
+	Row               int         `json:"r"`
+	Column            int         `json:"c"`
+	SeatType          string      `json:"t"`
+	Price             money.Money `json:"p"`
+	IsLocked          bool        `json:"l,omitempty"`
+	IsAccessible      bool        `json:"a,omitempty"`
+	IsCompanion       bool        `json:"cp,omitempty"`
+	CompanionOfSeatID *uint       `json:"co,omitempty"`
+}
+
+// EventCacheRepository caches events' public details, seat maps and available seat counts in
+// Redis, so bursts of reads at an on-sale don't all reach Postgres. The cache is best effort:
+// misses and Redis errors fall back to the database, and failed writes are logged.
+type EventCacheRepository struct {
+	redis redis.UniversalClient
+	ttl   time.Duration
+}
+
+func NewEventCacheRepository(redisClient redis.UniversalClient, ttl time.Duration) *EventCacheRepository {
+	return &EventCacheRepository{
+		redis: redisClient,
+		ttl:   ttl,
+	}
+}
+
+// GetEvent returns an event's cached details, without seats, or nil on a miss
+func (r *EventCacheRepository) GetEvent(ctx context.Context, eventID uint) *entities.Event {
+	var event entities.Event
+	if !r.get(ctx, eventID, eventCacheDetails, &event) {
+		return nil
+	}
+	return &event
+}
+
+// SetEvent caches an event's details; its seats are cached separately with SetSeats
+func (r *EventCacheRepository) SetEvent(ctx context.Context, event *entities.Event) error {
+	details := *event
+	details.Seats = nil
+	return r.set(ctx, event.ID, eventCacheDetails, &details)
+}
+
+// GetSeats returns an event's cached seat map, the seats on sale with whether they are being
+// checked out, or false on a miss
+func (r *EventCacheRepository) GetSeats(ctx context.Context, eventID uint) ([]entities.Seat, bool) {
+	var cached []cachedSeat
+	if !r.get(ctx, eventID, eventCacheSeats, &cached) {
+		return nil, false
+	}
+
+	seats := make([]entities.Seat, len(cached))
+	for i, seat := range cached {
+		seats[i] = entities.Seat{
+			ID:                seat.ID,
+			EventID:           eventID,
+			Row:               seat.Row,
+			Column:            seat.Column,
+			SeatType:          seat.SeatType,
+			Price:             seat.Price,
+			IsAvailable:       true,
+			IsLocked:          seat.IsLocked,
+			IsAccessible:      seat.IsAccessible,
+			IsCompanion:       seat.IsCompanion,
+			CompanionOfSeatID: seat.CompanionOfSeatID,
+		}
+	}
+	return seats, true
+}
+
+// SetSeats caches an event's seat map
+func (r *EventCacheRepository) SetSeats(ctx context.Context, eventID uint, seats []entities.Seat) error {
+	cached := make([]cachedSeat, len(seats))
+	for i, seat := range seats {
+		cached[i] = cachedSeat{
+			ID:                seat.ID,
+			Row:               seat.Row,
+			Column:            seat.Column,
+			SeatType:          seat.SeatType,
+			Price:             seat.Price,
+			IsLocked:          seat.IsLocked,
+			IsAccessible:      seat.IsAccessible,
+			IsCompanion:       seat.IsCompanion,
+			CompanionOfSeatID: seat.CompanionOfSeatID,
+		}
+	}
+	return r.set(ctx, eventID, eventCacheSeats, cached)
+}
+
+// GetAvailableCount returns an event's cached count of bookable seats, or false on a miss
+func (r *EventCacheRepository) GetAvailableCount(ctx context.Context, eventID uint) (int64, bool) {
+	value, err := r.redis.Get(ctx, redisconn.EventCacheKey(eventID, eventCacheAvailable)).Result()
+	if err != nil {
+		if err != redis.Nil {
+			warnLockError("read event cache", err)
+		}
+		return 0, false
+	}
+	count, err := strconv.ParseInt(value, 10, 64)
+	return count, err == nil
+}
+
+// SetAvailableCount caches an event's count of bookable seats
+func (r *EventCacheRepository) SetAvailableCount(ctx context.Context, eventID uint, count int64) error {
+	if err := r.redis.Set(ctx, redisconn.EventCacheKey(eventID, eventCacheAvailable), count, r.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to cache available seats: %w", err)
+	}
+	return nil
+}
+
+// Invalidate drops everything cached for an event, e.g. after it changed or a seat was sold
+func (r *EventCacheRepository) Invalidate(ctx context.Context, eventID uint) {
+	// All of an event's keys share its hash tag, so one DEL removes them even in cluster mode
+	if err := r.redis.Del(ctx,
+		redisconn.EventCacheKey(eventID, eventCacheDetails),
+		redisconn.EventCacheKey(eventID, eventCacheSeats),
+		redisconn.EventCacheKey(eventID, eventCacheAvailable),
+	).Err(); err != nil {
+		warnLockError("invalidate event cache", err)
+	}
+}
+
+// InvalidateSeats drops an event's cached seat map and count, which every sale or release
+// changes, and keeps its details
+func (r *EventCacheRepository) InvalidateSeats(ctx context.Context, eventID uint) {
+	if err := r.redis.Del(ctx,
+		redisconn.EventCacheKey(eventID, eventCacheSeats),
+		redisconn.EventCacheKey(eventID, eventCacheAvailable),
+	).Err(); err != nil {
+		warnLockError("invalidate event cache", err)
+	}
+}
+
+func (r *EventCacheRepository) get(ctx context.Context, eventID uint, part string, dest interface{}) bool {
+	data, err := r.redis.Get(ctx, redisconn.EventCacheKey(eventID, part)).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			warnLockError("read event cache", err)
+		}
+		return false
+	}
+	return json.Unmarshal(data, dest) == nil
+}
+
+func (r *EventCacheRepository) set(ctx context.Context, eventID uint, part string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode cached event %s: %w", part, err)
+	}
+	if err := r.redis.Set(ctx, redisconn.EventCacheKey(eventID, part), data, r.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to cache event %s: %w", part, err)
+	}
+	return nil
+}
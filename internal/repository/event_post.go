@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type EventPostRepository struct {
+	db *gorm.DB
+}
+
+func NewEventPostRepository(db *gorm.DB) *EventPostRepository {
+	return &EventPostRepository{db: db}
+}
+
+// CreatePost attaches a post to an event of the caller's tenant
+func (s *EventPostRepository) CreatePost(ctx context.Context, post *entities.EventPost) error {
+	var event entities.Event
+	if err := conn(ctx, s.db).Scopes(tenantScope(ctx, "events")).Select("id", "tenant_id").
+		First(&event, post.EventID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.NewNotFoundError("Event not found", errors.ErrRecordNotFound)
+		}
+		return errors.NewInternalError("Failed to fetch event", err)
+	}
+
+	post.TenantID = event.TenantID
+	if err := conn(ctx, s.db).Create(post).Error; err != nil {
+		return errors.NewInternalError("Failed to create event post", err)
+	}
+	return nil
+}
+
+// UpdatePost changes a post of the caller's tenant
+func (s *EventPostRepository) UpdatePost(ctx context.Context, postID uint, updates map[string]interface{}) (*entities.EventPost, error) {
+	post, err := s.GetPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if len(updates) == 0 {
+		return post, nil
+	}
+	if err := conn(ctx, s.db).Model(post).Updates(updates).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to update event post", err)
+	}
+	return post, nil
+}
+
+// DeletePost removes a post of the caller's tenant, returning it
+func (s *EventPostRepository) DeletePost(ctx context.Context, postID uint) (*entities.EventPost, error) {
+	post, err := s.GetPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn(ctx, s.db).Delete(post).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to delete event post", err)
+	}
+	return post, nil
+}
+
+// GetPost returns a post of the caller's tenant
+func (s *EventPostRepository) GetPost(ctx context.Context, postID uint) (*entities.EventPost, error) {
+	var post entities.EventPost
+	if err := conn(ctx, s.db).Scopes(tenantScope(ctx, "event_posts")).First(&post, postID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError(constants.ErrEventPostNotFound, errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch event post", err)
+	}
+	return &post, nil
+}
+
+// ListPosts returns an event's posts of the given kinds: FAQs by position, announcements and
+// FAQs at the same position newest first
+func (s *EventPostRepository) ListPosts(ctx context.Context, eventID uint, kinds ...string) ([]entities.EventPost, error) {
+	var posts []entities.EventPost
+	if err := conn(ctx, s.db).
+		Where("event_id = ? AND kind IN ?", eventID, kinds).
+		Order("position ASC, created_at DESC, id DESC").
+		Find(&posts).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch event posts", err)
+	}
+	return posts, nil
+}
+
+// GetPendingAnnouncements returns announcements whose attendees haven't been emailed yet
+func (s *EventPostRepository) GetPendingAnnouncements(ctx context.Context) ([]entities.EventPost, error) {
+	var posts []entities.EventPost
+	if err := conn(ctx, s.db).
+		Preload("Event").
+		Where("kind = ? AND notified_at IS NULL", constants.EventPostKindAnnouncement).
+		Order("created_at ASC, id ASC").
+		Find(&posts).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch pending announcements", err)
+	}
+	return posts, nil
+}
+
+// ClaimAnnouncement marks an announcement notified. It returns false if it already was, so
+// attendees are emailed at most once.
+func (s *EventPostRepository) ClaimAnnouncement(ctx context.Context, postID uint, now time.Time) (bool, error) {
+	result := conn(ctx, s.db).Model(&entities.EventPost{}).
+		Where("id = ? AND notified_at IS NULL", postID).
+		Update("notified_at", now)
+	if result.Error != nil {
+		return false, errors.NewInternalError("Failed to claim announcement", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetAttendees returns the confirmed bookings of an event with their users
+func (s *EventPostRepository) GetAttendees(ctx context.Context, eventID uint) ([]entities.Booking, error) {
+	var bookings []entities.Booking
+	if err := conn(ctx, s.db).
+		Preload("User").
+		Where("event_id = ? AND status = ?", eventID, constants.BookingStatusConfirmed).
+		Find(&bookings).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch attendees", err)
+	}
+	return bookings, nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FeedRepository reads the public catalog the sitemap and event feeds are generated from
+type FeedRepository struct {
+	db *gorm.DB
+}
+
+func NewFeedRepository(db *gorm.DB) *FeedRepository {
+	return &FeedRepository{db: db}
+}
+
+// listedEvents restricts a query to events shown in public listings
+func listedEvents(db *gorm.DB) *gorm.DB {
+	return db.Where("events.status IN ? AND events.sandbox = false",
+		[]string{constants.EventStatusActive, constants.EventStatusSoldOut})
+}
+
+// ListPublishedEvents returns listed events, most recently published first, with their venue
+// and organizer
+func (s *FeedRepository) ListPublishedEvents(ctx context.Context, limit, offset int) ([]entities.Event, int64, error) {
+	var total int64
+	if err := conn(ctx, s.db).Model(&entities.Event{}).Scopes(listedEvents).Count(&total).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to count events", err)
+	}
+
+	var events []entities.Event
+	if err := conn(ctx, s.db).Scopes(listedEvents).Preload("Venue").Preload("Tenant").
+		Order("events.created_at DESC, events.id DESC").Limit(limit).Offset(offset).
+		Find(&events).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to fetch events", err)
+	}
+	return events, total, nil
+}
+
+// ListSitemapEvents returns the IDs and last changes of listed events that haven't ended,
+// at most limit
+func (s *FeedRepository) ListSitemapEvents(ctx context.Context, now time.Time, limit int) ([]entities.Event, error) {
+	var events []entities.Event
+	if err := conn(ctx, s.db).Select("id", "updated_at").Scopes(listedEvents).
+		Where("end_time > ?", now).Order("id ASC").Limit(limit).
+		Find(&events).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch events", err)
+	}
+	return events, nil
+}
+
+// ListOrganizers returns the slugs and last changes of organizers with listed events
+func (s *FeedRepository) ListOrganizers(ctx context.Context, now time.Time) ([]entities.Tenant, error) {
+	listed := conn(ctx, s.db).Session(&gorm.Session{NewDB: true}).Model(&entities.Event{}).
+		Select("tenant_id").Scopes(listedEvents).Where("end_time > ?", now)
+
+	var tenants []entities.Tenant
+	if err := conn(ctx, s.db).Select("id", "slug", "updated_at").
+		Where("id IN (?)", listed).Order("id ASC").
+		Find(&tenants).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch organizers", err)
+	}
+	return tenants, nil
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ImportRepository struct {
+	db *gorm.DB
+}
+
+func NewImportRepository(db *gorm.DB) *ImportRepository {
+	return &ImportRepository{db: db}
+}
+
+// FindVenueByNameAndCity returns the venue matching the import key, or nil if none exists
+func (s *ImportRepository) FindVenueByNameAndCity(ctx context.Context, name, city string) (*entities.Venue, error) {
+	var venue entities.Venue
+
+	if err := conn(ctx, s.db).Scopes(tenantScope(ctx, "venues")).
+		Preload("Sections", func(db *gorm.DB) *gorm.DB { return db.Order("row_start ASC") }).
+		Where("LOWER(name) = ? AND LOWER(city) = ?", strings.ToLower(name), strings.ToLower(city)).
+		First(&venue).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, errors.NewInternalError("Failed to fetch venue", err)
+	}
+
+	return &venue, nil
+}
+
+// FindEvent returns the event matching the import key, or nil if none exists
+func (s *ImportRepository) FindEvent(ctx context.Context, venueID uint, name string, startTime time.Time) (*entities.Event, error) {
+	var event entities.Event
+
+	if err := conn(ctx, s.db).Scopes(tenantScope(ctx, "events")).
+		Where("venue_id = ? AND LOWER(name) = ? AND start_time = ?", venueID, strings.ToLower(name), startTime).
+		First(&event).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, errors.NewInternalError("Failed to fetch event", err)
+	}
+
+	return &event, nil
+}
+
+// HasVenueTimeConflict reports whether an active event at the venue overlaps the given window
+func (s *ImportRepository) HasVenueTimeConflict(ctx context.Context, venueID uint, startTime, endTime time.Time, excludeEventID uint) (bool, error) {
+	var count int64
+
+	query := conn(ctx, s.db).Model(&entities.Event{}).
+		Where("venue_id = ? AND status = ?", venueID, constants.EventStatusActive).
+		Where("NOT (end_time <= ? OR start_time >= ?)", startTime, endTime)
+
+	if excludeEventID > 0 {
+		query = query.Where("id != ?", excludeEventID)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return false, errors.NewInternalError("Failed to check venue time conflicts", err)
+	}
+
+	return count > 0, nil
+}
+
+// VenueHasEvents reports whether any event is held at the venue. Their seats were generated from
+// the venue's rows and columns.
+func (s *ImportRepository) VenueHasEvents(ctx context.Context, venueID uint) (bool, error) {
+	return venueHasEvents(conn(ctx, s.db), venueID)
+}
+
+func venueHasEvents(db *gorm.DB, venueID uint) (bool, error) {
+	var count int64
+	if err := db.Model(&entities.Event{}).Where("venue_id = ?", venueID).Count(&count).Error; err != nil {
+		return false, errors.NewInternalError("Failed to check venue events", err)
+	}
+	return count > 0, nil
+}
+
+// ApplyVenueImport creates or updates venues and replaces their sections in a single transaction.
+// Venues with a zero ID are created, all others are updated. Rows and columns of venues with
+// events can't be changed, as their events' seats were generated from them.
+func (s *ImportRepository) ApplyVenueImport(ctx context.Context, venues []*entities.Venue) error {
+	return conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		for _, venue := range venues {
+			sections := venue.Sections
+			venue.Sections = nil
+
+			if venue.ID == 0 {
+				venue.TenantID = tenantForCreate(ctx, venue.TenantID)
+				if err := tx.Create(venue).Error; err != nil {
+					return errors.NewInternalError("Failed to create venue", err)
+				}
+			} else {
+				var existing entities.Venue
+				if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "rows", "columns").
+					First(&existing, venue.ID).Error; err != nil {
+					if err == gorm.ErrRecordNotFound {
+						return errors.NewNotFoundError("Venue not found", errors.ErrRecordNotFound)
+					}
+					return errors.NewInternalError("Failed to fetch venue", err)
+				}
+				if existing.Rows != venue.Rows || existing.Columns != venue.Columns {
+					hasEvents, err := venueHasEvents(tx, venue.ID)
+					if err != nil {
+						return err
+					}
+					if hasEvents {
+						return errors.NewConflictError(constants.ErrVenueLayoutHasEvents, nil)
+					}
+				}
+
+				if err := tx.Model(&entities.Venue{ID: venue.ID}).
+					Select("name", "address", "city", "state", "country", "rows", "columns", "description").
+					Updates(venue).Error; err != nil {
+					return errors.NewInternalError("Failed to update venue", err)
+				}
+				if err := tx.Where("venue_id = ?", venue.ID).Delete(&entities.VenueSection{}).Error; err != nil {
+					return errors.NewInternalError("Failed to replace venue sections", err)
+				}
+			}
+
+			for i := range sections {
+				sections[i].ID = 0
+				sections[i].VenueID = venue.ID
+			}
+			if len(sections) > 0 {
+				if err := tx.Create(&sections).Error; err != nil {
+					return errors.NewInternalError("Failed to create venue sections", err)
+				}
+			}
+			venue.Sections = sections
+		}
+		return nil
+	})
+}
+
+// ApplyEventImport creates or updates events in a single transaction, generating seats for new events.
+// Events with a zero ID are created, all others are updated.
+func (s *ImportRepository) ApplyEventImport(ctx context.Context, events []*entities.Event) error {
+	return conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		venues := make(map[uint]*entities.Venue)
+
+		for _, event := range events {
+			if event.ID != 0 {
+				if err := tx.Model(&entities.Event{ID: event.ID}).
+					Select("description", "end_time", "price", "event_type", "is_high_demand").
+					Updates(event).Error; err != nil {
+					return errors.NewInternalError("Failed to update event", err)
+				}
+				continue
+			}
+
+			venue, ok := venues[event.VenueID]
+			if !ok {
+				venue = &entities.Venue{}
+				if err := tx.Scopes(tenantScope(ctx, "venues")).Preload("Sections").First(venue, event.VenueID).Error; err != nil {
+					if err == gorm.ErrRecordNotFound {
+						return errors.NewNotFoundError("Venue not found", errors.ErrRecordNotFound)
+					}
+					return errors.NewInternalError("Failed to fetch venue", err)
+				}
+				venues[event.VenueID] = venue
+			}
+
+			event.TenantID = venue.TenantID
+			event.SalesCloseMinutesBeforeStart = &venue.SalesCloseMinutesBeforeStart
+			event.AvailableSeats = venue.Rows * venue.Columns
+			if err := tx.Create(event).Error; err != nil {
+				return errors.NewInternalError("Failed to create event", err)
+			}
+
+			if err := createSeatsForEvent(tx, event, venue, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"api/pkg/money"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestApplyVenueImportKeepsLayoutOfVenuesWithEvents checks against a scratch Postgres database
+// that an import can't change the rows or columns of a venue once it has events, while venues
+// without events and other fields can still change. It needs TEST_DATABASE_URL.
+func TestApplyVenueImportKeepsLayoutOfVenuesWithEvents(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger:                                   logger.Discard,
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&entities.Tenant{}, &entities.Venue{}, &entities.VenueSection{}, &entities.Event{}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := NewTenantRepository(db).EnsureDefaultTenant(ctx); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewImportRepository(db)
+
+	runID := time.Now().Format("150405.000000")
+	newVenue := func(name string) *entities.Venue {
+		return &entities.Venue{Name: name + " " + runID, Address: "1 Test Street", City: "Test", State: "TS", Country: "US",
+			Rows: 5, Columns: 10,
+			Sections: []entities.VenueSection{{Name: "Floor", RowStart: 1, RowEnd: 5, SeatType: constants.SeatTypeStandard, PriceMultiplier: 1}}}
+	}
+	withEvents, empty := newVenue("Layout with events"), newVenue("Layout without events")
+	if err := repo.ApplyVenueImport(ctx, []*entities.Venue{withEvents, empty}); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().Add(30 * 24 * time.Hour)
+	event := entities.Event{Name: "Layout " + runID, VenueID: withEvents.ID, StartTime: start, EndTime: start.Add(3 * time.Hour),
+		Price: money.FromMajor(25), EventType: constants.EventTypeConcert, Status: constants.EventStatusActive}
+	if err := db.Create(&event).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	resized := newVenue("Layout with events")
+	resized.ID, resized.Columns = withEvents.ID, 12
+	err = repo.ApplyVenueImport(ctx, []*entities.Venue{resized})
+	if appErr, ok := err.(*errors.AppError); !ok || appErr.Message != constants.ErrVenueLayoutHasEvents {
+		t.Fatalf("resizing a venue with events returned %v, want %q", err, constants.ErrVenueLayoutHasEvents)
+	}
+	var current entities.Venue
+	if err := db.First(&current, withEvents.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if current.Columns != 10 {
+		t.Fatalf("venue with events has %d columns, want 10", current.Columns)
+	}
+
+	renamed := newVenue("Layout with events")
+	renamed.ID, renamed.Description = withEvents.ID, "Renovated"
+	resizedEmpty := newVenue("Layout without events")
+	resizedEmpty.ID, resizedEmpty.Rows = empty.ID, 8
+	resizedEmpty.Sections[0].RowEnd = 8
+	if err := repo.ApplyVenueImport(ctx, []*entities.Venue{renamed, resizedEmpty}); err != nil {
+		t.Fatalf("import keeping the layout of the venue with events returned %v", err)
+	}
+	if err := db.First(&current, empty.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if current.Rows != 8 {
+		t.Fatalf("venue without events has %d rows, want 8", current.Rows)
+	}
+}
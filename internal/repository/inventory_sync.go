@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InventorySyncRepository reconciles seats with the holds and releases of venues' external
+// inventory systems
+type InventorySyncRepository struct {
+	db *gorm.DB
+}
+
+func NewInventorySyncRepository(db *gorm.DB) *InventorySyncRepository {
+	return &InventorySyncRepository{db: db}
+}
+
+// ApplyExternalUpdate applies an external hold or release to a seat and returns the outcome.
+// Conflicts are resolved as follows:
+//   - messages older than the latest one applied to the seat are ignored as stale
+//   - local sales and online checkouts win: holding a sold or locked seat is a conflict
+//   - a newer hold under another reference replaces the current one
+//   - a release only frees the seat if it is for the current hold; releasing a seat that
+//     isn't held externally does nothing
+func (r *InventorySyncRepository) ApplyExternalUpdate(ctx context.Context, update entities.ExternalSeatUpdate) (string, error) {
+	var outcome string
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "event_id", "is_available", "is_locked", "is_held", "external_hold_ref", "external_updated_at").
+			Where("event_id = ?", update.EventID)
+		if update.SeatID != 0 {
+			query = query.Where("id = ?", update.SeatID)
+		} else {
+			query = query.Where("\"row\" = ? AND \"column\" = ?", update.Row, update.Column)
+		}
+		var seat entities.Seat
+		if err := query.First(&seat).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewNotFoundError("Seat not found", errors.ErrRecordNotFound)
+			}
+			return errors.NewInternalError("Failed to fetch seat", err)
+		}
+
+		if seat.ExternalUpdatedAt != nil && update.OccurredAt.Before(*seat.ExternalUpdatedAt) {
+			outcome = constants.InventoryStale
+			return nil
+		}
+
+		updates := map[string]interface{}{
+			"external_updated_at": update.OccurredAt,
+			"updated_at":          time.Now(),
+		}
+		capacityChange := 0
+		if update.Hold {
+			switch {
+			case seat.ExternalHoldRef == update.HoldRef:
+				outcome = constants.InventoryDuplicate
+				return nil
+			case seat.ExternalHoldRef == "" && !seat.IsAvailable:
+				return errors.NewConflictError("Seat is already sold", nil)
+			case seat.ExternalHoldRef == "" && (seat.IsLocked || slices.Contains(update.LockedSeatIDs, seat.ID)):
+				return errors.NewConflictError(constants.ErrSeatAlreadyLocked, nil)
+			}
+			// Held-back seats never counted as available
+			if seat.ExternalHoldRef == "" && !seat.IsHeld {
+				capacityChange = -1
+			}
+			updates["is_available"] = false
+			updates["external_hold_ref"] = update.HoldRef
+			outcome = constants.InventoryHeld
+		} else {
+			switch seat.ExternalHoldRef {
+			case "":
+				outcome = constants.InventoryNotHeld
+				return nil
+			case update.HoldRef:
+			default:
+				return errors.NewConflictError("Seat is held externally under another reference", nil)
+			}
+			if !seat.IsHeld {
+				capacityChange = 1
+			}
+			updates["is_available"] = true
+			updates["external_hold_ref"] = ""
+			outcome = constants.InventoryReleased
+		}
+
+		if err := tx.Model(&entities.Seat{}).Where("id = ?", seat.ID).Updates(updates).Error; err != nil {
+			return errors.NewInternalError("Failed to update seat", err)
+		}
+		if capacityChange != 0 {
+			if err := tx.Model(&entities.Event{}).Where("id = ?", seat.EventID).
+				UpdateColumn("available_seats", gorm.Expr("GREATEST(available_seats + ?, 0)", capacityChange)).Error; err != nil {
+				return errors.NewInternalError("Failed to update event capacity", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return outcome, nil
+}
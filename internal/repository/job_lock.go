@@ -0,0 +1,74 @@
+package repository
+
+import (
+	redisconn "api/internal/redis"
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// JobLockRepository coordinates scheduled jobs across instances through Redis. An instance
+// claims a job's run for an interval by taking the job's lock and marking the interval as run;
+// the lock is renewed while the run lasts so runs never overlap.
+type JobLockRepository struct {
+	redis redis.UniversalClient
+}
+
+func NewJobLockRepository(redisClient redis.UniversalClient) *JobLockRepository {
+	return &JobLockRepository{redis: redisClient}
+}
+
+// claimJobScript takes the job's lock (KEYS[1]) and marks the interval as run (KEYS[2]), unless
+// the interval already ran or another instance is still running the job
+const claimJobScript = `
+	if redis.call('EXISTS', KEYS[2]) == 1 then
+		return 0
+	end
+	if not redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+		return 0
+	end
+	redis.call('SET', KEYS[2], ARGV[1], 'PX', ARGV[3])
+	return 1
+`
+
+// renewJobScript extends the job's lock if the instance still holds it
+const renewJobScript = `
+	if redis.call('GET', KEYS[1]) == ARGV[1] then
+		return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	end
+	return 0
+`
+
+// releaseJobScript drops the job's lock if the instance still holds it
+const releaseJobScript = `
+	if redis.call('GET', KEYS[1]) == ARGV[1] then
+		return redis.call('DEL', KEYS[1])
+	end
+	return 0
+`
+
+// Claim claims the job's run for the interval starting at slot, holding its lock for lease.
+// False means the interval already ran or the job is running elsewhere.
+func (s *JobLockRepository) Claim(ctx context.Context, job, owner string, slot time.Time, interval, lease time.Duration) (bool, error) {
+	keys := []string{redisconn.JobLockKey(job), redisconn.JobRunKey(job, slot.Unix())}
+	claimed, err := s.redis.Eval(ctx, claimJobScript, keys, owner, lease.Milliseconds(), interval.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return claimed == 1, nil
+}
+
+// Renew extends the job's lock by lease. False means the lock expired and may have been taken.
+func (s *JobLockRepository) Renew(ctx context.Context, job, owner string, lease time.Duration) (bool, error) {
+	renewed, err := s.redis.Eval(ctx, renewJobScript, []string{redisconn.JobLockKey(job)}, owner, lease.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return renewed == 1, nil
+}
+
+// Release drops the job's lock once its run is over
+func (s *JobLockRepository) Release(ctx context.Context, job, owner string) error {
+	return s.redis.Eval(ctx, releaseJobScript, []string{redisconn.JobLockKey(job)}, owner).Err()
+}
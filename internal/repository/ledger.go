@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"api/pkg/money"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// LedgerRepository reports on the double-entry ledger. Journals are posted by the repositories
+// recording the movements themselves, in the same transaction.
+type LedgerRepository struct {
+	db *gorm.DB
+}
+
+func NewLedgerRepository(db *gorm.DB) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// GetBalances totals the debits and credits of every ledger account over the journals the
+// filter selects
+func (r *LedgerRepository) GetBalances(ctx context.Context, filter entities.LedgerFilter) (*entities.LedgerReport, error) {
+	query := conn(ctx, r.db).Table("ledger_entries").
+		Joins("JOIN ledger_journals ON ledger_journals.id = ledger_entries.journal_id").
+		Scopes(tenantScope(ctx, "ledger_journals"))
+	if filter.EventID != 0 {
+		query = query.Where("ledger_journals.event_id = ?", filter.EventID)
+	}
+	if filter.From != nil {
+		query = query.Where("ledger_journals.created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("ledger_journals.created_at < ?", *filter.To)
+	}
+
+	report := &entities.LedgerReport{Accounts: []entities.LedgerBalance{}}
+	if err := query.
+		Select("ledger_entries.account AS account, SUM(ledger_entries.debit) AS debits, SUM(ledger_entries.credit) AS credits").
+		Group("ledger_entries.account").
+		Order("ledger_entries.account ASC").
+		Scan(&report.Accounts).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to total ledger balances", err)
+	}
+
+	for i := range report.Accounts {
+		account := &report.Accounts[i]
+		account.Balance = account.Debits - account.Credits
+		report.TotalDebits += account.Debits
+		report.TotalCredits += account.Credits
+	}
+	report.Balanced = report.TotalDebits == report.TotalCredits
+	return report, nil
+}
+
+// postJournal records a journal with its entries inside the caller's transaction. Journals
+// whose debits don't equal their credits are refused, as are empty ones.
+func postJournal(tx *gorm.DB, journal *entities.LedgerJournal) error {
+	var debits, credits money.Money
+	for _, entry := range journal.Entries {
+		debits += entry.Debit
+		credits += entry.Credit
+	}
+	if len(journal.Entries) < 2 || debits != credits {
+		return errors.NewInternalError("Failed to post ledger journal",
+			fmt.Errorf("unbalanced %s journal: debits %s, credits %s", journal.Kind, debits, credits))
+	}
+
+	if err := tx.Create(journal).Error; err != nil {
+		return errors.NewInternalError("Failed to post ledger journal", err)
+	}
+	return nil
+}
+
+// transfer builds a journal moving an amount from one account to another: the debited
+// account receives it, the credited one gives it
+func transfer(kind, debit, credit string, amount money.Money) *entities.LedgerJournal {
+	return &entities.LedgerJournal{
+		Kind: kind,
+		Entries: []entities.LedgerEntry{
+			{Account: debit, Debit: amount},
+			{Account: credit, Credit: amount},
+		},
+	}
+}
+
+// postBookingSale records the money taken for a booking. Sandbox, complimentary and fully
+// discounted bookings took none.
+func postBookingSale(tx *gorm.DB, booking *entities.Booking) error {
+	if booking.PaymentStatus != constants.PaymentStatusPaid || booking.TotalAmount <= 0 {
+		return nil
+	}
+	journal := transfer(constants.LedgerKindSale, constants.LedgerAccountCash, constants.LedgerAccountSales, booking.TotalAmount)
+	journal.TenantID = booking.TenantID
+	journal.BookingID = &booking.ID
+	journal.EventID = &booking.EventID
+	journal.Description = fmt.Sprintf("%s booking %d", booking.Channel, booking.ID)
+	return postJournal(tx, journal)
+}
+
+// postBookingRefund records the money returned when a paid booking is cancelled
+func postBookingRefund(tx *gorm.DB, booking *entities.Booking) error {
+	if booking.PaymentStatus != constants.PaymentStatusPaid || booking.TotalAmount <= 0 {
+		return nil
+	}
+	journal := transfer(constants.LedgerKindRefund, constants.LedgerAccountRefunds, constants.LedgerAccountCash, booking.TotalAmount)
+	journal.TenantID = booking.TenantID
+	journal.BookingID = &booking.ID
+	journal.EventID = &booking.EventID
+	journal.Description = fmt.Sprintf("cancelled booking %d", booking.ID)
+	return postJournal(tx, journal)
+}
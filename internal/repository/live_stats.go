@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"api/internal/entities"
+	redisconn "api/internal/redis"
+	"api/pkg/errors"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Booking flow outcomes counted per event and minute
+const (
+	LiveIntentsCreated        = "intents_created"
+	LiveIntentsRejected       = "intents_rejected"
+	LiveConfirmations         = "confirmations"
+	LiveConfirmationsRejected = "confirmations_rejected"
+	LiveErrors                = "errors"
+	LiveSeatsReleased         = "seats_released" // seats freed by expired or cancelled intents and bookings
+	LiveLockConflicts         = "lock_conflicts" // intents refused because another user holds the seat
+)
+
+// liveCounters are the counters of a minute, in the order GetMinutes reads them
+var liveCounters = []string{LiveIntentsCreated, LiveIntentsRejected, LiveConfirmations, LiveConfirmationsRejected, LiveErrors, LiveSeatsReleased, LiveLockConflicts}
+
+// liveCounterTTL bounds how far back the live dashboard can look
+const liveCounterTTL = time.Hour
+
+// MaxLiveWindow is the longest window the live dashboard can report on, in minutes
+const MaxLiveWindow = int(liveCounterTTL / time.Minute)
+
+// LiveStatsRepository keeps the Redis counters behind the live on-sale dashboard. Counting is
+// best effort: a failed increment is logged and never fails the booking it counts.
+type LiveStatsRepository struct {
+	redis redis.UniversalClient
+}
+
+func NewLiveStatsRepository(redisClient redis.UniversalClient) *LiveStatsRepository {
+	return &LiveStatsRepository{
+		redis: redisClient,
+	}
+}
+
+// Record counts one occurrence of a booking flow outcome for an event, and across all events,
+// in the current minute
+func (r *LiveStatsRepository) Record(ctx context.Context, eventID uint, counter string) {
+	minute := time.Now().Unix() / 60
+	key := redisconn.LiveCounterKey(eventID, counter, minute)
+	platformKey := redisconn.PlatformCounterKey(counter, minute)
+
+	// The two keys hash to different slots, so they are counted in a plain pipeline
+	pipe := r.redis.Pipeline()
+	pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, liveCounterTTL)
+	pipe.Incr(ctx, platformKey)
+	pipe.Expire(ctx, platformKey, liveCounterTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		warnLockError("record live counter", err)
+	}
+}
+
+// RecordOutcome counts an intent or confirmation attempt as succeeded, rejected, or failed
+// with an internal error
+func (r *LiveStatsRepository) RecordOutcome(ctx context.Context, eventID uint, succeeded, rejected string, err error) {
+	switch {
+	case err == nil:
+		r.Record(ctx, eventID, succeeded)
+	case isInternalError(err):
+		r.Record(ctx, eventID, LiveErrors)
+	default:
+		r.Record(ctx, eventID, rejected)
+	}
+}
+
+func isInternalError(err error) bool {
+	appErr, ok := err.(*errors.AppError)
+	return !ok || appErr.Type == "INTERNAL_ERROR"
+}
+
+// GetMinutes returns an event's counts for each of the last minutes, the current one included,
+// oldest first
+func (r *LiveStatsRepository) GetMinutes(ctx context.Context, eventID uint, minutes int) ([]entities.LiveMinuteStats, error) {
+	// All of an event's keys share its hash tag, so one MGET reads them even in cluster mode
+	return r.getMinutes(ctx, minutes, func(counter string, minute int64) string {
+		return redisconn.LiveCounterKey(eventID, counter, minute)
+	})
+}
+
+// GetPlatformMinutes returns the counts across all events for each of the last minutes, oldest
+// first
+func (r *LiveStatsRepository) GetPlatformMinutes(ctx context.Context, minutes int) ([]entities.LiveMinuteStats, error) {
+	return r.getMinutes(ctx, minutes, redisconn.PlatformCounterKey)
+}
+
+func (r *LiveStatsRepository) getMinutes(ctx context.Context, minutes int, key func(counter string, minute int64) string) ([]entities.LiveMinuteStats, error) {
+	current := time.Now().Unix() / 60
+
+	keys := make([]string, 0, minutes*len(liveCounters))
+	for i := minutes - 1; i >= 0; i-- {
+		for _, counter := range liveCounters {
+			keys = append(keys, key(counter, current-int64(i)))
+		}
+	}
+	values, err := r.redis.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get live counters: %w", err)
+	}
+
+	stats := make([]entities.LiveMinuteStats, minutes)
+	for i := range stats {
+		counts := make([]int64, len(liveCounters))
+		for j := range liveCounters {
+			if s, ok := values[i*len(liveCounters)+j].(string); ok {
+				counts[j], _ = strconv.ParseInt(s, 10, 64)
+			}
+		}
+		stats[i] = entities.LiveMinuteStats{
+			Minute:                time.Unix((current-int64(minutes-1-i))*60, 0).UTC(),
+			IntentsCreated:        counts[0],
+			IntentsRejected:       counts[1],
+			Confirmations:         counts[2],
+			ConfirmationsRejected: counts[3],
+			Errors:                counts[4],
+			SeatsReleased:         counts[5],
+			LockConflicts:         counts[6],
+		}
+	}
+	return stats, nil
+}
+
+// CountLocksHeld returns how many of an event's seats are locked right now, from the index
+// the lock scripts maintain. Expired entries are dropped on the way.
+func (r *LiveStatsRepository) CountLocksHeld(ctx context.Context, eventID uint) (int64, error) {
+	key := redisconn.LiveLocksKey(eventID)
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	pipe := r.redis.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", "("+now)
+	held := pipe.ZCard(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to count seat locks: %w", err)
+	}
+	return held.Val(), nil
+}
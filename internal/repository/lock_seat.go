@@ -2,35 +2,80 @@ package repository
 
 import (
 	"api/constants"
+	redisconn "api/internal/redis"
+	logger "api/pkg/logging"
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
-type SeatLockRepository struct {
-	redis *redis.Client
+type SeatLockRepository interface {
+	LockSeat(ctx context.Context, eventID, seatID uint, userID uint, intentID string, expiresAt time.Time) error
+	UnlockSeat(ctx context.Context, eventID, seatID uint, userID uint, intentID string) error
+	IsLocked(ctx context.Context, eventID, seatID uint) (bool, string, error)
+	IsLockedByUser(ctx context.Context, eventID, seatID uint, userID uint) (bool, string, error)
+	ExtendLock(ctx context.Context, eventID, seatID uint, userID uint, intentID string, expiresAt time.Time) error
+	AlignLock(ctx context.Context, eventID, seatID uint, userID uint, intentID string, expiresAt time.Time, tolerance time.Duration) (int, time.Duration, error)
+	GetLockTTL(ctx context.Context, eventID, seatID uint) (time.Duration, error)
+	ListLocks(ctx context.Context, eventID uint) (map[uint]string, error)
+	RecordHeartbeat(ctx context.Context, intentID uint, at time.Time) error
+	GetStaleHeartbeats(ctx context.Context, before time.Time) ([]uint, error)
+	ClearHeartbeat(ctx context.Context, intentID uint) error
+	CleanupExpiredLocks(ctx context.Context) error
 }
 
-func NewSeatLockRepository(redisClient *redis.Client) *SeatLockRepository {
-	return &SeatLockRepository{
+type seatLockRepository struct {
+	redis redis.UniversalClient
+}
+
+func NewSeatLockRepository(redisClient redis.UniversalClient) SeatLockRepository {
+	return &seatLockRepository{
 		redis: redisClient,
 	}
 }
 
-// LockSeat creates a lock for a specific seat with TTL
-func (s *SeatLockRepository) LockSeat(ctx context.Context, seatID uint, userID uint, intentID string) error {
-	key := fmt.Sprintf("%s%d", constants.SeatLockPrefix, seatID)
+// warnLockError logs a failed Redis lock operation the database state makes up for. Errors
+// from degraded mode aren't logged one by one: entering it is logged once.
+func warnLockError(action string, err error) {
+	if !redisconn.IsUnavailable(err) {
+		logger.Warnf("Failed to %s: %v", action, err)
+	}
+}
+
+// Every script that takes or gives up a seat lock keeps the event's live lock index (KEYS[2])
+// in step, scoring each locked seat by its lock expiry so held locks can be counted without
+// scanning keys. The index outlives its last lock by an hour.
+
+// lockSeatScript sets the lock only if the seat is free and expires it at the exact millisecond
+// the intent does, rather than after a TTL measured from whenever the command arrives
+const lockSeatScript = `
+	if redis.call('SET', KEYS[1], ARGV[1], 'NX') then
+		redis.call('PEXPIREAT', KEYS[1], ARGV[2])
+		redis.call('ZADD', KEYS[2], ARGV[2], ARGV[3])
+		redis.call('EXPIRE', KEYS[2], 3600)
+		return 1
+	end
+	return 0
+`
+
+// lockKeys returns the keys of a seat's lock scripts: the lock and the event's live lock index
+func lockKeys(eventID, seatID uint) []string {
+	return []string{redisconn.SeatLockKey(eventID, seatID), redisconn.LiveLocksKey(eventID)}
+}
+
+// LockSeat creates a lock for a specific seat that expires at expiresAt, the intent's lock expiry
+func (s *seatLockRepository) LockSeat(ctx context.Context, eventID, seatID uint, userID uint, intentID string, expiresAt time.Time) error {
 	value := fmt.Sprintf("%d:%s", userID, intentID)
 
-	// Try to set the lock with NX (only if not exists) and TTL
-	result := s.redis.SetNX(ctx, key, value, time.Duration(constants.SeatLockDuration)*time.Minute)
-	if result.Err() != nil {
-		return fmt.Errorf("failed to create seat lock: %w", result.Err())
+	locked, err := s.redis.Eval(ctx, lockSeatScript, lockKeys(eventID, seatID), value, expiresAt.UnixMilli(), seatID).Int()
+	if err != nil {
+		return fmt.Errorf("failed to create seat lock: %w", err)
 	}
 
-	if !result.Val() {
+	if locked == 0 {
 		return fmt.Errorf("seat is already locked")
 	}
 
@@ -38,8 +83,7 @@ func (s *SeatLockRepository) LockSeat(ctx context.Context, seatID uint, userID u
 }
 
 // UnlockSeat removes the lock for a specific seat
-func (s *SeatLockRepository) UnlockSeat(ctx context.Context, seatID uint, userID uint, intentID string) error {
-	key := fmt.Sprintf("%s%d", constants.SeatLockPrefix, seatID)
+func (s *seatLockRepository) UnlockSeat(ctx context.Context, eventID, seatID uint, userID uint, intentID string) error {
 	expectedValue := fmt.Sprintf("%d:%s", userID, intentID)
 
 	// Lua script to atomically check and delete
@@ -48,13 +92,14 @@ func (s *SeatLockRepository) UnlockSeat(ctx context.Context, seatID uint, userID
 		local expected = ARGV[1]
 		local current = redis.call('GET', key)
 		if current == expected then
+			redis.call('ZREM', KEYS[2], ARGV[2])
 			return redis.call('DEL', key)
 		else
 			return 0
 		end
 	`
 
-	result := s.redis.Eval(ctx, script, []string{key}, expectedValue)
+	result := s.redis.Eval(ctx, script, lockKeys(eventID, seatID), expectedValue, seatID)
 	if result.Err() != nil {
 		return fmt.Errorf("failed to unlock seat: %w", result.Err())
 	}
@@ -63,8 +108,8 @@ func (s *SeatLockRepository) UnlockSeat(ctx context.Context, seatID uint, userID
 }
 
 // IsLocked checks if a seat is currently locked
-func (s *SeatLockRepository) IsLocked(ctx context.Context, seatID uint) (bool, string, error) {
-	key := fmt.Sprintf("%s%d", constants.SeatLockPrefix, seatID)
+func (s *seatLockRepository) IsLocked(ctx context.Context, eventID, seatID uint) (bool, string, error) {
+	key := redisconn.SeatLockKey(eventID, seatID)
 
 	result := s.redis.Get(ctx, key)
 	if result.Err() == redis.Nil {
@@ -78,8 +123,8 @@ func (s *SeatLockRepository) IsLocked(ctx context.Context, seatID uint) (bool, s
 }
 
 // IsLockedByUser checks if a seat is locked by a specific user
-func (s *SeatLockRepository) IsLockedByUser(ctx context.Context, seatID uint, userID uint) (bool, string, error) {
-	key := fmt.Sprintf("%s%d", constants.SeatLockPrefix, seatID)
+func (s *seatLockRepository) IsLockedByUser(ctx context.Context, eventID, seatID uint, userID uint) (bool, string, error) {
+	key := redisconn.SeatLockKey(eventID, seatID)
 
 	result := s.redis.Get(ctx, key)
 	if result.Err() == redis.Nil {
@@ -99,26 +144,26 @@ func (s *SeatLockRepository) IsLockedByUser(ctx context.Context, seatID uint, us
 	return false, lockValue, nil
 }
 
-// ExtendLock extends the TTL of an existing lock
-func (s *SeatLockRepository) ExtendLock(ctx context.Context, seatID uint, userID uint, intentID string) error {
-	key := fmt.Sprintf("%s%d", constants.SeatLockPrefix, seatID)
+// ExtendLock moves the expiry of an existing lock to expiresAt, which the caller stores as
+// the intent's new lock expiry
+func (s *seatLockRepository) ExtendLock(ctx context.Context, eventID, seatID uint, userID uint, intentID string, expiresAt time.Time) error {
 	expectedValue := fmt.Sprintf("%d:%s", userID, intentID)
 
-	// Lua script to atomically check and extend TTL
+	// Lua script to atomically check and move the expiry
 	script := `
 		local key = KEYS[1]
 		local expected = ARGV[1]
-		local ttl = ARGV[2]
 		local current = redis.call('GET', key)
 		if current == expected then
-			return redis.call('EXPIRE', key, ttl)
+			redis.call('ZADD', KEYS[2], ARGV[2], ARGV[3])
+			redis.call('EXPIRE', KEYS[2], 3600)
+			return redis.call('PEXPIREAT', key, ARGV[2])
 		else
 			return 0
 		end
 	`
 
-	ttlSeconds := constants.SeatLockDuration * 60
-	result := s.redis.Eval(ctx, script, []string{key}, expectedValue, ttlSeconds)
+	result := s.redis.Eval(ctx, script, lockKeys(eventID, seatID), expectedValue, expiresAt.UnixMilli(), seatID)
 	if result.Err() != nil {
 		return fmt.Errorf("failed to extend seat lock: %w", result.Err())
 	}
@@ -130,9 +175,56 @@ func (s *SeatLockRepository) ExtendLock(ctx context.Context, seatID uint, userID
 	return nil
 }
 
+// Outcomes of AlignLock
+const (
+	LockAligned   = iota // the lock expires when the intent does
+	LockRealigned        // the lock's expiry had drifted and was reset
+	LockRestored         // the lock was missing and was recreated
+	LockConflict         // another intent holds the lock
+)
+
+// alignLockScript compares the lock's expiry, read against the Redis clock, with the intent's
+// and resets it when they differ by more than the tolerance. It returns the outcome and the
+// drift in milliseconds. The seat is (re)indexed with the intent's expiry unless another
+// intent holds it.
+const alignLockScript = `
+	local current = redis.call('GET', KEYS[1])
+	if current and current ~= ARGV[1] then
+		return {3, 0}
+	end
+	redis.call('ZADD', KEYS[2], ARGV[2], ARGV[4])
+	redis.call('EXPIRE', KEYS[2], 3600)
+	if not current then
+		redis.call('SET', KEYS[1], ARGV[1])
+		redis.call('PEXPIREAT', KEYS[1], ARGV[2])
+		return {2, 0}
+	end
+	local pttl = redis.call('PTTL', KEYS[1])
+	local now = redis.call('TIME')
+	local expiry = tonumber(now[1]) * 1000 + math.floor(tonumber(now[2]) / 1000) + pttl
+	local drift = expiry - tonumber(ARGV[2])
+	if math.abs(drift) > tonumber(ARGV[3]) then
+		redis.call('PEXPIREAT', KEYS[1], ARGV[2])
+		return {1, drift}
+	end
+	return {0, drift}
+`
+
+// AlignLock makes the seat lock of a pending intent expire at the intent's lock expiry,
+// recreating it if it was lost. It returns one of the Lock* outcomes and the drift found.
+func (s *seatLockRepository) AlignLock(ctx context.Context, eventID, seatID uint, userID uint, intentID string, expiresAt time.Time, tolerance time.Duration) (int, time.Duration, error) {
+	value := fmt.Sprintf("%d:%s", userID, intentID)
+
+	result, err := s.redis.Eval(ctx, alignLockScript, lockKeys(eventID, seatID), value, expiresAt.UnixMilli(), tolerance.Milliseconds(), seatID).Int64Slice()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to align seat lock: %w", err)
+	}
+	return int(result[0]), time.Duration(result[1]) * time.Millisecond, nil
+}
+
 // GetLockTTL returns the remaining TTL for a seat lock
-func (s *SeatLockRepository) GetLockTTL(ctx context.Context, seatID uint) (time.Duration, error) {
-	key := fmt.Sprintf("%s%d", constants.SeatLockPrefix, seatID)
+func (s *seatLockRepository) GetLockTTL(ctx context.Context, eventID, seatID uint) (time.Duration, error) {
+	key := redisconn.SeatLockKey(eventID, seatID)
 
 	result := s.redis.TTL(ctx, key)
 	if result.Err() != nil {
@@ -142,25 +234,101 @@ func (s *SeatLockRepository) GetLockTTL(ctx context.Context, seatID uint) (time.
 	return result.Val(), nil
 }
 
-// CleanupExpiredLocks removes expired locks (this should be called periodically)
-func (s *SeatLockRepository) CleanupExpiredLocks(ctx context.Context) error {
-	pattern := constants.SeatLockPrefix + "*"
+// ListLocks returns the value of every seat lock of an event, by seat, as found through the
+// event's live lock index
+func (s *seatLockRepository) ListLocks(ctx context.Context, eventID uint) (map[uint]string, error) {
+	members, err := s.redis.ZRange(ctx, redisconn.LiveLocksKey(eventID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list seat locks: %w", err)
+	}
+	locks := make(map[uint]string, len(members))
+	if len(members) == 0 {
+		return locks, nil
+	}
 
-	keys, err := s.redis.Keys(ctx, pattern).Result()
+	seatIDs := make([]uint, 0, len(members))
+	keys := make([]string, 0, len(members))
+	for _, member := range members {
+		seatID, err := strconv.ParseUint(member, 10, 32)
+		if err != nil {
+			continue
+		}
+		seatIDs = append(seatIDs, uint(seatID))
+		keys = append(keys, redisconn.SeatLockKey(eventID, uint(seatID)))
+	}
+	// An event's keys share a hash tag, so this works on a cluster too
+	values, err := s.redis.MGet(ctx, keys...).Result()
 	if err != nil {
-		return fmt.Errorf("failed to get lock keys: %w", err)
+		return nil, fmt.Errorf("failed to list seat locks: %w", err)
 	}
+	for i, value := range values {
+		// Index entries outlive locks that expired
+		if value, ok := value.(string); ok {
+			locks[seatIDs[i]] = value
+		}
+	}
+	return locks, nil
+}
 
-	for _, key := range keys {
-		ttl, err := s.redis.TTL(ctx, key).Result()
+// RecordHeartbeat stores the time of the latest checkout heartbeat for an intent
+func (s *seatLockRepository) RecordHeartbeat(ctx context.Context, intentID uint, at time.Time) error {
+	if err := s.redis.ZAdd(ctx, constants.IntentHeartbeatKey, redis.Z{
+		Score:  float64(at.Unix()),
+		Member: intentID,
+	}).Err(); err != nil {
+		return fmt.Errorf("failed to record heartbeat: %w", err)
+	}
+	return nil
+}
+
+// GetStaleHeartbeats returns the intents whose latest heartbeat is older than the given time
+func (s *seatLockRepository) GetStaleHeartbeats(ctx context.Context, before time.Time) ([]uint, error) {
+	members, err := s.redis.ZRangeByScore(ctx, constants.IntentHeartbeatKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: fmt.Sprintf("(%d", before.Unix()),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stale heartbeats: %w", err)
+	}
+
+	intentIDs := make([]uint, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseUint(member, 10, 32)
 		if err != nil {
 			continue
 		}
+		intentIDs = append(intentIDs, uint(id))
+	}
+
+	return intentIDs, nil
+}
+
+// ClearHeartbeat stops heartbeat tracking for an intent
+func (s *seatLockRepository) ClearHeartbeat(ctx context.Context, intentID uint) error {
+	if err := s.redis.ZRem(ctx, constants.IntentHeartbeatKey, intentID).Err(); err != nil {
+		return fmt.Errorf("failed to clear heartbeat: %w", err)
+	}
+	return nil
+}
+
+// CleanupExpiredLocks removes expired locks (this should be called periodically)
+func (s *seatLockRepository) CleanupExpiredLocks(ctx context.Context) error {
+	pattern := constants.SeatLockPrefix + "*"
+
+	err := redisconn.ScanKeys(ctx, s.redis, pattern, func(key string) error {
+		ttl, err := s.redis.TTL(ctx, key).Result()
+		if err != nil {
+			return nil
+		}
 
 		// If TTL is -1 (no expiry) or -2 (key doesn't exist), clean it up
 		if ttl < 0 {
 			s.redis.Del(ctx, key)
 		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get lock keys: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"api/pkg/money"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoyaltyRepository struct {
+	db *gorm.DB
+}
+
+func NewLoyaltyRepository(db *gorm.DB) *LoyaltyRepository {
+	return &LoyaltyRepository{db: db}
+}
+
+// GetAccount returns the user's points balance, lifetime points and tier
+func (s *LoyaltyRepository) GetAccount(ctx context.Context, userID uint) (*entities.User, error) {
+	var user entities.User
+	if err := conn(ctx, s.db).
+		Select("id", "loyalty_points", "lifetime_points", "membership_tier").
+		First(&user, userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("User not found", errors.ErrUserNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch loyalty account", err)
+	}
+	return &user, nil
+}
+
+// ListTransactions returns the user's points ledger, newest first
+func (s *LoyaltyRepository) ListTransactions(ctx context.Context, userID uint, limit, offset int) ([]entities.LoyaltyTransaction, int64, error) {
+	var transactions []entities.LoyaltyTransaction
+	var total int64
+
+	query := conn(ctx, s.db).Model(&entities.LoyaltyTransaction{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to count loyalty transactions", err)
+	}
+
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&transactions).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to fetch loyalty transactions", err)
+	}
+
+	return transactions, total, nil
+}
+
+// lockLoyaltyAccount loads the user's points row with a row lock for the rest of the transaction
+func lockLoyaltyAccount(tx *gorm.DB, userID uint) (*entities.User, error) {
+	var user entities.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "loyalty_points", "lifetime_points", "membership_tier").
+		First(&user, userID).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch loyalty account", err)
+	}
+	return &user, nil
+}
+
+// loyaltyDiscount validates a redemption against the balance and caps it at the price.
+// It returns the points actually redeemed and the discount they are worth.
+func loyaltyDiscount(user *entities.User, requested int, price money.Money) (int, money.Money, error) {
+	if requested <= 0 {
+		return 0, 0, nil
+	}
+	if requested > user.LoyaltyPoints {
+		return 0, 0, errors.NewBadRequestError(constants.ErrInsufficientPoints, nil)
+	}
+
+	points := int64(requested)
+	perUnit := int64(constants.LoyaltyPointsPerDiscountUnit)
+	// Enough points to cover the price, rounded up to a whole point
+	if maxPoints := (price.Minor()*perUnit + money.MinorPerMajor - 1) / money.MinorPerMajor; points > maxPoints {
+		points = maxPoints
+	}
+	discount := money.FromMinor(points * money.MinorPerMajor / perUnit)
+	return int(points), min(price, discount), nil
+}
+
+// pointsEarned returns the points earned for paying the given amount
+func pointsEarned(amount money.Money) int {
+	return int(amount.Minor() * constants.LoyaltyPointsPerCurrencyUnit / money.MinorPerMajor)
+}
+
+// applyLoyaltyPoints adjusts a locked account by delta points and records the ledger entry.
+// Earned points also count towards the lifetime total, which may upgrade the tier; tiers
+// are never downgraded and tiers assigned manually by admins are left untouched.
+func applyLoyaltyPoints(tx *gorm.DB, user *entities.User, bookingID uint, delta, lifetimeDelta int, reason string) error {
+	if delta == 0 && lifetimeDelta == 0 {
+		return nil
+	}
+
+	user.LoyaltyPoints = max(user.LoyaltyPoints+delta, 0)
+	user.LifetimePoints = max(user.LifetimePoints+lifetimeDelta, 0)
+
+	updates := map[string]interface{}{
+		"loyalty_points":  user.LoyaltyPoints,
+		"lifetime_points": user.LifetimePoints,
+		"updated_at":      time.Now(),
+	}
+	current := entities.LoyaltyTierRank(user.MembershipTier)
+	manual := user.MembershipTier != "" && current < 0
+	if earned := entities.LoyaltyTierFor(user.LifetimePoints); earned != "" && !manual && entities.LoyaltyTierRank(earned) > current {
+		user.MembershipTier = earned
+		updates["membership_tier"] = earned
+	}
+
+	if err := tx.Model(&entities.User{}).Where("id = ?", user.ID).Updates(updates).Error; err != nil {
+		return errors.NewInternalError("Failed to update loyalty points", err)
+	}
+
+	entry := &entities.LoyaltyTransaction{
+		UserID:    user.ID,
+		BookingID: &bookingID,
+		Points:    delta,
+		Reason:    reason,
+		Balance:   user.LoyaltyPoints,
+	}
+	if err := tx.Create(entry).Error; err != nil {
+		return errors.NewInternalError("Failed to record loyalty transaction", err)
+	}
+	return nil
+}
+
+// reverseBookingLoyalty refunds redeemed points and takes back earned points for a cancelled booking
+func reverseBookingLoyalty(tx *gorm.DB, booking *entities.Booking) error {
+	if booking.PointsRedeemed == 0 && booking.PointsEarned == 0 {
+		return nil
+	}
+
+	user, err := lockLoyaltyAccount(tx, booking.UserID)
+	if err != nil {
+		return err
+	}
+	return applyLoyaltyPoints(tx, user, booking.ID,
+		booking.PointsRedeemed-booking.PointsEarned, -booking.PointsEarned, constants.LoyaltyReasonReversal)
+}
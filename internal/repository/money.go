@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"api/internal/entities"
+	"api/pkg/money"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// moneyModels are the entities with amount columns. Bookings also stand for their archive table.
+var moneyModels = []interface{}{
+	&entities.Event{},
+	&entities.Seat{},
+	&entities.Booking{},
+	&entities.Dispute{},
+	&entities.PaymentTransaction{},
+	&entities.LedgerEntry{},
+	&entities.ReferralCommission{},
+	&entities.EventSettlement{},
+	&entities.SeatPriceHistory{},
+}
+
+type MoneyRepository struct {
+	db *gorm.DB
+}
+
+func NewMoneyRepository(db *gorm.DB) *MoneyRepository {
+	return &MoneyRepository{db: db}
+}
+
+// ConvertColumns converts amount columns still holding decimal major units, from before amounts
+// were stored as whole minor units, rounding each amount to the nearest minor unit. It returns
+// the number of columns converted. It runs before AutoMigrate, which would otherwise cast the
+// decimals to integers as they are.
+func (s *MoneyRepository) ConvertColumns(ctx context.Context) (int, error) {
+	columns, err := s.moneyColumns()
+	if err != nil {
+		return 0, err
+	}
+
+	converted := 0
+	err = conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		for _, table := range columns {
+			var decimal []string
+			if err := tx.Raw(`SELECT column_name FROM information_schema.columns
+				WHERE table_schema = current_schema() AND table_name = ? AND column_name IN ?
+				AND data_type IN ('numeric', 'double precision', 'real')
+				ORDER BY ordinal_position`, table.name, table.columns).Scan(&decimal).Error; err != nil {
+				return err
+			}
+			if len(decimal) == 0 {
+				continue
+			}
+
+			// One statement per table, so each table is rewritten once
+			alters := make([]string, len(decimal))
+			for i, column := range decimal {
+				alters[i] = fmt.Sprintf(`ALTER COLUMN %[1]q TYPE bigint USING round(%[1]q * %[2]d)::bigint`, column, money.MinorPerMajor)
+			}
+			if err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s %s`, table.name, strings.Join(alters, ", "))).Error; err != nil {
+				return err
+			}
+			converted += len(decimal)
+		}
+		return nil
+	})
+	return converted, err
+}
+
+// moneyTable is a table's amount columns
+type moneyTable struct {
+	name    string
+	columns []string
+}
+
+// moneyColumns lists the amount columns of moneyModels, and of the bookings archive
+func (s *MoneyRepository) moneyColumns() ([]moneyTable, error) {
+	moneyType := reflect.TypeOf(money.Money(0))
+	cache := &sync.Map{}
+
+	var tables []moneyTable
+	for _, model := range moneyModels {
+		parsed, err := schema.Parse(model, cache, s.db.NamingStrategy)
+		if err != nil {
+			return nil, err
+		}
+		table := moneyTable{name: parsed.Table}
+		for _, field := range parsed.Fields {
+			if field.DBName != "" && field.FieldType == moneyType {
+				table.columns = append(table.columns, field.DBName)
+			}
+		}
+		tables = append(tables, table)
+		if parsed.Table == "bookings" {
+			tables = append(tables, moneyTable{name: bookingsArchiveTable, columns: table.columns})
+		}
+	}
+	return tables, nil
+}
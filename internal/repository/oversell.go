@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OversellRepository gives the oversell places of sold out free events to their waitlists
+type OversellRepository struct {
+	db *gorm.DB
+}
+
+func NewOversellRepository(db *gorm.DB) *OversellRepository {
+	return &OversellRepository{db: db}
+}
+
+// OversellOpening is a sold out event with oversell places nobody has taken yet
+type OversellOpening struct {
+	EventID uint
+	Places  int
+}
+
+// ListOpenings returns the events on sale at now whose seats are sold out and which have oversell
+// places left. Events whose online sales have closed are left out.
+func (r *OversellRepository) ListOpenings(ctx context.Context, now time.Time) ([]OversellOpening, error) {
+	var openings []OversellOpening
+	if err := conn(ctx, r.db).Model(&entities.Seat{}).
+		Select("seats.event_id, COUNT(*) as places").
+		Joins("JOIN events e ON e.id = seats.event_id").
+		Where("seats.is_oversell = true AND seats.is_available = true AND seats.is_locked = false").
+		Where("e.status IN ? AND e.available_seats = 0 AND e.sandbox = false", []string{constants.EventStatusActive, constants.EventStatusSoldOut}).
+		Where("e.start_time - make_interval(mins => COALESCE(e.sales_close_minutes_before_start, 0)) > ?", now).
+		Group("seats.event_id").
+		Order("seats.event_id").
+		Scan(&openings).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to list oversell openings", err)
+	}
+	return openings, nil
+}
+
+// BookPlace books the next free oversell place of an event for a user off its waitlist, as a
+// confirmed booking that takes no payment. Users already booked for the event aren't given
+// another, and the event's ticket limit applies as to any free booking, with ticketLimit
+// standing in for events without one.
+func (r *OversellRepository) BookPlace(ctx context.Context, eventID, userID uint, ticketLimit int, now time.Time) (*entities.Booking, error) {
+	var booking *entities.Booking
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var event entities.Event
+		if err := tx.Select("id", "tenant_id", "ticket_limit").First(&event, eventID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewNotFoundError("Event not found", errors.ErrRecordNotFound)
+			}
+			return errors.NewInternalError("Failed to fetch event", err)
+		}
+
+		// Locks the user's row, so a confirmation of theirs running meanwhile is counted
+		if err := checkTicketLimit(tx, &event, userID, ticketLimit); err != nil {
+			return err
+		}
+		var booked int64
+		if err := tx.Model(&entities.Booking{}).
+			Where("event_id = ? AND user_id = ? AND status = ?", eventID, userID, constants.BookingStatusConfirmed).
+			Count(&booked).Error; err != nil {
+			return errors.NewInternalError("Failed to fetch bookings", err)
+		}
+		if booked > 0 {
+			return errors.NewConflictError("User is already booked for this event", nil)
+		}
+
+		var seat entities.Seat
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("event_id = ? AND is_oversell = true AND is_available = true AND is_locked = false", eventID).
+			Order("\"column\" ASC").
+			First(&seat).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewBadRequestError(constants.ErrEventSoldOut, nil)
+			}
+			return errors.NewInternalError("Failed to fetch oversell place", err)
+		}
+
+		booking = &entities.Booking{
+			TenantID:      event.TenantID,
+			UserID:        userID,
+			EventID:       eventID,
+			SeatID:        seat.ID,
+			Status:        constants.BookingStatusConfirmed,
+			PaymentStatus: constants.PaymentStatusFree,
+			Channel:       constants.SalesChannelOversell,
+			BookedAt:      now,
+		}
+		if err := tx.Create(booking).Error; err != nil {
+			return errors.NewInternalError("Failed to create booking", err)
+		}
+
+		if err := tx.Model(&entities.Seat{}).Where("id = ?", seat.ID).
+			Updates(map[string]interface{}{
+				"is_available": false,
+				"updated_at":   now,
+			}).Error; err != nil {
+			return errors.NewInternalError("Failed to update seat", err)
+		}
+
+		booking.Seat = seat
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// syncOversellPlaces adds or removes oversell places so an event has as many as its oversell
+// percentage of its seats allows. Only places that were never booked are removed, so an event
+// can be left with more places than it allows until its oversell bookings are cancelled.
+func syncOversellPlaces(tx *gorm.DB, event *entities.Event) error {
+	var capacity int64
+	if err := tx.Model(&entities.Seat{}).
+		Where("event_id = ? AND is_oversell = false", event.ID).
+		Count(&capacity).Error; err != nil {
+		return errors.NewInternalError("Failed to count seats", err)
+	}
+	var places []entities.Seat
+	if err := tx.Select("id", "column").
+		Where("event_id = ? AND is_oversell = true", event.ID).
+		Order("\"column\" ASC").
+		Find(&places).Error; err != nil {
+		return errors.NewInternalError("Failed to fetch oversell places", err)
+	}
+
+	want := event.OversellPlaces(int(capacity))
+	switch {
+	case want > len(places):
+		// New places take the columns after the last one of row 0
+		next := 1
+		if len(places) > 0 {
+			next = places[len(places)-1].Column + 1
+		}
+		added := make([]entities.Seat, want-len(places))
+		for i := range added {
+			added[i] = entities.Seat{
+				EventID:     event.ID,
+				Row:         0,
+				Column:      next + i,
+				SeatType:    constants.SeatTypeStandard,
+				IsAvailable: true,
+				IsOversell:  true,
+			}
+		}
+		if err := tx.Omit(clause.Associations).Create(&added).Error; err != nil {
+			return errors.NewInternalError("Failed to create oversell places", err)
+		}
+	case want < len(places):
+		var unused []uint
+		if err := tx.Model(&entities.Seat{}).
+			Where("event_id = ? AND is_oversell = true AND is_available = true AND is_locked = false", event.ID).
+			Where("NOT EXISTS (SELECT 1 FROM bookings b WHERE b.seat_id = seats.id)").
+			Order("\"column\" DESC").
+			Limit(len(places)-want).
+			Pluck("id", &unused).Error; err != nil {
+			return errors.NewInternalError("Failed to fetch oversell places", err)
+		}
+		if len(unused) > 0 {
+			if err := tx.Delete(&entities.Seat{}, unused).Error; err != nil {
+				return errors.NewInternalError("Failed to remove oversell places", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) *PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+// ListTransactions returns payment transactions matching the filter, newest first
+func (s *PaymentRepository) ListTransactions(ctx context.Context, filter entities.PaymentTransactionFilter, limit, offset int) ([]entities.PaymentTransaction, int64, error) {
+	var transactions []entities.PaymentTransaction
+	var total int64
+
+	query := conn(ctx, s.db).Model(&entities.PaymentTransaction{}).
+		Scopes(tenantScopeVia(ctx, "payment_transactions.booking_id", "bookings"))
+	if filter.Provider != "" {
+		query = query.Where("provider = ?", filter.Provider)
+	}
+	if filter.ProviderReference != "" {
+		query = query.Where("provider_reference = ?", filter.ProviderReference)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.BookingID != 0 {
+		query = query.Where("booking_id = ?", filter.BookingID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to count payment transactions", err)
+	}
+
+	if err := query.
+		Preload("Events", func(db *gorm.DB) *gorm.DB { return db.Order("occurred_at ASC") }).
+		Order("created_at DESC").
+		Limit(limit).Offset(offset).
+		Find(&transactions).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to fetch payment transactions", err)
+	}
+
+	return transactions, total, nil
+}
+
+// GetTransaction returns a payment transaction with its status timeline and booking
+func (s *PaymentRepository) GetTransaction(ctx context.Context, transactionID uint) (*entities.PaymentTransaction, error) {
+	var transaction entities.PaymentTransaction
+
+	if err := conn(ctx, s.db).Scopes(tenantScopeVia(ctx, "payment_transactions.booking_id", "bookings")).
+		Preload("Booking").
+		Preload("Events", func(db *gorm.DB) *gorm.DB { return db.Order("occurred_at ASC") }).
+		First(&transaction, transactionID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Payment transaction not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch payment transaction", err)
+	}
+
+	return &transaction, nil
+}
+
+// createPaymentTransaction stores a transaction and the first entry of its status timeline
+func createPaymentTransaction(tx *gorm.DB, transaction *entities.PaymentTransaction) error {
+	if err := tx.Create(transaction).Error; err != nil {
+		return errors.NewInternalError("Failed to record payment transaction", err)
+	}
+
+	event := entities.PaymentTransactionEvent{
+		PaymentTransactionID: transaction.ID,
+		Status:               transaction.Status,
+		OccurredAt:           time.Now(),
+	}
+	if err := tx.Create(&event).Error; err != nil {
+		return errors.NewInternalError("Failed to record payment transaction status", err)
+	}
+
+	return nil
+}
+
+// transitionPaymentTransaction moves the payment transactions of a booking to a new status and
+// adds it to their timeline. Refunded and charged back transactions keep their status, and
+// bookings without a transaction, such as comps, are left alone.
+func transitionPaymentTransaction(tx *gorm.DB, bookingID uint, status string, occurredAt time.Time) error {
+	var transactions []entities.PaymentTransaction
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("booking_id = ? AND status NOT IN ?", bookingID,
+			[]string{status, constants.PaymentStatusRefunded, constants.PaymentStatusChargedBack}).
+		Find(&transactions).Error; err != nil {
+		return errors.NewInternalError("Failed to fetch payment transactions", err)
+	}
+
+	for _, transaction := range transactions {
+		if err := tx.Model(&transaction).Update("status", status).Error; err != nil {
+			return errors.NewInternalError("Failed to update payment transaction", err)
+		}
+		event := entities.PaymentTransactionEvent{
+			PaymentTransactionID: transaction.ID,
+			Status:               status,
+			OccurredAt:           occurredAt,
+		}
+		if err := tx.Create(&event).Error; err != nil {
+			return errors.NewInternalError("Failed to record payment transaction status", err)
+		}
+	}
+
+	return nil
+}
+
+// CountPaymentOutcomes counts the online payments that failed and succeeded since the given
+// time: payment attempts reported failed by checkouts retrying, and transactions other than
+// box-office sales that were paid, including those refunded or disputed since
+func (s *PaymentRepository) CountPaymentOutcomes(ctx context.Context, since time.Time) (failed, paid int64, err error) {
+	if err := conn(ctx, s.db).Model(&entities.PaymentAttempt{}).
+		Where("status = ? AND updated_at >= ?", constants.PaymentStatusFailed, since).
+		Count(&failed).Error; err != nil {
+		return 0, 0, errors.NewInternalError("Failed to count failed payments", err)
+	}
+	if err := conn(ctx, s.db).Model(&entities.PaymentTransaction{}).
+		Where("status NOT IN ? AND provider <> ? AND created_at >= ?",
+			[]string{constants.PaymentStatusPending, constants.PaymentStatusFailed}, constants.BoxOfficeProvider, since).
+		Count(&paid).Error; err != nil {
+		return 0, 0, errors.NewInternalError("Failed to count paid payments", err)
+	}
+	return failed, paid, nil
+}
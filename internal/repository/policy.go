@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// PolicyRepository stores users' acceptances of the terms of service and privacy policy
+type PolicyRepository struct {
+	db *gorm.DB
+}
+
+func NewPolicyRepository(db *gorm.DB) *PolicyRepository {
+	return &PolicyRepository{db: db}
+}
+
+// Record stores acceptances made together
+func (s *PolicyRepository) Record(ctx context.Context, acceptances []entities.PolicyAcceptance) error {
+	if len(acceptances) == 0 {
+		return nil
+	}
+	if err := conn(ctx, s.db).Create(&acceptances).Error; err != nil {
+		return errors.NewInternalError("Failed to record policy acceptance", err)
+	}
+	return nil
+}
+
+// AcceptedPolicies returns which of the given policy versions, by policy, the user accepted
+func (s *PolicyRepository) AcceptedPolicies(ctx context.Context, userID uint, versions map[string]string) ([]string, error) {
+	if len(versions) == 0 {
+		return nil, nil
+	}
+	query := conn(ctx, s.db).Model(&entities.PolicyAcceptance{}).Where("user_id = ?", userID)
+	matches := s.db.Where("1 = 0")
+	for policy, version := range versions {
+		matches = matches.Or("policy = ? AND version = ?", policy, version)
+	}
+
+	var accepted []string
+	if err := query.Where(matches).Distinct().Pluck("policy", &accepted).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to check policy acceptance", err)
+	}
+	return accepted, nil
+}
+
+// List returns the user's acceptances, newest first
+func (s *PolicyRepository) List(ctx context.Context, userID uint) ([]entities.PolicyAcceptance, error) {
+	var acceptances []entities.PolicyAcceptance
+	if err := conn(ctx, s.db).Where("user_id = ?", userID).Order("accepted_at DESC, id DESC").Find(&acceptances).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch policy acceptances", err)
+	}
+	return acceptances, nil
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"crypto/rand"
+	"math/big"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PresaleRepository struct {
+	db *gorm.DB
+}
+
+func NewPresaleRepository(db *gorm.DB) *PresaleRepository {
+	return &PresaleRepository{db: db}
+}
+
+// CreateBatch generates count unique codes for the batch's event and stores them with the batch
+func (s *PresaleRepository) CreateBatch(ctx context.Context, batch *entities.PresaleBatch, count int) error {
+	return conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		var eventCount int64
+		if err := tx.Model(&entities.Event{}).Scopes(tenantScope(ctx, "events")).
+			Where("id = ?", batch.EventID).Count(&eventCount).Error; err != nil {
+			return errors.NewInternalError("Failed to fetch event", err)
+		}
+		if eventCount == 0 {
+			return errors.NewNotFoundError(constants.ErrEventNotFound, errors.ErrRecordNotFound)
+		}
+
+		if err := tx.Create(batch).Error; err != nil {
+			return errors.NewInternalError("Failed to create presale batch", err)
+		}
+
+		codes := make([]entities.PresaleCode, count)
+		for i := range codes {
+			code, err := newPresaleCode()
+			if err != nil {
+				return errors.NewInternalError("Failed to generate presale code", err)
+			}
+			codes[i] = entities.PresaleCode{
+				BatchID: batch.ID,
+				EventID: batch.EventID,
+				Code:    code,
+				MaxUses: batch.MaxUses,
+			}
+		}
+		if err := tx.CreateInBatches(&codes, 200).Error; err != nil {
+			return errors.NewInternalError("Failed to create presale codes", err)
+		}
+
+		batch.Codes = codes
+		return nil
+	})
+}
+
+// ListBatches returns an event's presale batches with code usage totals, newest first
+func (s *PresaleRepository) ListBatches(ctx context.Context, eventID uint) ([]entities.PresaleBatchSummary, error) {
+	var summaries []entities.PresaleBatchSummary
+
+	if err := conn(ctx, s.db).
+		Table("presale_batches pb").
+		Select(`pb.id, pb.event_id, pb.name, pb.max_uses, pb.created_by, pb.created_at,
+			COUNT(pc.id) AS codes,
+			COUNT(pc.id) FILTER (WHERE pc.uses > 0) AS redeemed_codes,
+			COALESCE(SUM(pc.uses), 0) AS uses`).
+		Joins("LEFT JOIN presale_codes pc ON pc.batch_id = pb.id").
+		Scopes(tenantScopeVia(ctx, "pb.event_id", "events")).
+		Where("pb.event_id = ?", eventID).
+		Group("pb.id").
+		Order("pb.created_at DESC").
+		Scan(&summaries).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch presale batches", err)
+	}
+
+	return summaries, nil
+}
+
+// GetBatch returns a presale batch with all of its codes
+func (s *PresaleRepository) GetBatch(ctx context.Context, batchID uint) (*entities.PresaleBatch, error) {
+	var batch entities.PresaleBatch
+
+	if err := conn(ctx, s.db).Scopes(tenantScopeVia(ctx, "presale_batches.event_id", "events")).
+		Preload("Codes", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		First(&batch, batchID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Presale batch not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch presale batch", err)
+	}
+
+	return &batch, nil
+}
+
+// newPresaleCode generates a random code from an alphabet without lookalike characters
+func newPresaleCode() (string, error) {
+	return randomCode(constants.PresaleCodeLength)
+}
+
+// randomCode generates a code of length characters from the presale code alphabet
+func randomCode(length int) (string, error) {
+	alphabet := big.NewInt(int64(len(constants.PresaleCodeAlphabet)))
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, alphabet)
+		if err != nil {
+			return "", err
+		}
+		code[i] = constants.PresaleCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// checkSaleWindow rejects bookings before an event's general on-sale unless the presale is open
+// and the user either holds the early-access tier (or an earned tier above it) or has a valid
+// presale code. It returns the presale code that granted access, if one was needed.
+func checkSaleWindow(db *gorm.DB, event *entities.Event, userID uint, presaleCode string) (*entities.PresaleCode, error) {
+	now := time.Now()
+	if event.OnSaleAt == nil || !now.Before(*event.OnSaleAt) {
+		return nil, nil
+	}
+	if event.EarlyAccessAt == nil || now.Before(*event.EarlyAccessAt) {
+		return nil, errors.NewBadRequestError(constants.ErrNotOnSale, nil)
+	}
+
+	if event.EarlyAccessTier != "" {
+		var tier string
+		if err := db.Model(&entities.User{}).Select("membership_tier").Where("id = ?", userID).Scan(&tier).Error; err != nil {
+			return nil, errors.NewInternalError("Failed to fetch membership tier", err)
+		}
+
+		required := entities.LoyaltyTierRank(event.EarlyAccessTier)
+		if tier == event.EarlyAccessTier || (required >= 0 && entities.LoyaltyTierRank(tier) >= required) {
+			return nil, nil
+		}
+	}
+
+	presaleCode = strings.ToUpper(strings.TrimSpace(presaleCode))
+	if presaleCode == "" {
+		return nil, errors.NewBadRequestError(constants.ErrNotOnSale, nil)
+	}
+
+	var code entities.PresaleCode
+	if err := db.Where("code = ? AND event_id = ?", presaleCode, event.ID).First(&code).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewBadRequestError(constants.ErrInvalidPresaleCode, nil)
+		}
+		return nil, errors.NewInternalError("Failed to fetch presale code", err)
+	}
+	if code.MaxUses > 0 && code.Uses >= code.MaxUses {
+		return nil, errors.NewBadRequestError(constants.ErrPresaleCodeUsedUp, nil)
+	}
+
+	return &code, nil
+}
+
+// redeemPresaleCode counts a confirmed booking against its presale code, failing if the
+// code was used up by other bookings since the intent was created
+func redeemPresaleCode(tx *gorm.DB, codeID uint) error {
+	result := tx.Model(&entities.PresaleCode{}).
+		Where("id = ? AND (max_uses = 0 OR uses < max_uses)", codeID).
+		Updates(map[string]interface{}{
+			"uses":       gorm.Expr("uses + 1"),
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return errors.NewInternalError("Failed to redeem presale code", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.NewBadRequestError(constants.ErrPresaleCodeUsedUp, nil)
+	}
+	return nil
+}
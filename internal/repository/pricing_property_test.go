@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/money"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// The property tests generate inputs with testing/quick. Prices are whole cents, as stored.
+
+var quickConfig = &quick.Config{MaxCount: 2000}
+
+// randomPrice returns a price from 0 to 1000.00
+func randomPrice(r *rand.Rand) money.Money {
+	return money.FromMinor(int64(r.Intn(100_001)))
+}
+
+// redemption is one loyalty redemption against a seat price
+type redemption struct {
+	Balance   int
+	Requested int
+	Price     money.Money
+}
+
+func (redemption) Generate(r *rand.Rand, size int) reflect.Value {
+	balance := r.Intn(200_000)
+	return reflect.ValueOf(redemption{
+		Balance:   balance,
+		Requested: r.Intn(balance+1000) - 100, // mostly within the balance, sometimes over or negative
+		Price:     randomPrice(r),
+	})
+}
+
+func TestLoyaltyDiscountProperties(t *testing.T) {
+	property := func(in redemption) bool {
+		user := &entities.User{LoyaltyPoints: in.Balance}
+		points, discount, err := loyaltyDiscount(user, in.Requested, in.Price)
+
+		if in.Requested > in.Balance && in.Requested > 0 {
+			if err == nil {
+				t.Logf("%+v: redeeming more than the balance succeeded", in)
+				return false
+			}
+			return true
+		}
+		if err != nil {
+			t.Logf("%+v: unexpected error %v", in, err)
+			return false
+		}
+
+		switch {
+		case points < 0 || points > max(in.Requested, 0) || points > in.Balance:
+			t.Logf("%+v: redeemed %d points", in, points)
+		case discount < 0 || discount > in.Price:
+			t.Logf("%+v: discount %v outside [0, price]", in, discount)
+		case in.Price-discount < 0:
+			t.Logf("%+v: total %v is negative", in, in.Price-discount)
+		case discount.Minor() > int64(points)*money.MinorPerMajor/constants.LoyaltyPointsPerDiscountUnit:
+			t.Logf("%+v: discount %v is worth more than %d points", in, discount, points)
+		case points == in.Requested && in.Requested > 0 && discount < in.Price &&
+			discount.Minor() != int64(points)*money.MinorPerMajor/constants.LoyaltyPointsPerDiscountUnit:
+			t.Logf("%+v: %d points are worth more than discount %v", in, points, discount)
+		case pointsEarned(in.Price-discount) < 0:
+			t.Logf("%+v: earned negative points", in)
+		default:
+			return true
+		}
+		return false
+	}
+	if err := quick.Check(property, quickConfig); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLoyaltyDiscountIsMonotonic(t *testing.T) {
+	// Redeeming more points never lowers the discount
+	property := func(in redemption, extra uint16) bool {
+		requested := max(in.Requested, 0)
+		user := &entities.User{LoyaltyPoints: requested + int(extra)}
+
+		_, less, err := loyaltyDiscount(user, requested, in.Price)
+		if err != nil {
+			return false
+		}
+		_, more, err := loyaltyDiscount(user, requested+int(extra), in.Price)
+		if err != nil {
+			return false
+		}
+		return less <= more
+	}
+	if err := quick.Check(property, quickConfig); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPointsEarnedProperties(t *testing.T) {
+	property := func(a, b uint32) bool {
+		low, high := money.FromMinor(int64(min(a, b))), money.FromMinor(int64(max(a, b)))
+		return pointsEarned(low) >= 0 && pointsEarned(low) <= pointsEarned(high) &&
+			float64(pointsEarned(high)) <= high.Major()*constants.LoyaltyPointsPerCurrencyUnit
+	}
+	if err := quick.Check(property, quickConfig); err != nil {
+		t.Error(err)
+	}
+}
+
+// seatLayout is a venue and event to generate seats for
+type seatLayout struct {
+	Event     entities.Event
+	Venue     entities.Venue
+	BatchSize int
+}
+
+func (seatLayout) Generate(r *rand.Rand, size int) reflect.Value {
+	rows, columns := 1+r.Intn(40), 1+r.Intn(40)
+
+	// Sections may overlap or reach past the last row; the first match wins
+	sections := make([]entities.VenueSection, r.Intn(4))
+	for i := range sections {
+		start := 1 + r.Intn(rows+2)
+		sections[i] = entities.VenueSection{
+			RowStart:        start,
+			RowEnd:          start + r.Intn(rows),
+			SeatType:        fmt.Sprintf("section-%d", i),
+			PriceMultiplier: float64(r.Intn(301)) / 100,
+		}
+	}
+
+	return reflect.ValueOf(seatLayout{
+		Event: entities.Event{
+			ID:                 uint(1 + r.Intn(1000)),
+			Price:              randomPrice(r),
+			InitialReleaseRows: r.Intn(rows + 5),
+		},
+		Venue:     entities.Venue{Rows: rows, Columns: columns, Sections: sections},
+		BatchSize: 1 + r.Intn(100),
+	})
+}
+
+func TestGenerateSeatsProperties(t *testing.T) {
+	property := func(in seatLayout) bool {
+		var seats []entities.Seat
+		err := generateSeats(&in.Event, &in.Venue, in.BatchSize, func(batch []entities.Seat) error {
+			if len(batch) == 0 || len(batch) > in.BatchSize {
+				return fmt.Errorf("batch of %d seats, batch size %d", len(batch), in.BatchSize)
+			}
+			// The batch is reused after insert returns
+			seats = append(seats, batch...)
+			return nil
+		})
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+
+		// Every position of the venue gets exactly one seat
+		if len(seats) != in.Venue.Rows*in.Venue.Columns {
+			t.Logf("%d seats for a %dx%d venue", len(seats), in.Venue.Rows, in.Venue.Columns)
+			return false
+		}
+		positions := make(map[[2]int]bool, len(seats))
+		held := 0
+		for _, seat := range seats {
+			position := [2]int{seat.Row, seat.Column}
+			if positions[position] || seat.Row < 1 || seat.Row > in.Venue.Rows || seat.Column < 1 || seat.Column > in.Venue.Columns {
+				t.Logf("seat at row %d column %d is duplicated or outside the venue", seat.Row, seat.Column)
+				return false
+			}
+			positions[position] = true
+
+			if seat.EventID != in.Event.ID || !seat.IsAvailable || seat.IsLocked || seat.Price < 0 {
+				t.Logf("seat %+v", seat)
+				return false
+			}
+			if want := in.Event.Price.Mul(sectionMultiplier(in.Venue.Sections, seat.Row)); seat.Price != want {
+				t.Logf("seat in row %d costs %v, want %v", seat.Row, seat.Price, want)
+				return false
+			}
+			if seat.IsHeld {
+				held++
+			}
+		}
+
+		// Rows after the initial release are held, unless everything is released at once
+		wantHeld := 0
+		if in.Event.InitialReleaseRows > 0 && in.Event.InitialReleaseRows < in.Venue.Rows {
+			wantHeld = (in.Venue.Rows - in.Event.InitialReleaseRows) * in.Venue.Columns
+		}
+		if held != wantHeld {
+			t.Logf("%d held seats, want %d", held, wantHeld)
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(property, quickConfig); err != nil {
+		t.Error(err)
+	}
+}
+
+func sectionMultiplier(sections []entities.VenueSection, row int) float64 {
+	for _, section := range sections {
+		if row >= section.RowStart && row <= section.RowEnd {
+			return section.PriceMultiplier
+		}
+	}
+	return 1
+}
@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	redisconn "api/internal/redis"
+	"api/pkg/errors"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QueueRepository keeps the on-sale queues of high-demand events. Entries live in
+// event_queues, which is authoritative; a Redis sorted set per event holds the users still
+// waiting, scored by join time, so positions and lengths don't have to count rows.
+type QueueRepository interface {
+	GetEntry(ctx context.Context, userID, eventID uint) (*entities.EventQueue, error)
+	CreateEntry(ctx context.Context, entry *entities.EventQueue) (bool, error)
+	CancelEntry(ctx context.Context, userID, eventID uint) (*entities.EventQueue, error)
+	CompleteEntry(ctx context.Context, userID, eventID uint) (*entities.EventQueue, error)
+	CountAhead(ctx context.Context, entry *entities.EventQueue) (int64, error)
+	CountWaiting(ctx context.Context, eventID uint) (int64, error)
+	GetWaiting(ctx context.Context, eventID uint) ([]entities.EventQueue, error)
+	GetEventsToAdmit(ctx context.Context, now time.Time) ([]uint, error)
+	GetChangedEvents(ctx context.Context, since time.Time) ([]uint, error)
+	AdmitNext(ctx context.Context, eventID uint, maxActive int, now, expiresAt time.Time) ([]entities.EventQueue, error)
+	ExpireAdmissions(ctx context.Context, now time.Time) (int64, error)
+	GetAdmittedUsers(ctx context.Context, now time.Time) ([]uint, error)
+
+	Enqueue(ctx context.Context, eventID, userID uint, joinedAt time.Time) error
+	Dequeue(ctx context.Context, eventID uint, userIDs ...uint) error
+	Rank(ctx context.Context, eventID, userID uint) (int64, bool, error)
+	Length(ctx context.Context, eventID uint) (int64, error)
+	Rebuild(ctx context.Context, eventID uint, waiting []entities.EventQueue) error
+}
+
+type queueRepository struct {
+	db    *gorm.DB
+	redis redis.UniversalClient
+}
+
+func NewQueueRepository(db *gorm.DB, redisClient redis.UniversalClient) QueueRepository {
+	return &queueRepository{db: db, redis: redisClient}
+}
+
+// onSaleQueuedStatuses are the statuses of users still holding a place in an on-sale queue
+var onSaleQueuedStatuses = []string{constants.QueueStatusWaiting, constants.QueueStatusActive}
+
+// onSaleEntries scopes a query to the on-sale queue's rows of event_queues
+func onSaleEntries(db *gorm.DB) *gorm.DB {
+	return db.Model(&entities.EventQueue{}).Where("kind = ?", constants.QueueKindOnSale)
+}
+
+// GetEntry returns the user's waiting or admitted entry in an event's queue
+func (r *queueRepository) GetEntry(ctx context.Context, userID, eventID uint) (*entities.EventQueue, error) {
+	var entry entities.EventQueue
+	if err := conn(ctx, r.db).Scopes(onSaleEntries).
+		Where("user_id = ? AND event_id = ? AND status IN ?", userID, eventID, onSaleQueuedStatuses).
+		First(&entry).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError(constants.ErrNotInQueue, errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch queue entry", err)
+	}
+	return &entry, nil
+}
+
+// CreateEntry stores a new queue entry. It returns false, storing nothing, if the user already
+// holds a place in the event's queue.
+func (r *queueRepository) CreateEntry(ctx context.Context, entry *entities.EventQueue) (bool, error) {
+	result := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(entry)
+	if result.Error != nil {
+		return false, errors.NewInternalError("Failed to join queue", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CancelEntry takes the user out of an event's queue, returning the cancelled entry
+func (r *queueRepository) CancelEntry(ctx context.Context, userID, eventID uint) (*entities.EventQueue, error) {
+	return r.finishEntry(ctx, userID, eventID, constants.QueueStatusCancelled)
+}
+
+// CompleteEntry marks the user's place in an event's queue as used by a booking
+func (r *queueRepository) CompleteEntry(ctx context.Context, userID, eventID uint) (*entities.EventQueue, error) {
+	return r.finishEntry(ctx, userID, eventID, constants.QueueStatusCompleted)
+}
+
+func (r *queueRepository) finishEntry(ctx context.Context, userID, eventID uint, status string) (*entities.EventQueue, error) {
+	var entry entities.EventQueue
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Scopes(onSaleEntries).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND event_id = ? AND status IN ?", userID, eventID, onSaleQueuedStatuses).
+			First(&entry).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewNotFoundError(constants.ErrNotInQueue, errors.ErrRecordNotFound)
+			}
+			return errors.NewInternalError("Failed to fetch queue entry", err)
+		}
+		if err := tx.Model(&entry).Update("status", status).Error; err != nil {
+			return errors.NewInternalError("Failed to update queue entry", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// CountAhead counts the users waiting in front of a waiting entry, in join order
+func (r *queueRepository) CountAhead(ctx context.Context, entry *entities.EventQueue) (int64, error) {
+	var ahead int64
+	if err := conn(ctx, r.db).Scopes(onSaleEntries).
+		Where("event_id = ? AND status = ? AND (joined_at < ? OR (joined_at = ? AND id < ?))",
+			entry.EventID, constants.QueueStatusWaiting, entry.JoinedAt, entry.JoinedAt, entry.ID).
+		Count(&ahead).Error; err != nil {
+		return 0, errors.NewInternalError("Failed to get queue position", err)
+	}
+	return ahead, nil
+}
+
+// CountWaiting counts the users waiting in an event's queue
+func (r *queueRepository) CountWaiting(ctx context.Context, eventID uint) (int64, error) {
+	var waiting int64
+	if err := conn(ctx, r.db).Scopes(onSaleEntries).
+		Where("event_id = ? AND status = ?", eventID, constants.QueueStatusWaiting).
+		Count(&waiting).Error; err != nil {
+		return 0, errors.NewInternalError("Failed to get queue length", err)
+	}
+	return waiting, nil
+}
+
+// GetWaiting returns the users waiting in an event's queue, in join order
+func (r *queueRepository) GetWaiting(ctx context.Context, eventID uint) ([]entities.EventQueue, error) {
+	var waiting []entities.EventQueue
+	if err := conn(ctx, r.db).Scopes(onSaleEntries).
+		Where("event_id = ? AND status = ?", eventID, constants.QueueStatusWaiting).
+		Order("joined_at ASC, id ASC").
+		Find(&waiting).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch queue", err)
+	}
+	return waiting, nil
+}
+
+// GetEventsToAdmit returns the active events that are on sale and have users waiting in their
+// queue
+func (r *queueRepository) GetEventsToAdmit(ctx context.Context, now time.Time) ([]uint, error) {
+	var eventIDs []uint
+	if err := conn(ctx, r.db).Scopes(onSaleEntries).
+		Joins("JOIN events ON events.id = event_queues.event_id").
+		Where("event_queues.status = ? AND events.status = ? AND (events.on_sale_at IS NULL OR events.on_sale_at <= ?)",
+			constants.QueueStatusWaiting, constants.EventStatusActive, now).
+		Distinct().
+		Pluck("event_queues.event_id", &eventIDs).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch queues to admit", err)
+	}
+	return eventIDs, nil
+}
+
+// GetChangedEvents returns the events whose queue changed since the given time
+func (r *queueRepository) GetChangedEvents(ctx context.Context, since time.Time) ([]uint, error) {
+	var eventIDs []uint
+	if err := conn(ctx, r.db).Scopes(onSaleEntries).
+		Where("updated_at >= ?", since).
+		Distinct().
+		Pluck("event_id", &eventIDs).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch changed queues", err)
+	}
+	return eventIDs, nil
+}
+
+// AdmitNext admits the users at the front of an event's queue until maxActive users are
+// admitted, returning the newly admitted entries. Admissions last until expiresAt.
+func (r *queueRepository) AdmitNext(ctx context.Context, eventID uint, maxActive int, now, expiresAt time.Time) ([]entities.EventQueue, error) {
+	var admitted []entities.EventQueue
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Concurrent admissions of the same queue wait for each other, so maxActive holds
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&entities.Event{}, eventID).Error; err != nil {
+			return errors.NewInternalError("Failed to lock event", err)
+		}
+
+		var active int64
+		if err := tx.Scopes(onSaleEntries).
+			Where("event_id = ? AND status = ? AND expires_at > ?", eventID, constants.QueueStatusActive, now).
+			Count(&active).Error; err != nil {
+			return errors.NewInternalError("Failed to count admitted users", err)
+		}
+		free := maxActive - int(active)
+		if free <= 0 {
+			return nil
+		}
+
+		if err := tx.Scopes(onSaleEntries).
+			Where("event_id = ? AND status = ?", eventID, constants.QueueStatusWaiting).
+			Order("joined_at ASC, id ASC").
+			Limit(free).
+			Find(&admitted).Error; err != nil {
+			return errors.NewInternalError("Failed to fetch queue", err)
+		}
+		if len(admitted) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(admitted))
+		for i := range admitted {
+			ids[i] = admitted[i].ID
+			admitted[i].Status = constants.QueueStatusActive
+			admitted[i].ActiveAt = &now
+			admitted[i].ExpiresAt = &expiresAt
+		}
+		if err := tx.Model(&entities.EventQueue{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":     constants.QueueStatusActive,
+				"active_at":  now,
+				"expires_at": expiresAt,
+			}).Error; err != nil {
+			return errors.NewInternalError("Failed to admit users", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return admitted, nil
+}
+
+// ExpireAdmissions ends the admissions that ran out before now, returning how many did
+func (r *queueRepository) ExpireAdmissions(ctx context.Context, now time.Time) (int64, error) {
+	result := conn(ctx, r.db).Scopes(onSaleEntries).
+		Where("status = ? AND expires_at < ?", constants.QueueStatusActive, now).
+		Update("status", constants.QueueStatusExpired)
+	if result.Error != nil {
+		return 0, errors.NewInternalError("Failed to expire queue admissions", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// GetAdmittedUsers returns the users holding an unexpired admission from any on-sale queue
+func (r *queueRepository) GetAdmittedUsers(ctx context.Context, now time.Time) ([]uint, error) {
+	var userIDs []uint
+	if err := conn(ctx, r.db).Scopes(onSaleEntries).
+		Where("status = ? AND expires_at > ?", constants.QueueStatusActive, now).
+		Distinct().
+		Pluck("user_id", &userIDs).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch admitted users", err)
+	}
+	return userIDs, nil
+}
+
+// Enqueue adds a waiting user to an event's Redis queue. A user already in it keeps their place.
+func (r *queueRepository) Enqueue(ctx context.Context, eventID, userID uint, joinedAt time.Time) error {
+	member := redis.Z{Score: float64(joinedAt.UnixMicro()), Member: strconv.FormatUint(uint64(userID), 10)}
+	if err := r.redis.ZAddNX(ctx, redisconn.QueueKey(eventID), member).Err(); err != nil {
+		return fmt.Errorf("failed to join queue: %w", err)
+	}
+	return nil
+}
+
+// Dequeue removes users from an event's Redis queue
+func (r *queueRepository) Dequeue(ctx context.Context, eventID uint, userIDs ...uint) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	members := make([]interface{}, len(userIDs))
+	for i, userID := range userIDs {
+		members[i] = strconv.FormatUint(uint64(userID), 10)
+	}
+	if err := r.redis.ZRem(ctx, redisconn.QueueKey(eventID), members...).Err(); err != nil {
+		return fmt.Errorf("failed to leave queue: %w", err)
+	}
+	return nil
+}
+
+// Rank returns how many users wait in front of the user in an event's Redis queue, and false
+// if the user isn't in it
+func (r *queueRepository) Rank(ctx context.Context, eventID, userID uint) (int64, bool, error) {
+	rank, err := r.redis.ZRank(ctx, redisconn.QueueKey(eventID), strconv.FormatUint(uint64(userID), 10)).Result()
+	if err == redis.Nil {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get queue position: %w", err)
+	}
+	return rank, true, nil
+}
+
+// Length returns how many users wait in an event's Redis queue
+func (r *queueRepository) Length(ctx context.Context, eventID uint) (int64, error) {
+	length, err := r.redis.ZCard(ctx, redisconn.QueueKey(eventID)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get queue length: %w", err)
+	}
+	return length, nil
+}
+
+// Rebuild replaces an event's Redis queue with the waiting entries from the database
+func (r *queueRepository) Rebuild(ctx context.Context, eventID uint, waiting []entities.EventQueue) error {
+	key := redisconn.QueueKey(eventID)
+	pipe := r.redis.TxPipeline()
+	pipe.Del(ctx, key)
+	if len(waiting) > 0 {
+		members := make([]redis.Z, len(waiting))
+		for i, entry := range waiting {
+			members[i] = redis.Z{Score: float64(entry.JoinedAt.UnixMicro()), Member: strconv.FormatUint(uint64(entry.UserID), 10)}
+		}
+		pipe.ZAdd(ctx, key, members...)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to rebuild queue: %w", err)
+	}
+	return nil
+}
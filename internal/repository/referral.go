@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// newReferralCodeAttempts bounds the retries when a generated referral code is already taken
+const newReferralCodeAttempts = 5
+
+// commissionEarned holds for commissions whose booking b is still confirmed
+const commissionEarned = "b.status = 'confirmed' AND b.deleted_at IS NULL"
+
+type ReferralRepository struct {
+	db *gorm.DB
+}
+
+func NewReferralRepository(db *gorm.DB) *ReferralRepository {
+	return &ReferralRepository{db: db}
+}
+
+// CreateReferrer stores a referrer, generating a code unless one was chosen
+func (r *ReferralRepository) CreateReferrer(ctx context.Context, referrer *entities.Referrer) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if referrer.UserID != nil {
+			var userCount int64
+			if err := tx.Model(&entities.User{}).Where("id = ?", *referrer.UserID).Count(&userCount).Error; err != nil {
+				return errors.NewInternalError("Failed to fetch user", err)
+			}
+			if userCount == 0 {
+				return errors.NewNotFoundError("User not found", errors.ErrRecordNotFound)
+			}
+		}
+		return createReferrer(tx, referrer)
+	})
+}
+
+// EnsureUserReferrer returns the user's own referrer, creating it with the given commission
+// rate on first use
+func (r *ReferralRepository) EnsureUserReferrer(ctx context.Context, userID uint, rate float64) (*entities.Referrer, error) {
+	var referrer entities.Referrer
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Lock the user so concurrent requests don't create two codes
+		var user entities.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "first_name", "last_name").First(&user, userID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewNotFoundError("User not found", errors.ErrUserNotFound)
+			}
+			return errors.NewInternalError("Failed to fetch user", err)
+		}
+
+		err := tx.Where("user_id = ? AND kind = ?", userID, constants.ReferrerKindUser).First(&referrer).Error
+		if err == nil {
+			return nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return errors.NewInternalError("Failed to fetch referrer", err)
+		}
+
+		referrer = entities.Referrer{
+			Kind:           constants.ReferrerKindUser,
+			Name:           strings.TrimSpace(user.FirstName + " " + user.LastName),
+			UserID:         &user.ID,
+			CommissionRate: rate,
+			Active:         true,
+		}
+		return createReferrer(tx, &referrer)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &referrer, nil
+}
+
+func createReferrer(tx *gorm.DB, referrer *entities.Referrer) error {
+	if referrer.Code != "" {
+		var count int64
+		if err := tx.Model(&entities.Referrer{}).Where("code = ?", referrer.Code).Count(&count).Error; err != nil {
+			return errors.NewInternalError("Failed to check referral code", err)
+		}
+		if count > 0 {
+			return errors.NewConflictError("This referral code is already taken", nil)
+		}
+		if err := tx.Create(referrer).Error; err != nil {
+			return errors.NewInternalError("Failed to create referrer", err)
+		}
+		return nil
+	}
+
+	for attempt := 0; attempt < newReferralCodeAttempts; attempt++ {
+		code, err := randomCode(constants.ReferralCodeLength)
+		if err != nil {
+			return errors.NewInternalError("Failed to generate referral code", err)
+		}
+		var count int64
+		if err := tx.Model(&entities.Referrer{}).Where("code = ?", code).Count(&count).Error; err != nil {
+			return errors.NewInternalError("Failed to check referral code", err)
+		}
+		if count > 0 {
+			continue
+		}
+		referrer.Code = code
+		if err := tx.Create(referrer).Error; err != nil {
+			return errors.NewInternalError("Failed to create referrer", err)
+		}
+		return nil
+	}
+	return errors.NewInternalError("Failed to generate a unique referral code", nil)
+}
+
+func (r *ReferralRepository) ListReferrers(ctx context.Context) ([]entities.Referrer, error) {
+	var referrers []entities.Referrer
+	if err := conn(ctx, r.db).Order("id ASC").Find(&referrers).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch referrers", err)
+	}
+	return referrers, nil
+}
+
+// ListUserReferrers returns the referrers a user manages: their own code and the partner
+// codes linked to their account
+func (r *ReferralRepository) ListUserReferrers(ctx context.Context, userID uint) ([]entities.Referrer, error) {
+	var referrers []entities.Referrer
+	if err := conn(ctx, r.db).Where("user_id = ?", userID).Order("id ASC").Find(&referrers).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch referrers", err)
+	}
+	return referrers, nil
+}
+
+// UpdateReferrer changes a referrer's name, contact, commission rate or whether its code
+// still earns; the rate applies to bookings made from now on
+func (r *ReferralRepository) UpdateReferrer(ctx context.Context, referrerID uint, updates map[string]interface{}) (*entities.Referrer, error) {
+	var referrer entities.Referrer
+	if err := conn(ctx, r.db).First(&referrer, referrerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Referrer not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch referrer", err)
+	}
+	if err := conn(ctx, r.db).Model(&referrer).Updates(updates).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to update referrer", err)
+	}
+	return &referrer, nil
+}
+
+// GetStats totals the bookings and commissions of each referrer, by referrer ID
+func (r *ReferralRepository) GetStats(ctx context.Context, referrerIDs []uint, now time.Time) (map[uint]entities.ReferrerStats, error) {
+	stats := make(map[uint]entities.ReferrerStats, len(referrerIDs))
+	if len(referrerIDs) == 0 {
+		return stats, nil
+	}
+
+	var rows []entities.ReferrerStats
+	err := conn(ctx, r.db).Table("referral_commissions c").
+		Select(`
+			c.referrer_id,
+			COUNT(CASE WHEN `+commissionEarned+` THEN 1 END) as bookings,
+			COUNT(CASE WHEN NOT (`+commissionEarned+`) THEN 1 END) as cancelled,
+			COALESCE(SUM(CASE WHEN `+commissionEarned+` THEN c.amount END), 0) as revenue,
+			COALESCE(SUM(CASE WHEN `+commissionEarned+` AND c.status = 'pending' AND e.end_time >= ? THEN c.commission END), 0) as commission_pending,
+			COALESCE(SUM(CASE WHEN `+commissionEarned+` AND c.status = 'pending' AND e.end_time < ? THEN c.commission END), 0) as commission_payable,
+			COALESCE(SUM(CASE WHEN c.status = 'paid' THEN c.commission END), 0) as commission_paid
+		`, now, now).
+		Joins("JOIN bookings b ON b.id = c.booking_id").
+		Joins("JOIN events e ON e.id = c.event_id").
+		Where("c.referrer_id IN ?", referrerIDs).
+		Group("c.referrer_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to fetch referral stats", err)
+	}
+	for _, row := range rows {
+		stats[row.ReferrerID] = row
+	}
+	return stats, nil
+}
+
+// ListCommissions returns the latest commissions of the referrers with their event, at most limit
+func (r *ReferralRepository) ListCommissions(ctx context.Context, referrerIDs []uint, limit int) ([]entities.ReferralCommission, error) {
+	var commissions []entities.ReferralCommission
+	if len(referrerIDs) == 0 {
+		return commissions, nil
+	}
+	if err := conn(ctx, r.db).Preload("Event").Preload("Booking").
+		Where("referrer_id IN ?", referrerIDs).
+		Order("created_at DESC, id DESC").Limit(limit).
+		Find(&commissions).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch referral commissions", err)
+	}
+	return commissions, nil
+}
+
+// payableCommissions restricts a query over commissions c to those owed: pending, on bookings
+// still confirmed, of events that ended before now
+func payableCommissions(now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Joins("JOIN bookings b ON b.id = c.booking_id").
+			Joins("JOIN events e ON e.id = c.event_id").
+			Where("c.status = ? AND "+commissionEarned+" AND e.end_time < ?", constants.CommissionStatusPending, now)
+	}
+}
+
+// GetPayouts returns the commission owed to each referrer, largest first
+func (r *ReferralRepository) GetPayouts(ctx context.Context, now time.Time) ([]entities.ReferrerPayout, error) {
+	var payouts []entities.ReferrerPayout
+	err := conn(ctx, r.db).Table("referral_commissions c").Scopes(payableCommissions(now)).
+		Select(`
+			c.referrer_id,
+			rf.code,
+			rf.name,
+			rf.email,
+			COUNT(c.id) as commissions,
+			SUM(c.commission) as amount
+		`).
+		Joins("JOIN referrers rf ON rf.id = c.referrer_id").
+		Group("c.referrer_id, rf.code, rf.name, rf.email").
+		Order("amount DESC, c.referrer_id ASC").
+		Scan(&payouts).Error
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to fetch referral payouts", err)
+	}
+	return payouts, nil
+}
+
+// MarkPaid marks every commission owed to a referrer as paid, returning what was paid out
+func (r *ReferralRepository) MarkPaid(ctx context.Context, referrerID uint, now time.Time) (*entities.ReferrerPayout, error) {
+	var referrer entities.Referrer
+	if err := conn(ctx, r.db).First(&referrer, referrerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Referrer not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch referrer", err)
+	}
+
+	payout := &entities.ReferrerPayout{ReferrerID: referrer.ID, Code: referrer.Code, Name: referrer.Name, Email: referrer.Email}
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var commissions []entities.ReferralCommission
+		if err := tx.Table("referral_commissions c").Scopes(payableCommissions(now)).
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "c"}}).
+			Select("c.id", "c.commission").
+			Where("c.referrer_id = ?", referrerID).
+			Find(&commissions).Error; err != nil {
+			return errors.NewInternalError("Failed to fetch referral commissions", err)
+		}
+		if len(commissions) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(commissions))
+		for i, commission := range commissions {
+			ids[i] = commission.ID
+			payout.Amount += commission.Commission
+		}
+		payout.Commissions = int64(len(commissions))
+
+		if err := tx.Model(&entities.ReferralCommission{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{"status": constants.CommissionStatusPaid, "paid_at": now}).Error; err != nil {
+			return errors.NewInternalError("Failed to mark referral commissions paid", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payout, nil
+}
+
+// recordReferralCommission credits the referrer whose active code a booking was made with.
+// Codes that match no referrer are kept as attribution only, and users don't earn on their
+// own bookings.
+func recordReferralCommission(tx *gorm.DB, booking *entities.Booking) error {
+	if booking.ReferralCode == "" || booking.Sandbox || booking.TotalAmount <= 0 {
+		return nil
+	}
+
+	var referrer entities.Referrer
+	if err := tx.Where("code = ? AND active = true", booking.ReferralCode).First(&referrer).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return errors.NewInternalError("Failed to fetch referrer", err)
+	}
+	if referrer.UserID != nil && *referrer.UserID == booking.UserID {
+		return nil
+	}
+
+	if err := tx.Create(&entities.ReferralCommission{
+		ReferrerID: referrer.ID,
+		BookingID:  booking.ID,
+		EventID:    booking.EventID,
+		Amount:     booking.TotalAmount,
+		Rate:       referrer.CommissionRate,
+		Commission: booking.TotalAmount.Percent(referrer.CommissionRate),
+		Status:     constants.CommissionStatusPending,
+	}).Error; err != nil {
+		return errors.NewInternalError("Failed to record referral commission", err)
+	}
+	return nil
+}
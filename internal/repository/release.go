@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReleaseSeats puts the held seats in rows rowStart..rowEnd on sale and adds them to the
+// event's available seat count
+func (s *eventRepository) ReleaseSeats(ctx context.Context, eventID uint, rowStart, rowEnd int, releasedBy uint) (*entities.SeatRelease, error) {
+	release := &entities.SeatRelease{
+		EventID:    eventID,
+		RowStart:   rowStart,
+		RowEnd:     rowEnd,
+		ReleasedBy: releasedBy,
+	}
+
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		var event entities.Event
+		if err := tx.Scopes(tenantScope(ctx, "events")).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "status").
+			First(&event, eventID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewNotFoundError(constants.ErrEventNotFound, errors.ErrRecordNotFound)
+			}
+			return errors.NewInternalError("Failed to fetch event", err)
+		}
+		if event.Status != constants.EventStatusActive {
+			return errors.NewBadRequestError("Event is not active", nil)
+		}
+
+		result := tx.Model(&entities.Seat{}).
+			Where("event_id = ? AND is_held = true AND \"row\" BETWEEN ? AND ?", eventID, rowStart, rowEnd).
+			Updates(map[string]interface{}{
+				"is_held":    false,
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return errors.NewInternalError("Failed to release seats", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errors.NewBadRequestError("No held seats in these rows", nil)
+		}
+		release.SeatsReleased = int(result.RowsAffected)
+
+		if err := tx.Model(&entities.Event{}).Where("id = ?", eventID).
+			UpdateColumn("available_seats", gorm.Expr("available_seats + ?", release.SeatsReleased)).Error; err != nil {
+			return errors.NewInternalError("Failed to update event capacity", err)
+		}
+
+		if err := tx.Create(release).Error; err != nil {
+			return errors.NewInternalError("Failed to record seat release", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return release, nil
+}
+
+// ListReleases returns an event's release waves, oldest first, and the number of seats still held
+func (s *eventRepository) ListReleases(ctx context.Context, eventID uint) ([]entities.SeatRelease, int64, error) {
+	var releases []entities.SeatRelease
+	var held int64
+
+	if err := conn(ctx, s.db).Scopes(tenantScopeVia(ctx, "seat_releases.event_id", "events")).
+		Where("event_id = ?", eventID).
+		Order("created_at ASC").
+		Find(&releases).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to fetch seat releases", err)
+	}
+
+	if err := conn(ctx, s.db).Model(&entities.Seat{}).
+		Where("event_id = ? AND is_held = true", eventID).
+		Count(&held).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to count held seats", err)
+	}
+
+	return releases, held, nil
+}
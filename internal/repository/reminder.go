@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReminderRepository struct {
+	db *gorm.DB
+}
+
+func NewReminderRepository(db *gorm.DB) *ReminderRepository {
+	return &ReminderRepository{db: db}
+}
+
+// GetEventsStartingBetween returns active events with reminders enabled starting in the given window
+func (s *ReminderRepository) GetEventsStartingBetween(ctx context.Context, from, to time.Time) ([]entities.Event, error) {
+	var events []entities.Event
+
+	if err := conn(ctx, s.db).
+		Preload("Venue").
+		Where("status = ? AND start_time > ? AND start_time <= ? AND reminder_offsets <> '' AND sandbox = false",
+			constants.EventStatusActive, from, to).
+		Find(&events).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch upcoming events", err)
+	}
+
+	return events, nil
+}
+
+// GetBookingsMissingReminder returns confirmed bookings of an event that have no reminder for the offset yet
+func (s *ReminderRepository) GetBookingsMissingReminder(ctx context.Context, eventID uint, offset string) ([]entities.Booking, error) {
+	var bookings []entities.Booking
+
+	if err := conn(ctx, s.db).
+		Preload("User").
+		Preload("Seat").
+		Joins("LEFT JOIN booking_reminders br ON br.booking_id = bookings.id AND br.reminder_offset = ?", offset).
+		Where("bookings.event_id = ? AND bookings.status = ? AND br.id IS NULL",
+			eventID, constants.BookingStatusConfirmed).
+		Find(&bookings).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch bookings for reminders", err)
+	}
+
+	return bookings, nil
+}
+
+// ClaimReminder inserts a pending reminder record. It returns false if the reminder
+// was already claimed, which deduplicates sends across concurrent job runs.
+func (s *ReminderRepository) ClaimReminder(ctx context.Context, bookingID uint, offset string) (*entities.BookingReminder, bool, error) {
+	reminder := &entities.BookingReminder{
+		BookingID:      bookingID,
+		ReminderOffset: offset,
+		Status:         constants.ReminderStatusPending,
+	}
+
+	result := conn(ctx, s.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(reminder)
+	if result.Error != nil {
+		return nil, false, errors.NewInternalError("Failed to claim reminder", result.Error)
+	}
+
+	return reminder, result.RowsAffected > 0, nil
+}
+
+// MarkReminderSent records a successful delivery
+func (s *ReminderRepository) MarkReminderSent(ctx context.Context, reminderID uint) error {
+	if err := conn(ctx, s.db).Model(&entities.BookingReminder{}).
+		Where("id = ?", reminderID).
+		Updates(map[string]interface{}{
+			"status":  constants.ReminderStatusSent,
+			"sent_at": time.Now(),
+		}).Error; err != nil {
+		return errors.NewInternalError("Failed to update reminder", err)
+	}
+	return nil
+}
+
+// MarkReminderFailed records a failed delivery; failed reminders are not retried
+func (s *ReminderRepository) MarkReminderFailed(ctx context.Context, reminderID uint, cause error) error {
+	if err := conn(ctx, s.db).Model(&entities.BookingReminder{}).
+		Where("id = ?", reminderID).
+		Updates(map[string]interface{}{
+			"status": constants.ReminderStatusFailed,
+			"error":  cause.Error(),
+		}).Error; err != nil {
+		return errors.NewInternalError("Failed to update reminder", err)
+	}
+	return nil
+}
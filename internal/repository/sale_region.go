@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SaleRegionRepository keeps the users allowed to book an event from outside its sale countries
+type SaleRegionRepository struct {
+	db *gorm.DB
+}
+
+func NewSaleRegionRepository(db *gorm.DB) *SaleRegionRepository {
+	return &SaleRegionRepository{db: db}
+}
+
+// ListOverrides returns an event's overrides, newest first
+func (r *SaleRegionRepository) ListOverrides(ctx context.Context, eventID uint) ([]entities.SaleRegionOverride, error) {
+	var overrides []entities.SaleRegionOverride
+	if err := conn(ctx, r.db).Scopes(tenantScopeVia(ctx, "sale_region_overrides.event_id", "events")).
+		Where("event_id = ?", eventID).
+		Order("created_at DESC").
+		Find(&overrides).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch sale region overrides", err)
+	}
+	return overrides, nil
+}
+
+// AddOverride lets a user book the override's event from anywhere. Adding a user again
+// replaces the note.
+func (r *SaleRegionRepository) AddOverride(ctx context.Context, override *entities.SaleRegionOverride) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var eventCount int64
+		if err := tx.Model(&entities.Event{}).Scopes(tenantScope(ctx, "events")).
+			Where("id = ?", override.EventID).Count(&eventCount).Error; err != nil {
+			return errors.NewInternalError("Failed to fetch event", err)
+		}
+		if eventCount == 0 {
+			return errors.NewNotFoundError(constants.ErrEventNotFound, errors.ErrRecordNotFound)
+		}
+
+		var userCount int64
+		if err := tx.Model(&entities.User{}).Where("id = ?", override.UserID).Count(&userCount).Error; err != nil {
+			return errors.NewInternalError("Failed to fetch user", err)
+		}
+		if userCount == 0 {
+			return errors.NewNotFoundError("User not found", errors.ErrRecordNotFound)
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "event_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"note", "created_by"}),
+		}).Create(override).Error; err != nil {
+			return errors.NewInternalError("Failed to add sale region override", err)
+		}
+		return nil
+	})
+}
+
+// RemoveOverride takes a user off an event's overrides
+func (r *SaleRegionRepository) RemoveOverride(ctx context.Context, eventID, userID uint) error {
+	result := conn(ctx, r.db).Scopes(tenantScopeVia(ctx, "sale_region_overrides.event_id", "events")).
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		Delete(&entities.SaleRegionOverride{})
+	if result.Error != nil {
+		return errors.NewInternalError("Failed to remove sale region override", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.NewNotFoundError("Sale region override not found", errors.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// HasOverride reports whether the user may book the event from anywhere
+func (r *SaleRegionRepository) HasOverride(ctx context.Context, eventID, userID uint) (bool, error) {
+	var count int64
+	if err := conn(ctx, r.db).Model(&entities.SaleRegionOverride{}).
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		Count(&count).Error; err != nil {
+		return false, errors.NewInternalError("Failed to check sale region override", err)
+	}
+	return count > 0, nil
+}
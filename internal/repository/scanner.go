@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// lastSeenResolution is how stale a device's last seen time may get before a request updates it
+const lastSeenResolution = time.Minute
+
+// ScannerMinute counts the check-ins of one gate during one minute
+type ScannerMinute struct {
+	Gate     string
+	Minute   time.Time
+	CheckIns int64
+}
+
+type ScannerRepository struct {
+	db *gorm.DB
+}
+
+func NewScannerRepository(db *gorm.DB) *ScannerRepository {
+	return &ScannerRepository{db: db}
+}
+
+// Create registers a scanner device for an event of the caller's tenant
+func (s *ScannerRepository) Create(ctx context.Context, device *entities.ScannerDevice) error {
+	var event entities.Event
+	if err := conn(ctx, s.db).Scopes(tenantScope(ctx, "events")).Select("id", "tenant_id").
+		First(&event, device.EventID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.NewNotFoundError(constants.ErrEventNotFound, errors.ErrRecordNotFound)
+		}
+		return errors.NewInternalError("Failed to fetch event", err)
+	}
+
+	device.TenantID = event.TenantID
+	if err := conn(ctx, s.db).Create(device).Error; err != nil {
+		return errors.NewInternalError("Failed to register scanner device", err)
+	}
+	return nil
+}
+
+// ListByEvent returns an event's scanner devices, revoked ones included, by gate and name
+func (s *ScannerRepository) ListByEvent(ctx context.Context, eventID uint) ([]entities.ScannerDevice, error) {
+	var devices []entities.ScannerDevice
+	if err := conn(ctx, s.db).Scopes(tenantScope(ctx, "scanner_devices")).
+		Where("event_id = ?", eventID).
+		Order("gate, name, id").
+		Find(&devices).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch scanner devices", err)
+	}
+	return devices, nil
+}
+
+// GetByTokenHash returns the device holding a token, with its event
+func (s *ScannerRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*entities.ScannerDevice, error) {
+	var device entities.ScannerDevice
+	if err := conn(ctx, s.db).Preload("Event").Where("token_hash = ?", tokenHash).First(&device).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Scanner device not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch scanner device", err)
+	}
+	return &device, nil
+}
+
+// Revoke stops a device's token from authenticating
+func (s *ScannerRepository) Revoke(ctx context.Context, deviceID uint) (*entities.ScannerDevice, error) {
+	var device entities.ScannerDevice
+	if err := conn(ctx, s.db).Scopes(tenantScope(ctx, "scanner_devices")).First(&device, deviceID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Scanner device not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch scanner device", err)
+	}
+	if device.RevokedAt != nil {
+		return &device, nil
+	}
+
+	now := time.Now()
+	if err := conn(ctx, s.db).Model(&device).Update("revoked_at", now).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to revoke scanner device", err)
+	}
+	device.RevokedAt = &now
+	return &device, nil
+}
+
+// TouchLastSeen records that a device made a request, unless it did in the last minute
+func (s *ScannerRepository) TouchLastSeen(ctx context.Context, deviceID uint, now time.Time) error {
+	if err := conn(ctx, s.db).Model(&entities.ScannerDevice{}).
+		Where("id = ? AND (last_seen_at IS NULL OR last_seen_at < ?)", deviceID, now.Add(-lastSeenResolution)).
+		Update("last_seen_at", now).Error; err != nil {
+		return errors.NewInternalError("Failed to update scanner device", err)
+	}
+	return nil
+}
+
+// DeviceStats counts the check-ins of each of an event's scanner devices
+func (s *ScannerRepository) DeviceStats(ctx context.Context, eventID uint) ([]entities.ScannerDeviceStats, error) {
+	var stats []entities.ScannerDeviceStats
+	if err := conn(ctx, s.db).
+		Table("scanner_devices d").
+		Select(`d.id AS device_id, d.name, d.gate, d.revoked_at IS NOT NULL AS revoked, d.last_seen_at,
+			COUNT(b.id) AS check_ins, MAX(b.checked_in_at) AS last_check_in_at`).
+		Joins("LEFT JOIN bookings b ON b.check_in_device_id = d.id AND b.checked_in_at IS NOT NULL").
+		Scopes(tenantScope(ctx, "d")).
+		Where("d.event_id = ?", eventID).
+		Group("d.id").
+		Order("d.gate, d.name, d.id").
+		Scan(&stats).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch scanner device stats", err)
+	}
+	return stats, nil
+}
+
+// GateMinutes counts an event's check-ins per gate and minute since a time
+func (s *ScannerRepository) GateMinutes(ctx context.Context, eventID uint, since time.Time) ([]ScannerMinute, error) {
+	var minutes []ScannerMinute
+	if err := conn(ctx, s.db).
+		Table("bookings b").
+		Select("d.gate, date_trunc('minute', b.checked_in_at) AS minute, COUNT(*) AS check_ins").
+		Joins("JOIN scanner_devices d ON d.id = b.check_in_device_id").
+		Scopes(tenantScope(ctx, "d")).
+		Where("d.event_id = ? AND b.checked_in_at >= ?", eventID, since).
+		Group("d.gate, minute").
+		Scan(&minutes).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch gate throughput", err)
+	}
+	return minutes, nil
+}
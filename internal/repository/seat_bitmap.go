@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"api/internal/entities"
+	redisconn "api/internal/redis"
+	"api/pkg/errors"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// seatBitmapTTL bounds how long a bitmap is patched before it is rebuilt from the database,
+// which picks up changes made outside the booking workflow such as release waves and door sales
+const seatBitmapTTL = 5 * time.Minute
+
+// seatBitmapVersionTTL lets the versions of events nobody reads any more drop out of Redis
+const seatBitmapVersionTTL = 24 * time.Hour
+
+// seatIndexBatch is the number of seats per HSET when a bitmap's seat index is written
+const seatIndexBatch = 5000
+
+// setSeatBitScript sets a seat's bit (ARGV[2]) through the seat index and bumps the version
+// if the bit changed. Bitmaps that aren't built, or seats they don't cover, are skipped with -1;
+// the next build reads the seat from the database.
+const setSeatBitScript = `
+local index = redis.call('HGET', KEYS[2], ARGV[1])
+if not index or redis.call('EXISTS', KEYS[1]) == 0 then
+	return -1
+end
+local previous = redis.call('SETBIT', KEYS[1], tonumber(index), tonumber(ARGV[2]))
+if previous == tonumber(ARGV[2]) then
+	return tonumber(redis.call('GET', KEYS[3]) or '0')
+end
+return redis.call('INCR', KEYS[3])
+`
+
+// SeatBitmapRepository materializes events' seat availability as Redis bitmaps, one bit per
+// seat, so stadium-scale seat maps are served in a few kilobytes instead of per-seat JSON.
+// Bitmaps are built from the database and seat locks on first read, patched as seats are
+// locked, sold and released, and rebuilt every five minutes.
+type SeatBitmapRepository struct {
+	db    *gorm.DB
+	redis redis.UniversalClient
+}
+
+func NewSeatBitmapRepository(db *gorm.DB, redisClient redis.UniversalClient) *SeatBitmapRepository {
+	return &SeatBitmapRepository{db: db, redis: redisClient}
+}
+
+// Get returns an event's bitmap, building it if it isn't materialized
+func (r *SeatBitmapRepository) Get(ctx context.Context, eventID uint) (*entities.SeatBitmap, error) {
+	event, err := r.getEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := r.redis.Pipeline()
+	bits := pipe.Get(ctx, redisconn.SeatBitmapKey(eventID))
+	version := pipe.Get(ctx, redisconn.SeatBitmapVersionKey(eventID))
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get seat bitmap: %w", err)
+	}
+	if bits.Err() == redis.Nil {
+		return r.build(ctx, event)
+	}
+
+	bitmap := &entities.SeatBitmap{EventID: eventID, Rows: event.Venue.Rows, Columns: event.Venue.Columns}
+	bitmap.Bits, _ = bits.Bytes()
+	bitmap.Version, _ = version.Int64()
+	return bitmap, nil
+}
+
+// SetSeat marks a seat as on sale or not in its event's bitmap, if the bitmap is built
+func (r *SeatBitmapRepository) SetSeat(ctx context.Context, eventID, seatID uint, onSale bool) {
+	bit := 0
+	if onSale {
+		bit = 1
+	}
+	keys := []string{redisconn.SeatBitmapKey(eventID), redisconn.SeatBitmapIndexKey(eventID), redisconn.SeatBitmapVersionKey(eventID)}
+	if err := r.redis.Eval(ctx, setSeatBitScript, keys, seatID, bit).Err(); err != nil {
+		warnLockError("update seat bitmap", err)
+	}
+}
+
+// getEvent loads the event with its venue's dimensions, which lay out the bitmap
+func (r *SeatBitmapRepository) getEvent(ctx context.Context, eventID uint) (*entities.Event, error) {
+	var event entities.Event
+	if err := conn(ctx, r.db).Select("id", "venue_id").
+		Preload("Venue", func(db *gorm.DB) *gorm.DB { return db.Select("id", "rows", "columns") }).
+		First(&event, eventID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Event not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch event", err)
+	}
+	return &event, nil
+}
+
+// build materializes an event's bitmap: seats that are unsold, released and not locked in the
+// database or in Redis are on sale
+func (r *SeatBitmapRepository) build(ctx context.Context, event *entities.Event) (*entities.SeatBitmap, error) {
+	var seats []entities.Seat
+	if err := conn(ctx, r.db).Select("id", "row", "column", "is_available", "is_locked", "is_held").
+		Where("event_id = ?", event.ID).
+		Find(&seats).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch seats", err)
+	}
+
+	// Seats locked in Redis, from the index the lock scripts keep
+	locked, err := r.redis.ZRangeByScore(ctx, redisconn.LiveLocksKey(event.ID), &redis.ZRangeBy{
+		Min: strconv.FormatInt(time.Now().UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list seat locks: %w", err)
+	}
+	lockedSeats := make(map[string]bool, len(locked))
+	for _, seatID := range locked {
+		lockedSeats[seatID] = true
+	}
+
+	rows, columns := event.Venue.Rows, event.Venue.Columns
+	bits := make([]byte, (rows*columns+7)/8)
+	index := make([]interface{}, 0, 2*len(seats))
+	for _, seat := range seats {
+		if seat.Row < 1 || seat.Row > rows || seat.Column < 1 || seat.Column > columns {
+			continue
+		}
+		bit := (seat.Row-1)*columns + seat.Column - 1
+		seatID := strconv.FormatUint(uint64(seat.ID), 10)
+		index = append(index, seatID, bit)
+		if seat.IsAvailable && !seat.IsHeld && !seat.IsLocked && !lockedSeats[seatID] {
+			bits[bit/8] |= 0x80 >> (bit % 8)
+		}
+	}
+
+	// All of an event's keys share its hash tag, so the bitmap and its index swap in together
+	pipe := r.redis.TxPipeline()
+	indexKey := redisconn.SeatBitmapIndexKey(event.ID)
+	pipe.Del(ctx, indexKey)
+	for start := 0; start < len(index); start += 2 * seatIndexBatch {
+		end := min(start+2*seatIndexBatch, len(index))
+		pipe.HSet(ctx, indexKey, index[start:end]...)
+	}
+	pipe.Expire(ctx, indexKey, seatBitmapTTL)
+	pipe.Set(ctx, redisconn.SeatBitmapKey(event.ID), bits, seatBitmapTTL)
+	version := pipe.Incr(ctx, redisconn.SeatBitmapVersionKey(event.ID))
+	// The version outlives rebuilds, so it keeps growing while clients poll the event
+	pipe.Expire(ctx, redisconn.SeatBitmapVersionKey(event.ID), seatBitmapVersionTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to store seat bitmap: %w", err)
+	}
+
+	return &entities.SeatBitmap{EventID: event.ID, Rows: rows, Columns: columns, Version: version.Val(), Bits: bits}, nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UpdateSeatPrices sets the price of an event's seats matching the change's filters and
+// records each changed seat in its price history. Seats already at the price are left alone.
+func (s *eventRepository) UpdateSeatPrices(ctx context.Context, eventID uint, change entities.SeatPriceChange) ([]entities.SeatPriceHistory, error) {
+	var history []entities.SeatPriceHistory
+
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		var event entities.Event
+		if err := tx.Scopes(tenantScope(ctx, "events")).
+			Select("id", "status").
+			First(&event, eventID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewNotFoundError(constants.ErrEventNotFound, errors.ErrRecordNotFound)
+			}
+			return errors.NewInternalError("Failed to fetch event", err)
+		}
+		if event.Status != constants.EventStatusActive && event.Status != constants.EventStatusSoldOut {
+			return errors.NewBadRequestError("Seat prices can only be changed while the event is on sale", nil)
+		}
+
+		// Lock the seats so confirmations charge either the old or the new price, never a
+		// price missing from the history
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "event_id", "price", "pricing_rule_id").
+			Where("event_id = ? AND is_oversell = false", eventID).
+			Where("price <> ? OR pricing_rule_id IS DISTINCT FROM ?", change.Price, change.PricingRuleID)
+		if len(change.SeatIDs) > 0 {
+			query = query.Where("id IN ?", change.SeatIDs)
+		}
+		if change.SeatType != "" {
+			query = query.Where("seat_type = ?", change.SeatType)
+		}
+		if change.RowEnd > 0 {
+			query = query.Where("\"row\" BETWEEN ? AND ?", change.RowStart, change.RowEnd)
+		}
+		var seats []entities.Seat
+		if err := query.Order("id ASC").Find(&seats).Error; err != nil {
+			return errors.NewInternalError("Failed to fetch seats", err)
+		}
+		if len(seats) == 0 {
+			return errors.NewBadRequestError("No seats to reprice", nil)
+		}
+
+		seatIDs := make([]uint, len(seats))
+		history = make([]entities.SeatPriceHistory, len(seats))
+		for i, seat := range seats {
+			seatIDs[i] = seat.ID
+			history[i] = entities.SeatPriceHistory{
+				EventID:       eventID,
+				SeatID:        seat.ID,
+				OldPrice:      seat.Price,
+				NewPrice:      change.Price,
+				Source:        change.Source,
+				PricingRuleID: change.PricingRuleID,
+				ChangedBy:     change.ChangedBy,
+			}
+		}
+
+		if err := tx.Model(&entities.Seat{}).Where("id IN ?", seatIDs).
+			Updates(map[string]interface{}{
+				"price":           change.Price,
+				"pricing_rule_id": change.PricingRuleID,
+				"updated_at":      time.Now(),
+			}).Error; err != nil {
+			return errors.NewInternalError("Failed to update seat prices", err)
+		}
+
+		if err := tx.CreateInBatches(history, 500).Error; err != nil {
+			return errors.NewInternalError("Failed to record seat price history", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// ListSeatPriceHistory returns a seat's price changes, oldest first
+func (s *eventRepository) ListSeatPriceHistory(ctx context.Context, seatID uint) ([]entities.SeatPriceHistory, error) {
+	var seat entities.Seat
+	if err := conn(ctx, s.db).Scopes(tenantScopeVia(ctx, "seats.event_id", "events")).
+		Select("id").
+		First(&seat, seatID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Seat not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch seat", err)
+	}
+
+	var history []entities.SeatPriceHistory
+	if err := conn(ctx, s.db).
+		Where("seat_id = ?", seatID).
+		Order("created_at ASC, id ASC").
+		Find(&history).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch seat price history", err)
+	}
+	return history, nil
+}
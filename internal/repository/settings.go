@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SettingsRepository stores the runtime setting overrides of platform admins, auditing every
+// change
+type SettingsRepository struct {
+	db *gorm.DB
+}
+
+func NewSettingsRepository(db *gorm.DB) *SettingsRepository {
+	return &SettingsRepository{db: db}
+}
+
+// List returns every overridden setting
+func (s *SettingsRepository) List(ctx context.Context) ([]entities.RuntimeSetting, error) {
+	var settings []entities.RuntimeSetting
+	if err := conn(ctx, s.db).Order("key").Find(&settings).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch runtime settings", err)
+	}
+	return settings, nil
+}
+
+// Set overrides a setting, recording the change. Setting the current value again records nothing.
+func (s *SettingsRepository) Set(ctx context.Context, key, value string, adminID uint, now time.Time) (*entities.RuntimeSetting, error) {
+	setting := entities.RuntimeSetting{Key: key, Value: value, UpdatedBy: adminID, UpdatedAt: now}
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		var current entities.RuntimeSetting
+		found := true
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).First(&current).Error; err != nil {
+			if err != gorm.ErrRecordNotFound {
+				return err
+			}
+			found = false
+		}
+		if found && current.Value == value {
+			setting = current
+			return nil
+		}
+
+		if err := tx.Save(&setting).Error; err != nil {
+			return err
+		}
+		return tx.Create(&entities.SettingChange{
+			Key:       key,
+			OldValue:  current.Value,
+			NewValue:  value,
+			ChangedBy: adminID,
+			ChangedAt: now,
+		}).Error
+	})
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to save runtime setting", err)
+	}
+	return &setting, nil
+}
+
+// Reset removes a setting's override so its default applies again, recording the change.
+// Settings that weren't overridden are left alone and false is returned.
+func (s *SettingsRepository) Reset(ctx context.Context, key string, adminID uint, now time.Time) (bool, error) {
+	reset := false
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		var current entities.RuntimeSetting
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).First(&current).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil
+			}
+			return err
+		}
+
+		if err := tx.Delete(&current).Error; err != nil {
+			return err
+		}
+		reset = true
+		return tx.Create(&entities.SettingChange{
+			Key:       key,
+			OldValue:  current.Value,
+			ChangedBy: adminID,
+			ChangedAt: now,
+		}).Error
+	})
+	if err != nil {
+		return false, errors.NewInternalError("Failed to reset runtime setting", err)
+	}
+	return reset, nil
+}
+
+// ListChanges returns the audited changes newest first, optionally of one setting only
+func (s *SettingsRepository) ListChanges(ctx context.Context, key string, limit, offset int) ([]entities.SettingChange, int64, error) {
+	var changes []entities.SettingChange
+	var total int64
+
+	query := conn(ctx, s.db).Model(&entities.SettingChange{})
+	if key != "" {
+		query = query.Where("key = ?", key)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to count runtime setting changes", err)
+	}
+
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&changes).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to fetch runtime setting changes", err)
+	}
+
+	return changes, total, nil
+}
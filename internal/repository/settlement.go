@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SettlementRepository computes and freezes organizers' event settlements
+type SettlementRepository struct {
+	db *gorm.DB
+}
+
+func NewSettlementRepository(db *gorm.DB) *SettlementRepository {
+	return &SettlementRepository{db: db}
+}
+
+// GetEvent returns an event of the caller's tenant
+func (r *SettlementRepository) GetEvent(ctx context.Context, eventID uint) (*entities.Event, error) {
+	var event entities.Event
+	if err := conn(ctx, r.db).Scopes(tenantScope(ctx, "events")).
+		Select("id", "tenant_id", "name", "status", "start_time", "end_time").
+		First(&event, eventID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Event not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch event", err)
+	}
+	return &event, nil
+}
+
+// GetFrozenSettlement returns an event's frozen settlement
+func (r *SettlementRepository) GetFrozenSettlement(ctx context.Context, eventID uint) (*entities.EventSettlement, error) {
+	var settlement entities.EventSettlement
+	if err := conn(ctx, r.db).Where("event_id = ?", eventID).First(&settlement).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Settlement not frozen", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch settlement", err)
+	}
+	return &settlement, nil
+}
+
+// ComputeSettlement totals an event's sales, refunds, comps, chargebacks and capacity as they
+// stand, archived bookings included. Fees and the net payable are left to the caller.
+func (r *SettlementRepository) ComputeSettlement(ctx context.Context, event *entities.Event) (*entities.EventSettlement, error) {
+	settlement := &entities.EventSettlement{TenantID: event.TenantID, EventID: event.ID}
+	db := conn(ctx, r.db)
+
+	err := bookingHistory(db).Model(&entities.Booking{}).
+		Select(`
+			COUNT(CASE WHEN payment_status IN (?, ?) THEN 1 END),
+			COALESCE(SUM(CASE WHEN payment_status IN (?, ?) THEN total_amount END), 0),
+			COUNT(CASE WHEN payment_status IN (?, ?) AND status NOT IN (?, ?) THEN 1 END),
+			COALESCE(SUM(CASE WHEN payment_status IN (?, ?) AND status NOT IN (?, ?) THEN total_amount END), 0),
+			COUNT(CASE WHEN channel = ? AND status = ? THEN 1 END),
+			COUNT(CASE WHEN channel <> ? AND status = ? THEN 1 END)
+		`,
+			constants.PaymentStatusPaid, constants.PaymentStatusRefunded,
+			constants.PaymentStatusPaid, constants.PaymentStatusRefunded,
+			// Bookings released as no-shows keep their payment
+			constants.PaymentStatusPaid, constants.PaymentStatusRefunded, constants.BookingStatusConfirmed, constants.BookingStatusReleased,
+			constants.PaymentStatusPaid, constants.PaymentStatusRefunded, constants.BookingStatusConfirmed, constants.BookingStatusReleased,
+			constants.SalesChannelComp, constants.BookingStatusConfirmed,
+			constants.SalesChannelComp, constants.BookingStatusConfirmed).
+		Where("event_id = ?", event.ID).
+		Row().Scan(&settlement.TicketsSold, &settlement.GrossSales, &settlement.RefundedTickets, &settlement.Refunds,
+		&settlement.CompTickets, &settlement.SoldSeats)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to total event sales", err)
+	}
+
+	if err := db.Table("disputes d").
+		Joins("JOIN bookings b ON b.id = d.booking_id").
+		Where("b.event_id = ? AND d.status = ?", event.ID, constants.DisputeStatusLost).
+		Select("COALESCE(SUM(d.amount), 0)").
+		Row().Scan(&settlement.Chargebacks); err != nil {
+		return nil, errors.NewInternalError("Failed to total chargebacks", err)
+	}
+
+	if err := db.Model(&entities.Seat{}).
+		Select("COUNT(*), COUNT(CASE WHEN is_held AND is_available THEN 1 END)").
+		Where("event_id = ? AND is_oversell = false", event.ID).
+		Row().Scan(&settlement.Capacity, &settlement.HeldSeats); err != nil {
+		return nil, errors.NewInternalError("Failed to count seats", err)
+	}
+	settlement.SellableSeats = settlement.Capacity - settlement.HeldSeats - settlement.CompTickets
+
+	return settlement, nil
+}
+
+// SaveFrozenSettlement stores a frozen settlement and posts its platform fees to the ledger;
+// an event's settlement is only frozen once
+func (r *SettlementRepository) SaveFrozenSettlement(ctx context.Context, settlement *entities.EventSettlement) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(settlement)
+		if result.Error != nil {
+			return errors.NewInternalError("Failed to freeze settlement", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errors.NewConflictError("Settlement is already frozen", nil)
+		}
+
+		if settlement.PlatformFees <= 0 {
+			return nil
+		}
+		journal := transfer(constants.LedgerKindFee, constants.LedgerAccountSales, constants.LedgerAccountFees, settlement.PlatformFees)
+		journal.TenantID = settlement.TenantID
+		journal.EventID = &settlement.EventID
+		journal.Description = fmt.Sprintf("platform fees of event %d", settlement.EventID)
+		return postJournal(tx, journal)
+	})
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SupportRepository struct {
+	db *gorm.DB
+}
+
+func NewSupportRepository(db *gorm.DB) *SupportRepository {
+	return &SupportRepository{db: db}
+}
+
+// Open opens a support case on one of the user's bookings, archived ones included, with the
+// user's first message. A booking has at most one unresolved case at a time.
+func (s *SupportRepository) Open(ctx context.Context, userID, bookingID uint, category, message string) (*entities.SupportCase, error) {
+	var booking entities.Booking
+	if err := bookingHistory(conn(ctx, s.db)).
+		Where("id = ? AND user_id = ?", bookingID, userID).
+		First(&booking).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Booking not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch booking", err)
+	}
+
+	supportCase := &entities.SupportCase{
+		TenantID:  booking.TenantID,
+		BookingID: booking.ID,
+		UserID:    userID,
+		EventID:   booking.EventID,
+		Category:  category,
+		Status:    constants.SupportCaseStatusOpen,
+	}
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		// Lock the user's row so two requests can't both open a case on the booking
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+			First(&entities.User{}, userID).Error; err != nil {
+			return errors.NewInternalError("Failed to lock user", err)
+		}
+
+		var unresolved int64
+		if err := tx.Model(&entities.SupportCase{}).
+			Where("booking_id = ? AND status <> ?", bookingID, constants.SupportCaseStatusResolved).
+			Count(&unresolved).Error; err != nil {
+			return errors.NewInternalError("Failed to check support cases", err)
+		}
+		if unresolved > 0 {
+			return errors.NewConflictError(constants.ErrSupportCaseAlreadyOpen, nil)
+		}
+
+		if err := tx.Create(supportCase).Error; err != nil {
+			return errors.NewInternalError("Failed to open support case", err)
+		}
+		first := entities.SupportCaseMessage{CaseID: supportCase.ID, AuthorID: userID, Message: message}
+		if err := tx.Create(&first).Error; err != nil {
+			return errors.NewInternalError("Failed to open support case", err)
+		}
+		supportCase.Messages = []entities.SupportCaseMessage{first}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return supportCase, nil
+}
+
+// ListByUser returns a user's support cases, most recently updated first
+func (s *SupportRepository) ListByUser(ctx context.Context, userID uint, status string, limit, offset int) ([]entities.SupportCase, int64, error) {
+	return s.list(conn(ctx, s.db).Where("user_id = ?", userID), status, "", limit, offset)
+}
+
+// GetForUser returns one of a user's support cases with its messages
+func (s *SupportRepository) GetForUser(ctx context.Context, userID, caseID uint) (*entities.SupportCase, error) {
+	return s.get(conn(ctx, s.db).Where("user_id = ?", userID), caseID)
+}
+
+// List returns the support cases of the caller's tenant, most recently updated first, to
+// staff
+func (s *SupportRepository) List(ctx context.Context, status, category string, limit, offset int) ([]entities.SupportCase, int64, error) {
+	return s.list(conn(ctx, s.db).Scopes(tenantScope(ctx, "support_cases")), status, category, limit, offset)
+}
+
+// Get returns a support case of the caller's tenant with its messages, to staff
+func (s *SupportRepository) Get(ctx context.Context, caseID uint) (*entities.SupportCase, error) {
+	return s.get(conn(ctx, s.db).Scopes(tenantScope(ctx, "support_cases")), caseID)
+}
+
+// Respond adds a staff member's response to a support case of the caller's tenant, marking it
+// answered, or resolved if resolve is set. The message may be empty when resolving.
+func (s *SupportRepository) Respond(ctx context.Context, caseID, staffID uint, message string, resolve bool) (*entities.SupportCase, error) {
+	var supportCase entities.SupportCase
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Scopes(tenantScope(ctx, "support_cases")).Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&supportCase, caseID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewNotFoundError(constants.ErrSupportCaseNotFound, errors.ErrRecordNotFound)
+			}
+			return errors.NewInternalError("Failed to fetch support case", err)
+		}
+		if supportCase.Status == constants.SupportCaseStatusResolved {
+			return errors.NewBadRequestError(constants.ErrSupportCaseResolved, nil)
+		}
+
+		if message != "" {
+			reply := entities.SupportCaseMessage{CaseID: caseID, AuthorID: staffID, FromStaff: true, Message: message}
+			if err := tx.Create(&reply).Error; err != nil {
+				return errors.NewInternalError("Failed to respond to support case", err)
+			}
+		}
+
+		updates := map[string]interface{}{"status": constants.SupportCaseStatusAnswered}
+		if resolve {
+			updates = map[string]interface{}{"status": constants.SupportCaseStatusResolved, "resolved_at": time.Now()}
+		}
+		if err := tx.Model(&supportCase).Updates(updates).Error; err != nil {
+			return errors.NewInternalError("Failed to update support case", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.get(conn(ctx, s.db), caseID)
+}
+
+func (s *SupportRepository) list(query *gorm.DB, status, category string, limit, offset int) ([]entities.SupportCase, int64, error) {
+	var cases []entities.SupportCase
+	var total int64
+
+	query = query.Model(&entities.SupportCase{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to count support cases", err)
+	}
+	if err := query.Order("updated_at DESC, id DESC").Limit(limit).Offset(offset).
+		Find(&cases).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to fetch support cases", err)
+	}
+	return cases, total, nil
+}
+
+func (s *SupportRepository) get(query *gorm.DB, caseID uint) (*entities.SupportCase, error) {
+	var supportCase entities.SupportCase
+	if err := query.Preload("User").
+		Preload("Messages", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC, id ASC") }).
+		First(&supportCase, caseID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError(constants.ErrSupportCaseNotFound, errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch support case", err)
+	}
+	return &supportCase, nil
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TaskRepository struct {
+	db *gorm.DB
+}
+
+func NewTaskRepository(db *gorm.DB) *TaskRepository {
+	return &TaskRepository{db: db}
+}
+
+// Create records a new pending task, ready to run immediately
+func (s *TaskRepository) Create(ctx context.Context, task *entities.Task) error {
+	task.TenantID = tenantForCreate(ctx, task.TenantID)
+	task.Status = constants.TaskStatusPending
+	task.RunAt = time.Now()
+	if err := conn(ctx, s.db).Create(task).Error; err != nil {
+		return errors.NewInternalError("Failed to create task", err)
+	}
+	return nil
+}
+
+// GetByID returns a task; tenant admins only see their own tenant's tasks
+func (s *TaskRepository) GetByID(ctx context.Context, taskID uint) (*entities.Task, error) {
+	var task entities.Task
+	if err := conn(ctx, s.db).Scopes(tenantScope(ctx, "tasks")).First(&task, taskID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Task not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch task", err)
+	}
+	return &task, nil
+}
+
+// List returns tasks newest first, optionally filtered by kind and status
+func (s *TaskRepository) List(ctx context.Context, kind, status string, limit, offset int) ([]entities.Task, int64, error) {
+	var tasks []entities.Task
+	var total int64
+
+	query := conn(ctx, s.db).Model(&entities.Task{}).Scopes(tenantScope(ctx, "tasks"))
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to count tasks", err)
+	}
+
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&tasks).Error; err != nil {
+		return nil, 0, errors.NewInternalError("Failed to fetch tasks", err)
+	}
+
+	return tasks, total, nil
+}
+
+// Claim marks the next due pending task running and returns it, or nil when none is due.
+// SKIP LOCKED lets workers on several instances claim tasks without picking the same one.
+func (s *TaskRepository) Claim(ctx context.Context) (*entities.Task, error) {
+	var task entities.Task
+	now := time.Now()
+	if err := conn(ctx, s.db).Raw(`
+		UPDATE tasks SET status = ?, attempts = attempts + 1, started_at = ?, updated_at = ?
+		WHERE id = (
+			SELECT id FROM tasks WHERE status = ? AND run_at <= ?
+			ORDER BY run_at, id LIMIT 1 FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		constants.TaskStatusRunning, now, now, constants.TaskStatusPending, now).
+		Scan(&task).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to claim task", err)
+	}
+	if task.ID == 0 {
+		return nil, nil
+	}
+	return &task, nil
+}
+
+// RequeueStale puts running tasks that stopped reporting progress, e.g. because their instance
+// crashed, back in the queue. It returns how many were requeued.
+func (s *TaskRepository) RequeueStale(ctx context.Context, staleAfter time.Duration) (int64, error) {
+	now := time.Now()
+	result := conn(ctx, s.db).Model(&entities.Task{}).
+		Where("status = ? AND updated_at < ?", constants.TaskStatusRunning, now.Add(-staleAfter)).
+		Updates(map[string]interface{}{
+			"status": constants.TaskStatusPending,
+			"run_at": now,
+			"error":  "worker stopped responding",
+		})
+	if result.Error != nil {
+		return 0, errors.NewInternalError("Failed to requeue stale tasks", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// UpdateProgress records how many of the task's units are done
+func (s *TaskRepository) UpdateProgress(ctx context.Context, taskID uint, progress, total int) error {
+	if err := conn(ctx, s.db).Model(&entities.Task{}).Where("id = ?", taskID).
+		Updates(map[string]interface{}{"progress": progress, "total": total}).Error; err != nil {
+		return errors.NewInternalError("Failed to update task", err)
+	}
+	return nil
+}
+
+// Complete marks the task done, pointing at the record it created
+func (s *TaskRepository) Complete(ctx context.Context, taskID uint, resultID *uint) error {
+	now := time.Now()
+	if err := conn(ctx, s.db).Model(&entities.Task{}).Where("id = ?", taskID).
+		Updates(map[string]interface{}{
+			"status":       constants.TaskStatusCompleted,
+			"progress":     gorm.Expr("total"),
+			"error":        "",
+			"result_id":    resultID,
+			"completed_at": now,
+		}).Error; err != nil {
+		return errors.NewInternalError("Failed to update task", err)
+	}
+	return nil
+}
+
+// Retry puts the task back in the queue to run again at runAt, keeping the attempt's error
+func (s *TaskRepository) Retry(ctx context.Context, taskID uint, message string, runAt time.Time) error {
+	if err := conn(ctx, s.db).Model(&entities.Task{}).Where("id = ?", taskID).
+		Updates(map[string]interface{}{
+			"status":   constants.TaskStatusPending,
+			"error":    message,
+			"progress": 0,
+			"run_at":   runAt,
+		}).Error; err != nil {
+		return errors.NewInternalError("Failed to update task", err)
+	}
+	return nil
+}
+
+// Fail marks the task failed with a message safe to show the admin who started it
+func (s *TaskRepository) Fail(ctx context.Context, taskID uint, message string) error {
+	now := time.Now()
+	if err := conn(ctx, s.db).Model(&entities.Task{}).Where("id = ?", taskID).
+		Updates(map[string]interface{}{
+			"status":       constants.TaskStatusFailed,
+			"error":        message,
+			"completed_at": now,
+		}).Error; err != nil {
+		return errors.NewInternalError("Failed to update task", err)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/tenant"
+	"api/pkg/errors"
+	"context"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TenantRepository struct {
+	db *gorm.DB
+}
+
+func NewTenantRepository(db *gorm.DB) *TenantRepository {
+	return &TenantRepository{db: db}
+}
+
+// EnsureDefaultTenant creates the tenant existing venues, events and bookings are assigned to
+func (s *TenantRepository) EnsureDefaultTenant(ctx context.Context) error {
+	return conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&entities.Tenant{}).Where("id = ?", tenant.DefaultID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+		if err := tx.Create(&entities.Tenant{ID: tenant.DefaultID, Name: "Default", Slug: "default"}).Error; err != nil {
+			return err
+		}
+		// The row was inserted with an explicit ID, so move the sequence past it
+		return tx.Exec("SELECT setval(pg_get_serial_sequence('tenants', 'id'), (SELECT MAX(id) FROM tenants))").Error
+	})
+}
+
+func (s *TenantRepository) Create(ctx context.Context, t *entities.Tenant) error {
+	var count int64
+	if err := conn(ctx, s.db).Model(&entities.Tenant{}).Where("slug = ?", t.Slug).Count(&count).Error; err != nil {
+		return errors.NewInternalError("Failed to check tenant slug", err)
+	}
+	if count > 0 {
+		return errors.NewConflictError("A tenant with this slug already exists", nil)
+	}
+
+	if err := conn(ctx, s.db).Create(t).Error; err != nil {
+		return errors.NewInternalError("Failed to create tenant", err)
+	}
+	return nil
+}
+
+func (s *TenantRepository) GetByID(ctx context.Context, tenantID uint) (*entities.Tenant, error) {
+	var t entities.Tenant
+	if err := conn(ctx, s.db).First(&t, tenantID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Tenant not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch tenant", err)
+	}
+	return &t, nil
+}
+
+// GetBySlug returns the organizer behind a public storefront
+func (s *TenantRepository) GetBySlug(ctx context.Context, slug string) (*entities.Tenant, error) {
+	var t entities.Tenant
+	if err := conn(ctx, s.db).Where("slug = ?", slug).First(&t).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Organizer not found", errors.ErrRecordNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch organizer", err)
+	}
+	return &t, nil
+}
+
+// GetUpcomingEvents returns an organizer's public events that haven't started, soonest first
+func (s *TenantRepository) GetUpcomingEvents(ctx context.Context, tenantID uint, now time.Time, limit int) ([]entities.Event, error) {
+	var events []entities.Event
+	if err := conn(ctx, s.db).Preload("Venue").
+		Where("tenant_id = ? AND status IN ? AND start_time > ? AND sandbox = false",
+			tenantID, []string{constants.EventStatusActive, constants.EventStatusSoldOut}, now).
+		Order("start_time ASC").Limit(limit).Find(&events).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch organizer events", err)
+	}
+	return events, nil
+}
+
+// GetRatings averages the reviews attendees left for an organizer's events
+func (s *TenantRepository) GetRatings(ctx context.Context, tenantID uint) (entities.OrganizerRatings, error) {
+	var row struct {
+		Average float64
+		Count   int64
+	}
+	if err := conn(ctx, s.db).Model(&entities.EventReview{}).
+		Select("COALESCE(AVG(rating), 0) AS average, COUNT(*) AS count").
+		Where("tenant_id = ?", tenantID).
+		Scan(&row).Error; err != nil {
+		return entities.OrganizerRatings{}, errors.NewInternalError("Failed to fetch organizer ratings", err)
+	}
+	return entities.OrganizerRatings{Average: math.Round(row.Average*10) / 10, Count: row.Count}, nil
+}
+
+// GetOwn returns a tenant if the caller may manage it: tenant admins only their own tenant
+func (s *TenantRepository) GetOwn(ctx context.Context, tenantID uint) (*entities.Tenant, error) {
+	if scopedID, ok := tenant.FromContext(ctx); ok && scopedID != tenantID {
+		return nil, errors.NewNotFoundError("Tenant not found", errors.ErrRecordNotFound)
+	}
+	return s.GetByID(ctx, tenantID)
+}
+
+func (s *TenantRepository) List(ctx context.Context) ([]entities.Tenant, error) {
+	var tenants []entities.Tenant
+	if err := conn(ctx, s.db).Order("id ASC").Find(&tenants).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch tenants", err)
+	}
+	return tenants, nil
+}
+
+func (s *TenantRepository) Update(ctx context.Context, tenantID uint, updates map[string]interface{}) (*entities.Tenant, error) {
+	t, err := s.GetOwn(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn(ctx, s.db).Model(t).Updates(updates).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to update tenant", err)
+	}
+	return t, nil
+}
+
+// RateLimits returns the per-tenant admin rate limits that override the default
+func (s *TenantRepository) RateLimits(ctx context.Context) (map[uint]int, error) {
+	var tenants []entities.Tenant
+	if err := conn(ctx, s.db).Select("id", "rate_limit").
+		Where("rate_limit > 0").Find(&tenants).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to fetch tenant rate limits", err)
+	}
+	limits := make(map[uint]int, len(tenants))
+	for _, t := range tenants {
+		limits[t.ID] = t.RateLimit
+	}
+	return limits, nil
+}
+
+// AssignAdmin scopes an admin to a tenant, or makes them a platform admin when tenantID is nil
+func (s *TenantRepository) AssignAdmin(ctx context.Context, userID uint, tenantID *uint) (*entities.User, error) {
+	if tenantID != nil {
+		if _, err := s.GetByID(ctx, *tenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	var user entities.User
+	if err := conn(ctx, s.db).First(&user, userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("User not found", errors.ErrUserNotFound)
+		}
+		return nil, errors.NewInternalError("Failed to fetch user", err)
+	}
+	if !user.IsAdmin {
+		return nil, errors.NewBadRequestError("Only admins can be assigned to a tenant", nil)
+	}
+
+	// Tokens carry the tenant, so the ones issued before the change are revoked
+	if err := conn(ctx, s.db).Model(&entities.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+		"tenant_id":     tenantID,
+		"token_version": gorm.Expr("token_version + 1"),
+	}).Error; err != nil {
+		return nil, errors.NewInternalError("Failed to assign tenant", err)
+	}
+	user.TenantID = tenantID
+	user.Password = ""
+	return &user, nil
+}
+
+// tenantScope restricts a query to the tenant in ctx by filtering table's tenant_id.
+// Unscoped contexts (platform admins, public endpoints, background jobs) see every tenant.
+func tenantScope(ctx context.Context, table string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		tenantID, ok := tenant.FromContext(ctx)
+		if !ok {
+			return db
+		}
+		return db.Where(table+".tenant_id = ?", tenantID)
+	}
+}
+
+// tenantScopeVia restricts a query to rows whose column references a record of table that
+// belongs to the tenant in ctx, e.g. disputes.booking_id through bookings
+func tenantScopeVia(ctx context.Context, column, table string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		tenantID, ok := tenant.FromContext(ctx)
+		if !ok {
+			return db
+		}
+		owned := db.Session(&gorm.Session{NewDB: true}).Table(table).Select("id").Where("tenant_id = ?", tenantID)
+		return db.Where(column+" IN (?)", owned)
+	}
+}
+
+// tenantForCreate returns the tenant a new record belongs to: the caller's tenant if scoped,
+// otherwise the requested one, falling back to the default tenant
+func tenantForCreate(ctx context.Context, requested uint) uint {
+	if tenantID, ok := tenant.FromContext(ctx); ok {
+		return tenantID
+	}
+	if requested != 0 {
+		return requested
+	}
+	return tenant.DefaultID
+}
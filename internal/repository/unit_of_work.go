@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// UnitOfWork runs several repository calls in one database transaction. The transaction
+// travels in the context passed to the work, and repositories pick it up from there instead
+// of using their own connection.
+type UnitOfWork interface {
+	// Do runs work in a transaction that commits if work returns nil and rolls back otherwise.
+	// Called inside another unit of work it joins the outer transaction, so nothing commits
+	// until the outermost work returns.
+	Do(ctx context.Context, work func(ctx context.Context) error) error
+}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+// txKey is the context key of the transaction of the unit of work in progress
+type txKey struct{}
+
+func (u *unitOfWork) Do(ctx context.Context, work func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return work(ctx)
+	}
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return work(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn returns the transaction of the unit of work ctx belongs to, or db outside of one. A
+// repository's own transactions opened on it become savepoints of the unit of work.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
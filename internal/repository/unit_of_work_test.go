@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/money"
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestUnitOfWork checks against a scratch Postgres database that calls to several repository
+// methods commit or roll back together, including methods that open their own transaction.
+// It needs TEST_DATABASE_URL.
+func TestUnitOfWork(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	registerTestKeyring(t)
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger:                                   logger.Discard,
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&entities.User{}, &entities.Seat{}, &entities.BookingIntent{}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	uow := NewUnitOfWork(db)
+	repo := NewBookingRepository(db)
+	abort := errors.New("abort")
+
+	user := entities.User{Email: "unit-of-work-" + time.Now().Format("150405.000000") + "@example.com", Password: "unused"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	newSeat := func() *entities.Seat {
+		seat := &entities.Seat{EventID: 1, Row: 1, Column: 1, SeatType: constants.SeatTypeStandard, Price: money.FromMajor(10), IsAvailable: true}
+		if err := db.Create(seat).Error; err != nil {
+			t.Fatal(err)
+		}
+		return seat
+	}
+	newIntent := func(seat *entities.Seat) *entities.BookingIntent {
+		now := time.Now()
+		return &entities.BookingIntent{UserID: user.ID, EventID: seat.EventID, SeatID: seat.ID,
+			Status: constants.IntentStatusPending, LockExpiresAt: now.Add(time.Minute), CreatedAt: now}
+	}
+	intentStored := func(id uint) bool {
+		var count int64
+		if err := db.Model(&entities.BookingIntent{}).Where("id = ?", id).Count(&count).Error; err != nil {
+			t.Fatal(err)
+		}
+		return count == 1
+	}
+	seatLocked := func(seat *entities.Seat) bool {
+		var current entities.Seat
+		if err := db.First(&current, seat.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		return current.IsLocked
+	}
+
+	t.Run("rolls back every call", func(t *testing.T) {
+		seat := newSeat()
+		intent := newIntent(seat)
+		err := uow.Do(ctx, func(ctx context.Context) error {
+			if err := repo.CreateBookingIntent(ctx, intent, 0); err != nil {
+				return err
+			}
+			if err := repo.LockSeatInDatabase(ctx, seat.ID, user.ID, time.Now(), time.Now().Add(-time.Hour)); err != nil {
+				return err
+			}
+			return abort
+		})
+		if err != abort {
+			t.Fatalf("Do returned %v, want %v", err, abort)
+		}
+		if intentStored(intent.ID) || seatLocked(seat) {
+			t.Error("writes of the aborted unit of work were committed")
+		}
+	})
+
+	t.Run("nested units join the outer one", func(t *testing.T) {
+		seat := newSeat()
+		intent := newIntent(seat)
+		err := uow.Do(ctx, func(ctx context.Context) error {
+			if err := uow.Do(ctx, func(ctx context.Context) error {
+				return repo.CreateBookingIntent(ctx, intent, 0)
+			}); err != nil {
+				return err
+			}
+			if !intentStored(intent.ID) {
+				// Outside the transaction the inner unit's write isn't visible yet
+				return nil
+			}
+			return errors.New("inner unit of work committed on its own")
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !intentStored(intent.ID) {
+			t.Error("intent not committed with the outer unit of work")
+		}
+	})
+
+	t.Run("repository transactions become savepoints", func(t *testing.T) {
+		seat := newSeat()
+		intent := newIntent(seat)
+		if err := repo.CreateBookingIntent(ctx, intent, 0); err != nil {
+			t.Fatal(err)
+		}
+		err := uow.Do(ctx, func(ctx context.Context) error {
+			if _, err := repo.CancelBookingIntent(ctx, intent.ID, user.ID); err != nil {
+				return err
+			}
+			return abort
+		})
+		if err != abort {
+			t.Fatalf("Do returned %v, want %v", err, abort)
+		}
+		pending, err := repo.GetPendingIntent(ctx, intent.ID)
+		if err != nil || pending.Status != constants.IntentStatusPending {
+			t.Errorf("cancellation survived the rollback: %v", err)
+		}
+	})
+}
@@ -2,31 +2,43 @@ package repository
 
 import (
 	"api/internal/entities"
+	"api/internal/password"
 	"api/pkg/errors"
+	logger "api/pkg/logging"
 	"context"
 	"strings"
 
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
-type UserRepository struct {
-	db *gorm.DB
+type UserRepository interface {
+	Register(ctx context.Context, email, plaintext, firstName, lastName, phone string, isAdmin bool) (*entities.User, error)
+	Login(ctx context.Context, email, plaintext string) (*entities.User, error)
+	GetByID(ctx context.Context, userID uint) (*entities.User, error)
+	SetMembershipTier(ctx context.Context, userID uint, tier string) (*entities.User, error)
+	GetAdmins(ctx context.Context) ([]entities.User, error)
+	ChangePassword(ctx context.Context, userID uint, current, plaintext string) (*entities.User, error)
+	GetTokenVersion(ctx context.Context, userID uint) (int, error)
 }
 
-func NewUserRepository(db *gorm.DB) *UserRepository {
-	return &UserRepository{db: db}
+type userRepository struct {
+	db     *gorm.DB
+	hasher *password.Hasher
 }
 
-func (s *UserRepository) Register(ctx context.Context, email, password, firstName, lastName, phone string, isAdmin bool) (*entities.User, error) {
+func NewUserRepository(db *gorm.DB, hasher *password.Hasher) UserRepository {
+	return &userRepository{db: db, hasher: hasher}
+}
+
+func (s *userRepository) Register(ctx context.Context, email, plaintext, firstName, lastName, phone string, isAdmin bool) (*entities.User, error) {
 	// Check if user already exists
 	var existingUser entities.User
-	if err := s.db.WithContext(ctx).Where("email = ?", email).First(&existingUser).Error; err == nil {
+	if err := conn(ctx, s.db).Where("email = ?", email).First(&existingUser).Error; err == nil {
 		return nil, errors.NewConflictError("User already exists", errors.ErrUserAlreadyExists)
 	}
 
 	// Hash password
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hash, err := s.hasher.Hash(plaintext)
 	if err != nil {
 		return nil, errors.NewInternalError("Failed to hash password", err)
 	}
@@ -34,14 +46,14 @@ func (s *UserRepository) Register(ctx context.Context, email, password, firstNam
 	// Create user
 	user := &entities.User{
 		Email:     strings.ToLower(email),
-		Password:  string(hash),
+		Password:  hash,
 		FirstName: firstName,
 		LastName:  lastName,
 		Phone:     phone,
 		IsAdmin:   isAdmin,
 	}
 
-	if err := s.db.WithContext(ctx).Create(user).Error; err != nil {
+	if err := conn(ctx, s.db).Create(user).Error; err != nil {
 		if strings.Contains(err.Error(), "duplicate") || strings.Contains(err.Error(), "unique") {
 			return nil, errors.NewConflictError("User already exists", errors.ErrUserAlreadyExists)
 		}
@@ -53,27 +65,54 @@ func (s *UserRepository) Register(ctx context.Context, email, password, firstNam
 	return user, nil
 }
 
-func (s *UserRepository) Login(ctx context.Context, email, password string) (*entities.User, error) {
+func (s *userRepository) Login(ctx context.Context, email, plaintext string) (*entities.User, error) {
 	var user entities.User
-	if err := s.db.WithContext(ctx).Where("email = ?", strings.ToLower(email)).First(&user).Error; err != nil {
+	if err := conn(ctx, s.db).Where("email = ?", strings.ToLower(email)).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
+			// Take as long as a real check so response times don't reveal which emails exist
+			s.hasher.VerifyDummy(plaintext)
 			return nil, errors.NewUnauthorizedError("Invalid credentials", errors.ErrInvalidCredentials)
 		}
 		return nil, errors.NewInternalError("Database error", err)
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+	needsRehash, err := s.hasher.Verify(user.Password, plaintext)
+	if err != nil {
 		return nil, errors.NewUnauthorizedError("Invalid credentials", errors.ErrInvalidCredentials)
 	}
+	if user.DeletionScheduledAt != nil {
+		return nil, errors.NewUnauthorizedError("Account is scheduled for deletion, use the restore link to keep it", errors.ErrInvalidCredentials)
+	}
+
+	// Upgrade hashes made with an older algorithm or weaker parameters while we have the password
+	if needsRehash {
+		s.rehashPassword(ctx, &user, plaintext)
+	}
 
 	// Clear password from response
 	user.Password = ""
 	return &user, nil
 }
 
-func (s *UserRepository) GetByID(ctx context.Context, userID uint) (*entities.User, error) {
+// rehashPassword stores a new hash with the current parameters. Failures are only logged:
+// the old hash keeps working and the upgrade is retried on the next login.
+func (s *userRepository) rehashPassword(ctx context.Context, user *entities.User, plaintext string) {
+	hash, err := s.hasher.Hash(plaintext)
+	if err != nil {
+		logger.Warnf("Failed to rehash password for user %d: %v", user.ID, err)
+		return
+	}
+	// Only replace the hash that was verified, in case the password changed meanwhile
+	if err := conn(ctx, s.db).Model(&entities.User{}).
+		Where("id = ? AND password = ?", user.ID, user.Password).
+		Update("password", hash).Error; err != nil {
+		logger.Warnf("Failed to store rehashed password for user %d: %v", user.ID, err)
+	}
+}
+
+func (s *userRepository) GetByID(ctx context.Context, userID uint) (*entities.User, error) {
 	var user entities.User
-	if err := s.db.WithContext(ctx).Where("id = ?", userID).First(&user).Error; err != nil {
+	if err := conn(ctx, s.db).Where("id = ?", userID).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.NewNotFoundError("User not found", errors.ErrUserNotFound)
 		}
@@ -83,3 +122,78 @@ func (s *UserRepository) GetByID(ctx context.Context, userID uint) (*entities.Us
 	user.Password = ""
 	return &user, nil
 }
+
+// SetMembershipTier assigns the tier used to prioritise the user on event waitlists
+func (s *userRepository) SetMembershipTier(ctx context.Context, userID uint, tier string) (*entities.User, error) {
+	result := conn(ctx, s.db).Model(&entities.User{}).Where("id = ?", userID).Update("membership_tier", tier)
+	if result.Error != nil {
+		return nil, errors.NewInternalError("Database error", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.NewNotFoundError("User not found", errors.ErrUserNotFound)
+	}
+
+	return s.GetByID(ctx, userID)
+}
+
+// ChangePassword replaces the user's password once the current one is verified, and bumps the
+// token version so tokens issued with the old password are rejected
+func (s *userRepository) ChangePassword(ctx context.Context, userID uint, current, plaintext string) (*entities.User, error) {
+	var user entities.User
+	if err := conn(ctx, s.db).Where("id = ?", userID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("User not found", errors.ErrUserNotFound)
+		}
+		return nil, errors.NewInternalError("Database error", err)
+	}
+
+	if _, err := s.hasher.Verify(user.Password, current); err != nil {
+		return nil, errors.NewUnauthorizedError("Invalid credentials", errors.ErrInvalidCredentials)
+	}
+
+	hash, err := s.hasher.Hash(plaintext)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to hash password", err)
+	}
+
+	// Only replace the hash that was verified, in case the password changed meanwhile
+	result := conn(ctx, s.db).Model(&entities.User{}).
+		Where("id = ? AND password = ?", user.ID, user.Password).
+		Updates(map[string]interface{}{
+			"password":      hash,
+			"token_version": gorm.Expr("token_version + 1"),
+		})
+	if result.Error != nil {
+		return nil, errors.NewInternalError("Failed to change password", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.NewConflictError("Password was changed meanwhile", nil)
+	}
+
+	return s.GetByID(ctx, userID)
+}
+
+// GetTokenVersion returns the version tokens of the user must carry to be accepted
+func (s *userRepository) GetTokenVersion(ctx context.Context, userID uint) (int, error) {
+	var versions []int
+	if err := conn(ctx, s.db).Model(&entities.User{}).Where("id = ?", userID).Limit(1).Pluck("token_version", &versions).Error; err != nil {
+		return 0, errors.NewInternalError("Database error", err)
+	}
+	if len(versions) == 0 {
+		return 0, errors.NewNotFoundError("User not found", errors.ErrUserNotFound)
+	}
+	return versions[0], nil
+}
+
+// GetAdmins returns all admin users
+func (s *userRepository) GetAdmins(ctx context.Context) ([]entities.User, error) {
+	var users []entities.User
+	if err := conn(ctx, s.db).Where("is_admin = ?", true).Find(&users).Error; err != nil {
+		return nil, errors.NewInternalError("Database error", err)
+	}
+
+	for i := range users {
+		users[i].Password = ""
+	}
+	return users, nil
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"api/internal/entities"
+	"api/internal/password"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestRehashPasswordOnlyReplacesVerifiedHash checks against a scratch Postgres database that
+// upgrading a hash on login doesn't overwrite a password changed since it was verified.
+// It needs TEST_DATABASE_URL.
+func TestRehashPasswordOnlyReplacesVerifiedHash(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	registerTestKeyring(t)
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger:                                   logger.Discard,
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&entities.User{}); err != nil {
+		t.Fatal(err)
+	}
+
+	legacy, err := password.NewHasher(password.Params{Algorithm: password.Bcrypt, BcryptCost: bcrypt.MinCost})
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := password.NewHasher(password.Params{Algorithm: password.Argon2id,
+		Argon2Memory: 1024, Argon2Iterations: 1, Argon2Parallelism: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &userRepository{db: db, hasher: current}
+	ctx := context.Background()
+	runID := time.Now().Format("150405.000000")
+
+	createUser := func(email string) *entities.User {
+		hash, err := legacy.Hash("old password")
+		if err != nil {
+			t.Fatal(err)
+		}
+		user := &entities.User{Email: email, Password: hash}
+		if err := db.Create(user).Error; err != nil {
+			t.Fatal(err)
+		}
+		return user
+	}
+	storedHash := func(userID uint) string {
+		var user entities.User
+		if err := db.First(&user, userID).Error; err != nil {
+			t.Fatal(err)
+		}
+		return user.Password
+	}
+
+	// Unchanged since it was verified: the bcrypt hash is upgraded
+	user := createUser("rehash-" + runID + "@example.com")
+	repo.rehashPassword(ctx, user, "old password")
+	if hash := storedHash(user.ID); !strings.HasPrefix(hash, "$argon2id$") {
+		t.Errorf("stored hash = %q, want an argon2id hash", hash)
+	}
+
+	// Changed after the old hash was verified: the new password's hash is kept
+	user = createUser("rehash-changed-" + runID + "@example.com")
+	changed, err := current.Hash("new password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&entities.User{}).Where("id = ?", user.ID).Update("password", changed).Error; err != nil {
+		t.Fatal(err)
+	}
+	repo.rehashPassword(ctx, user, "old password")
+	if hash := storedHash(user.ID); hash != changed {
+		t.Errorf("rehash replaced a password changed since it was verified")
+	}
+}
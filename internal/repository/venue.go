@@ -2,26 +2,35 @@ package repository
 
 import (
 	"api/internal/entities"
+	"api/internal/expand"
 	"api/pkg/errors"
 	"context"
 
 	"gorm.io/gorm"
 )
 
-type VenueRepository struct {
+type VenueRepository interface {
+	GetVenues(ctx context.Context, limit, offset int, city string, metadata map[string]string) ([]entities.Venue, int64, error)
+	GetVenueByID(ctx context.Context, venueID uint) (*entities.Venue, error)
+	CreateVenue(ctx context.Context, venue *entities.Venue) error
+	UpdateVenue(ctx context.Context, venueID uint, updates map[string]interface{}) (*entities.Venue, error)
+	DeleteVenue(ctx context.Context, venueID uint) error
+}
+
+type venueRepository struct {
 	db *gorm.DB
 }
 
-func NewVenueRepository(db *gorm.DB) *VenueRepository {
-	return &VenueRepository{db: db}
+func NewVenueRepository(db *gorm.DB) VenueRepository {
+	return &venueRepository{db: db}
 }
 
 // GetVenues returns a paginated list of venues
-func (s *VenueRepository) GetVenues(ctx context.Context, limit, offset int, city string) ([]entities.Venue, int64, error) {
+func (s *venueRepository) GetVenues(ctx context.Context, limit, offset int, city string, metadata map[string]string) ([]entities.Venue, int64, error) {
 	var venues []entities.Venue
 	var total int64
 
-	query := s.db.WithContext(ctx).Model(&entities.Venue{})
+	query := conn(ctx, s.db).Model(&entities.Venue{}).Scopes(tenantScope(ctx, "venues"), metadataScope("venues", metadata))
 
 	if city != "" {
 		query = query.Where("city ILIKE ?", "%"+city+"%")
@@ -42,13 +51,15 @@ func (s *VenueRepository) GetVenues(ctx context.Context, limit, offset int, city
 	return venues, total, nil
 }
 
-// GetVenueByID returns a single venue with details
-func (s *VenueRepository) GetVenueByID(ctx context.Context, venueID uint) (*entities.Venue, error) {
+// GetVenueByID returns a single venue with its active events, unless ctx leaves them out
+func (s *venueRepository) GetVenueByID(ctx context.Context, venueID uint) (*entities.Venue, error) {
 	var venue entities.Venue
 
-	if err := s.db.WithContext(ctx).
-		Preload("Events", "status = ?", "active").
-		First(&venue, venueID).Error; err != nil {
+	query := conn(ctx, s.db).Scopes(tenantScope(ctx, "venues"))
+	if expand.Includes(ctx, expand.Events) {
+		query = query.Preload("Events", "status = ?", "active")
+	}
+	if err := query.First(&venue, venueID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.NewNotFoundError("Venue not found", errors.ErrRecordNotFound)
 		}
@@ -59,8 +70,21 @@ func (s *VenueRepository) GetVenueByID(ctx context.Context, venueID uint) (*enti
 }
 
 // CreateVenue creates a new venue (admin only)
-func (s *VenueRepository) CreateVenue(ctx context.Context, venue *entities.Venue) error {
-	if err := s.db.WithContext(ctx).Create(venue).Error; err != nil {
+func (s *venueRepository) CreateVenue(ctx context.Context, venue *entities.Venue) error {
+	if err := entities.ValidateMetadata(venue.Metadata); err != nil {
+		return errors.NewBadRequestError(err.Error(), err)
+	}
+
+	venue.TenantID = tenantForCreate(ctx, venue.TenantID)
+	var tenantCount int64
+	if err := conn(ctx, s.db).Model(&entities.Tenant{}).Where("id = ?", venue.TenantID).Count(&tenantCount).Error; err != nil {
+		return errors.NewInternalError("Failed to check tenant", err)
+	}
+	if tenantCount == 0 {
+		return errors.NewBadRequestError("Tenant not found", nil)
+	}
+
+	if err := conn(ctx, s.db).Create(venue).Error; err != nil {
 		return errors.NewInternalError("Failed to create venue", err)
 	}
 
@@ -68,17 +92,23 @@ func (s *VenueRepository) CreateVenue(ctx context.Context, venue *entities.Venue
 }
 
 // UpdateVenue updates an existing venue (admin only)
-func (s *VenueRepository) UpdateVenue(ctx context.Context, venueID uint, updates map[string]interface{}) (*entities.Venue, error) {
+func (s *venueRepository) UpdateVenue(ctx context.Context, venueID uint, updates map[string]interface{}) (*entities.Venue, error) {
 	var venue entities.Venue
 
-	if err := s.db.WithContext(ctx).First(&venue, venueID).Error; err != nil {
+	if err := conn(ctx, s.db).Scopes(tenantScope(ctx, "venues")).First(&venue, venueID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.NewNotFoundError("Venue not found", errors.ErrRecordNotFound)
 		}
 		return nil, errors.NewInternalError("Failed to fetch venue", err)
 	}
 
-	if err := s.db.WithContext(ctx).Model(&venue).Updates(updates).Error; err != nil {
+	if metadata, ok := updates["metadata"].(entities.Metadata); ok {
+		if err := entities.ValidateMetadata(metadata); err != nil {
+			return nil, errors.NewBadRequestError(err.Error(), err)
+		}
+	}
+
+	if err := conn(ctx, s.db).Model(&venue).Updates(updates).Error; err != nil {
 		return nil, errors.NewInternalError("Failed to update venue", err)
 	}
 
@@ -86,10 +116,10 @@ func (s *VenueRepository) UpdateVenue(ctx context.Context, venueID uint, updates
 }
 
 // DeleteVenue soft deletes a venue (admin only)
-func (s *VenueRepository) DeleteVenue(ctx context.Context, venueID uint) error {
+func (s *venueRepository) DeleteVenue(ctx context.Context, venueID uint) error {
 	var venue entities.Venue
 
-	if err := s.db.WithContext(ctx).First(&venue, venueID).Error; err != nil {
+	if err := conn(ctx, s.db).Scopes(tenantScope(ctx, "venues")).First(&venue, venueID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return errors.NewNotFoundError("Venue not found", errors.ErrRecordNotFound)
 		}
@@ -98,7 +128,7 @@ func (s *VenueRepository) DeleteVenue(ctx context.Context, venueID uint) error {
 
 	// Check if venue has active events
 	var activeEventsCount int64
-	if err := s.db.WithContext(ctx).Model(&entities.Event{}).
+	if err := conn(ctx, s.db).Model(&entities.Event{}).
 		Where("venue_id = ? AND status = ?", venueID, "active").
 		Count(&activeEventsCount).Error; err != nil {
 		return errors.NewInternalError("Failed to check active events", err)
@@ -108,7 +138,7 @@ func (s *VenueRepository) DeleteVenue(ctx context.Context, venueID uint) error {
 		return errors.NewBadRequestError("Cannot delete venue with active events", nil)
 	}
 
-	if err := s.db.WithContext(ctx).Delete(&venue).Error; err != nil {
+	if err := conn(ctx, s.db).Delete(&venue).Error; err != nil {
 		return errors.NewInternalError("Failed to delete venue", err)
 	}
 
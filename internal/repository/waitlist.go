@@ -1,6 +1,8 @@
 package repository
 
 import (
+	redisconn "api/internal/redis"
+	"api/pkg/errors"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -9,8 +11,23 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-type WaitlistRepository struct {
-	redis *redis.Client
+type WaitlistRepository interface {
+	JoinWaitlist(ctx context.Context, userID, eventID uint, tier string, priority, capacity int) (*WaitlistEntry, error)
+	GetWaitlistPosition(ctx context.Context, userID, eventID uint) (*WaitlistEntry, error)
+	RemoveFromWaitlist(ctx context.Context, userID, eventID uint) error
+	GetNextInWaitlist(ctx context.Context, eventID uint) (*WaitlistEntry, error)
+	GetWaitlistHead(ctx context.Context, eventID uint, count int) ([]*WaitlistEntry, error)
+	PopFromWaitlist(ctx context.Context, eventID uint) (*WaitlistEntry, error)
+	GetWaitlistSize(ctx context.Context, eventID uint) (int, error)
+	NotifyWaitlistUsers(ctx context.Context, eventID uint, count int) ([]*WaitlistEntry, error)
+	TakeWaitlistUser(ctx context.Context, eventID uint, skip []uint) (*WaitlistEntry, error)
+	ReturnToWaitlist(ctx context.Context, entry *WaitlistEntry) error
+	CleanupExpiredNotifications(ctx context.Context, eventID uint, notificationTTL time.Duration) error
+	MigrateKeys(ctx context.Context) (int, error)
+}
+
+type waitlistRepository struct {
+	redis redis.UniversalClient
 }
 
 type WaitlistEntry struct {
@@ -19,18 +36,21 @@ type WaitlistEntry struct {
 	JoinedAt  time.Time `json:"joined_at"`
 	Position  int       `json:"position"`
 	NotifiedAt *time.Time `json:"notified_at,omitempty"`
+	Tier      string    `json:"tier,omitempty"`
+	Priority  int       `json:"priority"` // lower is served first, FIFO within a priority
 }
 
-func NewWaitlistRepository(redis *redis.Client) *WaitlistRepository {
-	return &WaitlistRepository{
+func NewWaitlistRepository(redis redis.UniversalClient) WaitlistRepository {
+	return &waitlistRepository{
 		redis: redis,
 	}
 }
 
-// JoinWaitlist adds a user to the event waitlist queue
-func (r *WaitlistRepository) JoinWaitlist(ctx context.Context, userID, eventID uint) (*WaitlistEntry, error) {
-	queueKey := fmt.Sprintf("waitlist:event:%d", eventID)
-	userKey := fmt.Sprintf("waitlist:user:%d:event:%d", userID, eventID)
+// JoinWaitlist adds a user to the event waitlist queue behind every entry of the same or higher priority.
+// A capacity of 0 means the waitlist is unlimited; errors.ErrWaitlistFull is returned once it is reached.
+func (r *waitlistRepository) JoinWaitlist(ctx context.Context, userID, eventID uint, tier string, priority, capacity int) (*WaitlistEntry, error) {
+	queueKey := redisconn.WaitlistQueueKey(eventID)
+	userKey := redisconn.WaitlistUserKey(eventID, userID)
 	
 	// Check if user is already in the waitlist
 	exists, err := r.redis.Exists(ctx, userKey).Result()
@@ -47,51 +67,61 @@ func (r *WaitlistRepository) JoinWaitlist(ctx context.Context, userID, eventID u
 		UserID:   userID,
 		EventID:  eventID,
 		JoinedAt: time.Now(),
+		Tier:     tier,
+		Priority: priority,
 	}
 	
-	// Serialize entry
+	// Serialize entry; the user key holds the exact queued value so it can be removed with LREM
 	entryJSON, err := json.Marshal(entry)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal waitlist entry: %w", err)
 	}
 	
-	// Use Redis pipeline for atomic operations
-	pipe := r.redis.Pipeline()
-	
-	// Add to the end of the queue (FIFO)
-	pipe.RPush(ctx, queueKey, string(entryJSON))
-	
-	// Set user-specific key for quick lookups
-	pipe.Set(ctx, userKey, string(entryJSON), 24*time.Hour) // Expire after 24 hours
-	
-	// Get current queue length to determine position
-	pipe.LLen(ctx, queueKey)
-	
-	results, err := pipe.Exec(ctx)
+	// Lua script to enforce the cap and insert before the first lower-priority entry atomically
+	script := `
+		local size = redis.call("LLEN", KEYS[1])
+		local capacity = tonumber(ARGV[3])
+		if capacity > 0 and size >= capacity then
+			return -1
+		end
+		local priority = tonumber(ARGV[2])
+		local position = size + 1
+		local entries = redis.call("LRANGE", KEYS[1], 0, -1)
+		for i, existing in ipairs(entries) do
+			local ok, decoded = pcall(cjson.decode, existing)
+			if ok and tonumber(decoded.priority or 0) > priority then
+				redis.call("LINSERT", KEYS[1], "BEFORE", existing, ARGV[1])
+				position = i
+				break
+			end
+		end
+		if position == size + 1 then
+			redis.call("RPUSH", KEYS[1], ARGV[1])
+		end
+		redis.call("SET", KEYS[2], ARGV[1], "EX", 86400)
+		return position
+	`
+	
+	position, err := r.redis.Eval(ctx, script, []string{queueKey, userKey}, string(entryJSON), priority, capacity).Int()
 	if err != nil {
 		return nil, fmt.Errorf("failed to join waitlist: %w", err)
 	}
+	if position < 0 {
+		return nil, errors.ErrWaitlistFull
+	}
 	
-	// Get the queue length (position)
-	queueLength := results[2].(*redis.IntCmd).Val()
-	entry.Position = int(queueLength)
-	
-	// Update the entry with position
-	entry.Position = int(queueLength)
-	entryJSON, _ = json.Marshal(entry)
-	r.redis.Set(ctx, userKey, string(entryJSON), 24*time.Hour)
-	
+	entry.Position = position
 	return entry, nil
 }
 
 // GetWaitlistPosition returns the current position of a user in the waitlist
-func (r *WaitlistRepository) GetWaitlistPosition(ctx context.Context, userID, eventID uint) (*WaitlistEntry, error) {
-	userKey := fmt.Sprintf("waitlist:user:%d:event:%d", userID, eventID)
+func (r *waitlistRepository) GetWaitlistPosition(ctx context.Context, userID, eventID uint) (*WaitlistEntry, error) {
+	userKey := redisconn.WaitlistUserKey(eventID, userID)
 	
 	entryJSON, err := r.redis.Get(ctx, userKey).Result()
 	if err != nil {
 		if err == redis.Nil {
-			return nil, fmt.Errorf("user not found in waitlist")
+			return nil, errors.ErrNotInWaitlist
 		}
 		return nil, fmt.Errorf("failed to get waitlist position: %w", err)
 	}
@@ -102,7 +132,7 @@ func (r *WaitlistRepository) GetWaitlistPosition(ctx context.Context, userID, ev
 	}
 	
 	// Recalculate position by finding user in the queue
-	queueKey := fmt.Sprintf("waitlist:event:%d", eventID)
+	queueKey := redisconn.WaitlistQueueKey(eventID)
 	queueEntries, err := r.redis.LRange(ctx, queueKey, 0, -1).Result()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get queue entries: %w", err)
@@ -124,15 +154,15 @@ func (r *WaitlistRepository) GetWaitlistPosition(ctx context.Context, userID, ev
 }
 
 // RemoveFromWaitlist removes a user from the waitlist
-func (r *WaitlistRepository) RemoveFromWaitlist(ctx context.Context, userID, eventID uint) error {
-	queueKey := fmt.Sprintf("waitlist:event:%d", eventID)
-	userKey := fmt.Sprintf("waitlist:user:%d:event:%d", userID, eventID)
+func (r *waitlistRepository) RemoveFromWaitlist(ctx context.Context, userID, eventID uint) error {
+	queueKey := redisconn.WaitlistQueueKey(eventID)
+	userKey := redisconn.WaitlistUserKey(eventID, userID)
 	
 	// Get user's entry first
 	entryJSON, err := r.redis.Get(ctx, userKey).Result()
 	if err != nil {
 		if err == redis.Nil {
-			return fmt.Errorf("user not found in waitlist")
+			return errors.ErrNotInWaitlist
 		}
 		return fmt.Errorf("failed to get user waitlist entry: %w", err)
 	}
@@ -151,8 +181,8 @@ func (r *WaitlistRepository) RemoveFromWaitlist(ctx context.Context, userID, eve
 }
 
 // GetNextInWaitlist gets the next user in line for an event
-func (r *WaitlistRepository) GetNextInWaitlist(ctx context.Context, eventID uint) (*WaitlistEntry, error) {
-	queueKey := fmt.Sprintf("waitlist:event:%d", eventID)
+func (r *waitlistRepository) GetNextInWaitlist(ctx context.Context, eventID uint) (*WaitlistEntry, error) {
+	queueKey := redisconn.WaitlistQueueKey(eventID)
 	
 	// Get the first entry in the queue (FIFO)
 	entryJSON, err := r.redis.LIndex(ctx, queueKey, 0).Result()
@@ -172,9 +202,31 @@ func (r *WaitlistRepository) GetNextInWaitlist(ctx context.Context, eventID uint
 	return &entry, nil
 }
 
+// GetWaitlistHead returns the first count users in line for an event without removing them
+func (r *waitlistRepository) GetWaitlistHead(ctx context.Context, eventID uint, count int) ([]*WaitlistEntry, error) {
+	queueKey := redisconn.WaitlistQueueKey(eventID)
+	
+	entryJSONs, err := r.redis.LRange(ctx, queueKey, 0, int64(count-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get waitlist entries: %w", err)
+	}
+	
+	entries := make([]*WaitlistEntry, 0, len(entryJSONs))
+	for i, entryJSON := range entryJSONs {
+		var entry WaitlistEntry
+		if err := json.Unmarshal([]byte(entryJSON), &entry); err != nil {
+			continue
+		}
+		entry.Position = i + 1
+		entries = append(entries, &entry)
+	}
+	
+	return entries, nil
+}
+
 // PopFromWaitlist removes and returns the first user in the waitlist
-func (r *WaitlistRepository) PopFromWaitlist(ctx context.Context, eventID uint) (*WaitlistEntry, error) {
-	queueKey := fmt.Sprintf("waitlist:event:%d", eventID)
+func (r *waitlistRepository) PopFromWaitlist(ctx context.Context, eventID uint) (*WaitlistEntry, error) {
+	queueKey := redisconn.WaitlistQueueKey(eventID)
 	
 	// Pop the first entry from the queue
 	entryJSON, err := r.redis.LPop(ctx, queueKey).Result()
@@ -191,15 +243,15 @@ func (r *WaitlistRepository) PopFromWaitlist(ctx context.Context, eventID uint)
 	}
 	
 	// Remove user-specific key
-	userKey := fmt.Sprintf("waitlist:user:%d:event:%d", entry.UserID, eventID)
+	userKey := redisconn.WaitlistUserKey(eventID, entry.UserID)
 	r.redis.Del(ctx, userKey)
 	
 	return &entry, nil
 }
 
 // GetWaitlistSize returns the number of people waiting for an event
-func (r *WaitlistRepository) GetWaitlistSize(ctx context.Context, eventID uint) (int, error) {
-	queueKey := fmt.Sprintf("waitlist:event:%d", eventID)
+func (r *waitlistRepository) GetWaitlistSize(ctx context.Context, eventID uint) (int, error) {
+	queueKey := redisconn.WaitlistQueueKey(eventID)
 	
 	size, err := r.redis.LLen(ctx, queueKey).Result()
 	if err != nil {
@@ -209,41 +261,160 @@ func (r *WaitlistRepository) GetWaitlistSize(ctx context.Context, eventID uint)
 	return int(size), nil
 }
 
-// NotifyWaitlistUsers marks users as notified when seats become available
-func (r *WaitlistRepository) NotifyWaitlistUsers(ctx context.Context, eventID uint, count int) ([]*WaitlistEntry, error) {
-	queueKey := fmt.Sprintf("waitlist:event:%d", eventID)
-	
-	// Get the first 'count' entries without removing them
-	entryJSONs, err := r.redis.LRange(ctx, queueKey, 0, int64(count-1)).Result()
+// NotifyWaitlistUsers marks the first count users in line who haven't been notified yet as
+// notified, returning them. The queued entry and the user key are rewritten together, so the
+// user key still holds the exact queued value.
+func (r *waitlistRepository) NotifyWaitlistUsers(ctx context.Context, eventID uint, count int) ([]*WaitlistEntry, error) {
+	queueKey := redisconn.WaitlistQueueKey(eventID)
+
+	entryJSONs, err := r.redis.LRange(ctx, queueKey, 0, -1).Result()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get waitlist entries for notification: %w", err)
 	}
-	
+
+	// Replace the queued value in place; an entry that left the queue meanwhile is skipped
+	script := `
+		local index = redis.call("LPOS", KEYS[1], ARGV[1])
+		if not index then
+			return 0
+		end
+		redis.call("LSET", KEYS[1], index, ARGV[2])
+		redis.call("SET", KEYS[2], ARGV[2], "KEEPTTL")
+		return 1
+	`
+
 	var notifiedUsers []*WaitlistEntry
 	now := time.Now()
-	
-	for _, entryJSON := range entryJSONs {
+
+	for i, entryJSON := range entryJSONs {
+		if len(notifiedUsers) == count {
+			break
+		}
 		var entry WaitlistEntry
-		if err := json.Unmarshal([]byte(entryJSON), &entry); err != nil {
+		if err := json.Unmarshal([]byte(entryJSON), &entry); err != nil || entry.NotifiedAt != nil {
 			continue
 		}
-		
-		// Mark as notified
+
 		entry.NotifiedAt = &now
+		updatedJSON, err := json.Marshal(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal waitlist entry: %w", err)
+		}
+		userKey := redisconn.WaitlistUserKey(eventID, entry.UserID)
+		replaced, err := r.redis.Eval(ctx, script, []string{queueKey, userKey}, entryJSON, string(updatedJSON)).Int()
+		if err != nil {
+			return nil, fmt.Errorf("failed to notify waitlist user: %w", err)
+		}
+		if replaced == 0 {
+			continue
+		}
+
+		entry.Position = i + 1
 		notifiedUsers = append(notifiedUsers, &entry)
-		
-		// Update user-specific key with notification time
-		userKey := fmt.Sprintf("waitlist:user:%d:event:%d", entry.UserID, eventID)
-		updatedJSON, _ := json.Marshal(entry)
-		r.redis.Set(ctx, userKey, string(updatedJSON), 24*time.Hour)
 	}
-	
+
 	return notifiedUsers, nil
 }
 
+// TakeWaitlistUser takes the first user in line for an event who hasn't been notified of a seat
+// and isn't in skip off the waitlist, returning them with the position they had. It returns nil
+// when there is nobody left to take. ReturnToWaitlist puts them back.
+func (r *waitlistRepository) TakeWaitlistUser(ctx context.Context, eventID uint, skip []uint) (*WaitlistEntry, error) {
+	queueKey := redisconn.WaitlistQueueKey(eventID)
+	if skip == nil {
+		skip = []uint{}
+	}
+	skipJSON, err := json.Marshal(skip)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal skipped users: %w", err)
+	}
+
+	// Find and remove the entry in one step, so two takers never get the same user
+	script := `
+		local skip = {}
+		for _, id in ipairs(cjson.decode(ARGV[1])) do
+			skip[tonumber(id)] = true
+		end
+		local entries = redis.call("LRANGE", KEYS[1], 0, -1)
+		for i, existing in ipairs(entries) do
+			local ok, decoded = pcall(cjson.decode, existing)
+			if ok and decoded.notified_at == nil and not skip[tonumber(decoded.user_id)] then
+				redis.call("LREM", KEYS[1], 1, existing)
+				return {existing, i}
+			end
+		end
+		return false
+	`
+	result, err := r.redis.Eval(ctx, script, []string{queueKey}, string(skipJSON)).Slice()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to take waitlist user: %w", err)
+	}
+	if len(result) != 2 {
+		return nil, fmt.Errorf("failed to take waitlist user: unexpected reply %v", result)
+	}
+	entryJSON, _ := result[0].(string)
+	position, _ := result[1].(int64)
+
+	var entry WaitlistEntry
+	if err := json.Unmarshal([]byte(entryJSON), &entry); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal waitlist entry: %w", err)
+	}
+	if err := r.redis.Del(ctx, redisconn.WaitlistUserKey(eventID, entry.UserID)).Err(); err != nil {
+		return nil, fmt.Errorf("failed to take waitlist user: %w", err)
+	}
+	entry.Position = int(position)
+	return &entry, nil
+}
+
+// ReturnToWaitlist puts a user taken with TakeWaitlistUser back in their place: at the position
+// they had, or earlier if lower-priority users are in front of it by now. Users who joined the
+// waitlist again meanwhile keep their new place.
+func (r *waitlistRepository) ReturnToWaitlist(ctx context.Context, entry *WaitlistEntry) error {
+	queueKey := redisconn.WaitlistQueueKey(entry.EventID)
+	userKey := redisconn.WaitlistUserKey(entry.EventID, entry.UserID)
+
+	// The queued value is the entry as it joined, without its position
+	queued := *entry
+	queued.Position = 0
+	entryJSON, err := json.Marshal(queued)
+	if err != nil {
+		return fmt.Errorf("failed to marshal waitlist entry: %w", err)
+	}
+
+	script := `
+		if redis.call("EXISTS", KEYS[2]) == 1 then
+			return 0
+		end
+		local position = tonumber(ARGV[2])
+		local priority = tonumber(ARGV[3])
+		local entries = redis.call("LRANGE", KEYS[1], 0, -1)
+		local inserted = false
+		for i, existing in ipairs(entries) do
+			local ok, decoded = pcall(cjson.decode, existing)
+			if i >= position or (ok and tonumber(decoded.priority or 0) > priority) then
+				redis.call("LINSERT", KEYS[1], "BEFORE", existing, ARGV[1])
+				inserted = true
+				break
+			end
+		end
+		if not inserted then
+			redis.call("RPUSH", KEYS[1], ARGV[1])
+		end
+		redis.call("SET", KEYS[2], ARGV[1], "EX", 86400)
+		return 1
+	`
+	if err := r.redis.Eval(ctx, script, []string{queueKey, userKey}, string(entryJSON), entry.Position, entry.Priority).Err(); err != nil {
+		return fmt.Errorf("failed to return user to waitlist: %w", err)
+	}
+	return nil
+}
+
 // CleanupExpiredNotifications removes users who were notified but didn't book within the time limit
-func (r *WaitlistRepository) CleanupExpiredNotifications(ctx context.Context, eventID uint, notificationTTL time.Duration) error {
-	queueKey := fmt.Sprintf("waitlist:event:%d", eventID)
+func (r *waitlistRepository) CleanupExpiredNotifications(ctx context.Context, eventID uint, notificationTTL time.Duration) error {
+	queueKey := redisconn.WaitlistQueueKey(eventID)
 	
 	// Get all entries in the queue
 	entryJSONs, err := r.redis.LRange(ctx, queueKey, 0, -1).Result()
@@ -261,7 +432,7 @@ func (r *WaitlistRepository) CleanupExpiredNotifications(ctx context.Context, ev
 		
 		// If user was notified and the notification has expired, remove them
 		if entry.NotifiedAt != nil && entry.NotifiedAt.Before(cutoffTime) {
-			userKey := fmt.Sprintf("waitlist:user:%d:event:%d", entry.UserID, eventID)
+			userKey := redisconn.WaitlistUserKey(eventID, entry.UserID)
 			
 			// Remove from both queue and user key
 			pipe := r.redis.Pipeline()
@@ -273,3 +444,40 @@ func (r *WaitlistRepository) CleanupExpiredNotifications(ctx context.Context, ev
 	
 	return nil
 }
+
+// MigrateKeys renames waitlist keys written before they carried the event hash tag
+// (waitlist:event:<id> and waitlist:user:<user>:event:<id>). It only finds keys on a single
+// or sentinel deployment, the only ones that could have written them.
+func (r *waitlistRepository) MigrateKeys(ctx context.Context) (int, error) {
+	renamed := 0
+	rename := func(from, to string) error {
+		ok, err := r.redis.RenameNX(ctx, from, to).Result()
+		if err != nil {
+			return fmt.Errorf("failed to rename waitlist key %s: %w", from, err)
+		}
+		if ok {
+			renamed++
+		}
+		return nil
+	}
+
+	err := redisconn.ScanKeys(ctx, r.redis, "waitlist:event:*", func(key string) error {
+		var eventID uint
+		if _, err := fmt.Sscanf(key, "waitlist:event:%d", &eventID); err != nil {
+			return nil
+		}
+		return rename(key, redisconn.WaitlistQueueKey(eventID))
+	})
+	if err != nil {
+		return renamed, err
+	}
+
+	err = redisconn.ScanKeys(ctx, r.redis, "waitlist:user:*", func(key string) error {
+		var userID, eventID uint
+		if _, err := fmt.Sscanf(key, "waitlist:user:%d:event:%d", &userID, &eventID); err != nil {
+			return nil
+		}
+		return rename(key, redisconn.WaitlistUserKey(eventID, userID))
+	})
+	return renamed, err
+}
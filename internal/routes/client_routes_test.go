@@ -0,0 +1,109 @@
+package routes
+
+import (
+	"api/internal/config"
+	"api/internal/container"
+	"api/internal/middleware"
+	"api/internal/payments"
+	redisconn "api/internal/redis"
+	"api/pkg/client"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// TestClientEndpointsAreRouted calls every method of the hand-written client SDK and checks
+// that each request it sends matches a route the API registers, so a renamed or removed
+// route, or a client method added with a wrong path, fails here.
+func TestClientEndpointsAreRouted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	redisClient := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond, MaxRetries: -1})
+	defer redisClient.Close()
+	health := redisconn.NewHealth(redisClient, time.Minute)
+	allowlist, err := middleware.NewAllowlist(redisClient, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := SetupRoutes(&container.Container{
+		Config:          &config.Config{},
+		RedisHealth:     health,
+		BookingLimiter:  middleware.NewBackpressure("bookings", 1, 1, time.Second),
+		Drain:           middleware.NewDrain(),
+		JWTMiddleware:   middleware.NewJWTMiddleware(nil, nil),
+		RateLimiter:     middleware.NewRateLimiter(redisClient, allowlist, health),
+		Allowlist:       allowlist,
+		WebhookVerifier: middleware.NewWebhookVerifier("secret", time.Minute, redisClient, health),
+		// Served only with PAYMENT_MOCK_ENABLED, which the client's CreateMockCharge needs
+		MockPayments: payments.NewMockProvider(payments.MockConfig{}),
+	})
+
+	var mu sync.Mutex
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		sent = append(sent, req.Method+" "+req.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	c := reflect.ValueOf(client.New(server.URL))
+
+	contextType := reflect.TypeOf((*context.Context)(nil)).Elem()
+	for i := 0; i < c.NumMethod(); i++ {
+		method := c.Type().Method(i)
+		if method.Type.NumIn() < 2 || method.Type.In(1) != contextType {
+			continue
+		}
+		sent = nil
+		args := []reflect.Value{reflect.ValueOf(context.Background())}
+		for j := 2; j < method.Type.NumIn(); j++ {
+			args = append(args, reflect.Zero(method.Type.In(j)))
+		}
+		// Empty responses don't decode into every result; only the requests matter here
+		c.Method(i).Call(args)
+
+		if len(sent) == 0 {
+			t.Errorf("%s sent no request", method.Name)
+		}
+		for _, request := range sent {
+			if !routed(r.Routes(), request) {
+				t.Errorf("%s sent %s, which no route serves", method.Name, request)
+			}
+		}
+	}
+}
+
+// routed reports whether a "METHOD /path" request matches one of the routes
+func routed(routes gin.RoutesInfo, request string) bool {
+	method, path, _ := strings.Cut(request, " ")
+	segments := strings.Split(path, "/")
+	for _, route := range routes {
+		if route.Method != method {
+			continue
+		}
+		pattern := strings.Split(route.Path, "/")
+		if len(pattern) != len(segments) {
+			continue
+		}
+		matches := true
+		for i := range pattern {
+			if pattern[i] != segments[i] && !strings.HasPrefix(pattern[i], ":") {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
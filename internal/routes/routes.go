@@ -3,45 +3,110 @@ package routes
 import (
 	"api/internal/container"
 	"api/internal/handlers"
+	"api/internal/metrics"
 	"api/internal/middleware"
+	logger "api/pkg/logging"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// newEngine returns the router, taking client IPs from X-Forwarded-For only on requests from the
+// trusted proxies. Rate limit exemptions and sale regions go by the client IP, so any other
+// client could claim an address of its choosing.
+func newEngine(trustedProxies []string) (*gin.Engine, error) {
+	r := gin.Default()
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
 func SetupRoutes(deps *container.Container) *gin.Engine {
-	userHandler := handlers.NewUserHandler(deps.UserService, deps.JWTService)
-	eventHandler := handlers.NewEventHandler(deps.EventService, deps.VenueService)
+	userHandler := handlers.NewUserHandler(deps.UserService, deps.JWTService, deps.PolicyService)
+	policyHandler := handlers.NewPolicyHandler(deps.PolicyService)
+	accountHandler := handlers.NewAccountHandler(deps.AccountService)
+	eventHandler := handlers.NewEventHandler(deps.EventService, deps.VenueService).
+		WithPosts(deps.EventPosts).
+		WithEnrichment(deps.Enrichment)
+	eventPostHandler := handlers.NewEventPostHandler(deps.EventPosts)
 	venueHandler := handlers.NewVenueHandler(deps.VenueService)
-	bookingHandler := handlers.NewBookingHandler(deps.BookingService)
+	bookingHandler := handlers.NewBookingHandler(deps.BookingService).WithTickets(deps.TicketService)
+	ticketHandler := handlers.NewTicketHandler(deps.TicketService)
+	scannerHandler := handlers.NewScannerHandler(deps.ScannerService)
+	autocompleteHandler := handlers.NewAutocompleteHandler(deps.Autocomplete)
 	analyticsHandler := handlers.NewAnalyticsHandler(deps.AnalyticsService)
 	waitlistHandler := handlers.NewWaitlistHandler(deps.WaitlistService)
+	queueHandler := handlers.NewQueueHandler(deps.QueueService)
+	importHandler := handlers.NewImportHandler(deps.ImportService)
+	attendanceHandler := handlers.NewAttendanceHandler(deps.AttendanceService)
+	disputeHandler := handlers.NewDisputeHandler(deps.DisputeService)
+	supportHandler := handlers.NewSupportHandler(deps.SupportService)
+	paymentHandler := handlers.NewPaymentHandler(deps.PaymentService)
+	loyaltyHandler := handlers.NewLoyaltyHandler(deps.LoyaltyService)
+	presaleHandler := handlers.NewPresaleHandler(deps.PresaleService)
+	saleRegionHandler := handlers.NewSaleRegionHandler(deps.SaleRegionService)
+	artifactHandler := handlers.NewArtifactHandler(deps.ArtifactService, deps.Storage)
+	rateLimitHandler := handlers.NewRateLimitHandler(deps.RateLimiter, deps.Allowlist, deps.BookingLimiter)
+	tenantHandler := handlers.NewTenantHandler(deps.TenantService)
+	taskHandler := handlers.NewTaskHandler(deps.TaskService)
+	feedHandler := handlers.NewFeedHandler(deps.FeedService, deps.Config.SiteURL)
+	referralHandler := handlers.NewReferralHandler(deps.ReferralService)
+	boxOfficeHandler := handlers.NewBoxOfficeHandler(deps.BoxOfficeService)
+	settlementHandler := handlers.NewSettlementHandler(deps.SettlementService)
+	ledgerHandler := handlers.NewLedgerHandler(deps.LedgerService)
+	archiveHandler := handlers.NewArchiveHandler(deps.ArchiveService)
+	lockDivergenceHandler := handlers.NewLockDivergenceHandler(deps.LockDivergence)
+	deadLetterHandler := handlers.NewDeadLetterHandler(deps.DeadLetters)
+	settingsHandler := handlers.NewSettingsHandler(deps.SettingsService)
+	catalogSyncHandler := handlers.NewCatalogSyncHandler(deps.CatalogSync)
+	seatBitmapHandler := handlers.NewSeatBitmapHandler(deps.SeatBitmaps)
+	metricsHandler := handlers.NewMetricsHandler(metrics.Default)
+	healthHandler := handlers.NewHealthHandler(deps.RedisHealth, deps.Drain)
 
-	r := gin.Default()
+	r, err := newEngine(deps.Config.TrustedProxies)
+	if err != nil {
+		logger.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 	// CORS middleware
 	r.Use(middleware.CORSMiddleware())
+	// flag responses served without Redis so clients can show a banner
+	r.Use(middleware.DegradedMode(deps.RedisHealth))
+	// refuse new bookings and other changes once shutdown starts, letting running ones finish
+	r.Use(deps.Drain.RejectMutations())
 
 	// global rate limiting - 1000 requests per minute per IP
 	r.Use(deps.RateLimiter.RateLimit(1000, time.Minute))
 
-	// heath check endpoint
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status": "ok",
-		})
-	})
+	// heath check endpoint, reporting degraded while Redis is unavailable
+	r.GET("/health", healthHandler.GetHealth)
+
+	// Prometheus metrics, only served when a scrape token is configured
+	if deps.Config.MetricsToken != "" {
+		r.GET("/metrics", middleware.MetricsAuth(deps.Config.MetricsToken), metricsHandler.GetMetrics)
+	}
+
+	// Sitemap of storefront pages for search engines
+	r.GET("/sitemap.xml", feedHandler.GetSitemap)
 
 	// Public API routes
 	api := r.Group("/api")
 	{
+		// Service status, with the degraded mode banner
+		api.GET("/status", healthHandler.GetStatus)
+
 		// Authentication
 		auth := api.Group("/")
 		auth.Use(deps.RateLimiter.RateLimit(10, time.Minute)) // 10 auth attempts per minute
 		{
 			auth.POST("/register", userHandler.Register)
 			auth.POST("/login", userHandler.Login)
+			auth.POST("/account/restore", accountHandler.RestoreAccount)
 		}
 
+		// Terms of service and privacy policy versions to accept
+		api.GET("/policies", policyHandler.GetPolicies)
+
 		// Events
 		events := api.Group("/events")
 		events.Use(deps.RateLimiter.RateLimit(200, time.Minute)) // 200 requests per minute
@@ -49,6 +114,8 @@ func SetupRoutes(deps *container.Container) *gin.Engine {
 			events.GET("", eventHandler.GetEvents)
 			events.GET("/:id", eventHandler.GetEventByID)
 			events.GET("/:id/seats", eventHandler.GetAvailableSeats)
+			// Compact seat availability for stadium-scale seat maps
+			events.GET("/:id/seats/bitmap", seatBitmapHandler.GetBitmap)
 		}
 
 		// Venues
@@ -58,29 +125,109 @@ func SetupRoutes(deps *container.Container) *gin.Engine {
 			venues.GET("", venueHandler.GetVenues)
 			venues.GET("/:id", venueHandler.GetVenueByID)
 		}
+
+		// Search-as-you-type suggestions, a request per keystroke
+		autocomplete := api.Group("/")
+		autocomplete.Use(deps.RateLimiter.RateLimit(600, time.Minute)) // 600 requests per minute
+		{
+			autocomplete.GET("/venues/autocomplete", autocompleteHandler.SuggestVenues)
+			autocomplete.GET("/cities/autocomplete", autocompleteHandler.SuggestCities)
+		}
+
+		// Organizer landing pages, by tenant ID or storefront slug
+		organizers := api.Group("/organizers")
+		organizers.Use(deps.RateLimiter.RateLimit(200, time.Minute)) // 200 requests per minute
+		{
+			organizers.GET("/:id", tenantHandler.GetOrganizerPage)
+		}
+
+		// Feeds of newly published events for syndication partners
+		feeds := api.Group("/feeds")
+		feeds.Use(deps.RateLimiter.RateLimit(200, time.Minute)) // 200 requests per minute
+		{
+			feeds.GET("/events.atom", feedHandler.GetAtomFeed)
+			feeds.GET("/events.rss", feedHandler.GetRSSFeed)
+		}
+
+		// Public keys door scanners validate ticket QR codes with while offline
+		api.GET("/tickets/keys", ticketHandler.GetKeys)
+
+		// Door scanners, authenticated with the device token they were registered with
+		scanner := api.Group("/scanner")
+		scanner.Use(deps.RateLimiter.RateLimit(600, time.Minute)) // 600 scans per minute, a venue's scanners often share an IP
+		scanner.Use(middleware.ScannerAuth(deps.ScannerService))
+		{
+			scanner.POST("/check-ins", scannerHandler.CheckIn)
+			scanner.POST("/check-ins/sync", scannerHandler.SyncCheckIns)
+		}
+
+		// Signed downloads for artifacts in local storage
+		api.GET("/files/*key", artifactHandler.Download)
+
+		// Payment provider webhooks (HMAC signed)
+		webhooks := api.Group("/webhooks")
+		webhooks.Use(deps.WebhookVerifier.VerifySignature())
+		{
+			webhooks.POST("/payments/disputes", disputeHandler.HandleDisputeWebhook)
+		}
+
+		// Built-in mock payment provider, only served in dev and E2E setups
+		if deps.MockPayments != nil {
+			mockPaymentHandler := handlers.NewMockPaymentHandler(deps.MockPayments)
+			api.POST("/mock-payments/charges", mockPaymentHandler.CreateCharge)
+		}
+	}
+
+	// Authenticated routes reachable before accepting new policy versions, so users can review
+	// and accept them, or leave
+	account := api.Group("/")
+	account.Use(deps.JWTMiddleware.AuthRequired())
+	account.Use(deps.RateLimiter.UserRateLimit(100, time.Minute)) // 100 requests per user per minute
+	{
+		account.POST("/policies/accept", policyHandler.AcceptPolicies)
+		account.GET("/profile/policies", policyHandler.ListAcceptances)
+		account.DELETE("/profile", accountHandler.DeleteAccount)
+		// The caller's rate limit usage, so clients can throttle themselves
+		account.GET("/limits", rateLimitHandler.GetLimits)
 	}
 
-	// Protected API routes
+	// Protected API routes, for users who accepted the current policy versions
 	protected := api.Group("/")
-	protected.Use(deps.JWTMiddleware.AuthRequired())
+	protected.Use(deps.JWTMiddleware.AuthRequired(), middleware.PolicyGate(deps.PolicyService))
 	{
 		// User profile
 		profile := protected.Group("/")
 		profile.Use(deps.RateLimiter.UserRateLimit(100, time.Minute)) // 100 requests per user per minute
 		{
 			profile.GET("/profile", userHandler.GetProfile)
+			profile.PUT("/profile/password", userHandler.ChangePassword)
+			profile.GET("/loyalty", loyaltyHandler.GetSummary)
+			profile.GET("/loyalty/transactions", loyaltyHandler.ListTransactions)
+			profile.GET("/referrals", referralHandler.GetDashboard)
+			profile.POST("/referrals/code", referralHandler.GetCode)
 		}
 
 		// Booking management
 		bookings := protected.Group("/")
-		bookings.Use(deps.RateLimiter.UserRateLimit(50, time.Minute)) // 50 booking ops per user per minute
+		// 50 booking ops per user per minute; during on-sales 150 for users admitted from the queue, 20 for everyone else
+		bookings.Use(deps.RateLimiter.QueueRateLimit(50, time.Minute, middleware.QueuePolicy{Admitted: 150, Browsing: 20}, deps.QueueService.QueueToken))
 		{
-			bookings.POST("/booking-intents", bookingHandler.CreateBookingIntent)
-			bookings.POST("/bookings/confirm", bookingHandler.ConfirmBooking)
+			// The heaviest booking operations are capped so spikes can't exhaust the DB pool
+			// Clients are located for events only sold in some countries
+			bookings.POST("/booking-intents", middleware.GeoIP(deps.GeoIP), deps.BookingLimiter.Limit(), bookingHandler.CreateBookingIntent)
+			bookings.POST("/bookings/confirm", deps.BookingLimiter.Limit(), bookingHandler.ConfirmBooking)
 			bookings.POST("/booking-intents/cancel", bookingHandler.CancelBookingIntent)
+			bookings.POST("/booking-intents/:id/heartbeat", bookingHandler.HeartbeatBookingIntent)
+			bookings.GET("/booking-intents/:id/status", bookingHandler.GetBookingIntentStatus)
+			bookings.POST("/booking-intents/:id/retry-payment", bookingHandler.RetryPayment)
+			bookings.POST("/booking-intents/:id/resume-token", bookingHandler.IssueResumeToken)
+			bookings.POST("/booking-intents/resume", bookingHandler.ResumeBookingIntent)
 			bookings.DELETE("/bookings/:id", bookingHandler.CancelBooking)
 			bookings.GET("/bookings", bookingHandler.GetUserBookings)
+			bookings.GET("/bookings/upcoming", bookingHandler.GetUpcomingBookings)
 			bookings.GET("/bookings/:id", bookingHandler.GetBookingByID)
+			bookings.GET("/bookings/:id/ticket", bookingHandler.GetTicket)
+			bookings.POST("/bookings/:id/review", attendanceHandler.SubmitReview)
 		}
 
 		// Waitlist management
@@ -92,29 +239,179 @@ func SetupRoutes(deps *container.Container) *gin.Engine {
 			waitlist.DELETE("/events/:eventId/leave", waitlistHandler.LeaveWaitlist)
 			waitlist.GET("/events/:eventId/stats", waitlistHandler.GetWaitlistStats)
 		}
+
+		// On-sale queue of high-demand events
+		queue := protected.Group("/queue")
+		queue.Use(deps.RateLimiter.UserRateLimit(60, time.Minute)) // 60 queue ops per user per minute, status is polled
+		{
+			queue.POST("/events/:eventId/join", queueHandler.JoinQueue)
+			queue.GET("/events/:eventId/status", queueHandler.GetQueueStatus)
+			queue.DELETE("/events/:eventId/leave", queueHandler.LeaveQueue)
+			queue.GET("/events/:eventId/length", queueHandler.GetQueueLength)
+		}
+
+		// Support cases on bookings
+		support := protected.Group("/")
+		support.Use(deps.RateLimiter.UserRateLimit(30, time.Minute)) // 30 support ops per user per minute
+		{
+			support.POST("/bookings/:id/support-cases", supportHandler.OpenCase)
+			support.GET("/support-cases", supportHandler.ListUserCases)
+			support.GET("/support-cases/:id", supportHandler.GetUserCase)
+		}
 	}
 
 	// Admin only routes
 	admin := protected.Group("/admin")
 	admin.Use(deps.JWTMiddleware.AdminRequired())
-	admin.Use(deps.RateLimiter.UserRateLimit(200, time.Minute)) // 200 admin ops per minute
+	admin.Use(deps.RateLimiter.UserRateLimit(200, time.Minute))                                  // 200 admin ops per minute
+	admin.Use(deps.RateLimiter.TenantRateLimit(1000, time.Minute, deps.TenantService.RateLimit)) // shared by a tenant's admins
 	{
-		// User management
-		admin.GET("/users", userHandler.ListUsers)
-
 		// Venue management
 		admin.POST("/venues", venueHandler.CreateVenue)
 		admin.PUT("/venues/:id", venueHandler.UpdateVenue)
 		admin.DELETE("/venues/:id", venueHandler.DeleteVenue)
+		admin.GET("/venues/:id/utilization", analyticsHandler.GetVenueUtilization)
+		admin.GET("/venues/:id/heatmap", analyticsHandler.GetVenueHeatmap)
 
 		// Event management
 		admin.POST("/events", eventHandler.CreateEvent)
 		admin.PUT("/events/:id", eventHandler.UpdateEvent)
 		admin.DELETE("/events/:id", eventHandler.DeleteEvent)
+		// Rehearse an event's on-sale on a sandbox copy with its own seats
+		admin.POST("/events/:id/sandbox", eventHandler.CreateSandbox)
 		admin.GET("/events/:id/stats", eventHandler.GetEventStats)
+		admin.POST("/events/:id/releases", eventHandler.ReleaseSeats)
+		admin.GET("/events/:id/releases", eventHandler.ListReleases)
+		admin.PUT("/events/:id/seat-prices", eventHandler.UpdateSeatPrices)
+		// Announcements and FAQs shown on the event page
+		admin.POST("/events/:id/posts", eventPostHandler.CreatePost)
+		admin.PUT("/event-posts/:id", eventPostHandler.UpdatePost)
+		admin.DELETE("/event-posts/:id", eventPostHandler.DeletePost)
+		// Preload events into the cache before a big on-sale
+		admin.POST("/cache/warm", eventHandler.WarmCache)
+		admin.PUT("/seats/:id/accessibility", eventHandler.SetSeatAccessibility)
+		admin.GET("/seats/:id/price-history", eventHandler.GetSeatPriceHistory)
+		admin.GET("/seats/:id/lock", bookingHandler.InspectSeatLock) // who holds a seat, for support
+		admin.POST("/events/:id/intents/cleanup", bookingHandler.PurgeEventIntents)
+		admin.POST("/events/:id/presale-codes", presaleHandler.CreateBatch)
+		admin.GET("/events/:id/presale-codes", presaleHandler.ListBatches)
+		admin.GET("/presale-batches/:id", presaleHandler.GetBatch)
+		// Users who may book from outside an event's sale countries
+		admin.GET("/events/:id/sale-region-overrides", saleRegionHandler.ListOverrides)
+		admin.POST("/events/:id/sale-region-overrides", saleRegionHandler.AddOverride)
+		admin.DELETE("/events/:id/sale-region-overrides/:userId", saleRegionHandler.RemoveOverride)
+
+		// Attendance
+		admin.POST("/bookings/:id/check-in", attendanceHandler.CheckIn)
+		// Scans made offline, uploaded once the scanner is back online
+		admin.POST("/events/:id/check-ins/sync", ticketHandler.SyncCheckIns)
+		admin.POST("/events/:id/scanners", scannerHandler.RegisterDevice)
+		admin.GET("/events/:id/scanners", scannerHandler.ListDevices)
+		admin.DELETE("/scanners/:id", scannerHandler.RevokeDevice)
+		admin.GET("/events/:id/gates/stats", scannerHandler.GetGateStats) // entry rates by gate during doors-open
+		admin.POST("/events/:id/door-sales", boxOfficeHandler.SellAtDoor) // box office, open until the event ends
+		admin.POST("/events/:id/comps", boxOfficeHandler.IssueComps)
+		admin.GET("/events/:id/comps", boxOfficeHandler.ListComps)
+
+		// Payment disputes
+		admin.GET("/disputes", disputeHandler.ListDisputes)
+
+		// Support cases opened by users on their bookings
+		admin.GET("/support-cases", supportHandler.ListCases)
+		admin.GET("/support-cases/:id", supportHandler.GetCase)
+		admin.POST("/support-cases/:id/responses", supportHandler.Respond)
+
+		// Payment reconciliation
+		admin.GET("/payments", paymentHandler.ListTransactions)
+		admin.GET("/payments/:id", paymentHandler.GetTransaction)
 
 		// Analytics
 		admin.GET("/analytics/bookings", analyticsHandler.GetBookingAnalytics)
+		admin.GET("/analytics/waitlist-conversion", analyticsHandler.GetWaitlistConversion)
+		admin.GET("/analytics/acquisition", analyticsHandler.GetAcquisitionReport)
+		// Double-entry ledger of sales, refunds and fees for finance
+		admin.GET("/ledger/balances", ledgerHandler.GetBalances)
+		// Real-time on-sale counters for war-room monitoring
+		admin.GET("/events/:id/live", analyticsHandler.GetLiveEventStats)
+
+		// Background tasks such as event seat generation
+		admin.GET("/tasks", taskHandler.ListTasks)
+		admin.GET("/tasks/:id", taskHandler.GetTask)
+
+		// CSV imports (pass ?dry_run=true to get a diff without applying)
+		admin.POST("/imports/venues", importHandler.ImportVenues)
+		admin.POST("/imports/events", importHandler.ImportEvents)
+
+		// Organizer branding (tenant admins can only manage their own tenant)
+		admin.GET("/organizers/:id/profile", tenantHandler.GetOrganizerProfile)
+		admin.PUT("/organizers/:id/profile", tenantHandler.UpdateOrganizerProfile)
+	}
+
+	// Platform admin routes, not available to admins scoped to a tenant
+	platform := admin.Group("")
+	platform.Use(deps.JWTMiddleware.PlatformAdminRequired())
+	{
+		// User management
+		platform.GET("/users", userHandler.ListUsers)
+		platform.PUT("/users/:id/membership-tier", userHandler.SetMembershipTier)
+		platform.PUT("/users/:id/tenant", tenantHandler.AssignAdmin)
+
+		// Tenants
+		platform.POST("/tenants", tenantHandler.CreateTenant)
+		platform.GET("/tenants", tenantHandler.ListTenants)
+		platform.PUT("/tenants/:id", tenantHandler.UpdateTenant)
+
+		// Generated artifacts
+		platform.GET("/artifacts", artifactHandler.ListArtifacts)
+		platform.GET("/artifacts/:id/download", artifactHandler.GetDownloadURL)
+		platform.DELETE("/artifacts/:id", artifactHandler.DeleteArtifact)
+
+		// Rate limit exemptions (DELETE takes ?type=ip|user&value=)
+		platform.GET("/rate-limit/allowlist", rateLimitHandler.ListAllowlist)
+		platform.POST("/rate-limit/allowlist", rateLimitHandler.AddToAllowlist)
+		platform.DELETE("/rate-limit/allowlist", rateLimitHandler.RemoveFromAllowlist)
+		platform.GET("/rate-limit/load", rateLimitHandler.GetLoad)
+
+		// Seats whose database and Redis locks disagree
+		platform.GET("/seat-locks/divergences", lockDivergenceHandler.GetReport)
+		platform.POST("/seat-locks/divergences/check", lockDivergenceHandler.RunCheck)
+
+		// Failed notifications, admin webhook posts and domain event deliveries
+		platform.GET("/dead-letters", deadLetterHandler.ListDeadLetters)
+		platform.GET("/dead-letters/:id", deadLetterHandler.GetDeadLetter)
+		platform.POST("/dead-letters/:id/retry", deadLetterHandler.RetryDeadLetter)
+		platform.POST("/dead-letters/:id/discard", deadLetterHandler.DiscardDeadLetter)
+
+		// Runtime settings and their audit log
+		platform.GET("/settings", settingsHandler.ListSettings)
+		platform.GET("/settings/changes", settingsHandler.ListSettingChanges)
+		platform.PUT("/settings/:key", settingsHandler.UpdateSetting)
+		platform.DELETE("/settings/:key", settingsHandler.ResetSetting)
+
+		// Partner catalog feed sync
+		platform.GET("/catalog/sync", catalogSyncHandler.GetStatus)
+		platform.POST("/catalog/sync", catalogSyncHandler.RunSync)
+
+		// Booking archival
+		platform.POST("/archive/bookings", archiveHandler.ArchiveBookings)
+
+		// Referral program
+		platform.POST("/referrers", referralHandler.CreateReferrer)
+		platform.GET("/referrers", referralHandler.ListReferrers)
+		platform.PUT("/referrers/:id", referralHandler.UpdateReferrer)
+		platform.GET("/referrals/payouts", referralHandler.GetPayouts)
+		platform.POST("/referrers/:id/payouts", referralHandler.PayOut)
+	}
+
+	// Organizer reports, for the admins of an event's tenant
+	organizer := protected.Group("/organizer")
+	organizer.Use(deps.JWTMiddleware.AdminRequired())
+	organizer.Use(deps.RateLimiter.UserRateLimit(200, time.Minute))
+	organizer.Use(deps.RateLimiter.TenantRateLimit(1000, time.Minute, deps.TenantService.RateLimit))
+	{
+		// Settlements are generated on every request until frozen after the event completes
+		organizer.GET("/events/:id/settlement", settlementHandler.GetSettlement)
+		organizer.POST("/events/:id/settlement/freeze", settlementHandler.FreezeSettlement)
 	}
 
 	return r
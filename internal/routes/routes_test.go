@@ -0,0 +1,62 @@
+package routes
+
+import (
+	"api/internal/middleware"
+	redisconn "api/internal/redis"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// TestForwardedForOnlyFromTrustedProxies checks that a client can't skip rate limiting by
+// claiming an allowlisted address in X-Forwarded-For, while a trusted proxy can pass it on.
+// Redis is unreachable, so requests are counted in memory.
+func TestForwardedForOnlyFromTrustedProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond, MaxRetries: -1})
+	defer client.Close()
+	allowlist, err := middleware.NewAllowlist(client, "192.0.2.10", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rl := middleware.NewRateLimiter(client, allowlist, redisconn.NewHealth(client, time.Minute))
+
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		want           []int
+	}{
+		{"no trusted proxies", nil, "203.0.113.7:4000", []int{http.StatusOK, http.StatusTooManyRequests}},
+		{"untrusted peer", []string{"10.0.0.0/8"}, "203.0.113.8:4000", []int{http.StatusOK, http.StatusTooManyRequests}},
+		{"trusted proxy", []string{"10.0.0.0/8"}, "10.1.2.3:4000", []int{http.StatusOK, http.StatusOK}},
+	}
+	for _, tt := range tests {
+		r, err := newEngine(tt.trustedProxies)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Use(rl.RateLimit(1, time.Minute))
+		r.GET("/events", func(c *gin.Context) {})
+
+		for i, want := range tt.want {
+			req := httptest.NewRequest(http.MethodGet, "/events", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", "192.0.2.10")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != want {
+				t.Errorf("%s: request %d returned %d, want %d", tt.name, i+1, w.Code, want)
+			}
+		}
+	}
+
+	if _, err := newEngine([]string{"not-an-ip"}); err == nil {
+		t.Error("newEngine accepted an invalid trusted proxy")
+	}
+}
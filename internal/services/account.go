@@ -0,0 +1,111 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/notifications"
+	"api/internal/repository"
+	"api/pkg/errors"
+	logger "api/pkg/logging"
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// anonymizationBatch caps the accounts anonymized per query of a run
+const anonymizationBatch = 100
+
+// AccountService deletes accounts with a cooling-off period. Deleting an account blocks it right
+// away and mails a restore link; accounts not restored within the grace period are anonymized.
+type AccountService struct {
+	repo     *repository.AccountRepository
+	users    *UserService
+	bookings *BookingService
+	notifier notifications.Notifier
+	grace    time.Duration
+	siteURL  string // the storefront serving the restore page
+	now      func() time.Time
+}
+
+// Ensure AccountService implements AccountServiceInterface
+var _ AccountServiceInterface = (*AccountService)(nil)
+
+func NewAccountService(repo *repository.AccountRepository, users *UserService, bookings *BookingService, notifier notifications.Notifier, grace time.Duration, siteURL string) *AccountService {
+	return &AccountService{
+		repo:     repo,
+		users:    users,
+		bookings: bookings,
+		notifier: notifier,
+		grace:    grace,
+		siteURL:  siteURL,
+		now:      time.Now,
+	}
+}
+
+// DeleteAccount schedules the user's account for anonymization after the grace period. The
+// account can't log in meanwhile, its tokens are revoked and its pending intents cancelled.
+func (s *AccountService) DeleteAccount(ctx context.Context, userID uint) (*entities.User, error) {
+	token, err := newResumeToken()
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to generate restore token", err)
+	}
+
+	user, err := s.repo.ScheduleDeletion(ctx, userID, s.now().Add(s.grace), hashResumeToken(token))
+	if err != nil {
+		return nil, err
+	}
+	s.users.ForgetTokenVersion(userID)
+
+	// The account is blocked already; intents left pending expire with their locks anyway
+	if cancelled, err := s.bookings.CancelUserIntents(ctx, userID); err != nil {
+		logger.Warnf("Failed to cancel pending intents of deleted user %d: %v", userID, err)
+	} else if cancelled > 0 {
+		logger.Infof("Cancelled %d pending intents of deleted user %d", cancelled, userID)
+	}
+
+	if err := s.notifier.Send(ctx, notifications.Notification{
+		Type:      constants.NotificationTypeAccountDeletion,
+		UserID:    user.ID,
+		Recipient: user.Email,
+		Subject:   "Your account will be deleted",
+		Message: fmt.Sprintf("Your account will be deleted on %s. To keep it, open %s before then.",
+			user.DeletionScheduledAt.Format("2 January 2006"), s.restoreLink(token)),
+	}); err != nil {
+		logger.Warnf("Failed to send restore link to user %d: %v", userID, err)
+	}
+	return user, nil
+}
+
+// RestoreAccount cancels the deletion of the account a restore link was sent for. The user
+// logs in again afterwards, since the tokens revoked by the deletion stay revoked.
+func (s *AccountService) RestoreAccount(ctx context.Context, token string) (*entities.User, error) {
+	return s.repo.Restore(ctx, hashResumeToken(token), s.now())
+}
+
+// AnonymizeDueAccounts erases the personal data of the accounts whose grace period ended
+func (s *AccountService) AnonymizeDueAccounts(ctx context.Context) error {
+	for {
+		now := s.now()
+		userIDs, err := s.repo.GetDueDeletions(ctx, now, anonymizationBatch)
+		if err != nil {
+			return err
+		}
+		for _, userID := range userIDs {
+			anonymized, err := s.repo.Anonymize(ctx, userID, now)
+			if err != nil {
+				return err
+			}
+			if anonymized {
+				logger.Infof("Anonymized deleted account %d", userID)
+			}
+		}
+		if len(userIDs) < anonymizationBatch {
+			return nil
+		}
+	}
+}
+
+func (s *AccountService) restoreLink(token string) string {
+	return s.siteURL + "/account/restore?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"api/internal/domain"
+	"api/internal/notifications"
+	"api/internal/repository"
+	logger "api/pkg/logging"
+	"api/pkg/money"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AdminNotifications tells the admins' team channel about operational events: events selling
+// out, large refunds, reconciliation discrepancies and saturated on-sale queues. Delivery is
+// best effort and never fails the operation it reports on. A nil *AdminNotifications drops
+// everything, so services can hold one unconditionally.
+type AdminNotifications struct {
+	channel      notifications.AdminChannel
+	availability *repository.AvailabilityRepository
+	largeRefund  money.Money // refunds of at least this amount are reported, 0 disables them
+	now          func() time.Time
+}
+
+func NewAdminNotifications(channel notifications.AdminChannel, availability *repository.AvailabilityRepository, largeRefund money.Money) *AdminNotifications {
+	return &AdminNotifications{
+		channel:      channel,
+		availability: availability,
+		largeRefund:  largeRefund,
+		now:          time.Now,
+	}
+}
+
+// Subscribe follows the booking workflow for sell-outs and refunds
+func (n *AdminNotifications) Subscribe(events *domain.Dispatcher) {
+	domain.Subscribe(events, "admin_notifications", n.bookingConfirmed)
+	domain.Subscribe(events, "admin_notifications", n.bookingCancelled)
+}
+
+// bookingConfirmed reports the confirmation that sold an event's last seat
+func (n *AdminNotifications) bookingConfirmed(ctx context.Context, event domain.BookingConfirmed) error {
+	if n.availability == nil {
+		return nil
+	}
+	e, err := n.availability.GetEventAvailability(ctx, event.EventID)
+	if err != nil {
+		return err
+	}
+	if e.AvailableSeats > 0 {
+		return nil
+	}
+	n.notify(ctx, notifications.AdminEvent{
+		Kind:    notifications.AdminEventSoldOut,
+		EventID: event.EventID,
+		Title:   fmt.Sprintf("%s is sold out", e.Name),
+		Message: fmt.Sprintf("Booking %d sold the last seat of event %d.", event.BookingID, event.EventID),
+	})
+	return nil
+}
+
+func (n *AdminNotifications) bookingCancelled(ctx context.Context, event domain.BookingCancelled) error {
+	if n.largeRefund <= 0 || event.RefundAmount < n.largeRefund {
+		return nil
+	}
+	n.notify(ctx, notifications.AdminEvent{
+		Kind:    notifications.AdminEventLargeRefund,
+		EventID: event.EventID,
+		Title:   fmt.Sprintf("Refund of %s issued", event.RefundAmount),
+		Message: fmt.Sprintf("Booking %d of event %d was cancelled by user %d and refunded %s.",
+			event.BookingID, event.EventID, event.UserID, event.RefundAmount),
+	})
+	return nil
+}
+
+// LockDivergences reports seats whose database and Redis locks newly disagree past the grace
+// period, counted by kind
+func (n *AdminNotifications) LockDivergences(ctx context.Context, kinds map[string]int, repaired bool) {
+	if n == nil || len(kinds) == 0 {
+		return
+	}
+	names := make([]string, 0, len(kinds))
+	for kind := range kinds {
+		names = append(names, kind)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, kind := range names {
+		parts[i] = fmt.Sprintf("%d %s", kinds[kind], kind)
+	}
+
+	outcome := "They were reported only."
+	if repaired {
+		outcome = "The repairable ones were repaired from the database."
+	}
+	n.notify(ctx, notifications.AdminEvent{
+		Kind:    notifications.AdminEventReconciliationDiscrepancy,
+		Title:   "Seat locks diverged between the database and Redis",
+		Message: fmt.Sprintf("The lock reconciliation found %s. %s", strings.Join(parts, ", "), outcome),
+	})
+}
+
+// QueueSaturated reports an on-sale queue reaching the saturation threshold
+func (n *AdminNotifications) QueueSaturated(ctx context.Context, eventID uint, waiting int) {
+	if n == nil {
+		return
+	}
+	n.notify(ctx, notifications.AdminEvent{
+		Kind:    notifications.AdminEventQueueSaturated,
+		EventID: eventID,
+		Title:   fmt.Sprintf("On-sale queue of event %d is saturated", eventID),
+		Message: fmt.Sprintf("%d users are waiting in the queue of event %d.", waiting, eventID),
+	})
+}
+
+func (n *AdminNotifications) notify(ctx context.Context, event notifications.AdminEvent) {
+	event.At = n.now()
+	if err := n.channel.Notify(ctx, event); err != nil {
+		logger.Warnf("Failed to notify admins of %s: %v", event.Kind, err)
+	}
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"api/internal/alerting"
+	redisconn "api/internal/redis"
+	"api/internal/repository"
+	logger "api/pkg/logging"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Alert rules
+const (
+	AlertBookingFailureRate = "booking_failure_rate"
+	AlertLockContention     = "lock_contention"
+	AlertPaymentErrorRate   = "payment_error_rate"
+	AlertDeadLetterGrowth   = "dead_letter_growth"
+)
+
+// maxAlertWindowMinutes is how far back the live counters behind the alerts are kept
+const maxAlertWindowMinutes = 60
+
+// AlertThresholds are the limits the booking metrics are alerted on
+type AlertThresholds struct {
+	Window     time.Duration // the metrics are measured over the last Window, rounded to minutes
+	MinSamples int64         // attempts a window needs before its rates are judged
+
+	BookingFailureRate     float64 // percent of booking attempts failing with internal errors
+	LockConflictsPerMinute float64 // intents refused per minute because the seat was locked
+	PaymentErrorRate       float64 // percent of online payments failing
+	DeadLetters            float64 // async work failing into the dead letter queue over the window
+}
+
+// AlertMonitor periodically measures booking failure rates, lock contention, payment error
+// ratios and dead letter queue growth across all events and alerts when they cross their thresholds. A rule alerts once when
+// it starts firing and again when it resolves, not on every evaluation in between.
+type AlertMonitor struct {
+	liveStats   *repository.LiveStatsRepository
+	paymentRepo *repository.PaymentRepository
+	deadLetters *repository.DeadLetterRepository
+	health      *redisconn.Health
+	channel     alerting.Channel
+	thresholds  AlertThresholds
+	now         func() time.Time
+
+	mu     sync.Mutex // held for a whole evaluation, guarding firing
+	firing map[string]bool
+}
+
+func NewAlertMonitor(liveStats *repository.LiveStatsRepository, paymentRepo *repository.PaymentRepository, deadLetters *repository.DeadLetterRepository, health *redisconn.Health, channel alerting.Channel, thresholds AlertThresholds) *AlertMonitor {
+	return &AlertMonitor{
+		liveStats:   liveStats,
+		paymentRepo: paymentRepo,
+		deadLetters: deadLetters,
+		health:      health,
+		channel:     channel,
+		thresholds:  thresholds,
+		now:         time.Now,
+		firing:      make(map[string]bool),
+	}
+}
+
+// Evaluate measures the metrics over the window and sends the alerts of rules starting or
+// ceasing to fire. The booking metrics come from the live Redis counters and aren't judged
+// while Redis is unavailable; payment errors are still.
+func (m *AlertMonitor) Evaluate(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	minutes := int(m.thresholds.Window / time.Minute)
+	minutes = min(max(minutes, 1), maxAlertWindowMinutes)
+	now := m.now()
+
+	if m.health == nil || !m.health.Degraded() {
+		if err := m.evaluateBookings(ctx, minutes, now); err != nil && !redisconn.IsUnavailable(err) {
+			return err
+		}
+	}
+
+	since := now.Add(-time.Duration(minutes) * time.Minute)
+	failed, paid, err := m.paymentRepo.CountPaymentOutcomes(ctx, since)
+	if err != nil {
+		return err
+	}
+	total := failed + paid
+	rate := 0.0
+	if total >= m.thresholds.MinSamples && total > 0 {
+		rate = percent(failed, total)
+	}
+	m.judge(ctx, AlertPaymentErrorRate, alerting.SeverityCritical, rate, m.thresholds.PaymentErrorRate, now,
+		fmt.Sprintf("%.1f%% of online payments failed in the last %d minutes (%d of %d)", rate, minutes, failed, total))
+
+	dead, err := m.deadLetters.CountSince(ctx, since)
+	if err != nil {
+		return err
+	}
+	m.judge(ctx, AlertDeadLetterGrowth, alerting.SeverityWarning, float64(dead), m.thresholds.DeadLetters, now,
+		fmt.Sprintf("%d pieces of async work failed into the dead letter queue in the last %d minutes", dead, minutes))
+	return nil
+}
+
+func (m *AlertMonitor) evaluateBookings(ctx context.Context, minutes int, now time.Time) error {
+	stats, err := m.liveStats.GetPlatformMinutes(ctx, minutes)
+	if err != nil {
+		return err
+	}
+
+	var attempts, errored, conflicts int64
+	for _, minute := range stats {
+		attempts += minute.IntentsCreated + minute.IntentsRejected + minute.Confirmations + minute.ConfirmationsRejected + minute.Errors
+		errored += minute.Errors
+		conflicts += minute.LockConflicts
+	}
+
+	rate := 0.0
+	if attempts >= m.thresholds.MinSamples && attempts > 0 {
+		rate = percent(errored, attempts)
+	}
+	m.judge(ctx, AlertBookingFailureRate, alerting.SeverityCritical, rate, m.thresholds.BookingFailureRate, now,
+		fmt.Sprintf("%.1f%% of booking attempts failed with internal errors in the last %d minutes (%d of %d)", rate, minutes, errored, attempts))
+
+	perMinute := float64(conflicts) / float64(minutes)
+	m.judge(ctx, AlertLockContention, alerting.SeverityWarning, perMinute, m.thresholds.LockConflictsPerMinute, now,
+		fmt.Sprintf("%.0f intents per minute were refused on locked seats in the last %d minutes", perMinute, minutes))
+	return nil
+}
+
+// judge alerts when a rule's value crosses its threshold, and when it goes back under it. A
+// threshold of 0 disables the rule. Alerts that can't be delivered are retried on the next
+// evaluation.
+func (m *AlertMonitor) judge(ctx context.Context, rule, severity string, value, threshold float64, now time.Time, summary string) {
+	if threshold <= 0 {
+		return
+	}
+	firing := value >= threshold
+	if firing == m.firing[rule] {
+		return
+	}
+
+	alert := alerting.Alert{
+		Rule:      rule,
+		Severity:  severity,
+		Summary:   summary,
+		Value:     value,
+		Threshold: threshold,
+		Resolved:  !firing,
+		At:        now,
+	}
+	if err := m.channel.Send(ctx, alert); err != nil {
+		logger.Errorf("Failed to send %s alert: %v", rule, err)
+		return
+	}
+	m.firing[rule] = firing
+}
+
+func percent(part, total int64) float64 {
+	return float64(part) / float64(total) * 100
+}
@@ -1,28 +1,43 @@
 package services
 
 import (
+	"api/internal/domain"
 	"api/internal/entities"
+	redisconn "api/internal/redis"
 	"api/internal/repository"
+	"api/pkg/errors"
+	"context"
+	"time"
 )
 
 type AnalyticsServiceInterface interface {
-	GetBookingAnalytics() (*entities.BookingAnalytics, error)
+	GetBookingAnalytics(ctx context.Context) (*entities.BookingAnalytics, error)
+	GetLiveEventStats(ctx context.Context, eventID uint, windowMinutes int) (*entities.LiveEventStats, error)
+	GetWaitlistConversion(ctx context.Context, eventID uint, limit int) ([]entities.WaitlistConversion, error)
+	GetAcquisitionReport(ctx context.Context, filter entities.AcquisitionFilter) (*entities.AcquisitionReport, error)
+	GetVenueUtilization(ctx context.Context, venueID uint, from, to time.Time) (*entities.VenueUtilization, error)
+	GetVenueHeatmap(ctx context.Context, venueID uint, from, to time.Time) (*entities.SeatHeatmap, error)
 }
 
 type analyticsService struct {
 	analyticsRepo repository.AnalyticsRepository
+	liveStats     *repository.LiveStatsRepository
+	waitlistRepo  repository.WaitlistRepository
 }
 
-func NewAnalyticsService(analyticsRepo repository.AnalyticsRepository) AnalyticsServiceInterface {
+func NewAnalyticsService(analyticsRepo repository.AnalyticsRepository, liveStats *repository.LiveStatsRepository, waitlistRepo repository.WaitlistRepository) AnalyticsServiceInterface {
 	return &analyticsService{
 		analyticsRepo: analyticsRepo,
+		liveStats:     liveStats,
+		waitlistRepo:  waitlistRepo,
 	}
 }
 
-// GetBookingAnalytics returns comprehensive booking analytics for admin dashboard
-func (s *analyticsService) GetBookingAnalytics() (*entities.BookingAnalytics, error) {
+// GetBookingAnalytics returns comprehensive booking analytics for admin dashboard, limited to the
+// admin's tenant for tenant admins
+func (s *analyticsService) GetBookingAnalytics(ctx context.Context) (*entities.BookingAnalytics, error) {
 	// Get total booking counts
-	confirmedCount, cancelledCount, err := s.analyticsRepo.GetTotalBookingCounts()
+	confirmedCount, cancelledCount, err := s.analyticsRepo.GetTotalBookingCounts(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -35,31 +50,31 @@ func (s *analyticsService) GetBookingAnalytics() (*entities.BookingAnalytics, er
 	}
 
 	// Get total revenue
-	totalRevenue, err := s.analyticsRepo.GetTotalRevenue()
+	totalRevenue, err := s.analyticsRepo.GetTotalRevenue(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get most popular events (by total bookings)
-	popularEventsData, err := s.analyticsRepo.GetMostPopularEvents(10)
+	popularEventsData, err := s.analyticsRepo.GetMostPopularEvents(ctx, 10)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get most booked events (by confirmed bookings)
-	bookedEventsData, err := s.analyticsRepo.GetMostBookedEvents(10)
+	bookedEventsData, err := s.analyticsRepo.GetMostBookedEvents(ctx, 10)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get capacity utilization
-	capacityData, err := s.analyticsRepo.GetCapacityUtilization()
+	capacityData, err := s.analyticsRepo.GetCapacityUtilization(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get daily booking stats for last 30 days
-	dailyStatsData, err := s.analyticsRepo.GetDailyBookingStats(30)
+	dailyStatsData, err := s.analyticsRepo.GetDailyBookingStats(ctx, 30)
 	if err != nil {
 		return nil, err
 	}
@@ -80,6 +95,202 @@ func (s *analyticsService) GetBookingAnalytics() (*entities.BookingAnalytics, er
 	return analytics, nil
 }
 
+// GetWaitlistConversion reports how each event's waitlist converted into bookings, for sizing
+// future venues: eventID selects one event, 0 the limit most recent events with a waitlist
+func (s *analyticsService) GetWaitlistConversion(ctx context.Context, eventID uint, limit int) ([]entities.WaitlistConversion, error) {
+	conversions, err := s.analyticsRepo.GetWaitlistConversion(ctx, eventID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range conversions {
+		conversion := &conversions[i]
+		if conversion.Promoted > 0 {
+			conversion.ConversionRate = float64(conversion.PurchasedAfterPromotion) / float64(conversion.Promoted) * 100
+		}
+		if conversion.Waitlisted > 0 {
+			conversion.PurchaseRate = float64(conversion.Purchased) / float64(conversion.Waitlisted) * 100
+		}
+		conversion.UnmetDemand = conversion.Waitlisted - conversion.Purchased
+	}
+	return conversions, nil
+}
+
+// GetAcquisitionReport breaks bookings and revenue down by acquisition channel, with each
+// channel's share of the total
+func (s *analyticsService) GetAcquisitionReport(ctx context.Context, filter entities.AcquisitionFilter) (*entities.AcquisitionReport, error) {
+	channels, err := s.analyticsRepo.GetAcquisitionChannels(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	bookings, revenue, err := s.analyticsRepo.GetAcquisitionTotals(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range channels {
+		channel := &channels[i]
+		if bookings > 0 {
+			channel.BookingShare = float64(channel.Bookings) / float64(bookings) * 100
+		}
+		if revenue > 0 {
+			channel.RevenueShare = float64(channel.Revenue) / float64(revenue) * 100
+		}
+	}
+	if channels == nil {
+		channels = []entities.AcquisitionChannel{}
+	}
+	return &entities.AcquisitionReport{GroupBy: filter.GroupBy, Bookings: bookings, Revenue: revenue, Channels: channels}, nil
+}
+
+// GetVenueUtilization reports how a venue's events sold per month, for the months from the
+// month of from up to the one before to
+func (s *analyticsService) GetVenueUtilization(ctx context.Context, venueID uint, from, to time.Time) (*entities.VenueUtilization, error) {
+	venue, err := s.analyticsRepo.GetReportVenue(ctx, venueID)
+	if err != nil {
+		return nil, err
+	}
+	months, err := s.analyticsRepo.GetVenueMonths(ctx, venueID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entities.VenueUtilization{
+		VenueID:   venue.ID,
+		VenueName: venue.Name,
+		From:      from.Format("2006-01"),
+		To:        to.AddDate(0, -1, 0).Format("2006-01"),
+		Months:    months,
+	}
+	var totalSeats, bookedSeats int64
+	for i := range months {
+		month := &months[i]
+		if month.TotalSeats > 0 {
+			month.BookedPercent = float64(month.BookedSeats) / float64(month.TotalSeats) * 100
+		}
+		report.Events += month.Events
+		report.Revenue += month.Revenue
+		totalSeats += month.TotalSeats
+		bookedSeats += month.BookedSeats
+	}
+	if totalSeats > 0 {
+		report.BookedPercent = float64(bookedSeats) / float64(totalSeats) * 100
+	}
+	if report.Months == nil {
+		report.Months = []entities.VenueMonth{}
+	}
+	return report, nil
+}
+
+// GetVenueHeatmap reports the sell-through rate and average price of each seat position of a
+// venue across its events that started from the month of from up to the one before to
+func (s *analyticsService) GetVenueHeatmap(ctx context.Context, venueID uint, from, to time.Time) (*entities.SeatHeatmap, error) {
+	venue, err := s.analyticsRepo.GetReportVenue(ctx, venueID)
+	if err != nil {
+		return nil, err
+	}
+	seats, events, err := s.analyticsRepo.GetSeatHeat(ctx, venueID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range seats {
+		if seats[i].Offered > 0 {
+			seats[i].SellThrough = float64(seats[i].Sold) / float64(seats[i].Offered) * 100
+		}
+	}
+	if seats == nil {
+		seats = []entities.SeatHeat{}
+	}
+	return &entities.SeatHeatmap{
+		VenueID:   venue.ID,
+		VenueName: venue.Name,
+		Rows:      venue.Rows,
+		Columns:   venue.Columns,
+		From:      from.Format("2006-01"),
+		To:        to.AddDate(0, -1, 0).Format("2006-01"),
+		Events:    events,
+		Seats:     seats,
+	}, nil
+}
+
+// GetLiveEventStats returns an event's real-time on-sale counters. Everything but the pending
+// intent count comes from Redis; while it is unavailable those read as zero and the stats are
+// flagged degraded rather than failing the dashboard.
+func (s *analyticsService) GetLiveEventStats(ctx context.Context, eventID uint, windowMinutes int) (*entities.LiveEventStats, error) {
+	event, err := s.analyticsRepo.GetLiveEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.analyticsRepo.CountPendingIntents(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &entities.LiveEventStats{
+		EventID:        event.ID,
+		EventName:      event.Name,
+		GeneratedAt:    time.Now(),
+		IntentsPending: pending,
+		WindowMinutes:  windowMinutes,
+		Minutes:        []entities.LiveMinuteStats{},
+	}
+
+	// redisErr keeps the live stats when Redis is unavailable, so they can be reported degraded
+	redisErr := func(err error) error {
+		if redisconn.IsUnavailable(err) {
+			stats.Degraded = true
+			return nil
+		}
+		return errors.NewInternalError("Failed to read live counters", err)
+	}
+
+	if stats.LocksHeld, err = s.liveStats.CountLocksHeld(ctx, eventID); err != nil {
+		if err := redisErr(err); err != nil {
+			return nil, err
+		}
+	}
+	if stats.QueueLength, err = s.waitlistRepo.GetWaitlistSize(ctx, eventID); err != nil {
+		if err := redisErr(err); err != nil {
+			return nil, err
+		}
+	}
+	minutes, err := s.liveStats.GetMinutes(ctx, eventID, windowMinutes)
+	if err != nil {
+		if err := redisErr(err); err != nil {
+			return nil, err
+		}
+		return stats, nil
+	}
+	stats.Minutes = minutes
+
+	var created, confirmed, rejected, failed int64
+	for _, m := range minutes {
+		created += m.IntentsCreated
+		confirmed += m.Confirmations
+		rejected += m.IntentsRejected + m.ConfirmationsRejected
+		failed += m.Errors
+	}
+	stats.IntentsPerMinute = float64(created) / float64(windowMinutes)
+	stats.ConfirmationsPerMinute = float64(confirmed) / float64(windowMinutes)
+	if attempts := created + confirmed + rejected + failed; attempts > 0 {
+		stats.RejectionRate = float64(rejected) / float64(attempts) * 100
+		stats.ErrorRate = float64(failed) / float64(attempts) * 100
+	}
+
+	return stats, nil
+}
+
+// SubscribeLiveStats counts the seats the booking workflow releases on the live on-sale
+// dashboard
+func SubscribeLiveStats(events *domain.Dispatcher, liveStats *repository.LiveStatsRepository) {
+	domain.Subscribe(events, "live_stats", func(ctx context.Context, event domain.SeatReleased) error {
+		liveStats.Record(ctx, event.EventID, repository.LiveSeatsReleased)
+		return nil
+	})
+}
+
 // Helper functions to convert database results to response format
 
 func convertToPopularEvents(data []entities.EventBookingStats) []entities.PopularEvent {
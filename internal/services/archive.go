@@ -0,0 +1,76 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/repository"
+	"api/internal/tasks"
+	"api/pkg/errors"
+	logger "api/pkg/logging"
+	"context"
+	"time"
+)
+
+type ArchiveService struct {
+	archiveRepo *repository.ArchiveRepository
+	taskQueue   *tasks.Queue
+	afterMonths int
+}
+
+// archivalPayload is the input of an archival task
+type archivalPayload struct {
+	EndedBefore time.Time `json:"ended_before"`
+}
+
+// Ensure ArchiveService implements ArchiveServiceInterface
+var _ ArchiveServiceInterface = (*ArchiveService)(nil)
+
+func NewArchiveService(archiveRepo *repository.ArchiveRepository, taskQueue *tasks.Queue, afterMonths int) *ArchiveService {
+	return &ArchiveService{archiveRepo: archiveRepo, taskQueue: taskQueue, afterMonths: afterMonths}
+}
+
+// StartArchival queues a task that archives the bookings and intents of events completed more
+// than olderThanMonths ago, or the configured number of months when it is 0
+func (s *ArchiveService) StartArchival(ctx context.Context, olderThanMonths int, requestedBy uint) (*entities.Task, error) {
+	if olderThanMonths == 0 {
+		olderThanMonths = s.afterMonths
+	}
+	if olderThanMonths < 1 {
+		return nil, errors.NewBadRequestError("Archival age must be at least one month", nil)
+	}
+
+	task := &entities.Task{
+		Kind:      constants.TaskKindArchival,
+		CreatedBy: requestedBy,
+	}
+	payload := archivalPayload{EndedBefore: time.Now().AddDate(0, -olderThanMonths, 0)}
+	if err := s.taskQueue.Enqueue(ctx, task, payload); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// RunArchival is the task handler that archives events one at a time. Each event is archived
+// in its own transaction, so a retried task carries on with the events still left.
+func (s *ArchiveService) RunArchival(ctx context.Context, task *entities.Task, progress tasks.ProgressFunc) (*uint, error) {
+	var payload archivalPayload
+	if err := tasks.DecodePayload(task, &payload); err != nil {
+		return nil, err
+	}
+
+	eventIDs, err := s.archiveRepo.ListArchivableEvents(ctx, payload.EndedBefore)
+	if err != nil {
+		return nil, err
+	}
+	progress(0, len(eventIDs))
+
+	for i, eventID := range eventIDs {
+		bookings, intents, err := s.archiveRepo.ArchiveEvent(ctx, eventID)
+		if err != nil {
+			return nil, err
+		}
+		logger.Infof("Archived event %d: %d bookings, %d booking intents", eventID, bookings, intents)
+		progress(i+1, len(eventIDs))
+	}
+	return nil, nil
+}
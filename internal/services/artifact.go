@@ -0,0 +1,140 @@
+package services
+
+import (
+	"api/internal/entities"
+	"api/internal/repository"
+	"api/internal/storage"
+	"api/pkg/errors"
+	logger "api/pkg/logging"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// artifactCleanupBatch is the number of expired artifacts deleted per cleanup pass
+const artifactCleanupBatch = 100
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+type ArtifactService struct {
+	artifactRepo *repository.ArtifactRepository
+	storage      storage.Storage
+	urlTTL       time.Duration
+	retention    time.Duration
+}
+
+// Ensure ArtifactService implements ArtifactServiceInterface
+var _ ArtifactServiceInterface = (*ArtifactService)(nil)
+
+// NewArtifactService stores artifacts in the given backend. Download URLs stay valid for
+// urlTTL and artifacts are deleted after retention (0 keeps them until deleted explicitly).
+func NewArtifactService(artifactRepo *repository.ArtifactRepository, store storage.Storage, urlTTL, retention time.Duration) *ArtifactService {
+	return &ArtifactService{
+		artifactRepo: artifactRepo,
+		storage:      store,
+		urlTTL:       urlTTL,
+		retention:    retention,
+	}
+}
+
+// Save uploads a generated file and records it so it can be downloaded and cleaned up later
+func (s *ArtifactService) Save(ctx context.Context, kind, fileName, contentType string, data []byte) (*entities.Artifact, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, errors.NewInternalError("Failed to generate artifact key", err)
+	}
+
+	now := time.Now()
+	fileName = unsafeFileNameChars.ReplaceAllString(fileName, "_")
+	artifact := &entities.Artifact{
+		Kind:        kind,
+		Key:         fmt.Sprintf("%s/%s/%s-%s", kind, now.Format("2006/01/02"), hex.EncodeToString(suffix), fileName),
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+	}
+	if s.retention > 0 {
+		expiresAt := now.Add(s.retention)
+		artifact.ExpiresAt = &expiresAt
+	}
+
+	if err := s.storage.Put(ctx, artifact.Key, bytes.NewReader(data), artifact.Size, contentType); err != nil {
+		return nil, errors.NewInternalError("Failed to store artifact", err)
+	}
+	if err := s.artifactRepo.Create(ctx, artifact); err != nil {
+		// Don't leave an untracked object behind that cleanup would never find
+		if delErr := s.storage.Delete(ctx, artifact.Key); delErr != nil {
+			logger.Warnf("Failed to remove orphaned artifact %s: %v", artifact.Key, delErr)
+		}
+		return nil, err
+	}
+	return artifact, nil
+}
+
+func (s *ArtifactService) GetArtifact(ctx context.Context, id uint) (*entities.Artifact, error) {
+	return s.artifactRepo.GetByID(ctx, id)
+}
+
+func (s *ArtifactService) ListArtifacts(ctx context.Context, kind string, limit, offset int) ([]entities.Artifact, int64, error) {
+	return s.artifactRepo.List(ctx, kind, limit, offset)
+}
+
+// DownloadURL returns a pre-signed URL for the artifact and when it stops working
+func (s *ArtifactService) DownloadURL(ctx context.Context, id uint) (string, time.Time, error) {
+	artifact, err := s.artifactRepo.GetByID(ctx, id)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if artifact.ExpiresAt != nil && !artifact.ExpiresAt.After(time.Now()) {
+		return "", time.Time{}, errors.NewNotFoundError("Artifact has expired", errors.ErrRecordNotFound)
+	}
+
+	expiresAt := time.Now().Add(s.urlTTL)
+	url, err := s.storage.PresignGet(ctx, artifact.Key, s.urlTTL)
+	if err != nil {
+		return "", time.Time{}, errors.NewInternalError("Failed to generate download URL", err)
+	}
+	return url, expiresAt, nil
+}
+
+// DeleteArtifact removes an artifact from storage and forgets it
+func (s *ArtifactService) DeleteArtifact(ctx context.Context, id uint) error {
+	artifact, err := s.artifactRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.storage.Delete(ctx, artifact.Key); err != nil {
+		return errors.NewInternalError("Failed to delete stored artifact", err)
+	}
+	return s.artifactRepo.Delete(ctx, artifact.ID)
+}
+
+// CleanupExpired deletes expired artifacts from storage, one batch per run. Records are
+// only removed once the object is gone, so failed deletions are retried on the next run.
+func (s *ArtifactService) CleanupExpired(ctx context.Context) error {
+	artifacts, err := s.artifactRepo.ListExpired(ctx, time.Now(), artifactCleanupBatch)
+	if err != nil {
+		return err
+	}
+
+	deleted := 0
+	for _, artifact := range artifacts {
+		if err := s.storage.Delete(ctx, artifact.Key); err != nil {
+			logger.Errorf("Failed to delete expired artifact %s: %v", artifact.Key, err)
+			continue
+		}
+		if err := s.artifactRepo.Delete(ctx, artifact.ID); err != nil {
+			return err
+		}
+		deleted++
+	}
+
+	if deleted > 0 {
+		logger.Infof("Deleted %d expired artifacts", deleted)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/domain"
+	"api/internal/entities"
+	"api/internal/notifications"
+	"api/internal/repository"
+	logger "api/pkg/logging"
+	"api/pkg/money"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type AttendanceService struct {
+	attendanceRepo   *repository.AttendanceRepository
+	notifier         notifications.Notifier
+	events           *domain.Dispatcher
+	feedbackRequests bool
+}
+
+// Ensure AttendanceService implements AttendanceServiceInterface
+var _ AttendanceServiceInterface = (*AttendanceService)(nil)
+
+func NewAttendanceService(attendanceRepo *repository.AttendanceRepository, notifier notifications.Notifier, feedbackRequests bool) *AttendanceService {
+	return &AttendanceService{
+		attendanceRepo:   attendanceRepo,
+		notifier:         notifier,
+		feedbackRequests: feedbackRequests,
+	}
+}
+
+// WithEvents publishes the seats released from no-shows to the booking workflow's dispatcher,
+// so seat maps and holders hear about them
+func (s *AttendanceService) WithEvents(events *domain.Dispatcher) *AttendanceService {
+	s.events = events
+	return s
+}
+
+// CheckIn marks a booking as attended
+func (s *AttendanceService) CheckIn(ctx context.Context, bookingID uint) (*entities.Booking, error) {
+	return s.attendanceRepo.CheckInBooking(ctx, bookingID)
+}
+
+// SubmitReview rates an event the user attended; rating again replaces the earlier review
+func (s *AttendanceService) SubmitReview(ctx context.Context, userID, bookingID uint, rating int, comment string) (*entities.EventReview, error) {
+	return s.attendanceRepo.SaveReview(ctx, userID, bookingID, rating, strings.TrimSpace(comment))
+}
+
+// ProcessCompletedEvents completes ended events, records no-shows and, when enabled,
+// asks checked-in attendees for feedback. Each event is followed up exactly once.
+func (s *AttendanceService) ProcessCompletedEvents(ctx context.Context) error {
+	events, err := s.attendanceRepo.GetEventsPendingFollowUp(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		noShows, completed, err := s.attendanceRepo.CompleteEvent(ctx, event.ID)
+		if err != nil {
+			logger.Errorf("Failed to complete event %d: %v", event.ID, err)
+			continue
+		}
+		if !completed {
+			continue
+		}
+		logger.Infof("Event %d completed with %d no-shows", event.ID, noShows)
+
+		if s.feedbackRequests {
+			s.sendFeedbackRequests(ctx, &event)
+		}
+	}
+
+	return nil
+}
+
+// ReleaseNoShows puts the seats of bookings not checked in by their event's no-show cutoff back
+// on sale at the door, once per event. The cutoff waits for online sales to close.
+func (s *AttendanceService) ReleaseNoShows(ctx context.Context) error {
+	now := time.Now()
+	events, err := s.attendanceRepo.GetEventsDueNoShowRelease(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		releaseAt, ok := event.NoShowReleaseAt()
+		if !ok || now.Before(releaseAt) {
+			continue
+		}
+
+		released, claimed, err := s.attendanceRepo.ReleaseNoShows(ctx, event.ID, event.NoShowReleaseRefund, now)
+		if err != nil {
+			logger.Errorf("Failed to release no-shows of event %d: %v", event.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		logger.Infof("Released %d no-show seats of event %d to door sales", len(released), event.ID)
+
+		published := make([]domain.Event, 0, 2*len(released))
+		for _, b := range released {
+			var refund money.Money
+			if event.NoShowReleaseRefund && b.PaymentStatus == constants.PaymentStatusPaid {
+				refund = b.TotalAmount
+			}
+			published = append(published,
+				domain.NoShowReleased{BookingID: b.ID, UserID: b.UserID, EventID: b.EventID, SeatID: b.SeatID, RefundAmount: refund, OccurredAt: now},
+				domain.SeatReleased{EventID: b.EventID, SeatID: b.SeatID, UserID: b.UserID, Reason: domain.ReleaseNoShow, OccurredAt: now})
+		}
+		s.events.Publish(ctx, published...)
+	}
+
+	return nil
+}
+
+func (s *AttendanceService) sendFeedbackRequests(ctx context.Context, event *entities.Event) {
+	bookings, err := s.attendanceRepo.GetCheckedInBookings(ctx, event.ID)
+	if err != nil {
+		logger.Errorf("Failed to load attendees for event %d: %v", event.ID, err)
+		return
+	}
+
+	for _, booking := range bookings {
+		notification := notifications.Notification{
+			Type:      constants.NotificationTypeFeedbackRequest,
+			UserID:    booking.UserID,
+			Recipient: booking.User.Email,
+			EventID:   event.ID,
+			BookingID: booking.ID,
+			Subject:   fmt.Sprintf("How was %s?", event.Name),
+			Message:   fmt.Sprintf("Thanks for attending %s. We'd love to hear your feedback.", event.Name),
+		}
+		if err := s.notifier.Send(ctx, notification); err != nil {
+			logger.Warnf("Failed to send feedback request for booking %d: %v", booking.ID, err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/repository"
+	"context"
+	"strings"
+)
+
+// AutocompleteService suggests venues and cities as users type in search boxes
+type AutocompleteService struct {
+	autocompleteRepo *repository.AutocompleteRepository
+}
+
+func NewAutocompleteService(autocompleteRepo *repository.AutocompleteRepository) *AutocompleteService {
+	return &AutocompleteService{autocompleteRepo: autocompleteRepo}
+}
+
+// SuggestVenues returns up to limit venues matching what the user typed, best matches first
+func (s *AutocompleteService) SuggestVenues(ctx context.Context, query string, limit int) ([]entities.VenueSuggestion, error) {
+	query, ok := normalizeQuery(query)
+	if !ok {
+		return []entities.VenueSuggestion{}, nil
+	}
+	return s.autocompleteRepo.SuggestVenues(ctx, query, limit)
+}
+
+// SuggestCities returns up to limit cities matching what the user typed, best matches first
+func (s *AutocompleteService) SuggestCities(ctx context.Context, query string, limit int) ([]entities.CitySuggestion, error) {
+	query, ok := normalizeQuery(query)
+	if !ok {
+		return []entities.CitySuggestion{}, nil
+	}
+	return s.autocompleteRepo.SuggestCities(ctx, query, limit)
+}
+
+// normalizeQuery lowercases a query and collapses its whitespace, so "  New  york" and
+// "new york" share cached suggestions. It returns false if the query is too short to suggest.
+func normalizeQuery(query string) (string, bool) {
+	query = strings.ToLower(strings.Join(strings.Fields(query), " "))
+	return query, len([]rune(query)) >= constants.AutocompleteMinQueryLength
+}
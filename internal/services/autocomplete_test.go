@@ -0,0 +1,25 @@
+package services
+
+import "testing"
+
+// TestNormalizeQuery shares suggestions between spellings of a query that only differ in case
+// and whitespace, and skips queries too short to suggest
+func TestNormalizeQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+		ok    bool
+	}{
+		{"  New   York ", "new york", true},
+		{"Mad", "mad", true},
+		{"M", "m", false},
+		{"   ", "", false},
+		{"Zü", "zü", true},
+	}
+	for _, tt := range tests {
+		got, ok := normalizeQuery(tt.query)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("normalizeQuery(%q) = %q, %v, want %q, %v", tt.query, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"api/internal/domain"
+	"api/internal/entities"
+	"api/internal/repository"
+	logger "api/pkg/logging"
+	"context"
+)
+
+// AvailabilityCounters serves event listings' available seat counts from per-event Redis
+// counters, decremented as seats are sold and incremented as bookings are cancelled. Sales
+// that don't go through the booking workflow, such as door sales, comps and release waves,
+// are picked up by the periodic reconciliation with events.available_seats.
+type AvailabilityCounters struct {
+	repo *repository.AvailabilityRepository
+}
+
+func NewAvailabilityCounters(repo *repository.AvailabilityRepository) *AvailabilityCounters {
+	return &AvailabilityCounters{repo: repo}
+}
+
+// Subscribe keeps the counters in step with confirmations and cancellations. Oversell places
+// aren't counted as seats for sale.
+func (c *AvailabilityCounters) Subscribe(events *domain.Dispatcher) {
+	domain.Subscribe(events, "availability_counters", func(ctx context.Context, event domain.BookingConfirmed) error {
+		if !event.Oversell {
+			c.repo.Adjust(ctx, event.EventID, -1)
+		}
+		return nil
+	})
+	domain.Subscribe(events, "availability_counters", func(ctx context.Context, event domain.BookingCancelled) error {
+		if !event.Oversell {
+			c.repo.Adjust(ctx, event.EventID, 1)
+		}
+		return nil
+	})
+}
+
+// Available returns the events' counts of seats for sale. Events without a counter are counted
+// from their available_seats, which seeds the counter, as are all of them if Redis can't be read.
+func (c *AvailabilityCounters) Available(ctx context.Context, events []entities.Event) map[uint]int64 {
+	eventIDs := make([]uint, len(events))
+	for i := range events {
+		eventIDs[i] = events[i].ID
+	}
+
+	counts, err := c.repo.Get(ctx, eventIDs)
+	if err != nil {
+		logger.Debugf("Availability counters unavailable: %v", err)
+		counts = make(map[uint]int64, len(events))
+	}
+
+	missing := make(map[uint]int64)
+	for i := range events {
+		if _, ok := counts[events[i].ID]; !ok {
+			counts[events[i].ID] = int64(events[i].AvailableSeats)
+			missing[events[i].ID] = int64(events[i].AvailableSeats)
+		}
+	}
+	if err == nil && len(missing) > 0 {
+		if err := c.repo.Seed(ctx, missing); err != nil {
+			logger.Debugf("Availability counters not seeded: %v", err)
+		}
+	}
+	return counts
+}
+
+// Reconcile resets every counter of an event on sale to its available_seats, for the scheduler
+func (c *AvailabilityCounters) Reconcile(ctx context.Context) error {
+	drifted, err := c.repo.Reconcile(ctx)
+	if err != nil {
+		return err
+	}
+	for eventID, count := range drifted {
+		logger.Infof("Availability counter of event %d corrected from %d", eventID, count)
+	}
+	return nil
+}
@@ -1,53 +1,863 @@
 package services
 
 import (
+	"api/constants"
+	"api/internal/domain"
 	"api/internal/entities"
+	redisconn "api/internal/redis"
 	"api/internal/repository"
+	"api/pkg/errors"
+	logger "api/pkg/logging"
+	"api/pkg/money"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// BookingPolicy holds the limits of the booking workflow
+type BookingPolicy struct {
+	// LockDuration is how long a booking intent holds its seat
+	LockDuration time.Duration
+	// MaxPendingIntents stops one user from locking many seats at once; 0 is unlimited
+	MaxPendingIntents int
+	// FreeTicketLimit caps the tickets one user can hold for a free event without a limit of
+	// its own; 0 is unlimited
+	FreeTicketLimit int
+	// HeartbeatTimeout releases an intent early once its checkout stops sending heartbeats
+	HeartbeatTimeout time.Duration
+	// ResumeTokenTTL is how long a checkout handoff token can be redeemed on another device
+	ResumeTokenTTL time.Duration
+	// MaxPaymentAttempts caps the payment attempts of one intent
+	MaxPaymentAttempts int
+	// LockDriftTolerance is how far a Redis lock's expiry may be from its intent's before it is reset
+	LockDriftTolerance time.Duration
+}
+
+// DefaultBookingPolicy returns the booking limits from the constants, without a cap on
+// pending intents
+func DefaultBookingPolicy() BookingPolicy {
+	return BookingPolicy{
+		LockDuration:       time.Duration(constants.SeatLockDuration) * time.Minute,
+		HeartbeatTimeout:   constants.IntentHeartbeatTimeout * time.Second,
+		ResumeTokenTTL:     constants.IntentResumeTokenTTL * time.Second,
+		MaxPaymentAttempts: constants.MaxPaymentAttempts,
+		LockDriftTolerance: time.Second,
+	}
+}
+
+// BookingService runs the booking workflow: seats are held by booking intents, locked in Redis
+// with the database as the fallback, until the intent is confirmed, cancelled or expires. Other
+// modules follow the workflow through the domain events it publishes.
 type BookingService struct {
-	bookingRepo     *repository.BookingRepository
-	seatLockService *SeatLockService
-	waitlistService WaitlistServiceInterface
+	bookingRepo repository.BookingRepository
+	seatLocks   repository.SeatLockRepository
+	liveStats   *repository.LiveStatsRepository
+	unitOfWork  repository.UnitOfWork
+	events      *domain.Dispatcher
+	policy      BookingPolicy
+	saleRegions *SaleRegionService
+	settings    *SettingsService
+	now         func() time.Time
 }
 
 // Ensure BookingService implements BookingServiceInterface
 var _ BookingServiceInterface = (*BookingService)(nil)
 
-func NewBookingService(bookingRepo *repository.BookingRepository, seatLockService *SeatLockService, waitlistService WaitlistServiceInterface) *BookingService {
+func NewBookingService(bookingRepo repository.BookingRepository, seatLocks repository.SeatLockRepository, liveStats *repository.LiveStatsRepository, unitOfWork repository.UnitOfWork, events *domain.Dispatcher, policy BookingPolicy) *BookingService {
 	return &BookingService{
-		bookingRepo:     bookingRepo,
-		seatLockService: seatLockService,
-		waitlistService: waitlistService,
+		bookingRepo: bookingRepo,
+		seatLocks:   seatLocks,
+		liveStats:   liveStats,
+		unitOfWork:  unitOfWork,
+		events:      events,
+		policy:      policy,
+		now:         time.Now,
+	}
+}
+
+// WithClock makes the service read the current time from now instead of the system clock
+func (s *BookingService) WithClock(now func() time.Time) *BookingService {
+	s.now = now
+	return s
+}
+
+// WithSaleRegions enforces events' sale countries on intent creation. Without it, as when no
+// GeoIP provider is configured, sale countries aren't checked.
+func (s *BookingService) WithSaleRegions(saleRegions *SaleRegionService) *BookingService {
+	s.saleRegions = saleRegions
+	return s
+}
+
+// WithSettings reads the lock duration from the runtime settings, falling back to the policy's
+// while it isn't overridden. Intents keep the lock expiry they were created with.
+func (s *BookingService) WithSettings(settings *SettingsService) *BookingService {
+	s.settings = settings
+	return s
+}
+
+// lockDuration returns how long new booking intents currently hold their seat
+func (s *BookingService) lockDuration(ctx context.Context) time.Duration {
+	return s.settings.Duration(ctx, constants.SettingLockDuration, s.policy.LockDuration)
+}
+
+// CreateBookingIntent creates a booking intent and locks the seat, in Redis when it is
+// reachable and in the database otherwise. Users already holding the maximum of pending
+// intents get a conflict until one is confirmed, cancelled or expires. Free events take no
+// payment, so their intents are confirmed straight away, with the booking on the intent.
+func (s *BookingService) CreateBookingIntent(ctx context.Context, userID, seatID uint, options entities.BookingIntentOptions) (created *entities.BookingIntent, err error) {
+	// Load the seat first: its lock key is scoped to the event
+	seat, err := s.bookingRepo.GetSeat(ctx, seatID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		s.recordOutcome(ctx, seat.EventID, repository.LiveIntentsCreated, repository.LiveIntentsRejected, err)
+	}()
+
+	// Check Redis for an existing lock (fast path). In degraded mode this fails immediately
+	// and the seat is locked in the database only.
+	useRedis := true
+	isLocked, _, err := s.seatLocks.IsLocked(ctx, seat.EventID, seatID)
+	if err != nil {
+		useRedis = false
+	} else if isLocked {
+		isLockedByUser, _, err := s.seatLocks.IsLockedByUser(ctx, seat.EventID, seatID, userID)
+		switch {
+		case err != nil:
+			useRedis = false
+		case isLockedByUser:
+			return nil, errors.NewConflictError("You already have an active booking intent for this seat", nil)
+		default:
+			return nil, errors.NewConflictError(constants.ErrSeatAlreadyLocked, nil)
+		}
+	}
+
+	now := s.now()
+	if err := s.checkSeatBookable(seat, now); err != nil {
+		return nil, err
+	}
+
+	if s.saleRegions != nil {
+		if err := s.saleRegions.CheckSale(ctx, &seat.Event, userID, options.Country); err != nil {
+			return nil, err
+		}
+	}
+
+	code, err := s.bookingRepo.CheckSaleEligibility(ctx, seat, userID, options.PresaleCode)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create booking intent, recording acceptance of the event's terms. Its lock expiry is
+	// fixed here and shared by the database and the Redis lock.
+	intent := &entities.BookingIntent{
+		UserID:        userID,
+		EventID:       seat.EventID,
+		SeatID:        seatID,
+		Status:        constants.IntentStatusPending,
+		LockExpiresAt: now.Add(s.lockDuration(ctx)),
+		Attribution:   normalizeAttribution(options.Attribution),
+		CreatedAt:     now,
+	}
+	if code != nil {
+		intent.PresaleCodeID = &code.ID
 	}
+	if err := acceptTerms(&seat.Event, intent, options, now); err != nil {
+		return nil, err
+	}
+
+	if useRedis {
+		err = s.createWithRedisLock(ctx, intent, now)
+	} else {
+		err = s.createWithDatabaseLock(ctx, intent, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.events.Publish(ctx, domain.SeatLocked{IntentID: intent.ID, EventID: intent.EventID, SeatID: intent.SeatID, UserID: userID, OccurredAt: now})
+
+	if seat.Event.IsFree() {
+		return s.confirmFree(ctx, intent, options.Attendee)
+	}
+	return s.bookingRepo.GetIntentDetails(ctx, intent.ID)
 }
 
-// CreateBookingIntent creates a booking intent and locks the seat
-func (s *BookingService) CreateBookingIntent(ctx context.Context, userID, seatID uint) (*entities.BookingIntent, error) {
-	return s.bookingRepo.CreateBookingIntent(ctx, userID, seatID)
+// confirmFree confirms a free event's new intent without payment. If it can't be confirmed,
+// e.g. because the user already holds the event's ticket limit, the intent is cancelled.
+func (s *BookingService) confirmFree(ctx context.Context, intent *entities.BookingIntent, attendee entities.AttendeeDetails) (*entities.BookingIntent, error) {
+	booking, err := s.ConfirmBooking(ctx, intent.ID, entities.PaymentDetails{}, attendee, entities.ConfirmOptions{})
+	if err != nil {
+		if cancelErr := s.CancelBookingIntent(ctx, intent.ID, intent.UserID); cancelErr != nil {
+			logger.Warnf("Failed to cancel free event intent %d after its confirmation failed: %v", intent.ID, cancelErr)
+		}
+		return nil, err
+	}
+
+	confirmed, err := s.bookingRepo.GetIntentDetails(ctx, intent.ID)
+	if err != nil {
+		return nil, err
+	}
+	confirmed.Booking = booking
+	return confirmed, nil
 }
 
-func (s *BookingService) ConfirmBooking(ctx context.Context, bookingIntentID uint, paymentID string) (*entities.Booking, error) {
-	return s.bookingRepo.ConfirmBooking(ctx, bookingIntentID, paymentID)
+// checkSeatBookable checks that the seat can be sold at now: it is unsold, released, not
+// locked by a live database lock, and its event is active, upcoming and not sold out
+func (s *BookingService) checkSeatBookable(seat *entities.Seat, now time.Time) error {
+	if !seat.IsAvailable {
+		return errors.NewBadRequestError(constants.ErrSeatNotAvailable, nil)
+	}
+
+	// Held seats go on sale in a later release wave
+	if seat.IsHeld {
+		return errors.NewBadRequestError(constants.ErrSeatNotReleased, nil)
+	}
+
+	// Oversell places of free events go to the waitlist once the event sells out
+	if seat.IsOversell {
+		return errors.NewBadRequestError(constants.ErrOversellSeat, nil)
+	}
+
+	// Database locks whose intent's lock expiry has passed were left behind and no longer
+	// count. The expiry is the holding intent's, so changing the lock duration doesn't shorten it.
+	if seat.IsLocked && (seat.LockExpiresAt == nil || now.Before(*seat.LockExpiresAt)) {
+		return errors.NewConflictError(constants.ErrSeatAlreadyLocked, nil)
+	}
+
+	if seat.Event.Status != constants.EventStatusActive {
+		return errors.NewBadRequestError("Event is not active", nil)
+	}
+
+	if seat.Event.StartTime.Before(now) {
+		return errors.NewBadRequestError("Event has already started", nil)
+	}
+
+	if !now.Before(seat.Event.SalesCloseAt()) {
+		return errors.NewBadRequestError(constants.ErrSalesClosed, nil)
+	}
+
+	if seat.Event.AvailableSeats <= 0 {
+		return errors.NewBadRequestError(constants.ErrEventSoldOut, nil)
+	}
+
+	return nil
 }
 
+// createWithRedisLock takes the seat's Redis lock under a temporary ID, then stores the intent
+// and moves the lock to the intent's ID in one unit of work. If Redis goes down on the way the
+// seat is locked in the database instead.
+func (s *BookingService) createWithRedisLock(ctx context.Context, intent *entities.BookingIntent, now time.Time) error {
+	tempIntentID := fmt.Sprintf("temp_%d_%d", intent.UserID, now.UnixNano())
+	if err := s.seatLocks.LockSeat(ctx, intent.EventID, intent.SeatID, intent.UserID, tempIntentID, intent.LockExpiresAt); err != nil {
+		if redisconn.IsUnavailable(err) {
+			// Redis went down since the check
+			return s.createWithDatabaseLock(ctx, intent, now)
+		}
+		// Another user acquired the lock in the meantime
+		return errors.NewConflictError(constants.ErrSeatAlreadyLocked, err)
+	}
+
+	// heldAs is the ID the Redis lock is held under, to release it if the intent isn't stored
+	heldAs := tempIntentID
+	err := s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := s.bookingRepo.CreateBookingIntent(ctx, intent, s.policy.MaxPendingIntents); err != nil {
+			return err
+		}
+
+		if err := s.seatLocks.UnlockSeat(ctx, intent.EventID, intent.SeatID, intent.UserID, tempIntentID); err != nil {
+			warnLockError("unlock temp Redis lock", err)
+		}
+		heldAs = intentLockID(intent)
+		if err := s.seatLocks.LockSeat(ctx, intent.EventID, intent.SeatID, intent.UserID, heldAs, intent.LockExpiresAt); err != nil {
+			warnLockError("lock seat with real intent ID, falling back to database lock", err)
+			heldAs = ""
+			return s.lockSeatInDatabase(ctx, intent, now)
+		}
+		return nil
+	})
+	if err != nil && heldAs != "" {
+		if unlockErr := s.seatLocks.UnlockSeat(ctx, intent.EventID, intent.SeatID, intent.UserID, heldAs); unlockErr != nil {
+			warnLockError("unlock seat in Redis", unlockErr)
+		}
+	}
+	return err
+}
+
+// createWithDatabaseLock stores the intent and locks its seat in the database, in one unit of
+// work so an intent is never stored without its lock
+func (s *BookingService) createWithDatabaseLock(ctx context.Context, intent *entities.BookingIntent, now time.Time) error {
+	return s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := s.bookingRepo.CreateBookingIntent(ctx, intent, s.policy.MaxPendingIntents); err != nil {
+			return err
+		}
+		return s.lockSeatInDatabase(ctx, intent, now)
+	})
+}
+
+// lockSeatInDatabase locks an intent's seat in the database until the intent's lock expiry.
+// Locks whose holding intent's expiry has passed were left behind and are taken over.
+func (s *BookingService) lockSeatInDatabase(ctx context.Context, intent *entities.BookingIntent, now time.Time) error {
+	return s.bookingRepo.LockSeatInDatabase(ctx, intent.SeatID, intent.UserID, now, intent.LockExpiresAt)
+}
+
+// ConfirmBooking confirms a booking intent after successful payment
+func (s *BookingService) ConfirmBooking(ctx context.Context, bookingIntentID uint, payment entities.PaymentDetails, attendee entities.AttendeeDetails, options entities.ConfirmOptions) (confirmed *entities.Booking, err error) {
+	intent, err := s.bookingRepo.GetPendingIntent(ctx, bookingIntentID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		s.recordOutcome(ctx, intent.EventID, repository.LiveConfirmations, repository.LiveConfirmationsRejected, err)
+	}()
+
+	if s.expired(intent) {
+		return nil, errors.NewBadRequestError(constants.ErrBookingExpired, nil)
+	}
+
+	options.FreeTicketLimit = s.policy.FreeTicketLimit
+	booking, released, err := s.bookingRepo.ConfirmBooking(ctx, bookingIntentID, payment, attendee, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// The Redis locks only go once the booking is committed
+	now := s.now()
+	s.releaseLock(ctx, intent)
+	published := []domain.Event{domain.BookingConfirmed{
+		BookingID:   booking.ID,
+		IntentID:    intent.ID,
+		UserID:      booking.UserID,
+		EventID:     booking.EventID,
+		SeatID:      booking.SeatID,
+		TotalAmount: booking.TotalAmount,
+		OccurredAt:  now,
+	}}
+	for i := range released {
+		s.releaseLock(ctx, &released[i])
+		published = append(published, seatReleased(&released[i], domain.ReleaseIntentReleased, now))
+	}
+	s.events.Publish(ctx, published...)
+
+	return booking, nil
+}
+
+// CancelBookingIntent cancels a booking intent and unlocks the seat
 func (s *BookingService) CancelBookingIntent(ctx context.Context, bookingIntentID uint, userID uint) error {
-	return s.bookingRepo.CancelBookingIntent(ctx, bookingIntentID, userID)
+	intent, err := s.bookingRepo.CancelBookingIntent(ctx, bookingIntentID, userID)
+	if err != nil {
+		return err
+	}
+	s.releaseLock(ctx, intent)
+	s.events.Publish(ctx, seatReleased(intent, domain.ReleaseIntentCancelled, s.now()))
+	return nil
 }
 
+// CancelUserIntents cancels all of a user's pending intents and releases their seats,
+// returning how many were cancelled
+func (s *BookingService) CancelUserIntents(ctx context.Context, userID uint) (int, error) {
+	cancelled, err := s.bookingRepo.CancelUserIntents(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	now := s.now()
+	for i := range cancelled {
+		s.releaseLock(ctx, &cancelled[i])
+		s.events.Publish(ctx, seatReleased(&cancelled[i], domain.ReleaseIntentCancelled, now))
+	}
+	return len(cancelled), nil
+}
+
+// CancelBooking cancels a confirmed booking before its event starts
 func (s *BookingService) CancelBooking(ctx context.Context, bookingID uint, userID uint) error {
-	return s.bookingRepo.CancelBooking(ctx, bookingID, userID)
+	booking, err := s.bookingRepo.GetConfirmedBooking(ctx, bookingID, userID)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	if booking.Event.StartTime.Before(now) {
+		return errors.NewBadRequestError("Cannot cancel booking after event has started", nil)
+	}
+
+	cancelled, err := s.bookingRepo.CancelBooking(ctx, booking.ID)
+	if err != nil {
+		return err
+	}
+
+	published := make([]domain.Event, 0, 2*len(cancelled))
+	for _, b := range cancelled {
+		var refund money.Money
+		if b.PaymentStatus == constants.PaymentStatusPaid {
+			refund = b.TotalAmount
+		}
+		oversell := b.Channel == constants.SalesChannelOversell
+		published = append(published,
+			domain.BookingCancelled{BookingID: b.ID, UserID: b.UserID, EventID: b.EventID, SeatID: b.SeatID, RefundAmount: refund, Oversell: oversell, OccurredAt: now},
+			domain.SeatReleased{EventID: b.EventID, SeatID: b.SeatID, UserID: b.UserID, Reason: domain.ReleaseBookingCancelled, Oversell: oversell, OccurredAt: now})
+	}
+	s.events.Publish(ctx, published...)
+	return nil
 }
 
-func (s *BookingService) GetUserBookings(ctx context.Context, userID uint, limit, offset int) ([]entities.Booking, int64, error) {
-	return s.bookingRepo.GetUserBookings(ctx, userID, limit, offset)
+func (s *BookingService) GetUserBookings(ctx context.Context, userID uint, filter entities.BookingFilter, limit, offset int) ([]entities.Booking, int64, error) {
+	return s.bookingRepo.GetUserBookings(ctx, userID, filter, limit, offset)
+}
+
+// GetUpcomingBookings returns the user's next confirmed bookings, soonest event first
+func (s *BookingService) GetUpcomingBookings(ctx context.Context, userID uint, limit int) ([]entities.Booking, error) {
+	return s.bookingRepo.GetUpcomingBookings(ctx, userID, s.now(), limit)
 }
 
 func (s *BookingService) GetBookingByID(ctx context.Context, bookingID, userID uint) (*entities.Booking, error) {
 	return s.bookingRepo.GetBookingByID(ctx, bookingID, userID)
 }
 
+// CleanupExpiredIntents expires pending intents whose lock ran out and unlocks their seats,
+// then realigns the Redis locks of the intents still pending
 func (s *BookingService) CleanupExpiredIntents(ctx context.Context) error {
-	return s.bookingRepo.CleanupExpiredIntents(ctx)
+	now := s.now()
+	expired, err := s.bookingRepo.ExpireIntents(ctx, now)
+	if err != nil {
+		return err
+	}
+	for i := range expired {
+		s.releaseLock(ctx, &expired[i])
+		s.events.Publish(ctx, intentExpired(&expired[i], false, now)...)
+	}
+
+	return s.alignPendingLocks(ctx, now)
+}
+
+// PurgeEventIntents expires an event's pending intents created before the cutoff, e.g. ones
+// abandoned before sales re-open, releasing their seats and Redis locks
+func (s *BookingService) PurgeEventIntents(ctx context.Context, eventID uint, createdBefore time.Time) (*IntentPurgeResult, error) {
+	now := s.now()
+	expired, err := s.bookingRepo.ExpireEventIntents(ctx, eventID, createdBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &IntentPurgeResult{EventID: eventID, CreatedBefore: createdBefore, ExpiredIntents: len(expired)}
+	seats := make(map[uint]bool, len(expired))
+	for i := range expired {
+		seats[expired[i].SeatID] = true
+		if err := s.seatLocks.UnlockSeat(ctx, expired[i].EventID, expired[i].SeatID, expired[i].UserID, intentLockID(&expired[i])); err != nil {
+			warnLockError("unlock seat in Redis", err)
+			result.FailedLocks++
+		} else {
+			result.ReleasedLocks++
+		}
+		if err := s.seatLocks.ClearHeartbeat(ctx, expired[i].ID); err != nil {
+			warnLockError("clear heartbeat", err)
+		}
+		s.events.Publish(ctx, intentExpired(&expired[i], false, now)...)
+	}
+	result.ReleasedSeats = len(seats)
+	return result, nil
+}
+
+// alignPendingLocks checks that the Redis lock of every pending intent expires with the intent,
+// resetting locks that drifted and recreating lost ones, e.g. after Redis restarted or the
+// intent was created in degraded mode
+func (s *BookingService) alignPendingLocks(ctx context.Context, now time.Time) error {
+	intents, err := s.bookingRepo.GetPendingIntents(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	restored := 0
+	for _, intent := range intents {
+		outcome, drift, err := s.seatLocks.AlignLock(ctx, intent.EventID, intent.SeatID, intent.UserID, intentLockID(&intent), intent.LockExpiresAt, s.policy.LockDriftTolerance)
+		if err != nil {
+			if redisconn.IsUnavailable(err) {
+				return nil
+			}
+			return errors.NewInternalError("Failed to check seat lock drift", err)
+		}
+
+		switch outcome {
+		case repository.LockRealigned:
+			logger.Warnf("Seat lock for intent %d drifted %s from the intent expiry, reset", intent.ID, drift)
+		case repository.LockRestored:
+			restored++
+		case repository.LockConflict:
+			logger.Warnf("Seat %d is locked in Redis by another intent than pending intent %d", intent.SeatID, intent.ID)
+		}
+	}
+
+	if restored > 0 {
+		logger.Infof("Restored %d missing seat locks of pending intents", restored)
+	}
+	return nil
+}
+
+// HeartbeatBookingIntent keeps an open checkout's seat lock from being released early
+func (s *BookingService) HeartbeatBookingIntent(ctx context.Context, bookingIntentID uint, userID uint) (*entities.BookingIntent, error) {
+	intent, err := s.bookingRepo.GetUserPendingIntent(ctx, bookingIntentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.expired(intent) {
+		return nil, errors.NewBadRequestError(constants.ErrBookingExpired, nil)
+	}
+
+	if err := s.seatLocks.RecordHeartbeat(ctx, intent.ID, s.now()); err != nil {
+		if redisconn.IsUnavailable(err) {
+			// Without heartbeats the intent is only released when its lock expires
+			return intent, nil
+		}
+		return nil, errors.NewInternalError("Failed to record heartbeat", err)
+	}
+
+	return intent, nil
+}
+
+// IssueResumeToken creates a single-use token that hands a pending intent's checkout over to
+// another device. Issuing a new token replaces the previous one. The token expires after the
+// policy's ResumeTokenTTL, or with the seat lock if that comes first.
+func (s *BookingService) IssueResumeToken(ctx context.Context, bookingIntentID uint, userID uint) (string, time.Time, error) {
+	intent, err := s.bookingRepo.GetUserPendingIntent(ctx, bookingIntentID, userID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	now := s.now()
+	if now.After(intent.LockExpiresAt) {
+		return "", time.Time{}, errors.NewBadRequestError(constants.ErrBookingExpired, nil)
+	}
+
+	token, err := newResumeToken()
+	if err != nil {
+		return "", time.Time{}, errors.NewInternalError("Failed to generate resume token", err)
+	}
+
+	expiresAt := now.Add(s.policy.ResumeTokenTTL)
+	if intent.LockExpiresAt.Before(expiresAt) {
+		expiresAt = intent.LockExpiresAt
+	}
+
+	if err := s.bookingRepo.SaveResumeToken(ctx, intent.ID, hashResumeToken(token), expiresAt); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+// ResumeBookingIntent redeems a resume token for the user's pending intent. Before handing the
+// checkout over it checks the seat is still held for the intent, in the database and in Redis,
+// where a lost lock is recreated. The new device's heartbeats take over from the old one's.
+func (s *BookingService) ResumeBookingIntent(ctx context.Context, userID uint, token string) (*entities.BookingIntent, error) {
+	tokenHash := hashResumeToken(token)
+	intent, err := s.bookingRepo.GetIntentByResumeToken(ctx, userID, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if intent.ResumeTokenExpiresAt == nil || now.After(*intent.ResumeTokenExpiresAt) {
+		return nil, errors.NewBadRequestError(constants.ErrInvalidResumeToken, nil)
+	}
+	if now.After(intent.LockExpiresAt) {
+		return nil, errors.NewBadRequestError(constants.ErrBookingExpired, nil)
+	}
+
+	// Intents taken in degraded mode hold their seat in the database
+	seat, err := s.bookingRepo.GetSeat(ctx, intent.SeatID)
+	if err != nil {
+		return nil, err
+	}
+	if seat.IsLocked && (seat.LockedBy == nil || *seat.LockedBy != userID) {
+		return nil, errors.NewConflictError(constants.ErrSeatHoldLost, nil)
+	}
+
+	outcome, _, err := s.seatLocks.AlignLock(ctx, intent.EventID, intent.SeatID, userID, intentLockID(intent), intent.LockExpiresAt, s.policy.LockDriftTolerance)
+	if err != nil && !redisconn.IsUnavailable(err) {
+		return nil, errors.NewInternalError("Failed to check seat lock", err)
+	}
+	if err == nil && outcome == repository.LockConflict {
+		return nil, errors.NewConflictError(constants.ErrSeatHoldLost, nil)
+	}
+
+	// The token is single use
+	redeemed, err := s.bookingRepo.RedeemResumeToken(ctx, intent.ID, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	if !redeemed {
+		return nil, errors.NewBadRequestError(constants.ErrInvalidResumeToken, nil)
+	}
+
+	if err := s.seatLocks.RecordHeartbeat(ctx, intent.ID, now); err != nil {
+		warnLockError("record heartbeat", err)
+	}
+
+	return s.bookingRepo.GetIntentDetails(ctx, intent.ID)
+}
+
+// RetryPayment starts a new payment attempt for an intent whose previous payment failed. The
+// seat lock is left untouched, so the retry must still complete before the intent expires.
+func (s *BookingService) RetryPayment(ctx context.Context, bookingIntentID uint, userID uint, failureReason string) (*entities.BookingIntent, error) {
+	intent, err := s.bookingRepo.GetUserPendingIntent(ctx, bookingIntentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.expired(intent) {
+		return nil, errors.NewBadRequestError(constants.ErrBookingExpired, nil)
+	}
+
+	reference, err := newPaymentReference()
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to generate payment reference", err)
+	}
+
+	return s.bookingRepo.StartPaymentAttempt(ctx, intent.ID, reference, failureReason, s.policy.MaxPaymentAttempts)
+}
+
+// GetBookingIntentStatus returns the intent state and the remaining lock time
+func (s *BookingService) GetBookingIntentStatus(ctx context.Context, bookingIntentID uint, userID uint) (*BookingIntentStatus, error) {
+	intent, err := s.bookingRepo.GetBookingIntent(ctx, bookingIntentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &BookingIntentStatus{
+		BookingIntentID: intent.ID,
+		Status:          intent.Status,
+		PaymentStatus:   intent.PaymentStatus,
+		PaymentIntentID: intent.PaymentIntentID,
+	}
+
+	if intent.Status != constants.IntentStatusPending {
+		return status, nil
+	}
+
+	// The Redis lock expires at the same instant
+	expiresAt := intent.LockExpiresAt
+	remaining := expiresAt.Sub(s.now())
+	if remaining < 0 {
+		remaining = 0
+	}
+	status.ExpiresAt = &expiresAt
+	status.RemainingSeconds = int(remaining.Seconds())
+
+	return status, nil
+}
+
+// InspectSeatLock reports a seat's database lock, its Redis lock with the remaining TTL and the
+// intent behind them. An unreachable Redis is reported rather than failing the inspection.
+func (s *BookingService) InspectSeatLock(ctx context.Context, seatID uint) (*SeatLockInspection, error) {
+	seat, err := s.bookingRepo.GetSeat(ctx, seatID)
+	if err != nil {
+		return nil, err
+	}
+
+	inspection := &SeatLockInspection{
+		SeatID:      seat.ID,
+		EventID:     seat.EventID,
+		IsAvailable: seat.IsAvailable,
+		Database: DatabaseSeatLock{
+			IsLocked: seat.IsLocked,
+			LockedAt: seat.LockedAt,
+			LockedBy: seat.LockedBy,
+		},
+	}
+	s.inspectRedisLock(ctx, seat, &inspection.Redis)
+
+	intent, err := s.bookingRepo.GetLatestSeatIntent(ctx, seatID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); !ok || appErr.Type != "NOT_FOUND" {
+			return nil, err
+		}
+		return inspection, nil
+	}
+	inspection.Intent = &SeatLockIntent{
+		ID:             intent.ID,
+		UserID:         intent.UserID,
+		Status:         intent.Status,
+		PaymentStatus:  intent.PaymentStatus,
+		LockExpiresAt:  intent.LockExpiresAt,
+		CreatedAt:      intent.CreatedAt,
+		HoldsRedisLock: inspection.Redis.IsLocked && inspection.Redis.IntentID == intentLockID(intent),
+	}
+	return inspection, nil
+}
+
+// inspectRedisLock reads a seat's Redis lock and its TTL into lock
+func (s *BookingService) inspectRedisLock(ctx context.Context, seat *entities.Seat, lock *RedisSeatLock) {
+	locked, value, err := s.seatLocks.IsLocked(ctx, seat.EventID, seat.ID)
+	if err != nil {
+		lock.Error = err.Error()
+		return
+	}
+	lock.Reachable = true
+	if !locked {
+		return
+	}
+
+	ttl, err := s.seatLocks.GetLockTTL(ctx, seat.EventID, seat.ID)
+	if err != nil {
+		lock.Error = err.Error()
+		return
+	}
+	switch {
+	case ttl == -2:
+		// Expired since it was read
+		return
+	case ttl == -1:
+		lock.TTLSeconds = -1
+	default:
+		lock.TTLSeconds = int(ttl.Round(time.Second) / time.Second)
+	}
+
+	lock.IsLocked = true
+	lock.Value = value
+	if userID, intentID, ok := strings.Cut(value, ":"); ok {
+		if id, err := strconv.ParseUint(userID, 10, 32); err == nil {
+			owner := uint(id)
+			lock.UserID = &owner
+		}
+		lock.IntentID = intentID
+	}
+}
+
+// ReleaseAbandonedIntents frees seats held by checkouts that stopped sending heartbeats, before
+// the full lock duration has passed
+func (s *BookingService) ReleaseAbandonedIntents(ctx context.Context) error {
+	now := s.now()
+	before := now.Add(-s.policy.HeartbeatTimeout)
+	intentIDs, err := s.seatLocks.GetStaleHeartbeats(ctx, before)
+	if err != nil {
+		if redisconn.IsUnavailable(err) {
+			// Heartbeats live in Redis; until it is back intents run to their full lock duration
+			return nil
+		}
+		return errors.NewInternalError("Failed to fetch stale heartbeats", err)
+	}
+
+	released := 0
+	defer func() {
+		if released > 0 {
+			logger.Infof("Released %d abandoned booking intents", released)
+		}
+	}()
+
+	for _, intentID := range intentIDs {
+		intent, err := s.bookingRepo.ExpireBookingIntent(ctx, intentID)
+		if err != nil {
+			return err
+		}
+		if intent == nil {
+			// Already confirmed, cancelled or expired
+			if err := s.seatLocks.ClearHeartbeat(ctx, intentID); err != nil {
+				warnLockError("clear heartbeat", err)
+			}
+			continue
+		}
+		s.releaseLock(ctx, intent)
+		s.events.Publish(ctx, intentExpired(intent, true, now)...)
+		released++
+	}
+
+	return nil
+}
+
+// expired reports whether the intent's seat lock has run out
+func (s *BookingService) expired(intent *entities.BookingIntent) bool {
+	return s.now().After(intent.LockExpiresAt)
+}
+
+// releaseLock gives up a finished intent's Redis lock and heartbeat. The database state is
+// already final, so failures are only logged.
+func (s *BookingService) releaseLock(ctx context.Context, intent *entities.BookingIntent) {
+	if err := s.seatLocks.UnlockSeat(ctx, intent.EventID, intent.SeatID, intent.UserID, intentLockID(intent)); err != nil {
+		warnLockError("unlock seat in Redis", err)
+	}
+	if err := s.seatLocks.ClearHeartbeat(ctx, intent.ID); err != nil {
+		warnLockError("clear heartbeat", err)
+	}
+}
+
+// seatReleased is the event of a finished intent giving up its seat
+func seatReleased(intent *entities.BookingIntent, reason string, now time.Time) domain.SeatReleased {
+	return domain.SeatReleased{EventID: intent.EventID, SeatID: intent.SeatID, UserID: intent.UserID, Reason: reason, OccurredAt: now}
+}
+
+// intentExpired returns the events of an expired intent, which also frees its seat
+func intentExpired(intent *entities.BookingIntent, abandoned bool, now time.Time) []domain.Event {
+	reason := domain.ReleaseIntentExpired
+	if abandoned {
+		reason = domain.ReleaseIntentAbandoned
+	}
+	return []domain.Event{
+		domain.IntentExpired{IntentID: intent.ID, UserID: intent.UserID, EventID: intent.EventID, SeatID: intent.SeatID, Abandoned: abandoned, OccurredAt: now},
+		seatReleased(intent, reason, now),
+	}
+}
+
+// recordOutcome counts an intent or confirmation attempt on the live on-sale dashboard. Intents
+// refused because another user holds the seat are also counted as lock conflicts, for alerting.
+func (s *BookingService) recordOutcome(ctx context.Context, eventID uint, succeeded, rejected string, err error) {
+	if s.liveStats == nil {
+		return
+	}
+	s.liveStats.RecordOutcome(ctx, eventID, succeeded, rejected, err)
+	if appErr, ok := err.(*errors.AppError); ok && appErr.Message == constants.ErrSeatAlreadyLocked {
+		s.liveStats.Record(ctx, eventID, repository.LiveLockConflicts)
+	}
+}
+
+// intentLockID is the ID an intent's Redis seat lock is held under
+func intentLockID(intent *entities.BookingIntent) string {
+	return fmt.Sprintf("%d", intent.ID)
+}
+
+// warnLockError logs a failed Redis lock operation the database state makes up for. Errors
+// from degraded mode aren't logged one by one: entering it is logged once.
+func warnLockError(action string, err error) {
+	if !redisconn.IsUnavailable(err) {
+		logger.Warnf("Failed to %s: %v", action, err)
+	}
+}
+
+// newResumeToken generates a checkout handoff token
+func newResumeToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashResumeToken returns the form a resume token is stored in, so a database read can't be
+// used to take over a checkout
+func hashResumeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// newPaymentReference generates a unique reference for a payment attempt
+func newPaymentReference() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return constants.PaymentReferencePrefix + hex.EncodeToString(buf), nil
+}
+
+// normalizeAttribution lowercases UTM values, so "Newsletter" and "newsletter" are reported as
+// one channel, and uppercases referral codes like presale codes
+func normalizeAttribution(attribution entities.Attribution) entities.Attribution {
+	return entities.Attribution{
+		UTMSource:    strings.ToLower(strings.TrimSpace(attribution.UTMSource)),
+		UTMMedium:    strings.ToLower(strings.TrimSpace(attribution.UTMMedium)),
+		UTMCampaign:  strings.ToLower(strings.TrimSpace(attribution.UTMCampaign)),
+		ReferralCode: strings.ToUpper(strings.TrimSpace(attribution.ReferralCode)),
+	}
 }
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/encryption"
+	"api/internal/entities"
+	redisconn "api/internal/redis"
+	"api/internal/repository"
+	"api/pkg/errors"
+	"api/pkg/money"
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestLoweredLockDurationKeepsHeldSeatsLocked checks against a scratch Postgres database that
+// lowering the lock duration setting doesn't let another user take over a database lock before
+// the holding intent's lock expiry. Redis is unreachable, so intents lock their seats in the
+// database only. It needs TEST_DATABASE_URL.
+func TestLoweredLockDurationKeepsHeldSeatsLocked(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	keyring, err := encryption.NewKeyring(encryption.Config{Keys: "test:MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MDE="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encryption.Register(keyring)
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger:                                   logger.Discard,
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&entities.Tenant{}, &entities.User{}, &entities.Venue{}, &entities.VenueSection{},
+		&entities.Event{}, &entities.Seat{}, &entities.BookingIntent{}, &entities.Booking{}, &entities.PresaleCode{},
+		&entities.RuntimeSetting{}, &entities.SettingChange{}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := repository.NewTenantRepository(db).EnsureDefaultTenant(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	settings := NewSettingsService(repository.NewSettingsRepository(db), 0, RuntimeSettings(SettingDefaults{
+		LockDuration: 15 * time.Minute,
+		WaitlistHold: 10 * time.Minute,
+	}))
+	t.Cleanup(func() { settings.ResetSetting(ctx, constants.SettingLockDuration, 1) })
+
+	// Nothing listens on port 1: the first command switches the client to degraded mode
+	redisClient := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	redisconn.NewHealth(redisClient, time.Hour)
+	t.Cleanup(func() { redisClient.Close() })
+
+	start := time.Now()
+	now := start
+	service := NewBookingService(repository.NewBookingRepository(db), repository.NewSeatLockRepository(redisClient),
+		repository.NewLiveStatsRepository(redisClient), repository.NewUnitOfWork(db), nil, DefaultBookingPolicy()).
+		WithSettings(settings).
+		WithClock(func() time.Time { return now })
+
+	runID := start.Format("150405.000000")
+	var users []uint
+	for i := 0; i < 2; i++ {
+		user := entities.User{Email: fmt.Sprintf("lock-duration-%s-%d@example.com", runID, i), Password: "unused"}
+		if err := db.Create(&user).Error; err != nil {
+			t.Fatal(err)
+		}
+		users = append(users, user.ID)
+	}
+	venue := entities.Venue{Name: "Lock duration " + runID, Address: "1 Test Street", City: "Test", State: "TS",
+		Country: "US", Rows: 1, Columns: 1}
+	if err := db.Create(&venue).Error; err != nil {
+		t.Fatal(err)
+	}
+	event := entities.Event{
+		Name:           "Lock duration " + runID,
+		VenueID:        venue.ID,
+		StartTime:      start.Add(30 * 24 * time.Hour),
+		EndTime:        start.Add(30*24*time.Hour + 3*time.Hour),
+		Price:          money.FromMajor(25),
+		EventType:      constants.EventTypeConcert,
+		Status:         constants.EventStatusActive,
+		AvailableSeats: 1,
+	}
+	if err := db.Create(&event).Error; err != nil {
+		t.Fatal(err)
+	}
+	seat := entities.Seat{EventID: event.ID, Row: 1, Column: 1, SeatType: constants.SeatTypeStandard,
+		Price: event.Price, IsAvailable: true}
+	if err := db.Create(&seat).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := settings.UpdateSetting(ctx, constants.SettingLockDuration, "10m", 1); err != nil {
+		t.Fatal(err)
+	}
+	held, err := service.CreateBookingIntent(ctx, users[0], seat.ID, entities.BookingIntentOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Postgres keeps microseconds
+	if want := start.Add(10 * time.Minute); held.LockExpiresAt.Sub(want).Abs() > time.Microsecond {
+		t.Fatalf("intent lock expires at %v, want %v", held.LockExpiresAt, want)
+	}
+
+	// The held lock is older than the new duration but its intent's expiry hasn't passed
+	if _, err := settings.UpdateSetting(ctx, constants.SettingLockDuration, "2m", 1); err != nil {
+		t.Fatal(err)
+	}
+	now = start.Add(5 * time.Minute)
+	_, err = service.CreateBookingIntent(ctx, users[1], seat.ID, entities.BookingIntentOptions{})
+	appErr, ok := err.(*errors.AppError)
+	if !ok || appErr.Message != constants.ErrSeatAlreadyLocked {
+		t.Fatalf("intent for the held seat returned %v, want %q", err, constants.ErrSeatAlreadyLocked)
+	}
+	var current entities.Seat
+	if err := db.First(&current, seat.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !current.IsLocked || current.LockedBy == nil || *current.LockedBy != users[0] {
+		t.Fatalf("seat lock is held by %v, want user %d", current.LockedBy, users[0])
+	}
+
+	// Once the holding intent's expiry has passed the lock was left behind and is taken over
+	now = start.Add(11 * time.Minute)
+	if _, err := service.CreateBookingIntent(ctx, users[1], seat.ID, entities.BookingIntentOptions{}); err != nil {
+		t.Fatalf("intent after the held lock expired: %v", err)
+	}
+}
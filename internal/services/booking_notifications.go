@@ -0,0 +1,89 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/domain"
+	"api/internal/notifications"
+	"api/internal/repository"
+	"context"
+	"fmt"
+)
+
+// BookingNotifications tells users what happened to their bookings and seat holds, from the
+// events the booking workflow publishes
+type BookingNotifications struct {
+	userRepo repository.UserRepository
+	notifier notifications.Notifier
+}
+
+func NewBookingNotifications(userRepo repository.UserRepository, notifier notifications.Notifier) *BookingNotifications {
+	return &BookingNotifications{
+		userRepo: userRepo,
+		notifier: notifier,
+	}
+}
+
+// Subscribe registers the notifications with the dispatcher the booking service publishes to
+func (n *BookingNotifications) Subscribe(events *domain.Dispatcher) {
+	domain.Subscribe(events, "booking_notifications", n.bookingConfirmed)
+	domain.Subscribe(events, "booking_notifications", n.bookingCancelled)
+	domain.Subscribe(events, "booking_notifications", n.intentExpired)
+	domain.Subscribe(events, "booking_notifications", n.noShowReleased)
+}
+
+func (n *BookingNotifications) bookingConfirmed(ctx context.Context, event domain.BookingConfirmed) error {
+	return n.send(ctx, notifications.Notification{
+		Type:      constants.NotificationTypeBookingConfirmed,
+		UserID:    event.UserID,
+		EventID:   event.EventID,
+		BookingID: event.BookingID,
+		Subject:   "Your booking is confirmed",
+		Message:   fmt.Sprintf("Booking %d is confirmed. Total paid: %s.", event.BookingID, event.TotalAmount),
+	})
+}
+
+func (n *BookingNotifications) bookingCancelled(ctx context.Context, event domain.BookingCancelled) error {
+	return n.send(ctx, notifications.Notification{
+		Type:      constants.NotificationTypeBookingCancelled,
+		UserID:    event.UserID,
+		EventID:   event.EventID,
+		BookingID: event.BookingID,
+		Subject:   "Your booking was cancelled",
+		Message:   fmt.Sprintf("Booking %d was cancelled and its seat released.", event.BookingID),
+	})
+}
+
+func (n *BookingNotifications) intentExpired(ctx context.Context, event domain.IntentExpired) error {
+	return n.send(ctx, notifications.Notification{
+		Type:    constants.NotificationTypeHoldExpired,
+		UserID:  event.UserID,
+		EventID: event.EventID,
+		Subject: "Your seat hold expired",
+		Message: "Checkout wasn't completed in time, so the seat was released. It may still be available to book again.",
+	})
+}
+
+func (n *BookingNotifications) noShowReleased(ctx context.Context, event domain.NoShowReleased) error {
+	message := fmt.Sprintf("Booking %d wasn't checked in by the event's no-show cutoff, so its seat was put back on sale at the door. Under the event's policy the booking isn't refunded.", event.BookingID)
+	if event.RefundAmount > 0 {
+		message = fmt.Sprintf("Booking %d wasn't checked in by the event's no-show cutoff, so its seat was put back on sale at the door. %s has been refunded.", event.BookingID, event.RefundAmount)
+	}
+	return n.send(ctx, notifications.Notification{
+		Type:      constants.NotificationTypeNoShowReleased,
+		UserID:    event.UserID,
+		EventID:   event.EventID,
+		BookingID: event.BookingID,
+		Subject:   "Your seat was released",
+		Message:   message,
+	})
+}
+
+// send addresses a notification to its user's email address
+func (n *BookingNotifications) send(ctx context.Context, notification notifications.Notification) error {
+	user, err := n.userRepo.GetByID(ctx, notification.UserID)
+	if err != nil {
+		return err
+	}
+	notification.Recipient = user.Email
+	return n.notifier.Send(ctx, notification)
+}
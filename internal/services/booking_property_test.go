@@ -0,0 +1,406 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/encryption"
+	"api/internal/entities"
+	redisconn "api/internal/redis"
+	"api/internal/repository"
+	"api/pkg/money"
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// intentModel and bookingModel are the expected state of the intents and bookings made
+// during a sequence
+type intentModel struct {
+	userID uint
+	seatID uint
+	status string
+}
+
+type bookingModel struct {
+	userID uint
+	seatID uint
+	status string
+}
+
+// bookingMachine runs random operations against the booking service and a model of the seat
+// state machine: a seat is free, held by one pending intent, or sold to one booking
+type bookingMachine struct {
+	t       *testing.T
+	ctx     context.Context
+	db      *gorm.DB
+	service *BookingService
+	rng     *rand.Rand
+	event   entities.Event
+	seats   []uint
+	users   []uint
+
+	intents  map[uint]*intentModel
+	bookings map[uint]*bookingModel
+	payments int
+}
+
+// TestBookingStateMachineProperties drives random sequences of intent creation, confirmation,
+// cancellation and expiry against a scratch Postgres database, checking after every step that
+// the service agrees with the model and that seats are conserved: every seat is free,
+// locked or sold exactly once, and the event's available_seats counts the unsold ones. Redis
+// is unreachable, so intents take the database-only path. It needs TEST_DATABASE_URL; set
+// PROPERTY_SEED to replay a failing run.
+func TestBookingStateMachineProperties(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	keyring, err := encryption.NewKeyring(encryption.Config{Keys: "test:MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MDE="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encryption.Register(keyring)
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger:                                   logger.Discard,
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&entities.Tenant{}, &entities.User{}, &entities.Venue{}, &entities.VenueSection{},
+		&entities.Event{}, &entities.Seat{}, &entities.BookingIntent{}, &entities.PaymentAttempt{},
+		&entities.PaymentTransaction{}, &entities.PaymentTransactionEvent{}, &entities.Booking{},
+		&entities.LoyaltyTransaction{}, &entities.PresaleCode{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repository.NewTenantRepository(db).EnsureDefaultTenant(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing listens on port 1: the first command switches the client to degraded mode
+	redisClient := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	redisconn.NewHealth(redisClient, time.Hour)
+	t.Cleanup(func() { redisClient.Close() })
+	service := NewBookingService(repository.NewBookingRepository(db), repository.NewSeatLockRepository(redisClient),
+		repository.NewLiveStatsRepository(redisClient), repository.NewUnitOfWork(db), nil, DefaultBookingPolicy())
+
+	seed := time.Now().UnixNano()
+	if value := os.Getenv("PROPERTY_SEED"); value != "" {
+		if seed, err = strconv.ParseInt(value, 10, 64); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Logf("seed %d", seed)
+	rng := rand.New(rand.NewSource(seed))
+
+	const sequences, steps = 20, 60
+	for i := 0; i < sequences; i++ {
+		m := newBookingMachine(t, db, service, rng, fmt.Sprintf("%d-%d", seed, i))
+		for step := 0; step < steps && !t.Failed(); step++ {
+			m.step()
+			m.checkInvariants()
+		}
+		if t.Failed() {
+			t.Fatalf("sequence %d failed", i)
+		}
+	}
+}
+
+func newBookingMachine(t *testing.T, db *gorm.DB, service *BookingService, rng *rand.Rand, runID string) *bookingMachine {
+	t.Helper()
+	m := &bookingMachine{
+		t:        t,
+		ctx:      context.Background(),
+		db:       db,
+		service:  service,
+		rng:      rng,
+		intents:  make(map[uint]*intentModel),
+		bookings: make(map[uint]*bookingModel),
+	}
+
+	for i := 0; i < 3; i++ {
+		user := entities.User{
+			Email:         fmt.Sprintf("property-%s-%d@example.com", runID, i),
+			Password:      "unused",
+			LoyaltyPoints: rng.Intn(5000),
+		}
+		if err := db.Create(&user).Error; err != nil {
+			t.Fatal(err)
+		}
+		m.users = append(m.users, user.ID)
+	}
+
+	rows, columns := 1+rng.Intn(3), 1+rng.Intn(4)
+	venue := entities.Venue{Name: "Property " + runID, Address: "1 Test Street", City: "Test", State: "TS",
+		Country: "US", Rows: rows, Columns: columns}
+	if err := db.Create(&venue).Error; err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().Add(30 * 24 * time.Hour)
+	m.event = entities.Event{
+		Name:           "Property " + runID,
+		VenueID:        venue.ID,
+		StartTime:      start,
+		EndTime:        start.Add(3 * time.Hour),
+		Price:          money.FromMinor(int64(1 + rng.Intn(10_000))),
+		EventType:      constants.EventTypeConcert,
+		Status:         constants.EventStatusActive,
+		AvailableSeats: rows * columns,
+	}
+	if err := db.Create(&m.event).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	for row := 1; row <= rows; row++ {
+		for column := 1; column <= columns; column++ {
+			seat := entities.Seat{EventID: m.event.ID, Row: row, Column: column, SeatType: constants.SeatTypeStandard,
+				Price: m.event.Price, IsAvailable: true}
+			if err := db.Create(&seat).Error; err != nil {
+				t.Fatal(err)
+			}
+			m.seats = append(m.seats, seat.ID)
+		}
+	}
+	return m
+}
+
+func (m *bookingMachine) step() {
+	switch m.rng.Intn(5) {
+	case 0, 1:
+		m.createIntent()
+	case 2:
+		m.confirm()
+	case 3:
+		if m.rng.Intn(2) == 0 {
+			m.cancelIntent()
+		} else {
+			m.cancelBooking()
+		}
+	case 4:
+		m.expire()
+	}
+}
+
+func (m *bookingMachine) createIntent() {
+	userID, seatID := m.pick(m.users), m.pick(m.seats)
+	want := m.seatFree(seatID)
+
+	intent, err := m.service.CreateBookingIntent(m.ctx, userID, seatID, entities.BookingIntentOptions{})
+	if !m.outcome(fmt.Sprintf("create intent for seat %d by user %d", seatID, userID), want, err) || err != nil {
+		return
+	}
+	m.intents[intent.ID] = &intentModel{userID: userID, seatID: seatID, status: constants.IntentStatusPending}
+}
+
+func (m *bookingMachine) confirm() {
+	id, intent := m.pickIntent()
+	if intent == nil {
+		return
+	}
+	want := intent.status == constants.IntentStatusPending
+
+	// Redeem a random part of the balance
+	var balance int
+	if err := m.db.Model(&entities.User{}).Select("loyalty_points").Where("id = ?", intent.userID).Scan(&balance).Error; err != nil {
+		m.t.Fatal(err)
+	}
+	m.payments++
+	payment := entities.PaymentDetails{
+		PaymentID:    fmt.Sprintf("property_pay_%d_%d", m.event.ID, m.payments),
+		MethodType:   "card",
+		RedeemPoints: m.rng.Intn(balance + 1),
+	}
+
+	booking, err := m.service.ConfirmBooking(m.ctx, id, payment, entities.AttendeeDetails{}, entities.ConfirmOptions{})
+	if !m.outcome(fmt.Sprintf("confirm intent %d (%s)", id, intent.status), want, err) || err != nil {
+		return
+	}
+	intent.status = constants.IntentStatusConfirmed
+	m.bookings[booking.ID] = &bookingModel{userID: intent.userID, seatID: intent.seatID, status: constants.BookingStatusConfirmed}
+}
+
+func (m *bookingMachine) cancelIntent() {
+	id, intent := m.pickIntent()
+	if intent == nil {
+		return
+	}
+	userID := m.pick(m.users)
+	want := intent.status == constants.IntentStatusPending && intent.userID == userID
+
+	err := m.service.CancelBookingIntent(m.ctx, id, userID)
+	if !m.outcome(fmt.Sprintf("cancel intent %d (%s) by user %d", id, intent.status, userID), want, err) || err != nil {
+		return
+	}
+	intent.status = constants.IntentStatusCancelled
+}
+
+func (m *bookingMachine) cancelBooking() {
+	var ids []uint
+	for id := range m.bookings {
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return
+	}
+	id := m.pick(ids)
+	booking := m.bookings[id]
+	userID := m.pick(m.users)
+	want := booking.status == constants.BookingStatusConfirmed && booking.userID == userID
+
+	err := m.service.CancelBooking(m.ctx, id, userID)
+	if !m.outcome(fmt.Sprintf("cancel booking %d (%s) by user %d", id, booking.status, userID), want, err) || err != nil {
+		return
+	}
+	booking.status = constants.BookingStatusCancelled
+}
+
+// expire moves a pending intent's lock expiry into the past and runs the cleanup job
+func (m *bookingMachine) expire() {
+	id, intent := m.pickIntent()
+	if intent == nil || intent.status != constants.IntentStatusPending {
+		return
+	}
+	if err := m.db.Model(&entities.BookingIntent{}).Where("id = ?", id).
+		Update("lock_expires_at", time.Now().Add(-time.Second)).Error; err != nil {
+		m.t.Fatal(err)
+	}
+	if err := m.service.CleanupExpiredIntents(m.ctx); err != nil {
+		m.t.Errorf("cleanup of expired intents failed: %v", err)
+		return
+	}
+	intent.status = constants.IntentStatusExpired
+}
+
+// outcome reports whether the operation succeeded or failed as the model expects
+func (m *bookingMachine) outcome(op string, want bool, err error) bool {
+	m.t.Helper()
+	if want && err != nil {
+		m.t.Errorf("%s: unexpected error %v", op, err)
+		return false
+	}
+	if !want && err == nil {
+		m.t.Errorf("%s: succeeded, want an error", op)
+		return false
+	}
+	return true
+}
+
+func (m *bookingMachine) seatFree(seatID uint) bool {
+	for _, intent := range m.intents {
+		if intent.seatID == seatID && intent.status == constants.IntentStatusPending {
+			return false
+		}
+	}
+	for _, booking := range m.bookings {
+		if booking.seatID == seatID && booking.status == constants.BookingStatusConfirmed {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *bookingMachine) pick(ids []uint) uint {
+	return ids[m.rng.Intn(len(ids))]
+}
+
+func (m *bookingMachine) pickIntent() (uint, *intentModel) {
+	var ids []uint
+	for id := range m.intents {
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	id := m.pick(ids)
+	return id, m.intents[id]
+}
+
+// checkInvariants compares the database with the model
+func (m *bookingMachine) checkInvariants() {
+	m.t.Helper()
+
+	var seats []entities.Seat
+	if err := m.db.Where("event_id = ?", m.event.ID).Find(&seats).Error; err != nil {
+		m.t.Fatal(err)
+	}
+	if len(seats) != len(m.seats) {
+		m.t.Errorf("event has %d seats, want %d", len(seats), len(m.seats))
+	}
+
+	var bookings []entities.Booking
+	if err := m.db.Where("event_id = ? AND status = ?", m.event.ID, constants.BookingStatusConfirmed).Find(&bookings).Error; err != nil {
+		m.t.Fatal(err)
+	}
+	var intents []entities.BookingIntent
+	if err := m.db.Where("event_id = ? AND status = ?", m.event.ID, constants.IntentStatusPending).Find(&intents).Error; err != nil {
+		m.t.Fatal(err)
+	}
+
+	sold := make(map[uint]int)
+	for _, booking := range bookings {
+		sold[booking.SeatID]++
+		if booking.TotalAmount < 0 || booking.DiscountAmount < 0 {
+			m.t.Errorf("booking %d: total %v, discount %v", booking.ID, booking.TotalAmount, booking.DiscountAmount)
+		}
+		if model := m.bookings[booking.ID]; model == nil || model.status != constants.BookingStatusConfirmed {
+			m.t.Errorf("booking %d is confirmed, the model disagrees", booking.ID)
+		}
+	}
+	held := make(map[uint]int)
+	for _, intent := range intents {
+		held[intent.SeatID]++
+		if model := m.intents[intent.ID]; model == nil || model.status != constants.IntentStatusPending {
+			m.t.Errorf("intent %d is pending, the model disagrees", intent.ID)
+		}
+	}
+
+	for _, seat := range seats {
+		switch {
+		case sold[seat.ID] > 1:
+			m.t.Errorf("seat %d is sold %d times", seat.ID, sold[seat.ID])
+		case held[seat.ID] > 1:
+			m.t.Errorf("seat %d is held by %d pending intents", seat.ID, held[seat.ID])
+		case sold[seat.ID] == 1 && held[seat.ID] == 1:
+			m.t.Errorf("seat %d is both sold and held", seat.ID)
+		case seat.IsAvailable != (sold[seat.ID] == 0):
+			m.t.Errorf("seat %d: is_available %v with %d bookings", seat.ID, seat.IsAvailable, sold[seat.ID])
+		case seat.IsLocked != (held[seat.ID] == 1):
+			m.t.Errorf("seat %d: is_locked %v with %d pending intents", seat.ID, seat.IsLocked, held[seat.ID])
+		}
+	}
+	for _, booking := range bookings {
+		for _, seat := range seats {
+			if seat.ID == booking.SeatID && booking.TotalAmount+booking.DiscountAmount != seat.Price {
+				m.t.Errorf("booking %d: total %v and discount %v don't add up to the seat price %v",
+					booking.ID, booking.TotalAmount, booking.DiscountAmount, seat.Price)
+			}
+		}
+	}
+
+	// Conservation: the seats not sold are exactly the event's available seats
+	var event entities.Event
+	if err := m.db.Select("id", "available_seats").First(&event, m.event.ID).Error; err != nil {
+		m.t.Fatal(err)
+	}
+	if want := len(seats) - len(bookings); event.AvailableSeats != want || event.AvailableSeats < 0 {
+		m.t.Errorf("available_seats = %d, want %d", event.AvailableSeats, want)
+	}
+
+	var negative int64
+	if err := m.db.Model(&entities.User{}).Where("id IN ? AND loyalty_points < 0", m.users).Count(&negative).Error; err != nil {
+		m.t.Fatal(err)
+	}
+	if negative > 0 {
+		m.t.Errorf("%d users have a negative loyalty balance", negative)
+	}
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/repository"
+	"api/pkg/errors"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type BoxOfficeService struct {
+	boxOfficeRepo *repository.BoxOfficeRepository
+	seatLocks     repository.SeatLockRepository
+}
+
+// Ensure BoxOfficeService implements BoxOfficeServiceInterface
+var _ BoxOfficeServiceInterface = (*BoxOfficeService)(nil)
+
+func NewBoxOfficeService(boxOfficeRepo *repository.BoxOfficeRepository, seatLocks repository.SeatLockRepository) *BoxOfficeService {
+	return &BoxOfficeService{
+		boxOfficeRepo: boxOfficeRepo,
+		seatLocks:     seatLocks,
+	}
+}
+
+// SellAtDoor sells a seat at the box office. Seats held in Redis by online checkouts aren't
+// sold; in degraded mode those checkouts hold their seats in the database instead.
+func (s *BoxOfficeService) SellAtDoor(ctx context.Context, eventID uint, sale entities.DoorSale) (*entities.Booking, error) {
+	locks, err := s.seatLocks.ListLocks(ctx, eventID)
+	if err == nil {
+		if _, locked := locks[sale.SeatID]; sale.SeatID != 0 && locked {
+			return nil, errors.NewConflictError(constants.ErrSeatAlreadyLocked, nil)
+		}
+		for seatID := range locks {
+			sale.ExcludeSeatIDs = append(sale.ExcludeSeatIDs, seatID)
+		}
+	}
+
+	return s.boxOfficeRepo.SellAtDoor(ctx, eventID, sale, time.Now())
+}
+
+// IssueComps issues complimentary tickets for specific seats. Seats being checked out online
+// aren't comped.
+func (s *BoxOfficeService) IssueComps(ctx context.Context, eventID uint, comp entities.CompIssue) ([]entities.Booking, error) {
+	seen := make(map[uint]bool, len(comp.SeatIDs))
+	seatIDs := make([]uint, 0, len(comp.SeatIDs))
+	for _, seatID := range comp.SeatIDs {
+		if !seen[seatID] {
+			seen[seatID] = true
+			seatIDs = append(seatIDs, seatID)
+		}
+	}
+	comp.SeatIDs = seatIDs
+	comp.Reason = strings.TrimSpace(comp.Reason)
+	comp.AttendeeName = strings.Join(strings.Fields(comp.AttendeeName), " ")
+	if comp.Reason == "" {
+		return nil, errors.NewBadRequestError("A comp reason is required", nil)
+	}
+
+	if locks, err := s.seatLocks.ListLocks(ctx, eventID); err == nil {
+		for _, seatID := range seatIDs {
+			if _, locked := locks[seatID]; locked {
+				return nil, errors.NewConflictError(fmt.Sprintf("seat %d is locked by another user", seatID), nil)
+			}
+		}
+	}
+
+	return s.boxOfficeRepo.IssueComps(ctx, eventID, comp, time.Now())
+}
+
+// ListComps returns the complimentary tickets issued for an event
+func (s *BoxOfficeService) ListComps(ctx context.Context, eventID uint) ([]entities.Booking, error) {
+	return s.boxOfficeRepo.ListComps(ctx, eventID)
+}
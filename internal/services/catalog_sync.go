@@ -0,0 +1,412 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/catalog"
+	"api/internal/entities"
+	"api/internal/repository"
+	"api/internal/tenant"
+	"api/pkg/errors"
+	logger "api/pkg/logging"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// catalogRecentRuns is how many syncs the sync status lists
+const catalogRecentRuns = 10
+
+// CatalogSyncService periodically syncs a partner's catalog feed of venues and events into a
+// tenant. Feed entries are keyed by the source name and the partner's ID, so a sync creates
+// what is new, updates what changed and leaves the rest untouched; running it again changes
+// nothing. Entries are validated with the CSV importer's rules, and invalid ones are left out
+// and reported. Venues and events dropped from the feed are kept.
+type CatalogSyncService struct {
+	catalogRepo *repository.CatalogRepository
+	importRepo  *repository.ImportRepository
+	uow         repository.UnitOfWork
+	fetcher     *catalog.Fetcher // nil when no feed is configured
+	source      string
+	tenantID    uint
+	interval    time.Duration
+	running     sync.Mutex
+	now         func() time.Time
+}
+
+// Ensure CatalogSyncService implements CatalogSyncServiceInterface
+var _ CatalogSyncServiceInterface = (*CatalogSyncService)(nil)
+
+func NewCatalogSyncService(catalogRepo *repository.CatalogRepository, importRepo *repository.ImportRepository, uow repository.UnitOfWork,
+	fetcher *catalog.Fetcher, source string, tenantID uint, interval time.Duration) *CatalogSyncService {
+	if tenantID == 0 {
+		tenantID = tenant.DefaultID
+	}
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &CatalogSyncService{
+		catalogRepo: catalogRepo,
+		importRepo:  importRepo,
+		uow:         uow,
+		fetcher:     fetcher,
+		source:      source,
+		tenantID:    tenantID,
+		interval:    interval,
+		now:         time.Now,
+	}
+}
+
+// Enabled reports whether a feed is configured
+func (s *CatalogSyncService) Enabled() bool {
+	return s.fetcher != nil
+}
+
+// Interval is how often the scheduler syncs the feed
+func (s *CatalogSyncService) Interval() time.Duration {
+	return s.interval
+}
+
+// Sync syncs the feed, for the scheduler
+func (s *CatalogSyncService) Sync(ctx context.Context) error {
+	_, err := s.RunSync(ctx)
+	return err
+}
+
+// RunSync fetches the feed and applies it in a single transaction, then records the run. A
+// failed run is recorded too and returned along with the error.
+func (s *CatalogSyncService) RunSync(ctx context.Context) (*entities.CatalogSyncRun, error) {
+	if s.fetcher == nil {
+		return nil, errors.NewBadRequestError("No catalog feed is configured", nil)
+	}
+	// Syncs on other instances wait for the advisory lock instead
+	if !s.running.TryLock() {
+		return nil, errors.NewConflictError("A catalog sync is already running", nil)
+	}
+	defer s.running.Unlock()
+
+	ctx = tenant.WithTenant(ctx, s.tenantID)
+	run := &entities.CatalogSyncRun{TenantID: s.tenantID, Source: s.source, StartedAt: s.now()}
+
+	feed, err := s.fetcher.Fetch(ctx)
+	if err == nil {
+		err = s.uow.Do(ctx, func(ctx context.Context) error {
+			if err := s.catalogRepo.LockSync(ctx); err != nil {
+				return err
+			}
+			return s.apply(ctx, feed, run)
+		})
+	}
+
+	if err != nil {
+		// Nothing of a failed sync was applied
+		run = &entities.CatalogSyncRun{TenantID: s.tenantID, Source: s.source, StartedAt: run.StartedAt, Error: err.Error()}
+		run.Status = constants.CatalogSyncFailed
+	} else {
+		run.Status = constants.CatalogSyncSucceeded
+	}
+	run.FinishedAt = s.now()
+
+	if recordErr := s.catalogRepo.CreateRun(context.WithoutCancel(ctx), run); recordErr != nil {
+		logger.Errorf("Failed to record catalog sync: %v", recordErr)
+	}
+	if err != nil {
+		return run, fmt.Errorf("catalog sync failed: %w", err)
+	}
+	return run, nil
+}
+
+// GetStatus returns the sync configuration with its latest runs
+func (s *CatalogSyncService) GetStatus(ctx context.Context) (*entities.CatalogSyncStatus, error) {
+	runs, err := s.catalogRepo.ListRuns(ctx, s.source, catalogRecentRuns)
+	if err != nil {
+		return nil, err
+	}
+	lastSuccess, err := s.catalogRepo.LastSuccessfulRun(ctx, s.source)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.CatalogSyncStatus{
+		Enabled:     s.fetcher != nil,
+		Source:      s.source,
+		TenantID:    s.tenantID,
+		Interval:    s.interval,
+		LastSuccess: lastSuccess,
+		Runs:        runs,
+	}, nil
+}
+
+// rejectFunc reports a feed entry left out of the sync
+type rejectFunc func(kind, externalID, field, message string)
+
+// apply syncs the venues, then the events, which may be at venues created by this sync
+func (s *CatalogSyncService) apply(ctx context.Context, feed *catalog.Feed, run *entities.CatalogSyncRun) error {
+	reject := func(kind, externalID, field, message string) {
+		run.Rejected = append(run.Rejected, entities.CatalogSyncError{Kind: kind, ExternalID: externalID, Field: field, Message: message})
+	}
+
+	venueIDs, err := s.applyVenues(ctx, feed.Venues, run, reject)
+	if err != nil {
+		return err
+	}
+	return s.applyEvents(ctx, feed.Events, venueIDs, run, reject)
+}
+
+// applyVenues syncs the feed's venues and returns the local IDs of those synced, by feed ID
+func (s *CatalogSyncService) applyVenues(ctx context.Context, feedVenues []catalog.Venue, run *entities.CatalogSyncRun, reject rejectFunc) (map[string]uint, error) {
+	venueIDs := make(map[string]uint)
+	var toApply, adopted []*entities.Venue
+	seen := make(map[string]bool)
+
+	for i := range feedVenues {
+		feedVenue := &feedVenues[i]
+		externalID := strings.TrimSpace(feedVenue.ExternalID)
+		switch {
+		case externalID == "":
+			reject(constants.CatalogKindVenue, "", "external_id", "is required")
+			continue
+		case seen[externalID]:
+			reject(constants.CatalogKindVenue, externalID, "external_id", "is listed more than once")
+			continue
+		case len(feedVenue.Sections) == 0:
+			reject(constants.CatalogKindVenue, externalID, "sections", "at least one section is required")
+			continue
+		}
+		seen[externalID] = true
+
+		venue, rowErrs := buildVenue(feedVenueRecords(feedVenue))
+		if len(rowErrs) > 0 {
+			for _, rowErr := range rowErrs {
+				reject(constants.CatalogKindVenue, externalID, rowErr.Field, rowErr.Message)
+			}
+			continue
+		}
+		venue.ExternalSource, venue.ExternalID = s.source, externalID
+
+		existing, err := s.catalogRepo.FindVenueByExternalID(ctx, s.source, externalID)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			// Adopt a venue created before the feed was synced
+			if existing, err = s.importRepo.FindVenueByNameAndCity(ctx, venue.Name, venue.City); err != nil {
+				return nil, err
+			}
+			if existing != nil && existing.ExternalID != "" {
+				reject(constants.CatalogKindVenue, externalID, "name", "a venue with this name and city is synced from another feed entry")
+				continue
+			}
+		}
+
+		if existing == nil {
+			run.VenuesCreated++
+			toApply = append(toApply, venue)
+			continue
+		}
+
+		venue.ID = existing.ID
+		fields := diffVenue(existing, venue)
+		layoutErrs, err := layoutChangeErrors(ctx, s.importRepo, existing.ID, 0, fields)
+		if err != nil {
+			return nil, err
+		}
+		if len(layoutErrs) > 0 {
+			for _, layoutErr := range layoutErrs {
+				reject(constants.CatalogKindVenue, externalID, layoutErr.Field, layoutErr.Message)
+			}
+			continue
+		}
+		linked := existing.ExternalID != ""
+		switch {
+		case len(fields) == 0 && linked:
+			run.VenuesUnchanged++
+			venueIDs[externalID] = existing.ID
+			continue
+		case len(fields) > 0:
+			toApply = append(toApply, venue)
+		}
+		if !linked {
+			adopted = append(adopted, venue)
+		}
+		run.VenuesUpdated++
+		venueIDs[externalID] = existing.ID
+	}
+
+	if err := s.importRepo.ApplyVenueImport(ctx, toApply); err != nil {
+		return nil, err
+	}
+	for _, venue := range adopted {
+		if err := s.catalogRepo.LinkVenue(ctx, venue); err != nil {
+			return nil, err
+		}
+	}
+	for _, venue := range toApply {
+		venueIDs[venue.ExternalID] = venue.ID
+	}
+
+	return venueIDs, nil
+}
+
+// applyEvents syncs the feed's events. Events that have started are left as they are.
+func (s *CatalogSyncService) applyEvents(ctx context.Context, feedEvents []catalog.Event, venueIDs map[string]uint, run *entities.CatalogSyncRun, reject rejectFunc) error {
+	now := s.now()
+	var accepted, toApply, updated []*entities.Event
+	seen := make(map[string]bool)
+
+	for i := range feedEvents {
+		feedEvent := &feedEvents[i]
+		externalID := strings.TrimSpace(feedEvent.ExternalID)
+		switch {
+		case externalID == "":
+			reject(constants.CatalogKindEvent, "", "external_id", "is required")
+			continue
+		case seen[externalID]:
+			reject(constants.CatalogKindEvent, externalID, "external_id", "is listed more than once")
+			continue
+		}
+		seen[externalID] = true
+
+		event, rowErrs := buildEvent(feedEventRecord(feedEvent))
+		if !event.StartTime.IsZero() && !event.StartTime.After(now) {
+			run.EventsSkipped++
+			continue
+		}
+		if len(rowErrs) > 0 {
+			for _, rowErr := range rowErrs {
+				reject(constants.CatalogKindEvent, externalID, rowErr.Field, rowErr.Message)
+			}
+			continue
+		}
+		event.ExternalSource, event.ExternalID = s.source, externalID
+
+		// The venue may have been synced earlier, or rejected this time
+		venueExternalID := strings.TrimSpace(feedEvent.VenueExternalID)
+		venueID, ok := venueIDs[venueExternalID]
+		if !ok && venueExternalID != "" {
+			venue, err := s.catalogRepo.FindVenueByExternalID(ctx, s.source, venueExternalID)
+			if err != nil {
+				return err
+			}
+			if venue != nil {
+				venueID = venue.ID
+			}
+		}
+		if venueID == 0 {
+			reject(constants.CatalogKindEvent, externalID, "venue_external_id", "venue not found")
+			continue
+		}
+		event.VenueID = venueID
+
+		existing, err := s.catalogRepo.FindEventByExternalID(ctx, s.source, externalID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			// Adopt an event created before the feed was synced
+			if existing, err = s.importRepo.FindEvent(ctx, event.VenueID, event.Name, event.StartTime); err != nil {
+				return err
+			}
+			if existing != nil && existing.ExternalID != "" {
+				reject(constants.CatalogKindEvent, externalID, "name", "an event with this name and start time is synced from another feed entry")
+				continue
+			}
+		}
+		if existing != nil && existing.VenueID != event.VenueID {
+			reject(constants.CatalogKindEvent, externalID, "venue_external_id", "events can't be moved to another venue")
+			continue
+		}
+
+		var excludeID uint
+		if existing != nil {
+			excludeID = existing.ID
+		}
+		conflict, err := s.importRepo.HasVenueTimeConflict(ctx, event.VenueID, event.StartTime, event.EndTime, excludeID)
+		if err != nil {
+			return err
+		}
+		if !conflict {
+			for _, other := range accepted {
+				if other.VenueID == event.VenueID && event.StartTime.Before(other.EndTime) && other.StartTime.Before(event.EndTime) {
+					conflict = true
+					break
+				}
+			}
+		}
+		if conflict {
+			reject(constants.CatalogKindEvent, externalID, "start_time", constants.ErrVenueTimeConflict)
+			continue
+		}
+		accepted = append(accepted, event)
+
+		if existing == nil {
+			run.EventsCreated++
+			toApply = append(toApply, event)
+			continue
+		}
+
+		event.ID = existing.ID
+		fields := diffEvent(existing, event)
+		diffField(fields, "name", existing.Name, event.Name)
+		diffField(fields, "start_time", existing.StartTime.UTC().Format(time.RFC3339), event.StartTime.UTC().Format(time.RFC3339))
+		if len(fields) == 0 && existing.ExternalID != "" {
+			run.EventsUnchanged++
+			continue
+		}
+		run.EventsUpdated++
+		toApply = append(toApply, event)
+		updated = append(updated, event)
+	}
+
+	if err := s.importRepo.ApplyEventImport(ctx, toApply); err != nil {
+		return err
+	}
+	for _, event := range updated {
+		if err := s.catalogRepo.UpdateEventKeys(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// feedVenueRecords renders a feed venue as the rows of a venue CSV import, one per section
+func feedVenueRecords(venue *catalog.Venue) []csvRecord {
+	records := make([]csvRecord, len(venue.Sections))
+	for i, section := range venue.Sections {
+		multiplier := ""
+		if section.PriceMultiplier != 0 {
+			multiplier = strconv.FormatFloat(section.PriceMultiplier, 'f', -1, 64)
+		}
+		records[i] = csvRecord{values: map[string]string{
+			"venue_name":       venue.Name,
+			"address":          venue.Address,
+			"city":             venue.City,
+			"state":            venue.State,
+			"country":          venue.Country,
+			"columns":          strconv.Itoa(venue.Columns),
+			"description":      venue.Description,
+			"section":          section.Name,
+			"row_start":        strconv.Itoa(section.RowStart),
+			"row_end":          strconv.Itoa(section.RowEnd),
+			"seat_type":        section.SeatType,
+			"price_multiplier": multiplier,
+		}}
+	}
+	return records
+}
+
+// feedEventRecord renders a feed event as a row of an event CSV import
+func feedEventRecord(event *catalog.Event) csvRecord {
+	return csvRecord{values: map[string]string{
+		"name":           event.Name,
+		"description":    event.Description,
+		"start_time":     event.StartTime,
+		"end_time":       event.EndTime,
+		"price":          strconv.FormatFloat(event.Price, 'f', -1, 64),
+		"event_type":     event.EventType,
+		"is_high_demand": strconv.FormatBool(event.IsHighDemand),
+	}}
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/domain"
+	"api/internal/entities"
+	"api/internal/metrics"
+	"api/internal/notifications"
+	"api/internal/repository"
+	"api/pkg/errors"
+	logger "api/pkg/logging"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+var deadLetters = metrics.Default.NewCounterVec("dead_letters_total",
+	"Async work that failed and was kept as a dead letter, by source.", "source")
+
+// RetryFunc runs a dead letter's work again
+type RetryFunc func(ctx context.Context, letter *entities.DeadLetter) error
+
+// DeadLetterService keeps async work that failed, so platform admins can see it and retry or
+// discard it. Sources register how their work is retried; the work wrapped by Notifier,
+// AdminChannel and WatchDispatcher is kept when it fails.
+type DeadLetterService struct {
+	repo    *repository.DeadLetterRepository
+	retries map[string]RetryFunc
+}
+
+// Ensure DeadLetterService implements DeadLetterServiceInterface
+var _ DeadLetterServiceInterface = (*DeadLetterService)(nil)
+
+func NewDeadLetterService(repo *repository.DeadLetterRepository) *DeadLetterService {
+	return &DeadLetterService{
+		repo:    repo,
+		retries: make(map[string]RetryFunc),
+	}
+}
+
+// Register sets how dead letters of a source are retried. Sources must be registered before
+// the console is served.
+func (s *DeadLetterService) Register(source string, retry RetryFunc) {
+	s.retries[source] = retry
+}
+
+// Record keeps failed work with its JSON encoded payload. It returns an error only if the work
+// couldn't be kept, in which case the caller still owns the failure.
+func (s *DeadLetterService) Record(ctx context.Context, source, handler, kind string, payload interface{}, cause error) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode dead letter: %w", err)
+	}
+	letter := &entities.DeadLetter{
+		Source:  source,
+		Handler: handler,
+		Kind:    kind,
+		Payload: string(data),
+		Error:   cause.Error(),
+	}
+	// Kept even if the request that failed was cancelled meanwhile
+	if err := s.repo.Create(context.WithoutCancel(ctx), letter); err != nil {
+		return err
+	}
+	deadLetters.Inc(source)
+	logger.Warnf("Dead letter %d: %s %s (%s) failed: %v", letter.ID, source, kind, handler, cause)
+	return nil
+}
+
+func (s *DeadLetterService) ListDeadLetters(ctx context.Context, source, status string, limit, offset int) ([]entities.DeadLetter, int64, error) {
+	return s.repo.List(ctx, source, status, limit, offset)
+}
+
+func (s *DeadLetterService) GetDeadLetter(ctx context.Context, letterID uint) (*entities.DeadLetter, error) {
+	return s.repo.GetByID(ctx, letterID)
+}
+
+// RetryDeadLetter runs a dead letter's work again. A successful retry resolves the letter; a
+// failed one is counted and the letter stays dead with the new error.
+func (s *DeadLetterService) RetryDeadLetter(ctx context.Context, letterID uint) (*entities.DeadLetter, error) {
+	letter, err := s.repo.GetByID(ctx, letterID)
+	if err != nil {
+		return nil, err
+	}
+	if letter.Status != constants.DeadLetterStatusDead {
+		return nil, errors.NewConflictError("Dead letter was already retried or discarded", nil)
+	}
+	retry, ok := s.retries[letter.Source]
+	if !ok {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("Dead letters from %s can't be retried", letter.Source), nil)
+	}
+
+	if err := retry(ctx, letter); err != nil {
+		logger.Warnf("Retry of dead letter %d failed: %v", letter.ID, err)
+		return s.repo.RecordRetryFailure(ctx, letter.ID, err.Error())
+	}
+	return s.repo.Resolve(ctx, letter.ID, constants.DeadLetterStatusRetried, true)
+}
+
+// DiscardDeadLetter drops a dead letter's work for good
+func (s *DeadLetterService) DiscardDeadLetter(ctx context.Context, letterID uint) (*entities.DeadLetter, error) {
+	return s.repo.Resolve(ctx, letterID, constants.DeadLetterStatusDiscarded, false)
+}
+
+// Notifier wraps a notifier so notifications that fail to send are kept as dead letters.
+// Callers see them as sent once kept, since retrying them is up to the console.
+func (s *DeadLetterService) Notifier(next notifications.Notifier) notifications.Notifier {
+	s.Register(constants.DeadLetterSourceNotification, func(ctx context.Context, letter *entities.DeadLetter) error {
+		var notification notifications.Notification
+		if err := json.Unmarshal([]byte(letter.Payload), &notification); err != nil {
+			return fmt.Errorf("failed to decode notification: %w", err)
+		}
+		return next.Send(ctx, notification)
+	})
+	return &deadLetterNotifier{next: next, deadLetters: s}
+}
+
+type deadLetterNotifier struct {
+	next        notifications.Notifier
+	deadLetters *DeadLetterService
+}
+
+func (n *deadLetterNotifier) Send(ctx context.Context, notification notifications.Notification) error {
+	err := n.next.Send(ctx, notification)
+	if err == nil {
+		return nil
+	}
+	if recordErr := n.deadLetters.Record(ctx, constants.DeadLetterSourceNotification, "notifier", notification.Type, notification, err); recordErr != nil {
+		logger.Errorf("Failed to keep undelivered %s notification: %v", notification.Type, recordErr)
+		return err
+	}
+	return nil
+}
+
+// AdminChannel wraps the admins' channel so posts that fail are kept as dead letters
+func (s *DeadLetterService) AdminChannel(next notifications.AdminChannel) notifications.AdminChannel {
+	s.Register(constants.DeadLetterSourceAdminNotification, func(ctx context.Context, letter *entities.DeadLetter) error {
+		var event notifications.AdminEvent
+		if err := json.Unmarshal([]byte(letter.Payload), &event); err != nil {
+			return fmt.Errorf("failed to decode admin event: %w", err)
+		}
+		return next.Notify(ctx, event)
+	})
+	return &deadLetterAdminChannel{next: next, deadLetters: s}
+}
+
+type deadLetterAdminChannel struct {
+	next        notifications.AdminChannel
+	deadLetters *DeadLetterService
+}
+
+func (c *deadLetterAdminChannel) Notify(ctx context.Context, event notifications.AdminEvent) error {
+	err := c.next.Notify(ctx, event)
+	if err == nil {
+		return nil
+	}
+	if recordErr := c.deadLetters.Record(ctx, constants.DeadLetterSourceAdminNotification, "admin_channel", event.Kind, event, err); recordErr != nil {
+		logger.Errorf("Failed to keep undelivered %s admin event: %v", event.Kind, recordErr)
+		return err
+	}
+	return nil
+}
+
+// WatchDispatcher keeps the domain events a consumer failed to handle. Retrying hands the event
+// back to that consumer only.
+func (s *DeadLetterService) WatchDispatcher(events *domain.Dispatcher) {
+	s.Register(constants.DeadLetterSourceDomainEvent, func(ctx context.Context, letter *entities.DeadLetter) error {
+		return events.Redeliver(ctx, letter.Handler, letter.Kind, []byte(letter.Payload))
+	})
+	events.OnFailure(func(ctx context.Context, consumer string, event domain.Event, err error) {
+		if recordErr := s.Record(ctx, constants.DeadLetterSourceDomainEvent, consumer, event.Name(), event, err); recordErr != nil {
+			logger.Errorf("Failed to keep %s event unhandled by %s: %v", event.Name(), consumer, recordErr)
+		}
+	})
+}
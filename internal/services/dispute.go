@@ -0,0 +1,84 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/notifications"
+	"api/internal/repository"
+	logger "api/pkg/logging"
+	"context"
+	"fmt"
+)
+
+type DisputeService struct {
+	disputeRepo   *repository.DisputeRepository
+	userRepo      repository.UserRepository
+	notifier      notifications.Notifier
+	revokeTickets bool
+}
+
+// Ensure DisputeService implements DisputeServiceInterface
+var _ DisputeServiceInterface = (*DisputeService)(nil)
+
+func NewDisputeService(disputeRepo *repository.DisputeRepository, userRepo repository.UserRepository, notifier notifications.Notifier, revokeTickets bool) *DisputeService {
+	return &DisputeService{
+		disputeRepo:   disputeRepo,
+		userRepo:      userRepo,
+		notifier:      notifier,
+		revokeTickets: revokeTickets,
+	}
+}
+
+// HandleDisputeUpdate records a dispute webhook. Newly opened disputes notify admins and,
+// when configured, revoke the booking's ticket.
+func (s *DisputeService) HandleDisputeUpdate(ctx context.Context, update entities.DisputeUpdate) (*entities.Dispute, error) {
+	dispute, opened, changed, err := s.disputeRepo.RecordDisputeUpdate(ctx, update)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return dispute, nil
+	}
+	logger.Infof("Dispute %s for booking %d is now %s", dispute.ProviderDisputeID, dispute.BookingID, update.Status)
+
+	if !opened {
+		return dispute, nil
+	}
+
+	if s.revokeTickets {
+		if err := s.disputeRepo.RevokeTicket(ctx, dispute.BookingID); err != nil {
+			return nil, err
+		}
+	}
+
+	s.notifyAdmins(ctx, dispute)
+	return dispute, nil
+}
+
+// ListDisputes returns disputes for reporting
+func (s *DisputeService) ListDisputes(ctx context.Context, status string, limit, offset int) ([]entities.Dispute, int64, error) {
+	return s.disputeRepo.ListDisputes(ctx, status, limit, offset)
+}
+
+func (s *DisputeService) notifyAdmins(ctx context.Context, dispute *entities.Dispute) {
+	admins, err := s.userRepo.GetAdmins(ctx)
+	if err != nil {
+		logger.Errorf("Failed to load admins for dispute %s: %v", dispute.ProviderDisputeID, err)
+		return
+	}
+
+	for _, admin := range admins {
+		notification := notifications.Notification{
+			Type:      constants.NotificationTypeDisputeOpened,
+			UserID:    admin.ID,
+			Recipient: admin.Email,
+			BookingID: dispute.BookingID,
+			Subject:   fmt.Sprintf("Payment dispute opened for booking %d", dispute.BookingID),
+			Message: fmt.Sprintf("Dispute %s was opened against payment %s (amount %s, reason: %s).",
+				dispute.ProviderDisputeID, dispute.PaymentID, dispute.Amount, dispute.Reason),
+		}
+		if err := s.notifier.Send(ctx, notification); err != nil {
+			logger.Warnf("Failed to notify admin %d of dispute %s: %v", admin.ID, dispute.ProviderDisputeID, err)
+		}
+	}
+}
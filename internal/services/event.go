@@ -1,50 +1,341 @@
 package services
 
 import (
+	"api/constants"
+	"api/internal/domain"
 	"api/internal/entities"
+	"api/internal/expand"
 	"api/internal/repository"
+	"api/internal/tasks"
+	"api/internal/tenant"
+	"api/pkg/errors"
+	logger "api/pkg/logging"
 	"context"
+	"fmt"
+	"time"
 )
 
+// maxWarmedEvents caps the events one cache warm-up loads
+const maxWarmedEvents = 100
+
 type EventService struct {
-	eventRepo *repository.EventRepository
+	eventRepo     repository.EventRepository
+	taskQueue     *tasks.Queue
+	cache         *repository.EventCacheRepository // nil serves every read from the database
+	preheatWindow time.Duration
+	availability  *AvailabilityCounters // nil counts listings' available seats in the database
 }
 
 // GetAvailableSeatsCount implements EventServiceInterface.
 func (s *EventService) GetAvailableSeatsCount(ctx context.Context, eventID uint) (int64, error) {
-	return s.eventRepo.CountAvailableSeats(ctx, eventID)
+	if !s.cacheable(ctx) {
+		return s.eventRepo.CountAvailableSeats(ctx, eventID)
+	}
+	if count, ok := s.cache.GetAvailableCount(ctx, eventID); ok {
+		return count, nil
+	}
+
+	count, err := s.eventRepo.CountAvailableSeats(ctx, eventID)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.cache.SetAvailableCount(ctx, eventID, count); err != nil {
+		logger.Debugf("Event %d not cached: %v", eventID, err)
+	}
+	return count, nil
 }
 
 // Ensure EventService implements EventServiceInterface
 var _ EventServiceInterface = (*EventService)(nil)
 
-func NewEventService(eventRepo *repository.EventRepository) *EventService {
-	return &EventService{eventRepo: eventRepo}
+func NewEventService(eventRepo repository.EventRepository, taskQueue *tasks.Queue) *EventService {
+	return &EventService{eventRepo: eventRepo, taskQueue: taskQueue}
+}
+
+// WithCache serves public event reads from the Redis event cache. Warm-ups preload the events
+// whose on-sale or presale opens within preheatWindow, along with high-demand ones.
+func (s *EventService) WithCache(cache *repository.EventCacheRepository, preheatWindow time.Duration) *EventService {
+	s.cache = cache
+	s.preheatWindow = preheatWindow
+	return s
+}
+
+// WithAvailabilityCounters serves listings' available seat counts from Redis counters
+func (s *EventService) WithAvailabilityCounters(availability *AvailabilityCounters) *EventService {
+	s.availability = availability
+	return s
+}
+
+// GetListingAvailability returns the available seats of listed events, by event ID
+func (s *EventService) GetListingAvailability(ctx context.Context, events []entities.Event) map[uint]int64 {
+	if s.availability != nil {
+		return s.availability.Available(ctx, events)
+	}
+
+	counts := make(map[uint]int64, len(events))
+	for i := range events {
+		// A failed count is shown as 0 rather than failing the listing
+		counts[events[i].ID], _ = s.GetAvailableSeatsCount(ctx, events[i].ID)
+	}
+	return counts
+}
+
+// cacheable reports whether reads made with ctx can use the cache. Only public reads do:
+// tenant-scoped reads must not see other tenants' events.
+func (s *EventService) cacheable(ctx context.Context) bool {
+	if s.cache == nil {
+		return false
+	}
+	_, scoped := tenant.FromContext(ctx)
+	return !scoped
 }
 
 // GetEvents returns a paginated list of events
-func (s *EventService) GetEvents(ctx context.Context, limit, offset int, eventType, city string) ([]entities.Event, int64, error) {
-	return s.eventRepo.GetEvents(ctx, limit, offset, eventType, city)
+func (s *EventService) GetEvents(ctx context.Context, limit, offset int, eventType, city string, metadata map[string]string) ([]entities.Event, int64, error) {
+	return s.eventRepo.GetEvents(ctx, limit, offset, eventType, city, metadata)
 }
 
+// GetEventByID returns an event with the seats on sale, those being checked out included
 func (s *EventService) GetEventByID(ctx context.Context, eventID uint) (*entities.Event, error) {
-	return s.eventRepo.GetEventByID(ctx, eventID)
+	if !s.cacheable(ctx) {
+		return s.eventRepo.GetEventByID(ctx, eventID)
+	}
+	if event := s.cache.GetEvent(ctx, eventID); event != nil {
+		if !expand.Includes(ctx, expand.Seats) {
+			return event, nil
+		}
+		if seats, ok := s.cache.GetSeats(ctx, eventID); ok {
+			event.Seats = seats
+			return event, nil
+		}
+	}
+	if _, partial := expand.FromContext(ctx); partial {
+		// Only whole events are cached
+		return s.eventRepo.GetEventByID(ctx, eventID)
+	}
+
+	event, err := s.loadIntoCache(ctx, eventID)
+	if event == nil {
+		return nil, err
+	}
+	if err != nil {
+		logger.Debugf("Event %d not cached: %v", eventID, err)
+	}
+	return event, nil
+}
+
+func (s *EventService) GetAvailableSeats(ctx context.Context, eventID uint, filter entities.SeatFilter) ([]entities.Seat, error) {
+	// The cached seat map answers unfiltered reads: its seats that aren't being checked out
+	if s.cacheable(ctx) && filter.Accessible == nil && filter.Companion == nil {
+		if seats, ok := s.cache.GetSeats(ctx, eventID); ok {
+			available := make([]entities.Seat, 0, len(seats))
+			for _, seat := range seats {
+				if !seat.IsLocked {
+					available = append(available, seat)
+				}
+			}
+			return available, nil
+		}
+	}
+	return s.eventRepo.GetAvailableSeats(ctx, eventID, filter)
+}
+
+// WarmCache preloads events' details, seat maps and available seat counts into the cache, so
+// the first requests of an on-sale don't all reach the database. Without event IDs it warms
+// the hot events: upcoming high-demand events and those opening for sale soon.
+func (s *EventService) WarmCache(ctx context.Context, eventIDs []uint) (*entities.CacheWarmResult, error) {
+	if s.cache == nil {
+		return nil, errors.NewBadRequestError("The event cache is not enabled", nil)
+	}
+	if len(eventIDs) == 0 {
+		var err error
+		if eventIDs, err = s.eventRepo.ListHotEvents(ctx, time.Now().Add(s.preheatWindow), maxWarmedEvents); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &entities.CacheWarmResult{Events: make([]entities.CachedEvent, 0, len(eventIDs)), WarmedAt: time.Now()}
+	for _, eventID := range eventIDs {
+		cached := entities.CachedEvent{EventID: eventID}
+		event, err := s.loadIntoCache(ctx, eventID)
+		if err == nil {
+			cached.Seats = len(event.Seats)
+			cached.AvailableSeats, err = s.eventRepo.CountAvailableSeats(ctx, eventID)
+		}
+		if err == nil {
+			err = s.cache.SetAvailableCount(ctx, eventID, cached.AvailableSeats)
+		}
+		if err != nil {
+			cached.Error = err.Error()
+		}
+		result.Events = append(result.Events, cached)
+	}
+	return result, nil
+}
+
+// PreheatCache warms the hot events, at startup and on a schedule ahead of on-sales
+func (s *EventService) PreheatCache(ctx context.Context) error {
+	if s.cache == nil {
+		return nil
+	}
+	result, err := s.WarmCache(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	var lastErr string
+	for _, event := range result.Events {
+		if event.Error != "" {
+			failed++
+			lastErr = event.Error
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d events not cached: %s", failed, len(result.Events), lastErr)
+	}
+	return nil
+}
+
+// loadIntoCache reads an event with its seat map from the database and caches both. A failed
+// cache write is returned, but the event is loaded anyway.
+func (s *EventService) loadIntoCache(ctx context.Context, eventID uint) (*entities.Event, error) {
+	event, err := s.eventRepo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.SetEvent(ctx, event); err != nil {
+		return event, err
+	}
+	return event, s.cache.SetSeats(ctx, eventID, event.Seats)
+}
+
+// invalidate drops an event from the cache once an admin changed it or its seats
+func (s *EventService) invalidate(ctx context.Context, eventID uint) {
+	if s.cache != nil {
+		s.cache.Invalidate(ctx, eventID)
+	}
+}
+
+// SubscribeCache keeps cached seat maps and counts in step with the booking workflow, dropping
+// them whenever a seat is sold or freed
+func (s *EventService) SubscribeCache(events *domain.Dispatcher) {
+	if s.cache == nil {
+		return
+	}
+	domain.Subscribe(events, "event_cache", func(ctx context.Context, event domain.BookingConfirmed) error {
+		s.cache.InvalidateSeats(ctx, event.EventID)
+		return nil
+	})
+	domain.Subscribe(events, "event_cache", func(ctx context.Context, event domain.SeatReleased) error {
+		s.cache.InvalidateSeats(ctx, event.EventID)
+		return nil
+	})
+}
+
+// ReleaseSeats opens a further block of held rows for sale
+func (s *EventService) ReleaseSeats(ctx context.Context, eventID uint, rowStart, rowEnd int, releasedBy uint) (*entities.SeatRelease, error) {
+	release, err := s.eventRepo.ReleaseSeats(ctx, eventID, rowStart, rowEnd, releasedBy)
+	if err == nil {
+		s.invalidate(ctx, eventID)
+	}
+	return release, err
+}
+
+func (s *EventService) ListReleases(ctx context.Context, eventID uint) ([]entities.SeatRelease, int64, error) {
+	return s.eventRepo.ListReleases(ctx, eventID)
+}
+
+// UpdateSeatPrices reprices an event's seats in bulk on behalf of an admin, recording each
+// change in the seats' price history
+func (s *EventService) UpdateSeatPrices(ctx context.Context, eventID uint, change entities.SeatPriceChange) ([]entities.SeatPriceHistory, error) {
+	change.Source = constants.PriceSourceAdmin
+	change.PricingRuleID = nil
+	history, err := s.eventRepo.UpdateSeatPrices(ctx, eventID, change)
+	if err == nil {
+		s.invalidate(ctx, eventID)
+	}
+	return history, err
 }
 
-func (s *EventService) GetAvailableSeats(ctx context.Context, eventID uint) ([]entities.Seat, error) {
-	return s.eventRepo.GetAvailableSeats(ctx, eventID)
+func (s *EventService) ListSeatPriceHistory(ctx context.Context, seatID uint) ([]entities.SeatPriceHistory, error) {
+	return s.eventRepo.ListSeatPriceHistory(ctx, seatID)
 }
 
-func (s *EventService) CreateEvent(ctx context.Context, event *entities.Event) error {
-	return s.eventRepo.CreateEvent(ctx, event)
+// SetSeatAccessibility designates an accessible seat and its companion seats
+func (s *EventService) SetSeatAccessibility(ctx context.Context, seatID uint, accessible bool, companionSeatIDs []uint) (*entities.Seat, []entities.Seat, error) {
+	seat, companions, err := s.eventRepo.SetSeatAccessibility(ctx, seatID, accessible, companionSeatIDs)
+	if err == nil {
+		s.invalidate(ctx, seat.EventID)
+	}
+	return seat, companions, err
+}
+
+// CreateEvent validates the event and queues its creation, seats included, as a background
+// task that reports seat generation progress and the event ID once it completes
+func (s *EventService) CreateEvent(ctx context.Context, event *entities.Event, createdBy uint) (*entities.Task, error) {
+	venue, err := s.eventRepo.PrepareEvent(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	task := &entities.Task{
+		TenantID:  event.TenantID,
+		Kind:      constants.TaskKindEventCreation,
+		Total:     venue.Rows * venue.Columns,
+		CreatedBy: createdBy,
+	}
+	if err := s.taskQueue.Enqueue(ctx, task, event); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// CreateSandbox queues the creation of a sandbox copy of an event for rehearsing its on-sale.
+// The sandbox opens at the given on-sale times, which default to straight away, instead of
+// the real event's.
+func (s *EventService) CreateSandbox(ctx context.Context, eventID uint, onSaleAt, earlyAccessAt *time.Time, createdBy uint) (*entities.Task, error) {
+	event, err := s.eventRepo.NewSandboxEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := NormalizeEarlyAccess(onSaleAt, earlyAccessAt, ""); err != nil {
+		return nil, err
+	}
+	event.OnSaleAt = onSaleAt
+	event.EarlyAccessAt = earlyAccessAt
+
+	return s.CreateEvent(ctx, event, createdBy)
+}
+
+// RunEventCreation is the task handler that creates a queued event and its seats
+func (s *EventService) RunEventCreation(ctx context.Context, task *entities.Task, progress tasks.ProgressFunc) (*uint, error) {
+	var event entities.Event
+	if err := tasks.DecodePayload(task, &event); err != nil {
+		return nil, err
+	}
+
+	if err := s.eventRepo.CreateEvent(ctx, &event, repository.SeatProgressFunc(progress)); err != nil {
+		return nil, err
+	}
+	return &event.ID, nil
 }
 
 func (s *EventService) UpdateEvent(ctx context.Context, eventID uint, updates map[string]interface{}) (*entities.Event, error) {
-	return s.eventRepo.UpdateEvent(ctx, eventID, updates)
+	event, err := s.eventRepo.UpdateEvent(ctx, eventID, updates)
+	if err == nil {
+		s.invalidate(ctx, eventID)
+	}
+	return event, err
 }
 
 func (s *EventService) DeleteEvent(ctx context.Context, eventID uint) error {
-	return s.eventRepo.DeleteEvent(ctx, eventID)
+	if err := s.eventRepo.DeleteEvent(ctx, eventID); err != nil {
+		return err
+	}
+	s.invalidate(ctx, eventID)
+	return nil
 }
 
 func (s *EventService) GetEventStats(ctx context.Context, eventID uint) (map[string]interface{}, error) {
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/notifications"
+	"api/internal/repository"
+	logger "api/pkg/logging"
+	"context"
+	"fmt"
+	"time"
+)
+
+// EventPostService manages the announcements and FAQs organizers attach to events. New
+// announcements are emailed to the event's booked attendees by SendAnnouncements.
+type EventPostService struct {
+	postRepo *repository.EventPostRepository
+	notifier notifications.Notifier
+}
+
+func NewEventPostService(postRepo *repository.EventPostRepository, notifier notifications.Notifier) *EventPostService {
+	return &EventPostService{
+		postRepo: postRepo,
+		notifier: notifier,
+	}
+}
+
+// CreatePost publishes an announcement or FAQ entry on an event
+func (s *EventPostService) CreatePost(ctx context.Context, post *entities.EventPost) (*entities.EventPost, error) {
+	if err := s.postRepo.CreatePost(ctx, post); err != nil {
+		return nil, err
+	}
+	logger.Infof("Published %s %d on event %d", post.Kind, post.ID, post.EventID)
+	return post, nil
+}
+
+// UpdatePost edits a post. Attendees already emailed an announcement aren't emailed again.
+func (s *EventPostService) UpdatePost(ctx context.Context, postID uint, updates map[string]interface{}) (*entities.EventPost, error) {
+	return s.postRepo.UpdatePost(ctx, postID, updates)
+}
+
+// DeletePost takes a post down
+func (s *EventPostService) DeletePost(ctx context.Context, postID uint) error {
+	_, err := s.postRepo.DeletePost(ctx, postID)
+	return err
+}
+
+// ListPosts returns an event's posts of the given kinds
+func (s *EventPostService) ListPosts(ctx context.Context, eventID uint, kinds ...string) ([]entities.EventPost, error) {
+	return s.postRepo.ListPosts(ctx, eventID, kinds...)
+}
+
+// SendAnnouncements emails new announcements to the booked attendees of their events. Each
+// announcement is claimed before it is sent, so attendees receive it at most once.
+func (s *EventPostService) SendAnnouncements(ctx context.Context) error {
+	posts, err := s.postRepo.GetPendingAnnouncements(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range posts {
+		post := &posts[i]
+		claimed, err := s.postRepo.ClaimAnnouncement(ctx, post.ID, time.Now())
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		bookings, err := s.postRepo.GetAttendees(ctx, post.EventID)
+		if err != nil {
+			return err
+		}
+		for j := range bookings {
+			if err := s.notifier.Send(ctx, announcementNotification(post, &bookings[j])); err != nil {
+				logger.Warnf("Failed to send announcement %d for booking %d: %v", post.ID, bookings[j].ID, err)
+			}
+		}
+		logger.Infof("Announcement %d sent to %d attendees of event %d", post.ID, len(bookings), post.EventID)
+	}
+	return nil
+}
+
+// announcementNotification emails an announcement to the holder of a booking of its event
+func announcementNotification(post *entities.EventPost, booking *entities.Booking) notifications.Notification {
+	return notifications.Notification{
+		Type:      constants.NotificationTypeEventAnnouncement,
+		UserID:    booking.UserID,
+		Recipient: booking.User.Email,
+		EventID:   post.EventID,
+		BookingID: booking.ID,
+		Subject:   fmt.Sprintf("%s: %s", post.Event.Name, post.Title),
+		Message:   post.Body,
+	}
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"testing"
+)
+
+// TestAnnouncementNotification emails an announcement to the holder of each booking, under the
+// event's name
+func TestAnnouncementNotification(t *testing.T) {
+	post := &entities.EventPost{
+		ID:      4,
+		EventID: 9,
+		Event:   entities.Event{ID: 9, Name: "Summer Jam"},
+		Kind:    constants.EventPostKindAnnouncement,
+		Title:   "Gate change",
+		Body:    "Doors are now at Gate C.",
+	}
+	booking := &entities.Booking{ID: 31, UserID: 3, EventID: 9, User: entities.User{Email: "fan@example.com"}}
+
+	notification := announcementNotification(post, booking)
+	if notification.Type != constants.NotificationTypeEventAnnouncement {
+		t.Errorf("type = %q", notification.Type)
+	}
+	if notification.Recipient != "fan@example.com" || notification.UserID != 3 || notification.BookingID != 31 || notification.EventID != 9 {
+		t.Errorf("notification addressed to %+v", notification)
+	}
+	if notification.Subject != "Summer Jam: Gate change" || notification.Message != "Doors are now at Gate C." {
+		t.Errorf("notification says %q: %q", notification.Subject, notification.Message)
+	}
+}
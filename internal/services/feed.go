@@ -0,0 +1,106 @@
+package services
+
+import (
+	"api/internal/entities"
+	"api/internal/repository"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// feedCacheTTL is how long generated sitemaps and feed pages are served from memory
+	feedCacheTTL = 5 * time.Minute
+	// FeedPageSize is the number of events on each feed page
+	FeedPageSize = 50
+	// maxCachedFeedPages caps the feed pages kept in memory; crawlers rarely go further back
+	maxCachedFeedPages = 20
+	// maxSitemapURLs is the most URLs the sitemap protocol allows in one file
+	maxSitemapURLs = 50000
+)
+
+type cachedFeed struct {
+	value    interface{}
+	loadedAt time.Time
+}
+
+// FeedService generates the sitemap and the feed of newly published events. Both are cached
+// for a few minutes, since crawlers and syndication partners poll them.
+type FeedService struct {
+	feedRepo *repository.FeedRepository
+
+	mu    sync.Mutex
+	cache map[string]cachedFeed
+}
+
+// Ensure FeedService implements FeedServiceInterface
+var _ FeedServiceInterface = (*FeedService)(nil)
+
+func NewFeedService(feedRepo *repository.FeedRepository) *FeedService {
+	return &FeedService{feedRepo: feedRepo, cache: make(map[string]cachedFeed)}
+}
+
+// Sitemap lists organizers and listed events that haven't ended, at most 50,000 URLs
+func (s *FeedService) Sitemap(ctx context.Context) (*entities.Sitemap, error) {
+	if cached, ok := s.cached("sitemap"); ok {
+		return cached.(*entities.Sitemap), nil
+	}
+
+	now := time.Now()
+	organizers, err := s.feedRepo.ListOrganizers(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	// One more than fits tells whether the sitemap is truncated
+	events, err := s.feedRepo.ListSitemapEvents(ctx, now, maxSitemapURLs-len(organizers)+1)
+	if err != nil {
+		return nil, err
+	}
+	sitemap := &entities.Sitemap{Organizers: organizers, Events: events}
+	if len(organizers)+len(events) > maxSitemapURLs {
+		sitemap.Events = events[:maxSitemapURLs-len(organizers)]
+		sitemap.Truncated = true
+	}
+
+	s.store("sitemap", sitemap)
+	return sitemap, nil
+}
+
+// EventFeed returns a page of listed events, most recently published first
+func (s *FeedService) EventFeed(ctx context.Context, page int) (*entities.EventFeedPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	key := fmt.Sprintf("events:%d", page)
+	if cached, ok := s.cached(key); ok {
+		return cached.(*entities.EventFeedPage), nil
+	}
+
+	events, total, err := s.feedRepo.ListPublishedEvents(ctx, FeedPageSize, (page-1)*FeedPageSize)
+	if err != nil {
+		return nil, err
+	}
+	feed := &entities.EventFeedPage{Events: events, Page: page, PageSize: FeedPageSize, Total: total}
+
+	if page <= maxCachedFeedPages {
+		s.store(key, feed)
+	}
+	return feed, nil
+}
+
+func (s *FeedService) cached(key string) (interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cached, ok := s.cache[key]
+	if !ok || time.Since(cached.loadedAt) >= feedCacheTTL {
+		return nil, false
+	}
+	return cached.value, true
+}
+
+func (s *FeedService) store(key string, value interface{}) {
+	s.mu.Lock()
+	s.cache[key] = cachedFeed{value: value, loadedAt: time.Now()}
+	s.mu.Unlock()
+}
@@ -0,0 +1,516 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/repository"
+	"api/pkg/errors"
+	"api/pkg/money"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expected CSV columns. Header order does not matter, but every column must be present.
+var (
+	venueImportColumns = []string{"venue_name", "address", "city", "state", "country", "columns", "description", "section", "row_start", "row_end", "seat_type", "price_multiplier"}
+	eventImportColumns = []string{"name", "description", "venue_name", "venue_city", "start_time", "end_time", "price", "event_type", "is_high_demand"}
+)
+
+type ImportService struct {
+	importRepo *repository.ImportRepository
+}
+
+// Ensure ImportService implements ImportServiceInterface
+var _ ImportServiceInterface = (*ImportService)(nil)
+
+func NewImportService(importRepo *repository.ImportRepository) *ImportService {
+	return &ImportService{importRepo: importRepo}
+}
+
+// csvRecord is a single parsed CSV row keyed by column name
+type csvRecord struct {
+	line   int
+	values map[string]string
+}
+
+func (r csvRecord) get(column string) string {
+	return strings.TrimSpace(r.values[column])
+}
+
+// venueImport groups the CSV rows describing one venue
+type venueImport struct {
+	line    int
+	key     string
+	venue   *entities.Venue
+	records []csvRecord
+}
+
+// ImportVenues parses a venue seat map CSV (one row per section) and creates or updates venues.
+// With dryRun set, the diff is returned without touching the database.
+func (s *ImportService) ImportVenues(ctx context.Context, r io.Reader, dryRun bool) (*entities.ImportResult, error) {
+	records, err := readCSV(r, venueImportColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entities.ImportResult{Kind: constants.ImportKindVenues, DryRun: dryRun, Changes: []entities.ImportChange{}}
+
+	// Group section rows by venue, preserving file order
+	var imports []*venueImport
+	byKey := make(map[string]*venueImport)
+	for _, rec := range records {
+		name, city := rec.get("venue_name"), rec.get("city")
+		key := strings.ToLower(name) + "|" + strings.ToLower(city)
+
+		imp, ok := byKey[key]
+		if !ok {
+			imp = &venueImport{line: rec.line, key: name + " (" + city + ")"}
+			byKey[key] = imp
+			imports = append(imports, imp)
+		}
+		imp.records = append(imp.records, rec)
+	}
+
+	for _, imp := range imports {
+		venue, rowErrs := buildVenue(imp.records)
+		if len(rowErrs) > 0 {
+			result.Errors = append(result.Errors, rowErrs...)
+			continue
+		}
+		imp.venue = venue
+	}
+	if len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	var toApply []*entities.Venue
+	for _, imp := range imports {
+		existing, err := s.importRepo.FindVenueByNameAndCity(ctx, imp.venue.Name, imp.venue.City)
+		if err != nil {
+			return nil, err
+		}
+
+		change := entities.ImportChange{Line: imp.line, Key: imp.key}
+		if existing == nil {
+			change.Action = constants.ImportActionCreate
+			result.Created++
+			toApply = append(toApply, imp.venue)
+		} else {
+			change.Fields = diffVenue(existing, imp.venue)
+			layoutErrs, err := layoutChangeErrors(ctx, s.importRepo, existing.ID, imp.line, change.Fields)
+			if err != nil {
+				return nil, err
+			}
+			if len(layoutErrs) > 0 {
+				result.Errors = append(result.Errors, layoutErrs...)
+				continue
+			}
+			if len(change.Fields) == 0 {
+				change.Action = constants.ImportActionUnchanged
+				result.Unchanged++
+			} else {
+				change.Action = constants.ImportActionUpdate
+				result.Updated++
+				imp.venue.ID = existing.ID
+				toApply = append(toApply, imp.venue)
+			}
+		}
+		result.Changes = append(result.Changes, change)
+	}
+	if len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	if dryRun || len(toApply) == 0 {
+		return result, nil
+	}
+
+	if err := s.importRepo.ApplyVenueImport(ctx, toApply); err != nil {
+		return nil, err
+	}
+	result.Applied = true
+
+	return result, nil
+}
+
+// ImportEvents parses an event schedule CSV and creates or updates events at existing venues.
+// With dryRun set, the diff is returned without touching the database.
+func (s *ImportService) ImportEvents(ctx context.Context, r io.Reader, dryRun bool) (*entities.ImportResult, error) {
+	records, err := readCSV(r, eventImportColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entities.ImportResult{Kind: constants.ImportKindEvents, DryRun: dryRun, Changes: []entities.ImportChange{}}
+
+	type plannedEvent struct {
+		line  int
+		event *entities.Event
+	}
+	var planned []plannedEvent
+	venues := make(map[string]*entities.Venue)
+
+	for _, rec := range records {
+		event, rowErrs := buildEvent(rec)
+		for _, field := range []string{"venue_name", "venue_city"} {
+			if rec.get(field) == "" {
+				rowErrs = append(rowErrs, entities.ImportRowError{Line: rec.line, Field: field, Message: "is required"})
+			}
+		}
+		if len(rowErrs) > 0 {
+			result.Errors = append(result.Errors, rowErrs...)
+			continue
+		}
+
+		venueName, venueCity := rec.get("venue_name"), rec.get("venue_city")
+		venueKey := strings.ToLower(venueName) + "|" + strings.ToLower(venueCity)
+		venue, ok := venues[venueKey]
+		if !ok {
+			venue, err = s.importRepo.FindVenueByNameAndCity(ctx, venueName, venueCity)
+			if err != nil {
+				return nil, err
+			}
+			venues[venueKey] = venue
+		}
+		if venue == nil {
+			result.Errors = append(result.Errors, entities.ImportRowError{Line: rec.line, Field: "venue_name", Message: "venue not found"})
+			continue
+		}
+
+		event.VenueID = venue.ID
+		planned = append(planned, plannedEvent{line: rec.line, event: event})
+	}
+
+	// Reject overlapping events for the same venue within the file itself
+	for i := range planned {
+		for j := 0; j < i; j++ {
+			a, b := planned[i].event, planned[j].event
+			if a.VenueID == b.VenueID && a.StartTime.Before(b.EndTime) && b.StartTime.Before(a.EndTime) {
+				result.Errors = append(result.Errors, entities.ImportRowError{
+					Line:    planned[i].line,
+					Field:   "start_time",
+					Message: fmt.Sprintf("overlaps the event on line %d", planned[j].line),
+				})
+			}
+		}
+	}
+	if len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	var toApply []*entities.Event
+	for _, p := range planned {
+		existing, err := s.importRepo.FindEvent(ctx, p.event.VenueID, p.event.Name, p.event.StartTime)
+		if err != nil {
+			return nil, err
+		}
+
+		var excludeID uint
+		if existing != nil {
+			excludeID = existing.ID
+		}
+		conflict, err := s.importRepo.HasVenueTimeConflict(ctx, p.event.VenueID, p.event.StartTime, p.event.EndTime, excludeID)
+		if err != nil {
+			return nil, err
+		}
+		if conflict {
+			result.Errors = append(result.Errors, entities.ImportRowError{Line: p.line, Field: "start_time", Message: constants.ErrVenueTimeConflict})
+			continue
+		}
+
+		change := entities.ImportChange{Line: p.line, Key: fmt.Sprintf("%s @ %s", p.event.Name, p.event.StartTime.Format(time.RFC3339))}
+		if existing == nil {
+			change.Action = constants.ImportActionCreate
+			result.Created++
+			toApply = append(toApply, p.event)
+		} else {
+			change.Fields = diffEvent(existing, p.event)
+			if len(change.Fields) == 0 {
+				change.Action = constants.ImportActionUnchanged
+				result.Unchanged++
+			} else {
+				change.Action = constants.ImportActionUpdate
+				result.Updated++
+				p.event.ID = existing.ID
+				toApply = append(toApply, p.event)
+			}
+		}
+		result.Changes = append(result.Changes, change)
+	}
+	if len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	if dryRun || len(toApply) == 0 {
+		return result, nil
+	}
+
+	if err := s.importRepo.ApplyEventImport(ctx, toApply); err != nil {
+		return nil, err
+	}
+	result.Applied = true
+
+	return result, nil
+}
+
+// readCSV reads all records and checks that the header contains the required columns
+func readCSV(r io.Reader, columns []string) ([]csvRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.NewBadRequestError("CSV file is empty", nil)
+	}
+	if err != nil {
+		return nil, errors.NewBadRequestError("Invalid CSV header", err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	var missing []string
+	for _, column := range columns {
+		if _, ok := index[column]; !ok {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, errors.NewBadRequestError("CSV header is missing columns: "+strings.Join(missing, ", "), nil)
+	}
+
+	var records []csvRecord
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.NewBadRequestError("Invalid CSV content", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		values := make(map[string]string, len(columns))
+		for _, column := range columns {
+			values[column] = row[index[column]]
+		}
+		records = append(records, csvRecord{line: line, values: values})
+	}
+
+	if len(records) == 0 {
+		return nil, errors.NewBadRequestError("CSV file has no data rows", nil)
+	}
+
+	return records, nil
+}
+
+// buildVenue validates the section rows of a single venue and assembles the entity
+func buildVenue(records []csvRecord) (*entities.Venue, []entities.ImportRowError) {
+	var rowErrs []entities.ImportRowError
+	addErr := func(line int, field, message string) {
+		rowErrs = append(rowErrs, entities.ImportRowError{Line: line, Field: field, Message: message})
+	}
+
+	first := records[0]
+	venue := &entities.Venue{
+		Name:        first.get("venue_name"),
+		Address:     first.get("address"),
+		City:        first.get("city"),
+		State:       first.get("state"),
+		Country:     first.get("country"),
+		Description: first.get("description"),
+	}
+	for _, field := range []string{"venue_name", "address", "city", "state", "country"} {
+		if first.get(field) == "" {
+			addErr(first.line, field, "is required")
+		}
+	}
+
+	for _, rec := range records {
+		columns, err := strconv.Atoi(rec.get("columns"))
+		if err != nil || columns < 1 {
+			addErr(rec.line, "columns", "must be a positive integer")
+			continue
+		}
+		if venue.Columns == 0 {
+			venue.Columns = columns
+		} else if venue.Columns != columns {
+			addErr(rec.line, "columns", "must be the same for every section of a venue")
+		}
+
+		section := entities.VenueSection{
+			Name:            rec.get("section"),
+			SeatType:        strings.ToLower(rec.get("seat_type")),
+			PriceMultiplier: 1,
+		}
+		if section.Name == "" {
+			addErr(rec.line, "section", "is required")
+		}
+		if section.RowStart, err = strconv.Atoi(rec.get("row_start")); err != nil || section.RowStart < 1 {
+			addErr(rec.line, "row_start", "must be a positive integer")
+			continue
+		}
+		if section.RowEnd, err = strconv.Atoi(rec.get("row_end")); err != nil || section.RowEnd < section.RowStart {
+			addErr(rec.line, "row_end", "must be an integer not lower than row_start")
+			continue
+		}
+		if section.SeatType == "" {
+			section.SeatType = constants.SeatTypeStandard
+		} else if !isValidSeatType(section.SeatType) {
+			addErr(rec.line, "seat_type", "must be one of standard, premium, vip")
+		}
+		if raw := rec.get("price_multiplier"); raw != "" {
+			if section.PriceMultiplier, err = strconv.ParseFloat(raw, 64); err != nil || section.PriceMultiplier <= 0 {
+				addErr(rec.line, "price_multiplier", "must be a positive number")
+			}
+		}
+
+		for _, other := range venue.Sections {
+			if section.RowStart <= other.RowEnd && other.RowStart <= section.RowEnd {
+				addErr(rec.line, "row_start", fmt.Sprintf("rows overlap section %q", other.Name))
+			}
+		}
+		venue.Sections = append(venue.Sections, section)
+		if section.RowEnd > venue.Rows {
+			venue.Rows = section.RowEnd
+		}
+	}
+
+	sort.Slice(venue.Sections, func(i, j int) bool { return venue.Sections[i].RowStart < venue.Sections[j].RowStart })
+
+	return venue, rowErrs
+}
+
+// buildEvent validates a single event row and assembles the entity (without venue, which
+// callers check and resolve)
+func buildEvent(rec csvRecord) (*entities.Event, []entities.ImportRowError) {
+	var rowErrs []entities.ImportRowError
+	addErr := func(field, message string) {
+		rowErrs = append(rowErrs, entities.ImportRowError{Line: rec.line, Field: field, Message: message})
+	}
+
+	event := &entities.Event{
+		Name:        rec.get("name"),
+		Description: rec.get("description"),
+		EventType:   strings.ToLower(rec.get("event_type")),
+		Status:      constants.EventStatusActive,
+
+		ReminderOffsets: constants.DefaultReminderOffsets,
+	}
+	if event.Name == "" {
+		addErr("name", "is required")
+	}
+	if !isValidEventType(event.EventType) {
+		addErr("event_type", "must be one of concert, theater, sports, conference, other")
+	}
+
+	var err error
+	if event.StartTime, err = time.Parse(time.RFC3339, rec.get("start_time")); err != nil {
+		addErr("start_time", "must be an RFC3339 timestamp")
+	}
+	if event.EndTime, err = time.Parse(time.RFC3339, rec.get("end_time")); err != nil {
+		addErr("end_time", "must be an RFC3339 timestamp")
+	}
+	if !event.StartTime.IsZero() && !event.EndTime.IsZero() {
+		if !event.EndTime.After(event.StartTime) {
+			addErr("end_time", "must be after start_time")
+		}
+		if event.StartTime.Before(time.Now()) {
+			addErr("start_time", "must be in the future")
+		}
+	}
+
+	if event.Price, err = money.Parse(rec.get("price")); err != nil || event.Price < 0 {
+		addErr("price", "must be a non-negative amount with at most 2 decimal places")
+	}
+	if raw := rec.get("is_high_demand"); raw != "" {
+		if event.IsHighDemand, err = strconv.ParseBool(raw); err != nil {
+			addErr("is_high_demand", "must be true or false")
+		}
+	}
+
+	return event, rowErrs
+}
+
+// layoutChangeErrors rejects changes to the rows or columns of a venue with events, whose seats
+// were generated from them
+func layoutChangeErrors(ctx context.Context, importRepo *repository.ImportRepository, venueID uint, line int, fields map[string]entities.FieldChange) ([]entities.ImportRowError, error) {
+	var rowErrs []entities.ImportRowError
+	for _, field := range []string{"rows", "columns"} {
+		if _, ok := fields[field]; ok {
+			rowErrs = append(rowErrs, entities.ImportRowError{Line: line, Field: field, Message: constants.ErrVenueLayoutHasEvents})
+		}
+	}
+	if len(rowErrs) == 0 {
+		return nil, nil
+	}
+
+	hasEvents, err := importRepo.VenueHasEvents(ctx, venueID)
+	if err != nil || !hasEvents {
+		return nil, err
+	}
+	return rowErrs, nil
+}
+
+func diffVenue(existing, imported *entities.Venue) map[string]entities.FieldChange {
+	fields := make(map[string]entities.FieldChange)
+	diffField(fields, "name", existing.Name, imported.Name)
+	diffField(fields, "address", existing.Address, imported.Address)
+	diffField(fields, "city", existing.City, imported.City)
+	diffField(fields, "state", existing.State, imported.State)
+	diffField(fields, "country", existing.Country, imported.Country)
+	diffField(fields, "rows", existing.Rows, imported.Rows)
+	diffField(fields, "columns", existing.Columns, imported.Columns)
+	diffField(fields, "description", existing.Description, imported.Description)
+	diffField(fields, "sections", describeSections(existing.Sections), describeSections(imported.Sections))
+	return fields
+}
+
+func diffEvent(existing, imported *entities.Event) map[string]entities.FieldChange {
+	fields := make(map[string]entities.FieldChange)
+	diffField(fields, "description", existing.Description, imported.Description)
+	diffField(fields, "end_time", existing.EndTime.UTC().Format(time.RFC3339), imported.EndTime.UTC().Format(time.RFC3339))
+	diffField(fields, "price", existing.Price, imported.Price)
+	diffField(fields, "event_type", existing.EventType, imported.EventType)
+	diffField(fields, "is_high_demand", existing.IsHighDemand, imported.IsHighDemand)
+	return fields
+}
+
+func diffField[T comparable](fields map[string]entities.FieldChange, name string, from, to T) {
+	if from != to {
+		fields[name] = entities.FieldChange{From: from, To: to}
+	}
+}
+
+// describeSections renders sections as a comparable summary, e.g. "Floor:1-5:vip:2"
+func describeSections(sections []entities.VenueSection) string {
+	parts := make([]string, len(sections))
+	for i, section := range sections {
+		parts[i] = fmt.Sprintf("%s:%d-%d:%s:%g", section.Name, section.RowStart, section.RowEnd, section.SeatType, section.PriceMultiplier)
+	}
+	return strings.Join(parts, ";")
+}
+
+func isValidSeatType(seatType string) bool {
+	switch seatType {
+	case constants.SeatTypeStandard, constants.SeatTypePremium, constants.SeatTypeVIP:
+		return true
+	}
+	return false
+}
+
+func isValidEventType(eventType string) bool {
+	switch eventType {
+	case constants.EventTypeConcert, constants.EventTypeTheater, constants.EventTypeSports,
+		constants.EventTypeConference, constants.EventTypeOther:
+		return true
+	}
+	return false
+}
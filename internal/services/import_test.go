@@ -0,0 +1,316 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"api/pkg/money"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestReadCSV keys rows by the required columns in any header order and rejects files without
+// them or without data
+func TestReadCSV(t *testing.T) {
+	columns := []string{"name", "price"}
+	tests := []struct {
+		name    string
+		csv     string
+		want    []csvRecord
+		wantErr string
+	}{
+		{
+			name: "columns in any order and case",
+			csv:  " Price ,NAME,extra\n10,Opera,x\n\n0,Jazz,y\n",
+			want: []csvRecord{
+				{line: 2, values: map[string]string{"name": "Opera", "price": "10"}},
+				{line: 4, values: map[string]string{"name": "Jazz", "price": "0"}},
+			},
+		},
+		{name: "empty file", csv: "", wantErr: "CSV file is empty"},
+		{name: "missing columns", csv: "title\nOpera\n", wantErr: "CSV header is missing columns: name, price"},
+		{name: "header only", csv: "name,price\n", wantErr: "CSV file has no data rows"},
+		{name: "row with a different column count", csv: "name,price\nOpera\n", wantErr: "Invalid CSV content"},
+	}
+	for _, tt := range tests {
+		got, err := readCSV(strings.NewReader(tt.csv), columns)
+		if tt.wantErr != "" {
+			appErr, ok := err.(*errors.AppError)
+			if !ok || appErr.Type != "BAD_REQUEST" || appErr.Message != tt.wantErr {
+				t.Errorf("%s: readCSV returned %v, want bad request %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: readCSV returned %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: readCSV = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// venueRecord is a valid venue import row with the given fields replaced
+func venueRecord(line int, fields map[string]string) csvRecord {
+	values := map[string]string{
+		"venue_name": "Arena", "address": "1 Main St", "city": "Springfield", "state": "IL", "country": "US",
+		"columns": "10", "description": "", "section": "Floor", "row_start": "1", "row_end": "5",
+		"seat_type": "", "price_multiplier": "",
+	}
+	for field, value := range fields {
+		values[field] = value
+	}
+	return csvRecord{line: line, values: values}
+}
+
+// eventRecord is a valid event import row with the given fields replaced
+func eventRecord(fields map[string]string) csvRecord {
+	start := time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(time.Second)
+	values := map[string]string{
+		"name": "Opera Night", "description": "", "venue_name": "Arena", "venue_city": "Springfield",
+		"start_time": start.Format(time.RFC3339), "end_time": start.Add(3 * time.Hour).Format(time.RFC3339),
+		"price": "49.99", "event_type": "Theater", "is_high_demand": "",
+	}
+	for field, value := range fields {
+		values[field] = value
+	}
+	return csvRecord{line: 2, values: values}
+}
+
+// errorFields lists the row errors as "line:field", sorted
+func errorFields(rowErrs []entities.ImportRowError) []string {
+	fields := []string{}
+	for _, rowErr := range rowErrs {
+		fields = append(fields, fmt.Sprintf("%d:%s", rowErr.Line, rowErr.Field))
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// TestBuildVenue assembles a venue from its section rows and reports every invalid field by line
+func TestBuildVenue(t *testing.T) {
+	venue, rowErrs := buildVenue([]csvRecord{
+		venueRecord(2, map[string]string{"section": "Balcony", "row_start": "6", "row_end": "8", "seat_type": "VIP", "price_multiplier": "1.5"}),
+		venueRecord(3, nil),
+	})
+	if len(rowErrs) > 0 {
+		t.Fatalf("buildVenue returned errors %+v", rowErrs)
+	}
+	want := &entities.Venue{
+		Name: "Arena", Address: "1 Main St", City: "Springfield", State: "IL", Country: "US", Rows: 8, Columns: 10,
+		Sections: []entities.VenueSection{
+			{Name: "Floor", RowStart: 1, RowEnd: 5, SeatType: constants.SeatTypeStandard, PriceMultiplier: 1},
+			{Name: "Balcony", RowStart: 6, RowEnd: 8, SeatType: constants.SeatTypeVIP, PriceMultiplier: 1.5},
+		},
+	}
+	if !reflect.DeepEqual(venue, want) {
+		t.Errorf("buildVenue = %+v, want %+v", venue, want)
+	}
+
+	tests := []struct {
+		name    string
+		records []csvRecord
+		want    []string
+	}{
+		{
+			name:    "missing venue fields",
+			records: []csvRecord{venueRecord(2, map[string]string{"venue_name": "", "address": " ", "country": ""})},
+			want:    []string{"2:address", "2:country", "2:venue_name"},
+		},
+		{
+			name:    "invalid columns",
+			records: []csvRecord{venueRecord(2, map[string]string{"columns": "0"})},
+			want:    []string{"2:columns"},
+		},
+		{
+			name: "columns differing between sections",
+			records: []csvRecord{
+				venueRecord(2, nil),
+				venueRecord(3, map[string]string{"section": "Balcony", "row_start": "6", "row_end": "8", "columns": "12"}),
+			},
+			want: []string{"3:columns"},
+		},
+		{
+			name:    "missing section name",
+			records: []csvRecord{venueRecord(2, map[string]string{"section": ""})},
+			want:    []string{"2:section"},
+		},
+		{
+			name:    "invalid row_start",
+			records: []csvRecord{venueRecord(2, map[string]string{"row_start": "first"})},
+			want:    []string{"2:row_start"},
+		},
+		{
+			name:    "row_end before row_start",
+			records: []csvRecord{venueRecord(2, map[string]string{"row_start": "5", "row_end": "4"})},
+			want:    []string{"2:row_end"},
+		},
+		{
+			name:    "unknown seat type",
+			records: []csvRecord{venueRecord(2, map[string]string{"seat_type": "box"})},
+			want:    []string{"2:seat_type"},
+		},
+		{
+			name:    "non-positive price multiplier",
+			records: []csvRecord{venueRecord(2, map[string]string{"price_multiplier": "-1"})},
+			want:    []string{"2:price_multiplier"},
+		},
+		{
+			name: "overlapping sections",
+			records: []csvRecord{
+				venueRecord(2, nil),
+				venueRecord(3, map[string]string{"section": "Balcony", "row_start": "5", "row_end": "8"}),
+			},
+			want: []string{"3:row_start"},
+		},
+	}
+	for _, tt := range tests {
+		_, rowErrs := buildVenue(tt.records)
+		if got := errorFields(rowErrs); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: buildVenue errors = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestBuildEvent assembles an event from its row and reports every invalid field
+func TestBuildEvent(t *testing.T) {
+	rec := eventRecord(map[string]string{"is_high_demand": "true"})
+	event, rowErrs := buildEvent(rec)
+	if len(rowErrs) > 0 {
+		t.Fatalf("buildEvent returned errors %+v", rowErrs)
+	}
+	start, _ := time.Parse(time.RFC3339, rec.values["start_time"])
+	want := &entities.Event{
+		Name: "Opera Night", EventType: constants.EventTypeTheater, Status: constants.EventStatusActive,
+		StartTime: start, EndTime: start.Add(3 * time.Hour), Price: money.FromMinor(4999), IsHighDemand: true,
+		ReminderOffsets: constants.DefaultReminderOffsets,
+	}
+	if !reflect.DeepEqual(event, want) {
+		t.Errorf("buildEvent = %+v, want %+v", event, want)
+	}
+
+	past := time.Now().Add(-time.Hour).UTC()
+	tests := []struct {
+		name   string
+		fields map[string]string
+		want   []string
+	}{
+		{name: "missing name", fields: map[string]string{"name": ""}, want: []string{"2:name"}},
+		{name: "unknown event type", fields: map[string]string{"event_type": "gala"}, want: []string{"2:event_type"}},
+		{name: "start time not RFC3339", fields: map[string]string{"start_time": "2030-01-01 20:00"}, want: []string{"2:start_time"}},
+		{name: "end time not RFC3339", fields: map[string]string{"end_time": "tomorrow"}, want: []string{"2:end_time"}},
+		{
+			name:   "end time before start time",
+			fields: map[string]string{"end_time": rec.values["start_time"]},
+			want:   []string{"2:end_time"},
+		},
+		{
+			name: "start time in the past",
+			fields: map[string]string{
+				"start_time": past.Format(time.RFC3339),
+				"end_time":   past.Add(3 * time.Hour).Format(time.RFC3339),
+			},
+			want: []string{"2:start_time"},
+		},
+		{name: "negative price", fields: map[string]string{"price": "-5"}, want: []string{"2:price"}},
+		{name: "price with a fraction of a cent", fields: map[string]string{"price": "9.999"}, want: []string{"2:price"}},
+		{name: "invalid high demand flag", fields: map[string]string{"is_high_demand": "maybe"}, want: []string{"2:is_high_demand"}},
+	}
+	for _, tt := range tests {
+		_, rowErrs := buildEvent(eventRecord(tt.fields))
+		if got := errorFields(rowErrs); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: buildEvent errors = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestDiffVenue reports the fields a venue import changes, comparing sections as a whole
+func TestDiffVenue(t *testing.T) {
+	existing := func() *entities.Venue {
+		return &entities.Venue{
+			Name: "Arena", Address: "1 Main St", City: "Springfield", State: "IL", Country: "US", Rows: 5, Columns: 10,
+			Sections: []entities.VenueSection{{ID: 7, VenueID: 3, Name: "Floor", RowStart: 1, RowEnd: 5, SeatType: constants.SeatTypeStandard, PriceMultiplier: 1}},
+		}
+	}
+	tests := []struct {
+		name   string
+		change func(*entities.Venue)
+		want   map[string]entities.FieldChange
+	}{
+		{
+			name:   "unchanged, ignoring section IDs",
+			change: func(v *entities.Venue) { v.Sections[0].ID, v.Sections[0].VenueID = 0, 0 },
+			want:   map[string]entities.FieldChange{},
+		},
+		{
+			name:   "address",
+			change: func(v *entities.Venue) { v.Address = "2 Main St" },
+			want:   map[string]entities.FieldChange{"address": {From: "1 Main St", To: "2 Main St"}},
+		},
+		{
+			name: "rows and sections",
+			change: func(v *entities.Venue) {
+				v.Rows = 8
+				v.Sections = append(v.Sections, entities.VenueSection{Name: "Balcony", RowStart: 6, RowEnd: 8, SeatType: constants.SeatTypeVIP, PriceMultiplier: 1.5})
+			},
+			want: map[string]entities.FieldChange{
+				"rows":     {From: 5, To: 8},
+				"sections": {From: "Floor:1-5:standard:1", To: "Floor:1-5:standard:1;Balcony:6-8:vip:1.5"},
+			},
+		},
+		{
+			name:   "columns",
+			change: func(v *entities.Venue) { v.Columns = 12 },
+			want:   map[string]entities.FieldChange{"columns": {From: 10, To: 12}},
+		},
+	}
+	for _, tt := range tests {
+		imported := existing()
+		tt.change(imported)
+		if got := diffVenue(existing(), imported); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: diffVenue = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestDiffEvent reports the fields an event import changes, comparing end times in UTC
+func TestDiffEvent(t *testing.T) {
+	start := time.Date(2030, 5, 1, 20, 0, 0, 0, time.UTC)
+	existing := &entities.Event{
+		Name: "Opera Night", StartTime: start, EndTime: start.Add(3 * time.Hour), Price: money.FromMajor(50),
+		EventType: constants.EventTypeTheater,
+	}
+	berlin := time.FixedZone("CEST", 2*60*60)
+
+	tests := []struct {
+		name     string
+		imported entities.Event
+		want     map[string]entities.FieldChange
+	}{
+		{
+			name:     "same end time in another zone",
+			imported: entities.Event{EndTime: start.Add(3 * time.Hour).In(berlin), Price: money.FromMajor(50), EventType: constants.EventTypeTheater},
+			want:     map[string]entities.FieldChange{},
+		},
+		{
+			name: "price, end time and high demand",
+			imported: entities.Event{EndTime: start.Add(4 * time.Hour), Price: money.FromMajor(60), EventType: constants.EventTypeTheater,
+				IsHighDemand: true},
+			want: map[string]entities.FieldChange{
+				"end_time":       {From: "2030-05-01T23:00:00Z", To: "2030-05-02T00:00:00Z"},
+				"price":          {From: money.FromMajor(50), To: money.FromMajor(60)},
+				"is_high_demand": {From: false, To: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		if got := diffEvent(existing, &tt.imported); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: diffEvent = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"api/internal/entities"
 	"context"
+	"io"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -10,22 +11,101 @@ import (
 
 // BookingServiceInterface defines the contract for booking operations
 type BookingServiceInterface interface {
-	CreateBookingIntent(ctx context.Context, userID, seatID uint) (*entities.BookingIntent, error)
-	ConfirmBooking(ctx context.Context, bookingIntentID uint, paymentID string) (*entities.Booking, error)
+	CreateBookingIntent(ctx context.Context, userID, seatID uint, options entities.BookingIntentOptions) (*entities.BookingIntent, error)
+	ConfirmBooking(ctx context.Context, bookingIntentID uint, payment entities.PaymentDetails, attendee entities.AttendeeDetails, options entities.ConfirmOptions) (*entities.Booking, error)
 	CancelBookingIntent(ctx context.Context, bookingIntentID uint, userID uint) error
+	HeartbeatBookingIntent(ctx context.Context, bookingIntentID uint, userID uint) (*entities.BookingIntent, error)
+	GetBookingIntentStatus(ctx context.Context, bookingIntentID uint, userID uint) (*BookingIntentStatus, error)
+	RetryPayment(ctx context.Context, bookingIntentID uint, userID uint, failureReason string) (*entities.BookingIntent, error)
+	IssueResumeToken(ctx context.Context, bookingIntentID uint, userID uint) (string, time.Time, error)
+	ResumeBookingIntent(ctx context.Context, userID uint, token string) (*entities.BookingIntent, error)
 	CancelBooking(ctx context.Context, bookingID uint, userID uint) error
-	GetUserBookings(ctx context.Context, userID uint, limit, offset int) ([]entities.Booking, int64, error)
+	GetUserBookings(ctx context.Context, userID uint, filter entities.BookingFilter, limit, offset int) ([]entities.Booking, int64, error)
+	GetUpcomingBookings(ctx context.Context, userID uint, limit int) ([]entities.Booking, error)
 	GetBookingByID(ctx context.Context, bookingID, userID uint) (*entities.Booking, error)
 	CleanupExpiredIntents(ctx context.Context) error
+	ReleaseAbandonedIntents(ctx context.Context) error
+	InspectSeatLock(ctx context.Context, seatID uint) (*SeatLockInspection, error)
+	PurgeEventIntents(ctx context.Context, eventID uint, createdBefore time.Time) (*IntentPurgeResult, error)
+}
+
+// BookingIntentStatus is the lightweight view of an intent polled by checkout pages
+type BookingIntentStatus struct {
+	BookingIntentID  uint       `json:"booking_intent_id"`
+	Status           string     `json:"status"`
+	PaymentStatus    string     `json:"payment_status"`
+	PaymentIntentID  string     `json:"payment_intent_id,omitempty"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	RemainingSeconds int        `json:"remaining_seconds"`
+}
+
+// IntentPurgeResult reports an admin purge of an event's stale pending intents. Redis locks
+// that couldn't be released, e.g. in degraded mode, expire on their own.
+type IntentPurgeResult struct {
+	EventID        uint      `json:"event_id"`
+	CreatedBefore  time.Time `json:"created_before"`
+	ExpiredIntents int       `json:"expired_intents"`
+	ReleasedSeats  int       `json:"released_seats"`
+	ReleasedLocks  int       `json:"released_locks"`
+	FailedLocks    int       `json:"failed_locks"`
+}
+
+// SeatLockInspection is everything that holds a seat, for support to diagnose seats stuck as
+// locked: the database lock, the Redis lock and the intent behind them
+type SeatLockInspection struct {
+	SeatID      uint             `json:"seat_id"`
+	EventID     uint             `json:"event_id"`
+	IsAvailable bool             `json:"is_available"` // false once sold
+	Database    DatabaseSeatLock `json:"database"`
+	Redis       RedisSeatLock    `json:"redis"`
+	Intent      *SeatLockIntent  `json:"intent,omitempty"` // pending intent for the seat, or else its latest one
+}
+
+// DatabaseSeatLock is the lock taken on the seat row, by intents created while Redis was down
+type DatabaseSeatLock struct {
+	IsLocked bool       `json:"is_locked"`
+	LockedAt *time.Time `json:"locked_at,omitempty"`
+	LockedBy *uint      `json:"locked_by,omitempty"`
+}
+
+// RedisSeatLock is the seat's Redis lock. Its value is "userID:intentID"; intent IDs starting
+// with temp_ belong to intents still being created.
+type RedisSeatLock struct {
+	Reachable  bool   `json:"reachable"`
+	Error      string `json:"error,omitempty"`
+	IsLocked   bool   `json:"is_locked"`
+	Value      string `json:"value,omitempty"`
+	UserID     *uint  `json:"user_id,omitempty"`
+	IntentID   string `json:"intent_id,omitempty"`
+	TTLSeconds int    `json:"ttl_seconds,omitempty"` // -1 if the lock never expires
+}
+
+// SeatLockIntent is the booking intent behind a seat's locks
+type SeatLockIntent struct {
+	ID             uint      `json:"id"`
+	UserID         uint      `json:"user_id"`
+	Status         string    `json:"status"`
+	PaymentStatus  string    `json:"payment_status"`
+	LockExpiresAt  time.Time `json:"lock_expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	HoldsRedisLock bool      `json:"holds_redis_lock"`
 }
 
 // EventServiceInterface defines the contract for event operations
 type EventServiceInterface interface {
-	GetEvents(ctx context.Context, limit, offset int, eventType, city string) ([]entities.Event, int64, error)
+	GetEvents(ctx context.Context, limit, offset int, eventType, city string, metadata map[string]string) ([]entities.Event, int64, error)
 	GetEventByID(ctx context.Context, eventID uint) (*entities.Event, error)
-	GetAvailableSeats(ctx context.Context, eventID uint) ([]entities.Seat, error)
+	GetAvailableSeats(ctx context.Context, eventID uint, filter entities.SeatFilter) ([]entities.Seat, error)
+	SetSeatAccessibility(ctx context.Context, seatID uint, accessible bool, companionSeatIDs []uint) (*entities.Seat, []entities.Seat, error)
+	ReleaseSeats(ctx context.Context, eventID uint, rowStart, rowEnd int, releasedBy uint) (*entities.SeatRelease, error)
+	ListReleases(ctx context.Context, eventID uint) ([]entities.SeatRelease, int64, error)
+	UpdateSeatPrices(ctx context.Context, eventID uint, change entities.SeatPriceChange) ([]entities.SeatPriceHistory, error)
+	ListSeatPriceHistory(ctx context.Context, seatID uint) ([]entities.SeatPriceHistory, error)
 	GetAvailableSeatsCount(ctx context.Context, eventID uint) (int64, error)
-	CreateEvent(ctx context.Context, event *entities.Event) error
+	GetListingAvailability(ctx context.Context, events []entities.Event) map[uint]int64
+	WarmCache(ctx context.Context, eventIDs []uint) (*entities.CacheWarmResult, error)
+	CreateEvent(ctx context.Context, event *entities.Event, createdBy uint) (*entities.Task, error)
+	CreateSandbox(ctx context.Context, eventID uint, onSaleAt, earlyAccessAt *time.Time, createdBy uint) (*entities.Task, error)
 	UpdateEvent(ctx context.Context, eventID uint, updates map[string]interface{}) (*entities.Event, error)
 	DeleteEvent(ctx context.Context, eventID uint) error
 	GetEventStats(ctx context.Context, eventID uint) (map[string]interface{}, error)
@@ -36,17 +116,94 @@ type UserServiceInterface interface {
 	Register(ctx context.Context, email, password, firstName, lastName, phone string, isAdmin bool) (*entities.User, error)
 	Login(ctx context.Context, email, password string) (*entities.User, error)
 	GetByID(ctx context.Context, userID uint) (*entities.User, error)
+	SetMembershipTier(ctx context.Context, userID uint, tier string) (*entities.User, error)
+	ChangePassword(ctx context.Context, userID uint, current, password string) (*entities.User, error)
+	TokenVersion(ctx context.Context, userID uint) (int, error)
+}
+
+// AccountServiceInterface defines the contract for account deletion
+type AccountServiceInterface interface {
+	DeleteAccount(ctx context.Context, userID uint) (*entities.User, error)
+	RestoreAccount(ctx context.Context, token string) (*entities.User, error)
+}
+
+// PolicyServiceInterface defines the contract for terms of service and privacy policy acceptance
+type PolicyServiceInterface interface {
+	CurrentVersions() map[string]string
+	CheckAcceptance(consent entities.PolicyConsent) error
+	Accept(ctx context.Context, userID uint, consent entities.PolicyConsent, ip string) ([]entities.PolicyAcceptance, error)
+	PendingPolicies(ctx context.Context, userID uint) ([]string, error)
+	ListAcceptances(ctx context.Context, userID uint) ([]entities.PolicyAcceptance, error)
+}
+
+// SettingsServiceInterface defines the contract for the runtime settings console
+type SettingsServiceInterface interface {
+	ListSettings(ctx context.Context) ([]Setting, error)
+	UpdateSetting(ctx context.Context, key, value string, adminID uint) (*Setting, error)
+	ResetSetting(ctx context.Context, key string, adminID uint) (*Setting, error)
+	ListSettingChanges(ctx context.Context, key string, limit, offset int) ([]entities.SettingChange, int64, error)
 }
 
 // VenueServiceInterface defines the contract for venue operations
 type VenueServiceInterface interface {
-	GetVenues(ctx context.Context, limit, offset int, city string) ([]entities.Venue, int64, error)
+	GetVenues(ctx context.Context, limit, offset int, city string, metadata map[string]string) ([]entities.Venue, int64, error)
 	GetVenueByID(ctx context.Context, venueID uint) (*entities.Venue, error)
 	CreateVenue(ctx context.Context, venue *entities.Venue) error
 	UpdateVenue(ctx context.Context, venueID uint, updates map[string]interface{}) (*entities.Venue, error)
 	DeleteVenue(ctx context.Context, venueID uint) error
 }
 
+// ImportServiceInterface defines the contract for CSV catalog imports
+type ImportServiceInterface interface {
+	ImportVenues(ctx context.Context, r io.Reader, dryRun bool) (*entities.ImportResult, error)
+	ImportEvents(ctx context.Context, r io.Reader, dryRun bool) (*entities.ImportResult, error)
+}
+
+// CatalogSyncServiceInterface defines the contract for syncing a partner's catalog feed
+type CatalogSyncServiceInterface interface {
+	RunSync(ctx context.Context) (*entities.CatalogSyncRun, error)
+	GetStatus(ctx context.Context) (*entities.CatalogSyncStatus, error)
+}
+
+// AttendanceServiceInterface defines the contract for check-in and post-event follow-up
+type AttendanceServiceInterface interface {
+	CheckIn(ctx context.Context, bookingID uint) (*entities.Booking, error)
+	SubmitReview(ctx context.Context, userID, bookingID uint, rating int, comment string) (*entities.EventReview, error)
+	ProcessCompletedEvents(ctx context.Context) error
+}
+
+// DisputeServiceInterface defines the contract for payment dispute handling
+type DisputeServiceInterface interface {
+	HandleDisputeUpdate(ctx context.Context, update entities.DisputeUpdate) (*entities.Dispute, error)
+	ListDisputes(ctx context.Context, status string, limit, offset int) ([]entities.Dispute, int64, error)
+}
+
+// PaymentServiceInterface defines the contract for payment reconciliation lookups
+type PaymentServiceInterface interface {
+	ListTransactions(ctx context.Context, filter entities.PaymentTransactionFilter, limit, offset int) ([]entities.PaymentTransaction, int64, error)
+	GetTransaction(ctx context.Context, transactionID uint) (*entities.PaymentTransaction, error)
+}
+
+// LoyaltyServiceInterface defines the contract for loyalty points lookups
+type LoyaltyServiceInterface interface {
+	GetSummary(ctx context.Context, userID uint) (*entities.LoyaltySummary, error)
+	ListTransactions(ctx context.Context, userID uint, limit, offset int) ([]entities.LoyaltyTransaction, int64, error)
+}
+
+// PresaleServiceInterface defines the contract for presale code management
+type PresaleServiceInterface interface {
+	CreateBatch(ctx context.Context, eventID uint, name string, count, maxUses int, createdBy uint) (*entities.PresaleBatch, error)
+	ListBatches(ctx context.Context, eventID uint) ([]entities.PresaleBatchSummary, error)
+	GetBatch(ctx context.Context, batchID uint) (*entities.PresaleBatch, error)
+}
+
+// SaleRegionServiceInterface defines the contract for managing sale region overrides
+type SaleRegionServiceInterface interface {
+	ListOverrides(ctx context.Context, eventID uint) ([]entities.SaleRegionOverride, error)
+	AddOverride(ctx context.Context, eventID, userID uint, note string, createdBy uint) (*entities.SaleRegionOverride, error)
+	RemoveOverride(ctx context.Context, eventID, userID uint) error
+}
+
 // QueueServiceInterface defines the contract for queue operations
 type QueueServiceInterface interface {
 	JoinQueue(ctx context.Context, userID, eventID uint) (*entities.EventQueue, error)
@@ -72,21 +229,122 @@ type WaitlistEntry struct {
 	JoinedAt  time.Time `json:"joined_at"`
 	Position  int       `json:"position"`
 	NotifiedAt *time.Time `json:"notified_at,omitempty"`
+	Tier      string    `json:"tier,omitempty"`
 }
 
 // JWTServiceInterface defines the contract for JWT operations
 type JWTServiceInterface interface {
-	GenerateToken(userID uint, isAdmin bool) (string, error)
+	GenerateToken(userID uint, isAdmin bool, tenantID *uint, tokenVersion int) (string, error)
 	ValidateToken(tokenStr string) (*jwt.Token, error)
 	GetClaimsFromToken(tokenStr string) (jwt.MapClaims, error)
 }
 
 // SeatLockServiceInterface defines the contract for seat locking operations
 type SeatLockServiceInterface interface {
-	LockSeat(ctx context.Context, seatID uint, userID uint, intentID string) error
-	UnlockSeat(ctx context.Context, seatID uint, userID uint, intentID string) error
-	IsLocked(ctx context.Context, seatID uint) (bool, string, error)
-	ExtendLock(ctx context.Context, seatID uint, userID uint, intentID string) error
-	GetLockTTL(ctx context.Context, seatID uint) (time.Duration, error)
+	LockSeat(ctx context.Context, eventID, seatID uint, userID uint, intentID string, expiresAt time.Time) error
+	UnlockSeat(ctx context.Context, eventID, seatID uint, userID uint, intentID string) error
+	IsLocked(ctx context.Context, eventID, seatID uint) (bool, string, error)
+	ExtendLock(ctx context.Context, eventID, seatID uint, userID uint, intentID string, expiresAt time.Time) error
+	GetLockTTL(ctx context.Context, eventID, seatID uint) (time.Duration, error)
 	CleanupExpiredLocks(ctx context.Context) error
 }
+
+// ArtifactServiceInterface defines the contract for generated artifact storage
+type ArtifactServiceInterface interface {
+	Save(ctx context.Context, kind, fileName, contentType string, data []byte) (*entities.Artifact, error)
+	GetArtifact(ctx context.Context, id uint) (*entities.Artifact, error)
+	ListArtifacts(ctx context.Context, kind string, limit, offset int) ([]entities.Artifact, int64, error)
+	DownloadURL(ctx context.Context, id uint) (string, time.Time, error)
+	DeleteArtifact(ctx context.Context, id uint) error
+	CleanupExpired(ctx context.Context) error
+}
+
+// TenantServiceInterface defines the contract for organizer tenant management
+type TenantServiceInterface interface {
+	CreateTenant(ctx context.Context, t *entities.Tenant) error
+	GetTenant(ctx context.Context, tenantID uint) (*entities.Tenant, error)
+	ListTenants(ctx context.Context) ([]entities.Tenant, error)
+	UpdateTenant(ctx context.Context, tenantID uint, updates map[string]interface{}) (*entities.Tenant, error)
+	AssignAdmin(ctx context.Context, userID uint, tenantID *uint) (*entities.User, error)
+	GetOrganizerPage(ctx context.Context, idOrSlug string) (*entities.OrganizerPage, error)
+	GetBranding(ctx context.Context, tenantID uint) (*entities.Tenant, error)
+	UpdateBranding(ctx context.Context, tenantID uint, updates map[string]interface{}) (*entities.Tenant, error)
+	RateLimit(ctx context.Context, tenantID uint) int
+}
+
+// BoxOfficeServiceInterface defines the contract for seats sold at the door and complimentary tickets
+type BoxOfficeServiceInterface interface {
+	SellAtDoor(ctx context.Context, eventID uint, sale entities.DoorSale) (*entities.Booking, error)
+	IssueComps(ctx context.Context, eventID uint, comp entities.CompIssue) ([]entities.Booking, error)
+	ListComps(ctx context.Context, eventID uint) ([]entities.Booking, error)
+}
+
+// SeatBitmapServiceInterface defines the contract for seat availability bitmaps
+type SeatBitmapServiceInterface interface {
+	GetBitmap(ctx context.Context, eventID uint) (*entities.SeatBitmap, error)
+}
+
+// LedgerServiceInterface defines the contract for ledger reports
+type LedgerServiceInterface interface {
+	GetBalances(ctx context.Context, filter entities.LedgerFilter) (*entities.LedgerReport, error)
+}
+
+// SettlementServiceInterface defines the contract for organizers' event settlements
+type SettlementServiceInterface interface {
+	GetSettlement(ctx context.Context, eventID uint) (*entities.EventSettlement, error)
+	FreezeSettlement(ctx context.Context, eventID, adminID uint) (*entities.EventSettlement, error)
+}
+
+// ReferralServiceInterface defines the contract for referral codes and affiliate commissions
+type ReferralServiceInterface interface {
+	CreatePartner(ctx context.Context, name, email, code string, rate *float64, userID *uint) (*entities.Referrer, error)
+	GetUserCode(ctx context.Context, userID uint) (*entities.Referrer, error)
+	UpdateReferrer(ctx context.Context, referrerID uint, updates map[string]interface{}) (*entities.Referrer, error)
+	ListReferrers(ctx context.Context) ([]entities.ReferrerSummary, error)
+	GetDashboard(ctx context.Context, userID uint) (*entities.ReferrerDashboard, error)
+	GetPayouts(ctx context.Context) ([]entities.ReferrerPayout, error)
+	PayOut(ctx context.Context, referrerID uint) (*entities.ReferrerPayout, error)
+}
+
+// FeedServiceInterface defines the contract for the sitemap and public event feeds
+type FeedServiceInterface interface {
+	Sitemap(ctx context.Context) (*entities.Sitemap, error)
+	EventFeed(ctx context.Context, page int) (*entities.EventFeedPage, error)
+}
+
+// TaskServiceInterface defines the contract for background task status lookups
+type TaskServiceInterface interface {
+	GetTask(ctx context.Context, taskID uint) (*entities.Task, error)
+	ListTasks(ctx context.Context, kind, status string, limit, offset int) ([]entities.Task, int64, error)
+}
+
+// DeadLetterServiceInterface defines the contract for the failed async work console
+type DeadLetterServiceInterface interface {
+	ListDeadLetters(ctx context.Context, source, status string, limit, offset int) ([]entities.DeadLetter, int64, error)
+	GetDeadLetter(ctx context.Context, letterID uint) (*entities.DeadLetter, error)
+	RetryDeadLetter(ctx context.Context, letterID uint) (*entities.DeadLetter, error)
+	DiscardDeadLetter(ctx context.Context, letterID uint) (*entities.DeadLetter, error)
+}
+
+// ArchiveServiceInterface defines the contract for archiving old bookings
+type ArchiveServiceInterface interface {
+	StartArchival(ctx context.Context, olderThanMonths int, requestedBy uint) (*entities.Task, error)
+}
+
+// SupportServiceInterface defines the contract for booking support cases
+type SupportServiceInterface interface {
+	OpenCase(ctx context.Context, userID, bookingID uint, category, message string) (*entities.SupportCase, error)
+	ListUserCases(ctx context.Context, userID uint, status string, limit, offset int) ([]entities.SupportCase, int64, error)
+	GetUserCase(ctx context.Context, userID, caseID uint) (*entities.SupportCase, error)
+	ListCases(ctx context.Context, status, category string, limit, offset int) ([]entities.SupportCase, int64, error)
+	GetCase(ctx context.Context, caseID uint) (*entities.SupportCase, error)
+	Respond(ctx context.Context, caseID, staffID uint, message string, resolve bool) (*entities.SupportCase, error)
+}
+
+// EventPostServiceInterface defines the contract for event announcements and FAQs
+type EventPostServiceInterface interface {
+	CreatePost(ctx context.Context, post *entities.EventPost) (*entities.EventPost, error)
+	UpdatePost(ctx context.Context, postID uint, updates map[string]interface{}) (*entities.EventPost, error)
+	DeletePost(ctx context.Context, postID uint) error
+	ListPosts(ctx context.Context, eventID uint, kinds ...string) ([]entities.EventPost, error)
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"api/internal/entities"
+	"api/internal/inventory"
+	"api/internal/repository"
+	logger "api/pkg/logging"
+	"context"
+)
+
+// InventorySyncService applies the seat holds and releases of venues' external inventory
+// systems, as received by the inventory sync consumer
+type InventorySyncService struct {
+	syncRepo  *repository.InventorySyncRepository
+	seatLocks repository.SeatLockRepository
+}
+
+func NewInventorySyncService(syncRepo *repository.InventorySyncRepository, seatLocks repository.SeatLockRepository) *InventorySyncService {
+	return &InventorySyncService{
+		syncRepo:  syncRepo,
+		seatLocks: seatLocks,
+	}
+}
+
+// Apply reconciles a seat with an external message. Seats held in Redis by online checkouts
+// aren't given up to external holds; in degraded mode those checkouts hold their seats in the
+// database instead.
+func (s *InventorySyncService) Apply(ctx context.Context, msg *inventory.Message) error {
+	update := entities.ExternalSeatUpdate{
+		EventID:    msg.EventID,
+		SeatID:     msg.SeatID,
+		Row:        msg.Row,
+		Column:     msg.Column,
+		Hold:       msg.Action == inventory.ActionHold,
+		HoldRef:    msg.HoldRef,
+		OccurredAt: msg.OccurredAt,
+	}
+	if update.Hold {
+		if locks, err := s.seatLocks.ListLocks(ctx, msg.EventID); err == nil {
+			for seatID := range locks {
+				update.LockedSeatIDs = append(update.LockedSeatIDs, seatID)
+			}
+		}
+	}
+
+	outcome, err := s.syncRepo.ApplyExternalUpdate(ctx, update)
+	if err != nil {
+		return err
+	}
+	logger.Debugf("Inventory message %s from %s for event %d: %s", msg.ID, msg.Source, msg.EventID, outcome)
+	return nil
+}
@@ -7,27 +7,56 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// defaultTokenExpiry applies when no expiry is configured
+const defaultTokenExpiry = 72 * time.Hour
+
 type JWTService struct {
-	secret string
+	secret   string
+	issuer   string
+	audience string
+	expiry   time.Duration
 }
 
 // Ensure JWTService implements JWTServiceInterface
 var _ JWTServiceInterface = (*JWTService)(nil)
 
-func NewJWTService(secret string) *JWTService {
-	return &JWTService{secret: secret}
+// NewJWTService issues tokens valid for expiry. When set, the issuer and audience are written
+// to the iss and aud claims and required of every token validated.
+func NewJWTService(secret, issuer, audience string, expiry time.Duration) *JWTService {
+	if expiry <= 0 {
+		expiry = defaultTokenExpiry
+	}
+	return &JWTService{
+		secret:   secret,
+		issuer:   issuer,
+		audience: audience,
+		expiry:   expiry,
+	}
 }
 
-func (j *JWTService) GenerateToken(userID uint, isAdmin bool) (string, error) {
+// GenerateToken issues a token for the user. Admins scoped to a tenant carry a tenant_id claim.
+// The ver claim holds the user's token version, so changing credentials revokes the token.
+func (j *JWTService) GenerateToken(userID uint, isAdmin bool, tenantID *uint, tokenVersion int) (string, error) {
 	if j.secret == "" {
 		return "", errors.NewInternalError("JWT secret not configured", nil)
 	}
 
+	now := time.Now()
 	claims := jwt.MapClaims{
 		"user_id":  userID,
 		"is_admin": isAdmin,
-		"exp":      time.Now().Add(time.Hour * 72).Unix(),
-		"iat":      time.Now().Unix(),
+		"ver":      tokenVersion,
+		"exp":      now.Add(j.expiry).Unix(),
+		"iat":      now.Unix(),
+	}
+	if j.issuer != "" {
+		claims["iss"] = j.issuer
+	}
+	if j.audience != "" {
+		claims["aud"] = j.audience
+	}
+	if isAdmin && tenantID != nil {
+		claims["tenant_id"] = *tenantID
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -44,13 +73,21 @@ func (j *JWTService) ValidateToken(tokenStr string) (*jwt.Token, error) {
 		return nil, errors.NewInternalError("JWT secret not configured", nil)
 	}
 
+	options := []jwt.ParserOption{jwt.WithExpirationRequired()}
+	if j.issuer != "" {
+		options = append(options, jwt.WithIssuer(j.issuer))
+	}
+	if j.audience != "" {
+		options = append(options, jwt.WithAudience(j.audience))
+	}
+
 	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
 		// Validate signing method
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.NewUnauthorizedError("Invalid signing method", errors.ErrInvalidToken)
 		}
 		return []byte(j.secret), nil
-	})
+	}, options...)
 
 	if err != nil {
 		return nil, errors.NewUnauthorizedError("Invalid token", err)
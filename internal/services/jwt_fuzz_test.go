@@ -0,0 +1,53 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// FuzzValidateToken feeds malformed and tampered tokens to the JWT check. A token is only
+// accepted if it carries an HMAC signature made with the service's secret.
+func FuzzValidateToken(f *testing.F) {
+	const secret = "jwt-secret"
+	service := NewJWTService(secret, "api", "api", time.Hour)
+
+	token, err := service.GenerateToken(42, false, nil, 1)
+	if err != nil {
+		f.Fatal(err)
+	}
+	parts := strings.Split(token, ".")
+	forged, err := NewJWTService("other-secret", "api", "api", time.Hour).GenerateToken(42, true, nil, 1)
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	f.Add(token)
+	f.Add(forged)
+	f.Add(parts[0] + "." + parts[1] + ".")
+	// alg none with the original claims
+	f.Add("eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0." + parts[1] + ".")
+	f.Add(parts[0] + "." + parts[1] + "." + parts[2] + "x")
+	f.Add(strings.Repeat(".", 3))
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, tokenStr string) {
+		token, err := service.ValidateToken(tokenStr)
+		if err != nil {
+			return
+		}
+		method, ok := token.Method.(*jwt.SigningMethodHMAC)
+		if !ok {
+			t.Fatalf("accepted %q signed with %s", tokenStr, token.Method.Alg())
+		}
+		i := strings.LastIndex(tokenStr, ".")
+		if err := method.Verify(tokenStr[:i], token.Signature, []byte(secret)); err != nil {
+			t.Fatalf("accepted %q without a valid signature: %v", tokenStr, err)
+		}
+		if _, err := service.GetClaimsFromToken(tokenStr); err != nil {
+			t.Fatalf("claims of accepted token %q: %v", tokenStr, err)
+		}
+	})
+}
@@ -0,0 +1,23 @@
+package services
+
+import (
+	"api/internal/entities"
+	"api/internal/repository"
+	"context"
+)
+
+type LedgerService struct {
+	ledgerRepo *repository.LedgerRepository
+}
+
+// Ensure LedgerService implements LedgerServiceInterface
+var _ LedgerServiceInterface = (*LedgerService)(nil)
+
+func NewLedgerService(ledgerRepo *repository.LedgerRepository) *LedgerService {
+	return &LedgerService{ledgerRepo: ledgerRepo}
+}
+
+// GetBalances reports the balance of every ledger account for finance
+func (s *LedgerService) GetBalances(ctx context.Context, filter entities.LedgerFilter) (*entities.LedgerReport, error) {
+	return s.ledgerRepo.GetBalances(ctx, filter)
+}
@@ -0,0 +1,334 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/metrics"
+	redisconn "api/internal/redis"
+	"api/internal/repository"
+	logger "api/pkg/logging"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kinds of seat lock divergence between the database and Redis
+const (
+	DivergenceRedisLockMissing     = "redis_lock_missing"     // a pending intent's seat has no Redis lock
+	DivergenceRedisLockOrphaned    = "redis_lock_orphaned"    // a Redis lock no pending intent holds
+	DivergenceRedisLockConflict    = "redis_lock_conflict"    // a pending intent's seat is locked in Redis for someone else
+	DivergenceDatabaseLockOrphaned = "database_lock_orphaned" // a seat locked in the database without a pending intent
+)
+
+// maxReportedDivergences caps the seats listed in a divergence report
+const maxReportedDivergences = 100
+
+var (
+	lockDivergences = metrics.Default.NewCounterVec("seat_lock_divergences_total",
+		"Seat lock divergences between the database and Redis that outlasted the grace period, by kind.", "kind")
+	lockDivergenceRepairs = metrics.Default.NewCounterVec("seat_lock_divergence_repairs_total",
+		"Seat lock divergences repaired, by kind.", "kind")
+)
+
+// LockDivergence is one seat whose database and Redis locks disagree
+type LockDivergence struct {
+	Kind      string    `json:"kind"`
+	EventID   uint      `json:"event_id"`
+	SeatID    uint      `json:"seat_id"`
+	IntentID  uint      `json:"intent_id,omitempty"`  // the pending intent holding the seat in the database
+	LockValue string    `json:"lock_value,omitempty"` // the Redis lock's "userID:intentID"
+	FirstSeen time.Time `json:"first_seen"`
+	Repaired  bool      `json:"repaired"`
+
+	intent *entities.BookingIntent
+	seat   *entities.Seat
+}
+
+// key identifies a divergence across checks
+func (d *LockDivergence) key() string {
+	return fmt.Sprintf("%s:%d:%d:%s", d.Kind, d.SeatID, d.IntentID, d.LockValue)
+}
+
+// LockDivergenceReport is the outcome of the latest divergence check
+type LockDivergenceReport struct {
+	CheckedAt   time.Time        `json:"checked_at"`
+	Policy      string           `json:"policy"`
+	Grace       string           `json:"grace"`
+	Skipped     string           `json:"skipped,omitempty"`   // why the check didn't run
+	Divergences map[string]int   `json:"divergences"`         // outlasting the grace period, by kind
+	Repaired    map[string]int   `json:"repaired"`            // by kind
+	InGrace     int              `json:"in_grace"`            // found but still within the grace period
+	Seats       []LockDivergence `json:"seats"`               // outlasting the grace period, at most 100
+	Truncated   bool             `json:"truncated,omitempty"` // more seats diverged than are listed
+}
+
+// LockDivergenceDetector periodically compares the seat locks in the database, pending intents
+// and locked seat rows, with the locks in Redis. Seats that disagree for longer than the grace
+// period are reported and, with the repair policy, fixed: the database is authoritative, so
+// missing Redis locks are restored and orphaned locks released. Redis locks held for another
+// intent are only reported.
+type LockDivergenceDetector struct {
+	bookingRepo repository.BookingRepository
+	seatLocks   repository.SeatLockRepository
+	health      *redisconn.Health
+	policy      string
+	grace       time.Duration
+	now         func() time.Time
+	admin       *AdminNotifications
+
+	checking  sync.Mutex // held for a whole check, guarding firstSeen and reported
+	firstSeen map[string]time.Time
+	reported  map[string]bool
+
+	mu   sync.Mutex
+	last *LockDivergenceReport
+}
+
+func NewLockDivergenceDetector(bookingRepo repository.BookingRepository, seatLocks repository.SeatLockRepository, health *redisconn.Health, policy string, grace time.Duration) (*LockDivergenceDetector, error) {
+	if policy != constants.LockDivergenceRepair && policy != constants.LockDivergenceReport {
+		return nil, fmt.Errorf("unknown lock divergence policy %q, want %s or %s", policy, constants.LockDivergenceRepair, constants.LockDivergenceReport)
+	}
+	return &LockDivergenceDetector{
+		bookingRepo: bookingRepo,
+		seatLocks:   seatLocks,
+		health:      health,
+		policy:      policy,
+		grace:       grace,
+		now:         time.Now,
+		firstSeen:   make(map[string]time.Time),
+		reported:    make(map[string]bool),
+	}, nil
+}
+
+// WithClock makes the detector read the current time from now instead of the system clock
+func (d *LockDivergenceDetector) WithClock(now func() time.Time) *LockDivergenceDetector {
+	d.now = now
+	return d
+}
+
+// WithAdminNotifications tells the admins' channel about newly reported divergences
+func (d *LockDivergenceDetector) WithAdminNotifications(admin *AdminNotifications) *LockDivergenceDetector {
+	d.admin = admin
+	return d
+}
+
+// LastReport returns the outcome of the latest check, or nil before the first one
+func (d *LockDivergenceDetector) LastReport() *LockDivergenceReport {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.last
+}
+
+// Check looks for diverged seat locks and handles those past the grace period by policy. It
+// doesn't run while Redis is unavailable, when seats are expected to be locked in the
+// database only.
+func (d *LockDivergenceDetector) Check(ctx context.Context) error {
+	d.checking.Lock()
+	defer d.checking.Unlock()
+
+	now := d.now()
+	report := &LockDivergenceReport{
+		CheckedAt:   now,
+		Policy:      d.policy,
+		Grace:       d.grace.String(),
+		Divergences: make(map[string]int),
+		Repaired:    make(map[string]int),
+		Seats:       []LockDivergence{},
+	}
+	if d.health.Degraded() {
+		report.Skipped = "Redis is unavailable"
+		d.setLast(report)
+		return nil
+	}
+
+	found, err := d.find(ctx, now)
+	if err != nil {
+		if redisconn.IsUnavailable(err) {
+			report.Skipped = "Redis is unavailable"
+			d.setLast(report)
+			return nil
+		}
+		return err
+	}
+
+	// Divergences that went away, e.g. a lock being moved or released, are forgotten
+	firstSeen := make(map[string]time.Time, len(found))
+	newlyReported := make(map[string]int)
+	for i := range found {
+		divergence := &found[i]
+		key := divergence.key()
+		seen, ok := d.firstSeen[key]
+		if !ok {
+			seen = now
+		}
+		firstSeen[key] = seen
+		divergence.FirstSeen = seen
+		if now.Sub(seen) < d.grace {
+			report.InGrace++
+			continue
+		}
+
+		report.Divergences[divergence.Kind]++
+		if !d.reported[key] {
+			newlyReported[divergence.Kind]++
+			lockDivergences.Inc(divergence.Kind)
+			logger.Warnf("Seat %d of event %d has diverged locks (%s) since %s",
+				divergence.SeatID, divergence.EventID, divergence.Kind, seen.Format(time.RFC3339))
+		}
+		if d.policy == constants.LockDivergenceRepair && d.repair(ctx, divergence) {
+			divergence.Repaired = true
+			report.Repaired[divergence.Kind]++
+			lockDivergenceRepairs.Inc(divergence.Kind)
+			delete(firstSeen, key)
+		}
+		if len(report.Seats) < maxReportedDivergences {
+			report.Seats = append(report.Seats, *divergence)
+		} else {
+			report.Truncated = true
+		}
+	}
+
+	reported := make(map[string]bool, len(firstSeen))
+	for key, seen := range firstSeen {
+		if now.Sub(seen) >= d.grace {
+			reported[key] = true
+		}
+	}
+	d.firstSeen = firstSeen
+	d.reported = reported
+	d.setLast(report)
+
+	if repaired := sumCounts(report.Repaired); repaired > 0 {
+		logger.Infof("Repaired %d diverged seat locks", repaired)
+	}
+	d.admin.LockDivergences(ctx, newlyReported, d.policy == constants.LockDivergenceRepair)
+	return nil
+}
+
+func (d *LockDivergenceDetector) setLast(report *LockDivergenceReport) {
+	d.mu.Lock()
+	d.last = report
+	d.mu.Unlock()
+}
+
+// find compares the database's seat locks with Redis's
+func (d *LockDivergenceDetector) find(ctx context.Context, now time.Time) ([]LockDivergence, error) {
+	pending, err := d.bookingRepo.GetPendingIntents(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	pendingBySeat := make(map[uint]*entities.BookingIntent, len(pending))
+	for i := range pending {
+		pendingBySeat[pending[i].SeatID] = &pending[i]
+	}
+
+	eventIDs, err := d.bookingRepo.GetUpcomingEventIDs(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	events := make(map[uint]bool, len(eventIDs))
+	for _, eventID := range eventIDs {
+		events[eventID] = true
+	}
+	// Intents of events that started in the meantime still hold their seats
+	for _, intent := range pending {
+		events[intent.EventID] = true
+	}
+	sortedEvents := make([]uint, 0, len(events))
+	for eventID := range events {
+		sortedEvents = append(sortedEvents, eventID)
+	}
+	sort.Slice(sortedEvents, func(i, j int) bool { return sortedEvents[i] < sortedEvents[j] })
+
+	var found []LockDivergence
+	redisLocks := make(map[uint]map[uint]string, len(sortedEvents))
+	for _, eventID := range sortedEvents {
+		locks, err := d.seatLocks.ListLocks(ctx, eventID)
+		if err != nil {
+			return nil, err
+		}
+		redisLocks[eventID] = locks
+
+		seatIDs := make([]uint, 0, len(locks))
+		for seatID := range locks {
+			seatIDs = append(seatIDs, seatID)
+		}
+		sort.Slice(seatIDs, func(i, j int) bool { return seatIDs[i] < seatIDs[j] })
+		for _, seatID := range seatIDs {
+			if intent, ok := pendingBySeat[seatID]; ok && intent.EventID == eventID {
+				continue // compared from the intent's side below
+			}
+			found = append(found, LockDivergence{Kind: DivergenceRedisLockOrphaned, EventID: eventID, SeatID: seatID, LockValue: locks[seatID]})
+		}
+	}
+
+	for i := range pending {
+		intent := &pending[i]
+		value, locked := redisLocks[intent.EventID][intent.SeatID]
+		switch {
+		case !locked:
+			found = append(found, LockDivergence{Kind: DivergenceRedisLockMissing, EventID: intent.EventID, SeatID: intent.SeatID, IntentID: intent.ID, intent: intent})
+		case value != fmt.Sprintf("%d:%s", intent.UserID, intentLockID(intent)):
+			found = append(found, LockDivergence{Kind: DivergenceRedisLockConflict, EventID: intent.EventID, SeatID: intent.SeatID, IntentID: intent.ID, LockValue: value, intent: intent})
+		}
+	}
+
+	seats, err := d.bookingRepo.GetDatabaseLockedSeats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range seats {
+		if _, ok := pendingBySeat[seats[i].ID]; !ok {
+			found = append(found, LockDivergence{Kind: DivergenceDatabaseLockOrphaned, EventID: seats[i].EventID, SeatID: seats[i].ID, seat: &seats[i]})
+		}
+	}
+	return found, nil
+}
+
+// repair fixes a divergence in favour of the database, reporting whether it did
+func (d *LockDivergenceDetector) repair(ctx context.Context, divergence *LockDivergence) bool {
+	switch divergence.Kind {
+	case DivergenceRedisLockMissing:
+		intent := divergence.intent
+		outcome, _, err := d.seatLocks.AlignLock(ctx, intent.EventID, intent.SeatID, intent.UserID, intentLockID(intent), intent.LockExpiresAt, time.Second)
+		if err != nil {
+			warnLockError("restore diverged seat lock", err)
+			return false
+		}
+		return outcome == repository.LockRestored
+	case DivergenceRedisLockOrphaned:
+		userID, intentID, ok := strings.Cut(divergence.LockValue, ":")
+		owner, err := strconv.ParseUint(userID, 10, 32)
+		if !ok || err != nil {
+			logger.Warnf("Can't release seat lock %q of seat %d: not a lock value", divergence.LockValue, divergence.SeatID)
+			return false
+		}
+		// Only deletes the lock if it still has the value found
+		if err := d.seatLocks.UnlockSeat(ctx, divergence.EventID, divergence.SeatID, uint(owner), intentID); err != nil {
+			warnLockError("release orphaned seat lock", err)
+			return false
+		}
+		return true
+	case DivergenceDatabaseLockOrphaned:
+		released, err := d.bookingRepo.ReleaseDatabaseLock(ctx, divergence.seat)
+		if err != nil {
+			logger.Warnf("Failed to release orphaned database lock of seat %d: %v", divergence.SeatID, err)
+			return false
+		}
+		return released
+	}
+	// Which of two holders should keep the seat needs a person to decide
+	return false
+}
+
+func sumCounts(counts map[string]int) int {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	return total
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"api/internal/entities"
+	"api/internal/repository"
+	"api/pkg/errors"
+	"context"
+	"time"
+)
+
+type LoyaltyService struct {
+	loyaltyRepo *repository.LoyaltyRepository
+}
+
+// Ensure LoyaltyService implements LoyaltyServiceInterface
+var _ LoyaltyServiceInterface = (*LoyaltyService)(nil)
+
+func NewLoyaltyService(loyaltyRepo *repository.LoyaltyRepository) *LoyaltyService {
+	return &LoyaltyService{
+		loyaltyRepo: loyaltyRepo,
+	}
+}
+
+// GetSummary returns the user's points, tier and the points still needed for the next tier
+func (s *LoyaltyService) GetSummary(ctx context.Context, userID uint) (*entities.LoyaltySummary, error) {
+	account, err := s.loyaltyRepo.GetAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &entities.LoyaltySummary{
+		Points:         account.LoyaltyPoints,
+		LifetimePoints: account.LifetimePoints,
+		Tier:           account.MembershipTier,
+	}
+	for _, tier := range entities.LoyaltyTiers {
+		if account.LifetimePoints < tier.Threshold {
+			summary.NextTier = tier.Name
+			summary.PointsToNextTier = tier.Threshold - account.LifetimePoints
+			break
+		}
+	}
+
+	return summary, nil
+}
+
+func (s *LoyaltyService) ListTransactions(ctx context.Context, userID uint, limit, offset int) ([]entities.LoyaltyTransaction, int64, error) {
+	return s.loyaltyRepo.ListTransactions(ctx, userID, limit, offset)
+}
+
+// NormalizeEarlyAccess checks that early access opens before the general on-sale and returns the
+// canonical tier name. Without a tier nobody can book before the on-sale.
+func NormalizeEarlyAccess(onSaleAt, earlyAccessAt *time.Time, tier string) (string, error) {
+	if tier != "" {
+		normalized, err := NormalizeWaitlistTiers([]string{tier})
+		if err != nil {
+			return "", err
+		}
+		tier = normalized
+	}
+	if earlyAccessAt != nil && onSaleAt != nil && !earlyAccessAt.Before(*onSaleAt) {
+		return "", errors.NewBadRequestError("early_access_at must be before on_sale_at", nil)
+	}
+	return tier, nil
+}
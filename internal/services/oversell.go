@@ -0,0 +1,63 @@
+package services
+
+import (
+	"api/internal/domain"
+	"api/internal/repository"
+	logger "api/pkg/logging"
+	"context"
+	"time"
+)
+
+// OversellService books the oversell places of free events for their waitlists. Free events
+// expect some of their bookings not to turn up, so once their seats are sold out the users
+// next in line are booked straight into the places left, without the hold a released seat
+// is offered with.
+type OversellService struct {
+	oversellRepo *repository.OversellRepository
+	waitlist     *WaitlistService
+	events       *domain.Dispatcher
+	ticketLimit  int // free tickets per user for events without a ticket limit, 0 is unlimited
+}
+
+func NewOversellService(oversellRepo *repository.OversellRepository, waitlist *WaitlistService, events *domain.Dispatcher, ticketLimit int) *OversellService {
+	return &OversellService{
+		oversellRepo: oversellRepo,
+		waitlist:     waitlist,
+		events:       events,
+		ticketLimit:  ticketLimit,
+	}
+}
+
+// PromoteWaitlists books each sold out free event's oversell places left for the users at the
+// head of its waitlist, for the scheduler. Users are taken off the waitlist as they're booked
+// and put back in their place if their booking fails. The confirmations tell them they're booked.
+func (s *OversellService) PromoteWaitlists(ctx context.Context) error {
+	openings, err := s.oversellRepo.ListOpenings(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, opening := range openings {
+		eventID := opening.EventID
+		_, err := s.waitlist.BookFromWaitlist(ctx, eventID, opening.Places, func(ctx context.Context, userID uint) error {
+			now := time.Now()
+			booking, err := s.oversellRepo.BookPlace(ctx, eventID, userID, s.ticketLimit, now)
+			if err != nil {
+				return err
+			}
+			s.events.Publish(ctx, domain.BookingConfirmed{
+				BookingID:  booking.ID,
+				UserID:     booking.UserID,
+				EventID:    booking.EventID,
+				SeatID:     booking.SeatID,
+				Oversell:   true,
+				OccurredAt: now,
+			})
+			return nil
+		})
+		if err != nil {
+			logger.Warnf("Failed to book oversell places of event %d from its waitlist: %v", eventID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"api/internal/entities"
+	"api/internal/repository"
+	"context"
+)
+
+type PaymentService struct {
+	paymentRepo *repository.PaymentRepository
+}
+
+// Ensure PaymentService implements PaymentServiceInterface
+var _ PaymentServiceInterface = (*PaymentService)(nil)
+
+func NewPaymentService(paymentRepo *repository.PaymentRepository) *PaymentService {
+	return &PaymentService{
+		paymentRepo: paymentRepo,
+	}
+}
+
+func (s *PaymentService) ListTransactions(ctx context.Context, filter entities.PaymentTransactionFilter, limit, offset int) ([]entities.PaymentTransaction, int64, error) {
+	return s.paymentRepo.ListTransactions(ctx, filter, limit, offset)
+}
+
+func (s *PaymentService) GetTransaction(ctx context.Context, transactionID uint) (*entities.PaymentTransaction, error) {
+	return s.paymentRepo.GetTransaction(ctx, transactionID)
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/repository"
+	"api/pkg/errors"
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// upToDateCacheSize is how many users known to have accepted the current policies are kept
+// before the cache starts over
+const upToDateCacheSize = 100000
+
+// PolicyService tracks which versions of the terms of service and privacy policy each user
+// accepted. Users accept the current versions at registration, and again once a version is
+// bumped before they can use the API. Policies without a configured version aren't required.
+type PolicyService struct {
+	repo     *repository.PolicyRepository
+	versions map[string]string // current version by policy
+	now      func() time.Time
+
+	// Acceptances are never withdrawn, so users seen up to date stay so until the next deploy
+	// bumping a version
+	mu       sync.Mutex
+	upToDate map[uint]bool
+}
+
+// Ensure PolicyService implements PolicyServiceInterface
+var _ PolicyServiceInterface = (*PolicyService)(nil)
+
+func NewPolicyService(repo *repository.PolicyRepository, termsVersion, privacyVersion string) *PolicyService {
+	versions := make(map[string]string)
+	if v := strings.TrimSpace(termsVersion); v != "" {
+		versions[constants.PolicyTermsOfService] = v
+	}
+	if v := strings.TrimSpace(privacyVersion); v != "" {
+		versions[constants.PolicyPrivacy] = v
+	}
+	return &PolicyService{
+		repo:     repo,
+		versions: versions,
+		now:      time.Now,
+		upToDate: make(map[uint]bool),
+	}
+}
+
+// CurrentVersions returns the version users must have accepted, by policy
+func (s *PolicyService) CurrentVersions() map[string]string {
+	versions := make(map[string]string, len(s.versions))
+	for policy, version := range s.versions {
+		versions[policy] = version
+	}
+	return versions
+}
+
+// CheckAcceptance checks that the user agreed to the versions they were shown and that those
+// are still current
+func (s *PolicyService) CheckAcceptance(consent entities.PolicyConsent) error {
+	if len(s.versions) == 0 {
+		return nil
+	}
+	if !consent.Accept {
+		return errors.NewBadRequestError(constants.ErrPoliciesNotAccepted, nil)
+	}
+	shown := map[string]string{
+		constants.PolicyTermsOfService: strings.TrimSpace(consent.TermsVersion),
+		constants.PolicyPrivacy:        strings.TrimSpace(consent.PrivacyVersion),
+	}
+	for policy, version := range s.versions {
+		if shown[policy] != version {
+			return errors.NewBadRequestError(constants.ErrPolicyVersionChanged, nil)
+		}
+	}
+	return nil
+}
+
+// Accept records the user accepting the current versions, from the given IP address
+func (s *PolicyService) Accept(ctx context.Context, userID uint, consent entities.PolicyConsent, ip string) ([]entities.PolicyAcceptance, error) {
+	if err := s.CheckAcceptance(consent); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	acceptances := make([]entities.PolicyAcceptance, 0, len(s.versions))
+	for _, policy := range s.policies() {
+		acceptances = append(acceptances, entities.PolicyAcceptance{
+			UserID:     userID,
+			Policy:     policy,
+			Version:    s.versions[policy],
+			IPAddress:  ip,
+			AcceptedAt: now,
+		})
+	}
+	if err := s.repo.Record(ctx, acceptances); err != nil {
+		return nil, err
+	}
+	return acceptances, nil
+}
+
+// PendingPolicies returns the policies whose current version the user hasn't accepted
+func (s *PolicyService) PendingPolicies(ctx context.Context, userID uint) ([]string, error) {
+	if len(s.versions) == 0 {
+		return nil, nil
+	}
+	s.mu.Lock()
+	upToDate := s.upToDate[userID]
+	s.mu.Unlock()
+	if upToDate {
+		return nil, nil
+	}
+
+	accepted, err := s.repo.AcceptedPolicies(ctx, userID, s.versions)
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[string]bool, len(accepted))
+	for _, policy := range accepted {
+		done[policy] = true
+	}
+	var pending []string
+	for _, policy := range s.policies() {
+		if !done[policy] {
+			pending = append(pending, policy)
+		}
+	}
+
+	if len(pending) == 0 {
+		s.mu.Lock()
+		if len(s.upToDate) >= upToDateCacheSize {
+			s.upToDate = make(map[uint]bool)
+		}
+		s.upToDate[userID] = true
+		s.mu.Unlock()
+	}
+	return pending, nil
+}
+
+// ListAcceptances returns the user's acceptance history, newest first
+func (s *PolicyService) ListAcceptances(ctx context.Context, userID uint) ([]entities.PolicyAcceptance, error) {
+	return s.repo.List(ctx, userID)
+}
+
+// policies returns the required policies in a stable order
+func (s *PolicyService) policies() []string {
+	policies := make([]string, 0, len(s.versions))
+	for policy := range s.versions {
+		policies = append(policies, policy)
+	}
+	sort.Strings(policies)
+	return policies
+}
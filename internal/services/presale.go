@@ -0,0 +1,42 @@
+package services
+
+import (
+	"api/internal/entities"
+	"api/internal/repository"
+	"context"
+)
+
+type PresaleService struct {
+	presaleRepo *repository.PresaleRepository
+}
+
+// Ensure PresaleService implements PresaleServiceInterface
+var _ PresaleServiceInterface = (*PresaleService)(nil)
+
+func NewPresaleService(presaleRepo *repository.PresaleRepository) *PresaleService {
+	return &PresaleService{
+		presaleRepo: presaleRepo,
+	}
+}
+
+// CreateBatch generates a batch of presale codes for an event, each usable maxUses times (0 = unlimited)
+func (s *PresaleService) CreateBatch(ctx context.Context, eventID uint, name string, count, maxUses int, createdBy uint) (*entities.PresaleBatch, error) {
+	batch := &entities.PresaleBatch{
+		EventID:   eventID,
+		Name:      name,
+		MaxUses:   maxUses,
+		CreatedBy: createdBy,
+	}
+	if err := s.presaleRepo.CreateBatch(ctx, batch, count); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+func (s *PresaleService) ListBatches(ctx context.Context, eventID uint) ([]entities.PresaleBatchSummary, error) {
+	return s.presaleRepo.ListBatches(ctx, eventID)
+}
+
+func (s *PresaleService) GetBatch(ctx context.Context, batchID uint) (*entities.PresaleBatch, error) {
+	return s.presaleRepo.GetBatch(ctx, batchID)
+}
@@ -0,0 +1,319 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/domain"
+	"api/internal/entities"
+	redisconn "api/internal/redis"
+	"api/internal/repository"
+	"api/pkg/errors"
+	logger "api/pkg/logging"
+	"context"
+	"sync"
+	"time"
+)
+
+// queueTokensTTL is how long QueueToken reuses the admitted users it loaded
+const queueTokensTTL = 5 * time.Second
+
+// QueueService runs the on-sale queues of high-demand events: users join before tickets go on
+// sale and are admitted to book in join order, QueueMaxActive at a time. It is separate from
+// the waitlist, which users join once an event has sold out.
+type QueueService struct {
+	queueRepo repository.QueueRepository
+	eventRepo repository.EventRepository
+	health    *redisconn.Health
+
+	admin      *AdminNotifications
+	saturation int // queue length admins are told about, 0 disables it
+
+	tokensMu sync.Mutex
+	tokens   *queueTokens
+}
+
+// queueTokens is a snapshot of who holds an admission from the on-sale queues
+type queueTokens struct {
+	onSale   bool
+	admitted map[uint]bool
+	loadedAt time.Time
+}
+
+// Ensure QueueService implements QueueServiceInterface
+var _ QueueServiceInterface = (*QueueService)(nil)
+
+// NewQueueService keeps queues in the database and mirrors who is waiting to Redis for
+// positions, falling back to the database while health reports Redis unavailable
+func NewQueueService(queueRepo repository.QueueRepository, eventRepo repository.EventRepository, health *redisconn.Health) *QueueService {
+	return &QueueService{
+		queueRepo: queueRepo,
+		eventRepo: eventRepo,
+		health:    health,
+	}
+}
+
+// WithSaturationAlerts tells the admins' channel when an event's queue reaches saturation
+// waiting users
+func (s *QueueService) WithSaturationAlerts(admin *AdminNotifications, saturation int) *QueueService {
+	s.admin = admin
+	s.saturation = saturation
+	return s
+}
+
+// Subscribe follows the booking workflow: a user who books has used their place in the queue
+func (s *QueueService) Subscribe(events *domain.Dispatcher) {
+	domain.Subscribe(events, "queue", func(ctx context.Context, event domain.BookingConfirmed) error {
+		entry, err := s.queueRepo.CompleteEntry(ctx, event.UserID, event.EventID)
+		if err != nil {
+			if appErr, ok := err.(*errors.AppError); ok && appErr.Type == "NOT_FOUND" {
+				return nil
+			}
+			return err
+		}
+		s.dequeue(ctx, entry.EventID, entry.UserID)
+		return nil
+	})
+}
+
+// JoinQueue puts a user in the on-sale queue of a high-demand event. Joining again returns the
+// user's current place.
+func (s *QueueService) JoinQueue(ctx context.Context, userID, eventID uint) (*entities.EventQueue, error) {
+	event, err := s.eventRepo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status != constants.EventStatusActive {
+		return nil, errors.NewBadRequestError("Event is not active", nil)
+	}
+	if !event.IsHighDemand {
+		return nil, errors.NewBadRequestError(constants.ErrNoQueue, nil)
+	}
+	now := time.Now()
+	if !event.StartTime.After(now) {
+		return nil, errors.NewBadRequestError("Event has already started", nil)
+	}
+
+	if entry, err := s.queueRepo.GetEntry(ctx, userID, eventID); err == nil {
+		return s.withPosition(ctx, entry)
+	} else if appErr, ok := err.(*errors.AppError); !ok || appErr.Type != "NOT_FOUND" {
+		return nil, err
+	}
+
+	waiting, err := s.queueRepo.CountWaiting(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	entry := &entities.EventQueue{
+		EventID:       eventID,
+		UserID:        userID,
+		Kind:          constants.QueueKindOnSale,
+		QueuePosition: int(waiting) + 1,
+		Status:        constants.QueueStatusWaiting,
+		JoinedAt:      now,
+	}
+	created, err := s.queueRepo.CreateEntry(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		// A concurrent request joined first
+		existing, err := s.queueRepo.GetEntry(ctx, userID, eventID)
+		if err != nil {
+			return nil, err
+		}
+		return s.withPosition(ctx, existing)
+	}
+	// Reported once, by the join that reaches the threshold
+	if s.saturation > 0 && entry.QueuePosition == s.saturation {
+		s.admin.QueueSaturated(ctx, eventID, entry.QueuePosition)
+	}
+
+	// While degraded the user is queued in Redis on recovery
+	if !s.health.Degraded() {
+		if err := s.queueRepo.Enqueue(ctx, eventID, userID, entry.JoinedAt); err != nil && !redisconn.IsUnavailable(err) {
+			logger.Warnf("Failed to add user %d to the Redis queue for event %d: %v", userID, eventID, err)
+		}
+	}
+	return s.withPosition(ctx, entry)
+}
+
+// LeaveQueue gives up the user's place in an event's queue, or their admission if already
+// admitted
+func (s *QueueService) LeaveQueue(ctx context.Context, userID, eventID uint) error {
+	entry, err := s.queueRepo.CancelEntry(ctx, userID, eventID)
+	if err != nil {
+		return err
+	}
+	s.dequeue(ctx, entry.EventID, entry.UserID)
+	return nil
+}
+
+// GetQueueStatus returns the user's entry in an event's queue with their current position, 0
+// once admitted
+func (s *QueueService) GetQueueStatus(ctx context.Context, userID, eventID uint) (*entities.EventQueue, error) {
+	entry, err := s.queueRepo.GetEntry(ctx, userID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return s.withPosition(ctx, entry)
+}
+
+// GetQueueLength returns how many users are waiting in an event's queue
+func (s *QueueService) GetQueueLength(ctx context.Context, eventID uint) (int64, error) {
+	if !s.health.Degraded() {
+		length, err := s.queueRepo.Length(ctx, eventID)
+		if err == nil {
+			return length, nil
+		}
+		if !redisconn.IsUnavailable(err) {
+			logger.Warnf("Failed to get the Redis queue length for event %d: %v", eventID, err)
+		}
+	}
+	return s.queueRepo.CountWaiting(ctx, eventID)
+}
+
+// QueueToken reports whether any on-sale queue is running and whether the user holds an active
+// admission from one, for the booking rate limits. It runs on every booking request, so the
+// answer comes from a snapshot at most queueTokensTTL old; if it can't be loaded no on-sale is
+// assumed and the default limits apply.
+func (s *QueueService) QueueToken(ctx context.Context, userID uint) (onSale, admitted bool) {
+	s.tokensMu.Lock()
+	defer s.tokensMu.Unlock()
+
+	now := time.Now()
+	if s.tokens == nil || now.Sub(s.tokens.loadedAt) >= queueTokensTTL {
+		tokens, err := s.loadTokens(ctx, now)
+		if err != nil {
+			logger.Warnf("Failed to load queue admissions for rate limiting: %v", err)
+			return false, false
+		}
+		s.tokens = tokens
+	}
+	return s.tokens.onSale, s.tokens.admitted[userID]
+}
+
+func (s *QueueService) loadTokens(ctx context.Context, now time.Time) (*queueTokens, error) {
+	userIDs, err := s.queueRepo.GetAdmittedUsers(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	tokens := &queueTokens{onSale: len(userIDs) > 0, admitted: make(map[uint]bool, len(userIDs)), loadedAt: now}
+	for _, userID := range userIDs {
+		tokens.admitted[userID] = true
+	}
+	if !tokens.onSale {
+		// Queues still waiting for their on-sale to open aren't running yet
+		eventIDs, err := s.queueRepo.GetEventsToAdmit(ctx, now)
+		if err != nil {
+			return nil, err
+		}
+		tokens.onSale = len(eventIDs) > 0
+	}
+	return tokens, nil
+}
+
+// withPosition sets the entry's live queue position from Redis, or from the database when
+// Redis can't tell
+func (s *QueueService) withPosition(ctx context.Context, entry *entities.EventQueue) (*entities.EventQueue, error) {
+	if entry.Status != constants.QueueStatusWaiting {
+		entry.QueuePosition = 0
+		return entry, nil
+	}
+
+	if !s.health.Degraded() {
+		rank, ok, err := s.queueRepo.Rank(ctx, entry.EventID, entry.UserID)
+		if err == nil && ok {
+			entry.QueuePosition = int(rank) + 1
+			return entry, nil
+		}
+		if err != nil && !redisconn.IsUnavailable(err) {
+			logger.Warnf("Failed to get the Redis queue position of user %d for event %d: %v", entry.UserID, entry.EventID, err)
+		}
+	}
+
+	ahead, err := s.queueRepo.CountAhead(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	entry.QueuePosition = int(ahead) + 1
+	return entry, nil
+}
+
+// dequeue removes users from an event's Redis queue; while degraded they are removed on
+// recovery
+func (s *QueueService) dequeue(ctx context.Context, eventID uint, userIDs ...uint) {
+	if s.health.Degraded() {
+		return
+	}
+	if err := s.queueRepo.Dequeue(ctx, eventID, userIDs...); err != nil && !redisconn.IsUnavailable(err) {
+		logger.Warnf("Failed to remove %d users from the Redis queue for event %d: %v", len(userIDs), eventID, err)
+	}
+}
+
+// AdmitQueues ends admissions that ran out and admits the next users of every on-sale queue,
+// keeping at most QueueMaxActive users of each event admitted for QueueActiveDuration minutes
+func (s *QueueService) AdmitQueues(ctx context.Context) error {
+	now := time.Now()
+	expired, err := s.queueRepo.ExpireAdmissions(ctx, now)
+	if err != nil {
+		return err
+	}
+	if expired > 0 {
+		logger.Infof("Expired %d unused queue admissions", expired)
+	}
+
+	eventIDs, err := s.queueRepo.GetEventsToAdmit(ctx, now)
+	if err != nil {
+		return err
+	}
+	expiresAt := now.Add(constants.QueueActiveDuration * time.Minute)
+	admittedAny := false
+	for _, eventID := range eventIDs {
+		admitted, err := s.queueRepo.AdmitNext(ctx, eventID, constants.QueueMaxActive, now, expiresAt)
+		if err != nil {
+			logger.Warnf("Failed to admit users from the queue for event %d: %v", eventID, err)
+			continue
+		}
+		if len(admitted) == 0 {
+			continue
+		}
+		userIDs := make([]uint, len(admitted))
+		for i, entry := range admitted {
+			userIDs[i] = entry.UserID
+		}
+		s.dequeue(ctx, eventID, userIDs...)
+		logger.Infof("Admitted %d users from the queue for event %d", len(admitted), eventID)
+		admittedAny = true
+	}
+	if admittedAny {
+		// Users admitted here get their booking quota without waiting for the snapshot to age
+		s.tokensMu.Lock()
+		s.tokens = nil
+		s.tokensMu.Unlock()
+	}
+	return nil
+}
+
+// RestoreQueues rebuilds the Redis queues of events whose queue changed in the database while
+// Redis was unavailable. It runs when Redis recovers.
+func (s *QueueService) RestoreQueues(ctx context.Context, since time.Time) {
+	eventIDs, err := s.queueRepo.GetChangedEvents(ctx, since)
+	if err != nil {
+		logger.Errorf("Failed to load queue changes to restore: %v", err)
+		return
+	}
+
+	for _, eventID := range eventIDs {
+		waiting, err := s.queueRepo.GetWaiting(ctx, eventID)
+		if err != nil {
+			logger.Warnf("Failed to restore the queue for event %d: %v", eventID, err)
+			continue
+		}
+		if err := s.queueRepo.Rebuild(ctx, eventID, waiting); err != nil {
+			logger.Warnf("Failed to restore the queue for event %d: %v", eventID, err)
+		}
+	}
+
+	if len(eventIDs) > 0 {
+		logger.Infof("Rebuilt %d queues changed while Redis was unavailable", len(eventIDs))
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/repository"
+	"api/pkg/errors"
+	"context"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// referrerDashboardCommissions caps the commissions listed on a referrer dashboard
+const referrerDashboardCommissions = 50
+
+var referralCodePattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9-]{2,48}[A-Z0-9]$`)
+
+type ReferralService struct {
+	referralRepo *repository.ReferralRepository
+	// defaultRate is the commission, in percent, of referrers created without a rate of their own
+	defaultRate float64
+}
+
+// Ensure ReferralService implements ReferralServiceInterface
+var _ ReferralServiceInterface = (*ReferralService)(nil)
+
+func NewReferralService(referralRepo *repository.ReferralRepository, defaultRate float64) *ReferralService {
+	return &ReferralService{
+		referralRepo: referralRepo,
+		defaultRate:  defaultRate,
+	}
+}
+
+// CreatePartner sets up an affiliate partner. Without a code one is generated, and without a
+// rate the default commission applies; userID links the account that sees the dashboard.
+func (s *ReferralService) CreatePartner(ctx context.Context, name, email, code string, rate *float64, userID *uint) (*entities.Referrer, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code != "" && !referralCodePattern.MatchString(code) {
+		return nil, errors.NewBadRequestError("Referral codes are 4 to 50 letters, digits and dashes", nil)
+	}
+	referrer := &entities.Referrer{
+		Code:           code,
+		Kind:           constants.ReferrerKindPartner,
+		Name:           strings.TrimSpace(name),
+		Email:          strings.TrimSpace(email),
+		UserID:         userID,
+		CommissionRate: s.defaultRate,
+		Active:         true,
+	}
+	if rate != nil {
+		referrer.CommissionRate = *rate
+	}
+	if err := s.referralRepo.CreateReferrer(ctx, referrer); err != nil {
+		return nil, err
+	}
+	return referrer, nil
+}
+
+// GetUserCode returns the user's own referral code, creating it on first use
+func (s *ReferralService) GetUserCode(ctx context.Context, userID uint) (*entities.Referrer, error) {
+	return s.referralRepo.EnsureUserReferrer(ctx, userID, s.defaultRate)
+}
+
+// UpdateReferrer changes a referrer; a new commission rate applies to later bookings only
+func (s *ReferralService) UpdateReferrer(ctx context.Context, referrerID uint, updates map[string]interface{}) (*entities.Referrer, error) {
+	return s.referralRepo.UpdateReferrer(ctx, referrerID, updates)
+}
+
+// ListReferrers returns every referrer with the totals of their code
+func (s *ReferralService) ListReferrers(ctx context.Context) ([]entities.ReferrerSummary, error) {
+	referrers, err := s.referralRepo.ListReferrers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.summarize(ctx, referrers)
+}
+
+// GetDashboard returns the codes a user manages with their totals and latest commissions
+func (s *ReferralService) GetDashboard(ctx context.Context, userID uint) (*entities.ReferrerDashboard, error) {
+	referrers, err := s.referralRepo.ListUserReferrers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	summaries, err := s.summarize(ctx, referrers)
+	if err != nil {
+		return nil, err
+	}
+	commissions, err := s.referralRepo.ListCommissions(ctx, referrerIDs(referrers), referrerDashboardCommissions)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.ReferrerDashboard{Referrers: summaries, Commissions: commissions}, nil
+}
+
+// GetPayouts returns the commission owed to each referrer: on confirmed bookings of events
+// that have ended, not paid out yet
+func (s *ReferralService) GetPayouts(ctx context.Context) ([]entities.ReferrerPayout, error) {
+	return s.referralRepo.GetPayouts(ctx, time.Now())
+}
+
+// PayOut records that everything owed to a referrer was paid
+func (s *ReferralService) PayOut(ctx context.Context, referrerID uint) (*entities.ReferrerPayout, error) {
+	payout, err := s.referralRepo.MarkPaid(ctx, referrerID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if payout.Commissions == 0 {
+		return nil, errors.NewBadRequestError("Nothing is owed to this referrer", nil)
+	}
+	return payout, nil
+}
+
+func (s *ReferralService) summarize(ctx context.Context, referrers []entities.Referrer) ([]entities.ReferrerSummary, error) {
+	stats, err := s.referralRepo.GetStats(ctx, referrerIDs(referrers), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]entities.ReferrerSummary, len(referrers))
+	for i, referrer := range referrers {
+		summaries[i] = entities.ReferrerSummary{Referrer: referrer, Stats: stats[referrer.ID]}
+	}
+	return summaries, nil
+}
+
+func referrerIDs(referrers []entities.Referrer) []uint {
+	ids := make([]uint, len(referrers))
+	for i, referrer := range referrers {
+		ids[i] = referrer.ID
+	}
+	return ids
+}
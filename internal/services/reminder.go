@@ -0,0 +1,160 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/notifications"
+	"api/internal/repository"
+	"api/pkg/errors"
+	logger "api/pkg/logging"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type ReminderService struct {
+	reminderRepo *repository.ReminderRepository
+	notifier     notifications.Notifier
+}
+
+func NewReminderService(reminderRepo *repository.ReminderRepository, notifier notifications.Notifier) *ReminderService {
+	return &ReminderService{
+		reminderRepo: reminderRepo,
+		notifier:     notifier,
+	}
+}
+
+// SendDueReminders notifies confirmed attendees of events whose reminder offsets have been reached.
+// Each (booking, offset) pair is claimed in the database before sending, so reminders are never repeated.
+func (s *ReminderService) SendDueReminders(ctx context.Context) error {
+	now := time.Now()
+	events, err := s.reminderRepo.GetEventsStartingBetween(ctx, now, now.Add(constants.MaxReminderOffsetHours*time.Hour))
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		offsets, err := ParseReminderOffsets(event.ReminderOffsets)
+		if err != nil {
+			logger.Warnf("Skipping reminders for event %d: %v", event.ID, err)
+			continue
+		}
+
+		offset, due := dueReminderOffset(offsets, event.StartTime.Sub(now))
+		if !due {
+			continue
+		}
+		if err := s.sendEventReminders(ctx, &event, offset); err != nil {
+			logger.Errorf("Failed to send %s reminders for event %d: %v", offset, event.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// sendEventReminders sends the reminder for one offset to every booking that hasn't received it
+func (s *ReminderService) sendEventReminders(ctx context.Context, event *entities.Event, offset string) error {
+	bookings, err := s.reminderRepo.GetBookingsMissingReminder(ctx, event.ID, offset)
+	if err != nil {
+		return err
+	}
+
+	for _, booking := range bookings {
+		reminder, claimed, err := s.reminderRepo.ClaimReminder(ctx, booking.ID, offset)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		notification := notifications.Notification{
+			Type:      constants.NotificationTypeBookingReminder,
+			UserID:    booking.UserID,
+			Recipient: booking.User.Email,
+			EventID:   event.ID,
+			BookingID: booking.ID,
+			Subject:   fmt.Sprintf("Reminder: %s starts soon", event.Name),
+			Message: fmt.Sprintf("%s starts at %s at %s. Your seat: row %d, seat %d.",
+				event.Name, event.StartTime.Format(time.RFC1123), event.Venue.Name, booking.Seat.Row, booking.Seat.Column),
+		}
+
+		if err := s.notifier.Send(ctx, notification); err != nil {
+			if markErr := s.reminderRepo.MarkReminderFailed(ctx, reminder.ID, err); markErr != nil {
+				return markErr
+			}
+			continue
+		}
+
+		if err := s.reminderRepo.MarkReminderSent(ctx, reminder.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dueReminderOffset returns the reminder offset currently due for an event starting in untilStart.
+// Only the smallest reached offset is considered, so an attendee who books after several offsets
+// have passed receives a single reminder rather than one per missed offset.
+func dueReminderOffset(offsets []time.Duration, untilStart time.Duration) (string, bool) {
+	var due time.Duration
+	for _, offset := range offsets {
+		if untilStart <= offset && (due == 0 || offset < due) {
+			due = offset
+		}
+	}
+	if due == 0 {
+		return "", false
+	}
+	return formatReminderOffset(due), true
+}
+
+// ParseReminderOffsets parses a comma-separated list of durations such as "24h,2h"
+func ParseReminderOffsets(value string) ([]time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	var offsets []time.Duration
+	for _, part := range strings.Split(value, ",") {
+		offset, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil {
+			return nil, errors.NewBadRequestError(fmt.Sprintf("Invalid reminder offset %q", part), err)
+		}
+		if offset <= 0 || offset > constants.MaxReminderOffsetHours*time.Hour {
+			return nil, errors.NewBadRequestError(fmt.Sprintf("Reminder offset %q must be between 0 and %dh", part, constants.MaxReminderOffsetHours), nil)
+		}
+		offsets = append(offsets, offset)
+	}
+
+	return offsets, nil
+}
+
+// NormalizeReminderOffsets validates the offsets and renders them in canonical form, e.g. ["1440m", "2h"] -> "24h,2h"
+func NormalizeReminderOffsets(values []string) (string, error) {
+	offsets, err := ParseReminderOffsets(strings.Join(values, ","))
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]string, len(offsets))
+	for i, offset := range offsets {
+		parts[i] = formatReminderOffset(offset)
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// formatReminderOffset renders a duration compactly, e.g. 24h0m0s -> 24h, 1h30m0s -> 1h30m
+func formatReminderOffset(offset time.Duration) string {
+	formatted := offset.String()
+	if strings.HasSuffix(formatted, "m0s") {
+		formatted = strings.TrimSuffix(formatted, "0s")
+	}
+	if strings.HasSuffix(formatted, "h0m") {
+		formatted = strings.TrimSuffix(formatted, "0m")
+	}
+	return formatted
+}
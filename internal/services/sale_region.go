@@ -0,0 +1,106 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/geoip"
+	"api/internal/repository"
+	"api/pkg/errors"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SaleRegionService restricts the countries an event's tickets are sold in. The client's
+// country comes from the GeoIP middleware; admins can let single users book from anywhere.
+type SaleRegionService struct {
+	saleRegionRepo *repository.SaleRegionRepository
+}
+
+// Ensure SaleRegionService implements SaleRegionServiceInterface
+var _ SaleRegionServiceInterface = (*SaleRegionService)(nil)
+
+func NewSaleRegionService(saleRegionRepo *repository.SaleRegionRepository) *SaleRegionService {
+	return &SaleRegionService{
+		saleRegionRepo: saleRegionRepo,
+	}
+}
+
+// CheckSale rejects intents for an event with sale countries from clients in other countries,
+// or whose country is unknown, unless the user has an override
+func (s *SaleRegionService) CheckSale(ctx context.Context, event *entities.Event, userID uint, country string) error {
+	countries := ParseSaleCountries(event.SaleCountries)
+	if len(countries) == 0 {
+		return nil
+	}
+	for _, allowed := range countries {
+		if allowed == country {
+			return nil
+		}
+	}
+
+	override, err := s.saleRegionRepo.HasOverride(ctx, event.ID, userID)
+	if err != nil {
+		return err
+	}
+	if override {
+		return nil
+	}
+	if country == "" {
+		return errors.NewForbiddenError(constants.ErrSaleRegionUnknown, nil).WithCode(constants.CodeSaleRegionUnknown)
+	}
+	return errors.NewForbiddenError(constants.ErrSaleRegionNotAllowed, nil).WithCode(constants.CodeSaleRegionNotAllowed)
+}
+
+func (s *SaleRegionService) ListOverrides(ctx context.Context, eventID uint) ([]entities.SaleRegionOverride, error) {
+	return s.saleRegionRepo.ListOverrides(ctx, eventID)
+}
+
+// AddOverride lets a user book an event from any country
+func (s *SaleRegionService) AddOverride(ctx context.Context, eventID, userID uint, note string, createdBy uint) (*entities.SaleRegionOverride, error) {
+	override := &entities.SaleRegionOverride{
+		EventID:   eventID,
+		UserID:    userID,
+		Note:      strings.TrimSpace(note),
+		CreatedBy: createdBy,
+	}
+	if err := s.saleRegionRepo.AddOverride(ctx, override); err != nil {
+		return nil, err
+	}
+	return override, nil
+}
+
+func (s *SaleRegionService) RemoveOverride(ctx context.Context, eventID, userID uint) error {
+	return s.saleRegionRepo.RemoveOverride(ctx, eventID, userID)
+}
+
+// ParseSaleCountries splits a comma-separated country list such as "GB,IE"
+func ParseSaleCountries(value string) []string {
+	var countries []string
+	for _, part := range strings.Split(value, ",") {
+		if country := strings.ToUpper(strings.TrimSpace(part)); country != "" {
+			countries = append(countries, country)
+		}
+	}
+	return countries
+}
+
+// NormalizeSaleCountries validates ISO 3166-1 alpha-2 country codes and renders them in
+// canonical form, e.g. [" gb", "IE"] -> "GB,IE"
+func NormalizeSaleCountries(values []string) (string, error) {
+	seen := make(map[string]bool, len(values))
+	countries := make([]string, 0, len(values))
+
+	for _, value := range values {
+		country := geoip.NormalizeCountry(value)
+		if country == "" {
+			return "", errors.NewBadRequestError(fmt.Sprintf("Invalid country code %q", value), nil)
+		}
+		if !seen[country] {
+			seen[country] = true
+			countries = append(countries, country)
+		}
+	}
+
+	return strings.Join(countries, ","), nil
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/repository"
+	"api/pkg/errors"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ScannerService registers the door scanners of an event's gates, authenticates them with
+// device tokens and reports entry rates by gate
+type ScannerService struct {
+	scannerRepo   *repository.ScannerRepository
+	ticketService *TicketService
+}
+
+func NewScannerService(scannerRepo *repository.ScannerRepository, ticketService *TicketService) *ScannerService {
+	return &ScannerService{
+		scannerRepo:   scannerRepo,
+		ticketService: ticketService,
+	}
+}
+
+// RegisterDevice registers a scanner at a gate of an event and returns its device token, which
+// is only ever shown here
+func (s *ScannerService) RegisterDevice(ctx context.Context, eventID uint, name, gate string, createdBy uint) (*entities.ScannerDevice, string, error) {
+	token, err := newScannerToken()
+	if err != nil {
+		return nil, "", errors.NewInternalError("Failed to generate device token", err)
+	}
+
+	device := &entities.ScannerDevice{
+		EventID:   eventID,
+		Name:      strings.TrimSpace(name),
+		Gate:      strings.TrimSpace(gate),
+		TokenHash: hashResumeToken(token),
+		CreatedBy: createdBy,
+	}
+	if err := s.scannerRepo.Create(ctx, device); err != nil {
+		return nil, "", err
+	}
+	return device, token, nil
+}
+
+func (s *ScannerService) ListDevices(ctx context.Context, eventID uint) ([]entities.ScannerDevice, error) {
+	return s.scannerRepo.ListByEvent(ctx, eventID)
+}
+
+func (s *ScannerService) RevokeDevice(ctx context.Context, deviceID uint) (*entities.ScannerDevice, error) {
+	return s.scannerRepo.Revoke(ctx, deviceID)
+}
+
+// AuthenticateScanner returns the device a token belongs to. Tokens of revoked devices, and of
+// devices whose event has ended, are refused.
+func (s *ScannerService) AuthenticateScanner(ctx context.Context, token string) (*entities.ScannerDevice, error) {
+	if !strings.HasPrefix(token, constants.ScannerTokenPrefix) {
+		return nil, errors.NewUnauthorizedError("Invalid device token", nil)
+	}
+
+	device, err := s.scannerRepo.GetByTokenHash(ctx, hashResumeToken(token))
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok && appErr.Type == "NOT_FOUND" {
+			return nil, errors.NewUnauthorizedError("Invalid device token", err)
+		}
+		return nil, err
+	}
+	if device.RevokedAt != nil {
+		return nil, errors.NewUnauthorizedError("Device has been revoked", nil)
+	}
+
+	now := time.Now()
+	if now.After(device.Event.EndTime) {
+		return nil, errors.NewUnauthorizedError("Event has ended", nil)
+	}
+	if err := s.scannerRepo.TouchLastSeen(ctx, device.ID, now); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// CheckIn records a ticket a scanner admitted while online
+func (s *ScannerService) CheckIn(ctx context.Context, device *entities.ScannerDevice, token string) (OfflineCheckInResult, error) {
+	results, err := s.SyncCheckIns(ctx, device, []OfflineCheckIn{{Token: token, ScannedAt: time.Now()}})
+	if err != nil {
+		return OfflineCheckInResult{}, err
+	}
+	return results[0], nil
+}
+
+// SyncCheckIns records the tickets a scanner admitted while offline, at the scanner's event
+func (s *ScannerService) SyncCheckIns(ctx context.Context, device *entities.ScannerDevice, checkIns []OfflineCheckIn) ([]OfflineCheckInResult, error) {
+	return s.ticketService.SyncOfflineCheckIns(ctx, device.EventID, &device.ID, checkIns)
+}
+
+// GateStats reports how many attendees entered through each gate of an event, and how fast
+// over the last windowMinutes minutes
+func (s *ScannerService) GateStats(ctx context.Context, eventID uint, windowMinutes int) (*entities.GateStats, error) {
+	if windowMinutes <= 0 {
+		windowMinutes = constants.GateStatsWindowMinutes
+	}
+	if windowMinutes > constants.MaxGateStatsWindowMinutes {
+		windowMinutes = constants.MaxGateStatsWindowMinutes
+	}
+
+	devices, err := s.scannerRepo.DeviceStats(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	since := now.Truncate(time.Minute).Add(-time.Duration(windowMinutes-1) * time.Minute)
+	minutes, err := s.scannerRepo.GateMinutes(ctx, eventID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.GateStats{
+		EventID:       eventID,
+		GeneratedAt:   now,
+		WindowMinutes: windowMinutes,
+		Gates:         gateThroughput(devices, minutes, since, windowMinutes),
+		Devices:       devices,
+	}, nil
+}
+
+// gateThroughput sums device check-ins by gate and lays each gate's recent check-ins out minute
+// by minute from since, so quiet minutes show as zero
+func gateThroughput(devices []entities.ScannerDeviceStats, minutes []repository.ScannerMinute, since time.Time, windowMinutes int) []entities.GateThroughput {
+	gates := make(map[string]*entities.GateThroughput)
+	gate := func(name string) *entities.GateThroughput {
+		if g, ok := gates[name]; ok {
+			return g
+		}
+		g := &entities.GateThroughput{Gate: name, Minutes: make([]entities.GateMinute, windowMinutes)}
+		for i := range g.Minutes {
+			g.Minutes[i].Minute = since.Add(time.Duration(i) * time.Minute)
+		}
+		gates[name] = g
+		return g
+	}
+
+	for _, device := range devices {
+		g := gate(device.Gate)
+		if !device.Revoked {
+			g.Devices++
+		}
+		g.CheckIns += device.CheckIns
+	}
+	for _, minute := range minutes {
+		g := gate(minute.Gate)
+		i := int(minute.Minute.Sub(since) / time.Minute)
+		if i < 0 || i >= windowMinutes {
+			continue
+		}
+		g.Minutes[i].CheckIns += minute.CheckIns
+		g.WindowCheckIns += minute.CheckIns
+	}
+
+	result := make([]entities.GateThroughput, 0, len(gates))
+	for _, g := range gates {
+		g.CheckInsPerMinute = float64(g.WindowCheckIns) / float64(windowMinutes)
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Gate < result[j].Gate })
+	return result
+}
+
+// newScannerToken generates a scanner device token
+func newScannerToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return constants.ScannerTokenPrefix + hex.EncodeToString(buf), nil
+}
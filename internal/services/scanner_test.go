@@ -0,0 +1,45 @@
+package services
+
+import (
+	"api/internal/entities"
+	"api/internal/repository"
+	"testing"
+	"time"
+)
+
+// TestGateThroughput lays check-ins out per gate and minute, counting quiet minutes and gates
+// whose scanners haven't scanned anything yet
+func TestGateThroughput(t *testing.T) {
+	since := time.Date(2026, 10, 16, 19, 0, 0, 0, time.UTC)
+	devices := []entities.ScannerDeviceStats{
+		{DeviceID: 1, Gate: "North", CheckIns: 40},
+		{DeviceID: 2, Gate: "North", CheckIns: 25, Revoked: true},
+		{DeviceID: 3, Gate: "South", CheckIns: 0},
+	}
+	minutes := []repository.ScannerMinute{
+		{Gate: "North", Minute: since, CheckIns: 6},
+		{Gate: "North", Minute: since.Add(2 * time.Minute), CheckIns: 3},
+		{Gate: "North", Minute: since.Add(-time.Minute), CheckIns: 9}, // before the window
+	}
+
+	gates := gateThroughput(devices, minutes, since, 3)
+	if len(gates) != 2 || gates[0].Gate != "North" || gates[1].Gate != "South" {
+		t.Fatalf("gates %+v, want North and South", gates)
+	}
+
+	north := gates[0]
+	if north.Devices != 1 || north.CheckIns != 65 || north.WindowCheckIns != 9 || north.CheckInsPerMinute != 3 {
+		t.Errorf("north %+v", north)
+	}
+	want := []int64{6, 0, 3}
+	for i, minute := range north.Minutes {
+		if !minute.Minute.Equal(since.Add(time.Duration(i)*time.Minute)) || minute.CheckIns != want[i] {
+			t.Errorf("minute %d is %+v, want %d check-ins", i, minute, want[i])
+		}
+	}
+
+	south := gates[1]
+	if south.Devices != 1 || south.CheckIns != 0 || len(south.Minutes) != 3 || south.CheckInsPerMinute != 0 {
+		t.Errorf("south %+v", south)
+	}
+}
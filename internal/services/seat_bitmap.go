@@ -0,0 +1,43 @@
+package services
+
+import (
+	"api/internal/domain"
+	"api/internal/entities"
+	"api/internal/repository"
+	"context"
+)
+
+// SeatBitmapService serves events' seat availability as bitmaps patched by the booking
+// workflow: a seat's bit is cleared when it is taken into checkout or sold, and set again
+// when it is released
+type SeatBitmapService struct {
+	bitmaps *repository.SeatBitmapRepository
+}
+
+func NewSeatBitmapService(bitmaps *repository.SeatBitmapRepository) *SeatBitmapService {
+	return &SeatBitmapService{bitmaps: bitmaps}
+}
+
+// Subscribe keeps the bitmaps in step with seat locks, sales and releases
+func (s *SeatBitmapService) Subscribe(events *domain.Dispatcher) {
+	domain.Subscribe(events, "seat_bitmaps", func(ctx context.Context, event domain.SeatLocked) error {
+		s.bitmaps.SetSeat(ctx, event.EventID, event.SeatID, false)
+		return nil
+	})
+	domain.Subscribe(events, "seat_bitmaps", func(ctx context.Context, event domain.BookingConfirmed) error {
+		s.bitmaps.SetSeat(ctx, event.EventID, event.SeatID, false)
+		return nil
+	})
+	domain.Subscribe(events, "seat_bitmaps", func(ctx context.Context, event domain.SeatReleased) error {
+		// Oversell places aren't on the seat map
+		if !event.Oversell {
+			s.bitmaps.SetSeat(ctx, event.EventID, event.SeatID, true)
+		}
+		return nil
+	})
+}
+
+// GetBitmap returns an event's seat availability bitmap
+func (s *SeatBitmapService) GetBitmap(ctx context.Context, eventID uint) (*entities.SeatBitmap, error) {
+	return s.bitmaps.Get(ctx, eventID)
+}
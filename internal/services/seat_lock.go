@@ -2,6 +2,7 @@ package services
 
 import (
 	"api/constants"
+	redisconn "api/internal/redis"
 	"context"
 	"fmt"
 	"time"
@@ -10,30 +11,38 @@ import (
 )
 
 type SeatLockService struct {
-	redis *redis.Client
+	redis redis.UniversalClient
 }
 
 // Ensure SeatLockService implements SeatLockServiceInterface
 var _ SeatLockServiceInterface = (*SeatLockService)(nil)
 
-func NewSeatLockService(redisClient *redis.Client) *SeatLockService {
+func NewSeatLockService(redisClient redis.UniversalClient) *SeatLockService {
 	return &SeatLockService{
 		redis: redisClient,
 	}
 }
 
-// LockSeat creates a lock for a specific seat with TTL
-func (s *SeatLockService) LockSeat(ctx context.Context, seatID uint, userID uint, intentID string) error {
-	key := fmt.Sprintf("%s%d", constants.SeatLockPrefix, seatID)
+// LockSeat creates a lock for a specific seat that expires at expiresAt, the intent's lock expiry
+func (s *SeatLockService) LockSeat(ctx context.Context, eventID, seatID uint, userID uint, intentID string, expiresAt time.Time) error {
+	key := redisconn.SeatLockKey(eventID, seatID)
 	value := fmt.Sprintf("%d:%s", userID, intentID)
 
-	// Try to set the lock with NX (only if not exists) and TTL
-	result := s.redis.SetNX(ctx, key, value, time.Duration(constants.SeatLockDuration)*time.Minute)
-	if result.Err() != nil {
-		return fmt.Errorf("failed to create seat lock: %w", result.Err())
+	// Set the lock only if the seat is free, expiring it at the exact millisecond
+	script := `
+		if redis.call('SET', KEYS[1], ARGV[1], 'NX') then
+			redis.call('PEXPIREAT', KEYS[1], ARGV[2])
+			return 1
+		end
+		return 0
+	`
+
+	locked, err := s.redis.Eval(ctx, script, []string{key}, value, expiresAt.UnixMilli()).Int()
+	if err != nil {
+		return fmt.Errorf("failed to create seat lock: %w", err)
 	}
 
-	if !result.Val() {
+	if locked == 0 {
 		return fmt.Errorf("seat is already locked")
 	}
 
@@ -41,8 +50,8 @@ func (s *SeatLockService) LockSeat(ctx context.Context, seatID uint, userID uint
 }
 
 // UnlockSeat removes the lock for a specific seat
-func (s *SeatLockService) UnlockSeat(ctx context.Context, seatID uint, userID uint, intentID string) error {
-	key := fmt.Sprintf("%s%d", constants.SeatLockPrefix, seatID)
+func (s *SeatLockService) UnlockSeat(ctx context.Context, eventID, seatID uint, userID uint, intentID string) error {
+	key := redisconn.SeatLockKey(eventID, seatID)
 	expectedValue := fmt.Sprintf("%d:%s", userID, intentID)
 
 	// Lua script to atomically check and delete
@@ -66,8 +75,8 @@ func (s *SeatLockService) UnlockSeat(ctx context.Context, seatID uint, userID ui
 }
 
 // IsLocked checks if a seat is currently locked
-func (s *SeatLockService) IsLocked(ctx context.Context, seatID uint) (bool, string, error) {
-	key := fmt.Sprintf("%s%d", constants.SeatLockPrefix, seatID)
+func (s *SeatLockService) IsLocked(ctx context.Context, eventID, seatID uint) (bool, string, error) {
+	key := redisconn.SeatLockKey(eventID, seatID)
 
 	result := s.redis.Get(ctx, key)
 	if result.Err() == redis.Nil {
@@ -80,26 +89,25 @@ func (s *SeatLockService) IsLocked(ctx context.Context, seatID uint) (bool, stri
 	return true, result.Val(), nil
 }
 
-// ExtendLock extends the TTL of an existing lock
-func (s *SeatLockService) ExtendLock(ctx context.Context, seatID uint, userID uint, intentID string) error {
-	key := fmt.Sprintf("%s%d", constants.SeatLockPrefix, seatID)
+// ExtendLock moves the expiry of an existing lock to expiresAt, which the caller stores as
+// the intent's new lock expiry
+func (s *SeatLockService) ExtendLock(ctx context.Context, eventID, seatID uint, userID uint, intentID string, expiresAt time.Time) error {
+	key := redisconn.SeatLockKey(eventID, seatID)
 	expectedValue := fmt.Sprintf("%d:%s", userID, intentID)
 
-	// Lua script to atomically check and extend TTL
+	// Lua script to atomically check and move the expiry
 	script := `
 		local key = KEYS[1]
 		local expected = ARGV[1]
-		local ttl = ARGV[2]
 		local current = redis.call('GET', key)
 		if current == expected then
-			return redis.call('EXPIRE', key, ttl)
+			return redis.call('PEXPIREAT', key, ARGV[2])
 		else
 			return 0
 		end
 	`
 
-	ttlSeconds := constants.SeatLockDuration * 60
-	result := s.redis.Eval(ctx, script, []string{key}, expectedValue, ttlSeconds)
+	result := s.redis.Eval(ctx, script, []string{key}, expectedValue, expiresAt.UnixMilli())
 	if result.Err() != nil {
 		return fmt.Errorf("failed to extend seat lock: %w", result.Err())
 	}
@@ -112,8 +120,8 @@ func (s *SeatLockService) ExtendLock(ctx context.Context, seatID uint, userID ui
 }
 
 // GetLockTTL returns the remaining TTL for a seat lock
-func (s *SeatLockService) GetLockTTL(ctx context.Context, seatID uint) (time.Duration, error) {
-	key := fmt.Sprintf("%s%d", constants.SeatLockPrefix, seatID)
+func (s *SeatLockService) GetLockTTL(ctx context.Context, eventID, seatID uint) (time.Duration, error) {
+	key := redisconn.SeatLockKey(eventID, seatID)
 
 	result := s.redis.TTL(ctx, key)
 	if result.Err() != nil {
@@ -127,21 +135,20 @@ func (s *SeatLockService) GetLockTTL(ctx context.Context, seatID uint) (time.Dur
 func (s *SeatLockService) CleanupExpiredLocks(ctx context.Context) error {
 	pattern := constants.SeatLockPrefix + "*"
 
-	keys, err := s.redis.Keys(ctx, pattern).Result()
-	if err != nil {
-		return fmt.Errorf("failed to get lock keys: %w", err)
-	}
-
-	for _, key := range keys {
+	err := redisconn.ScanKeys(ctx, s.redis, pattern, func(key string) error {
 		ttl, err := s.redis.TTL(ctx, key).Result()
 		if err != nil {
-			continue
+			return nil
 		}
 
 		// If TTL is -1 (no expiry) or -2 (key doesn't exist), clean it up
 		if ttl < 0 {
 			s.redis.Del(ctx, key)
 		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get lock keys: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,290 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/repository"
+	"api/pkg/errors"
+	logger "api/pkg/logging"
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SettingDefinition describes a runtime setting platform admins can tune
+type SettingDefinition struct {
+	Key         string
+	Kind        string // duration or number
+	Description string
+	Default     string  // the configured value, applying until the setting is overridden
+	Min         float64 // bounds of the value, in seconds for durations
+	Max         float64
+}
+
+// SettingDefaults are the configured values of the runtime settings
+type SettingDefaults struct {
+	LockDuration time.Duration
+	WaitlistHold time.Duration
+	FeePercent   float64
+	FeePerTicket float64
+}
+
+// RuntimeSettings returns the tunable settings with the given defaults
+func RuntimeSettings(defaults SettingDefaults) []SettingDefinition {
+	return []SettingDefinition{
+		{
+			Key:         constants.SettingLockDuration,
+			Kind:        constants.SettingKindDuration,
+			Description: "How long a booking intent holds its seat",
+			Default:     defaults.LockDuration.String(),
+			Min:         time.Minute.Seconds(),
+			Max:         time.Hour.Seconds(),
+		},
+		{
+			Key:         constants.SettingWaitlistHold,
+			Kind:        constants.SettingKindDuration,
+			Description: "How long a waitlisted user offered a seat has to book it",
+			Default:     defaults.WaitlistHold.String(),
+			Min:         time.Minute.Seconds(),
+			Max:         (24 * time.Hour).Seconds(),
+		},
+		{
+			Key:         constants.SettingFeePercent,
+			Kind:        constants.SettingKindNumber,
+			Description: "Platform fee, in percent of an event's sales after refunds",
+			Default:     formatNumber(defaults.FeePercent),
+			Min:         0,
+			Max:         100,
+		},
+		{
+			Key:         constants.SettingFeePerTicket,
+			Kind:        constants.SettingKindNumber,
+			Description: "Platform fee per ticket sold and not refunded",
+			Default:     formatNumber(defaults.FeePerTicket),
+			Min:         0,
+			Max:         1000,
+		},
+	}
+}
+
+// Setting is a runtime setting with its current value
+type Setting struct {
+	SettingDefinition
+	Value     string
+	UpdatedBy *uint // set while the setting is overridden
+	UpdatedAt *time.Time
+}
+
+// SettingsService keeps the runtime settings platform admins override without a deploy. Values
+// are cached for the TTL, so other instances pick up a change within it. A nil SettingsService
+// leaves every setting at the consumer's default.
+type SettingsService struct {
+	repo        *repository.SettingsRepository
+	definitions []SettingDefinition
+	ttl         time.Duration
+	now         func() time.Time
+
+	mu       sync.Mutex
+	values   map[string]string // overrides by key
+	loadedAt time.Time
+}
+
+// Ensure SettingsService implements SettingsServiceInterface
+var _ SettingsServiceInterface = (*SettingsService)(nil)
+
+func NewSettingsService(repo *repository.SettingsRepository, ttl time.Duration, definitions []SettingDefinition) *SettingsService {
+	return &SettingsService{
+		repo:        repo,
+		definitions: definitions,
+		ttl:         ttl,
+		now:         time.Now,
+	}
+}
+
+// Duration returns a duration setting, or fallback while it isn't overridden
+func (s *SettingsService) Duration(ctx context.Context, key string, fallback time.Duration) time.Duration {
+	value, ok := s.override(ctx, key)
+	if !ok {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fallback
+	}
+	return d
+}
+
+// Float returns a number setting, or fallback while it isn't overridden
+func (s *SettingsService) Float(ctx context.Context, key string, fallback float64) float64 {
+	value, ok := s.override(ctx, key)
+	if !ok {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fallback
+	}
+	return f
+}
+
+// ListSettings returns every runtime setting with its current value
+func (s *SettingsService) ListSettings(ctx context.Context) ([]Setting, error) {
+	overrides, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]entities.RuntimeSetting, len(overrides))
+	for _, o := range overrides {
+		byKey[o.Key] = o
+	}
+
+	settings := make([]Setting, len(s.definitions))
+	for i, def := range s.definitions {
+		settings[i] = newSetting(def, byKey[def.Key])
+	}
+	return settings, nil
+}
+
+// UpdateSetting overrides a setting after checking the value against its kind and bounds
+func (s *SettingsService) UpdateSetting(ctx context.Context, key, value string, adminID uint) (*Setting, error) {
+	def, err := s.definition(key)
+	if err != nil {
+		return nil, err
+	}
+	normalized, err := def.normalize(value)
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := s.repo.Set(ctx, key, normalized, adminID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	logger.Infof("Runtime setting %s set to %s by admin %d", key, normalized, adminID)
+
+	setting := newSetting(*def, *saved)
+	return &setting, nil
+}
+
+// ResetSetting removes a setting's override so its default applies again
+func (s *SettingsService) ResetSetting(ctx context.Context, key string, adminID uint) (*Setting, error) {
+	def, err := s.definition(key)
+	if err != nil {
+		return nil, err
+	}
+
+	reset, err := s.repo.Reset(ctx, key, adminID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	if reset {
+		logger.Infof("Runtime setting %s reset to its default by admin %d", key, adminID)
+	}
+
+	setting := newSetting(*def, entities.RuntimeSetting{})
+	return &setting, nil
+}
+
+// ListSettingChanges returns the audited setting changes newest first, optionally of one setting
+func (s *SettingsService) ListSettingChanges(ctx context.Context, key string, limit, offset int) ([]entities.SettingChange, int64, error) {
+	return s.repo.ListChanges(ctx, key, limit, offset)
+}
+
+// override returns a setting's override, reloading the overrides once the cached ones are
+// older than the TTL. Failed reloads keep the last known overrides until the next TTL.
+func (s *SettingsService) override(ctx context.Context, key string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if now.Sub(s.loadedAt) >= s.ttl {
+		overrides, err := s.repo.List(ctx)
+		if err != nil {
+			logger.Warnf("Failed to reload runtime settings, keeping the last known values: %v", err)
+		} else {
+			s.values = make(map[string]string, len(overrides))
+			for _, o := range overrides {
+				s.values[o.Key] = o.Value
+			}
+		}
+		s.loadedAt = now
+	}
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// invalidate makes the next read reload the overrides
+func (s *SettingsService) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func (s *SettingsService) definition(key string) (*SettingDefinition, error) {
+	for i := range s.definitions {
+		if s.definitions[i].Key == key {
+			return &s.definitions[i], nil
+		}
+	}
+	return nil, errors.NewNotFoundError("Setting not found", nil)
+}
+
+// normalize parses a value of the setting's kind, checks its bounds and returns it in the form
+// it is stored in
+func (d SettingDefinition) normalize(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch d.Kind {
+	case constants.SettingKindDuration:
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return "", errors.NewBadRequestError(fmt.Sprintf("%s must be a duration such as 10m", d.Key), err)
+		}
+		if err := d.checkBounds(duration.Seconds()); err != nil {
+			return "", err
+		}
+		return duration.String(), nil
+	default:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return "", errors.NewBadRequestError(fmt.Sprintf("%s must be a number", d.Key), err)
+		}
+		if err := d.checkBounds(number); err != nil {
+			return "", err
+		}
+		return formatNumber(number), nil
+	}
+}
+
+func (d SettingDefinition) checkBounds(value float64) error {
+	if value >= d.Min && value <= d.Max {
+		return nil
+	}
+	if d.Kind == constants.SettingKindDuration {
+		return errors.NewBadRequestError(fmt.Sprintf("%s must be between %s and %s", d.Key,
+			time.Duration(d.Min*float64(time.Second)), time.Duration(d.Max*float64(time.Second))), nil)
+	}
+	return errors.NewBadRequestError(fmt.Sprintf("%s must be between %s and %s", d.Key, formatNumber(d.Min), formatNumber(d.Max)), nil)
+}
+
+func newSetting(def SettingDefinition, override entities.RuntimeSetting) Setting {
+	setting := Setting{SettingDefinition: def, Value: def.Default}
+	if override.Key != "" {
+		setting.Value = override.Value
+		setting.UpdatedBy = &override.UpdatedBy
+		setting.UpdatedAt = &override.UpdatedAt
+	}
+	return setting
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
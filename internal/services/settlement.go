@@ -0,0 +1,100 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/repository"
+	"api/pkg/errors"
+	"api/pkg/money"
+	"context"
+	"time"
+)
+
+type SettlementService struct {
+	settlementRepo *repository.SettlementRepository
+	feePercent     float64
+	feePerTicket   money.Money
+	settings       *SettingsService
+}
+
+// Ensure SettlementService implements SettlementServiceInterface
+var _ SettlementServiceInterface = (*SettlementService)(nil)
+
+func NewSettlementService(settlementRepo *repository.SettlementRepository, feePercent float64, feePerTicket money.Money) *SettlementService {
+	return &SettlementService{
+		settlementRepo: settlementRepo,
+		feePercent:     feePercent,
+		feePerTicket:   feePerTicket,
+	}
+}
+
+// WithSettings reads the platform fees from the runtime settings, falling back to the configured
+// fees while they aren't overridden. Frozen settlements keep the fees they were frozen with.
+func (s *SettlementService) WithSettings(settings *SettingsService) *SettlementService {
+	s.settings = settings
+	return s
+}
+
+// GetSettlement returns an event's frozen settlement, or else generates it from the event's
+// bookings as they stand
+func (s *SettlementService) GetSettlement(ctx context.Context, eventID uint) (*entities.EventSettlement, error) {
+	event, err := s.settlementRepo.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	frozen, err := s.settlementRepo.GetFrozenSettlement(ctx, event.ID)
+	if err == nil {
+		return frozen, nil
+	}
+	if appErr, ok := err.(*errors.AppError); !ok || appErr.Type != "NOT_FOUND" {
+		return nil, err
+	}
+
+	return s.generate(ctx, event)
+}
+
+// FreezeSettlement fixes an event's settlement once the event has completed, so refunds,
+// disputes or fee changes afterwards no longer move it
+func (s *SettlementService) FreezeSettlement(ctx context.Context, eventID, adminID uint) (*entities.EventSettlement, error) {
+	event, err := s.settlementRepo.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status != constants.EventStatusCompleted {
+		return nil, errors.NewBadRequestError("Settlements can only be frozen once the event has completed", nil)
+	}
+
+	settlement, err := s.generate(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	settlement.FrozenAt = &now
+	settlement.FrozenBy = &adminID
+	if err := s.settlementRepo.SaveFrozenSettlement(ctx, settlement); err != nil {
+		return nil, err
+	}
+	return settlement, nil
+}
+
+// generate totals the event's bookings and applies the platform fees: a percent of the sales
+// left after refunds, plus a fixed fee per ticket still sold
+func (s *SettlementService) generate(ctx context.Context, event *entities.Event) (*entities.EventSettlement, error) {
+	settlement, err := s.settlementRepo.ComputeSettlement(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	feePercent := s.settings.Float(ctx, constants.SettingFeePercent, s.feePercent)
+	// The per-ticket fee setting is in major units like the other amounts admins enter
+	feePerTicket := money.FromMajor(s.settings.Float(ctx, constants.SettingFeePerTicket, s.feePerTicket.Major()))
+
+	netSales := settlement.GrossSales - settlement.Refunds
+	settlement.FeePercent = feePercent
+	settlement.FeePerTicket = feePerTicket
+	settlement.PlatformFees = netSales.Percent(feePercent) + feePerTicket.Times(settlement.TicketsSold-settlement.RefundedTickets)
+	settlement.NetPayable = netSales - settlement.Chargebacks - settlement.PlatformFees
+	settlement.CreatedAt = time.Now()
+	return settlement, nil
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/notifications"
+	"api/internal/repository"
+	"api/pkg/errors"
+	logger "api/pkg/logging"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SupportService handles support cases users open on their bookings and the organizer staff's
+// responses, emailing users when their case is answered or resolved
+type SupportService struct {
+	supportRepo *repository.SupportRepository
+	notifier    notifications.Notifier
+}
+
+func NewSupportService(supportRepo *repository.SupportRepository, notifier notifications.Notifier) *SupportService {
+	return &SupportService{
+		supportRepo: supportRepo,
+		notifier:    notifier,
+	}
+}
+
+// OpenCase opens a support case on one of the user's bookings
+func (s *SupportService) OpenCase(ctx context.Context, userID, bookingID uint, category, message string) (*entities.SupportCase, error) {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return nil, errors.NewBadRequestError("Message is required", nil)
+	}
+	supportCase, err := s.supportRepo.Open(ctx, userID, bookingID, category, message)
+	if err != nil {
+		return nil, err
+	}
+	logger.Infof("Support case %d opened on booking %d (%s)", supportCase.ID, bookingID, category)
+	return supportCase, nil
+}
+
+// ListUserCases returns the user's support cases
+func (s *SupportService) ListUserCases(ctx context.Context, userID uint, status string, limit, offset int) ([]entities.SupportCase, int64, error) {
+	return s.supportRepo.ListByUser(ctx, userID, status, limit, offset)
+}
+
+// GetUserCase returns one of the user's support cases with its messages
+func (s *SupportService) GetUserCase(ctx context.Context, userID, caseID uint) (*entities.SupportCase, error) {
+	return s.supportRepo.GetForUser(ctx, userID, caseID)
+}
+
+// ListCases returns support cases to staff
+func (s *SupportService) ListCases(ctx context.Context, status, category string, limit, offset int) ([]entities.SupportCase, int64, error) {
+	return s.supportRepo.List(ctx, status, category, limit, offset)
+}
+
+// GetCase returns a support case with its messages to staff
+func (s *SupportService) GetCase(ctx context.Context, caseID uint) (*entities.SupportCase, error) {
+	return s.supportRepo.Get(ctx, caseID)
+}
+
+// Respond adds a staff response to a support case, resolving it if resolve is set, and emails
+// the user. Only resolving may leave the message out.
+func (s *SupportService) Respond(ctx context.Context, caseID, staffID uint, message string, resolve bool) (*entities.SupportCase, error) {
+	message = strings.TrimSpace(message)
+	if message == "" && !resolve {
+		return nil, errors.NewBadRequestError("Message is required", nil)
+	}
+
+	supportCase, err := s.supportRepo.Respond(ctx, caseID, staffID, message, resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.notifier.Send(ctx, supportCaseNotification(supportCase, message)); err != nil {
+		logger.Warnf("Failed to notify user %d of support case %d: %v", supportCase.UserID, supportCase.ID, err)
+	}
+	return supportCase, nil
+}
+
+// supportCaseNotification tells a user their support case was answered or resolved, quoting
+// the staff's message
+func supportCaseNotification(supportCase *entities.SupportCase, message string) notifications.Notification {
+	subject := fmt.Sprintf("Your support case %d has a new response", supportCase.ID)
+	body := fmt.Sprintf("Our team responded to your %s case about booking %d:\n\n%s", supportCase.Category, supportCase.BookingID, message)
+	if supportCase.Status == constants.SupportCaseStatusResolved {
+		subject = fmt.Sprintf("Your support case %d was resolved", supportCase.ID)
+		body = fmt.Sprintf("Your %s case about booking %d was resolved.", supportCase.Category, supportCase.BookingID)
+		if message != "" {
+			body += "\n\n" + message
+		}
+	}
+
+	return notifications.Notification{
+		Type:      constants.NotificationTypeSupportCaseUpdated,
+		UserID:    supportCase.UserID,
+		Recipient: supportCase.User.Email,
+		EventID:   supportCase.EventID,
+		BookingID: supportCase.BookingID,
+		Subject:   subject,
+		Message:   body,
+	}
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"strings"
+	"testing"
+)
+
+// TestSupportCaseNotification emails the staff's response to the user who opened the case, and
+// tells them when it is resolved, with or without a closing message
+func TestSupportCaseNotification(t *testing.T) {
+	supportCase := &entities.SupportCase{
+		ID:        7,
+		BookingID: 42,
+		UserID:    3,
+		User:      entities.User{Email: "fan@example.com"},
+		EventID:   9,
+		Category:  constants.SupportCategoryRefund,
+		Status:    constants.SupportCaseStatusAnswered,
+	}
+
+	answered := supportCaseNotification(supportCase, "We are looking into it.")
+	if answered.Recipient != "fan@example.com" || answered.UserID != 3 || answered.BookingID != 42 ||
+		answered.Type != constants.NotificationTypeSupportCaseUpdated {
+		t.Errorf("answered notification %+v", answered)
+	}
+	if !strings.Contains(answered.Subject, "new response") || !strings.HasSuffix(answered.Message, "We are looking into it.") {
+		t.Errorf("answered notification says %q: %q", answered.Subject, answered.Message)
+	}
+
+	supportCase.Status = constants.SupportCaseStatusResolved
+	resolved := supportCaseNotification(supportCase, "")
+	if !strings.Contains(resolved.Subject, "resolved") || resolved.Message != "Your refund case about booking 42 was resolved." {
+		t.Errorf("resolved notification says %q: %q", resolved.Subject, resolved.Message)
+	}
+	resolved = supportCaseNotification(supportCase, "Refunded in full.")
+	if !strings.HasSuffix(resolved.Message, "\n\nRefunded in full.") {
+		t.Errorf("resolved notification says %q", resolved.Message)
+	}
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"api/internal/entities"
+	"api/internal/repository"
+	"context"
+)
+
+type TaskService struct {
+	taskRepo *repository.TaskRepository
+}
+
+// Ensure TaskService implements TaskServiceInterface
+var _ TaskServiceInterface = (*TaskService)(nil)
+
+func NewTaskService(taskRepo *repository.TaskRepository) *TaskService {
+	return &TaskService{taskRepo: taskRepo}
+}
+
+func (s *TaskService) GetTask(ctx context.Context, taskID uint) (*entities.Task, error) {
+	return s.taskRepo.GetByID(ctx, taskID)
+}
+
+func (s *TaskService) ListTasks(ctx context.Context, kind, status string, limit, offset int) ([]entities.Task, int64, error) {
+	return s.taskRepo.List(ctx, kind, status, limit, offset)
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"api/internal/entities"
+	"api/internal/repository"
+	"api/pkg/errors"
+	logger "api/pkg/logging"
+	"context"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tenantRateLimitRefresh is how often per-tenant rate limits are reloaded from the database
+const tenantRateLimitRefresh = time.Minute
+
+// organizerPageTTL is how long a public organizer page is served from memory
+const organizerPageTTL = time.Minute
+
+// organizerPageEvents caps the upcoming events listed on an organizer page
+const organizerPageEvents = 20
+
+var tenantSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type TenantService struct {
+	tenantRepo *repository.TenantRepository
+
+	mu             sync.Mutex
+	rateLimits     map[uint]int
+	rateLimitsRead time.Time
+
+	pagesMu sync.Mutex
+	pages   map[string]cachedOrganizerPage // by the slug or ID the page was requested with
+}
+
+type cachedOrganizerPage struct {
+	page     *entities.OrganizerPage
+	loadedAt time.Time
+}
+
+// Ensure TenantService implements TenantServiceInterface
+var _ TenantServiceInterface = (*TenantService)(nil)
+
+func NewTenantService(tenantRepo *repository.TenantRepository) *TenantService {
+	return &TenantService{tenantRepo: tenantRepo, pages: make(map[string]cachedOrganizerPage)}
+}
+
+func (s *TenantService) CreateTenant(ctx context.Context, t *entities.Tenant) error {
+	t.Slug = strings.ToLower(strings.TrimSpace(t.Slug))
+	if !tenantSlugPattern.MatchString(t.Slug) {
+		return errors.NewBadRequestError("Slug may only contain lowercase letters, digits and dashes", nil)
+	}
+	if err := s.tenantRepo.Create(ctx, t); err != nil {
+		return err
+	}
+	s.invalidateRateLimits()
+	return nil
+}
+
+func (s *TenantService) GetTenant(ctx context.Context, tenantID uint) (*entities.Tenant, error) {
+	return s.tenantRepo.GetByID(ctx, tenantID)
+}
+
+func (s *TenantService) ListTenants(ctx context.Context) ([]entities.Tenant, error) {
+	return s.tenantRepo.List(ctx)
+}
+
+func (s *TenantService) UpdateTenant(ctx context.Context, tenantID uint, updates map[string]interface{}) (*entities.Tenant, error) {
+	t, err := s.tenantRepo.Update(ctx, tenantID, updates)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidateRateLimits()
+	s.invalidateOrganizerPages()
+	return t, nil
+}
+
+// GetOrganizerPage returns an organizer's profile, upcoming events and ratings by tenant ID or
+// storefront slug. Pages are cached for a minute; organizers that don't exist aren't cached.
+func (s *TenantService) GetOrganizerPage(ctx context.Context, idOrSlug string) (*entities.OrganizerPage, error) {
+	key := strings.ToLower(idOrSlug)
+	s.pagesMu.Lock()
+	cached, ok := s.pages[key]
+	s.pagesMu.Unlock()
+	if ok && time.Since(cached.loadedAt) < organizerPageTTL {
+		return cached.page, nil
+	}
+
+	var t *entities.Tenant
+	var err error
+	if tenantID, parseErr := strconv.ParseUint(key, 10, 32); parseErr == nil {
+		t, err = s.tenantRepo.GetByID(ctx, uint(tenantID))
+		if appErr, ok := err.(*errors.AppError); ok && appErr.Type == "NOT_FOUND" {
+			err = errors.NewNotFoundError("Organizer not found", errors.ErrRecordNotFound)
+		}
+	} else {
+		t, err = s.tenantRepo.GetBySlug(ctx, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.tenantRepo.GetUpcomingEvents(ctx, t.ID, time.Now(), organizerPageEvents)
+	if err != nil {
+		return nil, err
+	}
+	ratings, err := s.tenantRepo.GetRatings(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	page := &entities.OrganizerPage{Organizer: t, UpcomingEvents: events, Ratings: ratings}
+
+	s.pagesMu.Lock()
+	s.pages[key] = cachedOrganizerPage{page: page, loadedAt: time.Now()}
+	s.pagesMu.Unlock()
+	return page, nil
+}
+
+// GetBranding returns a tenant's profile; tenant admins may only read their own
+func (s *TenantService) GetBranding(ctx context.Context, tenantID uint) (*entities.Tenant, error) {
+	return s.tenantRepo.GetOwn(ctx, tenantID)
+}
+
+// UpdateBranding changes a tenant's display name, logo, support email or colors. Tenant
+// admins may only change their own tenant.
+func (s *TenantService) UpdateBranding(ctx context.Context, tenantID uint, updates map[string]interface{}) (*entities.Tenant, error) {
+	if logoURL, ok := updates["logo_url"].(string); ok && logoURL != "" {
+		parsed, err := url.Parse(logoURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, errors.NewBadRequestError("Logo URL must be an http or https URL", err)
+		}
+	}
+	if email, ok := updates["support_email"].(string); ok && email != "" {
+		if _, err := mail.ParseAddress(email); err != nil {
+			return nil, errors.NewBadRequestError("Support email is not a valid email address", err)
+		}
+	}
+	for _, column := range []string{"primary_color", "secondary_color", "accent_color"} {
+		color, ok := updates[column].(string)
+		if !ok || color == "" {
+			continue
+		}
+		if !hexColorPattern.MatchString(color) {
+			return nil, errors.NewBadRequestError("Colors must be hex values like #1a2b3c", nil)
+		}
+		updates[column] = strings.ToLower(color)
+	}
+	t, err := s.tenantRepo.Update(ctx, tenantID, updates)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidateOrganizerPages()
+	return t, nil
+}
+
+// AssignAdmin scopes an admin to a tenant, or makes them a platform admin when tenantID is nil.
+// The change applies to tokens issued from the admin's next login.
+func (s *TenantService) AssignAdmin(ctx context.Context, userID uint, tenantID *uint) (*entities.User, error) {
+	return s.tenantRepo.AssignAdmin(ctx, userID, tenantID)
+}
+
+// RateLimit returns the tenant's admin requests per minute, or 0 to use the default. Limits are
+// cached and refreshed every minute; if the refresh fails the previous limits are kept.
+func (s *TenantService) RateLimit(ctx context.Context, tenantID uint) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rateLimits == nil || time.Since(s.rateLimitsRead) >= tenantRateLimitRefresh {
+		s.rateLimitsRead = time.Now()
+		limits, err := s.tenantRepo.RateLimits(ctx)
+		if err != nil {
+			logger.Warnf("Failed to refresh tenant rate limits: %v", err)
+		} else {
+			s.rateLimits = limits
+		}
+	}
+	return s.rateLimits[tenantID]
+}
+
+func (s *TenantService) invalidateRateLimits() {
+	s.mu.Lock()
+	s.rateLimits = nil
+	s.mu.Unlock()
+}
+
+// invalidateOrganizerPages drops cached pages so profile changes show up right away
+func (s *TenantService) invalidateOrganizerPages() {
+	s.pagesMu.Lock()
+	s.pages = make(map[string]cachedOrganizerPage)
+	s.pagesMu.Unlock()
+}
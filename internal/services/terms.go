@@ -0,0 +1,45 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/pkg/errors"
+	"strings"
+	"time"
+)
+
+// NormalizeTerms trims an event's terms and conditions and checks they come with a version.
+// Clearing the terms also clears the version, so bookings no longer need acceptance.
+func NormalizeTerms(terms, version string) (string, string, error) {
+	terms = strings.TrimSpace(terms)
+	version = strings.TrimSpace(version)
+
+	if terms == "" {
+		return "", "", nil
+	}
+	if version == "" {
+		return "", "", errors.NewBadRequestError("terms_version is required when terms are set", nil)
+	}
+	if len(version) > 50 {
+		return "", "", errors.NewBadRequestError("terms_version must be at most 50 characters", nil)
+	}
+	return terms, version, nil
+}
+
+// acceptTerms checks that the user accepted the event's current terms and records the
+// acceptance, made at now, on the intent. Events without terms need no acceptance.
+func acceptTerms(event *entities.Event, intent *entities.BookingIntent, options entities.BookingIntentOptions, now time.Time) error {
+	if event.TermsVersion == "" {
+		return nil
+	}
+	if !options.AcceptTerms {
+		return errors.NewBadRequestError(constants.ErrTermsNotAccepted, nil)
+	}
+	if strings.TrimSpace(options.TermsVersion) != event.TermsVersion {
+		return errors.NewBadRequestError(constants.ErrTermsVersionChanged, nil)
+	}
+
+	intent.TermsVersion = event.TermsVersion
+	intent.TermsAcceptedAt = &now
+	return nil
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"api/internal/domain"
+	"api/internal/notifications"
+	"api/internal/services"
+	"api/pkg/money"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingAdminChannel keeps the admin events it is asked to deliver
+type recordingAdminChannel struct {
+	events []notifications.AdminEvent
+}
+
+func (c *recordingAdminChannel) Notify(ctx context.Context, event notifications.AdminEvent) error {
+	c.events = append(c.events, event)
+	return nil
+}
+
+func TestAdminNotificationsLargeRefunds(t *testing.T) {
+	ctx := context.Background()
+	channel := &recordingAdminChannel{}
+	events := domain.NewDispatcher()
+	services.NewAdminNotifications(channel, nil, money.FromMajor(500)).Subscribe(events)
+
+	events.Publish(ctx,
+		domain.BookingCancelled{BookingID: 11, UserID: 1, EventID: 3, RefundAmount: money.FromMajor(120)},
+		domain.BookingCancelled{BookingID: 12, UserID: 1, EventID: 3},
+		domain.BookingCancelled{BookingID: 13, UserID: 2, EventID: 3, RefundAmount: money.FromMajor(750)})
+
+	require.Len(t, channel.events, 1)
+	assert.Equal(t, notifications.AdminEventLargeRefund, channel.events[0].Kind)
+	assert.Equal(t, uint(3), channel.events[0].EventID)
+	assert.Contains(t, channel.events[0].Message, "Booking 13")
+	assert.False(t, channel.events[0].At.IsZero())
+}
+
+func TestAdminNotificationsLockDivergences(t *testing.T) {
+	ctx := context.Background()
+	channel := &recordingAdminChannel{}
+	admin := services.NewAdminNotifications(channel, nil, 0)
+
+	admin.LockDivergences(ctx, nil, true)
+	admin.LockDivergences(ctx, map[string]int{services.DivergenceRedisLockOrphaned: 2, services.DivergenceRedisLockMissing: 1}, true)
+
+	require.Len(t, channel.events, 1)
+	assert.Equal(t, notifications.AdminEventReconciliationDiscrepancy, channel.events[0].Kind)
+	assert.Contains(t, channel.events[0].Message, "1 redis_lock_missing, 2 redis_lock_orphaned")
+
+	// A nil notifier drops everything
+	var none *services.AdminNotifications
+	none.LockDivergences(ctx, map[string]int{services.DivergenceRedisLockMissing: 1}, false)
+	none.QueueSaturated(ctx, 3, 100)
+}
+
+func TestQueueSaturationNotifiesAdminsOnce(t *testing.T) {
+	ctx := context.Background()
+	channel := &recordingAdminChannel{}
+	f := newQueueFixture(t)
+	f.service.WithSaturationAlerts(services.NewAdminNotifications(channel, nil, 0), 5)
+
+	f.eventRepo.On("GetEventByID", ctx, uint(3)).Return(highDemandEvent(3), nil)
+	f.queueRepo.On("GetEntry", ctx, mock.Anything, uint(3)).Return(nil, notInQueue())
+	f.queueRepo.On("CountWaiting", ctx, uint(3)).Return(int64(4), nil).Once()
+	f.queueRepo.On("CountWaiting", ctx, uint(3)).Return(int64(5), nil).Once()
+	f.queueRepo.On("CreateEntry", ctx, mock.Anything).Return(true, nil)
+	f.queueRepo.On("Enqueue", ctx, uint(3), mock.Anything, mock.Anything).Return(nil)
+	f.queueRepo.On("Rank", ctx, uint(3), mock.Anything).Return(int64(4), true, nil)
+
+	_, err := f.service.JoinQueue(ctx, 1, 3)
+	require.NoError(t, err)
+	_, err = f.service.JoinQueue(ctx, 2, 3)
+	require.NoError(t, err)
+
+	require.Len(t, channel.events, 1)
+	assert.Equal(t, notifications.AdminEventQueueSaturated, channel.events[0].Kind)
+	assert.Equal(t, uint(3), channel.events[0].EventID)
+}
+
+func TestDiscordAdminChannel(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	channel, err := notifications.NewAdminChannel(notifications.AdminChannelConfig{Channel: notifications.AdminChannelDiscord, WebhookURL: server.URL})
+	require.NoError(t, err)
+	require.NoError(t, channel.Notify(context.Background(), notifications.AdminEvent{
+		Kind:  notifications.AdminEventSoldOut,
+		Title: "@everyone Rock Night is sold out",
+	}))
+	assert.Equal(t, "**@everyone Rock Night is sold out**\n", body["content"])
+	assert.Equal(t, map[string]interface{}{"parse": []interface{}{}}, body["allowed_mentions"])
+
+	_, err = notifications.NewAdminChannel(notifications.AdminChannelConfig{Channel: notifications.AdminChannelSlack})
+	assert.Error(t, err)
+}
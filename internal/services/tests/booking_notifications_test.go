@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"api/constants"
+	"api/internal/domain"
+	"api/internal/entities"
+	"api/internal/notifications"
+	"api/internal/services"
+	"api/pkg/money"
+	"api/test/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingNotifier keeps the notifications it is asked to send
+type recordingNotifier struct {
+	sent []notifications.Notification
+}
+
+func (n *recordingNotifier) Send(ctx context.Context, notification notifications.Notification) error {
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+func TestBookingNotifications(t *testing.T) {
+	ctx := context.Background()
+	userRepo := &mocks.MockUserRepository{}
+	userRepo.On("GetByID", ctx, uint(1)).Return(&entities.User{ID: 1, Email: "fan@example.com"}, nil)
+	notifier := &recordingNotifier{}
+	events := domain.NewDispatcher()
+	services.NewBookingNotifications(userRepo, notifier).Subscribe(events)
+
+	events.Publish(ctx,
+		domain.BookingConfirmed{BookingID: 11, UserID: 1, EventID: 3, TotalAmount: money.FromMajor(50)},
+		domain.SeatReleased{EventID: 3, SeatID: 5, UserID: 1, Reason: domain.ReleaseIntentCancelled},
+		domain.BookingCancelled{BookingID: 11, UserID: 1, EventID: 3},
+		domain.IntentExpired{IntentID: 7, UserID: 1, EventID: 3})
+
+	var types []string
+	for _, sent := range notifier.sent {
+		assert.Equal(t, "fan@example.com", sent.Recipient)
+		assert.Equal(t, uint(3), sent.EventID)
+		types = append(types, sent.Type)
+	}
+	// Released seats alone don't concern the user
+	assert.Equal(t, []string{
+		constants.NotificationTypeBookingConfirmed,
+		constants.NotificationTypeBookingCancelled,
+		constants.NotificationTypeHoldExpired,
+	}, types)
+	userRepo.AssertExpectations(t)
+}
+
+// TestNoShowReleaseNotifications tells holders whose seat went back on sale whether they were
+// refunded
+func TestNoShowReleaseNotifications(t *testing.T) {
+	ctx := context.Background()
+	userRepo := &mocks.MockUserRepository{}
+	userRepo.On("GetByID", ctx, uint(1)).Return(&entities.User{ID: 1, Email: "fan@example.com"}, nil)
+	notifier := &recordingNotifier{}
+	events := domain.NewDispatcher()
+	services.NewBookingNotifications(userRepo, notifier).Subscribe(events)
+
+	events.Publish(ctx,
+		domain.NoShowReleased{BookingID: 11, UserID: 1, EventID: 3, SeatID: 5},
+		domain.SeatReleased{EventID: 3, SeatID: 5, UserID: 1, Reason: domain.ReleaseNoShow},
+		domain.NoShowReleased{BookingID: 12, UserID: 1, EventID: 3, SeatID: 6, RefundAmount: money.FromMajor(40)})
+
+	if assert.Len(t, notifier.sent, 2) {
+		for _, sent := range notifier.sent {
+			assert.Equal(t, constants.NotificationTypeNoShowReleased, sent.Type)
+			assert.Equal(t, "fan@example.com", sent.Recipient)
+		}
+		assert.Equal(t, uint(11), notifier.sent[0].BookingID)
+		assert.Contains(t, notifier.sent[0].Message, "isn't refunded")
+		assert.Contains(t, notifier.sent[1].Message, "40.00 has been refunded")
+	}
+}
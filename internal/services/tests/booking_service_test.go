@@ -0,0 +1,812 @@
+package tests
+
+import (
+	"api/constants"
+	"api/internal/domain"
+	"api/internal/entities"
+	redisconn "api/internal/redis"
+	"api/internal/repository"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/pkg/money"
+	"api/test/mocks"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type BookingServiceTestSuite struct {
+	suite.Suite
+	ctx         context.Context
+	now         time.Time
+	policy      services.BookingPolicy
+	bookingRepo *mocks.MockBookingRepository
+	seatLocks   *mocks.MockSeatLockRepository
+	unitOfWork  *mocks.MockUnitOfWork
+	published   []domain.Event
+	service     *services.BookingService
+}
+
+func (suite *BookingServiceTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	suite.policy = services.DefaultBookingPolicy()
+	suite.policy.MaxPendingIntents = 2
+	suite.bookingRepo = &mocks.MockBookingRepository{}
+	suite.seatLocks = &mocks.MockSeatLockRepository{}
+	suite.unitOfWork = &mocks.MockUnitOfWork{}
+	suite.unitOfWork.On("Do", suite.ctx).Return(nil).Maybe()
+	suite.published = nil
+	events := domain.NewDispatcher()
+	recordEvents[domain.BookingConfirmed](suite, events)
+	recordEvents[domain.BookingCancelled](suite, events)
+	recordEvents[domain.IntentExpired](suite, events)
+	recordEvents[domain.SeatReleased](suite, events)
+	suite.service = services.NewBookingService(suite.bookingRepo, suite.seatLocks, nil, suite.unitOfWork, events, suite.policy).
+		WithClock(func() time.Time { return suite.now })
+}
+
+// recordEvents keeps the events of type T the service publishes
+func recordEvents[T domain.Event](suite *BookingServiceTestSuite, events *domain.Dispatcher) {
+	domain.Subscribe(events, "test", func(ctx context.Context, event T) error {
+		suite.published = append(suite.published, event)
+		return nil
+	})
+}
+
+func (suite *BookingServiceTestSuite) TearDownTest() {
+	suite.bookingRepo.AssertExpectations(suite.T())
+	suite.seatLocks.AssertExpectations(suite.T())
+	suite.unitOfWork.AssertExpectations(suite.T())
+}
+
+func TestBookingServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(BookingServiceTestSuite))
+}
+
+// seat returns a bookable seat of an upcoming event
+func (suite *BookingServiceTestSuite) seat() *entities.Seat {
+	return &entities.Seat{
+		ID:          5,
+		EventID:     3,
+		Price:       money.FromMajor(50),
+		IsAvailable: true,
+		Event: entities.Event{
+			ID:             3,
+			Price:          money.FromMajor(50),
+			Status:         constants.EventStatusActive,
+			StartTime:      suite.now.Add(48 * time.Hour),
+			AvailableSeats: 10,
+		},
+	}
+}
+
+// pendingIntent returns a pending intent whose lock expires in the given time
+func (suite *BookingServiceTestSuite) pendingIntent(expiresIn time.Duration) *entities.BookingIntent {
+	return &entities.BookingIntent{
+		ID:            7,
+		UserID:        1,
+		EventID:       3,
+		SeatID:        5,
+		Status:        constants.IntentStatusPending,
+		LockExpiresAt: suite.now.Add(expiresIn),
+	}
+}
+
+func (suite *BookingServiceTestSuite) assertAppError(err error, errType, message string) {
+	appErr, ok := err.(*errors.AppError)
+	if suite.True(ok, "expected an AppError, got %v", err) {
+		suite.Equal(errType, appErr.Type)
+		suite.Equal(message, appErr.Message)
+	}
+}
+
+// storesIntent expects the intent to be stored and assigns it an ID
+func (suite *BookingServiceTestSuite) storesIntent(id uint) *mock.Call {
+	return suite.bookingRepo.On("CreateBookingIntent", suite.ctx, mock.AnythingOfType("*entities.BookingIntent"), 2).
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*entities.BookingIntent).ID = id
+		}).Return(nil)
+}
+
+func (suite *BookingServiceTestSuite) TestCreateBookingIntent_LocksSeatInRedis() {
+	seat := suite.seat()
+	expiresAt := suite.now.Add(8 * time.Minute)
+	tempID := fmt.Sprintf("temp_1_%d", suite.now.UnixNano())
+	details := &entities.BookingIntent{ID: 7}
+
+	suite.bookingRepo.On("GetSeat", suite.ctx, uint(5)).Return(seat, nil)
+	suite.seatLocks.On("IsLocked", suite.ctx, uint(3), uint(5)).Return(false, "", nil)
+	suite.bookingRepo.On("CheckSaleEligibility", suite.ctx, seat, uint(1), "").Return(nil, nil)
+	suite.seatLocks.On("LockSeat", suite.ctx, uint(3), uint(5), uint(1), tempID, expiresAt).Return(nil)
+	suite.storesIntent(7)
+	suite.seatLocks.On("UnlockSeat", suite.ctx, uint(3), uint(5), uint(1), tempID).Return(nil)
+	suite.seatLocks.On("LockSeat", suite.ctx, uint(3), uint(5), uint(1), "7", expiresAt).Return(nil)
+	suite.bookingRepo.On("GetIntentDetails", suite.ctx, uint(7)).Return(details, nil)
+
+	intent, err := suite.service.CreateBookingIntent(suite.ctx, 1, 5, entities.BookingIntentOptions{})
+
+	suite.NoError(err)
+	suite.Equal(details, intent)
+	stored := suite.bookingRepo.Calls[2].Arguments.Get(1).(*entities.BookingIntent)
+	suite.Equal(expiresAt, stored.LockExpiresAt)
+	suite.Equal(suite.now, stored.CreatedAt)
+	suite.Equal(constants.IntentStatusPending, stored.Status)
+	suite.bookingRepo.AssertNotCalled(suite.T(), "LockSeatInDatabase", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *BookingServiceTestSuite) TestCreateBookingIntent_SeatLockedInRedis() {
+	tests := []struct {
+		name    string
+		ownLock bool
+		message string
+	}{
+		{"by another user", false, constants.ErrSeatAlreadyLocked},
+		{"by the same user", true, "You already have an active booking intent for this seat"},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+			suite.bookingRepo.On("GetSeat", suite.ctx, uint(5)).Return(suite.seat(), nil)
+			suite.seatLocks.On("IsLocked", suite.ctx, uint(3), uint(5)).Return(true, "9", nil)
+			suite.seatLocks.On("IsLockedByUser", suite.ctx, uint(3), uint(5), uint(1)).Return(tt.ownLock, "9", nil)
+
+			intent, err := suite.service.CreateBookingIntent(suite.ctx, 1, 5, entities.BookingIntentOptions{})
+
+			suite.Nil(intent)
+			suite.assertAppError(err, "CONFLICT", tt.message)
+			suite.TearDownTest()
+		})
+	}
+}
+
+func (suite *BookingServiceTestSuite) TestCreateBookingIntent_RejectsUnbookableSeats() {
+	lockedAt := suite.now.Add(-time.Minute)
+	lockExpiresAt := suite.now.Add(7 * time.Minute)
+	longLockedAt := suite.now.Add(-time.Hour)
+	longLockExpiresAt := suite.now.Add(time.Minute)
+	staleLockedAt := suite.now.Add(-9 * time.Minute)
+	staleLockExpiresAt := suite.now.Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		modify  func(seat *entities.Seat)
+		errType string
+		message string
+	}{
+		{"sold", func(seat *entities.Seat) { seat.IsAvailable = false }, "BAD_REQUEST", constants.ErrSeatNotAvailable},
+		{"held back", func(seat *entities.Seat) { seat.IsHeld = true }, "BAD_REQUEST", constants.ErrSeatNotReleased},
+		{"locked in the database", func(seat *entities.Seat) {
+			seat.IsLocked = true
+			seat.LockedAt = &lockedAt
+			seat.LockExpiresAt = &lockExpiresAt
+		}, "CONFLICT", constants.ErrSeatAlreadyLocked},
+		// The lock duration may have been lowered since: the holding intent's expiry decides
+		{"locked in the database longer than the lock duration", func(seat *entities.Seat) {
+			seat.IsLocked = true
+			seat.LockedAt = &longLockedAt
+			seat.LockExpiresAt = &longLockExpiresAt
+		}, "CONFLICT", constants.ErrSeatAlreadyLocked},
+		{"locked in the database without a lock expiry", func(seat *entities.Seat) {
+			seat.IsLocked = true
+			seat.LockedAt = &staleLockedAt
+		}, "CONFLICT", constants.ErrSeatAlreadyLocked},
+		{"event not active", func(seat *entities.Seat) { seat.Event.Status = constants.EventStatusCancelled }, "BAD_REQUEST", "Event is not active"},
+		{"event started", func(seat *entities.Seat) { seat.Event.StartTime = suite.now.Add(-time.Minute) }, "BAD_REQUEST", "Event has already started"},
+		{"sales closed", func(seat *entities.Seat) {
+			closeMinutes := 30
+			seat.Event.StartTime = suite.now.Add(20 * time.Minute)
+			seat.Event.SalesCloseMinutesBeforeStart = &closeMinutes
+		}, "BAD_REQUEST", constants.ErrSalesClosed},
+		{"event sold out", func(seat *entities.Seat) { seat.Event.AvailableSeats = 0 }, "BAD_REQUEST", constants.ErrEventSoldOut},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+			seat := suite.seat()
+			tt.modify(seat)
+			suite.bookingRepo.On("GetSeat", suite.ctx, uint(5)).Return(seat, nil)
+			suite.seatLocks.On("IsLocked", suite.ctx, uint(3), uint(5)).Return(false, "", nil)
+
+			intent, err := suite.service.CreateBookingIntent(suite.ctx, 1, 5, entities.BookingIntentOptions{})
+
+			suite.Nil(intent)
+			suite.assertAppError(err, tt.errType, tt.message)
+			suite.TearDownTest()
+		})
+	}
+
+	suite.Run("stale database lock", func() {
+		suite.SetupTest()
+		seat := suite.seat()
+		seat.IsLocked = true
+		seat.LockedAt = &staleLockedAt
+		seat.LockExpiresAt = &staleLockExpiresAt
+		suite.bookingRepo.On("GetSeat", suite.ctx, uint(5)).Return(seat, nil)
+		suite.seatLocks.On("IsLocked", suite.ctx, uint(3), uint(5)).Return(false, "", redisconn.ErrUnavailable)
+		suite.bookingRepo.On("CheckSaleEligibility", suite.ctx, seat, uint(1), "").Return(nil, nil)
+		suite.storesIntent(7)
+		suite.bookingRepo.On("LockSeatInDatabase", suite.ctx, uint(5), uint(1), suite.now, suite.now.Add(8*time.Minute)).Return(nil)
+		suite.bookingRepo.On("GetIntentDetails", suite.ctx, uint(7)).Return(&entities.BookingIntent{ID: 7}, nil)
+
+		_, err := suite.service.CreateBookingIntent(suite.ctx, 1, 5, entities.BookingIntentOptions{})
+
+		suite.NoError(err)
+		suite.TearDownTest()
+	})
+}
+
+func (suite *BookingServiceTestSuite) TestCreateBookingIntent_RequiresTermsAcceptance() {
+	seat := suite.seat()
+	seat.Event.TermsVersion = "v2"
+	suite.bookingRepo.On("GetSeat", suite.ctx, uint(5)).Return(seat, nil)
+	suite.seatLocks.On("IsLocked", suite.ctx, uint(3), uint(5)).Return(false, "", nil)
+	suite.bookingRepo.On("CheckSaleEligibility", suite.ctx, seat, uint(1), "").Return(nil, nil)
+
+	_, err := suite.service.CreateBookingIntent(suite.ctx, 1, 5, entities.BookingIntentOptions{AcceptTerms: true, TermsVersion: "v1"})
+
+	suite.assertAppError(err, "BAD_REQUEST", constants.ErrTermsVersionChanged)
+}
+
+func (suite *BookingServiceTestSuite) TestCreateBookingIntent_ReleasesTempLockWhenStoringFails() {
+	seat := suite.seat()
+	expiresAt := suite.now.Add(8 * time.Minute)
+	tempID := fmt.Sprintf("temp_1_%d", suite.now.UnixNano())
+	tooMany := errors.NewConflictError(constants.ErrTooManyIntents, nil)
+
+	suite.bookingRepo.On("GetSeat", suite.ctx, uint(5)).Return(seat, nil)
+	suite.seatLocks.On("IsLocked", suite.ctx, uint(3), uint(5)).Return(false, "", nil)
+	suite.bookingRepo.On("CheckSaleEligibility", suite.ctx, seat, uint(1), "").Return(nil, nil)
+	suite.seatLocks.On("LockSeat", suite.ctx, uint(3), uint(5), uint(1), tempID, expiresAt).Return(nil)
+	suite.bookingRepo.On("CreateBookingIntent", suite.ctx, mock.AnythingOfType("*entities.BookingIntent"), 2).Return(tooMany)
+	suite.seatLocks.On("UnlockSeat", suite.ctx, uint(3), uint(5), uint(1), tempID).Return(nil)
+
+	intent, err := suite.service.CreateBookingIntent(suite.ctx, 1, 5, entities.BookingIntentOptions{})
+
+	suite.Nil(intent)
+	suite.Equal(tooMany, err)
+}
+
+func (suite *BookingServiceTestSuite) TestCreateBookingIntent_FallsBackToDatabaseLock() {
+	seat := suite.seat()
+	suite.bookingRepo.On("GetSeat", suite.ctx, uint(5)).Return(seat, nil)
+	suite.seatLocks.On("IsLocked", suite.ctx, uint(3), uint(5)).Return(false, "", redisconn.ErrUnavailable)
+	suite.bookingRepo.On("CheckSaleEligibility", suite.ctx, seat, uint(1), "").Return(nil, nil)
+	suite.storesIntent(7)
+	suite.bookingRepo.On("LockSeatInDatabase", suite.ctx, uint(5), uint(1), suite.now, suite.now.Add(8*time.Minute)).Return(nil)
+	suite.bookingRepo.On("GetIntentDetails", suite.ctx, uint(7)).Return(&entities.BookingIntent{ID: 7}, nil)
+
+	intent, err := suite.service.CreateBookingIntent(suite.ctx, 1, 5, entities.BookingIntentOptions{})
+
+	suite.NoError(err)
+	suite.Equal(uint(7), intent.ID)
+	suite.seatLocks.AssertNotCalled(suite.T(), "LockSeat", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *BookingServiceTestSuite) TestCreateBookingIntent_ReleasesRedisLockWhenCommitFails() {
+	seat := suite.seat()
+	expiresAt := suite.now.Add(8 * time.Minute)
+	tempID := fmt.Sprintf("temp_1_%d", suite.now.UnixNano())
+	commitErr := errors.NewInternalError("Failed to commit", nil)
+
+	suite.unitOfWork = &mocks.MockUnitOfWork{}
+	suite.unitOfWork.On("Do", suite.ctx).Return(commitErr)
+	suite.service = services.NewBookingService(suite.bookingRepo, suite.seatLocks, nil, suite.unitOfWork, nil, suite.policy).
+		WithClock(func() time.Time { return suite.now })
+
+	suite.bookingRepo.On("GetSeat", suite.ctx, uint(5)).Return(seat, nil)
+	suite.seatLocks.On("IsLocked", suite.ctx, uint(3), uint(5)).Return(false, "", nil)
+	suite.bookingRepo.On("CheckSaleEligibility", suite.ctx, seat, uint(1), "").Return(nil, nil)
+	suite.seatLocks.On("LockSeat", suite.ctx, uint(3), uint(5), uint(1), tempID, expiresAt).Return(nil)
+	suite.storesIntent(7)
+	suite.seatLocks.On("UnlockSeat", suite.ctx, uint(3), uint(5), uint(1), tempID).Return(nil)
+	suite.seatLocks.On("LockSeat", suite.ctx, uint(3), uint(5), uint(1), "7", expiresAt).Return(nil)
+	suite.seatLocks.On("UnlockSeat", suite.ctx, uint(3), uint(5), uint(1), "7").Return(nil)
+
+	intent, err := suite.service.CreateBookingIntent(suite.ctx, 1, 5, entities.BookingIntentOptions{})
+
+	suite.Nil(intent)
+	suite.Equal(commitErr, err)
+}
+
+func (suite *BookingServiceTestSuite) TestCreateBookingIntent_FallsBackToDatabaseLockWhenRedisLockFails() {
+	seat := suite.seat()
+	expiresAt := suite.now.Add(8 * time.Minute)
+	tempID := fmt.Sprintf("temp_1_%d", suite.now.UnixNano())
+
+	suite.bookingRepo.On("GetSeat", suite.ctx, uint(5)).Return(seat, nil)
+	suite.seatLocks.On("IsLocked", suite.ctx, uint(3), uint(5)).Return(false, "", nil)
+	suite.bookingRepo.On("CheckSaleEligibility", suite.ctx, seat, uint(1), "").Return(nil, nil)
+	suite.seatLocks.On("LockSeat", suite.ctx, uint(3), uint(5), uint(1), tempID, expiresAt).Return(nil)
+	suite.storesIntent(7)
+	suite.seatLocks.On("UnlockSeat", suite.ctx, uint(3), uint(5), uint(1), tempID).Return(nil)
+	suite.seatLocks.On("LockSeat", suite.ctx, uint(3), uint(5), uint(1), "7", expiresAt).Return(redisconn.ErrUnavailable)
+	suite.bookingRepo.On("LockSeatInDatabase", suite.ctx, uint(5), uint(1), suite.now, suite.now.Add(8*time.Minute)).Return(nil)
+	suite.bookingRepo.On("GetIntentDetails", suite.ctx, uint(7)).Return(&entities.BookingIntent{ID: 7}, nil)
+
+	_, err := suite.service.CreateBookingIntent(suite.ctx, 1, 5, entities.BookingIntentOptions{})
+
+	suite.NoError(err)
+}
+
+func (suite *BookingServiceTestSuite) TestCreateBookingIntent_LostDatabaseLockRollsBack() {
+	seat := suite.seat()
+	lost := errors.NewConflictError(constants.ErrSeatAlreadyLocked, nil)
+	suite.bookingRepo.On("GetSeat", suite.ctx, uint(5)).Return(seat, nil)
+	suite.seatLocks.On("IsLocked", suite.ctx, uint(3), uint(5)).Return(false, "", redisconn.ErrUnavailable)
+	suite.bookingRepo.On("CheckSaleEligibility", suite.ctx, seat, uint(1), "").Return(nil, nil)
+	suite.storesIntent(7)
+	suite.bookingRepo.On("LockSeatInDatabase", suite.ctx, uint(5), uint(1), suite.now, suite.now.Add(8*time.Minute)).Return(lost)
+
+	intent, err := suite.service.CreateBookingIntent(suite.ctx, 1, 5, entities.BookingIntentOptions{})
+
+	suite.Nil(intent)
+	suite.Equal(lost, err)
+}
+
+func (suite *BookingServiceTestSuite) TestCreateBookingIntent_ConfirmsFreeEventsStraightAway() {
+	seat := suite.seat()
+	seat.Price, seat.Event.Price = 0, 0
+	attendee := entities.AttendeeDetails{FullName: "Ada Lovelace"}
+	booking := &entities.Booking{ID: 11, UserID: 1, EventID: 3, SeatID: 5, PaymentStatus: constants.PaymentStatusFree}
+	details := &entities.BookingIntent{ID: 7, Status: constants.IntentStatusConfirmed}
+
+	suite.bookingRepo.On("GetSeat", suite.ctx, uint(5)).Return(seat, nil)
+	suite.seatLocks.On("IsLocked", suite.ctx, uint(3), uint(5)).Return(false, "", redisconn.ErrUnavailable)
+	suite.bookingRepo.On("CheckSaleEligibility", suite.ctx, seat, uint(1), "").Return(nil, nil)
+	suite.storesIntent(7)
+	suite.bookingRepo.On("LockSeatInDatabase", suite.ctx, uint(5), uint(1), suite.now, suite.now.Add(8*time.Minute)).Return(nil)
+	suite.bookingRepo.On("GetPendingIntent", suite.ctx, uint(7)).Return(suite.pendingIntent(8*time.Minute), nil)
+	suite.bookingRepo.On("ConfirmBooking", suite.ctx, uint(7), entities.PaymentDetails{}, attendee, entities.ConfirmOptions{}).Return(booking, nil, nil)
+	suite.seatLocks.On("UnlockSeat", suite.ctx, uint(3), uint(5), uint(1), "7").Return(nil)
+	suite.seatLocks.On("ClearHeartbeat", suite.ctx, uint(7)).Return(nil)
+	suite.bookingRepo.On("GetIntentDetails", suite.ctx, uint(7)).Return(details, nil)
+
+	intent, err := suite.service.CreateBookingIntent(suite.ctx, 1, 5, entities.BookingIntentOptions{Attendee: attendee})
+
+	suite.NoError(err)
+	suite.Equal(constants.IntentStatusConfirmed, intent.Status)
+	suite.Equal(booking, intent.Booking)
+	suite.Equal(domain.BookingConfirmed{BookingID: 11, IntentID: 7, UserID: 1, EventID: 3, SeatID: 5, OccurredAt: suite.now}, suite.published[0])
+}
+
+func (suite *BookingServiceTestSuite) TestCreateBookingIntent_CancelsFreeIntentOverTicketLimit() {
+	seat := suite.seat()
+	seat.Price, seat.Event.Price = 0, 0
+	limitReached := errors.NewConflictError(constants.ErrTicketLimitReached, nil)
+
+	suite.bookingRepo.On("GetSeat", suite.ctx, uint(5)).Return(seat, nil)
+	suite.seatLocks.On("IsLocked", suite.ctx, uint(3), uint(5)).Return(false, "", redisconn.ErrUnavailable)
+	suite.bookingRepo.On("CheckSaleEligibility", suite.ctx, seat, uint(1), "").Return(nil, nil)
+	suite.storesIntent(7)
+	suite.bookingRepo.On("LockSeatInDatabase", suite.ctx, uint(5), uint(1), suite.now, suite.now.Add(8*time.Minute)).Return(nil)
+	suite.bookingRepo.On("GetPendingIntent", suite.ctx, uint(7)).Return(suite.pendingIntent(8*time.Minute), nil)
+	suite.bookingRepo.On("ConfirmBooking", suite.ctx, uint(7), mock.Anything, mock.Anything, mock.Anything).Return(nil, nil, limitReached)
+	suite.bookingRepo.On("CancelBookingIntent", suite.ctx, uint(7), uint(1)).Return(suite.pendingIntent(8*time.Minute), nil)
+	suite.seatLocks.On("UnlockSeat", suite.ctx, uint(3), uint(5), uint(1), "7").Return(nil)
+	suite.seatLocks.On("ClearHeartbeat", suite.ctx, uint(7)).Return(nil)
+
+	intent, err := suite.service.CreateBookingIntent(suite.ctx, 1, 5, entities.BookingIntentOptions{})
+
+	suite.Nil(intent)
+	suite.Equal(limitReached, err)
+	suite.Equal(domain.SeatReleased{EventID: 3, SeatID: 5, UserID: 1, Reason: domain.ReleaseIntentCancelled, OccurredAt: suite.now}, suite.published[len(suite.published)-1])
+}
+
+func (suite *BookingServiceTestSuite) TestConfirmBooking_ReleasesLocksAfterCommit() {
+	intent := suite.pendingIntent(time.Minute)
+	booking := &entities.Booking{ID: 11, UserID: 1, EventID: 3, SeatID: 5, TotalAmount: money.FromMajor(50)}
+	released := []entities.BookingIntent{{ID: 8, UserID: 1, EventID: 3, SeatID: 6}}
+	payment := entities.PaymentDetails{PaymentID: "pay_1"}
+	options := entities.ConfirmOptions{ReleaseOtherIntents: true}
+
+	suite.bookingRepo.On("GetPendingIntent", suite.ctx, uint(7)).Return(intent, nil)
+	suite.bookingRepo.On("ConfirmBooking", suite.ctx, uint(7), payment, entities.AttendeeDetails{}, options).Return(booking, released, nil)
+	suite.seatLocks.On("UnlockSeat", suite.ctx, uint(3), uint(5), uint(1), "7").Return(nil)
+	suite.seatLocks.On("ClearHeartbeat", suite.ctx, uint(7)).Return(nil)
+	suite.seatLocks.On("UnlockSeat", suite.ctx, uint(3), uint(6), uint(1), "8").Return(nil)
+	suite.seatLocks.On("ClearHeartbeat", suite.ctx, uint(8)).Return(nil)
+
+	confirmed, err := suite.service.ConfirmBooking(suite.ctx, 7, payment, entities.AttendeeDetails{}, options)
+
+	suite.NoError(err)
+	suite.Equal(booking, confirmed)
+	suite.Equal([]domain.Event{
+		domain.BookingConfirmed{BookingID: 11, IntentID: 7, UserID: 1, EventID: 3, SeatID: 5, TotalAmount: money.FromMajor(50), OccurredAt: suite.now},
+		domain.SeatReleased{EventID: 3, SeatID: 6, UserID: 1, Reason: domain.ReleaseIntentReleased, OccurredAt: suite.now},
+	}, suite.published)
+}
+
+func (suite *BookingServiceTestSuite) TestConfirmBooking_RejectsExpiredIntent() {
+	suite.bookingRepo.On("GetPendingIntent", suite.ctx, uint(7)).Return(suite.pendingIntent(-time.Second), nil)
+
+	booking, err := suite.service.ConfirmBooking(suite.ctx, 7, entities.PaymentDetails{PaymentID: "pay_1"}, entities.AttendeeDetails{}, entities.ConfirmOptions{})
+
+	suite.Nil(booking)
+	suite.assertAppError(err, "BAD_REQUEST", constants.ErrBookingExpired)
+}
+
+func (suite *BookingServiceTestSuite) TestConfirmBooking_KeepsLockWhenConfirmationFails() {
+	soldOut := errors.NewBadRequestError(constants.ErrEventSoldOut, nil)
+	suite.bookingRepo.On("GetPendingIntent", suite.ctx, uint(7)).Return(suite.pendingIntent(time.Minute), nil)
+	suite.bookingRepo.On("ConfirmBooking", suite.ctx, uint(7), mock.Anything, mock.Anything, mock.Anything).Return(nil, nil, soldOut)
+
+	_, err := suite.service.ConfirmBooking(suite.ctx, 7, entities.PaymentDetails{PaymentID: "pay_1"}, entities.AttendeeDetails{}, entities.ConfirmOptions{})
+
+	suite.Equal(soldOut, err)
+	suite.Empty(suite.published)
+}
+
+func (suite *BookingServiceTestSuite) TestCancelBookingIntent_UnlocksSeat() {
+	suite.bookingRepo.On("CancelBookingIntent", suite.ctx, uint(7), uint(1)).Return(suite.pendingIntent(time.Minute), nil)
+	suite.seatLocks.On("UnlockSeat", suite.ctx, uint(3), uint(5), uint(1), "7").Return(redisconn.ErrUnavailable)
+	suite.seatLocks.On("ClearHeartbeat", suite.ctx, uint(7)).Return(redisconn.ErrUnavailable)
+
+	suite.NoError(suite.service.CancelBookingIntent(suite.ctx, 7, 1))
+	suite.Equal([]domain.Event{
+		domain.SeatReleased{EventID: 3, SeatID: 5, UserID: 1, Reason: domain.ReleaseIntentCancelled, OccurredAt: suite.now},
+	}, suite.published)
+}
+
+func (suite *BookingServiceTestSuite) TestCancelUserIntents_ReleasesEverySeat() {
+	first := *suite.pendingIntent(time.Minute)
+	second := *suite.pendingIntent(time.Minute)
+	second.ID, second.SeatID = 8, 6
+	suite.bookingRepo.On("CancelUserIntents", suite.ctx, uint(1)).Return([]entities.BookingIntent{first, second}, nil)
+	suite.seatLocks.On("UnlockSeat", suite.ctx, uint(3), uint(5), uint(1), "7").Return(nil)
+	suite.seatLocks.On("UnlockSeat", suite.ctx, uint(3), uint(6), uint(1), "8").Return(nil)
+	suite.seatLocks.On("ClearHeartbeat", suite.ctx, mock.Anything).Return(nil)
+
+	cancelled, err := suite.service.CancelUserIntents(suite.ctx, 1)
+	suite.NoError(err)
+	suite.Equal(2, cancelled)
+	suite.Equal([]domain.Event{
+		domain.SeatReleased{EventID: 3, SeatID: 5, UserID: 1, Reason: domain.ReleaseIntentCancelled, OccurredAt: suite.now},
+		domain.SeatReleased{EventID: 3, SeatID: 6, UserID: 1, Reason: domain.ReleaseIntentCancelled, OccurredAt: suite.now},
+	}, suite.published)
+}
+
+func (suite *BookingServiceTestSuite) TestCancelBooking() {
+	suite.Run("before the event", func() {
+		suite.SetupTest()
+		booking := &entities.Booking{ID: 11, Event: entities.Event{StartTime: suite.now.Add(time.Hour)}}
+		suite.bookingRepo.On("GetConfirmedBooking", suite.ctx, uint(11), uint(1)).Return(booking, nil)
+		// The companion seat booked alongside it is cancelled too
+		cancelled := []entities.Booking{{ID: 11, UserID: 1, EventID: 3, SeatID: 5}, {ID: 12, UserID: 1, EventID: 3, SeatID: 6}}
+		suite.bookingRepo.On("CancelBooking", suite.ctx, uint(11)).Return(cancelled, nil)
+
+		suite.NoError(suite.service.CancelBooking(suite.ctx, 11, 1))
+		suite.Equal([]domain.Event{
+			domain.BookingCancelled{BookingID: 11, UserID: 1, EventID: 3, SeatID: 5, OccurredAt: suite.now},
+			domain.SeatReleased{EventID: 3, SeatID: 5, UserID: 1, Reason: domain.ReleaseBookingCancelled, OccurredAt: suite.now},
+			domain.BookingCancelled{BookingID: 12, UserID: 1, EventID: 3, SeatID: 6, OccurredAt: suite.now},
+			domain.SeatReleased{EventID: 3, SeatID: 6, UserID: 1, Reason: domain.ReleaseBookingCancelled, OccurredAt: suite.now},
+		}, suite.published)
+		suite.TearDownTest()
+	})
+
+	suite.Run("after the event started", func() {
+		suite.SetupTest()
+		booking := &entities.Booking{ID: 11, Event: entities.Event{StartTime: suite.now.Add(-time.Hour)}}
+		suite.bookingRepo.On("GetConfirmedBooking", suite.ctx, uint(11), uint(1)).Return(booking, nil)
+
+		err := suite.service.CancelBooking(suite.ctx, 11, 1)
+
+		suite.assertAppError(err, "BAD_REQUEST", "Cannot cancel booking after event has started")
+		suite.Empty(suite.published)
+		suite.TearDownTest()
+	})
+}
+
+func (suite *BookingServiceTestSuite) TestHeartbeatBookingIntent() {
+	suite.Run("records the heartbeat", func() {
+		suite.SetupTest()
+		intent := suite.pendingIntent(time.Minute)
+		suite.bookingRepo.On("GetUserPendingIntent", suite.ctx, uint(7), uint(1)).Return(intent, nil)
+		suite.seatLocks.On("RecordHeartbeat", suite.ctx, uint(7), suite.now).Return(nil)
+
+		got, err := suite.service.HeartbeatBookingIntent(suite.ctx, 7, 1)
+
+		suite.NoError(err)
+		suite.Equal(intent, got)
+		suite.TearDownTest()
+	})
+
+	suite.Run("degraded mode", func() {
+		suite.SetupTest()
+		suite.bookingRepo.On("GetUserPendingIntent", suite.ctx, uint(7), uint(1)).Return(suite.pendingIntent(time.Minute), nil)
+		suite.seatLocks.On("RecordHeartbeat", suite.ctx, uint(7), suite.now).Return(redisconn.ErrUnavailable)
+
+		_, err := suite.service.HeartbeatBookingIntent(suite.ctx, 7, 1)
+
+		suite.NoError(err)
+		suite.TearDownTest()
+	})
+
+	suite.Run("expired intent", func() {
+		suite.SetupTest()
+		suite.bookingRepo.On("GetUserPendingIntent", suite.ctx, uint(7), uint(1)).Return(suite.pendingIntent(-time.Second), nil)
+
+		_, err := suite.service.HeartbeatBookingIntent(suite.ctx, 7, 1)
+
+		suite.assertAppError(err, "BAD_REQUEST", constants.ErrBookingExpired)
+		suite.TearDownTest()
+	})
+}
+
+func (suite *BookingServiceTestSuite) TestIssueResumeToken_ExpiresWithTheLock() {
+	tests := []struct {
+		name      string
+		lockLeft  time.Duration
+		expiresIn time.Duration
+	}{
+		{"token TTL first", 5 * time.Minute, 2 * time.Minute},
+		{"lock expiry first", time.Minute, time.Minute},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+			suite.bookingRepo.On("GetUserPendingIntent", suite.ctx, uint(7), uint(1)).Return(suite.pendingIntent(tt.lockLeft), nil)
+			suite.bookingRepo.On("SaveResumeToken", suite.ctx, uint(7), mock.AnythingOfType("string"), suite.now.Add(tt.expiresIn)).Return(nil)
+
+			token, expiresAt, err := suite.service.IssueResumeToken(suite.ctx, 7, 1)
+
+			suite.NoError(err)
+			suite.Len(token, 64)
+			suite.Equal(suite.now.Add(tt.expiresIn), expiresAt)
+			suite.NotEqual(token, suite.bookingRepo.Calls[1].Arguments.String(2), "only the token's hash is stored")
+			suite.TearDownTest()
+		})
+	}
+}
+
+func (suite *BookingServiceTestSuite) TestResumeBookingIntent() {
+	tokenExpiry := suite.now.Add(time.Minute)
+	resumable := func() *entities.BookingIntent {
+		intent := suite.pendingIntent(5 * time.Minute)
+		intent.ResumeTokenExpiresAt = &tokenExpiry
+		return intent
+	}
+
+	suite.Run("hands the checkout over", func() {
+		suite.SetupTest()
+		intent := resumable()
+		details := &entities.BookingIntent{ID: 7}
+		suite.bookingRepo.On("GetIntentByResumeToken", suite.ctx, uint(1), mock.AnythingOfType("string")).Return(intent, nil)
+		suite.bookingRepo.On("GetSeat", suite.ctx, uint(5)).Return(suite.seat(), nil)
+		suite.seatLocks.On("AlignLock", suite.ctx, uint(3), uint(5), uint(1), "7", intent.LockExpiresAt, time.Second).
+			Return(repository.LockRestored, time.Duration(0), nil)
+		suite.bookingRepo.On("RedeemResumeToken", suite.ctx, uint(7), mock.AnythingOfType("string")).Return(true, nil)
+		suite.seatLocks.On("RecordHeartbeat", suite.ctx, uint(7), suite.now).Return(nil)
+		suite.bookingRepo.On("GetIntentDetails", suite.ctx, uint(7)).Return(details, nil)
+
+		got, err := suite.service.ResumeBookingIntent(suite.ctx, 1, "token")
+
+		suite.NoError(err)
+		suite.Equal(details, got)
+		suite.TearDownTest()
+	})
+
+	suite.Run("expired token", func() {
+		suite.SetupTest()
+		intent := resumable()
+		expired := suite.now.Add(-time.Second)
+		intent.ResumeTokenExpiresAt = &expired
+		suite.bookingRepo.On("GetIntentByResumeToken", suite.ctx, uint(1), mock.AnythingOfType("string")).Return(intent, nil)
+
+		_, err := suite.service.ResumeBookingIntent(suite.ctx, 1, "token")
+
+		suite.assertAppError(err, "BAD_REQUEST", constants.ErrInvalidResumeToken)
+		suite.TearDownTest()
+	})
+
+	suite.Run("seat locked in the database by someone else", func() {
+		suite.SetupTest()
+		other := uint(2)
+		seat := suite.seat()
+		seat.IsLocked = true
+		seat.LockedBy = &other
+		suite.bookingRepo.On("GetIntentByResumeToken", suite.ctx, uint(1), mock.AnythingOfType("string")).Return(resumable(), nil)
+		suite.bookingRepo.On("GetSeat", suite.ctx, uint(5)).Return(seat, nil)
+
+		_, err := suite.service.ResumeBookingIntent(suite.ctx, 1, "token")
+
+		suite.assertAppError(err, "CONFLICT", constants.ErrSeatHoldLost)
+		suite.TearDownTest()
+	})
+
+	suite.Run("Redis lock held by another intent", func() {
+		suite.SetupTest()
+		intent := resumable()
+		suite.bookingRepo.On("GetIntentByResumeToken", suite.ctx, uint(1), mock.AnythingOfType("string")).Return(intent, nil)
+		suite.bookingRepo.On("GetSeat", suite.ctx, uint(5)).Return(suite.seat(), nil)
+		suite.seatLocks.On("AlignLock", suite.ctx, uint(3), uint(5), uint(1), "7", intent.LockExpiresAt, time.Second).
+			Return(repository.LockConflict, time.Duration(0), nil)
+
+		_, err := suite.service.ResumeBookingIntent(suite.ctx, 1, "token")
+
+		suite.assertAppError(err, "CONFLICT", constants.ErrSeatHoldLost)
+		suite.TearDownTest()
+	})
+
+	suite.Run("token redeemed concurrently", func() {
+		suite.SetupTest()
+		intent := resumable()
+		suite.bookingRepo.On("GetIntentByResumeToken", suite.ctx, uint(1), mock.AnythingOfType("string")).Return(intent, nil)
+		suite.bookingRepo.On("GetSeat", suite.ctx, uint(5)).Return(suite.seat(), nil)
+		suite.seatLocks.On("AlignLock", suite.ctx, uint(3), uint(5), uint(1), "7", intent.LockExpiresAt, time.Second).
+			Return(0, time.Duration(0), redisconn.ErrUnavailable)
+		suite.bookingRepo.On("RedeemResumeToken", suite.ctx, uint(7), mock.AnythingOfType("string")).Return(false, nil)
+
+		_, err := suite.service.ResumeBookingIntent(suite.ctx, 1, "token")
+
+		suite.assertAppError(err, "BAD_REQUEST", constants.ErrInvalidResumeToken)
+		suite.TearDownTest()
+	})
+}
+
+func (suite *BookingServiceTestSuite) TestRetryPayment() {
+	suite.Run("starts a new attempt", func() {
+		suite.SetupTest()
+		retried := &entities.BookingIntent{ID: 7}
+		suite.bookingRepo.On("GetUserPendingIntent", suite.ctx, uint(7), uint(1)).Return(suite.pendingIntent(time.Minute), nil)
+		suite.bookingRepo.On("StartPaymentAttempt", suite.ctx, uint(7), mock.AnythingOfType("string"), "card_declined", constants.MaxPaymentAttempts).
+			Return(retried, nil)
+
+		got, err := suite.service.RetryPayment(suite.ctx, 7, 1, "card_declined")
+
+		suite.NoError(err)
+		suite.Equal(retried, got)
+		suite.Contains(suite.bookingRepo.Calls[1].Arguments.String(2), constants.PaymentReferencePrefix)
+		suite.TearDownTest()
+	})
+
+	suite.Run("expired intent", func() {
+		suite.SetupTest()
+		suite.bookingRepo.On("GetUserPendingIntent", suite.ctx, uint(7), uint(1)).Return(suite.pendingIntent(-time.Second), nil)
+
+		_, err := suite.service.RetryPayment(suite.ctx, 7, 1, "card_declined")
+
+		suite.assertAppError(err, "BAD_REQUEST", constants.ErrBookingExpired)
+		suite.TearDownTest()
+	})
+}
+
+func (suite *BookingServiceTestSuite) TestGetBookingIntentStatus_RemainingTime() {
+	suite.bookingRepo.On("GetBookingIntent", suite.ctx, uint(7), uint(1)).Return(suite.pendingIntent(90*time.Second), nil)
+
+	status, err := suite.service.GetBookingIntentStatus(suite.ctx, 7, 1)
+
+	suite.NoError(err)
+	suite.Equal(90, status.RemainingSeconds)
+	suite.Equal(suite.now.Add(90*time.Second), *status.ExpiresAt)
+}
+
+func (suite *BookingServiceTestSuite) TestInspectSeatLock() {
+	seat := suite.seat()
+	seat.IsLocked = true
+	seat.LockedBy = new(uint)
+	*seat.LockedBy = 1
+	suite.bookingRepo.On("GetSeat", suite.ctx, uint(5)).Return(seat, nil)
+	suite.seatLocks.On("IsLocked", suite.ctx, uint(3), uint(5)).Return(true, "1:7", nil)
+	suite.seatLocks.On("GetLockTTL", suite.ctx, uint(3), uint(5)).Return(90*time.Second, nil)
+	suite.bookingRepo.On("GetLatestSeatIntent", suite.ctx, uint(5)).Return(suite.pendingIntent(90*time.Second), nil)
+
+	inspection, err := suite.service.InspectSeatLock(suite.ctx, 5)
+
+	suite.NoError(err)
+	suite.True(inspection.Database.IsLocked)
+	suite.True(inspection.Redis.Reachable)
+	suite.True(inspection.Redis.IsLocked)
+	suite.Equal(uint(1), *inspection.Redis.UserID)
+	suite.Equal("7", inspection.Redis.IntentID)
+	suite.Equal(90, inspection.Redis.TTLSeconds)
+	suite.True(inspection.Intent.HoldsRedisLock)
+}
+
+func (suite *BookingServiceTestSuite) TestInspectSeatLock_DegradedMode() {
+	suite.bookingRepo.On("GetSeat", suite.ctx, uint(5)).Return(suite.seat(), nil)
+	suite.seatLocks.On("IsLocked", suite.ctx, uint(3), uint(5)).Return(false, "", redisconn.ErrUnavailable)
+	suite.bookingRepo.On("GetLatestSeatIntent", suite.ctx, uint(5)).
+		Return(nil, errors.NewNotFoundError("Booking intent not found", errors.ErrRecordNotFound))
+
+	inspection, err := suite.service.InspectSeatLock(suite.ctx, 5)
+
+	suite.NoError(err)
+	suite.False(inspection.Redis.Reachable)
+	suite.NotEmpty(inspection.Redis.Error)
+	suite.Nil(inspection.Intent)
+}
+
+func (suite *BookingServiceTestSuite) TestReleaseAbandonedIntents() {
+	suite.seatLocks.On("GetStaleHeartbeats", suite.ctx, suite.now.Add(-45*time.Second)).Return([]uint{7, 8}, nil)
+	suite.bookingRepo.On("ExpireBookingIntent", suite.ctx, uint(7)).Return(suite.pendingIntent(time.Minute), nil)
+	suite.seatLocks.On("UnlockSeat", suite.ctx, uint(3), uint(5), uint(1), "7").Return(nil)
+	suite.seatLocks.On("ClearHeartbeat", suite.ctx, uint(7)).Return(nil)
+	// Intent 8 was confirmed since its last heartbeat
+	suite.bookingRepo.On("ExpireBookingIntent", suite.ctx, uint(8)).Return(nil, nil)
+	suite.seatLocks.On("ClearHeartbeat", suite.ctx, uint(8)).Return(nil)
+
+	suite.NoError(suite.service.ReleaseAbandonedIntents(suite.ctx))
+	suite.Equal([]domain.Event{
+		domain.IntentExpired{IntentID: 7, UserID: 1, EventID: 3, SeatID: 5, Abandoned: true, OccurredAt: suite.now},
+		domain.SeatReleased{EventID: 3, SeatID: 5, UserID: 1, Reason: domain.ReleaseIntentAbandoned, OccurredAt: suite.now},
+	}, suite.published)
+}
+
+func (suite *BookingServiceTestSuite) TestReleaseAbandonedIntents_DegradedMode() {
+	suite.seatLocks.On("GetStaleHeartbeats", suite.ctx, mock.Anything).Return(nil, redisconn.ErrUnavailable)
+
+	suite.NoError(suite.service.ReleaseAbandonedIntents(suite.ctx))
+}
+
+func (suite *BookingServiceTestSuite) TestCleanupExpiredIntents() {
+	expired := []entities.BookingIntent{{ID: 7, UserID: 1, EventID: 3, SeatID: 5}}
+	pending := []entities.BookingIntent{
+		{ID: 8, UserID: 1, EventID: 3, SeatID: 6, LockExpiresAt: suite.now.Add(time.Minute)},
+		{ID: 9, UserID: 2, EventID: 3, SeatID: 7, LockExpiresAt: suite.now.Add(time.Minute)},
+	}
+
+	suite.bookingRepo.On("ExpireIntents", suite.ctx, suite.now).Return(expired, nil)
+	suite.seatLocks.On("UnlockSeat", suite.ctx, uint(3), uint(5), uint(1), "7").Return(nil)
+	suite.seatLocks.On("ClearHeartbeat", suite.ctx, uint(7)).Return(nil)
+	suite.bookingRepo.On("GetPendingIntents", suite.ctx, suite.now).Return(pending, nil)
+	suite.seatLocks.On("AlignLock", suite.ctx, uint(3), uint(6), uint(1), "8", suite.now.Add(time.Minute), time.Second).
+		Return(repository.LockRealigned, 3*time.Second, nil)
+	suite.seatLocks.On("AlignLock", suite.ctx, uint(3), uint(7), uint(2), "9", suite.now.Add(time.Minute), time.Second).
+		Return(repository.LockRestored, time.Duration(0), nil)
+
+	suite.NoError(suite.service.CleanupExpiredIntents(suite.ctx))
+	suite.Equal([]domain.Event{
+		domain.IntentExpired{IntentID: 7, UserID: 1, EventID: 3, SeatID: 5, OccurredAt: suite.now},
+		domain.SeatReleased{EventID: 3, SeatID: 5, UserID: 1, Reason: domain.ReleaseIntentExpired, OccurredAt: suite.now},
+	}, suite.published)
+}
+
+func (suite *BookingServiceTestSuite) TestPurgeEventIntents() {
+	cutoff := suite.now.Add(-30 * time.Minute)
+	expired := []entities.BookingIntent{
+		{ID: 7, UserID: 1, EventID: 3, SeatID: 5},
+		{ID: 8, UserID: 2, EventID: 3, SeatID: 6},
+	}
+
+	suite.bookingRepo.On("ExpireEventIntents", suite.ctx, uint(3), cutoff).Return(expired, nil)
+	suite.seatLocks.On("UnlockSeat", suite.ctx, uint(3), uint(5), uint(1), "7").Return(nil)
+	suite.seatLocks.On("UnlockSeat", suite.ctx, uint(3), uint(6), uint(2), "8").Return(redisconn.ErrUnavailable)
+	suite.seatLocks.On("ClearHeartbeat", suite.ctx, uint(7)).Return(nil)
+	suite.seatLocks.On("ClearHeartbeat", suite.ctx, uint(8)).Return(redisconn.ErrUnavailable)
+
+	result, err := suite.service.PurgeEventIntents(suite.ctx, 3, cutoff)
+
+	suite.NoError(err)
+	suite.Equal(&services.IntentPurgeResult{
+		EventID:        3,
+		CreatedBefore:  cutoff,
+		ExpiredIntents: 2,
+		ReleasedSeats:  2,
+		ReleasedLocks:  1,
+		FailedLocks:    1,
+	}, result)
+	suite.Len(suite.published, 4)
+}
+
+func (suite *BookingServiceTestSuite) TestCleanupExpiredIntents_StopsAligningInDegradedMode() {
+	pending := []entities.BookingIntent{
+		{ID: 8, UserID: 1, EventID: 3, SeatID: 6, LockExpiresAt: suite.now.Add(time.Minute)},
+		{ID: 9, UserID: 2, EventID: 3, SeatID: 7, LockExpiresAt: suite.now.Add(time.Minute)},
+	}
+
+	suite.bookingRepo.On("ExpireIntents", suite.ctx, suite.now).Return(nil, nil)
+	suite.bookingRepo.On("GetPendingIntents", suite.ctx, suite.now).Return(pending, nil)
+	suite.seatLocks.On("AlignLock", suite.ctx, uint(3), uint(6), uint(1), "8", suite.now.Add(time.Minute), time.Second).
+		Return(0, time.Duration(0), redisconn.ErrUnavailable).Once()
+
+	suite.NoError(suite.service.CleanupExpiredIntents(suite.ctx))
+}
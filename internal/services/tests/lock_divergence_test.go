@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	redisconn "api/internal/redis"
+	"api/internal/repository"
+	"api/internal/services"
+	"api/test/mocks"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type lockDivergenceFixture struct {
+	detector    *services.LockDivergenceDetector
+	bookingRepo *mocks.MockBookingRepository
+	seatLocks   *mocks.MockSeatLockRepository
+	redis       redis.UniversalClient
+	now         time.Time
+}
+
+// newLockDivergenceFixture builds a detector with a one minute grace period over a diverged
+// event: intent 7 has lost its Redis lock, intent 8's seat is locked for someone else, seat 20
+// is locked in Redis for a finished intent and seat 30 in the database without an intent
+func newLockDivergenceFixture(t *testing.T, policy string) *lockDivergenceFixture {
+	redisClient := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { redisClient.Close() })
+	f := &lockDivergenceFixture{
+		bookingRepo: &mocks.MockBookingRepository{},
+		seatLocks:   &mocks.MockSeatLockRepository{},
+		redis:       redisClient,
+		now:         time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	detector, err := services.NewLockDivergenceDetector(f.bookingRepo, f.seatLocks, redisconn.NewHealth(redisClient, time.Hour), policy, time.Minute)
+	require.NoError(t, err)
+	f.detector = detector.WithClock(func() time.Time { return f.now })
+
+	pending := []entities.BookingIntent{
+		{ID: 7, UserID: 1, EventID: 3, SeatID: 5, LockExpiresAt: f.now.Add(5 * time.Minute)},
+		{ID: 8, UserID: 2, EventID: 3, SeatID: 6, LockExpiresAt: f.now.Add(5 * time.Minute)},
+		{ID: 9, UserID: 2, EventID: 3, SeatID: 10, LockExpiresAt: f.now.Add(5 * time.Minute)},
+	}
+	f.bookingRepo.On("GetPendingIntents", mock.Anything, mock.Anything).Return(pending, nil)
+	f.bookingRepo.On("GetUpcomingEventIDs", mock.Anything, mock.Anything).Return([]uint{3}, nil)
+	f.seatLocks.On("ListLocks", mock.Anything, uint(3)).Return(map[uint]string{6: "4:12", 10: "2:9", 20: "3:11"}, nil)
+	f.bookingRepo.On("GetDatabaseLockedSeats", mock.Anything).
+		Return([]entities.Seat{{ID: 10, EventID: 3, IsLocked: true}, {ID: 30, EventID: 3, IsLocked: true}}, nil)
+	return f
+}
+
+func TestLockDivergenceDetector_RepairsAfterGracePeriod(t *testing.T) {
+	ctx := context.Background()
+	f := newLockDivergenceFixture(t, constants.LockDivergenceRepair)
+
+	require.NoError(t, f.detector.Check(ctx))
+	report := f.detector.LastReport()
+	assert.Equal(t, 4, report.InGrace)
+	assert.Empty(t, report.Divergences)
+
+	f.seatLocks.On("AlignLock", ctx, uint(3), uint(5), uint(1), "7", f.now.Add(5*time.Minute), time.Second).
+		Return(repository.LockRestored, time.Duration(0), nil)
+	f.seatLocks.On("UnlockSeat", ctx, uint(3), uint(20), uint(3), "11").Return(nil)
+	f.bookingRepo.On("ReleaseDatabaseLock", ctx, mock.MatchedBy(func(seat *entities.Seat) bool { return seat.ID == 30 })).
+		Return(true, nil)
+
+	f.now = f.now.Add(time.Minute)
+	require.NoError(t, f.detector.Check(ctx))
+	report = f.detector.LastReport()
+	assert.Equal(t, map[string]int{
+		services.DivergenceRedisLockMissing:     1,
+		services.DivergenceRedisLockConflict:    1,
+		services.DivergenceRedisLockOrphaned:    1,
+		services.DivergenceDatabaseLockOrphaned: 1,
+	}, report.Divergences)
+	// Which of two holders keeps the seat is left to a person
+	assert.Equal(t, map[string]int{
+		services.DivergenceRedisLockMissing:     1,
+		services.DivergenceRedisLockOrphaned:    1,
+		services.DivergenceDatabaseLockOrphaned: 1,
+	}, report.Repaired)
+	assert.Len(t, report.Seats, 4)
+	f.seatLocks.AssertExpectations(t)
+	f.bookingRepo.AssertExpectations(t)
+}
+
+func TestLockDivergenceDetector_ReportPolicyLeavesLocksAlone(t *testing.T) {
+	ctx := context.Background()
+	f := newLockDivergenceFixture(t, constants.LockDivergenceReport)
+
+	require.NoError(t, f.detector.Check(ctx))
+	f.now = f.now.Add(2 * time.Minute)
+	require.NoError(t, f.detector.Check(ctx))
+
+	report := f.detector.LastReport()
+	assert.Equal(t, 4, len(report.Seats))
+	assert.Empty(t, report.Repaired)
+	f.seatLocks.AssertNotCalled(t, "AlignLock", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	f.seatLocks.AssertNotCalled(t, "UnlockSeat", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	f.bookingRepo.AssertNotCalled(t, "ReleaseDatabaseLock", mock.Anything, mock.Anything)
+}
+
+func TestLockDivergenceDetector_SkipsWhileRedisIsUnavailable(t *testing.T) {
+	ctx := context.Background()
+	f := newLockDivergenceFixture(t, constants.LockDivergenceRepair)
+	require.Error(t, f.redis.Ping(ctx).Err())
+
+	require.NoError(t, f.detector.Check(ctx))
+	assert.NotEmpty(t, f.detector.LastReport().Skipped)
+	f.bookingRepo.AssertNotCalled(t, "GetPendingIntents", mock.Anything, mock.Anything)
+}
+
+func TestLockDivergenceDetector_RejectsUnknownPolicy(t *testing.T) {
+	_, err := services.NewLockDivergenceDetector(&mocks.MockBookingRepository{}, &mocks.MockSeatLockRepository{}, nil, "ignore", time.Minute)
+	assert.Error(t, err)
+}
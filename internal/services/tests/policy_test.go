@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/services"
+	"api/pkg/errors"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyAcceptanceRequiresCurrentVersions(t *testing.T) {
+	policies := services.NewPolicyService(nil, "2026-05", " 3 ")
+	assert.Equal(t, map[string]string{
+		constants.PolicyTermsOfService: "2026-05",
+		constants.PolicyPrivacy:        "3",
+	}, policies.CurrentVersions())
+
+	cases := []struct {
+		name    string
+		consent entities.PolicyConsent
+		message string
+	}{
+		{"accepted", entities.PolicyConsent{Accept: true, TermsVersion: "2026-05", PrivacyVersion: "3"}, ""},
+		{"not accepted", entities.PolicyConsent{TermsVersion: "2026-05", PrivacyVersion: "3"}, constants.ErrPoliciesNotAccepted},
+		{"outdated terms", entities.PolicyConsent{Accept: true, TermsVersion: "2026-01", PrivacyVersion: "3"}, constants.ErrPolicyVersionChanged},
+		{"privacy version missing", entities.PolicyConsent{Accept: true, TermsVersion: "2026-05"}, constants.ErrPolicyVersionChanged},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := policies.CheckAcceptance(tc.consent)
+			if tc.message == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var appErr *errors.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tc.message, appErr.Message)
+		})
+	}
+}
+
+func TestPoliciesWithoutVersionsArentRequired(t *testing.T) {
+	policies := services.NewPolicyService(nil, "", "")
+
+	assert.NoError(t, policies.CheckAcceptance(entities.PolicyConsent{}))
+	pending, err := policies.PendingPolicies(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
@@ -0,0 +1,243 @@
+package tests
+
+import (
+	"api/constants"
+	"api/internal/domain"
+	"api/internal/entities"
+	redisconn "api/internal/redis"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/test/mocks"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type queueFixture struct {
+	service   *services.QueueService
+	queueRepo *mocks.MockQueueRepository
+	eventRepo *mocks.MockEventRepository
+	redis     redis.UniversalClient
+}
+
+// newQueueFixture builds a QueueService over mocked repositories. Redis is reported healthy
+// until a command fails; the client points nowhere, so the fixture can degrade it.
+func newQueueFixture(t *testing.T) *queueFixture {
+	redisClient := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { redisClient.Close() })
+	f := &queueFixture{
+		queueRepo: &mocks.MockQueueRepository{},
+		eventRepo: &mocks.MockEventRepository{},
+		redis:     redisClient,
+	}
+	f.service = services.NewQueueService(f.queueRepo, f.eventRepo, redisconn.NewHealth(redisClient, time.Hour))
+	return f
+}
+
+// degrade makes the service's health report Redis unavailable
+func (f *queueFixture) degrade(t *testing.T) {
+	require.Error(t, f.redis.Ping(context.Background()).Err())
+}
+
+func highDemandEvent(id uint) *entities.Event {
+	return &entities.Event{ID: id, Status: constants.EventStatusActive, IsHighDemand: true, StartTime: time.Now().Add(24 * time.Hour)}
+}
+
+func notInQueue() error {
+	return errors.NewNotFoundError(constants.ErrNotInQueue, errors.ErrRecordNotFound)
+}
+
+func TestQueueServiceJoinQueue(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("joins and takes a position from Redis", func(t *testing.T) {
+		f := newQueueFixture(t)
+		f.eventRepo.On("GetEventByID", ctx, uint(3)).Return(highDemandEvent(3), nil)
+		f.queueRepo.On("GetEntry", ctx, uint(1), uint(3)).Return(nil, notInQueue()).Once()
+		f.queueRepo.On("CountWaiting", ctx, uint(3)).Return(int64(4), nil)
+		f.queueRepo.On("CreateEntry", ctx, mock.MatchedBy(func(entry *entities.EventQueue) bool {
+			return entry.Kind == constants.QueueKindOnSale && entry.Status == constants.QueueStatusWaiting
+		})).Return(true, nil)
+		f.queueRepo.On("Enqueue", ctx, uint(3), uint(1), mock.Anything).Return(nil)
+		f.queueRepo.On("Rank", ctx, uint(3), uint(1)).Return(int64(4), true, nil)
+
+		entry, err := f.service.JoinQueue(ctx, 1, 3)
+		require.NoError(t, err)
+		assert.Equal(t, 5, entry.QueuePosition)
+		f.queueRepo.AssertExpectations(t)
+	})
+
+	t.Run("joining again keeps the user's place", func(t *testing.T) {
+		f := newQueueFixture(t)
+		f.eventRepo.On("GetEventByID", ctx, uint(3)).Return(highDemandEvent(3), nil)
+		f.queueRepo.On("GetEntry", ctx, uint(1), uint(3)).
+			Return(&entities.EventQueue{ID: 8, EventID: 3, UserID: 1, Status: constants.QueueStatusWaiting}, nil)
+		f.queueRepo.On("Rank", ctx, uint(3), uint(1)).Return(int64(1), true, nil)
+
+		entry, err := f.service.JoinQueue(ctx, 1, 3)
+		require.NoError(t, err)
+		assert.Equal(t, uint(8), entry.ID)
+		assert.Equal(t, 2, entry.QueuePosition)
+		f.queueRepo.AssertNotCalled(t, "CreateEntry", mock.Anything, mock.Anything)
+	})
+
+	t.Run("events without a queue are refused", func(t *testing.T) {
+		f := newQueueFixture(t)
+		event := highDemandEvent(3)
+		event.IsHighDemand = false
+		f.eventRepo.On("GetEventByID", ctx, uint(3)).Return(event, nil)
+
+		_, err := f.service.JoinQueue(ctx, 1, 3)
+		if assert.IsType(t, &errors.AppError{}, err) {
+			assert.Equal(t, "BAD_REQUEST", err.(*errors.AppError).Type)
+			assert.Equal(t, constants.ErrNoQueue, err.(*errors.AppError).Message)
+		}
+	})
+
+	t.Run("degraded Redis leaves the queue to the database", func(t *testing.T) {
+		f := newQueueFixture(t)
+		f.degrade(t)
+		f.eventRepo.On("GetEventByID", ctx, uint(3)).Return(highDemandEvent(3), nil)
+		f.queueRepo.On("GetEntry", ctx, uint(1), uint(3)).Return(nil, notInQueue())
+		f.queueRepo.On("CountWaiting", ctx, uint(3)).Return(int64(0), nil)
+		f.queueRepo.On("CreateEntry", ctx, mock.Anything).Return(true, nil)
+		f.queueRepo.On("CountAhead", ctx, mock.Anything).Return(int64(0), nil)
+
+		entry, err := f.service.JoinQueue(ctx, 1, 3)
+		require.NoError(t, err)
+		assert.Equal(t, 1, entry.QueuePosition)
+		f.queueRepo.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestQueueServiceStatus(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("users missing from Redis are placed from the database", func(t *testing.T) {
+		f := newQueueFixture(t)
+		f.queueRepo.On("GetEntry", ctx, uint(1), uint(3)).
+			Return(&entities.EventQueue{EventID: 3, UserID: 1, Status: constants.QueueStatusWaiting}, nil)
+		f.queueRepo.On("Rank", ctx, uint(3), uint(1)).Return(int64(0), false, nil)
+		f.queueRepo.On("CountAhead", ctx, mock.Anything).Return(int64(6), nil)
+
+		entry, err := f.service.GetQueueStatus(ctx, 1, 3)
+		require.NoError(t, err)
+		assert.Equal(t, 7, entry.QueuePosition)
+	})
+
+	t.Run("admitted users have no position", func(t *testing.T) {
+		f := newQueueFixture(t)
+		f.queueRepo.On("GetEntry", ctx, uint(1), uint(3)).
+			Return(&entities.EventQueue{EventID: 3, UserID: 1, Status: constants.QueueStatusActive, QueuePosition: 12}, nil)
+
+		entry, err := f.service.GetQueueStatus(ctx, 1, 3)
+		require.NoError(t, err)
+		assert.Equal(t, 0, entry.QueuePosition)
+		f.queueRepo.AssertNotCalled(t, "Rank", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("length comes from the database while degraded", func(t *testing.T) {
+		f := newQueueFixture(t)
+		f.degrade(t)
+		f.queueRepo.On("CountWaiting", ctx, uint(3)).Return(int64(42), nil)
+
+		length, err := f.service.GetQueueLength(ctx, 3)
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), length)
+		f.queueRepo.AssertNotCalled(t, "Length", mock.Anything, mock.Anything)
+	})
+}
+
+func TestQueueServiceLeaveQueue(t *testing.T) {
+	ctx := context.Background()
+	f := newQueueFixture(t)
+	f.queueRepo.On("CancelEntry", ctx, uint(1), uint(3)).
+		Return(&entities.EventQueue{EventID: 3, UserID: 1, Status: constants.QueueStatusCancelled}, nil)
+	f.queueRepo.On("Dequeue", ctx, uint(3), []uint{1}).Return(nil)
+	f.queueRepo.On("CancelEntry", ctx, uint(2), uint(3)).Return(nil, notInQueue())
+
+	require.NoError(t, f.service.LeaveQueue(ctx, 1, 3))
+	err := f.service.LeaveQueue(ctx, 2, 3)
+	if assert.IsType(t, &errors.AppError{}, err) {
+		assert.Equal(t, "NOT_FOUND", err.(*errors.AppError).Type)
+	}
+	f.queueRepo.AssertExpectations(t)
+}
+
+func TestQueueServiceAdmitQueues(t *testing.T) {
+	ctx := context.Background()
+	f := newQueueFixture(t)
+	f.queueRepo.On("ExpireAdmissions", ctx, mock.Anything).Return(int64(2), nil)
+	f.queueRepo.On("GetEventsToAdmit", ctx, mock.Anything).Return([]uint{3, 4}, nil)
+	f.queueRepo.On("AdmitNext", ctx, uint(3), constants.QueueMaxActive, mock.Anything, mock.MatchedBy(func(expiresAt time.Time) bool {
+		return time.Until(expiresAt) > (constants.QueueActiveDuration-1)*time.Minute
+	})).Return([]entities.EventQueue{{EventID: 3, UserID: 1}, {EventID: 3, UserID: 2}}, nil)
+	f.queueRepo.On("AdmitNext", ctx, uint(4), constants.QueueMaxActive, mock.Anything, mock.Anything).
+		Return([]entities.EventQueue{}, nil)
+	f.queueRepo.On("Dequeue", ctx, uint(3), []uint{1, 2}).Return(nil)
+
+	require.NoError(t, f.service.AdmitQueues(ctx))
+	f.queueRepo.AssertExpectations(t)
+	f.queueRepo.AssertNumberOfCalls(t, "Dequeue", 1)
+}
+
+func TestQueueServiceBookingConfirmed(t *testing.T) {
+	ctx := context.Background()
+	f := newQueueFixture(t)
+	f.queueRepo.On("CompleteEntry", ctx, uint(1), uint(3)).
+		Return(&entities.EventQueue{EventID: 3, UserID: 1, Status: constants.QueueStatusCompleted}, nil)
+	f.queueRepo.On("Dequeue", ctx, uint(3), []uint{1}).Return(nil)
+	// Most bookings are for events the user never queued for
+	f.queueRepo.On("CompleteEntry", ctx, uint(2), uint(3)).Return(nil, notInQueue())
+
+	events := domain.NewDispatcher()
+	f.service.Subscribe(events)
+	events.Publish(ctx,
+		domain.BookingConfirmed{BookingID: 10, UserID: 1, EventID: 3},
+		domain.BookingConfirmed{BookingID: 11, UserID: 2, EventID: 3})
+
+	f.queueRepo.AssertExpectations(t)
+}
+
+func TestQueueServiceQueueToken(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("admitted users hold a token during an on-sale", func(t *testing.T) {
+		f := newQueueFixture(t)
+		f.queueRepo.On("GetAdmittedUsers", ctx, mock.Anything).Return([]uint{1}, nil).Once()
+
+		onSale, admitted := f.service.QueueToken(ctx, 1)
+		assert.True(t, onSale)
+		assert.True(t, admitted)
+		onSale, admitted = f.service.QueueToken(ctx, 2)
+		assert.True(t, onSale)
+		assert.False(t, admitted)
+		// The second lookup is answered from the snapshot
+		f.queueRepo.AssertNumberOfCalls(t, "GetAdmittedUsers", 1)
+	})
+
+	t.Run("queues waiting to admit count as an on-sale", func(t *testing.T) {
+		f := newQueueFixture(t)
+		f.queueRepo.On("GetAdmittedUsers", ctx, mock.Anything).Return([]uint{}, nil)
+		f.queueRepo.On("GetEventsToAdmit", ctx, mock.Anything).Return([]uint{3}, nil)
+
+		onSale, admitted := f.service.QueueToken(ctx, 1)
+		assert.True(t, onSale)
+		assert.False(t, admitted)
+	})
+
+	t.Run("default limits apply when admissions can't be loaded", func(t *testing.T) {
+		f := newQueueFixture(t)
+		f.queueRepo.On("GetAdmittedUsers", ctx, mock.Anything).
+			Return(nil, errors.NewInternalError("Failed to fetch admitted users", nil))
+
+		onSale, admitted := f.service.QueueToken(ctx, 1)
+		assert.False(t, onSale)
+		assert.False(t, admitted)
+	})
+}
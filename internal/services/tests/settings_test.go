@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"api/constants"
+	"api/internal/services"
+	"api/pkg/errors"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettingUpdatesAreValidatedBeforeSaving(t *testing.T) {
+	settings := services.NewSettingsService(nil, time.Minute, services.RuntimeSettings(services.SettingDefaults{
+		LockDuration: 8 * time.Minute,
+		WaitlistHold: 10 * time.Minute,
+		FeePercent:   2.5,
+		FeePerTicket: 0.5,
+	}))
+
+	cases := []struct {
+		name    string
+		key     string
+		value   string
+		errType string
+	}{
+		{"unknown setting", "booking.unknown", "5m", "NOT_FOUND"},
+		{"duration not parsed", constants.SettingLockDuration, "five minutes", "BAD_REQUEST"},
+		{"duration too short", constants.SettingLockDuration, "30s", "BAD_REQUEST"},
+		{"duration too long", constants.SettingWaitlistHold, "25h", "BAD_REQUEST"},
+		{"number not parsed", constants.SettingFeePercent, "2,5", "BAD_REQUEST"},
+		{"number not finite", constants.SettingFeePercent, "NaN", "BAD_REQUEST"},
+		{"negative fee", constants.SettingFeePerTicket, "-1", "BAD_REQUEST"},
+		{"percent above 100", constants.SettingFeePercent, "101", "BAD_REQUEST"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := settings.UpdateSetting(context.Background(), tc.key, tc.value, 1)
+			var appErr *errors.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tc.errType, appErr.Type)
+		})
+	}
+}
+
+func TestNilSettingsFallBackToDefaults(t *testing.T) {
+	var settings *services.SettingsService
+
+	assert.Equal(t, 8*time.Minute, settings.Duration(context.Background(), constants.SettingLockDuration, 8*time.Minute))
+	assert.Equal(t, 2.5, settings.Float(context.Background(), constants.SettingFeePercent, 2.5))
+}
@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"api/test/mocks"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangePasswordDropsCachedTokenVersion(t *testing.T) {
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	service := services.NewUserService(userRepo).WithTokenVersionCache(time.Minute)
+
+	userRepo.On("GetTokenVersion", ctx, uint(7)).Return(1, nil).Once()
+	userRepo.On("ChangePassword", ctx, uint(7), "old-secret", "new-secret").Return(&entities.User{ID: 7, TokenVersion: 2}, nil)
+	userRepo.On("GetTokenVersion", ctx, uint(7)).Return(2, nil).Once()
+
+	for i := 0; i < 3; i++ {
+		version, err := service.TokenVersion(ctx, 7)
+		require.NoError(t, err)
+		assert.Equal(t, 1, version)
+	}
+
+	user, err := service.ChangePassword(ctx, 7, "old-secret", "new-secret")
+	require.NoError(t, err)
+	assert.Equal(t, 2, user.TokenVersion)
+
+	version, err := service.TokenVersion(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, 2, version)
+	userRepo.AssertExpectations(t)
+}
+
+func TestValidateTokenChecksIssuerAndAudience(t *testing.T) {
+	service := services.NewJWTService("jwt-secret", "booking-api", "booking-clients", time.Hour)
+
+	token, err := service.GenerateToken(7, false, nil, 3)
+	require.NoError(t, err)
+	claims, err := service.GetClaimsFromToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, float64(3), claims["ver"])
+	assert.Equal(t, "booking-api", claims["iss"])
+
+	otherIssuer, err := services.NewJWTService("jwt-secret", "other-api", "booking-clients", time.Hour).GenerateToken(7, false, nil, 3)
+	require.NoError(t, err)
+	_, err = service.ValidateToken(otherIssuer)
+	assert.Error(t, err)
+
+	otherAudience, err := services.NewJWTService("jwt-secret", "booking-api", "other-clients", time.Hour).GenerateToken(7, false, nil, 3)
+	require.NoError(t, err)
+	_, err = service.ValidateToken(otherAudience)
+	assert.Error(t, err)
+
+	expired, err := services.NewJWTService("jwt-secret", "booking-api", "booking-clients", time.Nanosecond).GenerateToken(7, false, nil, 3)
+	require.NoError(t, err)
+	time.Sleep(1100 * time.Millisecond)
+	_, err = service.ValidateToken(expired)
+	assert.Error(t, err)
+}
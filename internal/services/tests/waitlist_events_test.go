@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"api/constants"
+	"api/internal/domain"
+	"api/internal/entities"
+	redisconn "api/internal/redis"
+	"api/internal/repository"
+	"api/internal/services"
+	"api/pkg/errors"
+	"api/test/mocks"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestWaitlistFollowsBookingEvents checks that a released seat goes to the first user still
+// waiting, who is notified, unless it is an oversell place, and that booking takes them off the
+// waitlist. The waitlist's
+// database mirror needs a scratch Postgres database in TEST_DATABASE_URL.
+func TestWaitlistFollowsBookingEvents(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger:                                   logger.Discard,
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&entities.EventQueue{}))
+
+	ctx := context.Background()
+	eventID := uint(time.Now().UnixNano() % 1_000_000_000)
+	joined := time.Now().Add(-time.Hour)
+	for i, userID := range []uint{1, 2} {
+		require.NoError(t, db.Create(&entities.EventQueue{EventID: eventID, UserID: userID, QueuePosition: i + 1,
+			Status: "waiting", JoinedAt: joined.Add(time.Duration(i) * time.Second)}).Error)
+	}
+
+	// Redis is reported healthy until a command fails; the repository is mocked
+	redisClient := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { redisClient.Close() })
+	waitlistRepo := &mocks.MockWaitlistRepository{}
+	waitlistRepo.On("NotifyWaitlistUsers", ctx, eventID, 1).
+		Return([]*repository.WaitlistEntry{{UserID: 1, EventID: eventID, JoinedAt: joined, Position: 1}}, nil).Once()
+	waitlistRepo.On("RemoveFromWaitlist", ctx, uint(1), eventID).Return(nil)
+	waitlistRepo.On("RemoveFromWaitlist", ctx, uint(3), eventID).Return(errors.ErrNotInWaitlist)
+	eventRepo := &mocks.MockEventRepository{}
+	eventRepo.On("GetEventByID", ctx, eventID).Return(&entities.Event{ID: eventID, Name: "Sold Out Show"}, nil)
+	userRepo := &mocks.MockUserRepository{}
+	userRepo.On("GetByID", ctx, uint(1)).Return(&entities.User{ID: 1, Email: "first@example.com"}, nil)
+	notifier := &recordingNotifier{}
+
+	events := domain.NewDispatcher()
+	services.NewWaitlistService(waitlistRepo, eventRepo, userRepo, db, redisconn.NewHealth(redisClient, time.Hour), notifier).
+		Subscribe(events)
+
+	status := func(userID uint) string {
+		var entry entities.EventQueue
+		require.NoError(t, db.Where("event_id = ? AND user_id = ?", eventID, userID).First(&entry).Error)
+		return entry.Status
+	}
+
+	// Oversell places are left to the oversell promotions job
+	events.Publish(ctx, domain.SeatReleased{EventID: eventID, SeatID: 4, UserID: 8, Reason: domain.ReleaseBookingCancelled, Oversell: true})
+	assert.Equal(t, "waiting", status(1))
+
+	events.Publish(ctx, domain.SeatReleased{EventID: eventID, SeatID: 5, UserID: 9, Reason: domain.ReleaseBookingCancelled})
+
+	assert.Equal(t, "active", status(1))
+	assert.Equal(t, "waiting", status(2))
+	if assert.Len(t, notifier.sent, 1) {
+		assert.Equal(t, constants.NotificationTypeWaitlistSeatAvailable, notifier.sent[0].Type)
+		assert.Equal(t, "first@example.com", notifier.sent[0].Recipient)
+	}
+
+	// The notified user books; bookings by users who never waited change nothing
+	events.Publish(ctx,
+		domain.BookingConfirmed{BookingID: 11, UserID: 1, EventID: eventID, SeatID: 5},
+		domain.BookingConfirmed{BookingID: 12, UserID: 3, EventID: eventID, SeatID: 6})
+
+	assert.Equal(t, "completed", status(1))
+	assert.Equal(t, "waiting", status(2))
+	waitlistRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+// TestBookFromWaitlistPutsBackUsersItCannotBook checks that users are taken off the waitlist to
+// be booked and put back in their place when their booking fails. A conflict moves on to the
+// next user, any other failure ends the run.
+func TestBookFromWaitlistPutsBackUsersItCannotBook(t *testing.T) {
+	ctx := context.Background()
+	eventID := uint(12)
+	first := &repository.WaitlistEntry{UserID: 1, EventID: eventID, Position: 1}
+	second := &repository.WaitlistEntry{UserID: 2, EventID: eventID, Position: 1}
+	third := &repository.WaitlistEntry{UserID: 3, EventID: eventID, Position: 1}
+
+	// Redis is reported healthy until a command fails; the repository is mocked
+	redisClient := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { redisClient.Close() })
+	waitlistRepo := &mocks.MockWaitlistRepository{}
+	waitlistRepo.On("TakeWaitlistUser", ctx, eventID, []uint{}).Return(first, nil).Once()
+	waitlistRepo.On("ReturnToWaitlist", ctx, first).Return(nil).Once()
+	waitlistRepo.On("TakeWaitlistUser", ctx, eventID, []uint{1}).Return(second, nil).Once()
+	waitlistRepo.On("TakeWaitlistUser", ctx, eventID, []uint{}).Return(third, nil).Once()
+	waitlistRepo.On("ReturnToWaitlist", ctx, third).Return(nil).Once()
+	service := services.NewWaitlistService(waitlistRepo, nil, nil, nil, redisconn.NewHealth(redisClient, time.Hour), nil)
+
+	failure := errors.NewInternalError("Failed to create booking", nil)
+	var booked []uint
+	book := func(ctx context.Context, userID uint) error {
+		switch userID {
+		case 1:
+			return errors.NewConflictError("User is already booked for this event", nil)
+		case 3:
+			return failure
+		}
+		booked = append(booked, userID)
+		return nil
+	}
+
+	count, err := service.BookFromWaitlist(ctx, eventID, 1, book)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []uint{2}, booked)
+
+	count, err = service.BookFromWaitlist(ctx, eventID, 2, book)
+	assert.Equal(t, failure, err)
+	assert.Equal(t, 0, count)
+	waitlistRepo.AssertExpectations(t)
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/repository"
+	"api/internal/tickets"
+	"api/pkg/errors"
+	"context"
+	"time"
+)
+
+// TicketKey is a public key door scanners download to validate ticket tokens offline
+type TicketKey struct {
+	KeyID     string
+	Algorithm string
+	PublicKey string
+}
+
+// OfflineCheckIn is a ticket a scanner admitted while it couldn't reach the API
+type OfflineCheckIn struct {
+	Token     string
+	ScannedAt time.Time
+}
+
+// OfflineCheckInResult tells a scanner what became of one offline scan
+type OfflineCheckInResult struct {
+	BookingID   uint
+	Outcome     string
+	Reason      string
+	CheckedInAt *time.Time
+}
+
+// TicketService issues the signed tokens printed as ticket QR codes and takes in the check-ins
+// scanners made offline with them
+type TicketService struct {
+	signer         *tickets.Signer
+	attendanceRepo *repository.AttendanceRepository
+	validBefore    time.Duration
+}
+
+// NewTicketService signs tokens that admit entry from validBefore ahead of an event's start
+// until it ends
+func NewTicketService(signer *tickets.Signer, attendanceRepo *repository.AttendanceRepository, validBefore time.Duration) *TicketService {
+	return &TicketService{signer: signer, attendanceRepo: attendanceRepo, validBefore: validBefore}
+}
+
+// Token returns the QR token of a booking, which must be loaded with its event
+func (s *TicketService) Token(booking *entities.Booking) (string, tickets.Claims) {
+	claims := tickets.Claims{
+		BookingID:  booking.ID,
+		EventID:    booking.EventID,
+		SeatID:     booking.SeatID,
+		ValidFrom:  booking.Event.StartTime.Add(-s.validBefore),
+		ValidUntil: booking.Event.EndTime,
+	}
+	return s.signer.Sign(claims), claims
+}
+
+// Keys lists the public keys tokens are currently signed with
+func (s *TicketService) Keys() []TicketKey {
+	return []TicketKey{{KeyID: s.signer.KeyID(), Algorithm: tickets.Algorithm, PublicKey: s.signer.PublicKey()}}
+}
+
+// SyncOfflineCheckIns records the scans a scanner made offline at an event's doors, attributed
+// to the registered scanner device when deviceID is set. Every scan gets an outcome: checked
+// in, a duplicate of an earlier scan of the same ticket, or rejected with a reason for staff to
+// follow up.
+func (s *TicketService) SyncOfflineCheckIns(ctx context.Context, eventID uint, deviceID *uint, checkIns []OfflineCheckIn) ([]OfflineCheckInResult, error) {
+	results := make([]OfflineCheckInResult, 0, len(checkIns))
+	for _, checkIn := range checkIns {
+		result, err := s.syncCheckIn(ctx, eventID, deviceID, checkIn)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (s *TicketService) syncCheckIn(ctx context.Context, eventID uint, deviceID *uint, checkIn OfflineCheckIn) (OfflineCheckInResult, error) {
+	claims, err := s.signer.Verify(checkIn.Token)
+	if err != nil {
+		return OfflineCheckInResult{Outcome: constants.CheckInSyncRejected, Reason: err.Error()}, nil
+	}
+
+	result := OfflineCheckInResult{BookingID: claims.BookingID, Outcome: constants.CheckInSyncRejected}
+	if claims.EventID != eventID {
+		result.Reason = "ticket is for another event"
+		return result, nil
+	}
+	if !claims.ValidAt(checkIn.ScannedAt) {
+		result.Reason = "ticket was scanned outside its validity window"
+		return result, nil
+	}
+
+	booking, recorded, err := s.attendanceRepo.RecordOfflineCheckIn(ctx, eventID, claims.BookingID, checkIn.ScannedAt, deviceID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok && appErr.Type != "INTERNAL_ERROR" {
+			result.Reason = appErr.Message
+			return result, nil
+		}
+		return OfflineCheckInResult{}, err
+	}
+
+	result.CheckedInAt = booking.CheckedInAt
+	result.Outcome = constants.CheckInSyncCheckedIn
+	if !recorded {
+		result.Outcome = constants.CheckInSyncDuplicate
+	}
+	return result, nil
+}
@@ -4,17 +4,40 @@ import (
 	"api/internal/entities"
 	"api/internal/repository"
 	"context"
+	"sync"
+	"time"
 )
 
+// tokenVersionCacheSize is how many cached token versions trigger dropping the stale ones
+const tokenVersionCacheSize = 10000
+
 type UserService struct {
-	userRepo *repository.UserRepository
+	userRepo repository.UserRepository
+
+	// Token versions are checked on every authenticated request, so they are kept in memory
+	// for versionTTL; 0 reads them every time
+	versionTTL time.Duration
+	versionsMu sync.Mutex
+	versions   map[uint]cachedTokenVersion
+}
+
+type cachedTokenVersion struct {
+	version  int
+	loadedAt time.Time
 }
 
 // Ensure UserService implements UserServiceInterface
 var _ UserServiceInterface = (*UserService)(nil)
 
-func NewUserService(userRepo *repository.UserRepository) *UserService {
-	return &UserService{userRepo: userRepo}
+func NewUserService(userRepo repository.UserRepository) *UserService {
+	return &UserService{userRepo: userRepo, versions: make(map[uint]cachedTokenVersion)}
+}
+
+// WithTokenVersionCache keeps token versions in memory for ttl. Credentials changed through
+// another instance revoke tokens on this one once the cached version expires.
+func (s *UserService) WithTokenVersionCache(ttl time.Duration) *UserService {
+	s.versionTTL = ttl
+	return s
 }
 
 func (s *UserService) Register(ctx context.Context, email, password, firstName, lastName, phone string, isAdmin bool) (*entities.User, error) {
@@ -28,3 +51,61 @@ func (s *UserService) Login(ctx context.Context, email, password string) (*entit
 func (s *UserService) GetByID(ctx context.Context, userID uint) (*entities.User, error) {
 	return s.userRepo.GetByID(ctx, userID)
 }
+
+func (s *UserService) SetMembershipTier(ctx context.Context, userID uint, tier string) (*entities.User, error) {
+	return s.userRepo.SetMembershipTier(ctx, userID, tier)
+}
+
+// ChangePassword replaces the user's password, revoking every token issued before. The
+// returned user carries the new token version.
+func (s *UserService) ChangePassword(ctx context.Context, userID uint, current, password string) (*entities.User, error) {
+	user, err := s.userRepo.ChangePassword(ctx, userID, current, password)
+	if err != nil {
+		return nil, err
+	}
+	s.ForgetTokenVersion(userID)
+	return user, nil
+}
+
+// ForgetTokenVersion drops the user's cached token version after it was bumped, so this
+// instance rejects the revoked tokens right away
+func (s *UserService) ForgetTokenVersion(userID uint) {
+	s.versionsMu.Lock()
+	delete(s.versions, userID)
+	s.versionsMu.Unlock()
+}
+
+// TokenVersion returns the version the user's tokens must carry to be accepted
+func (s *UserService) TokenVersion(ctx context.Context, userID uint) (int, error) {
+	if s.versionTTL > 0 {
+		s.versionsMu.Lock()
+		cached, ok := s.versions[userID]
+		s.versionsMu.Unlock()
+		if ok && time.Since(cached.loadedAt) < s.versionTTL {
+			return cached.version, nil
+		}
+	}
+
+	version, err := s.userRepo.GetTokenVersion(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if s.versionTTL > 0 {
+		s.cacheTokenVersion(userID, version)
+	}
+	return version, nil
+}
+
+func (s *UserService) cacheTokenVersion(userID uint, version int) {
+	s.versionsMu.Lock()
+	defer s.versionsMu.Unlock()
+	now := time.Now()
+	if len(s.versions) >= tokenVersionCacheSize {
+		for id, cached := range s.versions {
+			if now.Sub(cached.loadedAt) >= s.versionTTL {
+				delete(s.versions, id)
+			}
+		}
+	}
+	s.versions[userID] = cachedTokenVersion{version: version, loadedAt: now}
+}
@@ -7,18 +7,18 @@ import (
 )
 
 type VenueService struct {
-	venueRepo *repository.VenueRepository
+	venueRepo repository.VenueRepository
 }
 
 // Ensure VenueService implements VenueServiceInterface
 var _ VenueServiceInterface = (*VenueService)(nil)
 
-func NewVenueService(venueRepo *repository.VenueRepository) *VenueService {
+func NewVenueService(venueRepo repository.VenueRepository) *VenueService {
 	return &VenueService{venueRepo: venueRepo}
 }
 
-func (s *VenueService) GetVenues(ctx context.Context, limit, offset int, city string) ([]entities.Venue, int64, error) {
-	return s.venueRepo.GetVenues(ctx, limit, offset, city)
+func (s *VenueService) GetVenues(ctx context.Context, limit, offset int, city string, metadata map[string]string) ([]entities.Venue, int64, error) {
+	return s.venueRepo.GetVenues(ctx, limit, offset, city, metadata)
 }
 
 func (s *VenueService) GetVenueByID(ctx context.Context, venueID uint) (*entities.Venue, error) {
@@ -1,8 +1,14 @@
 package services
 
 import (
+	"api/constants"
+	"api/internal/domain"
 	"api/internal/entities"
+	"api/internal/notifications"
+	redisconn "api/internal/redis"
 	"api/internal/repository"
+	"api/pkg/errors"
+	logger "api/pkg/logging"
 	"context"
 	"fmt"
 	"time"
@@ -11,19 +17,87 @@ import (
 )
 
 type WaitlistService struct {
-	waitlistRepo *repository.WaitlistRepository
-	eventRepo    *repository.EventRepository
+	waitlistRepo repository.WaitlistRepository
+	eventRepo    repository.EventRepository
+	userRepo     repository.UserRepository
 	db           *gorm.DB
+	health       *redisconn.Health
+	notifier     notifications.Notifier
+	settings     *SettingsService
 }
 
-func NewWaitlistService(waitlistRepo *repository.WaitlistRepository, eventRepo *repository.EventRepository, db *gorm.DB) *WaitlistService {
+// NewWaitlistService queues users in Redis and mirrors the queue to the database, which
+// serves it alone while health reports Redis unavailable
+func NewWaitlistService(waitlistRepo repository.WaitlistRepository, eventRepo repository.EventRepository, userRepo repository.UserRepository, db *gorm.DB, health *redisconn.Health, notifier notifications.Notifier) *WaitlistService {
 	return &WaitlistService{
 		waitlistRepo: waitlistRepo,
 		eventRepo:    eventRepo,
+		userRepo:     userRepo,
 		db:           db,
+		health:       health,
+		notifier:     notifier,
 	}
 }
 
+// WithSettings reads the promotion hold from the runtime settings, falling back to the default
+// while it isn't overridden
+func (s *WaitlistService) WithSettings(settings *SettingsService) *WaitlistService {
+	s.settings = settings
+	return s
+}
+
+// Subscribe follows the booking workflow: every seat it releases goes to the next user in
+// line, and users who book leave the waitlist
+func (s *WaitlistService) Subscribe(events *domain.Dispatcher) {
+	domain.Subscribe(events, "waitlist", s.seatReleased)
+	domain.Subscribe(events, "waitlist", func(ctx context.Context, event domain.BookingConfirmed) error {
+		return s.RemoveUserFromWaitlistAfterBooking(ctx, event.UserID, event.EventID)
+	})
+}
+
+// seatReleased offers a released seat to the next user waiting for the event and notifies them
+func (s *WaitlistService) seatReleased(ctx context.Context, event domain.SeatReleased) error {
+	// Seats of no-shows are only sold at the door, online sales have closed
+	if event.Reason == domain.ReleaseNoShow {
+		return nil
+	}
+	// Oversell places are handed to the waitlist by the oversell promotions job
+	if event.Oversell {
+		return nil
+	}
+	notified, err := s.ProcessSeatAvailability(ctx, event.EventID, 1)
+	if err != nil {
+		return err
+	}
+	for _, entry := range notified {
+		if err := s.notifySeatAvailable(ctx, entry); err != nil {
+			logger.Warnf("Failed to notify user %d of a seat for event %d: %v", entry.UserID, entry.EventID, err)
+		}
+	}
+	return nil
+}
+
+// notifySeatAvailable tells a waitlisted user a seat is theirs to book for a while
+func (s *WaitlistService) notifySeatAvailable(ctx context.Context, entry *WaitlistEntry) error {
+	user, err := s.userRepo.GetByID(ctx, entry.UserID)
+	if err != nil {
+		return err
+	}
+	event, err := s.eventRepo.GetEventByID(ctx, entry.EventID)
+	if err != nil {
+		return err
+	}
+	return s.notifier.Send(ctx, notifications.Notification{
+		Type:      constants.NotificationTypeWaitlistSeatAvailable,
+		UserID:    user.ID,
+		Recipient: user.Email,
+		EventID:   event.ID,
+		Subject:   fmt.Sprintf("A seat is available for %s", event.Name),
+		Message: fmt.Sprintf("A seat opened up for %s. Book it within %d minutes, before it goes to the next person in line.",
+			event.Name, int(s.promotionHold(ctx)/time.Minute)),
+	})
+}
+
 // JoinWaitlist adds a user to the event waitlist if the event is full
 func (s *WaitlistService) JoinWaitlist(ctx context.Context, userID, eventID uint) (*WaitlistEntry, error) {
 	// First check if the event exists and is active
@@ -41,9 +115,22 @@ func (s *WaitlistService) JoinWaitlist(ctx context.Context, userID, eventID uint
 		return nil, fmt.Errorf("seats are still available for this event, please book directly instead of joining waitlist")
 	}
 
+	// Queue the user by the event's priority tiers, e.g. members ahead of general
+	tier, priority, err := s.tierFor(ctx, event, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.health.Degraded() {
+		return s.joinWaitlistDB(ctx, userID, event, tier)
+	}
+
 	// Join the waitlist
-	repoEntry, err := s.waitlistRepo.JoinWaitlist(ctx, userID, eventID)
+	repoEntry, err := s.waitlistRepo.JoinWaitlist(ctx, userID, eventID, tier, priority, event.WaitlistCap)
 	if err != nil {
+		if err == errors.ErrWaitlistFull {
+			return nil, errors.NewConflictError(constants.ErrWaitlistFull, err)
+		}
 		return nil, fmt.Errorf("failed to join waitlist: %w", err)
 	}
 
@@ -54,12 +141,14 @@ func (s *WaitlistService) JoinWaitlist(ctx context.Context, userID, eventID uint
 		JoinedAt:   repoEntry.JoinedAt,
 		Position:   repoEntry.Position,
 		NotifiedAt: repoEntry.NotifiedAt,
+		Tier:       repoEntry.Tier,
 	}
 
 	// Also store in database for persistence
 	dbEntry := &entities.EventQueue{
 		EventID:       eventID,
 		UserID:        userID,
+		Kind:          constants.QueueKindWaitlist,
 		QueuePosition: repoEntry.Position,
 		Status:        "waiting",
 		JoinedAt:      repoEntry.JoinedAt,
@@ -76,6 +165,10 @@ func (s *WaitlistService) JoinWaitlist(ctx context.Context, userID, eventID uint
 
 // GetWaitlistPosition returns the current position of a user in the waitlist
 func (s *WaitlistService) GetWaitlistPosition(ctx context.Context, userID, eventID uint) (*WaitlistEntry, error) {
+	if s.health.Degraded() {
+		return s.waitlistPositionDB(ctx, userID, eventID)
+	}
+
 	repoEntry, err := s.waitlistRepo.GetWaitlistPosition(ctx, userID, eventID)
 	if err != nil {
 		return nil, err
@@ -88,6 +181,7 @@ func (s *WaitlistService) GetWaitlistPosition(ctx context.Context, userID, event
 		JoinedAt:   repoEntry.JoinedAt,
 		Position:   repoEntry.Position,
 		NotifiedAt: repoEntry.NotifiedAt,
+		Tier:       repoEntry.Tier,
 	}
 
 	return entry, nil
@@ -95,15 +189,16 @@ func (s *WaitlistService) GetWaitlistPosition(ctx context.Context, userID, event
 
 // LeaveWaitlist removes a user from the waitlist
 func (s *WaitlistService) LeaveWaitlist(ctx context.Context, userID, eventID uint) error {
-	// Remove from Redis
-	err := s.waitlistRepo.RemoveFromWaitlist(ctx, userID, eventID)
-	if err != nil {
-		return fmt.Errorf("failed to remove from Redis waitlist: %w", err)
+	// Remove from Redis; while degraded the entry is removed there on recovery
+	if !s.health.Degraded() {
+		if err := s.waitlistRepo.RemoveFromWaitlist(ctx, userID, eventID); err != nil && !redisconn.IsUnavailable(err) {
+			return fmt.Errorf("failed to remove from Redis waitlist: %w", err)
+		}
 	}
 
 	// Update database entry status
 	result := s.db.WithContext(ctx).
-		Model(&entities.EventQueue{}).
+		Scopes(waitlistEntries).
 		Where("user_id = ? AND event_id = ? AND status = ?", userID, eventID, "waiting").
 		Update("status", "cancelled")
 
@@ -116,35 +211,50 @@ func (s *WaitlistService) LeaveWaitlist(ctx context.Context, userID, eventID uin
 
 // GetWaitlistSize returns the number of people waiting for an event
 func (s *WaitlistService) GetWaitlistSize(ctx context.Context, eventID uint) (int, error) {
+	if s.health.Degraded() {
+		return s.waitlistSizeDB(ctx, eventID)
+	}
 	return s.waitlistRepo.GetWaitlistSize(ctx, eventID)
 }
 
-// ProcessSeatAvailability marks seats as available for waitlisted users
+// waitlistNotificationTTL is how long a notified user has to book before losing their place,
+// unless overridden by the promotion hold setting
+const waitlistNotificationTTL = constants.WaitlistPromotionHold * time.Minute
+
+// promotionHold returns how long a notified user currently has to book
+func (s *WaitlistService) promotionHold(ctx context.Context) time.Duration {
+	return s.settings.Duration(ctx, constants.SettingWaitlistHold, waitlistNotificationTTL)
+}
+
+// ProcessSeatAvailability marks the first availableSeats users still waiting as notified and
+// returns them
 func (s *WaitlistService) ProcessSeatAvailability(ctx context.Context, eventID uint, availableSeats int) ([]*WaitlistEntry, error) {
 	if availableSeats <= 0 {
 		return nil, nil
 	}
 
-	// Mark the first N users in the waitlist as having seats available
-	// They can check their status and book
+	// Mark the first N users in the waitlist as having seats available; the queue is
+	// already ordered by tier then join time. They can check their status and book
 	availableUsers := make([]*WaitlistEntry, 0)
-	
-	for i := 0; i < availableSeats; i++ {
-		// Get the next user in queue
-		nextUser, err := s.waitlistRepo.GetNextInWaitlist(ctx, eventID)
-		if err != nil {
-			break // No more users in queue
-		}
-		if nextUser == nil {
-			break // Empty queue
-		}
 
+	var nextUsers []*repository.WaitlistEntry
+	var err error
+	if s.health.Degraded() {
+		nextUsers, err = s.waitlistHeadDB(ctx, eventID, availableSeats)
+	} else {
+		nextUsers, err = s.waitlistRepo.NotifyWaitlistUsers(ctx, eventID, availableSeats)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get waitlist: %w", err)
+	}
+
+	for _, nextUser := range nextUsers {
 		// Update database entry to mark as active with expiration
 		now := time.Now()
-		expiresAt := now.Add(10 * time.Minute) // Give users 10 minutes to book
+		expiresAt := now.Add(s.promotionHold(ctx))
 
 		err = s.db.WithContext(ctx).
-			Model(&entities.EventQueue{}).
+			Scopes(waitlistEntries).
 			Where("user_id = ? AND event_id = ? AND status = ?", nextUser.UserID, eventID, "waiting").
 			Updates(map[string]interface{}{
 				"status":     "active",
@@ -153,16 +263,18 @@ func (s *WaitlistService) ProcessSeatAvailability(ctx context.Context, eventID u
 			}).Error
 
 		if err != nil {
-			fmt.Printf("Failed to update database for user %d: %v\n", nextUser.UserID, err)
+			logger.Warnf("Failed to update database for user %d: %v", nextUser.UserID, err)
 			continue
 		}
 
 		// Convert to service WaitlistEntry
 		serviceEntry := &WaitlistEntry{
-			UserID:   nextUser.UserID,
-			EventID:  nextUser.EventID,
-			JoinedAt: nextUser.JoinedAt,
-			Position: nextUser.Position,
+			UserID:     nextUser.UserID,
+			EventID:    nextUser.EventID,
+			JoinedAt:   nextUser.JoinedAt,
+			Position:   nextUser.Position,
+			NotifiedAt: &now,
+			Tier:       nextUser.Tier,
 		}
 
 		availableUsers = append(availableUsers, serviceEntry)
@@ -171,25 +283,73 @@ func (s *WaitlistService) ProcessSeatAvailability(ctx context.Context, eventID u
 	return availableUsers, nil
 }
 
+// BookFromWaitlist books up to places for the users next in line for an event, in waitlist
+// order, straight away instead of offering them a hold. Each user is taken off the waitlist
+// while book runs and put back in their place if it fails. A conflict, such as the user already
+// holding tickets, moves on to the next user; other failures end the run. Users holding an offer
+// for a released seat are passed over. It returns the number of users booked; book is expected
+// to publish their BookingConfirmed, which completes their waitlist entry.
+func (s *WaitlistService) BookFromWaitlist(ctx context.Context, eventID uint, places int, book func(ctx context.Context, userID uint) error) (int, error) {
+	degraded := s.health.Degraded()
+	booked := 0
+	failed := []uint{}
+	for booked < places {
+		var entry *repository.WaitlistEntry
+		var err error
+		if degraded {
+			// The database entry stays waiting until the booking completes it, so a failed
+			// booking leaves it in place
+			entry, err = s.nextWaitingDB(ctx, eventID, failed)
+		} else {
+			entry, err = s.waitlistRepo.TakeWaitlistUser(ctx, eventID, failed)
+		}
+		if err != nil {
+			return booked, fmt.Errorf("failed to take from waitlist: %w", err)
+		}
+		if entry == nil {
+			break
+		}
+
+		if err := book(ctx, entry.UserID); err != nil {
+			logger.Warnf("Failed to book user %d off the waitlist of event %d, putting them back: %v", entry.UserID, eventID, err)
+			failed = append(failed, entry.UserID)
+			if !degraded {
+				if returnErr := s.waitlistRepo.ReturnToWaitlist(ctx, entry); returnErr != nil {
+					logger.Errorf("Failed to put user %d back on the waitlist of event %d: %v", entry.UserID, eventID, returnErr)
+				}
+			}
+			if appErr, ok := err.(*errors.AppError); !ok || appErr.Type != "CONFLICT" {
+				return booked, err
+			}
+			continue
+		}
+		booked++
+	}
+	return booked, nil
+}
+
 // CleanupExpiredWaitlist removes users who were notified but didn't book within the time limit
 func (s *WaitlistService) CleanupExpiredWaitlist(ctx context.Context) error {
-	// Clean up expired notifications from Redis (5 minutes default)
+	// Clean up expired notifications from Redis
 	events, err := s.getActiveEvents(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get active events: %w", err)
 	}
 
 	for _, event := range events {
-		err := s.waitlistRepo.CleanupExpiredNotifications(ctx, event.ID, 10*time.Minute)
-		if err != nil {
-			fmt.Printf("Failed to cleanup expired notifications for event %d: %v\n", event.ID, err)
+		if s.health.Degraded() {
+			break
+		}
+		err := s.waitlistRepo.CleanupExpiredNotifications(ctx, event.ID, s.promotionHold(ctx))
+		if err != nil && !redisconn.IsUnavailable(err) {
+			logger.Warnf("Failed to cleanup expired notifications for event %d: %v", event.ID, err)
 		}
 	}
 
 	// Update database entries that have expired
 	now := time.Now()
 	err = s.db.WithContext(ctx).
-		Model(&entities.EventQueue{}).
+		Scopes(waitlistEntries).
 		Where("status = ? AND expires_at < ?", "active", now).
 		Update("status", "expired").Error
 
@@ -214,19 +374,19 @@ func (s *WaitlistService) getActiveEvents(ctx context.Context) ([]entities.Event
 func (s *WaitlistService) RemoveUserFromWaitlistAfterBooking(ctx context.Context, userID, eventID uint) error {
 	// Remove from Redis
 	err := s.waitlistRepo.RemoveFromWaitlist(ctx, userID, eventID)
-	if err != nil {
+	if err != nil && err != errors.ErrNotInWaitlist && !redisconn.IsUnavailable(err) {
 		// Log error but don't fail the booking process
-		fmt.Printf("Failed to remove user %d from Redis waitlist for event %d: %v\n", userID, eventID, err)
+		logger.Warnf("Failed to remove user %d from Redis waitlist for event %d: %v", userID, eventID, err)
 	}
 
 	// Update database entry status
 	result := s.db.WithContext(ctx).
-		Model(&entities.EventQueue{}).
+		Scopes(waitlistEntries).
 		Where("user_id = ? AND event_id = ? AND status IN (?)", userID, eventID, []string{"waiting", "active"}).
 		Update("status", "completed")
 
 	if result.Error != nil {
-		fmt.Printf("Failed to update database waitlist entry for user %d, event %d: %v\n", userID, eventID, result.Error)
+		logger.Warnf("Failed to update database waitlist entry for user %d, event %d: %v", userID, eventID, result.Error)
 	}
 
 	return nil
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"api/constants"
+	"api/internal/entities"
+	"api/internal/repository"
+	"api/pkg/errors"
+	logger "api/pkg/logging"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// queuedStatuses are the event_queue statuses still holding a place in the queue; notified
+// users keep theirs until they book or their notification expires, as in Redis
+var queuedStatuses = []string{"waiting", "active"}
+
+// tierFor returns the waitlist tier and priority of a user for an event
+func (s *WaitlistService) tierFor(ctx context.Context, event *entities.Event, userID uint) (string, int, error) {
+	var user entities.User
+	if err := s.db.WithContext(ctx).Select("id", "membership_tier").First(&user, userID).Error; err != nil {
+		return "", 0, fmt.Errorf("failed to get user: %w", err)
+	}
+	tier, priority := waitlistTierPriority(ParseWaitlistTiers(event.WaitlistTiers), user.MembershipTier)
+	return tier, priority, nil
+}
+
+// waitlistEntries scopes a query to the waitlist's rows of event_queues, which also holds
+// the on-sale queue
+func waitlistEntries(db *gorm.DB) *gorm.DB {
+	return db.Model(&entities.EventQueue{}).Where("kind = ?", constants.QueueKindWaitlist)
+}
+
+// joinWaitlistDB queues the user in the database alone while Redis is unavailable. Tiers
+// aren't applied until the entry is copied back to Redis: meanwhile users are served in
+// join order.
+func (s *WaitlistService) joinWaitlistDB(ctx context.Context, userID uint, event *entities.Event, tier string) (*WaitlistEntry, error) {
+	if entry, err := s.waitlistPositionDB(ctx, userID, event.ID); err == nil {
+		return entry, nil
+	}
+
+	var dbEntry entities.EventQueue
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Joins for the same event queue up behind the event row so the cap holds
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&entities.Event{}, event.ID).Error; err != nil {
+			return err
+		}
+
+		var queued int64
+		if err := tx.Scopes(waitlistEntries).
+			Where("event_id = ? AND status IN ?", event.ID, queuedStatuses).
+			Count(&queued).Error; err != nil {
+			return err
+		}
+		if event.WaitlistCap > 0 && int(queued) >= event.WaitlistCap {
+			return errors.ErrWaitlistFull
+		}
+
+		dbEntry = entities.EventQueue{
+			EventID:       event.ID,
+			UserID:        userID,
+			Kind:          constants.QueueKindWaitlist,
+			QueuePosition: int(queued) + 1,
+			Status:        "waiting",
+			JoinedAt:      time.Now(),
+		}
+		return tx.Create(&dbEntry).Error
+	})
+	if err != nil {
+		if err == errors.ErrWaitlistFull {
+			return nil, errors.NewConflictError(constants.ErrWaitlistFull, err)
+		}
+		return nil, fmt.Errorf("failed to save waitlist entry to database: %w", err)
+	}
+
+	return &WaitlistEntry{
+		UserID:   userID,
+		EventID:  event.ID,
+		JoinedAt: dbEntry.JoinedAt,
+		Position: dbEntry.QueuePosition,
+		Tier:     tier,
+	}, nil
+}
+
+// waitlistPositionDB finds the user's place among the queued database entries
+func (s *WaitlistService) waitlistPositionDB(ctx context.Context, userID, eventID uint) (*WaitlistEntry, error) {
+	db := s.db.WithContext(ctx)
+
+	var dbEntry entities.EventQueue
+	if err := db.Scopes(waitlistEntries).Where("user_id = ? AND event_id = ? AND status IN ?", userID, eventID, queuedStatuses).
+		First(&dbEntry).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotInWaitlist
+		}
+		return nil, fmt.Errorf("failed to get waitlist position: %w", err)
+	}
+
+	var ahead int64
+	if err := db.Scopes(waitlistEntries).
+		Where("event_id = ? AND status IN ? AND (joined_at < ? OR (joined_at = ? AND id < ?))",
+			eventID, queuedStatuses, dbEntry.JoinedAt, dbEntry.JoinedAt, dbEntry.ID).
+		Count(&ahead).Error; err != nil {
+		return nil, fmt.Errorf("failed to get waitlist position: %w", err)
+	}
+
+	return &WaitlistEntry{
+		UserID:     userID,
+		EventID:    eventID,
+		JoinedAt:   dbEntry.JoinedAt,
+		Position:   int(ahead) + 1,
+		NotifiedAt: dbEntry.ActiveAt,
+	}, nil
+}
+
+// waitlistSizeDB counts the queued database entries of an event
+func (s *WaitlistService) waitlistSizeDB(ctx context.Context, eventID uint) (int, error) {
+	var size int64
+	if err := s.db.WithContext(ctx).Scopes(waitlistEntries).
+		Where("event_id = ? AND status IN ?", eventID, queuedStatuses).
+		Count(&size).Error; err != nil {
+		return 0, fmt.Errorf("failed to get waitlist size: %w", err)
+	}
+	return int(size), nil
+}
+
+// waitlistHeadDB returns the first users still waiting for a seat, in join order
+func (s *WaitlistService) waitlistHeadDB(ctx context.Context, eventID uint, count int) ([]*repository.WaitlistEntry, error) {
+	var dbEntries []entities.EventQueue
+	if err := s.db.WithContext(ctx).Scopes(waitlistEntries).
+		Where("event_id = ? AND status = ?", eventID, "waiting").
+		Order("joined_at ASC, id ASC").
+		Limit(count).
+		Find(&dbEntries).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]*repository.WaitlistEntry, len(dbEntries))
+	for i, e := range dbEntries {
+		entries[i] = &repository.WaitlistEntry{
+			UserID:   e.UserID,
+			EventID:  e.EventID,
+			JoinedAt: e.JoinedAt,
+			Position: i + 1,
+		}
+	}
+	return entries, nil
+}
+
+// nextWaitingDB returns the first user still waiting for a seat who isn't in skip, in join
+// order, or nil when there is none
+func (s *WaitlistService) nextWaitingDB(ctx context.Context, eventID uint, skip []uint) (*repository.WaitlistEntry, error) {
+	query := s.db.WithContext(ctx).Scopes(waitlistEntries).
+		Where("event_id = ? AND status = ?", eventID, "waiting")
+	if len(skip) > 0 {
+		query = query.Where("user_id NOT IN ?", skip)
+	}
+	var dbEntry entities.EventQueue
+	if err := query.Order("joined_at ASC, id ASC").First(&dbEntry).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &repository.WaitlistEntry{
+		UserID:   dbEntry.UserID,
+		EventID:  dbEntry.EventID,
+		JoinedAt: dbEntry.JoinedAt,
+	}, nil
+}
+
+// RestoreWaitlists copies waitlist changes made in the database while Redis was unavailable
+// back to Redis: users who joined are queued by tier again and users who left are removed.
+// It runs when Redis recovers.
+func (s *WaitlistService) RestoreWaitlists(ctx context.Context, since time.Time) {
+	var changed []entities.EventQueue
+	if err := s.db.WithContext(ctx).Scopes(waitlistEntries).
+		Where("updated_at >= ?", since).
+		Order("joined_at ASC, id ASC").
+		Find(&changed).Error; err != nil {
+		logger.Errorf("Failed to load waitlist changes to restore: %v", err)
+		return
+	}
+
+	events := make(map[uint]*entities.Event)
+	restored := 0
+	for _, entry := range changed {
+		if entry.Status != "waiting" && entry.Status != "active" {
+			// Left, booked or expired; a missing Redis entry is what we want
+			if err := s.waitlistRepo.RemoveFromWaitlist(ctx, entry.UserID, entry.EventID); err == nil {
+				restored++
+			}
+			continue
+		}
+
+		event, ok := events[entry.EventID]
+		if !ok {
+			event = &entities.Event{}
+			if err := s.db.WithContext(ctx).Select("id", "waitlist_tiers").First(event, entry.EventID).Error; err != nil {
+				logger.Warnf("Failed to restore waitlist entry of user %d for event %d: %v", entry.UserID, entry.EventID, err)
+				continue
+			}
+			events[entry.EventID] = event
+		}
+		tier, priority, err := s.tierFor(ctx, event, entry.UserID)
+		if err != nil {
+			logger.Warnf("Failed to restore waitlist entry of user %d for event %d: %v", entry.UserID, entry.EventID, err)
+			continue
+		}
+		// Users already in the Redis queue keep their place; the cap was enforced when they joined
+		if _, err := s.waitlistRepo.JoinWaitlist(ctx, entry.UserID, entry.EventID, tier, priority, 0); err != nil {
+			logger.Warnf("Failed to restore waitlist entry of user %d for event %d: %v", entry.UserID, entry.EventID, err)
+			continue
+		}
+		restored++
+	}
+
+	if restored > 0 {
+		logger.Infof("Copied %d waitlist changes made while Redis was unavailable back to Redis", restored)
+	}
+}
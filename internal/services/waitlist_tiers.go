@@ -0,0 +1,63 @@
+package services
+
+import (
+	"api/constants"
+	"api/pkg/errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var waitlistTierPattern = regexp.MustCompile(`^[a-z0-9_-]{1,50}$`)
+
+// ParseWaitlistTiers splits a comma-separated tier list such as "member,general", highest priority first
+func ParseWaitlistTiers(value string) []string {
+	var tiers []string
+	for _, part := range strings.Split(value, ",") {
+		if tier := strings.ToLower(strings.TrimSpace(part)); tier != "" {
+			tiers = append(tiers, tier)
+		}
+	}
+	return tiers
+}
+
+// NormalizeWaitlistTiers validates the tiers and renders them in canonical form, e.g. [" Member", "general"] -> "member,general"
+func NormalizeWaitlistTiers(values []string) (string, error) {
+	seen := make(map[string]bool, len(values))
+	tiers := make([]string, 0, len(values))
+
+	for _, value := range values {
+		tier := strings.ToLower(strings.TrimSpace(value))
+		if !waitlistTierPattern.MatchString(tier) {
+			return "", errors.NewBadRequestError(fmt.Sprintf("Invalid waitlist tier %q", value), nil)
+		}
+		if seen[tier] {
+			return "", errors.NewBadRequestError(fmt.Sprintf("Duplicate waitlist tier %q", value), nil)
+		}
+		seen[tier] = true
+		tiers = append(tiers, tier)
+	}
+
+	return strings.Join(tiers, ","), nil
+}
+
+// waitlistTierPriority returns the tier a user queues in and its priority, where 0 is served first.
+// Users whose tier isn't listed fall into the general tier, or after every listed tier if there is none.
+func waitlistTierPriority(tiers []string, membershipTier string) (string, int) {
+	tier := strings.ToLower(strings.TrimSpace(membershipTier))
+	if tier == "" {
+		tier = constants.WaitlistTierGeneral
+	}
+
+	for i, t := range tiers {
+		if t == tier {
+			return tier, i
+		}
+	}
+	for i, t := range tiers {
+		if t == constants.WaitlistTierGeneral {
+			return t, i
+		}
+	}
+	return constants.WaitlistTierGeneral, len(tiers)
+}
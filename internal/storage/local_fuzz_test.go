@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// FuzzLocalOpen feeds malformed keys, expiries and signatures to the signed download check.
+// A link only opens if it was signed for that key and expiry, hasn't expired, and the key
+// stays inside the storage directory.
+func FuzzLocalOpen(f *testing.F) {
+	dir := f.TempDir()
+	s, err := NewLocalStorage(dir, "http://localhost:8080", "test-secret")
+	if err != nil {
+		f.Fatal(err)
+	}
+	if err := s.Put(context.Background(), "reports/sales.csv", strings.NewReader("a,b\n"), 4, "text/csv"); err != nil {
+		f.Fatal(err)
+	}
+
+	link, err := s.PresignGet(context.Background(), "reports/sales.csv", time.Hour)
+	if err != nil {
+		f.Fatal(err)
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		f.Fatal(err)
+	}
+	expires, signature := u.Query().Get("expires"), u.Query().Get("signature")
+	future, _ := strconv.ParseInt(expires, 10, 64)
+
+	f.Add("reports/sales.csv", expires, signature)
+	f.Add("reports/sales.csv", expires, strings.ToUpper(signature))
+	f.Add("reports/sales.csv", expires+"0", signature)
+	f.Add("reports/sales.csv", "-1", s.sign("reports/sales.csv", -1))
+	f.Add("reports/../../etc/passwd", expires, s.sign("reports/../../etc/passwd", future))
+	f.Add("/etc/passwd", expires, s.sign("/etc/passwd", future))
+	f.Add("reports\\sales.csv", expires, s.sign("reports\\sales.csv", future))
+	f.Add("reports/sales.csv", "1e18", signature)
+	f.Add("", "", "")
+
+	f.Fuzz(func(t *testing.T, key, expires, signature string) {
+		file, err := s.Open(key, expires, signature)
+		if err != nil {
+			return
+		}
+		defer file.Close()
+
+		expiresAt, parseErr := strconv.ParseInt(expires, 10, 64)
+		switch {
+		case parseErr != nil || expiresAt < time.Now().Unix():
+			t.Errorf("opened %q with expiry %q", key, expires)
+		case signature != s.sign(key, expiresAt):
+			t.Errorf("opened %q with signature %q", key, signature)
+		case ValidateKey(key) != nil:
+			t.Errorf("opened invalid key %q", key)
+		}
+		if rel, err := filepath.Rel(dir, file.Name()); err != nil || strings.HasPrefix(rel, "..") {
+			t.Errorf("opened %s outside %s", file.Name(), dir)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Backends
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+	BackendGCS   = "gcs"
+)
+
+// Storage stores generated artifacts such as tickets, receipts and exports
+type Storage interface {
+	// Put writes size bytes from body under key, replacing any existing object
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Delete removes the object; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+	// PresignGet returns a URL that downloads the object until ttl elapses
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// Config selects and configures a storage backend
+type Config struct {
+	Backend string
+
+	// Local backend
+	LocalDir      string
+	PublicURL     string // base URL the API is served from, used for local download links
+	SigningSecret string // signs local download links
+
+	// S3 and GCS backends (GCS through its S3-compatible XML API with HMAC keys)
+	Bucket    string
+	Region    string
+	Endpoint  string // custom S3-compatible endpoint, e.g. MinIO; objects are addressed path-style
+	AccessKey string
+	SecretKey string
+}
+
+// New returns the backend selected by the config
+func New(cfg Config) (Storage, error) {
+	switch cfg.Backend {
+	case "", BackendLocal:
+		return NewLocalStorage(cfg.LocalDir, cfg.PublicURL, cfg.SigningSecret)
+	case BackendS3:
+		return NewS3Storage(cfg.Bucket, cfg.Region, cfg.Endpoint, cfg.AccessKey, cfg.SecretKey)
+	case BackendGCS:
+		return NewGCSStorage(cfg.Bucket, cfg.AccessKey, cfg.SecretKey)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
+
+// ValidateKey rejects keys that are empty or could escape the storage root
+func ValidateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid storage key %q", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package tasks
+
+import (
+	"api/internal/entities"
+	"api/internal/repository"
+	"api/pkg/errors"
+	logger "api/pkg/logging"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// pollInterval is how often idle workers look for due tasks, e.g. retries or tasks
+	// enqueued by another instance
+	pollInterval = time.Second
+	// staleAfter is how long a running task may go without progress before it is requeued
+	staleAfter = 10 * time.Minute
+	// baseBackoff doubles with every failed attempt, up to maxBackoff
+	baseBackoff = 5 * time.Second
+	maxBackoff  = 5 * time.Minute
+)
+
+// ProgressFunc records how many of a task's units are done
+type ProgressFunc func(done, total int)
+
+// Handler runs one task of a kind and returns the ID of the record it created, if any. Bad
+// request, not found and conflict app errors fail the task at once; other errors are retried.
+type Handler func(ctx context.Context, task *entities.Task, progress ProgressFunc) (*uint, error)
+
+// Queue runs DB-backed tasks on a pool of workers. Tasks survive restarts, and several
+// instances can share the queue.
+type Queue struct {
+	taskRepo    *repository.TaskRepository
+	workers     int
+	maxAttempts int
+	handlers    map[string]Handler
+	wake        chan struct{}
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+}
+
+func NewQueue(taskRepo *repository.TaskRepository, workers, maxAttempts int) *Queue {
+	if workers < 1 {
+		workers = 1
+	}
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &Queue{
+		taskRepo:    taskRepo,
+		workers:     workers,
+		maxAttempts: maxAttempts,
+		handlers:    make(map[string]Handler),
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// Register sets the handler for a task kind. Handlers must be registered before Start.
+func (q *Queue) Register(kind string, handler Handler) {
+	q.handlers[kind] = handler
+}
+
+// Enqueue stores the task with its JSON encoded payload and wakes a worker. Callers set the
+// task's kind, tenant, creator and, if known, total.
+func (q *Queue) Enqueue(ctx context.Context, task *entities.Task, payload interface{}) error {
+	if _, ok := q.handlers[task.Kind]; !ok {
+		return errors.NewInternalError(fmt.Sprintf("No handler for task kind %s", task.Kind), nil)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return errors.NewInternalError("Failed to encode task payload", err)
+	}
+	task.Payload = string(data)
+	task.MaxAttempts = q.maxAttempts
+
+	if err := q.taskRepo.Create(ctx, task); err != nil {
+		return err
+	}
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// DecodePayload unmarshals the task's payload into v
+func DecodePayload(task *entities.Task, v interface{}) error {
+	if err := json.Unmarshal([]byte(task.Payload), v); err != nil {
+		return errors.NewBadRequestError("Invalid task payload", err)
+	}
+	return nil
+}
+
+// Start launches the workers and the loop that requeues tasks abandoned by crashed instances
+func (q *Queue) Start(ctx context.Context) {
+	ctx, q.cancel = context.WithCancel(ctx)
+
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.work(ctx)
+	}
+	q.wg.Add(1)
+	go q.requeueStale(ctx)
+
+	logger.Infof("Task queue started with %d workers", q.workers)
+}
+
+// Stop stops claiming tasks and waits for running ones to finish
+func (q *Queue) Stop() {
+	if q.cancel == nil {
+		return
+	}
+	q.cancel()
+	q.wg.Wait()
+	logger.Info("Task queue stopped")
+}
+
+func (q *Queue) work(ctx context.Context) {
+	defer q.wg.Done()
+
+	for ctx.Err() == nil {
+		task, err := q.taskRepo.Claim(ctx)
+		if err != nil {
+			logger.Errorf("Failed to claim task: %v", err)
+		}
+		if task == nil {
+			select {
+			case <-ctx.Done():
+			case <-q.wake:
+			case <-time.After(pollInterval):
+			}
+			continue
+		}
+
+		// Running tasks finish on shutdown rather than being cut off halfway
+		q.run(context.WithoutCancel(ctx), task)
+	}
+}
+
+// run executes one attempt of a task and records its outcome
+func (q *Queue) run(ctx context.Context, task *entities.Task) {
+	start := time.Now()
+	resultID, err := q.execute(ctx, task)
+	if err == nil {
+		if err := q.taskRepo.Complete(ctx, task.ID, resultID); err != nil {
+			logger.Errorf("Failed to mark task %d completed: %v", task.ID, err)
+		}
+		logger.Debugf("Task %d (%s) completed in %s", task.ID, task.Kind, time.Since(start))
+		return
+	}
+
+	message := publicMessage(err)
+	if isPermanent(err) || task.Attempts >= task.MaxAttempts {
+		logger.Errorf("Task %d (%s) failed after %d attempts: %v", task.ID, task.Kind, task.Attempts, err)
+		if err := q.taskRepo.Fail(ctx, task.ID, message); err != nil {
+			logger.Errorf("Failed to mark task %d failed: %v", task.ID, err)
+		}
+		return
+	}
+
+	delay := backoff(task.Attempts)
+	logger.Warnf("Task %d (%s) attempt %d failed, retrying in %s: %v", task.ID, task.Kind, task.Attempts, delay, err)
+	if err := q.taskRepo.Retry(ctx, task.ID, message, time.Now().Add(delay)); err != nil {
+		logger.Errorf("Failed to requeue task %d: %v", task.ID, err)
+	}
+}
+
+// execute calls the kind's handler, turning a panic into an error so the worker survives
+func (q *Queue) execute(ctx context.Context, task *entities.Task) (resultID *uint, err error) {
+	handler, ok := q.handlers[task.Kind]
+	if !ok {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("No handler for task kind %s", task.Kind), nil)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, task, func(done, total int) {
+		if err := q.taskRepo.UpdateProgress(ctx, task.ID, done, total); err != nil {
+			logger.Warnf("Failed to record progress of task %d: %v", task.ID, err)
+		}
+	})
+}
+
+func (q *Queue) requeueStale(ctx context.Context) {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			requeued, err := q.taskRepo.RequeueStale(ctx, staleAfter)
+			if err != nil {
+				logger.Errorf("Failed to requeue stale tasks: %v", err)
+			} else if requeued > 0 {
+				logger.Warnf("Requeued %d stale tasks", requeued)
+			}
+		}
+	}
+}
+
+// backoff returns the delay before retrying after the given number of attempts
+func backoff(attempts int) time.Duration {
+	delay := baseBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
+
+// isPermanent reports whether retrying can't help, e.g. the request itself was invalid
+func isPermanent(err error) bool {
+	appErr, ok := err.(*errors.AppError)
+	if !ok {
+		return false
+	}
+	switch appErr.Type {
+	case "BAD_REQUEST", "NOT_FOUND", "CONFLICT":
+		return true
+	}
+	return false
+}
+
+// publicMessage hides internal error details from admins polling the task
+func publicMessage(err error) string {
+	if appErr, ok := err.(*errors.AppError); ok && appErr.Type != "INTERNAL_ERROR" {
+		return appErr.Message
+	}
+	return "task failed"
+}
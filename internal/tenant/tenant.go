@@ -0,0 +1,21 @@
+// Package tenant carries the organizer tenant a request is scoped to.
+package tenant
+
+import "context"
+
+// DefaultID is the tenant records created before multi-tenancy belong to
+const DefaultID uint = 1
+
+type contextKey struct{}
+
+// WithTenant scopes everything done with ctx to the tenant
+func WithTenant(ctx context.Context, tenantID uint) context.Context {
+	return context.WithValue(ctx, contextKey{}, tenantID)
+}
+
+// FromContext returns the tenant ctx is scoped to. Requests from platform admins and
+// public endpoints aren't scoped and see every tenant's records.
+func FromContext(ctx context.Context) (uint, bool) {
+	tenantID, ok := ctx.Value(contextKey{}).(uint)
+	return tenantID, ok
+}
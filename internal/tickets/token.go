@@ -0,0 +1,162 @@
+// Package tickets signs the tokens printed as ticket QR codes. Tokens are short enough for a
+// small QR code and signed with Ed25519, so door scanners holding only the public key can
+// validate them without reaching the API.
+package tickets
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Algorithm names the signature scheme, as published to scanners
+const Algorithm = "Ed25519"
+
+// tokenVersion is the first byte of every payload, bumped when the layout changes
+const tokenVersion = 1
+
+// keyIDSize is how many bytes of the public key's SHA-256 identify it in a token
+const keyIDSize = 4
+
+var (
+	ErrMalformed  = errors.New("malformed ticket token")
+	ErrUnknownKey = errors.New("ticket token signed with an unknown key")
+	ErrSignature  = errors.New("invalid ticket token signature")
+)
+
+// Claims is what a ticket token vouches for. Only IDs are carried, so a photographed QR code
+// reveals nothing about the attendee.
+type Claims struct {
+	BookingID  uint
+	EventID    uint
+	SeatID     uint
+	ValidFrom  time.Time
+	ValidUntil time.Time
+}
+
+// ValidAt reports whether the ticket admits entry at t
+func (c Claims) ValidAt(t time.Time) bool {
+	return !t.Before(c.ValidFrom) && !t.After(c.ValidUntil)
+}
+
+// Signer issues and verifies ticket tokens
+type Signer struct {
+	private ed25519.PrivateKey
+	public  ed25519.PublicKey
+	keyID   []byte
+}
+
+// NewSigner builds a signer from a 32 byte Ed25519 seed
+func NewSigner(seed []byte) (*Signer, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("ticket signing key must be %d bytes, got %d", ed25519.SeedSize, len(seed))
+	}
+	private := ed25519.NewKeyFromSeed(seed)
+	public := private.Public().(ed25519.PublicKey)
+	sum := sha256.Sum256(public)
+	return &Signer{private: private, public: public, keyID: sum[:keyIDSize]}, nil
+}
+
+// NewSignerFromConfig decodes a base64 seed, or derives one from fallbackSecret when none is
+// configured
+func NewSignerFromConfig(key, fallbackSecret string) (*Signer, error) {
+	if key == "" {
+		seed := sha256.Sum256([]byte("ticket-signing:" + fallbackSecret))
+		return NewSigner(seed[:])
+	}
+	seed, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("ticket signing key is not valid base64: %w", err)
+	}
+	return NewSigner(seed)
+}
+
+// KeyID identifies the signing key, so scanners can pick the public key a token needs
+func (s *Signer) KeyID() string {
+	return hex.EncodeToString(s.keyID)
+}
+
+// PublicKey is the base64 public key scanners verify tokens with
+func (s *Signer) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(s.public)
+}
+
+// Sign returns the token for claims, "<payload>.<signature>" in unpadded base64url. The payload
+// is the version byte, key ID, then the IDs and the validity window (Unix seconds) as varints.
+func (s *Signer) Sign(claims Claims) string {
+	payload := make([]byte, 0, 1+keyIDSize+5*binary.MaxVarintLen64)
+	payload = append(payload, tokenVersion)
+	payload = append(payload, s.keyID...)
+	payload = binary.AppendUvarint(payload, uint64(claims.BookingID))
+	payload = binary.AppendUvarint(payload, uint64(claims.EventID))
+	payload = binary.AppendUvarint(payload, uint64(claims.SeatID))
+	payload = binary.AppendVarint(payload, claims.ValidFrom.Unix())
+	payload = binary.AppendVarint(payload, claims.ValidUntil.Unix())
+
+	signature := ed25519.Sign(s.private, payload)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// Verify checks the token's signature and returns its claims. It doesn't check the validity
+// window, which depends on when the ticket was scanned.
+func (s *Signer) Verify(token string) (Claims, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return Claims{}, ErrMalformed
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return Claims{}, ErrMalformed
+	}
+	if len(payload) < 1+keyIDSize || payload[0] != tokenVersion {
+		return Claims{}, ErrMalformed
+	}
+	if !bytes.Equal(payload[1:1+keyIDSize], s.keyID) {
+		return Claims{}, ErrUnknownKey
+	}
+	if !ed25519.Verify(s.public, payload, signature) {
+		return Claims{}, ErrSignature
+	}
+	return decodeClaims(payload[1+keyIDSize:])
+}
+
+func decodeClaims(data []byte) (Claims, error) {
+	reader := bytes.NewReader(data)
+	var ids [3]uint64
+	for i := range ids {
+		id, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return Claims{}, ErrMalformed
+		}
+		ids[i] = id
+	}
+	var window [2]int64
+	for i := range window {
+		unix, err := binary.ReadVarint(reader)
+		if err != nil {
+			return Claims{}, ErrMalformed
+		}
+		window[i] = unix
+	}
+	if reader.Len() != 0 {
+		return Claims{}, ErrMalformed
+	}
+	return Claims{
+		BookingID:  uint(ids[0]),
+		EventID:    uint(ids[1]),
+		SeatID:     uint(ids[2]),
+		ValidFrom:  time.Unix(window[0], 0),
+		ValidUntil: time.Unix(window[1], 0),
+	}, nil
+}
@@ -0,0 +1,90 @@
+package tickets
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	signer, err := NewSignerFromConfig("", "jwt-secret")
+	if err != nil {
+		t.Fatalf("new signer: %v", err)
+	}
+
+	claims := Claims{
+		BookingID:  1234,
+		EventID:    56,
+		SeatID:     789012,
+		ValidFrom:  time.Unix(1760000000, 0),
+		ValidUntil: time.Unix(1760030000, 0),
+	}
+	token := signer.Sign(claims)
+	if len(token) > 120 {
+		t.Errorf("token is %d characters, too long for a small QR code", len(token))
+	}
+
+	got, err := signer.Verify(token)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if got.BookingID != claims.BookingID || got.EventID != claims.EventID || got.SeatID != claims.SeatID ||
+		!got.ValidFrom.Equal(claims.ValidFrom) || !got.ValidUntil.Equal(claims.ValidUntil) {
+		t.Errorf("claims %+v, want %+v", got, claims)
+	}
+	if !got.ValidAt(claims.ValidFrom) || got.ValidAt(claims.ValidUntil.Add(time.Second)) {
+		t.Error("validity window not applied")
+	}
+
+	// Scanners only hold the public key
+	public, _ := base64.StdEncoding.DecodeString(signer.PublicKey())
+	payload, signature, _ := strings.Cut(token, ".")
+	rawPayload, _ := base64.RawURLEncoding.DecodeString(payload)
+	rawSignature, _ := base64.RawURLEncoding.DecodeString(signature)
+	if !ed25519.Verify(public, rawPayload, rawSignature) {
+		t.Error("token doesn't verify with the published public key")
+	}
+}
+
+func TestVerifyRejectsTamperedTokens(t *testing.T) {
+	signer, _ := NewSignerFromConfig("", "jwt-secret")
+	other, _ := NewSignerFromConfig("", "another-secret")
+
+	token := signer.Sign(Claims{BookingID: 1, EventID: 2, SeatID: 3, ValidFrom: time.Now(), ValidUntil: time.Now().Add(time.Hour)})
+	payload, signature, _ := strings.Cut(token, ".")
+	forged := other.Sign(Claims{BookingID: 1, EventID: 2, SeatID: 3, ValidFrom: time.Now(), ValidUntil: time.Now().Add(time.Hour)})
+	forgedPayload, forgedSignature, _ := strings.Cut(forged, ".")
+
+	rawPayload, _ := base64.RawURLEncoding.DecodeString(payload)
+	rawPayload[len(rawPayload)-1]++
+	changed := base64.RawURLEncoding.EncodeToString(rawPayload)
+
+	cases := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"no signature", payload, ErrMalformed},
+		{"not base64", "!!!." + signature, ErrMalformed},
+		{"changed payload", changed + "." + signature, ErrSignature},
+		{"signature of another token", payload + "." + forgedSignature, ErrSignature},
+		{"another key", forgedPayload + "." + forgedSignature, ErrUnknownKey},
+	}
+	for _, tc := range cases {
+		if _, err := signer.Verify(tc.token); !errors.Is(err, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestNewSignerFromConfigRejectsBadKeys(t *testing.T) {
+	if _, err := NewSignerFromConfig("not base64!", ""); err == nil {
+		t.Error("invalid base64 key was accepted")
+	}
+	if _, err := NewSignerFromConfig(base64.StdEncoding.EncodeToString([]byte("short")), ""); err == nil {
+		t.Error("short key was accepted")
+	}
+}
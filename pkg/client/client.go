@@ -0,0 +1,183 @@
+// Package client is a typed Go client for the public and authenticated API. It is written by
+// hand rather than generated from the OpenAPI spec, which isn't kept in this repository, and
+// shares the request and response types with the handlers instead. The contract tests in
+// internal/handlers/tests run it against the real handlers so a change in response shape
+// breaks the build instead of the clients, and internal/routes checks that every endpoint it
+// calls is still routed.
+package client
+
+import (
+	"api/pkg/response"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client calls the API at a base URL such as https://tickets.example.com
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+	strict     bool
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient replaces the default client, which times out after 30 seconds
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken sets the bearer token sent on every request
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithStrictDecoding rejects response fields the client doesn't know about. The contract
+// tests use it to catch fields added on the server without updating the shared types.
+func WithStrictDecoding() Option {
+	return func(c *Client) {
+		c.strict = true
+	}
+}
+
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// SetToken replaces the bearer token, e.g. after Login
+func (c *Client) SetToken(token string) {
+	c.token = token
+}
+
+// APIError is returned for any non-2xx response
+type APIError struct {
+	StatusCode int
+	Code       string // the error field of the response body
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("api error %d: %s: %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("api error %d: %s", e.StatusCode, e.Code)
+}
+
+// Page is one page of a paginated listing
+type Page[T any] struct {
+	Data       []T   `json:"data"`
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+	HasNext    bool  `json:"has_next"`
+	HasPrev    bool  `json:"has_prev"`
+
+	Links response.PageLinks `json:"links"`
+}
+
+// envelope is the {message, data} body written by response.Success
+type envelope struct {
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// do sends the request and decodes a 2xx body into out, which may be nil
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		var errResp response.ErrorResponse
+		if json.Unmarshal(data, &errResp) == nil {
+			apiErr.Code = errResp.Error
+			apiErr.Message = errResp.Message
+		}
+		if apiErr.Code == "" {
+			apiErr.Code = http.StatusText(resp.StatusCode)
+		}
+		return apiErr
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	return c.decode(data, out)
+}
+
+// doData sends the request and decodes the data field of a response.Success body
+func (c *Client) doData(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var env envelope
+	if err := c.do(ctx, method, path, query, body, &env); err != nil {
+		return err
+	}
+	if out == nil || len(env.Data) == 0 || string(env.Data) == "null" {
+		return nil
+	}
+	return c.decode(env.Data, out)
+}
+
+func (c *Client) decode(data []byte, out interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if c.strict {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
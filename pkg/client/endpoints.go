@@ -0,0 +1,400 @@
+package client
+
+import (
+	"api/pkg/request"
+	"api/pkg/response"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// BookingIntentStatus mirrors services.BookingIntentStatus, which lives in an internal package
+type BookingIntentStatus struct {
+	BookingIntentID  uint       `json:"booking_intent_id"`
+	Status           string     `json:"status"`
+	PaymentStatus    string     `json:"payment_status"`
+	PaymentIntentID  string     `json:"payment_intent_id,omitempty"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	RemainingSeconds int        `json:"remaining_seconds"`
+}
+
+// WaitlistStats is the body of the waitlist stats endpoint
+type WaitlistStats struct {
+	EventID      uint  `json:"event_id"`
+	WaitlistSize int64 `json:"waitlist_size"`
+}
+
+// ListEventsOptions filters the public event listing. Zero values are left out.
+type ListEventsOptions struct {
+	Page      int
+	Limit     int
+	City      string
+	EventType string
+	Metadata  []string // key:value custom field filters
+}
+
+// Status
+
+func (c *Client) GetHealth(ctx context.Context) (*response.HealthResponse, error) {
+	var health response.HealthResponse
+	if err := c.do(ctx, http.MethodGet, "/health", nil, nil, &health); err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
+
+func (c *Client) GetStatus(ctx context.Context) (*response.ServiceStatusResponse, error) {
+	var status response.ServiceStatusResponse
+	if err := c.doData(ctx, http.MethodGet, "/api/status", nil, nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Auth
+
+func (c *Client) Register(ctx context.Context, req request.RegisterRequest) (*response.UserResponse, error) {
+	var user response.UserResponse
+	if err := c.doData(ctx, http.MethodPost, "/api/register", nil, req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Login authenticates and stores the returned token on the client
+func (c *Client) Login(ctx context.Context, email, password string) (*response.LoginResponse, error) {
+	var login response.LoginResponse
+	req := request.LoginRequest{Email: email, Password: password}
+	if err := c.do(ctx, http.MethodPost, "/api/login", nil, req, &login); err != nil {
+		return nil, err
+	}
+	c.SetToken(login.Token)
+	return &login, nil
+}
+
+func (c *Client) GetProfile(ctx context.Context) (*response.UserResponse, error) {
+	var user response.UserResponse
+	if err := c.do(ctx, http.MethodGet, "/api/profile", nil, nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetLimits returns the caller's usage of each rate limit that applies to them
+func (c *Client) GetLimits(ctx context.Context) (*response.RateLimitsResponse, error) {
+	var limits response.RateLimitsResponse
+	if err := c.do(ctx, http.MethodGet, "/api/limits", nil, nil, &limits); err != nil {
+		return nil, err
+	}
+	return &limits, nil
+}
+
+// GetPolicies returns the terms of service and privacy policy versions to accept
+func (c *Client) GetPolicies(ctx context.Context) (*response.PolicyVersionsResponse, error) {
+	var versions response.PolicyVersionsResponse
+	if err := c.do(ctx, http.MethodGet, "/api/policies", nil, nil, &versions); err != nil {
+		return nil, err
+	}
+	return &versions, nil
+}
+
+// AcceptPolicies accepts the current policy versions, as required after they are bumped
+func (c *Client) AcceptPolicies(ctx context.Context, versions response.PolicyVersionsResponse) ([]response.PolicyAcceptanceResponse, error) {
+	var acceptances []response.PolicyAcceptanceResponse
+	req := request.PolicyAcceptanceRequest{AcceptPolicies: true, TermsVersion: versions.TermsVersion, PrivacyVersion: versions.PrivacyVersion}
+	if err := c.doData(ctx, http.MethodPost, "/api/policies/accept", nil, req, &acceptances); err != nil {
+		return nil, err
+	}
+	return acceptances, nil
+}
+
+// ChangePassword replaces the password and stores the new token on the client, since every
+// token issued before is revoked
+func (c *Client) ChangePassword(ctx context.Context, current, password string) (*response.LoginResponse, error) {
+	var login response.LoginResponse
+	req := request.ChangePasswordRequest{CurrentPassword: current, NewPassword: password}
+	if err := c.doData(ctx, http.MethodPut, "/api/profile/password", nil, req, &login); err != nil {
+		return nil, err
+	}
+	c.SetToken(login.Token)
+	return &login, nil
+}
+
+// Events
+
+func (c *Client) ListEvents(ctx context.Context, opts ListEventsOptions) (*Page[response.EventResponse], error) {
+	query := url.Values{}
+	if opts.Page > 0 {
+		query.Set("page", strconv.Itoa(opts.Page))
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.City != "" {
+		query.Set("city", opts.City)
+	}
+	if opts.EventType != "" {
+		query.Set("event_type", opts.EventType)
+	}
+	for _, filter := range opts.Metadata {
+		query.Add("metadata", filter)
+	}
+
+	var page Page[response.EventResponse]
+	if err := c.do(ctx, http.MethodGet, "/api/events", query, nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+func (c *Client) GetEvent(ctx context.Context, eventID uint) (*response.EventDetailResponse, error) {
+	var event response.EventDetailResponse
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/events/%d", eventID), nil, nil, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// GetAvailableSeats lists open seats, optionally only accessible or companion seats
+func (c *Client) GetAvailableSeats(ctx context.Context, eventID uint, filter request.SeatFilterRequest) ([]response.SeatResponse, error) {
+	query := url.Values{}
+	if filter.Accessible != nil {
+		query.Set("accessible", strconv.FormatBool(*filter.Accessible))
+	}
+	if filter.Companion != nil {
+		query.Set("companion", strconv.FormatBool(*filter.Companion))
+	}
+
+	var seats []response.SeatResponse
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/events/%d/seats", eventID), query, nil, &seats); err != nil {
+		return nil, err
+	}
+	return seats, nil
+}
+
+// Booking intents
+
+func (c *Client) CreateBookingIntent(ctx context.Context, req request.CreateBookingIntentRequest) (*response.BookingIntentResponse, error) {
+	var intent response.BookingIntentResponse
+	if err := c.doData(ctx, http.MethodPost, "/api/booking-intents", nil, req, &intent); err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+func (c *Client) CancelBookingIntent(ctx context.Context, intentID uint) error {
+	req := request.CancelBookingIntentRequest{BookingIntentID: intentID}
+	return c.doData(ctx, http.MethodPost, "/api/booking-intents/cancel", nil, req, nil)
+}
+
+func (c *Client) HeartbeatBookingIntent(ctx context.Context, intentID uint) (*response.HeartbeatResponse, error) {
+	var heartbeat response.HeartbeatResponse
+	if err := c.doData(ctx, http.MethodPost, fmt.Sprintf("/api/booking-intents/%d/heartbeat", intentID), nil, nil, &heartbeat); err != nil {
+		return nil, err
+	}
+	return &heartbeat, nil
+}
+
+func (c *Client) GetBookingIntentStatus(ctx context.Context, intentID uint) (*BookingIntentStatus, error) {
+	var status BookingIntentStatus
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/booking-intents/%d/status", intentID), nil, nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// RetryPayment starts a new payment attempt. An empty failure reason sends no body.
+func (c *Client) RetryPayment(ctx context.Context, intentID uint, failureReason string) (*response.PaymentRetryResponse, error) {
+	var body interface{}
+	if failureReason != "" {
+		body = request.RetryPaymentRequest{FailureReason: failureReason}
+	}
+
+	var retry response.PaymentRetryResponse
+	if err := c.doData(ctx, http.MethodPost, fmt.Sprintf("/api/booking-intents/%d/retry-payment", intentID), nil, body, &retry); err != nil {
+		return nil, err
+	}
+	return &retry, nil
+}
+
+func (c *Client) IssueResumeToken(ctx context.Context, intentID uint) (*response.ResumeTokenResponse, error) {
+	var token response.ResumeTokenResponse
+	if err := c.doData(ctx, http.MethodPost, fmt.Sprintf("/api/booking-intents/%d/resume-token", intentID), nil, nil, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (c *Client) ResumeBookingIntent(ctx context.Context, resumeToken string) (*response.BookingIntentResponse, error) {
+	var intent response.BookingIntentResponse
+	req := request.ResumeBookingIntentRequest{ResumeToken: resumeToken}
+	if err := c.doData(ctx, http.MethodPost, "/api/booking-intents/resume", nil, req, &intent); err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+// Bookings
+
+func (c *Client) ConfirmBooking(ctx context.Context, req request.ConfirmBookingRequest) (*response.BookingResponse, error) {
+	var booking response.BookingResponse
+	if err := c.doData(ctx, http.MethodPost, "/api/bookings/confirm", nil, req, &booking); err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+func (c *Client) CancelBooking(ctx context.Context, bookingID uint) error {
+	return c.doData(ctx, http.MethodDelete, fmt.Sprintf("/api/bookings/%d", bookingID), nil, nil, nil)
+}
+
+func (c *Client) ListBookings(ctx context.Context, page, limit int) (*Page[response.BookingResponse], error) {
+	query := url.Values{}
+	if page > 0 {
+		query.Set("page", strconv.Itoa(page))
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	var bookings Page[response.BookingResponse]
+	if err := c.do(ctx, http.MethodGet, "/api/bookings", query, nil, &bookings); err != nil {
+		return nil, err
+	}
+	return &bookings, nil
+}
+
+func (c *Client) GetBooking(ctx context.Context, bookingID uint) (*response.BookingResponse, error) {
+	var booking response.BookingResponse
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/bookings/%d", bookingID), nil, nil, &booking); err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+func (c *Client) GetTicket(ctx context.Context, bookingID uint) (*response.TicketResponse, error) {
+	var ticket response.TicketResponse
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/bookings/%d/ticket", bookingID), nil, nil, &ticket); err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// Door scanners
+
+// GetTicketKeys returns the public keys ticket QR tokens are signed with, for validating them
+// offline
+func (c *Client) GetTicketKeys(ctx context.Context) ([]response.TicketKeyResponse, error) {
+	var keys []response.TicketKeyResponse
+	if err := c.doData(ctx, http.MethodGet, "/api/tickets/keys", nil, nil, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// SyncCheckIns uploads the tickets a scanner admitted while offline and returns the outcome of
+// each, in order
+func (c *Client) SyncCheckIns(ctx context.Context, eventID uint, req request.SyncCheckInsRequest) ([]response.OfflineCheckInResult, error) {
+	var results []response.OfflineCheckInResult
+	if err := c.doData(ctx, http.MethodPost, fmt.Sprintf("/api/admin/events/%d/check-ins/sync", eventID), nil, req, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// RegisterScanner registers a door scanner at a gate of an event. The returned token is only
+// shown once.
+func (c *Client) RegisterScanner(ctx context.Context, eventID uint, req request.RegisterScannerRequest) (*response.ScannerDeviceResponse, error) {
+	var device response.ScannerDeviceResponse
+	if err := c.doData(ctx, http.MethodPost, fmt.Sprintf("/api/admin/events/%d/scanners", eventID), nil, req, &device); err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// ScanTicket checks in a ticket, with the client authenticated as a scanner device
+func (c *Client) ScanTicket(ctx context.Context, token string) (*response.OfflineCheckInResult, error) {
+	var result response.OfflineCheckInResult
+	if err := c.doData(ctx, http.MethodPost, "/api/scanner/check-ins", nil, request.ScanTicketRequest{Token: token}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Waitlist
+
+func (c *Client) JoinWaitlist(ctx context.Context, eventID uint) (*response.WaitlistResponse, error) {
+	var entry response.WaitlistResponse
+	if err := c.doData(ctx, http.MethodPost, fmt.Sprintf("/api/waitlist/events/%d/join", eventID), nil, nil, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (c *Client) GetWaitlistPosition(ctx context.Context, eventID uint) (*response.WaitlistResponse, error) {
+	var entry response.WaitlistResponse
+	if err := c.doData(ctx, http.MethodGet, fmt.Sprintf("/api/waitlist/events/%d/position", eventID), nil, nil, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (c *Client) LeaveWaitlist(ctx context.Context, eventID uint) error {
+	return c.doData(ctx, http.MethodDelete, fmt.Sprintf("/api/waitlist/events/%d/leave", eventID), nil, nil, nil)
+}
+
+func (c *Client) GetWaitlistStats(ctx context.Context, eventID uint) (*WaitlistStats, error) {
+	var stats WaitlistStats
+	if err := c.doData(ctx, http.MethodGet, fmt.Sprintf("/api/waitlist/events/%d/stats", eventID), nil, nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// Admin
+
+// CreateVenue creates a venue and its seat map, returning the venue ID
+func (c *Client) CreateVenue(ctx context.Context, req request.CreateVenueRequest) (uint, error) {
+	var created struct {
+		VenueID uint `json:"venue_id"`
+	}
+	if err := c.doData(ctx, http.MethodPost, "/api/admin/venues", nil, req, &created); err != nil {
+		return 0, err
+	}
+	return created.VenueID, nil
+}
+
+// CreateEvent starts event creation in the background and returns the task to poll with
+// GetTask; the task's ResultID is the event ID once it completes
+func (c *Client) CreateEvent(ctx context.Context, req request.CreateEventRequest) (uint, error) {
+	var started struct {
+		TaskID uint `json:"task_id"`
+	}
+	if err := c.doData(ctx, http.MethodPost, "/api/admin/events", nil, req, &started); err != nil {
+		return 0, err
+	}
+	return started.TaskID, nil
+}
+
+func (c *Client) GetTask(ctx context.Context, taskID uint) (*response.TaskResponse, error) {
+	var task response.TaskResponse
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/admin/tasks/%d", taskID), nil, nil, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// Mock payments
+
+// CreateMockCharge charges a test card through the built-in mock provider, which is only
+// served when PAYMENT_MOCK_ENABLED is set. Declines return an *APIError with status 402.
+func (c *Client) CreateMockCharge(ctx context.Context, req request.MockChargeRequest) (*response.MockChargeResponse, error) {
+	var charge response.MockChargeResponse
+	if err := c.doData(ctx, http.MethodPost, "/api/mock-payments/charges", nil, req, &charge); err != nil {
+		return nil, err
+	}
+	return &charge, nil
+}
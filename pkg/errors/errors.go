@@ -16,12 +16,15 @@ var (
 	ErrBadRequest         = errors.New("bad request")
 	ErrUnauthorized       = errors.New("unauthorized")
 	ErrRecordNotFound     = errors.New("record not found")
+	ErrWaitlistFull       = errors.New("waitlist is full")
+	ErrNotInWaitlist      = errors.New("user not found in waitlist")
 )
 
 // AppError represents an application error with additional context
 type AppError struct {
 	Type    string `json:"type"`
 	Message string `json:"message"`
+	Code    string `json:"code,omitempty"` // machine readable, set on errors clients handle specifically
 	Cause   error  `json:"-"`
 }
 
@@ -49,6 +52,14 @@ func NewUnauthorizedError(message string, cause error) *AppError {
 	}
 }
 
+func NewForbiddenError(message string, cause error) *AppError {
+	return &AppError{
+		Type:    "FORBIDDEN",
+		Message: message,
+		Cause:   cause,
+	}
+}
+
 func NewInternalError(message string, cause error) *AppError {
 	return &AppError{
 		Type:    "INTERNAL_ERROR",
@@ -72,3 +83,9 @@ func NewConflictError(message string, cause error) *AppError {
 		Cause:   cause,
 	}
 }
+
+// WithCode sets the error's machine readable code
+func (e *AppError) WithCode(code string) *AppError {
+	e.Code = code
+	return e
+}
@@ -0,0 +1,139 @@
+// Package money represents amounts exactly, as whole minor units of the currency such as
+// cents, so prices, fees and refunds add up without floating point drift. Amounts are
+// encoded in JSON as decimal numbers of major units, e.g. 12.5 for 1250 cents, so API clients
+// see the same numbers as before.
+package money
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// MinorPerMajor is the number of minor units in a major unit, e.g. cents in a dollar
+const MinorPerMajor = 100
+
+// rateScale is the precision factors and percentages are applied with: six decimal places
+const rateScale = 1_000_000
+
+// Money is an amount in minor units. The zero value is zero.
+type Money int64
+
+// FromMinor returns an amount of minor units
+func FromMinor(minor int64) Money {
+	return Money(minor)
+}
+
+// FromMajor converts an amount of major units, such as a configured fee, rounding to the
+// nearest minor unit and halves away from zero
+func FromMajor(major float64) Money {
+	return Money(math.Round(major * MinorPerMajor))
+}
+
+// Parse reads a decimal amount of major units such as "12.34", "-0.5" or "1e2". Amounts with
+// a fraction of a minor unit are rejected.
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	amount, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	amount.Mul(amount, big.NewRat(MinorPerMajor, 1))
+	if !amount.IsInt() {
+		return 0, fmt.Errorf("invalid amount %q: more than 2 decimal places", s)
+	}
+	if !amount.Num().IsInt64() {
+		return 0, fmt.Errorf("invalid amount %q: out of range", s)
+	}
+	return Money(amount.Num().Int64()), nil
+}
+
+// Minor returns the amount in minor units
+func (m Money) Minor() int64 {
+	return int64(m)
+}
+
+// Major returns the amount in major units, for display and ratios only
+func (m Money) Major() float64 {
+	return float64(m) / MinorPerMajor
+}
+
+// String formats the amount in major units with two decimals, e.g. "12.50"
+func (m Money) String() string {
+	sign := ""
+	minor := uint64(m)
+	if m < 0 {
+		sign, minor = "-", -minor
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/MinorPerMajor, minor%MinorPerMajor)
+}
+
+// Times returns the amount n times over, e.g. a per-ticket fee for n tickets
+func (m Money) Times(n int64) Money {
+	return m * Money(n)
+}
+
+// Mul scales the amount by factor, such as a section's price multiplier, rounding to the
+// nearest minor unit and halves away from zero. The factor is applied to six decimal places.
+func (m Money) Mul(factor float64) Money {
+	return m.scale(int64(math.Round(factor * rateScale)))
+}
+
+// Percent returns percent of the amount, such as a fee or commission rate, rounding to the
+// nearest minor unit and halves away from zero. The percentage is applied to four decimal places.
+func (m Money) Percent(percent float64) Money {
+	return m.scale(int64(math.Round(percent * rateScale / 100)))
+}
+
+// scale multiplies the amount by scaled/rateScale with exact rounding
+func (m Money) scale(scaled int64) Money {
+	product := new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(scaled))
+	quotient, remainder := new(big.Int).QuoRem(product, big.NewInt(rateScale), new(big.Int))
+	// Round halves away from zero: QuoRem truncates, leaving the remainder the sign of product
+	if twice := new(big.Int).Abs(remainder); twice.Lsh(twice, 1).Cmp(big.NewInt(rateScale)) >= 0 {
+		if product.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return Money(quotient.Int64())
+}
+
+// Sum adds amounts up
+func Sum(amounts ...Money) Money {
+	var total Money
+	for _, amount := range amounts {
+		total += amount
+	}
+	return total
+}
+
+// MarshalJSON encodes the amount as a decimal number of major units, without trailing zeros
+func (m Money) MarshalJSON() ([]byte, error) {
+	s := m.String()
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "" || s == "-" {
+		s = "0"
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalJSON decodes a decimal number of major units, also accepting it quoted
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	amount, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*m = amount
+	return nil
+}
@@ -0,0 +1,259 @@
+package money
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestFromMajor(t *testing.T) {
+	tests := []struct {
+		major float64
+		want  Money
+	}{
+		{0, 0},
+		{12.34, 1234},
+		{0.1 + 0.2, 30},
+		{-2.5, -250},
+		{-0.005, -1},
+	}
+	for _, tt := range tests {
+		if got := FromMajor(tt.major); got != tt.want {
+			t.Errorf("FromMajor(%v) = %d, want %d", tt.major, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Money
+		wantErr bool
+	}{
+		{in: "0", want: 0},
+		{in: "12", want: 1200},
+		{in: "12.5", want: 1250},
+		{in: "12.34", want: 1234},
+		{in: " 49.99 ", want: 4999},
+		{in: "-0.01", want: -1},
+		{in: "1e2", want: 10000},
+		{in: "0.10", want: 10},
+		{in: "12.345", wantErr: true},
+		{in: "0.001", wantErr: true},
+		{in: "", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "99999999999999999999", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		in   Money
+		want string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{1250, "12.50"},
+		{-5, "-0.05"},
+		{-1234, "-12.34"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		in   Money
+		want string
+	}{
+		{0, "0"},
+		{1200, "12"},
+		{1250, "12.5"},
+		{1234, "12.34"},
+		{5, "0.05"},
+		{-250, "-2.5"},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.in)
+		if err != nil || string(data) != tt.want {
+			t.Errorf("json.Marshal(%d) = %s, %v, want %s", tt.in, data, err, tt.want)
+		}
+	}
+
+	var body struct {
+		Price  Money  `json:"price"`
+		Quoted Money  `json:"quoted"`
+		Unset  *Money `json:"unset"`
+	}
+	if err := json.Unmarshal([]byte(`{"price": 19.99, "quoted": "5.5", "unset": null}`), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Price != 1999 || body.Quoted != 550 || body.Unset != nil {
+		t.Errorf("decoded %+v", body)
+	}
+	if err := json.Unmarshal([]byte(`{"price": 19.999}`), &body); err == nil {
+		t.Error("decoding a fraction of a cent succeeded")
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	for minor := int64(-10_000); minor <= 100_000; minor++ {
+		in := FromMinor(minor)
+		data, err := json.Marshal(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out Money
+		if err := json.Unmarshal(data, &out); err != nil || out != in {
+			t.Fatalf("%d encoded as %s decoded to %d, %v", in, data, out, err)
+		}
+	}
+}
+
+// exactScale is amount*factor rounded half away from zero, computed with rationals
+func exactScale(amount Money, factor *big.Rat) Money {
+	product := new(big.Rat).Mul(new(big.Rat).SetInt64(int64(amount)), factor)
+	num, denom := product.Num(), product.Denom()
+	quotient, remainder := new(big.Int).QuoRem(num, denom, new(big.Int))
+	if twice := new(big.Int).Abs(remainder); twice.Lsh(twice, 1).Cmp(denom) >= 0 {
+		if num.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return Money(quotient.Int64())
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		amount  Money
+		percent float64
+		want    Money
+	}{
+		{10000, 10, 1000},
+		{999, 10, 100},   // 99.9 cents
+		{995, 10, 100},   // 99.5 cents rounds up
+		{994, 10, 99},    // 99.4 cents
+		{-995, 10, -100}, // halves round away from zero
+		{1234, 0, 0},
+		{1234, 100, 1234},
+		{333, 33.3333, 111},
+		{1, 50, 1},
+		{1, 49.99, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.Percent(tt.percent); got != tt.want {
+			t.Errorf("Money(%d).Percent(%v) = %d, want %d", tt.amount, tt.percent, got, tt.want)
+		}
+	}
+
+	// Every amount up to 1000.00 at a range of rates matches exact rational arithmetic
+	for _, percent := range []string{"0", "1", "2.5", "3.75", "7", "12.5", "15", "33.3333", "99.99", "100"} {
+		rate, _ := new(big.Rat).SetString(percent)
+		rate.Quo(rate, big.NewRat(100, 1))
+		f, _ := rate.Float64()
+		for minor := int64(0); minor <= 100_000; minor++ {
+			amount := FromMinor(minor)
+			if got, want := amount.Percent(f*100), exactScale(amount, rate); got != want {
+				t.Fatalf("Money(%d).Percent(%s) = %d, want %d", amount, percent, got, want)
+			}
+		}
+	}
+}
+
+func TestMul(t *testing.T) {
+	tests := []struct {
+		amount Money
+		factor float64
+		want   Money
+	}{
+		{1000, 1, 1000},
+		{1000, 1.5, 1500},
+		{3333, 1.5, 5000}, // 4999.5 rounds up
+		{2222, 1.15, 2555},
+		{10, 0.333, 3},
+		{1000, 0, 0},
+		{-3333, 1.5, -5000},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.Mul(tt.factor); got != tt.want {
+			t.Errorf("Money(%d).Mul(%v) = %d, want %d", tt.amount, tt.factor, got, tt.want)
+		}
+	}
+
+	// Section multipliers from 0.00 to 3.00 on every price up to 20.00
+	for step := int64(0); step <= 300; step++ {
+		factor := big.NewRat(step, 100)
+		f, _ := factor.Float64()
+		for minor := int64(0); minor <= 2_000; minor++ {
+			amount := FromMinor(minor)
+			if got, want := amount.Mul(f), exactScale(amount, factor); got != want {
+				t.Fatalf("Money(%d).Mul(%v) = %d, want %d", amount, f, got, want)
+			}
+		}
+	}
+}
+
+func TestTotals(t *testing.T) {
+	// A hundred bookings of 0.10 total exactly 10.00, which float64 sums don't
+	var floatTotal float64
+	bookings := make([]Money, 100)
+	for i := range bookings {
+		bookings[i] = FromMajor(0.1)
+		floatTotal += 0.1
+	}
+	if floatTotal == 10 {
+		t.Fatal("float64 sum was exact; pick amounts that drift")
+	}
+	if got := Sum(bookings...); got != 1000 {
+		t.Errorf("Sum = %d, want 1000", got)
+	}
+
+	// A settlement nets out to the cent: sales less refunds, chargebacks and fees
+	sales := Sum(FromMajor(49.99).Times(37), FromMajor(19.95).Times(12))
+	refunds := FromMajor(49.99).Times(3)
+	chargebacks := FromMajor(19.95)
+	feePerTicket := FromMajor(0.35)
+	netSales := sales - refunds
+	fees := netSales.Percent(2.9) + feePerTicket.Times(37+12-3)
+	net := netSales - chargebacks - fees
+	if sales != 208_903 || netSales != 193_906 || fees != 5_623+1_610 || net != 184_678 {
+		t.Errorf("sales %s, net sales %s, fees %s, net payable %s", sales, netSales, fees, net)
+	}
+	if net+fees+chargebacks+refunds != sales {
+		t.Error("settlement doesn't add back up to the gross sales")
+	}
+
+	// Ledger journals balance exactly however many entries they have
+	var debits, credits Money
+	for minor := int64(1); minor <= 10_000; minor++ {
+		debits += FromMinor(minor)
+		credits += FromMinor(10_001 - minor)
+	}
+	if debits != credits || debits != 50_005_000 {
+		t.Errorf("debits %d, credits %d", debits, credits)
+	}
+}
+
+func TestTimes(t *testing.T) {
+	if got := FromMajor(0.35).Times(3); got != 105 {
+		t.Errorf("Times = %d, want 105", got)
+	}
+	if got := FromMajor(12.5).Times(0); got != 0 {
+		t.Errorf("Times(0) = %d, want 0", got)
+	}
+}
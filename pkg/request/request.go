@@ -1,6 +1,10 @@
 package request
 
 import (
+	"api/pkg/money"
+	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,6 +18,24 @@ type RegisterRequest struct {
 	LastName  string `json:"last_name" binding:"required"`
 	Phone     string `json:"phone"`
 	IsAdmin   bool   `json:"is_admin"`
+	PolicyAcceptanceRequest
+}
+
+// PolicyAcceptanceRequest accepts the terms of service and privacy policy versions the user
+// was shown, as listed by GET /api/policies
+type PolicyAcceptanceRequest struct {
+	AcceptPolicies bool   `json:"accept_policies"`
+	TermsVersion   string `json:"terms_of_service_version"`
+	PrivacyVersion string `json:"privacy_policy_version"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6,nefield=CurrentPassword"`
+}
+
+type RestoreAccountRequest struct {
+	Token string `json:"token" binding:"required"`
 }
 
 type LoginRequest struct {
@@ -21,8 +43,43 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// User requests
+type SetMembershipTierRequest struct {
+	// Empty resets the user to the general tier
+	Tier string `json:"tier"`
+}
+
+type AssignTenantRequest struct {
+	// null makes the admin a platform admin with access to every tenant
+	TenantID *uint `json:"tenant_id"`
+}
+
+// Tenant requests
+type CreateTenantRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+	Slug string `json:"slug" binding:"required,max=100"`
+	// Admin requests per minute shared by the tenant's admins, 0 uses the default
+	RateLimit int `json:"rate_limit" binding:"min=0"`
+}
+
+type UpdateTenantRequest struct {
+	Name      *string `json:"name" binding:"omitempty,max=255"`
+	RateLimit *int    `json:"rate_limit" binding:"omitempty,min=0"`
+}
+
+// UpdateOrganizerProfileRequest changes an organizer's storefront branding; send "" to clear a field
+type UpdateOrganizerProfileRequest struct {
+	DisplayName    *string `json:"display_name" binding:"omitempty,max=255"`
+	LogoURL        *string `json:"logo_url" binding:"omitempty,max=500"`
+	SupportEmail   *string `json:"support_email" binding:"omitempty,max=255"`
+	PrimaryColor   *string `json:"primary_color" binding:"omitempty"`
+	SecondaryColor *string `json:"secondary_color" binding:"omitempty"`
+	AccentColor    *string `json:"accent_color" binding:"omitempty"`
+}
+
 // Venue requests
 type CreateVenueRequest struct {
+	TenantID    uint   `json:"tenant_id"` // platform admins only; tenant admins always create in their own tenant
 	Name        string `json:"name" binding:"required"`
 	Address     string `json:"address" binding:"required"`
 	City        string `json:"city" binding:"required"`
@@ -31,6 +88,15 @@ type CreateVenueRequest struct {
 	Rows        int    `json:"rows" binding:"required,min=1"`
 	Columns     int    `json:"columns" binding:"required,min=1"`
 	Description string `json:"description"`
+	// Custom fields with string, number or boolean values
+	Metadata map[string]interface{} `json:"metadata"`
+	// Minutes before start ticket sales close for events created at the venue without a cutoff
+	SalesCloseMinutesBeforeStart int `json:"sales_close_minutes_before_start" binding:"min=0,max=10080"`
+	// Location of the venue, given together, for weather forecasts
+	Latitude  *float64 `json:"latitude" binding:"required_with=Longitude,omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"required_with=Latitude,omitempty,min=-180,max=180"`
+	// Enrichers adding outside data to the venue's event details, e.g. ["weather"]
+	Enrichments []string `json:"enrichments"`
 }
 
 type UpdateVenueRequest struct {
@@ -42,66 +108,515 @@ type UpdateVenueRequest struct {
 	Rows        *int    `json:"rows"`
 	Columns     *int    `json:"columns"`
 	Description *string `json:"description"`
+	// Replaces all custom fields; {} removes them
+	Metadata *map[string]interface{} `json:"metadata"`
+	// Applies to events created from now on
+	SalesCloseMinutesBeforeStart *int `json:"sales_close_minutes_before_start" binding:"omitempty,min=0,max=10080"`
+	// Location of the venue, for weather forecasts
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	// Replaces the enabled enrichers; [] disables them
+	Enrichments *[]string `json:"enrichments"`
 }
 
 // Event requests
 type CreateEventRequest struct {
-	Name         string    `json:"name" binding:"required"`
-	Description  string    `json:"description"`
-	VenueID      uint      `json:"venue_id" binding:"required"`
-	StartTime    time.Time `json:"start_time" binding:"required"`
-	EndTime      time.Time `json:"end_time" binding:"required"`
-	Price        float64   `json:"price" binding:"required,min=0"`
-	EventType    string    `json:"event_type" binding:"required"`
-	IsHighDemand bool      `json:"is_high_demand"`
+	Name         string      `json:"name" binding:"required"`
+	Description  string      `json:"description"`
+	VenueID      uint        `json:"venue_id" binding:"required"`
+	StartTime    time.Time   `json:"start_time" binding:"required"`
+	EndTime      time.Time   `json:"end_time" binding:"required"`
+	Price        money.Money `json:"price" binding:"required,min=0"`
+	EventType    string      `json:"event_type" binding:"required"`
+	IsHighDemand bool        `json:"is_high_demand"`
+	// Durations before start_time at which attendees are reminded, e.g. ["24h", "2h"]. Left out
+	// it defaults to ["24h", "2h"], an empty list sends no reminders.
+	ReminderOffsets []string `json:"reminder_offsets"`
+	// Maximum waitlist size, 0 means unlimited
+	WaitlistCap int `json:"waitlist_cap" binding:"min=0"`
+	// Waitlist priority tiers, highest first, e.g. ["member", "general"]
+	WaitlistTiers []string `json:"waitlist_tiers"`
+	// General on-sale time; before it only early-access members can book
+	OnSaleAt        *time.Time `json:"on_sale_at"`
+	EarlyAccessAt   *time.Time `json:"early_access_at"`
+	EarlyAccessTier string     `json:"early_access_tier"`
+	// Rows 1..N go on sale at creation and later rows are held for release waves; 0 releases all rows
+	InitialReleaseRows int `json:"initial_release_rows" binding:"min=0"`
+	// Terms and conditions attendees must accept to book, identified by terms_version
+	Terms        string `json:"terms"`
+	TermsVersion string `json:"terms_version"`
+	// Attendee details collected at confirmation; ID numbers are stored encrypted
+	MinimumAge      int  `json:"minimum_age" binding:"min=0,max=100"`
+	RequireFullName bool `json:"require_full_name"`
+	RequireIDNumber bool `json:"require_id_number"`
+	// Box-office mode: staff can sell seats at the door until the event ends
+	DoorSales bool `json:"door_sales"`
+	// Minutes before start_time the doors open
+	DoorsOpenMinutesBeforeStart int `json:"doors_open_minutes_before_start" binding:"min=0,max=1440"`
+	// Door-sale events only: minutes after the doors open that seats of bookings not checked in go
+	// back on sale at the door, 0 never releases them; released bookings are refunded if
+	// no_show_release_refund is set
+	NoShowReleaseMinutes int  `json:"no_show_release_minutes" binding:"min=0,max=1440"`
+	NoShowReleaseRefund  bool `json:"no_show_release_refund"`
+	// Free events only: percentage of capacity booked on top of it for expected no-shows, in
+	// places given to the waitlist once the seats are sold out
+	OversellPercent int `json:"oversell_percent" binding:"min=0,max=50"`
+	// Free events only: confirmed tickets one user can hold, 0 takes the configured default
+	TicketLimit int `json:"ticket_limit" binding:"min=0,max=50"`
+	// ISO country codes intents may be created from, e.g. ["GB", "IE"]; empty sells everywhere
+	SaleCountries []string `json:"sale_countries"`
+	// Custom fields; some event types require fields, e.g. sports events need home_team and away_team
+	Metadata map[string]interface{} `json:"metadata"`
+	// Minutes before start_time ticket sales close; defaults to the venue's policy
+	SalesCloseMinutesBeforeStart *int `json:"sales_close_minutes_before_start" binding:"omitempty,min=0,max=10080"`
+}
+
+// CreateSandboxRequest sets when a sandbox event's rehearsal on-sale opens; without times it
+// opens as soon as the sandbox's seats are generated
+type CreateSandboxRequest struct {
+	OnSaleAt      *time.Time `json:"on_sale_at"`
+	EarlyAccessAt *time.Time `json:"early_access_at"`
 }
 
 type UpdateEventRequest struct {
-	Name         *string    `json:"name"`
-	Description  *string    `json:"description"`
-	VenueID      *uint      `json:"venue_id"`
-	StartTime    *time.Time `json:"start_time"`
-	EndTime      *time.Time `json:"end_time"`
-	Price        *float64   `json:"price"`
-	EventType    *string    `json:"event_type"`
-	IsHighDemand *bool      `json:"is_high_demand"`
-	Status       *string    `json:"status"`
+	Name         *string      `json:"name"`
+	Description  *string      `json:"description"`
+	VenueID      *uint        `json:"venue_id"`
+	StartTime    *time.Time   `json:"start_time"`
+	EndTime      *time.Time   `json:"end_time"`
+	Price        *money.Money `json:"price"`
+	EventType    *string      `json:"event_type"`
+	IsHighDemand *bool        `json:"is_high_demand"`
+	Status       *string      `json:"status"`
+	// An empty list disables reminders for the event
+	ReminderOffsets *[]string `json:"reminder_offsets"`
+	WaitlistCap     *int      `json:"waitlist_cap" binding:"omitempty,min=0"`
+	// An empty list queues everyone first come, first served
+	WaitlistTiers   *[]string  `json:"waitlist_tiers"`
+	OnSaleAt        *time.Time `json:"on_sale_at"`
+	EarlyAccessAt   *time.Time `json:"early_access_at"`
+	EarlyAccessTier *string    `json:"early_access_tier"`
+	// Terms and terms_version are replaced together; bump the version whenever the terms change.
+	// Empty terms remove the acceptance requirement.
+	Terms        *string `json:"terms"`
+	TermsVersion *string `json:"terms_version"`
+	// Attendee requirements apply to bookings confirmed after the change
+	MinimumAge      *int  `json:"minimum_age" binding:"omitempty,min=0,max=100"`
+	RequireFullName *bool `json:"require_full_name"`
+	RequireIDNumber *bool `json:"require_id_number"`
+	// Turns box-office mode on or off
+	DoorSales                   *bool `json:"door_sales"`
+	DoorsOpenMinutesBeforeStart *int  `json:"doors_open_minutes_before_start" binding:"omitempty,min=0,max=1440"`
+	// 0 stops releasing the seats of no-shows
+	NoShowReleaseMinutes *int  `json:"no_show_release_minutes" binding:"omitempty,min=0,max=1440"`
+	NoShowReleaseRefund  *bool `json:"no_show_release_refund"`
+	// Adds or removes oversell places; places already booked are kept
+	OversellPercent *int `json:"oversell_percent" binding:"omitempty,min=0,max=50"`
+	// Tickets users already hold over a lower limit are kept
+	TicketLimit *int `json:"ticket_limit" binding:"omitempty,min=0,max=50"`
+	// An empty list lifts the sale region restriction
+	SaleCountries *[]string `json:"sale_countries"`
+	// Replaces all custom fields and is checked against the (new) event type's schema
+	Metadata *map[string]interface{} `json:"metadata"`
+	// 0 keeps sales open until the event starts
+	SalesCloseMinutesBeforeStart *int `json:"sales_close_minutes_before_start" binding:"omitempty,min=0,max=10080"`
+}
+
+// Seat requests
+type SetSeatAccessibilityRequest struct {
+	IsAccessible bool `json:"is_accessible"`
+	// Seats reserved for companions of whoever books this seat; replaces the current companions
+	CompanionSeatIDs []uint `json:"companion_seat_ids" binding:"omitempty,max=4"`
+}
+
+type ReleaseSeatsRequest struct {
+	RowStart int `json:"row_start" binding:"required,min=1"`
+	RowEnd   int `json:"row_end" binding:"required,gtefield=RowStart"`
+}
+
+// PurgeIntentsRequest expires an event's pending intents created more than OlderThanMinutes ago
+type PurgeIntentsRequest struct {
+	OlderThanMinutes int `json:"older_than_minutes" binding:"required,min=1,max=10080"`
+}
+
+// WarmCacheRequest lists the events to preload into the cache; without any the hot events are
+type WarmCacheRequest struct {
+	EventIDs []uint `json:"event_ids" binding:"max=100"`
+}
+
+// UpdateSeatPricesRequest reprices the seats matching all of the filters given; without
+// filters every seat of the event is repriced
+type UpdateSeatPricesRequest struct {
+	Price    *money.Money `json:"price" binding:"required,min=0"`
+	SeatIDs  []uint       `json:"seat_ids" binding:"omitempty,max=1000"`
+	SeatType string       `json:"seat_type" binding:"omitempty,max=50"`
+	RowStart int          `json:"row_start" binding:"required_with=RowEnd,omitempty,min=1"`
+	RowEnd   int          `json:"row_end" binding:"required_with=RowStart,omitempty,gtefield=RowStart"`
+}
+
+// Rate limit requests
+type RateLimitAllowlistRequest struct {
+	Type string `json:"type" form:"type" binding:"required,oneof=ip user"`
+	// An IP address or CIDR range for type ip, a user ID for type user
+	Value string `json:"value" form:"value" binding:"required,max=64"`
+}
+
+// Presale requests
+type CreatePresaleBatchRequest struct {
+	Name  string `json:"name" binding:"required,max=100"`
+	Count int    `json:"count" binding:"required,min=1,max=1000"`
+	// Uses allowed per code, defaults to 1; 0 means unlimited
+	MaxUses *int `json:"max_uses" binding:"omitempty,min=0"`
+}
+
+// AddSaleRegionOverrideRequest lets a user book an event from outside its sale countries
+type AddSaleRegionOverrideRequest struct {
+	UserID uint   `json:"user_id" binding:"required"`
+	Note   string `json:"note" binding:"max=255"`
 }
 
 // Booking requests
 type CreateBookingIntentRequest struct {
-	SeatID uint `json:"seat_id" binding:"required"`
+	SeatID      uint   `json:"seat_id" binding:"required"`
+	PresaleCode string `json:"presale_code" binding:"omitempty,max=32"` // required before the general on-sale unless the user's tier has early access
+	// Required for events with terms and conditions: accept_terms=true and the terms_version shown to the user
+	AcceptTerms  bool   `json:"accept_terms"`
+	TermsVersion string `json:"terms_version" binding:"omitempty,max=50"`
+	// Acquisition channel: the utm_* parameters of the link the user arrived through, or a
+	// referral code they were given
+	UTMSource    string `json:"utm_source" binding:"max=100"`
+	UTMMedium    string `json:"utm_medium" binding:"max=100"`
+	UTMCampaign  string `json:"utm_campaign" binding:"max=100"`
+	ReferralCode string `json:"referral_code" binding:"max=50"`
+	// Free events are confirmed straight from the intent, so their attendee details come here
+	Attendee *AttendeeRequest `json:"attendee"`
 }
 
 type ConfirmBookingRequest struct {
-	BookingIntentID uint   `json:"booking_intent_id" binding:"required"`
-	PaymentID       string `json:"payment_id" binding:"required"`
+	BookingIntentID     uint                  `json:"booking_intent_id" binding:"required"`
+	PaymentID           string                `json:"payment_id" binding:"required"`
+	Provider            string                `json:"provider" binding:"omitempty,max=50"`
+	Currency            string                `json:"currency" binding:"omitempty,len=3,alpha"`
+	PaymentMethod       *PaymentMethodRequest `json:"payment_method"`
+	RedeemPoints        int                   `json:"redeem_points" binding:"min=0"` // loyalty points applied as a discount
+	Attendee            *AttendeeRequest      `json:"attendee"`                      // required for events with attendee requirements
+	ReleaseOtherIntents bool                  `json:"release_other_intents"`         // cancel the user's other pending intents for this event
+}
+
+// PaymentMethodRequest carries display details only; card numbers are never accepted
+type PaymentMethodRequest struct {
+	Type      string `json:"type" binding:"omitempty,oneof=card wallet bank_transfer"`
+	CardBrand string `json:"card_brand" binding:"omitempty,max=20,alpha"`
+	CardLast4 string `json:"card_last4" binding:"omitempty,len=4,number"`
+}
+
+// AttendeeRequest holds the attendee details an event may require
+type AttendeeRequest struct {
+	FullName    string `json:"full_name" binding:"omitempty,max=200"`
+	DateOfBirth string `json:"date_of_birth" binding:"omitempty,datetime=2006-01-02"`
+	IDNumber    string `json:"id_number" binding:"omitempty,max=50"`
+}
+
+// DoorSaleRequest sells a seat at the box office; without a seat_id the best available seat
+// is sold, and without an amount the seat's price is charged
+type DoorSaleRequest struct {
+	SeatID        uint             `json:"seat_id"`
+	UserID        *uint            `json:"user_id"` // the buyer's account, if they have one
+	Amount        *money.Money     `json:"amount" binding:"omitempty,min=0"`
+	PaymentMethod string           `json:"payment_method" binding:"required,oneof=cash card"`
+	Currency      string           `json:"currency" binding:"omitempty,len=3,alpha"`
+	Reference     string           `json:"reference" binding:"max=255"` // receipt or card terminal reference
+	Attendee      *AttendeeRequest `json:"attendee"`
+	CheckIn       bool             `json:"check_in"` // admit the buyer straight away
+}
+
+// IssueCompsRequest books complimentary tickets for specific seats
+type IssueCompsRequest struct {
+	SeatIDs      []uint `json:"seat_ids" binding:"required,min=1,max=50"`
+	Reason       string `json:"reason" binding:"required,max=255"`
+	UserID       *uint  `json:"user_id"`                         // the guest's account, if they have one
+	AttendeeName string `json:"attendee_name" binding:"max=200"` // printed on the tickets
+}
+
+// ResumeBookingIntentRequest redeems a token issued on the device that started the checkout
+type ResumeBookingIntentRequest struct {
+	ResumeToken string `json:"resume_token" binding:"required"`
+}
+
+// SubmitReviewRequest rates an attended event from 1 to 5 stars
+type SubmitReviewRequest struct {
+	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
+	Comment string `json:"comment" binding:"max=1000"`
+}
+
+// SyncCheckInsRequest uploads the tickets a scanner admitted while offline
+type SyncCheckInsRequest struct {
+	CheckIns []OfflineCheckInRequest `json:"check_ins" binding:"required,min=1,max=1000,dive"`
+}
+
+type OfflineCheckInRequest struct {
+	Token     string    `json:"token" binding:"required,max=200"`
+	ScannedAt time.Time `json:"scanned_at" binding:"required"`
+}
+
+// RegisterScannerRequest registers a door scanner at one gate of an event
+type RegisterScannerRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	Gate string `json:"gate" binding:"required,max=50"`
+}
+
+// ScanTicketRequest checks in the ticket a scanner just read
+type ScanTicketRequest struct {
+	Token string `json:"token" binding:"required,max=200"`
 }
 
 type CancelBookingIntentRequest struct {
 	BookingIntentID uint `json:"booking_intent_id" binding:"required"`
 }
 
+type RetryPaymentRequest struct {
+	FailureReason string `json:"failure_reason" binding:"max=255"`
+}
+
+// Webhook requests
+type DisputeWebhookRequest struct {
+	DisputeID  string      `json:"dispute_id" binding:"required"`
+	PaymentID  string      `json:"payment_id" binding:"required"`
+	Status     string      `json:"status" binding:"required,oneof=open under_review won lost"`
+	Reason     string      `json:"reason"`
+	Amount     money.Money `json:"amount" binding:"min=0"`
+	OccurredAt time.Time   `json:"occurred_at"`
+}
+
+// BookingHistoryRequest filters and orders a user's bookings, e.g. period=upcoming for an
+// "Upcoming" tab
+type BookingHistoryRequest struct {
+	PaginationRequest
+	FieldsRequest
+	Status  string `form:"status" binding:"omitempty,oneof=pending confirmed cancelled refunded"`
+	EventID uint   `form:"event_id"`
+	Period  string `form:"period" binding:"omitempty,oneof=upcoming past"`
+	Sort    string `form:"sort" binding:"omitempty,oneof=newest oldest event_start event_start_desc"`
+}
+
+// UpcomingBookingsRequest sizes the upcoming bookings widget
+type UpcomingBookingsRequest struct {
+	Limit int `form:"limit,default=3" binding:"min=1,max=10"`
+}
+
+type PaymentTransactionFilterRequest struct {
+	PaginationRequest
+	Provider  string     `form:"provider"`
+	Reference string     `form:"reference"`
+	Status    string     `form:"status" binding:"omitempty,oneof=pending paid failed refunded"`
+	BookingID uint       `form:"booking_id"`
+	From      *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To        *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type DisputeFilterRequest struct {
+	PaginationRequest
+	Status string `form:"status" binding:"omitempty,oneof=open under_review won lost"`
+}
+
+// CreateEventPostRequest publishes an announcement or FAQ entry on an event. The title is the
+// question of an FAQ and the body its answer.
+type CreateEventPostRequest struct {
+	Kind     string `json:"kind" binding:"required,oneof=announcement faq"`
+	Title    string `json:"title" binding:"required,max=255"`
+	Body     string `json:"body" binding:"required,max=10000"`
+	Position int    `json:"position" binding:"min=0"` // order of FAQs, lowest first
+}
+
+type UpdateEventPostRequest struct {
+	Title    *string `json:"title" binding:"omitempty,min=1,max=255"`
+	Body     *string `json:"body" binding:"omitempty,min=1,max=10000"`
+	Position *int    `json:"position" binding:"omitempty,min=0"`
+}
+
+// OpenSupportCaseRequest opens a support case on a booking
+type OpenSupportCaseRequest struct {
+	Category string `json:"category" binding:"required,oneof=refund ticket access payment other"`
+	Message  string `json:"message" binding:"required,max=5000"`
+}
+
+// RespondSupportCaseRequest answers a support case; the message may be left out when resolving
+type RespondSupportCaseRequest struct {
+	Message string `json:"message" binding:"max=5000"`
+	Resolve bool   `json:"resolve"`
+}
+
+type SupportCaseFilterRequest struct {
+	PaginationRequest
+	Status   string `form:"status" binding:"omitempty,oneof=open answered resolved"`
+	Category string `form:"category" binding:"omitempty,oneof=refund ticket access payment other"`
+}
+
+// MockChargeRequest charges a test card through the built-in mock payment provider
+type MockChargeRequest struct {
+	PaymentReference string      `json:"payment_reference" binding:"required,max=255"` // payment_intent_id of the booking intent
+	Amount           money.Money `json:"amount" binding:"min=0"`
+	Currency         string      `json:"currency" binding:"omitempty,len=3,alpha"`
+	CardNumber       string      `json:"card_number" binding:"required,min=12,max=23"`
+}
+
 // Queue requests
 type JoinQueueRequest struct {
 	EventID uint `json:"event_id" binding:"required"`
 }
 
+// Import requests
+type ImportRequest struct {
+	DryRun bool `form:"dry_run"`
+}
+
+// FieldsRequest selects the fields of a response, as comma separated JSON names with dots for
+// the fields of relations, e.g. fields=id,name,venue.city, and the relations to load, e.g.
+// expand=venue,seats. Without expand every relation is loaded.
+type FieldsRequest struct {
+	Fields string  `form:"fields"`
+	Expand *string `form:"expand"`
+}
+
+// AutocompleteRequest is what a user typed in a search box so far
+type AutocompleteRequest struct {
+	Q     string `form:"q" binding:"max=100"`
+	Limit int    `form:"limit,default=10" binding:"min=1,max=20"`
+}
+
 // Pagination and filtering
 type PaginationRequest struct {
 	Page  int `form:"page,default=1" binding:"min=1"`
 	Limit int `form:"limit,default=10" binding:"min=1,max=100"`
 }
 
+// maxOffset caps the rows skipped, far past any listing, so huge page numbers can't overflow
+const maxOffset = math.MaxInt32
+
+// Offset returns the number of rows before the requested page
+func (p PaginationRequest) Offset() int {
+	if p.Page <= 1 || p.Limit <= 0 {
+		return 0
+	}
+	if p.Page-1 > maxOffset/p.Limit {
+		return maxOffset
+	}
+	return (p.Page - 1) * p.Limit
+}
+
+// FeedRequest selects a page of an event feed, 50 events each
+type FeedRequest struct {
+	Page int `form:"page,default=1" binding:"min=1,max=10000"`
+}
+
+// AcquisitionFilterRequest selects the bookings an acquisition report covers; from and to
+// are inclusive booking dates
+type AcquisitionFilterRequest struct {
+	EventID uint   `form:"event_id"`
+	From    string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To      string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+	GroupBy string `form:"group_by,default=channel" binding:"oneof=channel campaign referral"`
+	Limit   int    `form:"limit,default=50" binding:"min=1,max=200"`
+}
+
+// VenueReportRequest selects the months a venue utilization report or seat heatmap covers,
+// inclusive; the last 12 months by default
+type VenueReportRequest struct {
+	From string `form:"from" binding:"omitempty,datetime=2006-01"`
+	To   string `form:"to" binding:"omitempty,datetime=2006-01"`
+}
+
+// LedgerFilterRequest selects the journals a ledger balance report covers; from and to are
+// inclusive posting dates
+type LedgerFilterRequest struct {
+	EventID uint   `form:"event_id"`
+	From    string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To      string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// CreateReferrerRequest sets up an affiliate partner; without a code one is generated, and
+// without a commission rate (percent) the default applies
+type CreateReferrerRequest struct {
+	Name           string   `json:"name" binding:"required,max=255"`
+	Email          string   `json:"email" binding:"omitempty,email,max=255"`
+	Code           string   `json:"code" binding:"omitempty,max=50"`
+	CommissionRate *float64 `json:"commission_rate" binding:"omitempty,min=0,max=100"`
+	UserID         *uint    `json:"user_id"` // account that sees the referrer dashboard
+}
+
+type UpdateReferrerRequest struct {
+	Name           *string  `json:"name" binding:"omitempty,min=1,max=255"`
+	Email          *string  `json:"email" binding:"omitempty,max=255"`
+	CommissionRate *float64 `json:"commission_rate" binding:"omitempty,min=0,max=100"`
+	Active         *bool    `json:"active"`
+}
+
 type EventFilterRequest struct {
 	PaginationRequest
+	FieldsRequest
 	City      string `form:"city"`
 	EventType string `form:"event_type"`
+	// Custom field filters as key:value, repeatable, e.g. metadata=home_team:Lakers
+	Metadata []string `form:"metadata"`
+}
+
+type ArtifactFilterRequest struct {
+	PaginationRequest
+	Kind string `form:"kind"`
+}
+
+type SeatFilterRequest struct {
+	Accessible *bool `form:"accessible"`
+	Companion  *bool `form:"companion"`
+}
+
+type TaskFilterRequest struct {
+	PaginationRequest
+	Kind   string `form:"kind"`
+	Status string `form:"status" binding:"omitempty,oneof=pending running completed failed"`
+}
+
+// DeadLetterFilterRequest filters the failed async work console
+type DeadLetterFilterRequest struct {
+	PaginationRequest
+	Source string `form:"source" binding:"omitempty,oneof=notification admin_notification domain_event"`
+	Status string `form:"status" binding:"omitempty,oneof=dead retried discarded"`
+}
+
+// UpdateSettingRequest overrides a runtime setting, e.g. "5m" for durations or "2.5" for numbers
+type UpdateSettingRequest struct {
+	Value string `json:"value" binding:"required"`
+}
+
+// SettingChangeFilterRequest filters the runtime setting audit log
+type SettingChangeFilterRequest struct {
+	PaginationRequest
+	Key string `form:"key"`
+}
+
+// ArchiveBookingsRequest overrides how many months after completion events are archived
+type ArchiveBookingsRequest struct {
+	OlderThanMonths int `json:"older_than_months" binding:"omitempty,min=1,max=120"`
 }
 
 type VenueFilterRequest struct {
 	PaginationRequest
-	City string `form:"city"`
+	FieldsRequest
+	City     string   `form:"city"`
+	Metadata []string `form:"metadata"` // key:value, repeatable
+}
+
+// ParseMetadataFilters turns key:value metadata query parameters into a map
+func ParseMetadataFilters(values []string) (map[string]string, error) {
+	filters := make(map[string]string, len(values))
+	for _, value := range values {
+		key, match, ok := strings.Cut(value, ":")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("metadata filter %q must be key:value", value)
+		}
+		filters[key] = match
+	}
+	return filters, nil
 }
 
 // Helper function to bind JSON request
@@ -0,0 +1,184 @@
+package request
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The fuzz targets run their seed corpus with go test; fuzz one of them with e.g.
+// go test ./pkg/request/ -run '^$' -fuzz FuzzBindConfirmBooking -fuzztime 1m
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func jsonContext(body []byte) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return c
+}
+
+func queryContext(query string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.URL.RawQuery = query
+	return c
+}
+
+var (
+	digits  = regexp.MustCompile(`^[0-9]+$`)
+	letters = regexp.MustCompile(`^[a-zA-Z]+$`)
+)
+
+func FuzzBindCreateBookingIntent(f *testing.F) {
+	f.Add([]byte(`{"seat_id": 1}`))
+	f.Add([]byte(`{"seat_id": 1, "presale_code": "EARLY-2024", "accept_terms": true, "terms_version": "v1"}`))
+	f.Add([]byte(`{"seat_id": -1}`))
+	f.Add([]byte(`{"seat_id": 18446744073709551616}`))
+	f.Add([]byte(`{"seat_id": "1"}`))
+	f.Add([]byte(`[]`))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		var req CreateBookingIntentRequest
+		if BindJSON(jsonContext(body), &req) != nil {
+			return
+		}
+		if req.SeatID == 0 {
+			t.Errorf("bound without a seat: %q", body)
+		}
+		if utf8.RuneCountInString(req.PresaleCode) > 32 || utf8.RuneCountInString(req.TermsVersion) > 50 {
+			t.Errorf("bound an oversized presale code or terms version: %q", body)
+		}
+	})
+}
+
+func FuzzBindConfirmBooking(f *testing.F) {
+	f.Add([]byte(`{"booking_intent_id": 1, "payment_id": "pay_123"}`))
+	f.Add([]byte(`{"booking_intent_id": 1, "payment_id": "pay_123", "currency": "usd", "redeem_points": 500,
+		"payment_method": {"type": "card", "card_brand": "visa", "card_last4": "4242"}}`))
+	f.Add([]byte(`{"booking_intent_id": 1, "payment_id": "pay_123", "payment_method": {"card_last4": "-123"}}`))
+	f.Add([]byte(`{"booking_intent_id": 1, "payment_id": "pay_123", "redeem_points": -5}`))
+	f.Add([]byte(`{"booking_intent_id": 1, "payment_id": "pay_123", "attendee": {"date_of_birth": "2000-02-30"}}`))
+	f.Add([]byte(`{"booking_intent_id": 1, "payment_id": "pay_123", "payment_method": null, "attendee": null}`))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		var req ConfirmBookingRequest
+		if BindJSON(jsonContext(body), &req) != nil {
+			return
+		}
+		switch {
+		case req.BookingIntentID == 0 || req.PaymentID == "":
+			t.Errorf("bound without an intent or payment: %q", body)
+		case req.RedeemPoints < 0:
+			t.Errorf("bound negative points: %q", body)
+		case req.Currency != "" && (len(req.Currency) != 3 || !letters.MatchString(req.Currency)):
+			t.Errorf("bound currency %q", req.Currency)
+		case utf8.RuneCountInString(req.Provider) > 50:
+			t.Errorf("bound an oversized provider: %q", body)
+		}
+		if method := req.PaymentMethod; method != nil {
+			if method.CardLast4 != "" && (len(method.CardLast4) != 4 || !digits.MatchString(method.CardLast4)) {
+				t.Errorf("bound card_last4 %q", method.CardLast4)
+			}
+			if method.CardBrand != "" && !letters.MatchString(method.CardBrand) {
+				t.Errorf("bound card_brand %q", method.CardBrand)
+			}
+			if method.Type != "" && method.Type != "card" && method.Type != "wallet" && method.Type != "bank_transfer" {
+				t.Errorf("bound payment method type %q", method.Type)
+			}
+		}
+		if attendee := req.Attendee; attendee != nil && utf8.RuneCountInString(attendee.FullName) > 200 {
+			t.Errorf("bound an oversized attendee name: %q", body)
+		}
+	})
+}
+
+func FuzzBindReleaseSeats(f *testing.F) {
+	f.Add([]byte(`{"row_start": 1, "row_end": 5}`))
+	f.Add([]byte(`{"row_start": 5, "row_end": 1}`))
+	f.Add([]byte(`{"row_start": 0, "row_end": 0}`))
+	f.Add([]byte(`{"row_start": 1e3, "row_end": 2e3}`))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		var req ReleaseSeatsRequest
+		if BindJSON(jsonContext(body), &req) != nil {
+			return
+		}
+		if req.RowStart < 1 || req.RowEnd < req.RowStart {
+			t.Errorf("bound rows %d..%d: %q", req.RowStart, req.RowEnd, body)
+		}
+	})
+}
+
+func FuzzBindDisputeWebhook(f *testing.F) {
+	f.Add([]byte(`{"dispute_id": "dp_1", "payment_id": "pay_1", "status": "open", "amount": 50, "occurred_at": "2024-01-01T00:00:00Z"}`))
+	f.Add([]byte(`{"dispute_id": "dp_1", "payment_id": "pay_1", "status": "closed"}`))
+	f.Add([]byte(`{"dispute_id": "dp_1", "payment_id": "pay_1", "status": "won", "amount": -1}`))
+	f.Add([]byte(`{"dispute_id": "dp_1", "payment_id": "pay_1", "status": "lost", "occurred_at": "yesterday"}`))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		var req DisputeWebhookRequest
+		if BindJSON(jsonContext(body), &req) != nil {
+			return
+		}
+		switch req.Status {
+		case "open", "under_review", "won", "lost":
+		default:
+			t.Errorf("bound status %q", req.Status)
+		}
+		if req.DisputeID == "" || req.PaymentID == "" || req.Amount < 0 {
+			t.Errorf("bound %+v", req)
+		}
+	})
+}
+
+func FuzzBindPagination(f *testing.F) {
+	f.Add("")
+	f.Add("page=2&limit=50")
+	f.Add("page=0&limit=10")
+	f.Add("page=1&limit=101")
+	f.Add("page=9223372036854775807&limit=100")
+	f.Add("page=-1")
+	f.Add("page=1&page=2&limit=")
+
+	f.Fuzz(func(t *testing.T, query string) {
+		var req PaginationRequest
+		if BindQuery(queryContext(query), &req) != nil {
+			return
+		}
+		if req.Page < 1 || req.Limit < 1 || req.Limit > 100 {
+			t.Errorf("bound page %d, limit %d from %q", req.Page, req.Limit, query)
+		}
+		if offset := req.Offset(); offset < 0 || offset > maxOffset {
+			t.Errorf("offset %d for page %d, limit %d", offset, req.Page, req.Limit)
+		}
+	})
+}
+
+func FuzzParseMetadataFilters(f *testing.F) {
+	f.Add("home_team:Lakers")
+	f.Add("parking:true")
+	f.Add(":value")
+	f.Add("key:with:colons")
+	f.Add("novalue")
+
+	f.Fuzz(func(t *testing.T, value string) {
+		filters, err := ParseMetadataFilters([]string{value})
+		if err != nil {
+			return
+		}
+		for key, match := range filters {
+			if key == "" || key+":"+match != value || strings.Contains(key, ":") {
+				t.Errorf("%q parsed as %q = %q", value, key, match)
+			}
+		}
+	})
+}
@@ -0,0 +1,261 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FieldSet is the fields of a response a client asked for, as a tree of JSON names. A field
+// without children is returned whole, relations included.
+type FieldSet map[string]FieldSet
+
+// ParseFields parses a fields query parameter such as "id,name,venue.city" against the
+// response type of sample, rejecting fields the response doesn't have. An empty parameter
+// selects every field and returns a nil set.
+func ParseFields(raw string, sample interface{}) (FieldSet, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	fields := FieldSet{}
+	for _, path := range strings.Split(raw, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if err := checkField(reflect.TypeOf(sample), path); err != nil {
+			return nil, err
+		}
+		node := fields
+		names := strings.Split(path, ".")
+		for i, name := range names {
+			child, seen := node[name]
+			if seen && child == nil {
+				break // already selected whole
+			}
+			if i == len(names)-1 {
+				node[name] = nil
+				break
+			}
+			if child == nil {
+				child = FieldSet{}
+				node[name] = child
+			}
+			node = child
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// Includes reports whether a field such as "seats" or "event.venue" is selected, so handlers can
+// skip loading and building relations nobody asked for
+func (f FieldSet) Includes(path string) bool {
+	node := f
+	for _, name := range strings.Split(path, ".") {
+		if node == nil {
+			return true // selected whole
+		}
+		child, ok := node[name]
+		if !ok {
+			return false
+		}
+		node = child
+	}
+	return true
+}
+
+// Without returns the set with the fields at paths left out, such as relations that weren't
+// loaded. Fields selected whole are expanded into their own fields against the response type
+// of sample first.
+func (f FieldSet) Without(sample interface{}, paths ...string) FieldSet {
+	if len(paths) == 0 {
+		return f
+	}
+	t := reflect.TypeOf(sample)
+	fields := f.clone()
+	if fields == nil {
+		fields = allFields(t)
+	}
+	for _, path := range paths {
+		fields.remove(t, strings.Split(path, "."))
+	}
+	return fields
+}
+
+func (f FieldSet) remove(t reflect.Type, names []string) {
+	if len(names) == 1 {
+		delete(f, names[0])
+		return
+	}
+	child, ok := f[names[0]]
+	if !ok {
+		return
+	}
+	field, _ := jsonField(elemType(t), names[0])
+	if child == nil {
+		child = allFields(field)
+		f[names[0]] = child
+	}
+	child.remove(field, names[1:])
+}
+
+func (f FieldSet) clone() FieldSet {
+	if f == nil {
+		return nil
+	}
+	fields := make(FieldSet, len(f))
+	for name, children := range f {
+		fields[name] = children.clone()
+	}
+	return fields
+}
+
+// allFields selects every field of the struct type t whole
+func allFields(t reflect.Type) FieldSet {
+	fields := FieldSet{}
+	t = elemType(t)
+	if t.Kind() != reflect.Struct {
+		return fields
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && tag == "" {
+			for name := range allFields(field.Type) {
+				fields[name] = nil
+			}
+			continue
+		}
+		if tag == "" {
+			tag = field.Name
+		}
+		fields[tag] = nil
+	}
+	return fields
+}
+
+// Select returns data with only the selected fields, applied to each item of a list. A nil
+// set returns data unchanged.
+func (f FieldSet) Select(data interface{}) (interface{}, error) {
+	if f == nil {
+		return data, nil
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber() // keep IDs and amounts exactly as encoded
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return f.prune(value), nil
+}
+
+func (f FieldSet) prune(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		for i := range v {
+			v[i] = f.prune(v[i])
+		}
+		return v
+	case map[string]interface{}:
+		selected := make(map[string]interface{}, len(f))
+		for name, children := range f {
+			field, ok := v[name]
+			if !ok {
+				continue // omitted when empty
+			}
+			if children != nil {
+				field = children.prune(field)
+			}
+			selected[name] = field
+		}
+		return selected
+	}
+	return value
+}
+
+// checkField reports whether path names a field of t, following relations and lists
+func checkField(t reflect.Type, path string) error {
+	for _, name := range strings.Split(path, ".") {
+		t = elemType(t)
+		if t.Kind() != reflect.Struct {
+			return fmt.Errorf("unknown field %q", path)
+		}
+		field, ok := jsonField(t, name)
+		if !ok {
+			return fmt.Errorf("unknown field %q", path)
+		}
+		t = field
+	}
+	return nil
+}
+
+// jsonField finds the type of the field of struct t encoded as name, including the fields of
+// embedded structs
+func jsonField(t reflect.Type, name string) (reflect.Type, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && tag == "" {
+			if embedded := elemType(field.Type); embedded.Kind() == reflect.Struct {
+				if found, ok := jsonField(embedded, name); ok {
+					return found, true
+				}
+			}
+			continue
+		}
+		if tag == "" {
+			tag = field.Name
+		}
+		if tag == name {
+			return field.Type, true
+		}
+	}
+	return nil, false
+}
+
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t
+}
+
+// JSONFields responds with the selected fields of data
+func JSONFields(c *gin.Context, status int, data interface{}, fields FieldSet) {
+	selected, err := fields.Select(data)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "failed to encode response")
+		return
+	}
+	JSON(c, status, selected)
+}
+
+// PaginatedFields responds with the selected fields of each item of a page of a listing
+func PaginatedFields(c *gin.Context, status int, data interface{}, fields FieldSet, page, limit int, total int64) {
+	selected, err := fields.Select(data)
+	if err != nil {
+		Error(c, http.StatusInternalServerError, "failed to encode response")
+		return
+	}
+	Paginated(c, status, selected, page, limit, total)
+}
@@ -1,6 +1,9 @@
 package response
 
 import (
+	"api/pkg/money"
+	"encoding/json"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -8,12 +11,32 @@ import (
 
 // Auth responses
 type UserResponse struct {
-	ID        uint   `json:"id"`
-	Email     string `json:"email"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Phone     string `json:"phone"`
-	IsAdmin   bool   `json:"is_admin"`
+	ID             uint   `json:"id"`
+	Email          string `json:"email"`
+	FirstName      string `json:"first_name"`
+	LastName       string `json:"last_name"`
+	Phone          string `json:"phone"`
+	IsAdmin        bool   `json:"is_admin"`
+	MembershipTier string `json:"membership_tier,omitempty"`
+	TenantID       *uint  `json:"tenant_id,omitempty"` // set for admins scoped to one organizer
+}
+
+// PolicyVersionsResponse lists the current policy versions users must accept
+type PolicyVersionsResponse struct {
+	TermsVersion   string `json:"terms_of_service_version,omitempty"`
+	PrivacyVersion string `json:"privacy_policy_version,omitempty"`
+}
+
+type PolicyAcceptanceResponse struct {
+	Policy     string    `json:"policy"`
+	Version    string    `json:"version"`
+	IPAddress  string    `json:"ip_address"`
+	AcceptedAt time.Time `json:"accepted_at"`
+}
+
+// AccountDeletionResponse tells when a deleted account will be anonymized
+type AccountDeletionResponse struct {
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
 }
 
 type LoginResponse struct {
@@ -33,6 +56,13 @@ type VenueResponse struct {
 	Columns     int    `json:"columns"`
 	Capacity    int    `json:"capacity"` // calculated as rows * columns
 	Description string `json:"description"`
+	// Custom fields
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	// Default sales cutoff of the venue's events
+	SalesCloseMinutesBeforeStart int      `json:"sales_close_minutes_before_start"`
+	Latitude                     *float64 `json:"latitude,omitempty"`
+	Longitude                    *float64 `json:"longitude,omitempty"`
+	Enrichments                  []string `json:"enrichments,omitempty"` // enrichers adding outside data to event details
 }
 
 type VenueDetailResponse struct {
@@ -50,55 +80,708 @@ type EventResponse struct {
 	EndTime        time.Time     `json:"end_time"`
 	Capacity       int           `json:"capacity"`
 	AvailableSeats int           `json:"available_seats"`
-	Price          float64       `json:"price"`
+	Price          money.Money   `json:"price"`
 	EventType      string        `json:"event_type"`
 	Status         string        `json:"status"`
 	IsHighDemand   bool          `json:"is_high_demand"`
+	Sandbox        bool          `json:"sandbox,omitempty"` // rehearsal copy of an event: bookings take no payment
+	// Organizer branding for white-label storefronts
+	Organizer *OrganizerResponse     `json:"organizer,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
 }
 
 type EventDetailResponse struct {
 	EventResponse
-	Seats []SeatResponse `json:"seats,omitempty"`
+	Terms        string `json:"terms,omitempty"`
+	TermsVersion string `json:"terms_version,omitempty"` // send with accept_terms when creating a booking intent
+	// Attendee details to collect when confirming a booking
+	MinimumAge      int            `json:"minimum_age,omitempty"`
+	RequireFullName bool           `json:"require_full_name,omitempty"`
+	RequireIDNumber bool           `json:"require_id_number,omitempty"`
+	SaleCountries   []string       `json:"sale_countries,omitempty"` // intents can only be created from these countries
+	Seats           []SeatResponse `json:"seats,omitempty"`
+	// Seats can be booked until then; it precedes start_time by the event's sales cutoff
+	SalesCloseAt time.Time `json:"sales_close_at"`
+	DoorSales    bool      `json:"door_sales,omitempty"` // seats are also sold at the door until the event ends
+	DoorsOpenAt  time.Time `json:"doors_open_at"`
+	// Tickets not checked in by then lose their seat, which goes back on sale at the door
+	NoShowReleaseAt     *time.Time `json:"no_show_release_at,omitempty"`
+	NoShowReleaseRefund bool       `json:"no_show_release_refund,omitempty"` // released tickets are refunded
+	// Free events only: percentage of capacity the waitlist is booked into once sold out
+	OversellPercent int `json:"oversell_percent,omitempty"`
+	// Free events only: confirmed tickets one user can hold, 0 for the configured default
+	TicketLimit int `json:"ticket_limit,omitempty"`
+	// Posts of the organizer: announcements newest first, FAQs in their order
+	Announcements []EventPostResponse `json:"announcements,omitempty"`
+	FAQs          []EventPostResponse `json:"faqs,omitempty"`
+	// Outside data from the enrichers the venue enables, by enricher, e.g. the weather forecast
+	Enrichments map[string]interface{} `json:"enrichments,omitempty"`
+}
+
+type EventPostResponse struct {
+	ID        uint      `json:"id"`
+	EventID   uint      `json:"event_id"`
+	Kind      string    `json:"kind"`
+	Title     string    `json:"title"` // the question of an FAQ
+	Body      string    `json:"body"`  // the answer of an FAQ
+	Position  int       `json:"position,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // Seat responses
 type SeatResponse struct {
-	ID          uint    `json:"id"`
-	Row         int     `json:"row"`
-	Column      int     `json:"column"`
-	SeatType    string  `json:"seat_type"`
-	Price       float64 `json:"price"`
-	IsAvailable bool    `json:"is_available"`
-	IsLocked    bool    `json:"is_locked"`
+	ID                uint        `json:"id"`
+	Row               int         `json:"row"`
+	Column            int         `json:"column"`
+	SeatType          string      `json:"seat_type"`
+	Price             money.Money `json:"price"`
+	IsAvailable       bool        `json:"is_available"`
+	IsLocked          bool        `json:"is_locked"`
+	IsAccessible      bool        `json:"is_accessible"`
+	IsCompanion       bool        `json:"is_companion"`
+	CompanionOfSeatID *uint       `json:"companion_of_seat_id,omitempty"`
 }
 
 // Booking responses
 type BookingIntentResponse struct {
-	ID     uint          `json:"id"`
-	Event  EventResponse `json:"event"`
-	Seat   SeatResponse  `json:"seat"`
-	Status string        `json:"status"`
+	ID        uint          `json:"id"`
+	Event     EventResponse `json:"event"`
+	Seat      SeatResponse  `json:"seat"`
+	Status    string        `json:"status"`
+	ExpiresAt time.Time     `json:"expires_at"` // the seat is released if the booking isn't confirmed by then
+	// Free events are booked as soon as the intent is created, without a payment step
+	Booking *BookingResponse `json:"booking,omitempty"`
 }
 
 type BookingResponse struct {
-	ID            uint          `json:"id"`
-	Event         EventResponse `json:"event"`
-	Seat          SeatResponse  `json:"seat"`
-	Status        string        `json:"status"`
-	PaymentStatus string        `json:"payment_status"`
-	TotalAmount   float64       `json:"total_amount"`
-	BookedAt      time.Time     `json:"booked_at"`
-	CancelledAt   *time.Time    `json:"cancelled_at,omitempty"`
+	ID             uint          `json:"id"`
+	Event          EventResponse `json:"event"`
+	Seat           SeatResponse  `json:"seat"`
+	Status         string        `json:"status"`
+	PaymentStatus  string        `json:"payment_status"`
+	TotalAmount    money.Money   `json:"total_amount"`
+	BookedAt       time.Time     `json:"booked_at"`
+	CancelledAt    *time.Time    `json:"cancelled_at,omitempty"`
+	CheckedInAt    *time.Time    `json:"checked_in_at,omitempty"`
+	NoShow         bool          `json:"no_show"`
+	DiscountAmount money.Money   `json:"discount_amount,omitempty"`
+	PointsRedeemed int           `json:"points_redeemed,omitempty"`
+	PointsEarned   int           `json:"points_earned,omitempty"`
+	// Terms acceptance recorded for compliance
+	TermsVersion    string     `json:"terms_version,omitempty"`
+	TermsAcceptedAt *time.Time `json:"terms_accepted_at,omitempty"`
+	// Seat price charged before discounts and the pricing rule that set it
+	SeatPrice     money.Money `json:"seat_price"`
+	PricingRuleID *uint       `json:"pricing_rule_id,omitempty"`
+}
+
+// UpcomingBookingResponse is a booking on the upcoming bookings widget of app home screens
+type UpcomingBookingResponse struct {
+	BookingID uint      `json:"booking_id"`
+	EventID   uint      `json:"event_id"`
+	EventName string    `json:"event_name"`
+	VenueName string    `json:"venue_name"`
+	City      string    `json:"city"`
+	StartTime time.Time `json:"start_time"`
+	// StartsInSeconds counts down to the start, 0 once the event is in progress
+	StartsInSeconds int64  `json:"starts_in_seconds"`
+	Row             int    `json:"row"`
+	Column          int    `json:"column"`
+	SeatType        string `json:"seat_type"`
+	TicketURL       string `json:"ticket_url"`
+}
+
+// DoorSaleResponse is a seat sold at the box office
+type DoorSaleResponse struct {
+	BookingID     uint        `json:"booking_id"`
+	EventID       uint        `json:"event_id"`
+	UserID        uint        `json:"user_id"`
+	SeatID        uint        `json:"seat_id"`
+	Row           int         `json:"row"`
+	Column        int         `json:"column"`
+	SeatType      string      `json:"seat_type"`
+	TotalAmount   money.Money `json:"total_amount"`
+	PaymentStatus string      `json:"payment_status"`
+	PaymentID     string      `json:"payment_id"`
+	AttendeeName  string      `json:"attendee_name,omitempty"`
+	BookedAt      time.Time   `json:"booked_at"`
+	CheckedInAt   *time.Time  `json:"checked_in_at,omitempty"`
+}
+
+// CompResponse is a complimentary ticket
+type CompResponse struct {
+	BookingID    uint       `json:"booking_id"`
+	EventID      uint       `json:"event_id"`
+	UserID       uint       `json:"user_id"`
+	SeatID       uint       `json:"seat_id"`
+	Row          int        `json:"row"`
+	Column       int        `json:"column"`
+	SeatType     string     `json:"seat_type"`
+	Status       string     `json:"status"`
+	Reason       string     `json:"reason"`
+	AttendeeName string     `json:"attendee_name,omitempty"`
+	BookedAt     time.Time  `json:"booked_at"`
+	CheckedInAt  *time.Time `json:"checked_in_at,omitempty"`
+}
+
+// SettlementResponse is an organizer's statement for an event
+type SettlementResponse struct {
+	EventID         uint        `json:"event_id"`
+	TicketsSold     int64       `json:"tickets_sold"` // paid tickets, refunded ones included
+	GrossSales      money.Money `json:"gross_sales"`
+	RefundedTickets int64       `json:"refunded_tickets"`
+	Refunds         money.Money `json:"refunds"`
+	Chargebacks     money.Money `json:"chargebacks"`
+	CompTickets     int64       `json:"comp_tickets"`
+	FeePercent      float64     `json:"fee_percent"`
+	FeePerTicket    money.Money `json:"fee_per_ticket"`
+	PlatformFees    money.Money `json:"platform_fees"`
+	NetPayable      money.Money `json:"net_payable"`
+	Capacity        int64       `json:"capacity"`
+	HeldSeats       int64       `json:"held_seats"`
+	SellableSeats   int64       `json:"sellable_seats"`
+	SoldSeats       int64       `json:"sold_seats"`
+	// Frozen settlements no longer change; others are generated on every request
+	Frozen      bool       `json:"frozen"`
+	FrozenAt    *time.Time `json:"frozen_at,omitempty"`
+	GeneratedAt time.Time  `json:"generated_at"`
+}
+
+// TicketResponse is what gets printed on a ticket
+type TicketResponse struct {
+	BookingID    uint      `json:"booking_id"`
+	EventName    string    `json:"event_name"`
+	VenueName    string    `json:"venue_name"`
+	VenueAddress string    `json:"venue_address"`
+	StartTime    time.Time `json:"start_time"`
+	Row          int       `json:"row"`
+	Column       int       `json:"column"`
+	SeatType     string    `json:"seat_type"`
+	AttendeeName string    `json:"attendee_name,omitempty"`
+	AttendeeID   string    `json:"attendee_id,omitempty"` // masked, only the last 4 characters are shown
+	MinimumAge   int       `json:"minimum_age,omitempty"` // printed so door staff know to check age
+	// Token is encoded in the ticket's QR code, signed so scanners can validate it offline
+	Token      string     `json:"token,omitempty"`
+	ValidFrom  *time.Time `json:"valid_from,omitempty"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+}
+
+// TicketKeyResponse is a public key scanners validate ticket tokens with
+type TicketKeyResponse struct {
+	KeyID     string `json:"key_id"`
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"public_key"` // base64
+}
+
+// OfflineCheckInResult is the outcome of one scan uploaded by a scanner
+type OfflineCheckInResult struct {
+	BookingID   uint       `json:"booking_id,omitempty"`
+	Outcome     string     `json:"outcome"` // checked_in, duplicate or rejected
+	Reason      string     `json:"reason,omitempty"`
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+}
+
+// ScannerDeviceResponse is a registered door scanner. Token is only returned on registration.
+type ScannerDeviceResponse struct {
+	ID         uint       `json:"id"`
+	EventID    uint       `json:"event_id"`
+	Name       string     `json:"name"`
+	Gate       string     `json:"gate"`
+	Token      string     `json:"token,omitempty"`
+	CreatedBy  uint       `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+type HeartbeatResponse struct {
+	BookingIntentID  uint      `json:"booking_intent_id"`
+	Status           string    `json:"status"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	HeartbeatTimeout int       `json:"heartbeat_timeout_seconds"`
+}
+
+// ResumeTokenResponse carries the token another device redeems to continue the checkout
+type ResumeTokenResponse struct {
+	BookingIntentID uint      `json:"booking_intent_id"`
+	ResumeToken     string    `json:"resume_token"`
+	ExpiresAt       time.Time `json:"expires_at"` // the token can't be redeemed after this
+}
+
+type PaymentAttemptResponse struct {
+	AttemptNumber int       `json:"attempt_number"`
+	Reference     string    `json:"reference"`
+	Status        string    `json:"status"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type PaymentRetryResponse struct {
+	BookingIntentID  uint                     `json:"booking_intent_id"`
+	PaymentReference string                   `json:"payment_reference"`
+	ExpiresAt        time.Time                `json:"expires_at"`
+	Attempts         []PaymentAttemptResponse `json:"attempts"`
+}
+
+// MockChargeResponse carries what the client passes on to ConfirmBooking
+type MockChargeResponse struct {
+	PaymentID        string      `json:"payment_id"`
+	PaymentReference string      `json:"payment_reference"`
+	Provider         string      `json:"provider"`
+	Status           string      `json:"status"`
+	Amount           money.Money `json:"amount"`
+	Currency         string      `json:"currency,omitempty"`
+	CardBrand        string      `json:"card_brand"`
+	CardLast4        string      `json:"card_last4"`
+	DisputeOpened    bool        `json:"dispute_opened,omitempty"`
+	CreatedAt        time.Time   `json:"created_at"`
+}
+
+// Payment responses
+type PaymentTransactionEventResponse struct {
+	Status     string    `json:"status"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+type PaymentTransactionResponse struct {
+	ID                uint                              `json:"id"`
+	BookingID         *uint                             `json:"booking_id,omitempty"`
+	BookingIntentID   *uint                             `json:"booking_intent_id,omitempty"`
+	Provider          string                            `json:"provider"`
+	ProviderReference string                            `json:"provider_reference"`
+	Amount            money.Money                       `json:"amount"`
+	Currency          string                            `json:"currency"`
+	MethodType        string                            `json:"method_type,omitempty"`
+	MaskedMethod      string                            `json:"masked_method,omitempty"`
+	Status            string                            `json:"status"`
+	CreatedAt         time.Time                         `json:"created_at"`
+	Timeline          []PaymentTransactionEventResponse `json:"timeline"`
+}
+
+// SeatPriceChangeResponse is an entry of a seat's price history
+type SeatPriceChangeResponse struct {
+	SeatID        uint        `json:"seat_id"`
+	OldPrice      money.Money `json:"old_price"`
+	NewPrice      money.Money `json:"new_price"`
+	Source        string      `json:"source"`
+	PricingRuleID *uint       `json:"pricing_rule_id,omitempty"`
+	ChangedBy     *uint       `json:"changed_by,omitempty"`
+	ChangedAt     time.Time   `json:"changed_at"`
+}
+
+type CacheWarmResponse struct {
+	Events   []CachedEventResponse `json:"events"`
+	WarmedAt time.Time             `json:"warmed_at"`
+}
+
+type CachedEventResponse struct {
+	EventID        uint   `json:"event_id"`
+	Seats          int    `json:"seats"` // seats on sale in the cached seat map
+	AvailableSeats int64  `json:"available_seats"`
+	Error          string `json:"error,omitempty"` // why the event couldn't be cached
+}
+
+// SeatBitmapResponse is an event's seat availability, one bit per seat in row-major order
+// from the most significant bit of the first byte, set for seats on sale
+type SeatBitmapResponse struct {
+	EventID  uint   `json:"event_id"`
+	Rows     int    `json:"rows"`
+	Columns  int    `json:"columns"`
+	Version  int64  `json:"version"`
+	Encoding string `json:"encoding"` // base64
+	Bitmap   string `json:"bitmap"`
+}
+
+type SeatReleaseResponse struct {
+	ID            uint      `json:"id"`
+	RowStart      int       `json:"row_start"`
+	RowEnd        int       `json:"row_end"`
+	SeatsReleased int       `json:"seats_released"`
+	ReleasedBy    uint      `json:"released_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Artifact responses
+type ArtifactResponse struct {
+	ID          uint       `json:"id"`
+	Kind        string     `json:"kind"`
+	FileName    string     `json:"file_name"`
+	ContentType string     `json:"content_type"`
+	Size        int64      `json:"size"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type ArtifactDownloadResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Tenant responses
+type TenantResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	RateLimit int       `json:"rate_limit"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OrganizerResponse is the public branding profile of an organizer tenant
+type OrganizerResponse struct {
+	ID           uint            `json:"id"`
+	Slug         string          `json:"slug"`
+	DisplayName  string          `json:"display_name"`
+	LogoURL      string          `json:"logo_url,omitempty"`
+	SupportEmail string          `json:"support_email,omitempty"`
+	Colors       OrganizerColors `json:"colors"`
+}
+
+type OrganizerColors struct {
+	Primary   string `json:"primary,omitempty"`
+	Secondary string `json:"secondary,omitempty"`
+	Accent    string `json:"accent,omitempty"`
+}
+
+// OrganizerPageResponse is an organizer's public landing page: the branding profile plus
+// upcoming events and ratings
+type OrganizerPageResponse struct {
+	OrganizerResponse
+	UpcomingEvents []OrganizerEventResponse `json:"upcoming_events"`
+	Ratings        OrganizerRatingsResponse `json:"ratings"`
+}
+
+// OrganizerEventResponse is an event listed on an organizer page. Seat availability is left
+// out because pages are cached; sold out events say so in status.
+type OrganizerEventResponse struct {
+	ID           uint          `json:"id"`
+	Name         string        `json:"name"`
+	Venue        VenueResponse `json:"venue"`
+	StartTime    time.Time     `json:"start_time"`
+	EndTime      time.Time     `json:"end_time"`
+	Price        money.Money   `json:"price"`
+	EventType    string        `json:"event_type"`
+	Status       string        `json:"status"`
+	IsHighDemand bool          `json:"is_high_demand"`
+}
+
+type OrganizerRatingsResponse struct {
+	Average float64 `json:"average"` // 1 to 5, 0 without reviews
+	Count   int64   `json:"count"`
+}
+
+// EventReviewResponse is an attendee's rating of an event
+type EventReviewResponse struct {
+	ID        uint      `json:"id"`
+	BookingID uint      `json:"booking_id"`
+	EventID   uint      `json:"event_id"`
+	Rating    int       `json:"rating"`
+	Comment   string    `json:"comment,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Referral responses
+type ReferrerResponse struct {
+	ID             uint      `json:"id"`
+	Code           string    `json:"code"`
+	Kind           string    `json:"kind"` // user or partner
+	Name           string    `json:"name"`
+	Email          string    `json:"email,omitempty"`
+	UserID         *uint     `json:"user_id,omitempty"`
+	CommissionRate float64   `json:"commission_rate"` // percent
+	Active         bool      `json:"active"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ReferrerStatsResponse totals the bookings made with a referrer's code
+type ReferrerStatsResponse struct {
+	Bookings          int64       `json:"bookings"`
+	Cancelled         int64       `json:"cancelled"`
+	Revenue           money.Money `json:"revenue"`
+	CommissionPending money.Money `json:"commission_pending"` // events that haven't ended yet
+	CommissionPayable money.Money `json:"commission_payable"`
+	CommissionPaid    money.Money `json:"commission_paid"`
+}
+
+type ReferrerSummaryResponse struct {
+	ReferrerResponse
+	Stats ReferrerStatsResponse `json:"stats"`
+}
+
+type ReferralCommissionResponse struct {
+	ID            uint        `json:"id"`
+	ReferrerID    uint        `json:"referrer_id"`
+	BookingID     uint        `json:"booking_id"`
+	BookingStatus string      `json:"booking_status"` // commission is only owed on confirmed bookings
+	EventID       uint        `json:"event_id"`
+	EventName     string      `json:"event_name"`
+	Amount        money.Money `json:"amount"`
+	Rate          float64     `json:"rate"`
+	Commission    money.Money `json:"commission"`
+	Status        string      `json:"status"` // pending or paid
+	PaidAt        *time.Time  `json:"paid_at,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+type ReferrerDashboardResponse struct {
+	Referrers   []ReferrerSummaryResponse    `json:"referrers"`
+	Commissions []ReferralCommissionResponse `json:"commissions"` // latest first
+}
+
+// ReferrerPayoutResponse is the commission owed to, or just paid out to, a referrer
+type ReferrerPayoutResponse struct {
+	ReferrerID  uint        `json:"referrer_id"`
+	Code        string      `json:"code"`
+	Name        string      `json:"name"`
+	Email       string      `json:"email,omitempty"`
+	Commissions int64       `json:"commissions"`
+	Amount      money.Money `json:"amount"`
+}
+
+// Task responses
+type TaskResponse struct {
+	ID          uint       `json:"id"`
+	Kind        string     `json:"kind"`
+	Status      string     `json:"status"` // pending, running, completed or failed
+	Progress    int        `json:"progress"`
+	Total       int        `json:"total"`
+	Percent     int        `json:"percent"`
+	Error       string     `json:"error,omitempty"` // last attempt's error; set on retrying and failed tasks
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"max_attempts"`
+	ResultID    *uint      `json:"result_id,omitempty"` // e.g. the created event's ID
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// Dead letter responses
+type DeadLetterResponse struct {
+	ID            uint            `json:"id"`
+	Source        string          `json:"source"`  // notification, admin_notification or domain_event
+	Handler       string          `json:"handler"` // e.g. the consumer that failed to handle a domain event
+	Kind          string          `json:"kind"`
+	Status        string          `json:"status"` // dead, retried or discarded
+	Error         string          `json:"error"`
+	Retries       int             `json:"retries"`
+	Payload       json.RawMessage `json:"payload,omitempty"` // only when a single dead letter is inspected
+	CreatedAt     time.Time       `json:"created_at"`
+	LastRetriedAt *time.Time      `json:"last_retried_at,omitempty"`
+	ResolvedAt    *time.Time      `json:"resolved_at,omitempty"`
+}
+
+// Runtime settings responses
+type SettingResponse struct {
+	Key         string     `json:"key"`
+	Kind        string     `json:"kind"` // duration or number
+	Description string     `json:"description"`
+	Value       string     `json:"value"`
+	Default     string     `json:"default"`
+	Overridden  bool       `json:"overridden"`
+	Min         float64    `json:"min"` // in seconds for durations
+	Max         float64    `json:"max"`
+	UpdatedBy   *uint      `json:"updated_by,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+type SettingChangeResponse struct {
+	ID        uint      `json:"id"`
+	Key       string    `json:"key"`
+	OldValue  string    `json:"old_value,omitempty"` // empty when the default applied before
+	NewValue  string    `json:"new_value,omitempty"` // empty when reset to the default
+	ChangedBy uint      `json:"changed_by"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// Catalog sync responses
+type CatalogSyncRunResponse struct {
+	ID              uint                       `json:"id"`
+	Status          string                     `json:"status"` // succeeded or failed
+	VenuesCreated   int                        `json:"venues_created"`
+	VenuesUpdated   int                        `json:"venues_updated"`
+	VenuesUnchanged int                        `json:"venues_unchanged"`
+	EventsCreated   int                        `json:"events_created"`
+	EventsUpdated   int                        `json:"events_updated"`
+	EventsUnchanged int                        `json:"events_unchanged"`
+	EventsSkipped   int                        `json:"events_skipped"`     // already started
+	Rejected        []CatalogSyncErrorResponse `json:"rejected,omitempty"` // invalid feed entries, left out
+	Error           string                     `json:"error,omitempty"`
+	StartedAt       time.Time                  `json:"started_at"`
+	FinishedAt      time.Time                  `json:"finished_at"`
+}
+
+type CatalogSyncErrorResponse struct {
+	Kind       string `json:"kind"` // venue or event
+	ExternalID string `json:"external_id"`
+	Field      string `json:"field,omitempty"`
+	Message    string `json:"message"`
+}
+
+type CatalogSyncStatusResponse struct {
+	Enabled       bool                     `json:"enabled"` // whether a feed is configured
+	Source        string                   `json:"source"`
+	TenantID      uint                     `json:"tenant_id"`
+	Interval      string                   `json:"interval"`
+	LastSuccessAt *time.Time               `json:"last_success_at,omitempty"`
+	Runs          []CatalogSyncRunResponse `json:"runs"` // newest first
+}
+
+// Rate limit responses
+type AllowlistEntryResponse struct {
+	Type   string `json:"type"`
+	Value  string `json:"value"`
+	Source string `json:"source"` // config or runtime; only runtime entries can be removed
+}
+
+// BackpressureResponse reports a load-shedding limiter's queue depth and counters since start
+type BackpressureResponse struct {
+	Name        string `json:"name"`
+	Concurrency int    `json:"concurrency"`
+	InFlight    int    `json:"in_flight"`
+	Waiting     int64  `json:"waiting"` // queue depth
+	MaxQueue    int64  `json:"max_queue"`
+	Admitted    int64  `json:"admitted"`
+	Shed        int64  `json:"shed"`      // rejected with 503 because the queue was full
+	TimedOut    int64  `json:"timed_out"` // rejected with 429 after waiting too long
+}
+
+// RateLimitsResponse is the caller's standing against the rate limits that apply to them
+type RateLimitsResponse struct {
+	Allowlisted bool                      `json:"allowlisted"` // exempt from every limit below
+	Limits      []RateLimitStatusResponse `json:"limits"`
+}
+
+// RateLimitStatusResponse is the caller's usage of one rate limit policy. Policies of a scope
+// count the same requests against different budgets, depending on the route.
+type RateLimitStatusResponse struct {
+	Scope         string     `json:"scope"` // ip, user or tenant
+	Limit         int        `json:"limit"`
+	WindowSeconds int        `json:"window_seconds"`
+	Used          int        `json:"used"`
+	Remaining     int        `json:"remaining"`
+	ResetAt       *time.Time `json:"reset_at,omitempty"` // unset while nothing was counted in the window
+}
+
+// Presale responses
+type PresaleCodeResponse struct {
+	Code    string `json:"code"`
+	MaxUses int    `json:"max_uses"`
+	Uses    int    `json:"uses"`
+}
+
+type PresaleBatchResponse struct {
+	ID            uint                  `json:"id"`
+	EventID       uint                  `json:"event_id"`
+	Name          string                `json:"name"`
+	MaxUses       int                   `json:"max_uses"`
+	CreatedBy     uint                  `json:"created_by"`
+	CreatedAt     time.Time             `json:"created_at"`
+	CodeCount     int                   `json:"code_count"`
+	RedeemedCodes int                   `json:"redeemed_codes"`
+	Uses          int                   `json:"uses"`
+	Codes         []PresaleCodeResponse `json:"codes,omitempty"`
+}
+
+type SaleRegionOverrideResponse struct {
+	EventID   uint      `json:"event_id"`
+	UserID    uint      `json:"user_id"`
+	Note      string    `json:"note,omitempty"`
+	CreatedBy uint      `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Loyalty responses
+type LoyaltySummaryResponse struct {
+	Points           int    `json:"points"`
+	LifetimePoints   int    `json:"lifetime_points"`
+	Tier             string `json:"tier,omitempty"`
+	NextTier         string `json:"next_tier,omitempty"`
+	PointsToNextTier int    `json:"points_to_next_tier,omitempty"`
+}
+
+type LoyaltyTransactionResponse struct {
+	ID        uint      `json:"id"`
+	BookingID *uint     `json:"booking_id,omitempty"`
+	Points    int       `json:"points"`
+	Reason    string    `json:"reason"`
+	Balance   int       `json:"balance"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Dispute responses
+type DisputeEventResponse struct {
+	Status     string    `json:"status"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+type DisputeResponse struct {
+	ID                uint                   `json:"id"`
+	ProviderDisputeID string                 `json:"provider_dispute_id"`
+	BookingID         uint                   `json:"booking_id"`
+	EventID           uint                   `json:"event_id"`
+	UserID            uint                   `json:"user_id"`
+	PaymentID         string                 `json:"payment_id"`
+	Status            string                 `json:"status"`
+	Reason            string                 `json:"reason,omitempty"`
+	Amount            money.Money            `json:"amount"`
+	TicketRevoked     bool                   `json:"ticket_revoked"`
+	OpenedAt          time.Time              `json:"opened_at"`
+	ClosedAt          *time.Time             `json:"closed_at,omitempty"`
+	Events            []DisputeEventResponse `json:"events"`
+}
+
+type SupportCaseMessageResponse struct {
+	ID        uint      `json:"id"`
+	AuthorID  uint      `json:"author_id"`
+	FromStaff bool      `json:"from_staff"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SupportCaseResponse struct {
+	ID         uint                         `json:"id"`
+	BookingID  uint                         `json:"booking_id"`
+	EventID    uint                         `json:"event_id"`
+	UserID     uint                         `json:"user_id"`
+	Category   string                       `json:"category"`
+	Status     string                       `json:"status"`
+	CreatedAt  time.Time                    `json:"created_at"`
+	UpdatedAt  time.Time                    `json:"updated_at"`
+	ResolvedAt *time.Time                   `json:"resolved_at,omitempty"`
+	Messages   []SupportCaseMessageResponse `json:"messages,omitempty"` // left out of listings
 }
 
 // Queue responses
 type QueueResponse struct {
 	ID            uint       `json:"id"`
-	QueuePosition int        `json:"queue_position"`
+	EventID       uint       `json:"event_id"`
+	UserID        uint       `json:"user_id"`
+	QueuePosition int        `json:"queue_position"` // 0 once admitted
 	Status        string     `json:"status"`
 	JoinedAt      time.Time  `json:"joined_at"`
 	ActiveAt      *time.Time `json:"active_at,omitempty"`
-	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"` // end of the admission to book
+}
+
+type QueueLengthResponse struct {
+	EventID uint  `json:"event_id"`
+	Length  int64 `json:"length"`
+}
+
+// Autocomplete responses
+type VenueSuggestionResponse struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	City    string `json:"city"`
+	State   string `json:"state"`
+	Country string `json:"country"`
+}
+
+type CitySuggestionResponse struct {
+	City    string `json:"city"`
+	State   string `json:"state"`
+	Country string `json:"country"`
+	Venues  int64  `json:"venues"` // venues in the city, which ranks equally good matches
 }
 
 // Pagination responses
@@ -108,19 +791,45 @@ type PaginatedResponse struct {
 	Limit      int         `json:"limit"`
 	Total      int64       `json:"total"`
 	TotalPages int         `json:"total_pages"`
+	HasNext    bool        `json:"has_next"`
+	HasPrev    bool        `json:"has_prev"`
+	Links      PageLinks   `json:"links"`
+}
+
+// PageLinks are the paths of neighbouring pages of a listing, with the request's other query
+// parameters kept. Prev and Next are empty on the first and last pages.
+type PageLinks struct {
+	Self  string `json:"self"`
+	First string `json:"first"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last"`
 }
 
 // Analytics responses
 type EventStatsResponse struct {
-	EventID             uint    `json:"event_id"`
-	EventName           string  `json:"event_name"`
-	TotalSeats          int64   `json:"total_seats"`
-	BookedSeats         int64   `json:"booked_seats"`
-	LockedSeats         int64   `json:"locked_seats"`
-	AvailableSeats      int64   `json:"available_seats"`
-	CapacityUtilization float64 `json:"capacity_utilization"`
-	TotalRevenue        float64 `json:"total_revenue"`
-	BookingRate         float64 `json:"booking_rate"`
+	EventID             uint        `json:"event_id"`
+	EventName           string      `json:"event_name"`
+	TotalSeats          int64       `json:"total_seats"`
+	BookedSeats         int64       `json:"booked_seats"`
+	LockedSeats         int64       `json:"locked_seats"`
+	AvailableSeats      int64       `json:"available_seats"`
+	CapacityUtilization float64     `json:"capacity_utilization"`
+	TotalRevenue        money.Money `json:"total_revenue"`
+	BookingRate         float64     `json:"booking_rate"`
+	CheckedIn           int64       `json:"checked_in"`
+	NoShows             int64       `json:"no_shows"`
+	NoShowRate          float64     `json:"no_show_rate"`
+	// Booked seats and revenue split into online sales and box-office door sales
+	OnlineSeats   int64       `json:"online_seats"`
+	OnlineRevenue money.Money `json:"online_revenue"`
+	DoorSeats     int64       `json:"door_seats"`
+	DoorRevenue   money.Money `json:"door_revenue"`
+	CompSeats     int64       `json:"comp_seats"` // complimentary tickets, included in booked seats and attendance
+	// Oversell places of free events on top of total_seats, and those booked from the waitlist,
+	// which are included in booked seats and attendance
+	OversellPlaces int64 `json:"oversell_places"`
+	OversellSeats  int64 `json:"oversell_seats"`
 }
 
 // Waitlist responses
@@ -131,6 +840,7 @@ type WaitlistResponse struct {
 	Status     string     `json:"status"`
 	JoinedAt   time.Time  `json:"joined_at"`
 	NotifiedAt *time.Time `json:"notified_at,omitempty"`
+	Tier       string     `json:"tier,omitempty"`
 }
 
 // Notification responses
@@ -143,10 +853,25 @@ type NotificationResponse struct {
 	ExpiresAt time.Time `json:"expires_at"`
 }
 
+// Status responses
+type HealthResponse struct {
+	Status        string     `json:"status"` // ok, degraded while Redis is unavailable, or draining during shutdown
+	Redis         string     `json:"redis"`  // up or unavailable
+	DegradedSince *time.Time `json:"degraded_since,omitempty"`
+}
+
+type ServiceStatusResponse struct {
+	Degraded bool   `json:"degraded"` // clients show Banner while set
+	Banner   string `json:"banner,omitempty"`
+	// Features lists what works differently while degraded
+	Features []string `json:"features,omitempty"`
+}
+
 // Generic responses
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message,omitempty"`
+	Code    string `json:"code,omitempty"` // machine readable, for errors clients handle specifically
 }
 
 type SuccessResponse struct {
@@ -170,17 +895,55 @@ func Error(c *gin.Context, status int, err string, message ...string) {
 	c.JSON(status, response)
 }
 
+// ErrorWithCode responds with an error carrying a machine readable code
+func ErrorWithCode(c *gin.Context, status int, code string, err string) {
+	c.JSON(status, ErrorResponse{Error: err, Code: code})
+}
+
 func JSON(c *gin.Context, status int, data interface{}) {
 	c.JSON(status, data)
 }
 
+// Paginated responds with one page of a listing, with links to its neighbouring pages, and sets
+// X-Total-Count to the number of items in the whole listing
 func Paginated(c *gin.Context, status int, data interface{}, page, limit int, total int64) {
 	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	lastPage := max(totalPages, 1)
+
+	links := PageLinks{
+		Self:  pageLink(c, page),
+		First: pageLink(c, 1),
+		Last:  pageLink(c, lastPage),
+	}
+	hasPrev := page > 1
+	if hasPrev {
+		// A page past the end links back to the last page
+		links.Prev = pageLink(c, min(page-1, lastPage))
+	}
+	hasNext := page < totalPages
+	if hasNext {
+		links.Next = pageLink(c, page+1)
+	}
+
+	c.Header(TotalCountHeader, strconv.FormatInt(total, 10))
 	c.JSON(status, PaginatedResponse{
 		Data:       data,
 		Page:       page,
 		Limit:      limit,
 		Total:      total,
 		TotalPages: totalPages,
+		HasNext:    hasNext,
+		HasPrev:    hasPrev,
+		Links:      links,
 	})
 }
+
+// TotalCountHeader carries the number of items in a paginated listing
+const TotalCountHeader = "X-Total-Count"
+
+// pageLink is the path of the request with its page parameter set to page
+func pageLink(c *gin.Context, page int) string {
+	query := c.Request.URL.Query()
+	query.Set("page", strconv.Itoa(page))
+	return c.Request.URL.Path + "?" + query.Encode()
+}
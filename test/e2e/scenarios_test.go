@@ -0,0 +1,396 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"api/constants"
+	"api/pkg/client"
+	"api/pkg/request"
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestFlashSale has 500 users fight over 100 seats. No seat may be sold twice and
+// available_seats must never go negative or drift from the bookings actually made.
+func TestFlashSale(t *testing.T) {
+	const (
+		rows, columns = 10, 10
+		capacity      = rows * columns
+		buyers        = 500
+		attempts      = 5
+	)
+	ctx := context.Background()
+
+	admin := newAdmin(t)
+	eventID := createEvent(t, admin, "flash-sale", rows, columns)
+	users := registerUsers(t, "flash", buyers)
+
+	watch := watchAvailability(admin, eventID)
+
+	var sold atomic.Int64
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, u := range users {
+		wg.Add(1)
+		go func(u *user) {
+			defer wg.Done()
+			<-start
+			for i := 0; i < attempts; i++ {
+				seats, err := u.client.GetAvailableSeats(ctx, eventID, request.SeatFilterRequest{})
+				if err != nil || len(seats) == 0 {
+					return
+				}
+				seat := seats[rand.Intn(len(seats))]
+				if _, err := book(ctx, u.client, seat.ID); err == nil {
+					sold.Add(1)
+					return
+				}
+			}
+		}(u)
+	}
+	close(start)
+	wg.Wait()
+
+	minAvailable := watch.Stop(t)
+	if minAvailable < 0 {
+		t.Errorf("available_seats went negative: %d", minAvailable)
+	}
+
+	seats := confirmedSeats(t, users, eventID)
+	booked := 0
+	for seatID, count := range seats {
+		booked += count
+		if count > 1 {
+			t.Errorf("seat %d was sold %d times", seatID, count)
+		}
+	}
+	if booked > capacity {
+		t.Errorf("%d bookings for %d seats", booked, capacity)
+	}
+	if int64(booked) != sold.Load() {
+		t.Errorf("server has %d confirmed bookings, clients saw %d confirmations", booked, sold.Load())
+	}
+
+	event, err := admin.GetEvent(ctx, eventID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.AvailableSeats != capacity-booked {
+		t.Errorf("available_seats is %d, want %d after %d bookings", event.AvailableSeats, capacity-booked, booked)
+	}
+	open, err := admin.GetAvailableSeats(ctx, eventID, request.SeatFilterRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, seat := range open {
+		if seats[seat.ID] > 0 {
+			t.Errorf("booked seat %d is listed as available", seat.ID)
+		}
+	}
+	t.Logf("%d users booked %d of %d seats; available_seats bottomed out at %d", buyers, booked, capacity, minAvailable)
+}
+
+// TestWaitlistOrder fills a small event, then checks waitlist positions follow join order,
+// for sequential and concurrent joins and after someone leaves
+func TestWaitlistOrder(t *testing.T) {
+	const (
+		sequential = 10
+		concurrent = 20
+	)
+	ctx := context.Background()
+
+	admin := newAdmin(t)
+	eventID := createEvent(t, admin, "waitlist", 1, 2)
+	buyers := registerUsers(t, "waitlist-buyer", 2)
+	seats, err := admin.GetAvailableSeats(ctx, eventID, request.SeatFilterRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bookingIDs := make([]uint, len(seats))
+	for i, seat := range seats {
+		booking, err := book(ctx, buyers[i].client, seat.ID)
+		if err != nil {
+			t.Fatalf("failed to sell out the event: %v", err)
+		}
+		bookingIDs[i] = booking.ID
+	}
+
+	waiters := registerUsers(t, "waiter", sequential+concurrent)
+
+	t.Run("sequential joins keep join order", func(t *testing.T) {
+		for i, u := range waiters[:sequential] {
+			entry, err := u.client.JoinWaitlist(ctx, eventID)
+			if err != nil {
+				t.Fatalf("waiter %d failed to join: %v", i, err)
+			}
+			if entry.Position != i+1 {
+				t.Errorf("waiter %d joined at position %d, want %d", i, entry.Position, i+1)
+			}
+		}
+	})
+
+	t.Run("concurrent joins get distinct positions in join order", func(t *testing.T) {
+		var wg sync.WaitGroup
+		errs := make(chan error, concurrent)
+		for _, u := range waiters[sequential:] {
+			wg.Add(1)
+			go func(u *user) {
+				defer wg.Done()
+				if _, err := u.client.JoinWaitlist(ctx, eventID); err != nil {
+					errs <- err
+				}
+			}(u)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Fatalf("concurrent join failed: %v", err)
+		}
+
+		type joined struct {
+			position int
+			joinedAt time.Time
+		}
+		entries := make([]joined, 0, len(waiters))
+		for _, u := range waiters {
+			entry, err := u.client.GetWaitlistPosition(ctx, eventID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			entries = append(entries, joined{entry.Position, entry.JoinedAt})
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].position < entries[j].position })
+		for i, entry := range entries {
+			if entry.position != i+1 {
+				t.Fatalf("positions are not contiguous: got %d at index %d", entry.position, i)
+			}
+			if i > 0 && entry.joinedAt.Before(entries[i-1].joinedAt) {
+				t.Errorf("position %d joined before position %d", entry.position, entries[i-1].position)
+			}
+		}
+	})
+
+	t.Run("leaving moves everyone behind up by one", func(t *testing.T) {
+		before := make(map[uint]int)
+		for _, u := range waiters {
+			entry, err := u.client.GetWaitlistPosition(ctx, eventID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			before[u.id] = entry.Position
+		}
+
+		leaver := waiters[2]
+		if err := leaver.client.LeaveWaitlist(ctx, eventID); err != nil {
+			t.Fatal(err)
+		}
+		for _, u := range waiters {
+			if u == leaver {
+				continue
+			}
+			entry, err := u.client.GetWaitlistPosition(ctx, eventID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := before[u.id]
+			if want > before[leaver.id] {
+				want--
+			}
+			if entry.Position != want {
+				t.Errorf("user %d moved from %d to %d, want %d", u.id, before[u.id], entry.Position, want)
+			}
+		}
+	})
+
+	t.Run("cancellation promotes the head of the waitlist", func(t *testing.T) {
+		if err := buyers[0].client.CancelBooking(ctx, bookingIDs[0]); err != nil {
+			t.Fatal(err)
+		}
+		deadline := time.Now().Add(30 * time.Second)
+		for {
+			entry, err := waiters[0].client.GetWaitlistPosition(ctx, eventID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if entry.Status == "notified" {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("the first waiter was not notified after a cancellation")
+			}
+			time.Sleep(time.Second)
+		}
+		second, err := waiters[1].client.GetWaitlistPosition(ctx, eventID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if second.Status != "waiting" {
+			t.Errorf("only one seat was freed, but the second waiter is %s", second.Status)
+		}
+	})
+}
+
+// TestConcurrentConfirmations confirms one paid intent from many requests at once;
+// exactly one booking may come out of it
+func TestConcurrentConfirmations(t *testing.T) {
+	const confirmations = 10
+	ctx := context.Background()
+
+	admin := newAdmin(t)
+	eventID := createEvent(t, admin, "double-confirm", 1, 1)
+	users := registerUsers(t, "double-confirm", 1)
+	c := users[0].client
+
+	seats, err := c.GetAvailableSeats(ctx, eventID, request.SeatFilterRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	intent, err := c.CreateBookingIntent(ctx, request.CreateBookingIntentRequest{SeatID: seats[0].ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	paid, err := charge(ctx, c, intent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var confirmed atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < confirmations; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.ConfirmBooking(ctx, confirmRequest(intent.ID, paid)); err == nil {
+				confirmed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if confirmed.Load() != 1 {
+		t.Errorf("%d of %d confirmations succeeded, want exactly 1", confirmed.Load(), confirmations)
+	}
+	if seats := confirmedSeats(t, users, eventID); seats[intent.Seat.ID] != 1 {
+		t.Errorf("seat has %d confirmed bookings, want 1", seats[intent.Seat.ID])
+	}
+}
+
+// TestConfirmCancelRace confirms and cancels the same intent at once. Exactly one of the two
+// may win, and the seat ends up either booked or free again, never both or neither.
+func TestConfirmCancelRace(t *testing.T) {
+	const rounds = 20
+	ctx := context.Background()
+
+	admin := newAdmin(t)
+	eventID := createEvent(t, admin, "confirm-cancel", 4, 5)
+	users := registerUsers(t, "confirm-cancel", rounds)
+	seats, err := admin.GetAvailableSeats(ctx, eventID, request.SeatFilterRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for round, u := range users {
+		intent, err := u.client.CreateBookingIntent(ctx, request.CreateBookingIntentRequest{SeatID: seats[round].ID})
+		if err != nil {
+			t.Fatalf("round %d: %v", round, err)
+		}
+		paid, err := charge(ctx, u.client, intent)
+		if err != nil {
+			t.Fatalf("round %d: %v", round, err)
+		}
+
+		var confirmErr, cancelErr error
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, confirmErr = u.client.ConfirmBooking(ctx, confirmRequest(intent.ID, paid))
+		}()
+		go func() {
+			defer wg.Done()
+			cancelErr = u.client.CancelBookingIntent(ctx, intent.ID)
+		}()
+		wg.Wait()
+
+		if (confirmErr == nil) == (cancelErr == nil) {
+			t.Errorf("round %d: confirm error %v, cancel error %v; exactly one should succeed", round, confirmErr, cancelErr)
+			continue
+		}
+
+		booked := confirmedSeats(t, []*user{u}, eventID)[intent.Seat.ID] == 1
+		open, err := u.client.GetAvailableSeats(ctx, eventID, request.SeatFilterRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		free := false
+		for _, seat := range open {
+			free = free || seat.ID == intent.Seat.ID
+		}
+		if booked == free {
+			t.Errorf("round %d: seat booked=%t and free=%t after the race", round, booked, free)
+		}
+	}
+}
+
+// TestAbandonedCheckoutRace leaves a checkout without heartbeats while other users wait
+// for its seat. Exactly one of them gets it once the lock is released, and the abandoned
+// intent can no longer be confirmed.
+func TestAbandonedCheckoutRace(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits for the abandoned intent release job")
+	}
+	const (
+		contenders = 10
+		// heartbeat timeout plus a couple of runs of the release job
+		releaseTimeout = constants.IntentHeartbeatTimeout*time.Second + time.Minute
+	)
+	ctx := context.Background()
+
+	admin := newAdmin(t)
+	eventID := createEvent(t, admin, "abandoned", 1, 1)
+	users := registerUsers(t, "abandoned", contenders+1)
+	abandoner, rest := users[0], users[1:]
+
+	seats, err := admin.GetAvailableSeats(ctx, eventID, request.SeatFilterRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seatID := seats[0].ID
+	abandoned, err := abandoner.client.CreateBookingIntent(ctx, request.CreateBookingIntentRequest{SeatID: seatID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var winners atomic.Int64
+	var wg sync.WaitGroup
+	deadline := time.Now().Add(releaseTimeout)
+	for _, u := range rest {
+		wg.Add(1)
+		go func(c *client.Client) {
+			defer wg.Done()
+			for time.Now().Before(deadline) && winners.Load() == 0 {
+				if _, err := book(ctx, c, seatID); err == nil {
+					winners.Add(1)
+					return
+				}
+				time.Sleep(time.Second)
+			}
+		}(u.client)
+	}
+	wg.Wait()
+
+	if winners.Load() != 1 {
+		t.Fatalf("%d users booked the released seat, want exactly 1", winners.Load())
+	}
+	if _, err := payAndConfirm(ctx, abandoner.client, abandoned); err == nil {
+		t.Error("the abandoned intent was confirmed after its seat was released and sold")
+	}
+	if seats := confirmedSeats(t, users, eventID); seats[seatID] != 1 {
+		t.Errorf("seat has %d confirmed bookings, want 1", seats[seatID])
+	}
+}
@@ -0,0 +1,317 @@
+//go:build e2e
+
+// Package e2e runs scripted on-sale scenarios against a running stack, started with
+// docker-compose.yml in this directory, and checks the booking invariants hold under
+// concurrency. Point E2E_BASE_URL elsewhere to run it against another deployment that has
+// the mock payment provider enabled.
+package e2e
+
+import (
+	"api/constants"
+	"api/internal/payments"
+	"api/pkg/client"
+	"api/pkg/money"
+	"api/pkg/request"
+	"api/pkg/response"
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	baseURL = envOr("E2E_BASE_URL", "http://localhost:8080")
+	// runID keeps emails and venue names unique across runs against the same database
+	runID = strconv.FormatInt(time.Now().UnixNano(), 36)
+)
+
+const (
+	// registerWorkers bounds concurrent registrations while setting up users
+	registerWorkers   = 25
+	eventReadyTimeout = time.Minute
+)
+
+func TestMain(m *testing.M) {
+	if err := waitForAPI(2 * time.Minute); err != nil {
+		fmt.Fprintf(os.Stderr, "e2e: API at %s is not reachable: %v\n", baseURL, err)
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func waitForAPI(timeout time.Duration) error {
+	c := client.New(baseURL, client.WithHTTPClient(&http.Client{Timeout: 2 * time.Second}))
+	deadline := time.Now().Add(timeout)
+	for {
+		_, err := c.GetHealth(context.Background())
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+type user struct {
+	id     uint
+	client *client.Client
+}
+
+// registerUsers signs up and logs in n users in parallel
+func registerUsers(t *testing.T, prefix string, n int) []*user {
+	t.Helper()
+
+	users := make([]*user, n)
+	errs := make(chan error, n)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < registerWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				u, err := registerUser(fmt.Sprintf("%s-%d", prefix, i), false)
+				if err != nil {
+					errs <- err
+					continue
+				}
+				users[i] = u
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("failed to register users: %v", err)
+	}
+	return users
+}
+
+func registerUser(name string, admin bool) (*user, error) {
+	ctx := context.Background()
+	c := client.New(baseURL)
+	email := fmt.Sprintf("%s-%s@e2e.test", name, runID)
+	password := "e2e-password"
+
+	policies, err := c.GetPolicies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get policies: %w", err)
+	}
+	if _, err := c.Register(ctx, request.RegisterRequest{
+		Email:     email,
+		Password:  password,
+		FirstName: "E2E",
+		LastName:  name,
+		IsAdmin:   admin,
+		PolicyAcceptanceRequest: request.PolicyAcceptanceRequest{
+			AcceptPolicies: true,
+			TermsVersion:   policies.TermsVersion,
+			PrivacyVersion: policies.PrivacyVersion,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("register %s: %w", email, err)
+	}
+	login, err := c.Login(ctx, email, password)
+	if err != nil {
+		return nil, fmt.Errorf("login %s: %w", email, err)
+	}
+	return &user{id: login.User.ID, client: c}, nil
+}
+
+func newAdmin(t *testing.T) *client.Client {
+	t.Helper()
+	admin, err := registerUser("admin-"+strings.ToLower(t.Name()), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return admin.client
+}
+
+// createEvent creates a venue of rows x columns seats and an event on sale now, waiting
+// for its seats to be generated
+func createEvent(t *testing.T, admin *client.Client, name string, rows, columns int) uint {
+	t.Helper()
+	ctx := context.Background()
+
+	venueID, err := admin.CreateVenue(ctx, request.CreateVenueRequest{
+		Name:    fmt.Sprintf("%s venue %s", name, runID),
+		Address: "1 Scenario Street",
+		City:    "Testville",
+		State:   "TS",
+		Country: "US",
+		Rows:    rows,
+		Columns: columns,
+	})
+	if err != nil {
+		t.Fatalf("failed to create venue: %v", err)
+	}
+
+	start := time.Now().Add(30 * 24 * time.Hour)
+	taskID, err := admin.CreateEvent(ctx, request.CreateEventRequest{
+		Name:      fmt.Sprintf("%s %s", name, runID),
+		VenueID:   venueID,
+		StartTime: start,
+		EndTime:   start.Add(3 * time.Hour),
+		Price:     money.FromMajor(50),
+		EventType: constants.EventTypeConcert,
+	})
+	if err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+
+	deadline := time.Now().Add(eventReadyTimeout)
+	for time.Now().Before(deadline) {
+		task, err := admin.GetTask(ctx, taskID)
+		if err != nil {
+			t.Fatalf("failed to poll event creation: %v", err)
+		}
+		switch task.Status {
+		case constants.TaskStatusCompleted:
+			return *task.ResultID
+		case constants.TaskStatusFailed:
+			t.Fatalf("event creation failed: %s", task.Error)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	t.Fatalf("event creation did not finish within %s", eventReadyTimeout)
+	return 0
+}
+
+// book runs the whole checkout for a seat, cancelling the intent if payment or
+// confirmation fails so no lock is left behind
+func book(ctx context.Context, c *client.Client, seatID uint) (*response.BookingResponse, error) {
+	intent, err := c.CreateBookingIntent(ctx, request.CreateBookingIntentRequest{SeatID: seatID})
+	if err != nil {
+		return nil, err
+	}
+
+	booking, err := payAndConfirm(ctx, c, intent)
+	if err != nil {
+		c.CancelBookingIntent(ctx, intent.ID)
+		return nil, err
+	}
+	return booking, nil
+}
+
+func payAndConfirm(ctx context.Context, c *client.Client, intent *response.BookingIntentResponse) (*response.BookingResponse, error) {
+	charge, err := charge(ctx, c, intent)
+	if err != nil {
+		return nil, err
+	}
+	return c.ConfirmBooking(ctx, confirmRequest(intent.ID, charge))
+}
+
+// charge pays for the intent with the mock provider's always-succeeding card
+func charge(ctx context.Context, c *client.Client, intent *response.BookingIntentResponse) (*response.MockChargeResponse, error) {
+	status, err := c.GetBookingIntentStatus(ctx, intent.ID)
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateMockCharge(ctx, request.MockChargeRequest{
+		PaymentReference: status.PaymentIntentID,
+		Amount:           intent.Seat.Price,
+		Currency:         "USD",
+		CardNumber:       payments.CardSuccess,
+	})
+}
+
+func confirmRequest(intentID uint, charge *response.MockChargeResponse) request.ConfirmBookingRequest {
+	return request.ConfirmBookingRequest{
+		BookingIntentID: intentID,
+		PaymentID:       charge.PaymentID,
+		Provider:        charge.Provider,
+		Currency:        charge.Currency,
+		PaymentMethod: &request.PaymentMethodRequest{
+			Type:      "card",
+			CardBrand: charge.CardBrand,
+			CardLast4: charge.CardLast4,
+		},
+	}
+}
+
+// confirmedSeats returns the seats of the users' confirmed bookings for the event, as the
+// server reports them, with how many bookings hold each seat
+func confirmedSeats(t *testing.T, users []*user, eventID uint) map[uint]int {
+	t.Helper()
+	ctx := context.Background()
+
+	seats := make(map[uint]int)
+	for _, u := range users {
+		page, err := u.client.ListBookings(ctx, 1, 100)
+		if err != nil {
+			t.Fatalf("failed to list bookings of user %d: %v", u.id, err)
+		}
+		for _, booking := range page.Data {
+			if booking.Event.ID == eventID && booking.Status == constants.BookingStatusConfirmed {
+				seats[booking.Seat.ID]++
+			}
+		}
+	}
+	return seats
+}
+
+// availabilityWatch polls the event's available_seats while a scenario runs
+type availabilityWatch struct {
+	stop    chan struct{}
+	done    chan struct{}
+	min     int
+	samples int
+	err     error
+}
+
+func watchAvailability(c *client.Client, eventID uint) *availabilityWatch {
+	w := &availabilityWatch{stop: make(chan struct{}), done: make(chan struct{}), min: math.MaxInt}
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+			}
+			event, err := c.GetEvent(context.Background(), eventID)
+			if err != nil {
+				w.err = err
+				continue
+			}
+			w.samples++
+			if event.AvailableSeats < w.min {
+				w.min = event.AvailableSeats
+			}
+		}
+	}()
+	return w
+}
+
+// Stop ends the watch and returns the lowest available_seats seen
+func (w *availabilityWatch) Stop(t *testing.T) int {
+	t.Helper()
+	close(w.stop)
+	<-w.done
+	if w.samples == 0 {
+		t.Fatalf("available_seats was never sampled: %v", w.err)
+	}
+	return w.min
+}
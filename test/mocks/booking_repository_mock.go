@@ -0,0 +1,212 @@
+package mocks
+
+import (
+	"api/internal/entities"
+	"api/internal/repository"
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBookingRepository struct {
+	mock.Mock
+}
+
+// Ensure MockBookingRepository implements repository.BookingRepository
+var _ repository.BookingRepository = (*MockBookingRepository)(nil)
+
+func (m *MockBookingRepository) GetSeat(ctx context.Context, seatID uint) (*entities.Seat, error) {
+	args := m.Called(ctx, seatID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Seat), args.Error(1)
+}
+
+func (m *MockBookingRepository) CheckSaleEligibility(ctx context.Context, seat *entities.Seat, userID uint, presaleCode string) (*entities.PresaleCode, error) {
+	args := m.Called(ctx, seat, userID, presaleCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.PresaleCode), args.Error(1)
+}
+
+func (m *MockBookingRepository) CreateBookingIntent(ctx context.Context, intent *entities.BookingIntent, maxPending int) error {
+	args := m.Called(ctx, intent, maxPending)
+	return args.Error(0)
+}
+
+func (m *MockBookingRepository) LockSeatInDatabase(ctx context.Context, seatID, userID uint, lockedAt, expiresAt time.Time) error {
+	args := m.Called(ctx, seatID, userID, lockedAt, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockBookingRepository) GetBookingIntent(ctx context.Context, bookingIntentID uint, userID uint) (*entities.BookingIntent, error) {
+	args := m.Called(ctx, bookingIntentID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BookingIntent), args.Error(1)
+}
+
+func (m *MockBookingRepository) GetPendingIntent(ctx context.Context, bookingIntentID uint) (*entities.BookingIntent, error) {
+	args := m.Called(ctx, bookingIntentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BookingIntent), args.Error(1)
+}
+
+func (m *MockBookingRepository) GetUserPendingIntent(ctx context.Context, bookingIntentID uint, userID uint) (*entities.BookingIntent, error) {
+	args := m.Called(ctx, bookingIntentID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BookingIntent), args.Error(1)
+}
+
+func (m *MockBookingRepository) GetIntentDetails(ctx context.Context, bookingIntentID uint) (*entities.BookingIntent, error) {
+	args := m.Called(ctx, bookingIntentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BookingIntent), args.Error(1)
+}
+
+func (m *MockBookingRepository) GetPendingIntents(ctx context.Context, expiringAfter time.Time) ([]entities.BookingIntent, error) {
+	args := m.Called(ctx, expiringAfter)
+	r, _ := args.Get(0).([]entities.BookingIntent)
+	return r, args.Error(1)
+}
+
+func (m *MockBookingRepository) GetLatestSeatIntent(ctx context.Context, seatID uint) (*entities.BookingIntent, error) {
+	args := m.Called(ctx, seatID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BookingIntent), args.Error(1)
+}
+
+func (m *MockBookingRepository) GetDatabaseLockedSeats(ctx context.Context) ([]entities.Seat, error) {
+	args := m.Called(ctx)
+	r, _ := args.Get(0).([]entities.Seat)
+	return r, args.Error(1)
+}
+
+func (m *MockBookingRepository) ReleaseDatabaseLock(ctx context.Context, seat *entities.Seat) (bool, error) {
+	args := m.Called(ctx, seat)
+	return args.Get(0).(bool), args.Error(1)
+}
+
+func (m *MockBookingRepository) GetUpcomingEventIDs(ctx context.Context, now time.Time) ([]uint, error) {
+	args := m.Called(ctx, now)
+	r, _ := args.Get(0).([]uint)
+	return r, args.Error(1)
+}
+
+func (m *MockBookingRepository) ConfirmBooking(ctx context.Context, bookingIntentID uint, payment entities.PaymentDetails, attendee entities.AttendeeDetails, options entities.ConfirmOptions) (*entities.Booking, []entities.BookingIntent, error) {
+	args := m.Called(ctx, bookingIntentID, payment, attendee, options)
+	r0, _ := args.Get(0).(*entities.Booking)
+	r1, _ := args.Get(1).([]entities.BookingIntent)
+	return r0, r1, args.Error(2)
+}
+
+func (m *MockBookingRepository) CancelBookingIntent(ctx context.Context, bookingIntentID uint, userID uint) (*entities.BookingIntent, error) {
+	args := m.Called(ctx, bookingIntentID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BookingIntent), args.Error(1)
+}
+
+func (m *MockBookingRepository) CancelUserIntents(ctx context.Context, userID uint) ([]entities.BookingIntent, error) {
+	args := m.Called(ctx, userID)
+	r, _ := args.Get(0).([]entities.BookingIntent)
+	return r, args.Error(1)
+}
+
+func (m *MockBookingRepository) ExpireBookingIntent(ctx context.Context, bookingIntentID uint) (*entities.BookingIntent, error) {
+	args := m.Called(ctx, bookingIntentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BookingIntent), args.Error(1)
+}
+
+func (m *MockBookingRepository) ExpireIntents(ctx context.Context, before time.Time) ([]entities.BookingIntent, error) {
+	args := m.Called(ctx, before)
+	r, _ := args.Get(0).([]entities.BookingIntent)
+	return r, args.Error(1)
+}
+
+func (m *MockBookingRepository) ExpireEventIntents(ctx context.Context, eventID uint, createdBefore time.Time) ([]entities.BookingIntent, error) {
+	args := m.Called(ctx, eventID, createdBefore)
+	r, _ := args.Get(0).([]entities.BookingIntent)
+	return r, args.Error(1)
+}
+
+func (m *MockBookingRepository) SaveResumeToken(ctx context.Context, bookingIntentID uint, tokenHash string, expiresAt time.Time) error {
+	args := m.Called(ctx, bookingIntentID, tokenHash, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockBookingRepository) GetIntentByResumeToken(ctx context.Context, userID uint, tokenHash string) (*entities.BookingIntent, error) {
+	args := m.Called(ctx, userID, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BookingIntent), args.Error(1)
+}
+
+func (m *MockBookingRepository) RedeemResumeToken(ctx context.Context, bookingIntentID uint, tokenHash string) (bool, error) {
+	args := m.Called(ctx, bookingIntentID, tokenHash)
+	return args.Get(0).(bool), args.Error(1)
+}
+
+func (m *MockBookingRepository) StartPaymentAttempt(ctx context.Context, bookingIntentID uint, reference, failureReason string, maxAttempts int) (*entities.BookingIntent, error) {
+	args := m.Called(ctx, bookingIntentID, reference, failureReason, maxAttempts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BookingIntent), args.Error(1)
+}
+
+func (m *MockBookingRepository) GetConfirmedBooking(ctx context.Context, bookingID uint, userID uint) (*entities.Booking, error) {
+	args := m.Called(ctx, bookingID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Booking), args.Error(1)
+}
+
+func (m *MockBookingRepository) CancelBooking(ctx context.Context, bookingID uint) ([]entities.Booking, error) {
+	args := m.Called(ctx, bookingID)
+	r, _ := args.Get(0).([]entities.Booking)
+	return r, args.Error(1)
+}
+
+func (m *MockBookingRepository) GetUserBookings(ctx context.Context, userID uint, filter entities.BookingFilter, limit, offset int) ([]entities.Booking, int64, error) {
+	args := m.Called(ctx, userID, filter, limit, offset)
+	r, _ := args.Get(0).([]entities.Booking)
+	return r, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockBookingRepository) GetUpcomingBookings(ctx context.Context, userID uint, now time.Time, limit int) ([]entities.Booking, error) {
+	args := m.Called(ctx, userID, now, limit)
+	r, _ := args.Get(0).([]entities.Booking)
+	return r, args.Error(1)
+}
+
+func (m *MockBookingRepository) GetBookingByID(ctx context.Context, bookingID, userID uint) (*entities.Booking, error) {
+	args := m.Called(ctx, bookingID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Booking), args.Error(1)
+}
+
+func (m *MockBookingRepository) BackfillLockExpiry(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
@@ -2,7 +2,9 @@ package mocks
 
 import (
 	"api/internal/entities"
+	"api/internal/services"
 	"context"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -11,16 +13,16 @@ type MockBookingService struct {
 	mock.Mock
 }
 
-func (m *MockBookingService) CreateBookingIntent(ctx context.Context, userID, seatID uint) (*entities.BookingIntent, error) {
-	args := m.Called(ctx, userID, seatID)
+func (m *MockBookingService) CreateBookingIntent(ctx context.Context, userID, seatID uint, options entities.BookingIntentOptions) (*entities.BookingIntent, error) {
+	args := m.Called(ctx, userID, seatID, options)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entities.BookingIntent), args.Error(1)
 }
 
-func (m *MockBookingService) ConfirmBooking(ctx context.Context, bookingIntentID uint, paymentID string) (*entities.Booking, error) {
-	args := m.Called(ctx, bookingIntentID, paymentID)
+func (m *MockBookingService) ConfirmBooking(ctx context.Context, bookingIntentID uint, payment entities.PaymentDetails, attendee entities.AttendeeDetails, options entities.ConfirmOptions) (*entities.Booking, error) {
+	args := m.Called(ctx, bookingIntentID, payment, attendee, options)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -32,16 +34,59 @@ func (m *MockBookingService) CancelBookingIntent(ctx context.Context, bookingInt
 	return args.Error(0)
 }
 
+func (m *MockBookingService) HeartbeatBookingIntent(ctx context.Context, bookingIntentID uint, userID uint) (*entities.BookingIntent, error) {
+	args := m.Called(ctx, bookingIntentID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BookingIntent), args.Error(1)
+}
+
+func (m *MockBookingService) GetBookingIntentStatus(ctx context.Context, bookingIntentID uint, userID uint) (*services.BookingIntentStatus, error) {
+	args := m.Called(ctx, bookingIntentID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.BookingIntentStatus), args.Error(1)
+}
+
+func (m *MockBookingService) RetryPayment(ctx context.Context, bookingIntentID uint, userID uint, failureReason string) (*entities.BookingIntent, error) {
+	args := m.Called(ctx, bookingIntentID, userID, failureReason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BookingIntent), args.Error(1)
+}
+
+func (m *MockBookingService) IssueResumeToken(ctx context.Context, bookingIntentID uint, userID uint) (string, time.Time, error) {
+	args := m.Called(ctx, bookingIntentID, userID)
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockBookingService) ResumeBookingIntent(ctx context.Context, userID uint, token string) (*entities.BookingIntent, error) {
+	args := m.Called(ctx, userID, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BookingIntent), args.Error(1)
+}
+
 func (m *MockBookingService) CancelBooking(ctx context.Context, bookingID uint, userID uint) error {
 	args := m.Called(ctx, bookingID, userID)
 	return args.Error(0)
 }
 
-func (m *MockBookingService) GetUserBookings(ctx context.Context, userID uint, limit, offset int) ([]entities.Booking, int64, error) {
-	args := m.Called(ctx, userID, limit, offset)
+func (m *MockBookingService) GetUserBookings(ctx context.Context, userID uint, filter entities.BookingFilter, limit, offset int) ([]entities.Booking, int64, error) {
+	args := m.Called(ctx, userID, filter, limit, offset)
 	return args.Get(0).([]entities.Booking), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockBookingService) GetUpcomingBookings(ctx context.Context, userID uint, limit int) ([]entities.Booking, error) {
+	args := m.Called(ctx, userID, limit)
+	r, _ := args.Get(0).([]entities.Booking)
+	return r, args.Error(1)
+}
+
 func (m *MockBookingService) GetBookingByID(ctx context.Context, bookingID, userID uint) (*entities.Booking, error) {
 	args := m.Called(ctx, bookingID, userID)
 	if args.Get(0) == nil {
@@ -54,3 +99,24 @@ func (m *MockBookingService) CleanupExpiredIntents(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
 }
+
+func (m *MockBookingService) ReleaseAbandonedIntents(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockBookingService) InspectSeatLock(ctx context.Context, seatID uint) (*services.SeatLockInspection, error) {
+	args := m.Called(ctx, seatID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.SeatLockInspection), args.Error(1)
+}
+
+func (m *MockBookingService) PurgeEventIntents(ctx context.Context, eventID uint, createdBefore time.Time) (*services.IntentPurgeResult, error) {
+	args := m.Called(ctx, eventID, createdBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.IntentPurgeResult), args.Error(1)
+}
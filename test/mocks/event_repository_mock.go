@@ -0,0 +1,121 @@
+package mocks
+
+import (
+	"api/internal/entities"
+	"api/internal/repository"
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEventRepository struct {
+	mock.Mock
+}
+
+// Ensure MockEventRepository implements repository.EventRepository
+var _ repository.EventRepository = (*MockEventRepository)(nil)
+
+func (m *MockEventRepository) GetEvents(ctx context.Context, limit, offset int, eventType, city string, metadata map[string]string) ([]entities.Event, int64, error) {
+	args := m.Called(ctx, limit, offset, eventType, city, metadata)
+	r, _ := args.Get(0).([]entities.Event)
+	return r, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockEventRepository) GetEventByID(ctx context.Context, eventID uint) (*entities.Event, error) {
+	args := m.Called(ctx, eventID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Event), args.Error(1)
+}
+
+func (m *MockEventRepository) GetAvailableSeats(ctx context.Context, eventID uint, filter entities.SeatFilter) ([]entities.Seat, error) {
+	args := m.Called(ctx, eventID, filter)
+	r, _ := args.Get(0).([]entities.Seat)
+	return r, args.Error(1)
+}
+
+func (m *MockEventRepository) CountAvailableSeats(ctx context.Context, eventID uint) (int64, error) {
+	args := m.Called(ctx, eventID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockEventRepository) ListHotEvents(ctx context.Context, onSaleBefore time.Time, limit int) ([]uint, error) {
+	args := m.Called(ctx, onSaleBefore, limit)
+	r, _ := args.Get(0).([]uint)
+	return r, args.Error(1)
+}
+
+func (m *MockEventRepository) PrepareEvent(ctx context.Context, event *entities.Event) (*entities.Venue, error) {
+	args := m.Called(ctx, event)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Venue), args.Error(1)
+}
+
+func (m *MockEventRepository) CreateEvent(ctx context.Context, event *entities.Event, progress repository.SeatProgressFunc) error {
+	args := m.Called(ctx, event, progress)
+	return args.Error(0)
+}
+
+func (m *MockEventRepository) NewSandboxEvent(ctx context.Context, eventID uint) (*entities.Event, error) {
+	args := m.Called(ctx, eventID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Event), args.Error(1)
+}
+
+func (m *MockEventRepository) UpdateEvent(ctx context.Context, eventID uint, updates map[string]interface{}) (*entities.Event, error) {
+	args := m.Called(ctx, eventID, updates)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Event), args.Error(1)
+}
+
+func (m *MockEventRepository) DeleteEvent(ctx context.Context, eventID uint) error {
+	args := m.Called(ctx, eventID)
+	return args.Error(0)
+}
+
+func (m *MockEventRepository) GetEventStats(ctx context.Context, eventID uint) (map[string]interface{}, error) {
+	args := m.Called(ctx, eventID)
+	r, _ := args.Get(0).(map[string]interface{})
+	return r, args.Error(1)
+}
+
+func (m *MockEventRepository) SetSeatAccessibility(ctx context.Context, seatID uint, accessible bool, companionSeatIDs []uint) (*entities.Seat, []entities.Seat, error) {
+	args := m.Called(ctx, seatID, accessible, companionSeatIDs)
+	r0, _ := args.Get(0).(*entities.Seat)
+	r1, _ := args.Get(1).([]entities.Seat)
+	return r0, r1, args.Error(2)
+}
+
+func (m *MockEventRepository) ReleaseSeats(ctx context.Context, eventID uint, rowStart, rowEnd int, releasedBy uint) (*entities.SeatRelease, error) {
+	args := m.Called(ctx, eventID, rowStart, rowEnd, releasedBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.SeatRelease), args.Error(1)
+}
+
+func (m *MockEventRepository) ListReleases(ctx context.Context, eventID uint) ([]entities.SeatRelease, int64, error) {
+	args := m.Called(ctx, eventID)
+	r, _ := args.Get(0).([]entities.SeatRelease)
+	return r, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockEventRepository) UpdateSeatPrices(ctx context.Context, eventID uint, change entities.SeatPriceChange) ([]entities.SeatPriceHistory, error) {
+	args := m.Called(ctx, eventID, change)
+	r, _ := args.Get(0).([]entities.SeatPriceHistory)
+	return r, args.Error(1)
+}
+
+func (m *MockEventRepository) ListSeatPriceHistory(ctx context.Context, seatID uint) ([]entities.SeatPriceHistory, error) {
+	args := m.Called(ctx, seatID)
+	r, _ := args.Get(0).([]entities.SeatPriceHistory)
+	return r, args.Error(1)
+}
@@ -0,0 +1,144 @@
+package mocks
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEventService struct {
+	mock.Mock
+}
+
+// Ensure MockEventService implements services.EventServiceInterface
+var _ services.EventServiceInterface = (*MockEventService)(nil)
+
+func (m *MockEventService) GetEvents(ctx context.Context, limit, offset int, eventType, city string, metadata map[string]string) ([]entities.Event, int64, error) {
+	args := m.Called(ctx, limit, offset, eventType, city, metadata)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]entities.Event), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockEventService) GetEventByID(ctx context.Context, eventID uint) (*entities.Event, error) {
+	args := m.Called(ctx, eventID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Event), args.Error(1)
+}
+
+func (m *MockEventService) GetAvailableSeats(ctx context.Context, eventID uint, filter entities.SeatFilter) ([]entities.Seat, error) {
+	args := m.Called(ctx, eventID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.Seat), args.Error(1)
+}
+
+func (m *MockEventService) SetSeatAccessibility(ctx context.Context, seatID uint, accessible bool, companionSeatIDs []uint) (*entities.Seat, []entities.Seat, error) {
+	args := m.Called(ctx, seatID, accessible, companionSeatIDs)
+	var seat *entities.Seat
+	if args.Get(0) != nil {
+		seat = args.Get(0).(*entities.Seat)
+	}
+	var companions []entities.Seat
+	if args.Get(1) != nil {
+		companions = args.Get(1).([]entities.Seat)
+	}
+	return seat, companions, args.Error(2)
+}
+
+func (m *MockEventService) ReleaseSeats(ctx context.Context, eventID uint, rowStart, rowEnd int, releasedBy uint) (*entities.SeatRelease, error) {
+	args := m.Called(ctx, eventID, rowStart, rowEnd, releasedBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.SeatRelease), args.Error(1)
+}
+
+func (m *MockEventService) ListReleases(ctx context.Context, eventID uint) ([]entities.SeatRelease, int64, error) {
+	args := m.Called(ctx, eventID)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]entities.SeatRelease), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockEventService) UpdateSeatPrices(ctx context.Context, eventID uint, change entities.SeatPriceChange) ([]entities.SeatPriceHistory, error) {
+	args := m.Called(ctx, eventID, change)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.SeatPriceHistory), args.Error(1)
+}
+
+func (m *MockEventService) ListSeatPriceHistory(ctx context.Context, seatID uint) ([]entities.SeatPriceHistory, error) {
+	args := m.Called(ctx, seatID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.SeatPriceHistory), args.Error(1)
+}
+
+func (m *MockEventService) GetAvailableSeatsCount(ctx context.Context, eventID uint) (int64, error) {
+	args := m.Called(ctx, eventID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockEventService) GetListingAvailability(ctx context.Context, events []entities.Event) map[uint]int64 {
+	args := m.Called(ctx, events)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(map[uint]int64)
+}
+
+func (m *MockEventService) WarmCache(ctx context.Context, eventIDs []uint) (*entities.CacheWarmResult, error) {
+	args := m.Called(ctx, eventIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.CacheWarmResult), args.Error(1)
+}
+
+func (m *MockEventService) CreateEvent(ctx context.Context, event *entities.Event, createdBy uint) (*entities.Task, error) {
+	args := m.Called(ctx, event, createdBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Task), args.Error(1)
+}
+
+func (m *MockEventService) CreateSandbox(ctx context.Context, eventID uint, onSaleAt, earlyAccessAt *time.Time, createdBy uint) (*entities.Task, error) {
+	args := m.Called(ctx, eventID, onSaleAt, earlyAccessAt, createdBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Task), args.Error(1)
+}
+
+func (m *MockEventService) UpdateEvent(ctx context.Context, eventID uint, updates map[string]interface{}) (*entities.Event, error) {
+	args := m.Called(ctx, eventID, updates)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Event), args.Error(1)
+}
+
+func (m *MockEventService) DeleteEvent(ctx context.Context, eventID uint) error {
+	args := m.Called(ctx, eventID)
+	return args.Error(0)
+}
+
+func (m *MockEventService) GetEventStats(ctx context.Context, eventID uint) (map[string]interface{}, error) {
+	args := m.Called(ctx, eventID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]interface{}), args.Error(1)
+}
@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	"api/internal/entities"
+	"api/internal/services"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFeedService struct {
+	mock.Mock
+}
+
+// Ensure MockFeedService implements services.FeedServiceInterface
+var _ services.FeedServiceInterface = (*MockFeedService)(nil)
+
+func (m *MockFeedService) Sitemap(ctx context.Context) (*entities.Sitemap, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Sitemap), args.Error(1)
+}
+
+func (m *MockFeedService) EventFeed(ctx context.Context, page int) (*entities.EventFeedPage, error) {
+	args := m.Called(ctx, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.EventFeedPage), args.Error(1)
+}
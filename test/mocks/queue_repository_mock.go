@@ -0,0 +1,116 @@
+package mocks
+
+import (
+	"api/internal/entities"
+	"api/internal/repository"
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockQueueRepository struct {
+	mock.Mock
+}
+
+// Ensure MockQueueRepository implements repository.QueueRepository
+var _ repository.QueueRepository = (*MockQueueRepository)(nil)
+
+func (m *MockQueueRepository) GetEntry(ctx context.Context, userID, eventID uint) (*entities.EventQueue, error) {
+	args := m.Called(ctx, userID, eventID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.EventQueue), args.Error(1)
+}
+
+func (m *MockQueueRepository) CreateEntry(ctx context.Context, entry *entities.EventQueue) (bool, error) {
+	args := m.Called(ctx, entry)
+	return args.Get(0).(bool), args.Error(1)
+}
+
+func (m *MockQueueRepository) CancelEntry(ctx context.Context, userID, eventID uint) (*entities.EventQueue, error) {
+	args := m.Called(ctx, userID, eventID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.EventQueue), args.Error(1)
+}
+
+func (m *MockQueueRepository) CompleteEntry(ctx context.Context, userID, eventID uint) (*entities.EventQueue, error) {
+	args := m.Called(ctx, userID, eventID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.EventQueue), args.Error(1)
+}
+
+func (m *MockQueueRepository) CountAhead(ctx context.Context, entry *entities.EventQueue) (int64, error) {
+	args := m.Called(ctx, entry)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQueueRepository) CountWaiting(ctx context.Context, eventID uint) (int64, error) {
+	args := m.Called(ctx, eventID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQueueRepository) GetWaiting(ctx context.Context, eventID uint) ([]entities.EventQueue, error) {
+	args := m.Called(ctx, eventID)
+	r, _ := args.Get(0).([]entities.EventQueue)
+	return r, args.Error(1)
+}
+
+func (m *MockQueueRepository) GetEventsToAdmit(ctx context.Context, now time.Time) ([]uint, error) {
+	args := m.Called(ctx, now)
+	r, _ := args.Get(0).([]uint)
+	return r, args.Error(1)
+}
+
+func (m *MockQueueRepository) GetChangedEvents(ctx context.Context, since time.Time) ([]uint, error) {
+	args := m.Called(ctx, since)
+	r, _ := args.Get(0).([]uint)
+	return r, args.Error(1)
+}
+
+func (m *MockQueueRepository) AdmitNext(ctx context.Context, eventID uint, maxActive int, now, expiresAt time.Time) ([]entities.EventQueue, error) {
+	args := m.Called(ctx, eventID, maxActive, now, expiresAt)
+	r, _ := args.Get(0).([]entities.EventQueue)
+	return r, args.Error(1)
+}
+
+func (m *MockQueueRepository) ExpireAdmissions(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQueueRepository) GetAdmittedUsers(ctx context.Context, now time.Time) ([]uint, error) {
+	args := m.Called(ctx, now)
+	r, _ := args.Get(0).([]uint)
+	return r, args.Error(1)
+}
+
+func (m *MockQueueRepository) Enqueue(ctx context.Context, eventID, userID uint, joinedAt time.Time) error {
+	args := m.Called(ctx, eventID, userID, joinedAt)
+	return args.Error(0)
+}
+
+func (m *MockQueueRepository) Dequeue(ctx context.Context, eventID uint, userIDs ...uint) error {
+	args := m.Called(ctx, eventID, userIDs)
+	return args.Error(0)
+}
+
+func (m *MockQueueRepository) Rank(ctx context.Context, eventID, userID uint) (int64, bool, error) {
+	args := m.Called(ctx, eventID, userID)
+	return args.Get(0).(int64), args.Get(1).(bool), args.Error(2)
+}
+
+func (m *MockQueueRepository) Length(ctx context.Context, eventID uint) (int64, error) {
+	args := m.Called(ctx, eventID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQueueRepository) Rebuild(ctx context.Context, eventID uint, waiting []entities.EventQueue) error {
+	args := m.Called(ctx, eventID, waiting)
+	return args.Error(0)
+}
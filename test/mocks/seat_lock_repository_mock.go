@@ -0,0 +1,78 @@
+package mocks
+
+import (
+	"api/internal/repository"
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSeatLockRepository struct {
+	mock.Mock
+}
+
+// Ensure MockSeatLockRepository implements repository.SeatLockRepository
+var _ repository.SeatLockRepository = (*MockSeatLockRepository)(nil)
+
+func (m *MockSeatLockRepository) LockSeat(ctx context.Context, eventID, seatID uint, userID uint, intentID string, expiresAt time.Time) error {
+	args := m.Called(ctx, eventID, seatID, userID, intentID, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockSeatLockRepository) UnlockSeat(ctx context.Context, eventID, seatID uint, userID uint, intentID string) error {
+	args := m.Called(ctx, eventID, seatID, userID, intentID)
+	return args.Error(0)
+}
+
+func (m *MockSeatLockRepository) IsLocked(ctx context.Context, eventID, seatID uint) (bool, string, error) {
+	args := m.Called(ctx, eventID, seatID)
+	return args.Get(0).(bool), args.Get(1).(string), args.Error(2)
+}
+
+func (m *MockSeatLockRepository) IsLockedByUser(ctx context.Context, eventID, seatID uint, userID uint) (bool, string, error) {
+	args := m.Called(ctx, eventID, seatID, userID)
+	return args.Get(0).(bool), args.Get(1).(string), args.Error(2)
+}
+
+func (m *MockSeatLockRepository) ExtendLock(ctx context.Context, eventID, seatID uint, userID uint, intentID string, expiresAt time.Time) error {
+	args := m.Called(ctx, eventID, seatID, userID, intentID, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockSeatLockRepository) AlignLock(ctx context.Context, eventID, seatID uint, userID uint, intentID string, expiresAt time.Time, tolerance time.Duration) (int, time.Duration, error) {
+	args := m.Called(ctx, eventID, seatID, userID, intentID, expiresAt, tolerance)
+	return args.Get(0).(int), args.Get(1).(time.Duration), args.Error(2)
+}
+
+func (m *MockSeatLockRepository) GetLockTTL(ctx context.Context, eventID, seatID uint) (time.Duration, error) {
+	args := m.Called(ctx, eventID, seatID)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
+
+func (m *MockSeatLockRepository) RecordHeartbeat(ctx context.Context, intentID uint, at time.Time) error {
+	args := m.Called(ctx, intentID, at)
+	return args.Error(0)
+}
+
+func (m *MockSeatLockRepository) GetStaleHeartbeats(ctx context.Context, before time.Time) ([]uint, error) {
+	args := m.Called(ctx, before)
+	r, _ := args.Get(0).([]uint)
+	return r, args.Error(1)
+}
+
+func (m *MockSeatLockRepository) ClearHeartbeat(ctx context.Context, intentID uint) error {
+	args := m.Called(ctx, intentID)
+	return args.Error(0)
+}
+
+func (m *MockSeatLockRepository) ListLocks(ctx context.Context, eventID uint) (map[uint]string, error) {
+	args := m.Called(ctx, eventID)
+	r, _ := args.Get(0).(map[uint]string)
+	return r, args.Error(1)
+}
+
+func (m *MockSeatLockRepository) CleanupExpiredLocks(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
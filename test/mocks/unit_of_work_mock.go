@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"api/internal/repository"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockUnitOfWork runs the work directly, without a transaction. Return an error from Do to
+// simulate a failed commit.
+type MockUnitOfWork struct {
+	mock.Mock
+}
+
+// Ensure MockUnitOfWork implements repository.UnitOfWork
+var _ repository.UnitOfWork = (*MockUnitOfWork)(nil)
+
+func (m *MockUnitOfWork) Do(ctx context.Context, work func(ctx context.Context) error) error {
+	if err := work(ctx); err != nil {
+		return err
+	}
+	args := m.Called(ctx)
+	return args.Error(0)
+}
@@ -0,0 +1,67 @@
+package mocks
+
+import (
+	"api/internal/entities"
+	"api/internal/repository"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+// Ensure MockUserRepository implements repository.UserRepository
+var _ repository.UserRepository = (*MockUserRepository)(nil)
+
+func (m *MockUserRepository) Register(ctx context.Context, email, plaintext, firstName, lastName, phone string, isAdmin bool) (*entities.User, error) {
+	args := m.Called(ctx, email, plaintext, firstName, lastName, phone, isAdmin)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Login(ctx context.Context, email, plaintext string) (*entities.User, error) {
+	args := m.Called(ctx, email, plaintext)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, userID uint) (*entities.User, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.User), args.Error(1)
+}
+
+func (m *MockUserRepository) SetMembershipTier(ctx context.Context, userID uint, tier string) (*entities.User, error) {
+	args := m.Called(ctx, userID, tier)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetAdmins(ctx context.Context) ([]entities.User, error) {
+	args := m.Called(ctx)
+	r, _ := args.Get(0).([]entities.User)
+	return r, args.Error(1)
+}
+
+func (m *MockUserRepository) ChangePassword(ctx context.Context, userID uint, current, plaintext string) (*entities.User, error) {
+	args := m.Called(ctx, userID, current, plaintext)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetTokenVersion(ctx context.Context, userID uint) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"api/internal/entities"
+	"api/internal/repository"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockVenueRepository struct {
+	mock.Mock
+}
+
+// Ensure MockVenueRepository implements repository.VenueRepository
+var _ repository.VenueRepository = (*MockVenueRepository)(nil)
+
+func (m *MockVenueRepository) GetVenues(ctx context.Context, limit, offset int, city string, metadata map[string]string) ([]entities.Venue, int64, error) {
+	args := m.Called(ctx, limit, offset, city, metadata)
+	r, _ := args.Get(0).([]entities.Venue)
+	return r, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockVenueRepository) GetVenueByID(ctx context.Context, venueID uint) (*entities.Venue, error) {
+	args := m.Called(ctx, venueID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Venue), args.Error(1)
+}
+
+func (m *MockVenueRepository) CreateVenue(ctx context.Context, venue *entities.Venue) error {
+	args := m.Called(ctx, venue)
+	return args.Error(0)
+}
+
+func (m *MockVenueRepository) UpdateVenue(ctx context.Context, venueID uint, updates map[string]interface{}) (*entities.Venue, error) {
+	args := m.Called(ctx, venueID, updates)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Venue), args.Error(1)
+}
+
+func (m *MockVenueRepository) DeleteVenue(ctx context.Context, venueID uint) error {
+	args := m.Called(ctx, venueID)
+	return args.Error(0)
+}
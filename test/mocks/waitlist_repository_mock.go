@@ -0,0 +1,91 @@
+package mocks
+
+import (
+	"api/internal/repository"
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWaitlistRepository struct {
+	mock.Mock
+}
+
+// Ensure MockWaitlistRepository implements repository.WaitlistRepository
+var _ repository.WaitlistRepository = (*MockWaitlistRepository)(nil)
+
+func (m *MockWaitlistRepository) JoinWaitlist(ctx context.Context, userID, eventID uint, tier string, priority, capacity int) (*repository.WaitlistEntry, error) {
+	args := m.Called(ctx, userID, eventID, tier, priority, capacity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.WaitlistEntry), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) GetWaitlistPosition(ctx context.Context, userID, eventID uint) (*repository.WaitlistEntry, error) {
+	args := m.Called(ctx, userID, eventID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.WaitlistEntry), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) RemoveFromWaitlist(ctx context.Context, userID, eventID uint) error {
+	args := m.Called(ctx, userID, eventID)
+	return args.Error(0)
+}
+
+func (m *MockWaitlistRepository) GetNextInWaitlist(ctx context.Context, eventID uint) (*repository.WaitlistEntry, error) {
+	args := m.Called(ctx, eventID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.WaitlistEntry), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) GetWaitlistHead(ctx context.Context, eventID uint, count int) ([]*repository.WaitlistEntry, error) {
+	args := m.Called(ctx, eventID, count)
+	r, _ := args.Get(0).([]*repository.WaitlistEntry)
+	return r, args.Error(1)
+}
+
+func (m *MockWaitlistRepository) PopFromWaitlist(ctx context.Context, eventID uint) (*repository.WaitlistEntry, error) {
+	args := m.Called(ctx, eventID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.WaitlistEntry), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) GetWaitlistSize(ctx context.Context, eventID uint) (int, error) {
+	args := m.Called(ctx, eventID)
+	return args.Get(0).(int), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) NotifyWaitlistUsers(ctx context.Context, eventID uint, count int) ([]*repository.WaitlistEntry, error) {
+	args := m.Called(ctx, eventID, count)
+	r, _ := args.Get(0).([]*repository.WaitlistEntry)
+	return r, args.Error(1)
+}
+
+func (m *MockWaitlistRepository) TakeWaitlistUser(ctx context.Context, eventID uint, skip []uint) (*repository.WaitlistEntry, error) {
+	args := m.Called(ctx, eventID, skip)
+	r, _ := args.Get(0).(*repository.WaitlistEntry)
+	return r, args.Error(1)
+}
+
+func (m *MockWaitlistRepository) ReturnToWaitlist(ctx context.Context, entry *repository.WaitlistEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockWaitlistRepository) CleanupExpiredNotifications(ctx context.Context, eventID uint, notificationTTL time.Duration) error {
+	args := m.Called(ctx, eventID, notificationTTL)
+	return args.Error(0)
+}
+
+func (m *MockWaitlistRepository) MigrateKeys(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int), args.Error(1)
+}
@@ -2,6 +2,7 @@ package test
 
 import (
 	"api/internal/entities"
+	"api/pkg/money"
 	"bytes"
 	"encoding/json"
 	"net/http"
@@ -128,7 +129,7 @@ func (m *MockEntities) GetMockEvent() *entities.Event {
 		Venue:          *venue,
 		StartTime:      time.Now().Add(24 * time.Hour),
 		EndTime:        time.Now().Add(26 * time.Hour),
-		Price:          100.0,
+		Price:          money.FromMajor(100),
 		EventType:      "concert",
 		Status:         "active",
 		IsHighDemand:   false,
@@ -147,7 +148,7 @@ func (m *MockEntities) GetMockSeat() *entities.Seat {
 		Row:         1,
 		Column:      1,
 		SeatType:    "Standard",
-		Price:       100.0,
+		Price:       money.FromMajor(100),
 		IsAvailable: true,
 		IsLocked:    false,
 		CreatedAt:   time.Now(),
@@ -193,7 +194,7 @@ func (m *MockEntities) GetMockBooking() *entities.Booking {
 		Status:          "confirmed",
 		PaymentStatus:   "paid",
 		PaymentID:       "pay_test123",
-		TotalAmount:     100.0,
+		TotalAmount:     money.FromMajor(100),
 		BookedAt:        time.Now(),
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),